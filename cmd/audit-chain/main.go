@@ -0,0 +1,114 @@
+// Command audit-chain checks the hash chains of the merchant capital flows and the audit trail, see
+// internal/repository/migration/sql/000017_hash_chain.up.sql:
+//
+//	audit-chain verify [-chain <table>] [-key <merchant account id>]   report the first broken link of every chain
+//	audit-chain checkpoint                                             keep the last link of every chain now
+//	audit-chain export [-from <date>] [-to <date>] [-out <file>]       write the checkpoints as json
+//
+// verify exits with status 1 when a chain is broken. Exported checkpoints are meant to be kept outside of the
+// database, a chain rewritten after a checkpoint no longer matches it.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/hypay-id/backend-dashboard-hypay/config"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/repository"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/service"
+	"go.uber.org/zap"
+)
+
+func main() {
+	slog.NewLogger(slog.Info)
+
+	if len(os.Args) < 2 {
+		slog.Fatalw("missing command, use one of verify, checkpoint, export")
+	}
+	command := os.Args[1]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	chain := flags.String("chain", "", "chain to verify, "+constant.ChainCapitalFlow+" or "+constant.ChainAudit)
+	chainKey := flags.String("key", "", "merchant account id of the capital flow chain to verify")
+	from := flags.String("from", "", "export checkpoints taken from this date")
+	to := flags.String("to", "", "export checkpoints taken until this date")
+	out := flags.String("out", "", "file to export to, stdout when empty")
+	flags.Parse(os.Args[2:])
+
+	// read from env
+	envConfig, err := config.Reader()
+	if err != nil {
+		slog.Fatalw("failed to read config file", zap.Error(err))
+	}
+
+	// bind env to schema
+	cfg := config.BindConfig(envConfig)
+
+	repoReads := repository.NewReadsRepo(cfg.Storage)
+	repoWrites := repository.NewWritesRepo(cfg.Storage)
+	hashChains := service.NewHashChain(repoReads.HashChainReads, repoWrites.UnitOfWork)
+
+	switch command {
+	case "verify":
+		resp, err := hashChains.VerifyHashChainSvc(dto.VerifyHashChainReq{
+			Chain:    *chain,
+			ChainKey: *chainKey,
+		})
+		if err != nil {
+			slog.Fatalw("failed to verify hash chains", zap.Error(err))
+		}
+
+		verification := resp.Data.(dto.HashChainVerificationRespDto)
+		slog.Infof("%v links verified on %v chains", verification.TotalLinks, verification.TotalChains)
+		for _, chainBreak := range verification.Breaks {
+			slog.Errorw("broken hash chain",
+				"chain", chainBreak.Chain,
+				"chain_key", chainBreak.ChainKey,
+				"chain_seq", chainBreak.ChainSeq,
+				"row_id", chainBreak.RowId,
+				"reason", chainBreak.Reason,
+				"detail", chainBreak.Detail,
+			)
+		}
+
+		if len(verification.Breaks) > 0 {
+			os.Exit(1)
+		}
+	case "checkpoint":
+		resp, err := hashChains.CreateChainCheckpointSvc(constant.CreateBySystem)
+		if err != nil {
+			slog.Fatalw("failed to create chain checkpoint", zap.Error(err))
+		}
+		slog.Infow(resp.ResponseMessage, "checkpoint", resp.Data)
+	case "export":
+		resp, err := hashChains.GetListChainCheckpointSvc(dto.QueryParamsChainCheckpoint{
+			MinDate: *from,
+			MaxDate: *to,
+		})
+		if err != nil {
+			slog.Fatalw("failed to get chain checkpoints", zap.Error(err))
+		}
+
+		output := os.Stdout
+		if *out != "" {
+			output, err = os.Create(*out)
+			if err != nil {
+				slog.Fatalw("failed to create export file", zap.Error(err))
+			}
+			defer output.Close()
+		}
+
+		encoder := json.NewEncoder(output)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(resp.Data)
+		if err != nil {
+			slog.Fatalw("failed to write chain checkpoints", zap.Error(err))
+		}
+	default:
+		slog.Fatalw("unknown command, use one of verify, checkpoint, export", "command", command)
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+	"github.com/labstack/echo/v4"
+)
+
+type simulator struct {
+	apiKey         string
+	callbackUrl    string
+	callbackSecret string
+	scenarios      *scenarioStore
+	httpClient     *http.Client
+
+	mu           sync.Mutex
+	nextId       int
+	transactions map[int]*dto.CreateDisbursementRequestResponseData
+}
+
+func newSimulator(apiKey string, callbackUrl string, callbackSecret string, cfg scenarioConfig) *simulator {
+	scenarios := &scenarioStore{}
+	scenarios.set(cfg)
+
+	return &simulator{
+		apiKey:         apiKey,
+		callbackUrl:    callbackUrl,
+		callbackSecret: callbackSecret,
+		scenarios:      scenarios,
+		httpClient:     &http.Client{Timeout: constant.ThirtySecond},
+		nextId:         1,
+		transactions:   map[int]*dto.CreateDisbursementRequestResponseData{},
+	}
+}
+
+func (sm *simulator) registerRoutes(e *echo.Echo) {
+	// endpoints called by the jack adapter
+	e.GET("/inquiry", sm.authorize(sm.inquiryHandler))
+	e.GET("/balance", sm.authorize(sm.balanceHandler))
+	e.POST("/disbursements", sm.authorize(sm.createHandler))
+	e.POST("/disbursements/:id/confirm", sm.authorize(sm.confirmHandler))
+	e.GET("/disbursements/:id", sm.authorize(sm.statusHandler))
+
+	// endpoints for scripting the simulator
+	e.GET("/simulator/scenarios", sm.getScenariosHandler)
+	e.PUT("/simulator/scenarios", sm.putScenariosHandler)
+	e.GET("/simulator/transactions", sm.listTransactionsHandler)
+}
+
+func (sm *simulator) authorize(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if c.Request().Header.Get("Authorization") != sm.apiKey {
+			return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+				"status":  http.StatusUnauthorized,
+				"message": "invalid api key",
+			})
+		}
+
+		return next(c)
+	}
+}
+
+func (sm *simulator) inquiryHandler(c echo.Context) error {
+	accountNumber := c.QueryParam("account_number")
+	bankName := c.QueryParam("bank_name")
+	scenario, accountName := sm.scenarios.forAccount(accountNumber)
+	slog.Infof("SIMULATOR [inquiry-account] account number: %v, bank name: %v, scenario: %v", accountNumber, bankName, scenario)
+
+	if scenario == scenarioValidationError {
+		return c.JSON(http.StatusOK, dto.InquiryAccountResponse{
+			Status: constant.JackStatusInvalid,
+			Data: dto.InquiryData{
+				AccountNo: accountNumber,
+				BankName:  bankName,
+				Errors:    "account number is not valid",
+			},
+		})
+	}
+
+	if scenario == scenarioNameMismatch {
+		accountName = "Zzq Simulator Mismatch"
+	}
+
+	return c.JSON(http.StatusOK, dto.InquiryAccountResponse{
+		Status: constant.JackStatusOk,
+		Data: dto.InquiryData{
+			ID:          accountNumber,
+			AccountNo:   accountNumber,
+			BankName:    bankName,
+			AccountName: accountName,
+		},
+	})
+}
+
+func (sm *simulator) balanceHandler(c echo.Context) error {
+	cfg := sm.scenarios.get()
+
+	balance := cfg.Balance
+	if cfg.Default == scenarioInsufficientBalance {
+		balance = 0
+	}
+
+	// the adapter reads the third balance, keep the same shape as jack
+	return c.JSON(http.StatusOK, dto.JackGetBalanceResponse{
+		Status: constant.JackStatusOk,
+		Data: dto.JackBalancesData{
+			Total: 3,
+			Balances: []dto.JackBalanceDetailData{
+				{ID: 1, Currency: "USD", IsActive: true},
+				{ID: 2, Currency: "SGD", IsActive: true},
+				{ID: 3, Currency: constant.JackDisbursementCurrency, Balance: balance, IsActive: true},
+			},
+		},
+	})
+}
+
+func (sm *simulator) createHandler(c echo.Context) error {
+	var req dto.CreateDisbursementRequest
+
+	err := c.Bind(&req)
+	if err != nil {
+		return c.JSON(http.StatusOK, dto.CreateDisbursementRequestResponse{
+			Status: constant.JackStatusInvalid,
+			Data:   dto.CreateDisbursementRequestResponseData{ErrorMessage: "invalid request body"},
+		})
+	}
+
+	scenario, _ := sm.scenarios.forAccount(req.Beneficiary.Account)
+	slog.Infof("SIMULATOR %v [create-disbursement] account number: %v, scenario: %v", req.ReferenceID, req.Beneficiary.Account, scenario)
+
+	if scenario == scenarioTimeout {
+		time.Sleep(time.Duration(sm.scenarios.get().TimeoutDelaySecond) * time.Second)
+	}
+
+	payerId, _ := strconv.Atoi(req.PayerID)
+	now := time.Now().Format(time.RFC3339)
+
+	sm.mu.Lock()
+	transaction := &dto.CreateDisbursementRequestResponseData{
+		ID:              sm.nextId,
+		ReferenceID:     req.ReferenceID,
+		CallbackURL:     req.CallbackURL,
+		PayerID:         payerId,
+		Mode:            req.Mode,
+		Sender:          req.Sender,
+		Source:          req.Source,
+		Destination:     req.Destination,
+		Beneficiary:     req.Beneficiary,
+		Notes:           req.Notes,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		State:           "created",
+		SentAmount:      req.Destination.Amount,
+		TransactionType: "disbursement",
+	}
+	sm.transactions[transaction.ID] = transaction
+	sm.nextId++
+	resp := *transaction
+	sm.mu.Unlock()
+
+	return c.JSON(http.StatusOK, dto.CreateDisbursementRequestResponse{
+		Status: constant.JackStatusOk,
+		Data:   resp,
+	})
+}
+
+func (sm *simulator) confirmHandler(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+
+	sm.mu.Lock()
+	transaction, ok := sm.transactions[id]
+	if !ok {
+		sm.mu.Unlock()
+		return c.JSON(http.StatusNotFound, map[string]interface{}{
+			"status":  http.StatusNotFound,
+			"message": "transaction not found",
+		})
+	}
+
+	transaction.State = constant.JackStateStatusConfirm
+	transaction.UpdatedAt = time.Now().Format(time.RFC3339)
+	resp := *transaction
+	sm.mu.Unlock()
+
+	go sm.sendCallbacks(id)
+
+	return c.JSON(http.StatusOK, dto.CreateDisbursementRequestResponse{
+		Status: constant.JackStatusOk,
+		Data:   resp,
+	})
+}
+
+func (sm *simulator) statusHandler(c echo.Context) error {
+	id, _ := strconv.Atoi(c.Param("id"))
+
+	sm.mu.Lock()
+	transaction, ok := sm.transactions[id]
+	var resp dto.CreateDisbursementRequestResponseData
+	if ok {
+		resp = *transaction
+	}
+	sm.mu.Unlock()
+
+	if !ok {
+		return c.JSON(http.StatusNotFound, map[string]interface{}{
+			"status":  http.StatusNotFound,
+			"message": "transaction not found",
+		})
+	}
+
+	return c.JSON(http.StatusOK, dto.CreateDisbursementRequestResponse{
+		Status: constant.JackStatusOk,
+		Data:   resp,
+	})
+}
+
+// sendCallbacks moves the confirmed transaction into its final state and notifies the dashboard
+func (sm *simulator) sendCallbacks(id int) {
+	time.Sleep(time.Duration(sm.scenarios.get().CallbackDelaySecond) * time.Second)
+
+	sm.mu.Lock()
+	transaction := sm.transactions[id]
+	scenario, _ := sm.scenarios.forAccount(transaction.Beneficiary.Account)
+
+	attempts := 1
+	switch scenario {
+	case scenarioDeclined:
+		transaction.State = constant.JackStateStatusDeclined
+		transaction.ErrorMessage = "declined by simulator"
+	case scenarioCanceled:
+		transaction.State = constant.JackStateStatusCanceled
+		transaction.ErrorMessage = "canceled by simulator"
+	case scenarioDuplicateCallback:
+		transaction.State = constant.JackStateStatusCompleted
+		attempts = 2
+	default:
+		transaction.State = constant.JackStateStatusCompleted
+	}
+	transaction.PaidAt = time.Now().Format(time.RFC3339)
+	transaction.UpdatedAt = transaction.PaidAt
+	payload := *transaction
+	sm.mu.Unlock()
+
+	callbackUrl := sm.callbackUrl
+	if callbackUrl == "" {
+		callbackUrl = payload.CallbackURL
+	}
+
+	payloadJSON, _ := json.Marshal(payload)
+	mac := hmac.New(sha256.New, []byte(sm.callbackSecret))
+	mac.Write(payloadJSON)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	for i := 0; i < attempts; i++ {
+		r, err := http.NewRequest(http.MethodPost, callbackUrl, bytes.NewBuffer(payloadJSON))
+		if err != nil {
+			slog.Infof("SIMULATOR %v [callback] failed to create request: %v", payload.ReferenceID, err.Error())
+			return
+		}
+
+		r.Header.Add("Content-Type", "application/json")
+		r.Header.Add(constant.JackCallbackSignatureHeader, signature)
+
+		response, err := sm.httpClient.Do(r)
+		if err != nil {
+			slog.Infof("SIMULATOR %v [callback] failed to send: %v", payload.ReferenceID, err.Error())
+			continue
+		}
+		response.Body.Close()
+
+		slog.Infof("SIMULATOR %v [callback] state %v sent to %v, http status %v", payload.ReferenceID, payload.State, callbackUrl, response.StatusCode)
+	}
+}
+
+func (sm *simulator) getScenariosHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, sm.scenarios.get())
+}
+
+func (sm *simulator) putScenariosHandler(c echo.Context) error {
+	cfg := defaultScenarioConfig()
+
+	err := c.Bind(&cfg)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request payload"})
+	}
+
+	err = cfg.validate()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	sm.scenarios.set(cfg)
+	return c.JSON(http.StatusOK, cfg)
+}
+
+func (sm *simulator) listTransactionsHandler(c echo.Context) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	transactions := make([]dto.CreateDisbursementRequestResponseData, 0, len(sm.transactions))
+	for id := 1; id < sm.nextId; id++ {
+		transactions = append(transactions, *sm.transactions[id])
+	}
+
+	return c.JSON(http.StatusOK, transactions)
+}
@@ -0,0 +1,46 @@
+// Command jack-simulator mimics the jack disbursement API so the disbursement flow can run
+// without the jack sandbox. Point the jack provider_credentials of an interface setting to it:
+//
+//	INQUIRY_ACCOUNT_URL           http://localhost:9090/inquiry
+//	GET_BALANCE_URL               http://localhost:9090/balance
+//	DISBURSEMENT_TRANSACTIONS_URL http://localhost:9090/disbursements
+//	API_KEY                       same value as -api-key
+//	CALLBACK_SECRET               same value as -callback-secret
+//
+// Scenarios are picked per beneficiary account number from the -scenarios file and can be
+// replaced at runtime with PUT /simulator/scenarios, see scenarios.example.json.
+package main
+
+import (
+	"flag"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+func main() {
+	slog.NewLogger(slog.Info)
+
+	port := flag.String("port", "9090", "listen port")
+	apiKey := flag.String("api-key", "simulator-api-key", "api key expected in the Authorization header")
+	callbackUrl := flag.String("callback-url", "http://localhost:8080/merchant-dashboard/v1/provider-jack/disbursement", "callback target, empty uses the callback_url of the request")
+	callbackSecret := flag.String("callback-secret", "simulator-callback-secret", "secret used to sign callbacks")
+	scenariosPath := flag.String("scenarios", "", "scenario json file")
+	flag.Parse()
+
+	cfg, err := loadScenarioConfig(*scenariosPath)
+	if err != nil {
+		slog.Fatalw("failed to load scenarios", zap.Error(err))
+	}
+
+	e := echo.New()
+	e.HideBanner = true
+	newSimulator(*apiKey, *callbackUrl, *callbackSecret, cfg).registerRoutes(e)
+
+	slog.Infof("jack simulator listening on :%v with default scenario %v", *port, cfg.Default)
+	err = e.Start(":" + *port)
+	if err != nil {
+		slog.Fatalw("jack simulator stopped", zap.Error(err))
+	}
+}
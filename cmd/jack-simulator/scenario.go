@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+const (
+	// scenarioCompleted confirms the disbursement and calls back with state completed
+	scenarioCompleted = "completed"
+	// scenarioDeclined confirms the disbursement and calls back with state declined
+	scenarioDeclined = "declined"
+	// scenarioCanceled confirms the disbursement and calls back with state canceled
+	scenarioCanceled = "canceled"
+	// scenarioDuplicateCallback calls back completed twice
+	scenarioDuplicateCallback = "duplicate_callback"
+	// scenarioNameMismatch returns an inquiry account name that never matches the request
+	scenarioNameMismatch = "name_mismatch"
+	// scenarioValidationError rejects the inquiry with status 422
+	scenarioValidationError = "validation_error"
+	// scenarioInsufficientBalance reports an empty provider balance, only works as default scenario
+	// because jack get balance doesn't know the beneficiary account
+	scenarioInsufficientBalance = "insufficient_balance"
+	// scenarioTimeout holds the create transaction response longer than the adapter timeout
+	scenarioTimeout = "timeout"
+)
+
+var knownScenarios = map[string]bool{
+	scenarioCompleted:           true,
+	scenarioDeclined:            true,
+	scenarioCanceled:            true,
+	scenarioDuplicateCallback:   true,
+	scenarioNameMismatch:        true,
+	scenarioValidationError:     true,
+	scenarioInsufficientBalance: true,
+	scenarioTimeout:             true,
+}
+
+type accountScenario struct {
+	Scenario    string `json:"scenario"`
+	AccountName string `json:"accountName"`
+}
+
+// scenarioConfig scripts the simulator, accounts are keyed by beneficiary account number
+type scenarioConfig struct {
+	Default             string                     `json:"default"`
+	DefaultAccountName  string                     `json:"defaultAccountName"`
+	Balance             int                        `json:"balance"`
+	CallbackDelaySecond int                        `json:"callbackDelaySecond"`
+	TimeoutDelaySecond  int                        `json:"timeoutDelaySecond"`
+	Accounts            map[string]accountScenario `json:"accounts"`
+}
+
+func defaultScenarioConfig() scenarioConfig {
+	return scenarioConfig{
+		Default:             scenarioCompleted,
+		DefaultAccountName:  "Simulator Account",
+		Balance:             1000000000,
+		CallbackDelaySecond: 2,
+		TimeoutDelaySecond:  95,
+		Accounts:            map[string]accountScenario{},
+	}
+}
+
+func loadScenarioConfig(path string) (scenarioConfig, error) {
+	cfg := defaultScenarioConfig()
+	if path == "" {
+		return cfg, nil
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	err = json.Unmarshal(contents, &cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	return cfg, cfg.validate()
+}
+
+func (sc scenarioConfig) validate() error {
+	if !knownScenarios[sc.Default] {
+		return fmt.Errorf("unknown default scenario %q", sc.Default)
+	}
+
+	for accountNumber, account := range sc.Accounts {
+		if !knownScenarios[account.Scenario] {
+			return fmt.Errorf("unknown scenario %q for account %v", account.Scenario, accountNumber)
+		}
+	}
+
+	return nil
+}
+
+// scenarioStore keeps the active config, it can be replaced at runtime by a test suite
+type scenarioStore struct {
+	mu  sync.RWMutex
+	cfg scenarioConfig
+}
+
+func (ss *scenarioStore) get() scenarioConfig {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	return ss.cfg
+}
+
+func (ss *scenarioStore) set(cfg scenarioConfig) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	if cfg.Accounts == nil {
+		cfg.Accounts = map[string]accountScenario{}
+	}
+	ss.cfg = cfg
+}
+
+// forAccount returns the scenario and account holder name of accountNumber
+func (ss *scenarioStore) forAccount(accountNumber string) (string, string) {
+	cfg := ss.get()
+
+	account, ok := cfg.Accounts[accountNumber]
+	if !ok {
+		return cfg.Default, cfg.DefaultAccountName
+	}
+
+	if account.AccountName == "" {
+		account.AccountName = cfg.DefaultAccountName
+	}
+
+	return account.Scenario, account.AccountName
+}
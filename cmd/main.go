@@ -3,10 +3,13 @@ package main
 import (
 	"github.com/hypay-id/backend-dashboard-hypay/config"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/adapter"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/envelope"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/repository"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/server/http"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/server/http/controller"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/server/scheduler"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/service"
 	"go.uber.org/zap"
 )
@@ -28,6 +31,12 @@ func main() {
 	// init repository for writes
 	repoWrites := repository.NewWritesRepo(cfg.Storage)
 
+	// keys for the merchant secrets and provider credentials stored encrypted
+	keyring, err := envelope.ParseKeyring(cfg.App.EncryptionKeys, cfg.App.EncryptionActiveKey)
+	if err != nil {
+		slog.Fatalw("failed to load encryption keys", zap.Error(err))
+	}
+
 	// adapter injector
 	adptr := adapter.New(cfg.App)
 
@@ -37,11 +46,29 @@ func main() {
 		repoWrites,
 		cfg.App,
 		adptr.MerchantCallback,
-		adptr.JackProvider,
+		adptr.PayoutProviders,
+		keyring,
 	)
 
+	// background jobs
+	jobScheduler := scheduler.NewScheduler()
+	jobScheduler.Every(constant.ReconciliationInterval, "balance reconciliation", func() error {
+		_, err := svc.Reconciliations.RunReconciliationSvc(constant.CreateBySystem)
+		return err
+	})
+	jobScheduler.Every(constant.CallbackDeliveryInterval, "merchant callback delivery", svc.Merchants.DeliverMerchantCallbacksSvc)
+	jobScheduler.Every(constant.MerchantKeyRotationCheck, "merchant key rotation", svc.Merchants.RotateMerchantKeysSvc)
+	jobScheduler.Every(constant.ChainCheckpointInterval, "hash chain checkpoint", func() error {
+		_, err := svc.HashChains.CreateChainCheckpointSvc(constant.CreateBySystem)
+		return err
+	})
+	jobScheduler.Every(constant.DisbursementBatchResumeInterval, "disbursement batch resume", svc.Transactions.ResumeDisbursementBatchesSvc)
+	jobScheduler.Every(constant.DisbursementRequestStaleInterval, "stale disbursement request", svc.Transactions.FailStaleDisbursementRequestsSvc)
+	jobScheduler.Start()
+	defer jobScheduler.Stop()
+
 	// http server will be used only for callback operation
-	httpController := controller.NewController(cfg, svc.Transactions, svc.Merchants, svc.Users, svc.Providers)
+	httpController := controller.NewController(cfg, svc.Transactions, svc.Merchants, svc.Users, svc.Providers, svc.Reconciliations, svc.Audits, svc.HashChains)
 	httpServer := http.NewHttpServer(cfg.HTTPServer, httpController)
 	httpServer.ListenAndServe()
 }
@@ -0,0 +1,106 @@
+// Command migrate applies the versioned schema migrations embedded in internal/repository/migration
+// to the writes database configured for the dashboard:
+//
+//	migrate up                    apply every pending migration
+//	migrate down -steps 1         revert the latest applied migrations
+//	migrate status                list migrations and when they were applied
+//	migrate seed                  insert the reference data, safe to run again
+//	migrate baseline -version 6   mark migrations up to version as applied without running them,
+//	                              for databases created before migrations existed
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/hypay-id/backend-dashboard-hypay/config"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/repository"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/repository/migration"
+	"go.uber.org/zap"
+)
+
+func main() {
+	slog.NewLogger(slog.Info)
+
+	if len(os.Args) < 2 {
+		slog.Fatalw("missing command, use one of up, down, status, seed, baseline")
+	}
+	command := os.Args[1]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	steps := flags.Int("steps", 1, "number of migrations to revert with down")
+	version := flags.Int("version", 0, "latest version to mark as applied with baseline")
+	flags.Parse(os.Args[2:])
+
+	// read from env
+	envConfig, err := config.Reader()
+	if err != nil {
+		slog.Fatalw("failed to read config file", zap.Error(err))
+	}
+
+	// bind env to schema
+	cfg := config.BindConfig(envConfig)
+
+	db, err := repository.OpenWritesDB(cfg.Storage)
+	if err != nil {
+		slog.Fatalw("failed to open writes database", zap.Error(err))
+	}
+	defer db.Close()
+
+	migrator, err := migration.New(db)
+	if err != nil {
+		slog.Fatalw("failed to load migrations", zap.Error(err))
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up()
+		for _, m := range applied {
+			slog.Infof("applied %06d_%v", m.Version, m.Name)
+		}
+		if err != nil {
+			slog.Fatalw("failed to apply migrations", zap.Error(err))
+		}
+		slog.Infof("%v migrations applied", len(applied))
+	case "down":
+		reverted, err := migrator.Down(*steps)
+		for _, m := range reverted {
+			slog.Infof("reverted %06d_%v", m.Version, m.Name)
+		}
+		if err != nil {
+			slog.Fatalw("failed to revert migrations", zap.Error(err))
+		}
+		slog.Infof("%v migrations reverted", len(reverted))
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			slog.Fatalw("failed to read migration status", zap.Error(err))
+		}
+		for _, s := range statuses {
+			if s.AppliedAt == nil {
+				slog.Infof("%06d_%v pending", s.Version, s.Name)
+				continue
+			}
+			slog.Infof("%06d_%v applied at %v", s.Version, s.Name, s.AppliedAt.Format("2006-01-02 15:04:05"))
+		}
+	case "seed":
+		seeded, err := migrator.Seed()
+		for _, name := range seeded {
+			slog.Infof("seeded %v", name)
+		}
+		if err != nil {
+			slog.Fatalw("failed to seed", zap.Error(err))
+		}
+	case "baseline":
+		marked, err := migrator.Baseline(*version)
+		for _, m := range marked {
+			slog.Infof("marked %06d_%v as applied", m.Version, m.Name)
+		}
+		if err != nil {
+			slog.Fatalw("failed to baseline migrations", zap.Error(err))
+		}
+	default:
+		slog.Fatalw("unknown command, use one of up, down, status, seed, baseline", "command", command)
+	}
+}
@@ -0,0 +1,158 @@
+// Command rotate-keys keeps the merchant secrets and provider credentials stored encrypted under the active
+// key of CONFIG_ENCRYPTION_KEYS, see internal/pkg/envelope:
+//
+//	rotate-keys rotate                  re-encrypt every value that is plaintext or sealed with an older key
+//	rotate-keys seal -provider <id> -key <key> -value <secret>
+//	                                    print the sealed form of a provider credential to insert by hand
+//
+// Keep the older keys in CONFIG_ENCRYPTION_KEYS until rotate reports nothing left to re-encrypt.
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/hypay-id/backend-dashboard-hypay/config"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/envelope"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/repository"
+	"go.uber.org/zap"
+)
+
+func main() {
+	slog.NewLogger(slog.Info)
+
+	if len(os.Args) < 2 {
+		slog.Fatalw("missing command, use one of rotate, seal")
+	}
+	command := os.Args[1]
+
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	value := flags.String("value", "", "plaintext to seal with seal")
+	providerId := flags.String("provider", "", "provider id of the credential to seal with seal")
+	credentialKey := flags.String("key", "", "key of the credential to seal with seal")
+	flags.Parse(os.Args[2:])
+
+	// read from env
+	envConfig, err := config.Reader()
+	if err != nil {
+		slog.Fatalw("failed to read config file", zap.Error(err))
+	}
+
+	// bind env to schema
+	cfg := config.BindConfig(envConfig)
+
+	keyring, err := envelope.ParseKeyring(cfg.App.EncryptionKeys, cfg.App.EncryptionActiveKey)
+	if err != nil {
+		slog.Fatalw("failed to load encryption keys", zap.Error(err))
+	}
+
+	switch command {
+	case "rotate":
+		repoWrites := repository.NewWritesRepo(cfg.Storage)
+
+		rotated, err := rotateMerchantSecrets(repoWrites, keyring)
+		if err != nil {
+			slog.Fatalw("failed to rotate merchant secrets", zap.Error(err))
+		}
+		slog.Infof("%v merchant secrets re-encrypted", rotated)
+
+		rotated, err = rotateProviderCredentials(repoWrites, keyring)
+		if err != nil {
+			slog.Fatalw("failed to rotate provider credentials", zap.Error(err))
+		}
+		slog.Infof("%v provider credentials re-encrypted", rotated)
+	case "seal":
+		if *value == "" || *providerId == "" || *credentialKey == "" {
+			slog.Fatalw("missing -provider, -key or -value to seal")
+		}
+
+		sealed, err := keyring.Seal(*value, providerCredentialData(*providerId, *credentialKey))
+		if err != nil {
+			slog.Fatalw("failed to seal value", zap.Error(err))
+		}
+		fmt.Println(sealed)
+	default:
+		slog.Fatalw("unknown command, use one of rotate, seal", "command", command)
+	}
+}
+
+func rotateMerchantSecrets(repoWrites *repository.Repository, keyring *envelope.Keyring) (int, error) {
+	keys, err := repoWrites.MerchantWrites.GetMerchantKeySecretsRepo()
+	if err != nil {
+		return 0, err
+	}
+
+	var rotated int
+	for _, key := range keys {
+		if !keyring.NeedsRotation(key.Secret) {
+			continue
+		}
+
+		sealed, err := reseal(keyring, key.Secret, envelope.AdditionalData("merchant_keys.secret", key.MerchantId, key.Version))
+		if err != nil {
+			return rotated, fmt.Errorf("merchant %v key version %v: %w", key.MerchantId, key.Version, err)
+		}
+
+		err = repoWrites.MerchantWrites.RotateMerchantKeySecretRepo(key.Id, key.Secret, sealed)
+		if err == sql.ErrNoRows {
+			slog.Infof("merchant %v key version %v changed while rotating, run rotate again", key.MerchantId, key.Version)
+			continue
+		}
+		if err != nil {
+			return rotated, fmt.Errorf("merchant %v key version %v: %w", key.MerchantId, key.Version, err)
+		}
+
+		rotated++
+	}
+
+	return rotated, nil
+}
+
+func rotateProviderCredentials(repoWrites *repository.Repository, keyring *envelope.Keyring) (int, error) {
+	credentials, err := repoWrites.ProviderWrites.GetProviderCredentialsRepo()
+	if err != nil {
+		return 0, err
+	}
+
+	var rotated int
+	for _, cred := range credentials {
+		if !keyring.NeedsRotation(cred.Value) {
+			continue
+		}
+
+		sealed, err := reseal(keyring, cred.Value, providerCredentialData(cred.ProviderId, cred.Key))
+		if err != nil {
+			return rotated, fmt.Errorf("credential %v: %w", cred.Id, err)
+		}
+
+		err = repoWrites.ProviderWrites.RotateProviderCredentialRepo(cred.Id, cred.Value, sealed)
+		if err == sql.ErrNoRows {
+			slog.Infof("credential %v changed while rotating, run rotate again", cred.Id)
+			continue
+		}
+		if err != nil {
+			return rotated, fmt.Errorf("credential %v: %w", cred.Id, err)
+		}
+
+		rotated++
+	}
+
+	return rotated, nil
+}
+
+// reseal opens value with whichever key sealed it and seals it again with the active key
+func reseal(keyring *envelope.Keyring, value string, additionalData string) (string, error) {
+	plaintext, err := keyring.Open(value, additionalData)
+	if err != nil {
+		return "", err
+	}
+
+	return keyring.Seal(plaintext, additionalData)
+}
+
+func providerCredentialData(providerId string, key string) string {
+	return envelope.AdditionalData("provider_credentials.value", providerId, key)
+}
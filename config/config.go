@@ -46,6 +46,10 @@ type App struct {
 	Domain              string
 	AppPassMail         string
 	CallbackUrl         string
+	DashboardUrl        string
+	// EncryptionKeys are the key-encryption keys as id:base64key pairs separated by comma
+	EncryptionKeys      string
+	EncryptionActiveKey string
 }
 
 type PSQL struct {
@@ -67,6 +71,9 @@ type HTTPServer struct {
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	IdleTimeout     time.Duration
+	// TrustedProxies are the CIDR ranges of the proxies in front of the server separated by comma,
+	// when empty the client ip is the address of the connection and forwarding headers are ignored
+	TrustedProxies string
 }
 
 func BindConfig(env EnvConfig) Schema {
@@ -78,6 +85,7 @@ func BindConfig(env EnvConfig) Schema {
 			ReadTimeout:     time.Minute * 3,
 			WriteTimeout:    time.Minute * 5,
 			IdleTimeout:     time.Minute * 15,
+			TrustedProxies:  env.ServerTrustedProxies,
 		},
 		Storage: Storage{
 			PSQL: map[string]PSQL{
@@ -113,12 +121,16 @@ func BindConfig(env EnvConfig) Schema {
 			Domain:              env.Domain,
 			AppPassMail:         env.AppPassMail,
 			CallbackUrl:         env.AppCallbackUrl,
+			DashboardUrl:        env.AppDashboardUrl,
+			EncryptionKeys:      env.AppEncryptionKeys,
+			EncryptionActiveKey: env.AppEncryptionActiveKey,
 		},
 	}
 }
 
 type EnvConfig struct {
 	ServerPort                    string `mapstructure:"CONFIG_SERVER_PORT"`
+	ServerTrustedProxies          string `mapstructure:"CONFIG_SERVER_TRUSTED_PROXIES"`
 	StorageDatabaseNameWrites     string `mapstructure:"CONFIG_STORAGE_DB_NAME_WRITES"`
 	StorageDatabaseUsernameWrites string `mapstructure:"CONFIG_STORAGE_USERNAME_WRITES"`
 	StorageDatabasePasswordWrites string `mapstructure:"CONFIG_STORAGE_PASSWORD_WRITES"`
@@ -142,4 +154,7 @@ type EnvConfig struct {
 	Domain                        string `mapstructure:"CONFIG_DOMAIN"`
 	AppPassMail                   string `mapstructure:"CONFIG_APP_MAIL"`
 	AppCallbackUrl                string `mapstructure:"CONFIG_URL_DISBURSEMENT_CALLBACK"`
+	AppDashboardUrl               string `mapstructure:"CONFIG_URL_DASHBOARD"`
+	AppEncryptionKeys             string `mapstructure:"CONFIG_ENCRYPTION_KEYS"`
+	AppEncryptionActiveKey        string `mapstructure:"CONFIG_ENCRYPTION_ACTIVE_KEY"`
 }
@@ -1,17 +1,29 @@
 package internal
 
 import (
+	"net/http"
+
 	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
 )
 
 type MerchantCallbackItf interface {
-	SendCallbackAdptr(url string, transactionEntity entity.PaymentDetailMerchantProvider, transactionStatusLogLatest entity.TransactionStatusLogs, merchantSecret string) (interface{}, error)
+	SendCallbackAdptr(url string, transactionEntity entity.PaymentDetailMerchantProvider, transactionStatusLogLatest entity.TransactionStatusLogs, signingKeys []dto.MerchantSigningKey) (interface{}, error)
+}
+
+// PayoutProviderItf is implemented by every disbursement provider adapter
+type PayoutProviderItf interface {
+	InquiryAccount(payload dto.PayoutRequest, credentials []entity.ProviderCredentialsEntity) (dto.PayoutInquiryResult, error)
+	GetBalance(username string, credentials []entity.ProviderCredentialsEntity) (money.Money, error)
+	CreatePayout(payload dto.PayoutRequest, credentials []entity.ProviderCredentialsEntity) (dto.PayoutResult, error)
+	ConfirmPayout(username string, providerReferenceId string, credentials []entity.ProviderCredentialsEntity) (dto.PayoutResult, error)
+	GetPayoutStatus(username string, providerReferenceId string, credentials []entity.ProviderCredentialsEntity) (dto.PayoutResult, error)
+	ParseCallback(body []byte) (dto.PayoutResult, error)
+	VerifyCallback(headers http.Header, body []byte, credentials []entity.ProviderCredentialsEntity) error
 }
 
-type JackProviderItf interface {
-	InquiryAccount(payload dto.MerchantDisbursement, credentials []entity.ProviderCredentialsEntity, bankCode string) (dto.InquiryAccountResponse, error)
-	GetBalance(username string, credentials []entity.ProviderCredentialsEntity) (int, error)
-	ConfirmDisbursement(payload dto.ConfirmTransactionPayload, credentials []entity.ProviderCredentialsEntity) (dto.CreateDisbursementRequestResponse, error)
-	CreateDisbursement(payload dto.MerchantDisbursement, credentials []entity.ProviderCredentialsEntity, bankCode string, paymentId string) (dto.CreateDisbursementRequestResponse, error)
+// PayoutProviderRegistryItf resolves the payout adapter of a routed provider channel
+type PayoutProviderRegistryItf interface {
+	Resolve(providerId string, interfaceSetting string) (PayoutProviderItf, error)
 }
@@ -5,16 +5,21 @@ import (
 	"github.com/hypay-id/backend-dashboard-hypay/internal"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/adapter/jack"
 	merchantcallback "github.com/hypay-id/backend-dashboard-hypay/internal/adapter/merchant_callback"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
 )
 
 type Adapter struct {
 	MerchantCallback internal.MerchantCallbackItf
-	JackProvider     internal.JackProviderItf
+	PayoutProviders  internal.PayoutProviderRegistryItf
 }
 
 func New(cfg config.App) *Adapter {
+	// every payout provider registers under its provider_id
+	payoutProviders := newPayoutRegistry()
+	payoutProviders.Register(constant.ProviderJack, jack.New(cfg))
+
 	return &Adapter{
 		MerchantCallback: merchantcallback.New(cfg),
-		JackProvider:     jack.New(cfg),
+		PayoutProviders:  payoutProviders,
 	}
 }
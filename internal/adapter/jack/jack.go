@@ -2,12 +2,17 @@ package jack
 
 import (
 	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/hypay-id/backend-dashboard-hypay/config"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
@@ -15,6 +20,7 @@ import (
 	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
 )
 
@@ -32,9 +38,10 @@ func New(cfg config.App) *jack {
 	}
 }
 
-func (jk *jack) InquiryAccount(payload dto.MerchantDisbursement, credentials []entity.ProviderCredentialsEntity, bankCode string) (dto.InquiryAccountResponse, error) {
+func (jk *jack) InquiryAccount(payload dto.PayoutRequest, credentials []entity.ProviderCredentialsEntity) (dto.PayoutInquiryResult, error) {
+	var result dto.PayoutInquiryResult
 	var resp dto.InquiryAccountResponse
-	bankName := constant.TransformJackBank[bankCode].BankName
+	bankName := payload.Bank.ProviderBankName
 	var cfg dto.JackCredentialsDto
 
 	for _, cred := range credentials {
@@ -47,12 +54,12 @@ func (jk *jack) InquiryAccount(payload dto.MerchantDisbursement, credentials []e
 		}
 	}
 
-	slog.Infof("JACK %v [inquiry-account] with account number: %v, bank name: %v", payload.Username, payload.BankAccountNumber, payload.BankName)
+	slog.Infof("JACK %v [inquiry-account] with account number: %v, bank name: %v", payload.Username, payload.BankAccountNumber, bankName)
 
 	// http request
 	r, err := http.NewRequest(http.MethodGet, cfg.InquiryUrl, nil)
 	if err != nil {
-		return resp, errors.New("failed to create request")
+		return result, errors.New("failed to create request")
 	}
 
 	// set header
@@ -68,7 +75,7 @@ func (jk *jack) InquiryAccount(payload dto.MerchantDisbursement, credentials []e
 	r.Close = true
 	response, err := jk.httpClient.Do(r)
 	if err != nil {
-		return resp, errors.New("failed to send request")
+		return result, errors.New("failed to send request")
 	}
 
 	defer func() {
@@ -82,38 +89,43 @@ func (jk *jack) InquiryAccount(payload dto.MerchantDisbursement, credentials []e
 	contents, err := ioutil.ReadAll(response.Body)
 	if err != nil {
 		slog.Infof("JACK %v [inquiry-account] got error failed to read response %v", payload.Username, string(contents))
-		return resp, errors.New("failed to read response body")
+		return result, errors.New("failed to read response body")
 	}
 
 	if response.StatusCode != http.StatusOK {
 		slog.Infof("JACK %v [inquiry-account] got error response status not ok: %v", payload.Username, string(contents))
-		return resp, errors.New(string(contents))
+		return result, errors.New(string(contents))
 	}
 
 	// conver response to struct
 	err = json.Unmarshal(contents, &resp)
 	if err != nil {
 		slog.Infof("JACK %v [inquiry-account] error failed to unmarshall response %v", payload.Username, string(contents))
-		return resp, errors.New("failed to unmarshall response to struct")
+		return result, errors.New("failed to unmarshall response to struct")
 	}
 
 	// validate status on response payload
 	if resp.Status != constant.JackStatusOk {
 		if resp.Status == constant.JackStatusInvalid {
 			msg := resp.Data.Errors
-			return resp, errors.New(msg)
+			return result, errors.New(msg)
 		}
 
 		slog.Infof("JACK %v [inquiry-account] got error response status not ok: %v", payload.Username, converter.ToString(resp))
-		return resp, errors.New(converter.ToString(resp))
+		return result, errors.New(converter.ToString(resp))
 	}
 
 	slog.Infof("JACK %v [inquiry-account] response data: %v", payload.Username, converter.ToString(resp))
 
-	return resp, nil
+	result = dto.PayoutInquiryResult{
+		AccountNumber: resp.Data.AccountNo,
+		AccountName:   resp.Data.AccountName,
+	}
+
+	return result, nil
 }
 
-func (jk *jack) GetBalance(username string, credentials []entity.ProviderCredentialsEntity) (int, error) {
+func (jk *jack) GetBalance(username string, credentials []entity.ProviderCredentialsEntity) (money.Money, error) {
 	var cfg dto.JackCredentialsDto
 	var resp dto.JackGetBalanceResponse
 
@@ -130,7 +142,7 @@ func (jk *jack) GetBalance(username string, credentials []entity.ProviderCredent
 	// create request
 	r, err := http.NewRequest(http.MethodGet, cfg.GetBalanceUrl, nil)
 	if err != nil {
-		return 0, errors.New("failed to create request")
+		return money.Money{}, errors.New("failed to create request")
 	}
 
 	r.Header.Add("Content-Type", "application/json")
@@ -139,7 +151,7 @@ func (jk *jack) GetBalance(username string, credentials []entity.ProviderCredent
 
 	response, err := jk.httpClient.Do(r)
 	if err != nil {
-		return 0, errors.New("failed to send request")
+		return money.Money{}, errors.New("failed to send request")
 	}
 
 	defer func() {
@@ -153,32 +165,32 @@ func (jk *jack) GetBalance(username string, credentials []entity.ProviderCredent
 	contents, err := ioutil.ReadAll(response.Body)
 	if err != nil {
 		slog.Infof("JACK %v [get-balance] got error failed to read response %v", username, string(contents))
-		return 0, errors.New("failed to read response body")
+		return money.Money{}, errors.New("failed to read response body")
 	}
 
 	if response.StatusCode != http.StatusOK {
 		slog.Infof("JACK %v [get-balance] got error response status not ok", username)
-		return 0, errors.New(string(contents))
+		return money.Money{}, errors.New(string(contents))
 	}
 
 	// convert response to struct
 	err = json.Unmarshal(contents, &resp)
 	if err != nil {
 		slog.Infof("JACK %v [get-balance] error failed to unmarshall response %v", username, string(contents))
-		return 0, errors.New("failed to unmarshall response to struct")
+		return money.Money{}, errors.New("failed to unmarshall response to struct")
 	}
 
 	if resp.Status != constant.JackStatusOk {
 		slog.Infof("JACK %v [get-balance] got error response status not ok", username)
-		return 0, errors.New(converter.ToString(resp))
+		return money.Money{}, errors.New(converter.ToString(resp))
 	}
 
 	slog.Infof("JACK %v [get-balance]: %v", username, converter.ToString(resp))
 
-	return resp.Data.Balances[2].Balance, nil
+	return money.FromInt(int64(resp.Data.Balances[2].Balance)), nil
 }
 
-func (jk *jack) ConfirmDisbursement(payload dto.ConfirmTransactionPayload, credentials []entity.ProviderCredentialsEntity) (dto.CreateDisbursementRequestResponse, error) {
+func (jk *jack) ConfirmPayout(username string, providerReferenceId string, credentials []entity.ProviderCredentialsEntity) (dto.PayoutResult, error) {
 	var resp dto.CreateDisbursementRequestResponse
 	var cfg dto.JackCredentialsDto
 
@@ -193,12 +205,12 @@ func (jk *jack) ConfirmDisbursement(payload dto.ConfirmTransactionPayload, crede
 	}
 
 	// URL path param join
-	confirmTransactionURL, _ := url.JoinPath(cfg.DisbursementUrl, url.PathEscape(payload.ProviderID), "confirm")
+	confirmTransactionURL, _ := url.JoinPath(cfg.DisbursementUrl, url.PathEscape(providerReferenceId), "confirm")
 
 	// http request
 	r, err := http.NewRequest(http.MethodPost, confirmTransactionURL, nil)
 	if err != nil {
-		return resp, errors.New("failed to create request")
+		return dto.PayoutResult{}, errors.New("failed to create request")
 	}
 
 	r.Header.Add("Content-Type", "application/json")
@@ -207,7 +219,7 @@ func (jk *jack) ConfirmDisbursement(payload dto.ConfirmTransactionPayload, crede
 
 	response, err := jk.httpClient.Do(r)
 	if err != nil {
-		return resp, errors.New("failed to send request")
+		return dto.PayoutResult{}, errors.New("failed to send request")
 	}
 
 	defer func() {
@@ -220,38 +232,38 @@ func (jk *jack) ConfirmDisbursement(payload dto.ConfirmTransactionPayload, crede
 	// read response body
 	contents, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		slog.Infof("JACK %v [confirm-disbursement] got error failed to read response %v", payload.Username, string(contents))
-		return resp, errors.New("failed to read response body")
+		slog.Infof("JACK %v [confirm-disbursement] got error failed to read response %v", username, string(contents))
+		return dto.PayoutResult{}, errors.New("failed to read response body")
 	}
 
 	if response.StatusCode != http.StatusOK {
-		slog.Infof("JACK %v [confirm-disbursement] got error response status not ok", payload.Username)
-		return resp, errors.New(string(contents))
+		slog.Infof("JACK %v [confirm-disbursement] got error response status not ok", username)
+		return dto.PayoutResult{}, errors.New(string(contents))
 	}
 
 	// convert response to struct
 	err = json.Unmarshal(contents, &resp)
 	if err != nil {
-		slog.Infof("JACK %v [confirm-disbursement] error failed to unmarshall response %v", payload.Username, string(contents))
-		return resp, errors.New("failed to unmarshall response to struct")
+		slog.Infof("JACK %v [confirm-disbursement] error failed to unmarshall response %v", username, string(contents))
+		return dto.PayoutResult{}, errors.New("failed to unmarshall response to struct")
 	}
 
 	if resp.Status != constant.JackStatusOk {
-		slog.Infof("JACK %v [confirm-disbursement] response data with status not ok: %v", payload.Username, converter.ToString(resp))
-		return resp, errors.New(resp.Data.ErrorMessage)
+		slog.Infof("JACK %v [confirm-disbursement] response data with status not ok: %v", username, converter.ToString(resp))
+		return dto.PayoutResult{}, errors.New(resp.Data.ErrorMessage)
 	}
 
 	if resp.Data.State != constant.JackStateStatusConfirm {
-		slog.Infof("JACK %v [confirm-disbursement] response data with state not confirmed: %v", payload.Username, converter.ToString(resp))
-		return resp, errors.New("status not confirmed")
+		slog.Infof("JACK %v [confirm-disbursement] response data with state not confirmed: %v", username, converter.ToString(resp))
+		return dto.PayoutResult{}, errors.New("status not confirmed")
 	}
 
-	slog.Infof("JACK %v [confirm-disbursement] response data: %v", payload.Username, converter.ToString(resp))
+	slog.Infof("JACK %v [confirm-disbursement] response data: %v", username, converter.ToString(resp))
 
-	return resp, nil
+	return jackPayoutResult(resp.Data), nil
 }
 
-func (jk *jack) CreateDisbursement(payload dto.MerchantDisbursement, credentials []entity.ProviderCredentialsEntity, bankCode string, paymentId string) (dto.CreateDisbursementRequestResponse, error) {
+func (jk *jack) CreatePayout(payload dto.PayoutRequest, credentials []entity.ProviderCredentialsEntity) (dto.PayoutResult, error) {
 	var resp dto.CreateDisbursementRequestResponse
 	var cfg dto.JackCredentialsDto
 
@@ -269,7 +281,7 @@ func (jk *jack) CreateDisbursement(payload dto.MerchantDisbursement, credentials
 	firstAccountName, lastAccountName, err := helper.CheckingFirstAndLastStr(payload.BankAccountName)
 	if err != nil {
 		slog.Infof("JACK %v [create-disbursement] got failed there is no account name", payload.Username)
-		return resp, err
+		return dto.PayoutResult{}, err
 	}
 	// create disbursement payload request
 	senderData := dto.SenderData{
@@ -279,7 +291,7 @@ func (jk *jack) CreateDisbursement(payload dto.MerchantDisbursement, credentials
 	}
 
 	destinationData := dto.DestinationData{
-		Amount:         converter.ToString(payload.Amount),
+		Amount:         payload.Amount.String(),
 		Currency:       constant.JackDisbursementCurrency,
 		CountryIsoCode: constant.JackDisbursementCountryIsoName,
 	}
@@ -299,9 +311,9 @@ func (jk *jack) CreateDisbursement(payload dto.MerchantDisbursement, credentials
 	notes := constant.JackDisbursementNotes + " - " + payload.BankAccountName
 
 	requestData := dto.CreateDisbursementRequest{
-		ReferenceID: paymentId,
+		ReferenceID: payload.PaymentId,
 		CallbackURL: jk.configApp.CallbackUrl,
-		PayerID:     constant.TransformJackBank[bankCode].Id,
+		PayerID:     payload.Bank.ProviderBankId,
 		Mode:        constant.JackDisbursementMode,
 		Source:      sourceData,
 		Sender:      senderData,
@@ -317,7 +329,7 @@ func (jk *jack) CreateDisbursement(payload dto.MerchantDisbursement, credentials
 	// http request
 	r, err := http.NewRequest(http.MethodPost, cfg.DisbursementUrl, bytes.NewBuffer(payloadJSON))
 	if err != nil {
-		return resp, errors.New("failed to create request")
+		return dto.PayoutResult{}, errors.New("failed to create request")
 	}
 
 	r.Header.Add("Content-Type", "application/json")
@@ -326,7 +338,7 @@ func (jk *jack) CreateDisbursement(payload dto.MerchantDisbursement, credentials
 
 	response, err := jk.httpClient.Do(r)
 	if err != nil {
-		return resp, errors.New("failed to send request")
+		return dto.PayoutResult{}, errors.New("failed to send request")
 	}
 
 	defer func() {
@@ -340,27 +352,172 @@ func (jk *jack) CreateDisbursement(payload dto.MerchantDisbursement, credentials
 	contents, err := ioutil.ReadAll(response.Body)
 	if err != nil {
 		slog.Infof("JACK %v [create-disbursement] got error failed to read response %v", payload.Username, string(contents))
-		return resp, errors.New("failed to read response body")
+		return dto.PayoutResult{}, errors.New("failed to read response body")
 	}
 
 	if response.StatusCode != http.StatusOK {
 		slog.Infof("JACK %v [create-disbursement] got error response status not ok: %v", payload.Username, string(contents))
-		return resp, errors.New(string(contents))
+		return dto.PayoutResult{}, errors.New(string(contents))
 	}
 
 	// convert response to struct
 	err = json.Unmarshal(contents, &resp)
 	if err != nil {
 		slog.Infof("JACK %v [create-disbursement] error failed to unmarshall response %v", payload.Username, string(contents))
-		return resp, errors.New("failed to unmarshall response to struct")
+		return dto.PayoutResult{}, errors.New("failed to unmarshall response to struct")
 	}
 
 	if resp.Status != constant.JackStatusOk {
 		slog.Infof("JACK %v [create-disbursement] got error response status not ok: %v", payload.Username, converter.ToString(resp))
-		return resp, errors.New(resp.Data.ErrorMessage)
+		return dto.PayoutResult{}, errors.New(resp.Data.ErrorMessage)
 	}
 
 	slog.Infof("JACK %v [create-disbursement] response data: %v", payload.Username, converter.ToString(resp))
 
-	return resp, nil
+	return jackPayoutResult(resp.Data), nil
+}
+
+func (jk *jack) GetPayoutStatus(username string, providerReferenceId string, credentials []entity.ProviderCredentialsEntity) (dto.PayoutResult, error) {
+	var resp dto.CreateDisbursementRequestResponse
+	var cfg dto.JackCredentialsDto
+
+	for _, cred := range credentials {
+		if cred.Key == constant.JackApiKeyCred {
+			cfg.ApiKey = cred.Value
+		}
+
+		if cred.Key == constant.JackDisbursementUrlCred {
+			cfg.DisbursementUrl = cred.Value
+		}
+	}
+
+	// URL path param join
+	statusTransactionURL, _ := url.JoinPath(cfg.DisbursementUrl, url.PathEscape(providerReferenceId))
+
+	// http request
+	r, err := http.NewRequest(http.MethodGet, statusTransactionURL, nil)
+	if err != nil {
+		return dto.PayoutResult{}, errors.New("failed to create request")
+	}
+
+	r.Header.Add("Content-Type", "application/json")
+	r.Header.Add("Authorization", cfg.ApiKey)
+	r.Close = true
+
+	response, err := jk.httpClient.Do(r)
+	if err != nil {
+		return dto.PayoutResult{}, errors.New("failed to send request")
+	}
+
+	defer func() {
+		err = response.Body.Close()
+		if err != nil {
+			log.Println("failed to close response body, could lead to memory leak")
+		}
+	}()
+
+	// read response body
+	contents, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		slog.Infof("JACK %v [status-disbursement] got error failed to read response %v", username, string(contents))
+		return dto.PayoutResult{}, errors.New("failed to read response body")
+	}
+
+	if response.StatusCode != http.StatusOK {
+		slog.Infof("JACK %v [status-disbursement] got error response status not ok: %v", username, string(contents))
+		return dto.PayoutResult{}, errors.New(string(contents))
+	}
+
+	// convert response to struct
+	err = json.Unmarshal(contents, &resp)
+	if err != nil {
+		slog.Infof("JACK %v [status-disbursement] error failed to unmarshall response %v", username, string(contents))
+		return dto.PayoutResult{}, errors.New("failed to unmarshall response to struct")
+	}
+
+	if resp.Status != constant.JackStatusOk {
+		slog.Infof("JACK %v [status-disbursement] got error response status not ok: %v", username, converter.ToString(resp))
+		return dto.PayoutResult{}, errors.New(resp.Data.ErrorMessage)
+	}
+
+	slog.Infof("JACK %v [status-disbursement] response data: %v", username, converter.ToString(resp))
+
+	return jackPayoutResult(resp.Data), nil
+}
+
+func (jk *jack) ParseCallback(body []byte) (dto.PayoutResult, error) {
+	var payload dto.CreateDisbursementRequestResponseData
+
+	err := json.Unmarshal(body, &payload)
+	if err != nil {
+		slog.Infof("JACK [callback] error failed to unmarshall payload %v", string(body))
+		return dto.PayoutResult{}, errors.New("invalid request body")
+	}
+
+	slog.Infof("JACK %v [callback] payload: %v", payload.ReferenceID, converter.ToString(payload))
+
+	if _, err = money.Parse(payload.Destination.Amount); err != nil {
+		return dto.PayoutResult{}, errors.New("invalid amount")
+	}
+
+	return jackPayoutResult(payload), nil
+}
+
+// VerifyCallback accepts a hex hmac-sha256 of the body in X-Callback-Signature or the shared
+// secret itself in X-Callback-Token, any configured callback secret may match
+func (jk *jack) VerifyCallback(headers http.Header, body []byte, credentials []entity.ProviderCredentialsEntity) error {
+	signature := headers.Get(constant.JackCallbackSignatureHeader)
+	token := headers.Get(constant.JackCallbackTokenHeader)
+	if signature == "" && token == "" {
+		return errors.New("callback is not signed")
+	}
+
+	hasSecret := false
+	for _, cred := range credentials {
+		if cred.Key != constant.CallbackSecretCred || cred.Value == "" {
+			continue
+		}
+		hasSecret = true
+
+		if signature != "" {
+			mac := hmac.New(sha256.New, []byte(cred.Value))
+			mac.Write(body)
+			expected := hex.EncodeToString(mac.Sum(nil))
+			if hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+				return nil
+			}
+		}
+
+		if token != "" && subtle.ConstantTimeCompare([]byte(cred.Value), []byte(token)) == 1 {
+			return nil
+		}
+	}
+
+	if !hasSecret {
+		return errors.New("callback secret is not configured")
+	}
+
+	return errors.New("invalid callback signature")
+}
+
+// jackPayoutResult translates jack disbursement state into the platform status
+func jackPayoutResult(data dto.CreateDisbursementRequestResponseData) dto.PayoutResult {
+	status := constant.StatusProcessing
+	switch data.State {
+	case constant.JackStateStatusCompleted:
+		status = constant.StatusSuccess
+	case constant.JackStateStatusDeclined, constant.JackStateStatusCanceled:
+		status = constant.StatusFailed
+	}
+
+	// amount is validated where it matters, create and confirm responses only need the state
+	amount, _ := money.Parse(data.Destination.Amount)
+
+	return dto.PayoutResult{
+		PaymentId:           data.ReferenceID,
+		ProviderReferenceId: converter.ToString(data.ID),
+		Status:              status,
+		Amount:              amount,
+		ErrorMessage:        data.ErrorMessage,
+	}
 }
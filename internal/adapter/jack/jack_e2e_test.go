@@ -0,0 +1,452 @@
+//go:build e2e
+
+// End to end suite of the jack disbursement flow against cmd/jack-simulator, run with
+//
+//	go test -tags e2e ./internal/adapter/jack/
+//
+// The simulator is built and started on a free port, its callbacks are received by the suite
+// and go through the same verify and parse steps as the provider callback endpoint.
+package jack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/config"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+const (
+	e2eApiKey         = "e2e-api-key"
+	e2eCallbackSecret = "e2e-callback-secret"
+
+	e2eAccountCompleted         = "2000000001"
+	e2eAccountDeclined          = "2000000002"
+	e2eAccountCanceled          = "2000000003"
+	e2eAccountDuplicateCallback = "2000000004"
+	e2eAccountTimeout           = "2000000005"
+	e2eAccountNameMismatch      = "2000000006"
+	e2eAccountValidationError   = "2000000007"
+
+	e2eAccountName = "Budi Santoso"
+)
+
+var (
+	simulatorUrl string
+	callbacks    = &callbackInbox{received: map[string]chan receivedCallback{}}
+)
+
+type receivedCallback struct {
+	header http.Header
+	body   []byte
+}
+
+// callbackInbox keeps the callbacks sent by the simulator per reference id
+type callbackInbox struct {
+	mu       sync.Mutex
+	received map[string]chan receivedCallback
+}
+
+func (ci *callbackInbox) channel(referenceId string) chan receivedCallback {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	ch, ok := ci.received[referenceId]
+	if !ok {
+		ch = make(chan receivedCallback, 4)
+		ci.received[referenceId] = ch
+	}
+
+	return ch
+}
+
+func (ci *callbackInbox) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	var payload dto.CreateDisbursementRequestResponseData
+	_ = json.Unmarshal(body, &payload)
+
+	ci.channel(payload.ReferenceID) <- receivedCallback{header: r.Header.Clone(), body: body}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (ci *callbackInbox) wait(t *testing.T, referenceId string, timeout time.Duration) (receivedCallback, bool) {
+	t.Helper()
+
+	select {
+	case cb := <-ci.channel(referenceId):
+		return cb, true
+	case <-time.After(timeout):
+		return receivedCallback{}, false
+	}
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(runSuite(m))
+}
+
+func runSuite(m *testing.M) int {
+	slog.NewLogger(slog.Info)
+
+	dir, err := os.MkdirTemp("", "jack-simulator")
+	if err != nil {
+		fmt.Println("failed to create temp dir:", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+
+	binary := filepath.Join(dir, "jack-simulator")
+	build := exec.Command("go", "build", "-o", binary, "github.com/hypay-id/backend-dashboard-hypay/cmd/jack-simulator")
+	build.Stdout, build.Stderr = os.Stdout, os.Stderr
+	if err = build.Run(); err != nil {
+		fmt.Println("failed to build jack simulator:", err)
+		return 1
+	}
+
+	port, err := freePort()
+	if err != nil {
+		fmt.Println("failed to find a free port:", err)
+		return 1
+	}
+
+	// an empty -callback-url makes the simulator call back the callback_url sent by the adapter
+	simulator := exec.Command(binary, "-port", port, "-api-key", e2eApiKey, "-callback-secret", e2eCallbackSecret, "-callback-url=")
+	simulator.Stdout, simulator.Stderr = io.Discard, os.Stderr
+	if err = simulator.Start(); err != nil {
+		fmt.Println("failed to start jack simulator:", err)
+		return 1
+	}
+	defer func() {
+		_ = simulator.Process.Kill()
+		_ = simulator.Wait()
+	}()
+
+	simulatorUrl = "http://127.0.0.1:" + port
+	if err = waitReady(simulatorUrl+"/simulator/scenarios", 10*time.Second); err != nil {
+		fmt.Println("jack simulator is not ready:", err)
+		return 1
+	}
+
+	return m.Run()
+}
+
+func freePort() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer l.Close()
+
+	_, port, err := net.SplitHostPort(l.Addr().String())
+	return port, err
+}
+
+func waitReady(url string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		response, err := http.Get(url)
+		if err == nil {
+			response.Body.Close()
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// newE2eJack returns the adapter pointed at the simulator with callbacks going to a local receiver
+func newE2eJack(t *testing.T) (*jack, []entity.ProviderCredentialsEntity) {
+	t.Helper()
+
+	receiver := httptest.NewServer(callbacks)
+	t.Cleanup(receiver.Close)
+
+	putScenarios(t, map[string]interface{}{
+		"default":             "completed",
+		"defaultAccountName":  e2eAccountName,
+		"balance":             500000000,
+		"callbackDelaySecond": 0,
+		"timeoutDelaySecond":  3,
+		"accounts": map[string]interface{}{
+			e2eAccountDeclined:          map[string]string{"scenario": "declined"},
+			e2eAccountCanceled:          map[string]string{"scenario": "canceled"},
+			e2eAccountDuplicateCallback: map[string]string{"scenario": "duplicate_callback"},
+			e2eAccountTimeout:           map[string]string{"scenario": "timeout"},
+			e2eAccountNameMismatch:      map[string]string{"scenario": "name_mismatch"},
+			e2eAccountValidationError:   map[string]string{"scenario": "validation_error"},
+		},
+	})
+
+	credentials := []entity.ProviderCredentialsEntity{
+		{ProviderId: constant.ProviderJack, Key: constant.JackApiKeyCred, Value: e2eApiKey},
+		{ProviderId: constant.ProviderJack, Key: constant.JackInquiryKeyUrlCred, Value: simulatorUrl + "/inquiry"},
+		{ProviderId: constant.ProviderJack, Key: constant.JackGetBalanceKeyUrlCred, Value: simulatorUrl + "/balance"},
+		{ProviderId: constant.ProviderJack, Key: constant.JackDisbursementUrlCred, Value: simulatorUrl + "/disbursements"},
+		{ProviderId: constant.ProviderJack, Key: constant.CallbackSecretCred, Value: e2eCallbackSecret},
+	}
+
+	return New(config.App{CallbackUrl: receiver.URL}), credentials
+}
+
+func putScenarios(t *testing.T, cfg map[string]interface{}) {
+	t.Helper()
+
+	payload, _ := json.Marshal(cfg)
+	r, _ := http.NewRequest(http.MethodPut, simulatorUrl+"/simulator/scenarios", bytes.NewBuffer(payload))
+	r.Header.Set("Content-Type", "application/json")
+
+	response, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatalf("failed to put scenarios: %v", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(response.Body)
+		t.Fatalf("put scenarios got status %v: %s", response.StatusCode, body)
+	}
+}
+
+func simulatorTransaction(t *testing.T, referenceId string) (dto.CreateDisbursementRequestResponseData, bool) {
+	t.Helper()
+
+	response, err := http.Get(simulatorUrl + "/simulator/transactions")
+	if err != nil {
+		t.Fatalf("failed to list simulator transactions: %v", err)
+	}
+	defer response.Body.Close()
+
+	var transactions []dto.CreateDisbursementRequestResponseData
+	if err = json.NewDecoder(response.Body).Decode(&transactions); err != nil {
+		t.Fatalf("failed to decode simulator transactions: %v", err)
+	}
+
+	for _, transaction := range transactions {
+		if transaction.ReferenceID == referenceId {
+			return transaction, true
+		}
+	}
+
+	return dto.CreateDisbursementRequestResponseData{}, false
+}
+
+func e2ePayoutRequest(accountNumber string) dto.PayoutRequest {
+	return dto.PayoutRequest{
+		Username:          "e2e",
+		PaymentId:         fmt.Sprintf("E2E%v", time.Now().UnixNano()),
+		Amount:            money.MustParse("150000"),
+		BankAccountNumber: accountNumber,
+		BankAccountName:   e2eAccountName,
+		Bank: entity.ProviderBankCodeEntity{
+			ProviderId:       constant.ProviderJack,
+			BankCode:         "014",
+			ProviderBankId:   "1",
+			ProviderBankName: "BCA",
+		},
+	}
+}
+
+// disburse runs inquiry, create and confirm the way the disbursement service does
+func disburse(t *testing.T, jk *jack, credentials []entity.ProviderCredentialsEntity, payload dto.PayoutRequest) dto.PayoutResult {
+	t.Helper()
+
+	inquiry, err := jk.InquiryAccount(payload, credentials)
+	if err != nil {
+		t.Fatalf("InquiryAccount got err %v", err)
+	}
+	if inquiry.AccountName != payload.BankAccountName {
+		t.Fatalf("InquiryAccount name = %v, want %v", inquiry.AccountName, payload.BankAccountName)
+	}
+
+	created, err := jk.CreatePayout(payload, credentials)
+	if err != nil {
+		t.Fatalf("CreatePayout got err %v", err)
+	}
+	if created.PaymentId != payload.PaymentId || created.Status != constant.StatusProcessing {
+		t.Fatalf("CreatePayout = %+v, want processing %v", created, payload.PaymentId)
+	}
+
+	confirmed, err := jk.ConfirmPayout(payload.Username, created.ProviderReferenceId, credentials)
+	if err != nil {
+		t.Fatalf("ConfirmPayout got err %v", err)
+	}
+	if confirmed.Status != constant.StatusProcessing {
+		t.Fatalf("ConfirmPayout status = %v, want %v", confirmed.Status, constant.StatusProcessing)
+	}
+
+	return confirmed
+}
+
+// settleByCallback verifies and parses the next callback of payload the way the callback endpoint does
+func settleByCallback(t *testing.T, jk *jack, credentials []entity.ProviderCredentialsEntity, paymentId string) dto.PayoutResult {
+	t.Helper()
+
+	cb, ok := callbacks.wait(t, paymentId, 10*time.Second)
+	if !ok {
+		t.Fatalf("no callback received for %v", paymentId)
+	}
+
+	if err := jk.VerifyCallback(cb.header, cb.body, credentials); err != nil {
+		t.Fatalf("VerifyCallback got err %v", err)
+	}
+
+	result, err := jk.ParseCallback(cb.body)
+	if err != nil {
+		t.Fatalf("ParseCallback got err %v", err)
+	}
+	if result.PaymentId != paymentId {
+		t.Fatalf("callback payment id = %v, want %v", result.PaymentId, paymentId)
+	}
+
+	return result
+}
+
+func TestE2eDisbursementSettled(t *testing.T) {
+	jk, credentials := newE2eJack(t)
+
+	balance, err := jk.GetBalance("e2e", credentials)
+	if err != nil || balance.String() != "500000000.00" {
+		t.Fatalf("GetBalance = %v, %v, want 500000000.00", balance, err)
+	}
+
+	tests := []struct {
+		name         string
+		account      string
+		wantStatus   string
+		wantErrorMsg string
+	}{
+		{"completed", e2eAccountCompleted, constant.StatusSuccess, ""},
+		{"declined", e2eAccountDeclined, constant.StatusFailed, "declined by simulator"},
+		{"canceled", e2eAccountCanceled, constant.StatusFailed, "canceled by simulator"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := e2ePayoutRequest(tt.account)
+			confirmed := disburse(t, jk, credentials, payload)
+
+			result := settleByCallback(t, jk, credentials, payload.PaymentId)
+			if result.Status != tt.wantStatus || result.ErrorMessage != tt.wantErrorMsg {
+				t.Errorf("callback = %+v, want status %v error %q", result, tt.wantStatus, tt.wantErrorMsg)
+			}
+			if !result.Amount.Equal(payload.Amount) {
+				t.Errorf("callback amount = %v, want %v", result.Amount, payload.Amount)
+			}
+
+			// the status endpoint used by reconciliation agrees with the callback
+			status, err := jk.GetPayoutStatus(payload.Username, confirmed.ProviderReferenceId, credentials)
+			if err != nil {
+				t.Fatalf("GetPayoutStatus got err %v", err)
+			}
+			if status.Status != tt.wantStatus {
+				t.Errorf("GetPayoutStatus status = %v, want %v", status.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestE2eDisbursementDuplicateCallback(t *testing.T) {
+	jk, credentials := newE2eJack(t)
+
+	payload := e2ePayoutRequest(e2eAccountDuplicateCallback)
+	confirmed := disburse(t, jk, credentials, payload)
+
+	first := settleByCallback(t, jk, credentials, payload.PaymentId)
+	second := settleByCallback(t, jk, credentials, payload.PaymentId)
+
+	// both deliveries describe the same final state, so settling twice must be a no op
+	if first != second {
+		t.Errorf("duplicate callbacks differ: %+v and %+v", first, second)
+	}
+	if first.Status != constant.StatusSuccess || first.ProviderReferenceId != confirmed.ProviderReferenceId {
+		t.Errorf("callback = %+v, want success of %v", first, confirmed.ProviderReferenceId)
+	}
+}
+
+func TestE2eDisbursementCallbackTampered(t *testing.T) {
+	jk, credentials := newE2eJack(t)
+
+	payload := e2ePayoutRequest(e2eAccountCompleted)
+	disburse(t, jk, credentials, payload)
+
+	cb, ok := callbacks.wait(t, payload.PaymentId, 10*time.Second)
+	if !ok {
+		t.Fatalf("no callback received for %v", payload.PaymentId)
+	}
+
+	tampered := bytes.Replace(cb.body, []byte(`"completed"`), []byte(`"declined"`), 1)
+	if err := jk.VerifyCallback(cb.header, tampered, credentials); err == nil {
+		t.Error("VerifyCallback of a tampered body got no err")
+	}
+
+	unsigned := http.Header{}
+	if err := jk.VerifyCallback(unsigned, cb.body, credentials); err == nil {
+		t.Error("VerifyCallback of an unsigned body got no err")
+	}
+}
+
+func TestE2eDisbursementTimeout(t *testing.T) {
+	jk, credentials := newE2eJack(t)
+	// the simulator holds the response for 3 seconds, well past this timeout
+	jk.httpClient.Timeout = time.Second
+
+	payload := e2ePayoutRequest(e2eAccountTimeout)
+
+	_, err := jk.CreatePayout(payload, credentials)
+	if err == nil {
+		t.Fatal("CreatePayout got no err on timeout")
+	}
+
+	// the provider still creates the disbursement once it answers, it stays unconfirmed and
+	// never calls back, which is what reconciliation has to pick up
+	transaction, ok := simulatorTransaction(t, payload.PaymentId)
+	for deadline := time.Now().Add(5 * time.Second); !ok && time.Now().Before(deadline); {
+		time.Sleep(200 * time.Millisecond)
+		transaction, ok = simulatorTransaction(t, payload.PaymentId)
+	}
+	if !ok {
+		t.Fatalf("simulator has no transaction for %v", payload.PaymentId)
+	}
+	if transaction.State != "created" {
+		t.Errorf("simulator transaction state = %v, want created", transaction.State)
+	}
+
+	if _, ok = callbacks.wait(t, payload.PaymentId, time.Second); ok {
+		t.Error("got a callback for an unconfirmed disbursement")
+	}
+}
+
+func TestE2eDisbursementInquiryRejected(t *testing.T) {
+	jk, credentials := newE2eJack(t)
+
+	mismatch, err := jk.InquiryAccount(e2ePayoutRequest(e2eAccountNameMismatch), credentials)
+	if err != nil {
+		t.Fatalf("InquiryAccount got err %v", err)
+	}
+	if mismatch.AccountName == e2eAccountName {
+		t.Errorf("InquiryAccount name = %v, want a mismatching name", mismatch.AccountName)
+	}
+
+	_, err = jk.InquiryAccount(e2ePayoutRequest(e2eAccountValidationError), credentials)
+	if err == nil || err.Error() != "account number is not valid" {
+		t.Errorf("InquiryAccount got err %v, want account number is not valid", err)
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/hypay-id/backend-dashboard-hypay/config"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
@@ -30,16 +32,15 @@ func New(cfg config.App) *merchantCallback {
 	}
 }
 
-func (mc *merchantCallback) SendCallbackAdptr(url string, transactionEntity entity.PaymentDetailMerchantProvider, transactionStatusLogLatest entity.TransactionStatusLogs, merchantSecret string) (interface{}, error) {
+func (mc *merchantCallback) SendCallbackAdptr(url string, transactionEntity entity.PaymentDetailMerchantProvider, transactionStatusLogLatest entity.TransactionStatusLogs, signingKeys []dto.MerchantSigningKey) (interface{}, error) {
 	var merchantResponse interface{}
-	amountFormatted := helper.FormatFloat64(transactionEntity.TransactionAmount)
 
 	// request data to merchant
 	requestData := dto.MerchantCallbackDto{
 		TransactionId:         transactionEntity.PaymentID,
 		MerchantTransactionId: transactionEntity.MerchantRefNumber,
 		Status:                transactionEntity.Status,
-		Amount:                amountFormatted,
+		Amount:                transactionEntity.TransactionAmount,
 		TransactionType:       transactionEntity.PaymentMethodName,
 		TransactionCreatedAt:  transactionEntity.TransactionCreatedAt,
 		TransactionUpdatedAt:  transactionEntity.TransactionUpdatedAt,
@@ -52,7 +53,17 @@ func (mc *merchantCallback) SendCallbackAdptr(url string, transactionEntity enti
 	// payload json
 	payloadJson, _ := json.Marshal(requestData)
 
-	signature := helper.StringToSignatureSymmetric(string(payloadJson), merchantSecret)
+	// x-signature keeps the active key for merchants that verify one signature, x-signatures carries every live
+	// key as v<version>=<signature> so a rotation doesn't break merchants that are still deploying the new key
+	var signature string
+	signatures := make([]string, len(signingKeys))
+	for i, key := range signingKeys {
+		keySignature := helper.StringToSignatureSymmetric(string(payloadJson), key.Secret)
+		if i == 0 {
+			signature = keySignature
+		}
+		signatures[i] = fmt.Sprintf("v%v=%v", key.Version, keySignature)
+	}
 
 	r, err := http.NewRequest(http.MethodPost, transactionEntity.MerchantCallbackURL, bytes.NewBuffer(payloadJson))
 	if err != nil {
@@ -61,6 +72,10 @@ func (mc *merchantCallback) SendCallbackAdptr(url string, transactionEntity enti
 
 	r.Header.Add("Content-Type", "application/json")
 	r.Header.Add("x-signature", signature)
+	if len(signingKeys) > 0 {
+		r.Header.Add("x-signature-version", converter.ToString(signingKeys[0].Version))
+		r.Header.Add("x-signatures", strings.Join(signatures, ","))
+	}
 	r.Close = true
 
 	response, err := mc.httpClient.Do(r)
@@ -83,7 +98,7 @@ func (mc *merchantCallback) SendCallbackAdptr(url string, transactionEntity enti
 		return merchantResponse, err
 	}
 
-	if response.StatusCode != http.StatusOK {
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
 		merchantResponse = converter.ToString(response.StatusCode) + ":" + string(contents)
 		return merchantResponse, errors.New("status not ok")
 	}
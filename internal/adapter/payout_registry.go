@@ -0,0 +1,44 @@
+package adapter
+
+import (
+	"fmt"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+)
+
+type payoutRegistry struct {
+	providers map[string]internal.PayoutProviderItf
+}
+
+func newPayoutRegistry() *payoutRegistry {
+	return &payoutRegistry{
+		providers: map[string]internal.PayoutProviderItf{},
+	}
+}
+
+// Register adds the adapter used for every interface setting of providerId
+func (pr *payoutRegistry) Register(providerId string, provider internal.PayoutProviderItf) {
+	pr.providers[providerId] = provider
+}
+
+// RegisterInterface adds an adapter used only for one interface setting of providerId
+func (pr *payoutRegistry) RegisterInterface(providerId string, interfaceSetting string, provider internal.PayoutProviderItf) {
+	pr.providers[payoutRegistryKey(providerId, interfaceSetting)] = provider
+}
+
+// Resolve prefers the adapter of the interface setting and falls back to the provider adapter
+func (pr *payoutRegistry) Resolve(providerId string, interfaceSetting string) (internal.PayoutProviderItf, error) {
+	if provider, ok := pr.providers[payoutRegistryKey(providerId, interfaceSetting)]; ok {
+		return provider, nil
+	}
+
+	if provider, ok := pr.providers[providerId]; ok {
+		return provider, nil
+	}
+
+	return nil, fmt.Errorf("payout provider %v is not registered", providerId)
+}
+
+func payoutRegistryKey(providerId string, interfaceSetting string) string {
+	return providerId + "/" + interfaceSetting
+}
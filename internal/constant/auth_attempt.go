@@ -0,0 +1,26 @@
+package constant
+
+import "time"
+
+const (
+	AuthScopeUser = "USER"
+	AuthScopeIp   = "IP"
+
+	AuthCredentialPassword = "PASSWORD"
+	AuthCredentialPin      = "PIN"
+	// AuthCredentialReset counts reset link requests, every request counts and not only failed ones
+	AuthCredentialReset = "RESET"
+)
+
+const (
+	// AuthAttemptWindow forgets failures older than this, the count starts again after a quiet period
+	AuthAttemptWindow = OneHour
+	// AuthDelayAfter failures in a row are free, every further one doubles the wait before the next check
+	AuthDelayAfter = 2
+	AuthMaxDelay   = ThirtySecond
+	AuthLockout    = 15 * time.Minute
+
+	// an address is shared by every user behind it so it gets more room than a single user
+	AuthUserLockThreshold = 5
+	AuthIpLockThreshold   = 20
+)
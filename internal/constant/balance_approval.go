@@ -0,0 +1,17 @@
+package constant
+
+// manual balance operations that need approval once their amount reaches the threshold of their type
+const (
+	BalanceOperationTopUp            = "TOP_UP"
+	BalanceOperationHold             = "HOLD"
+	BalanceOperationSettlement       = "SETTLEMENT"
+	BalanceOperationTransfer         = "TRANSFER"
+	BalanceOperationPayoutSettlement = "PAYOUT_SETTLEMENT"
+	BalanceOperationReverse          = "REVERSE"
+)
+
+const (
+	BalanceRequestPendingApproval = "PENDING_APPROVAL"
+	BalanceRequestApproved        = "APPROVED"
+	BalanceRequestRejected        = "REJECTED"
+)
@@ -0,0 +1,16 @@
+package constant
+
+const (
+	CallbackDeliveryPending    = "PENDING"
+	CallbackDeliveryDelivered  = "DELIVERED"
+	CallbackDeliveryDeadLetter = "DEAD_LETTER"
+	// CallbackDeliverySuperseded is a pending delivery replaced by a newer status of the same payment
+	CallbackDeliverySuperseded = "SUPERSEDED"
+)
+
+const (
+	CallbackDeliveryInterval  = ThirtySecond
+	CallbackDeliveryBatchSize = 50
+	// CallbackDeliveryLease hides a claimed delivery from other instances while it's being sent
+	CallbackDeliveryLease = 2 * NinetySecond
+)
@@ -1,5 +1,7 @@
 package constant
 
+import "github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+
 const CreateBySystem = "SYSTEM"
 const BucketName = "hypaystagingreportstorage"
 const BusinessHypayEmail = "business@hypay.id"
@@ -7,6 +9,12 @@ const MainType = "MAIN"
 const GeneralErrMsg = "failed, please ask admin for more info"
 const BankAccountNameSimilarityMatchInPercent = 0.3
 
+// disbursement amount accepted from provider callbacks
+var (
+	DisbursementMinAmount = money.FromInt(10000)
+	DisbursementMaxAmount = money.FromInt(500000000)
+)
+
 const (
 	IpAddressHypay   = "0.0.0.0"
 	CallbackUrlHypay = "https://www.hypay.id"
@@ -43,6 +51,8 @@ const (
 	RoleNameAdmin           = "admin"
 	RoleNameCustomerSupport = "customer support"
 	RoleNameFinance         = "finance"
+	// RoleNameMerchantApprover approves the disbursements of its merchant
+	RoleNameMerchantApprover = "merchant approver"
 )
 
 const (
@@ -50,6 +60,8 @@ const (
 	StatusFailed     = "FAILED"
 	StatusProcessing = "PROCESSING"
 	StatusReversed   = "REVERSED"
+	StatusExpired    = "EXPIRED"
+	StatusRefunded   = "REFUNDED"
 )
 
 const (
@@ -64,6 +76,9 @@ const (
 	StatusLogFailed             = "FAILED / REFUSED BY PROVIDER"
 	StatusLogAcceptedByPlatform = "ACCEPTED BY PLATFORM"
 	StatusLogAcceptedByProvider = "ACCEPTED BY PROVIDER"
+	StatusLogReversed           = "REVERSED BY OPERATION"
+	StatusLogExpired            = "EXPIRED WITHOUT PAYMENT"
+	StatusLogRefunded           = "REFUNDED TO PAYER"
 )
 
 const (
@@ -144,7 +159,3 @@ const InternalExport = "[INTERNAL-EXPORT]"
 const (
 	ProviderJack = "ID-JACK"
 )
-
-var ProviderListName = []string{
-	"ID-JACK",
-}
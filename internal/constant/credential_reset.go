@@ -0,0 +1,17 @@
+package constant
+
+import "time"
+
+const (
+	CredentialResetTTL = 30 * time.Minute
+	// CredentialResetAudience keeps reset tokens apart from access tokens signed with the same secret
+	CredentialResetAudience = "credential-reset"
+	// CredentialResetPath is the dashboard page that reads the token from the emailed link
+	CredentialResetPath = "/reset-credential"
+
+	// CredentialResetRequestWindow is how long reset requests are counted and how long a subject that asked
+	// too often is held back
+	CredentialResetRequestWindow = OneHour
+	CredentialResetUserLimit     = 3
+	CredentialResetIpLimit       = 20
+)
@@ -0,0 +1,28 @@
+package constant
+
+// a disbursement request waits for approval, is processing once the last approval sends it to the provider and
+// ends as disbursed, failed or rejected
+const (
+	DisbursementRequestPendingApproval = "PENDING_APPROVAL"
+	DisbursementRequestProcessing      = "PROCESSING"
+	DisbursementRequestDisbursed       = "DISBURSED"
+	DisbursementRequestFailed          = "FAILED"
+	DisbursementRequestRejected        = "REJECTED"
+)
+
+const (
+	DisbursementReviewApproved = "APPROVED"
+	DisbursementReviewRejected = "REJECTED"
+)
+
+// DisbursementMaxApprovers caps the approvers a rule can ask for
+const DisbursementMaxApprovers = 5
+
+const DisbursementRequestInterruptedMsg = "disbursement was interrupted, check the transaction list before disbursing it again"
+
+const (
+	// DisbursementRequestStaleInterval is how often requests left processing are looked for
+	DisbursementRequestStaleInterval = FiveMinutes
+	// DisbursementRequestStaleAfter is how long a request stays processing before it is taken as interrupted
+	DisbursementRequestStaleAfter = FifteenMinutes
+)
@@ -0,0 +1,48 @@
+package constant
+
+// a batch is uploaded with its rows validated, processing once confirmed and completed when every valid row
+// was disbursed, failed or sent for approval. A batch stopped by an unexpected error is failed, its rows keep
+// the status they got so far.
+const (
+	DisbursementBatchUploaded   = "UPLOADED"
+	DisbursementBatchProcessing = "PROCESSING"
+	DisbursementBatchCompleted  = "COMPLETED"
+	DisbursementBatchFailed     = "FAILED"
+)
+
+const (
+	DisbursementBatchRowValid           = "VALID"
+	DisbursementBatchRowInvalid         = "INVALID"
+	DisbursementBatchRowProcessing      = "PROCESSING"
+	DisbursementBatchRowDisbursed       = "DISBURSED"
+	DisbursementBatchRowPendingApproval = "PENDING_APPROVAL"
+	DisbursementBatchRowFailed          = "FAILED"
+	// DisbursementBatchRowInterrupted is a row stopped while it was being disbursed, the provider may have taken
+	// it so it is never disbursed again on its own
+	DisbursementBatchRowInterrupted = "INTERRUPTED"
+)
+
+const DisbursementBatchRowInterruptedMsg = "disbursement was interrupted, check the transaction list before disbursing it again"
+
+const (
+	// DisbursementBatchMaxFileSize is the largest file accepted for a batch
+	DisbursementBatchMaxFileSize = 5 << 20
+	// DisbursementBatchMaxRows caps the rows of a batch, the header row not counted
+	DisbursementBatchMaxRows = 1000
+	// DisbursementBatchConcurrency caps the rows of a batch sent to the provider at the same time
+	DisbursementBatchConcurrency = 5
+	// DisbursementBatchResumeInterval is how often processing batches left behind by a stopped instance are looked for
+	DisbursementBatchResumeInterval = FiveMinutes
+	// DisbursementBatchStaleAfter is how long a processing batch goes without any of its rows changing before it is
+	// taken as left behind and resumed
+	DisbursementBatchStaleAfter = FifteenMinutes
+)
+
+// DisbursementBatchHeaders are the columns of a batch file in this order, the first row of the file is the header
+var DisbursementBatchHeaders = []string{
+	"Bank Code",
+	"Bank Account Number",
+	"Bank Account Name",
+	"Amount",
+	"Note",
+}
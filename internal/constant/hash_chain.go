@@ -0,0 +1,26 @@
+package constant
+
+// the hash chained tables, capital flows are chained per merchant account and the audit trail per history type
+const (
+	ChainCapitalFlow = "merchant_capital_flows"
+	ChainAudit       = "histories_operations"
+	// ChainAuditKey is the key of the audit rows chained before the audit trail was chained per history type
+	ChainAuditKey = "all"
+	// ChainCheckpoints names the checkpoints in a break found on a checkpoint itself
+	ChainCheckpoints = "chain_checkpoints"
+)
+
+// why a link of a chain is broken
+const (
+	ChainBreakSequence   = "SEQUENCE_GAP"
+	ChainBreakPrevHash   = "PREV_HASH_MISMATCH"
+	ChainBreakHash       = "HASH_MISMATCH"
+	ChainBreakCheckpoint = "CHECKPOINT_MISMATCH"
+	ChainBreakUnchained  = "UNCHAINED_ROWS"
+)
+
+const (
+	// ChainVerifyBatch is how many links are read at once while walking a chain
+	ChainVerifyBatch        = 1000
+	ChainCheckpointInterval = OneDay
+)
@@ -0,0 +1,70 @@
+package constant
+
+// history types and activities written to histories_operations
+const (
+	HistoryTypeSecurity = "SECURITY"
+
+	HistoryActivityLockout = "LOCKOUT"
+	HistoryActivityUnlock  = "UNLOCK"
+)
+
+const (
+	HistoryTypeUserManagement = "USER_MANAGEMENT"
+
+	HistoryActivityUpdateUserStatus = "UPDATE_USER_STATUS"
+	HistoryActivityUpdateUserRole   = "UPDATE_USER_ROLE"
+	HistoryActivityRemoveUser       = "REMOVE_USER"
+)
+
+// HistoryTypeRequest rows are written for every mutating dashboard request, their activity is the method and
+// route. the domain rows written by the same request share its request id.
+const HistoryTypeRequest = "REQUEST"
+
+const (
+	HistoryTypeMerchant           = "MERCHANT"
+	HistoryTypeMerchantPaychannel = "MERCHANT_PAYCHANNEL"
+	HistoryTypeProviderPaychannel = "PROVIDER_PAYCHANNEL"
+	HistoryTypeBalance            = "BALANCE"
+
+	HistoryActivityUpdateStatus     = "UPDATE_STATUS"
+	HistoryActivityUpdateFeeLimit   = "UPDATE_FEE_LIMIT"
+	HistoryActivityReplaceRouting   = "REPLACE_ROUTING"
+	HistoryActivityRotateKey        = "ROTATE_KEY"
+	HistoryActivityTopUp            = "TOP_UP"
+	HistoryActivityHold             = "HOLD"
+	HistoryActivitySettlement       = "SETTLEMENT"
+	HistoryActivityTransfer         = "TRANSFER"
+	HistoryActivityPayoutSettlement = "PAYOUT_SETTLEMENT"
+	HistoryActivityReverse          = "REVERSE"
+)
+
+const (
+	HistoryTypeBalanceRequest = "BALANCE_REQUEST"
+
+	HistoryActivityRequestApproval = "REQUEST_APPROVAL"
+	HistoryActivityApprove         = "APPROVE"
+	HistoryActivityReject          = "REJECT"
+	HistoryActivityUpdateThreshold = "UPDATE_THRESHOLD"
+)
+
+const (
+	HistoryTypeDisbursementRequest = "DISBURSEMENT_REQUEST"
+
+	HistoryActivityDisburse            = "DISBURSE"
+	HistoryActivityUpdateApprovalRules = "UPDATE_APPROVAL_RULES"
+)
+
+const (
+	HistoryTypeDisbursementBatch = "DISBURSEMENT_BATCH"
+
+	HistoryActivityUpload   = "UPLOAD"
+	HistoryActivityConfirm  = "CONFIRM"
+	HistoryActivityComplete = "COMPLETE"
+	HistoryActivityFail     = "FAIL"
+)
+
+// AuditMaxBody is the largest request body kept on a request row, bigger bodies are only described
+const AuditMaxBody = 64 << 10
+
+// AuditRedacted replaces the values of sensitive fields such as pins, passwords and secrets
+const AuditRedacted = "[REDACTED]"
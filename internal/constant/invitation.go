@@ -0,0 +1,13 @@
+package constant
+
+const (
+	InvitationStatusPending  = "PENDING"
+	InvitationStatusAccepted = "ACCEPTED"
+	InvitationStatusRevoked  = "REVOKED"
+)
+
+const (
+	InvitationTTL = 3 * OneDay
+	// InvitationPath is the dashboard page that reads the token from the emailed link
+	InvitationPath = "/accept-invite"
+)
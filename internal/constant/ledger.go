@@ -0,0 +1,24 @@
+package constant
+
+const (
+	LedgerOwnerMerchant = "MERCHANT"
+	LedgerOwnerPlatform = "PLATFORM"
+	LedgerOwnerProvider = "PROVIDER"
+	LedgerPlatformId    = "HYPAY"
+)
+
+const (
+	LedgerAccountSettled        = "SETTLED"
+	LedgerAccountNotSettled     = "NOT_SETTLED"
+	LedgerAccountHold           = "HOLD"
+	LedgerAccountPendingOut     = "PENDING_OUT"
+	LedgerAccountFeeRevenue     = "FEE_REVENUE"
+	LedgerAccountProviderFloat  = "PROVIDER_FLOAT"
+	LedgerAccountClearing       = "EXTERNAL_CLEARING"
+	LedgerAccountOpeningBalance = "OPENING_BALANCE"
+)
+
+const (
+	LedgerDirectionDebit  = "DEBIT"
+	LedgerDirectionCredit = "CREDIT"
+)
@@ -0,0 +1,21 @@
+package constant
+
+import "time"
+
+// a merchant has one ACTIVE key, at most one NEXT key waiting for its activation and the RETIRING keys it
+// replaced, every one of them still signs callbacks
+const (
+	MerchantKeyNext     = "NEXT"
+	MerchantKeyActive   = "ACTIVE"
+	MerchantKeyRetiring = "RETIRING"
+	MerchantKeyRetired  = "RETIRED"
+)
+
+const (
+	// MerchantKeyOverlap is how long a replaced key keeps signing callbacks when the rotation doesn't say
+	MerchantKeyOverlap    = OneDay
+	MerchantKeyMaxOverlap = 7 * OneDay
+	// MerchantKeyMaxSchedule is how far ahead a rotation can be scheduled
+	MerchantKeyMaxSchedule   = ThirtyDays
+	MerchantKeyRotationCheck = time.Minute
+)
@@ -20,6 +20,16 @@ const (
 	JackApiKeyCred           = "API_KEY"
 )
 
+const (
+	// CallbackSecretCred is the shared secret providers sign their callbacks with
+	CallbackSecretCred = "CALLBACK_SECRET"
+	// CallbackIpAllowlistCred is an optional comma separated list of ip or cidr allowed to send callbacks
+	CallbackIpAllowlistCred = "CALLBACK_IP_ALLOWLIST"
+
+	JackCallbackSignatureHeader = "X-Callback-Signature"
+	JackCallbackTokenHeader     = "X-Callback-Token"
+)
+
 const (
 	JackStatusOk             = 200
 	JackStatusInvalid        = 422
@@ -42,3 +52,14 @@ const (
 	SourceCallback = "CALLBACK"
 	SourceQuery    = "QUERY"
 )
+
+// provider confirmation result of a callback that arrived after the transaction left PROCESSING
+const ConfirmationResultDuplicateIgnored = "DUPLICATE_IGNORED"
+
+const (
+	ProviderCallbackReceived = "RECEIVED"
+	ProviderCallbackApplied  = "APPLIED"
+	ProviderCallbackIgnored  = "IGNORED"
+	ProviderCallbackRejected = "REJECTED"
+	ProviderCallbackFailed   = "FAILED"
+)
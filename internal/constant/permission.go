@@ -0,0 +1,44 @@
+package constant
+
+// permission names checked by the route middleware, they are stored in permissions.permission_name
+// and granted to roles through role_permissions
+const (
+	PermissionAuditView = "audit.view"
+
+	PermissionBalanceTopUp            = "balance.topup"
+	PermissionBalanceHold             = "balance.hold"
+	PermissionBalanceSettlement       = "balance.settlement"
+	PermissionBalanceTransfer         = "balance.transfer"
+	PermissionBalancePayoutSettlement = "balance.payout_settlement"
+	PermissionBalanceReverse          = "balance.reverse"
+	// PermissionBalanceApprove reviews the balance operations requested by other users
+	PermissionBalanceApprove         = "balance.approve"
+	PermissionBalanceThresholdManage = "balance.threshold.manage"
+
+	PermissionTransactionUpdateStatus = "transaction.update_status"
+	// PermissionTransactionOverrideStatus reopens a final transaction status, see the transaction state machine
+	PermissionTransactionOverrideStatus = "transaction.override_status"
+
+	PermissionCallbackSend       = "callback.send"
+	PermissionDisbursementCreate = "disbursement.create"
+	PermissionExportCreate       = "export.create"
+	// PermissionDisbursementApprove reviews the disbursements requested by other users of the same merchant
+	PermissionDisbursementApprove        = "disbursement.approve"
+	PermissionDisbursementApprovalManage = "disbursement.approval.manage"
+
+	PermissionMerchantCreate           = "merchant.create"
+	PermissionMerchantUpdateStatus     = "merchant.update_status"
+	PermissionMerchantPaychannelManage = "merchant.paychannel.manage"
+	PermissionMerchantSecretView       = "merchant.secret.view"
+	PermissionMerchantSecretRotate     = "merchant.secret.rotate"
+	PermissionMerchantUserInvite       = "merchant.user.invite"
+	PermissionMerchantUserManage       = "merchant.user.manage"
+
+	PermissionProviderPaychannelManage = "provider.paychannel.manage"
+
+	PermissionReconciliationRun     = "reconciliation.run"
+	PermissionReconciliationResolve = "reconciliation.resolve"
+
+	PermissionRoleManage = "role.manage"
+	PermissionUserUnlock = "user.unlock"
+)
@@ -0,0 +1,21 @@
+package constant
+
+const (
+	ReconciliationStatusRunning   = "RUNNING"
+	ReconciliationStatusCompleted = "COMPLETED"
+	ReconciliationStatusFailed    = "FAILED"
+)
+
+const (
+	DiscrepancyStatusOpen     = "OPEN"
+	DiscrepancyStatusResolved = "RESOLVED"
+)
+
+const (
+	// ReconciliationBucketCapitalFlow compares balance_capital_flow with credit minus debit flows
+	ReconciliationBucketCapitalFlow = "BALANCE_CAPITAL_FLOW"
+	// ReconciliationBucketSum compares balance_capital_flow with the sum of every bucket
+	ReconciliationBucketSum = "BUCKET_SUM"
+)
+
+const ReconciliationInterval = OneDay
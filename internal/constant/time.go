@@ -16,9 +16,14 @@ const (
 	EightMinutes    = 8 * time.Minute
 	TenMinutes      = 10 * time.Minute
 	ThirteenMinutes = 13 * time.Minute
+	FifteenMinutes  = 15 * time.Minute
 	FinalIncrement  = 5 * time.Minute
 
 	MaxRetrySyncStatus = 5
+
+	// dashboards renew the short lived access token with the refresh token issued at login
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 7 * 24 * time.Hour
 )
 
 var DelayBasedOnCounter = map[int]time.Duration{
@@ -0,0 +1,15 @@
+package constant
+
+const (
+	// LoginChallengeVerify asks an enrolled user for a totp or recovery code
+	LoginChallengeVerify = "VERIFY"
+	// LoginChallengeSetup lets a user whose role requires two factor enroll before the first token is issued
+	LoginChallengeSetup = "SETUP"
+)
+
+const (
+	TwoFactorIssuer            = "Hypay"
+	LoginChallengeTTL          = FiveMinutes
+	LoginChallengeMaxAttempts  = 5
+	TwoFactorRecoveryCodeCount = 10
+)
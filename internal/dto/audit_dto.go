@@ -0,0 +1,48 @@
+package dto
+
+// AuditActor is who made a change and through which request, controllers fill it from the echo context
+type AuditActor struct {
+	Username  string
+	IpAddress string
+	RequestId string
+}
+
+// AuditEntry is one row of the audit trail, Before and After are marshaled to json and the fields that differ
+// between them are kept as the changes of the row
+type AuditEntry struct {
+	HistoryType  string
+	Activity     string
+	TargetId     string
+	Before       interface{}
+	After        interface{}
+	ResponseCode int
+	Actor        AuditActor
+}
+
+type QueryParamsAuditLog struct {
+	Username    string `json:"username"`
+	HistoryType string `json:"historyType"`
+	Activity    string `json:"activity"`
+	TargetId    string `json:"targetId"`
+	RequestId   string `json:"requestId"`
+	IpAddress   string `json:"ipAddress"`
+	MinDate     string `json:"minDate"`
+	MaxDate     string `json:"maxDate"`
+	Search      string `json:"search"`
+	Page        string `json:"page"`
+	PageSize    string `json:"pageSize"`
+}
+
+type CreateAuditLogPayload struct {
+	HistoryType string
+	Activity    string
+	TargetId    string
+	// PayloadBefore, PayloadAfter and Changes are json documents, nil is stored as NULL
+	PayloadBefore []byte
+	PayloadAfter  []byte
+	Changes       []byte
+	Username      string
+	IpAddress     string
+	RequestId     string
+	ResponseCode  int
+}
@@ -0,0 +1,41 @@
+package dto
+
+import "github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+
+type CreateBalanceOperationRequestPayload struct {
+	OperationType    string
+	MerchantId       string
+	TargetMerchantId string
+	PaymentId        string
+	Amount           money.Money
+	Notes            string
+	Payload          []byte
+	RequestedBy      string
+}
+
+type ReviewBalanceOperationReq struct {
+	BalanceOperationRequestId int    `json:"balanceOperationRequestId"`
+	Notes                     string `json:"notes"`
+	Pin                       string `json:"pin"`
+	Approve                   bool   `json:"-"`
+	Username                  string
+	Actor                     AuditActor `json:"-"`
+}
+
+type QueryParamsBalanceOperationRequest struct {
+	Status        string `json:"status"`
+	OperationType string `json:"operationType"`
+	MerchantId    string `json:"merchantId"`
+	RequestedBy   string `json:"requestedBy"`
+	MinDate       string `json:"minDate"`
+	MaxDate       string `json:"maxDate"`
+	Page          string `json:"page"`
+	PageSize      string `json:"pageSize"`
+}
+
+type UpdateBalanceApprovalThresholdReq struct {
+	OperationType string      `json:"operationType"`
+	MinAmount     money.Money `json:"minAmount"`
+	Username      string
+	Actor         AuditActor `json:"-"`
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+)
+
+type CreateDisbursementRequestPayload struct {
+	MerchantId        string      `json:"merchantId"`
+	Amount            money.Money `json:"amount"`
+	BankName          string      `json:"bankName"`
+	BankAccountName   string      `json:"bankAccountName"`
+	BankAccountNumber string      `json:"bankAccountNumber"`
+	Note              string      `json:"note"`
+	RequiredApprovals int         `json:"requiredApprovals"`
+	RequestedBy       string      `json:"requestedBy"`
+}
+
+type ReviewDisbursementRequestReq struct {
+	DisbursementRequestId int    `json:"disbursementRequestId"`
+	Notes                 string `json:"notes"`
+	Pin                   string `json:"pin"`
+	Approve               bool   `json:"-"`
+	Username              string
+	Actor                 AuditActor `json:"-"`
+}
+
+type QueryParamsDisbursementRequest struct {
+	MerchantId  string `json:"merchantId"`
+	Status      string `json:"status"`
+	RequestedBy string `json:"requestedBy"`
+	MinDate     string `json:"minDate"`
+	MaxDate     string `json:"maxDate"`
+	Page        string `json:"page"`
+	PageSize    string `json:"pageSize"`
+	Username    string
+}
+
+type DisbursementRequestDetailDto struct {
+	entity.DisbursementRequest
+	Reviews []entity.DisbursementRequestReview `json:"reviews"`
+}
+
+type DisbursementApprovalRuleDto struct {
+	MinAmount         money.Money `json:"minAmount"`
+	RequiredApprovals int         `json:"requiredApprovals"`
+}
+
+type UpdateDisbursementApprovalRulesReq struct {
+	Rules    []DisbursementApprovalRuleDto `json:"rules"`
+	Username string
+	Actor    AuditActor `json:"-"`
+}
@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+)
+
+type UploadDisbursementBatchReq struct {
+	FileName string
+	Content  []byte
+	Username string
+	Actor    AuditActor `json:"-"`
+}
+
+type CreateDisbursementBatchPayload struct {
+	MerchantId  string      `json:"merchantId"`
+	FileName    string      `json:"fileName"`
+	TotalRows   int         `json:"totalRows"`
+	ValidRows   int         `json:"validRows"`
+	TotalAmount money.Money `json:"totalAmount"`
+	TotalFee    money.Money `json:"totalFee"`
+	UploadedBy  string      `json:"uploadedBy"`
+}
+
+// DisbursementBatchRowDto is a row of a batch file once validated, amount is nil when it couldn't be read
+type DisbursementBatchRowDto struct {
+	RowNumber         int          `json:"rowNumber"`
+	BankCode          string       `json:"bankCode"`
+	BankName          string       `json:"bankName"`
+	BankAccountName   string       `json:"bankAccountName"`
+	BankAccountNumber string       `json:"bankAccountNumber"`
+	Amount            *money.Money `json:"amount"`
+	Fee               money.Money  `json:"fee"`
+	Note              string       `json:"note"`
+	Status            string       `json:"status"`
+	ErrorMessage      string       `json:"errorMessage"`
+}
+
+type DisbursementBatchUploadRespDto struct {
+	DisbursementBatchId int                       `json:"disbursementBatchId"`
+	TotalRows           int                       `json:"totalRows"`
+	ValidRows           int                       `json:"validRows"`
+	InvalidRows         int                       `json:"invalidRows"`
+	TotalAmount         money.Money               `json:"totalAmount"`
+	TotalFee            money.Money               `json:"totalFee"`
+	GrandTotal          money.Money               `json:"grandTotal"`
+	Rows                []DisbursementBatchRowDto `json:"rows"`
+}
+
+type ConfirmDisbursementBatchReq struct {
+	DisbursementBatchId int    `json:"disbursementBatchId"`
+	Pin                 string `json:"pin"`
+	Username            string
+	Actor               AuditActor `json:"-"`
+}
+
+type QueryParamsDisbursementBatch struct {
+	MerchantId string `json:"merchantId"`
+	Status     string `json:"status"`
+	UploadedBy string `json:"uploadedBy"`
+	MinDate    string `json:"minDate"`
+	MaxDate    string `json:"maxDate"`
+	Page       string `json:"page"`
+	PageSize   string `json:"pageSize"`
+	Username   string
+}
+
+// DisbursementBatchDetailDto sums the batch up for the merchant, grand total is the amount and the fee of the
+// valid rows together
+type DisbursementBatchDetailDto struct {
+	entity.DisbursementBatch
+	InvalidRows   int                           `json:"invalidRows"`
+	GrandTotal    money.Money                   `json:"grandTotal"`
+	StatusSummary map[string]int                `json:"statusSummary"`
+	Rows          []entity.DisbursementBatchRow `json:"rows"`
+}
+
+type DisbursementBatchResultFileDto struct {
+	FileName string
+	Content  []byte
+}
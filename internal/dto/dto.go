@@ -4,17 +4,34 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
 )
 
 type LoginPayload struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	Device    string `json:"-"`
+	IpAddress string `json:"-"`
+}
+
+type RefreshTokenPayload struct {
+	RefreshToken string `json:"refreshToken"`
 }
 
 type Claims struct {
-	Username string `json:"username"`
-	UserType string `json:"userType"`
-	RoleName string `json:"roleName"`
+	Username    string   `json:"username"`
+	UserType    string   `json:"userType"`
+	RoleName    string   `json:"roleName"`
+	Permissions []string `json:"permissions"`
+	SessionId   string   `json:"sessionId"`
+	jwt.StandardClaims
+}
+
+// CredentialResetClaims are signed into the emailed reset token, the id is looked up to use it only once
+type CredentialResetClaims struct {
+	Username   string `json:"username"`
+	Credential string `json:"credential"`
 	jwt.StandardClaims
 }
 
@@ -48,6 +65,7 @@ type QueryParams struct {
 type QueryParamsMerchantCallback struct {
 	PayType        string `json:"payType"`
 	CallbackStatus string `json:"callbackStatus"`
+	DeliveryStatus string `json:"deliveryStatus"`
 	MerchantName   string `json:"merchantName"`
 	Search         string `json:"search"`
 	Page           string `json:"page"`
@@ -81,16 +99,16 @@ type PaginatedResponse struct {
 }
 
 type FeeResponse struct {
-	MerchantFee      FeeData `json:"merchantFee"`
-	ProviderFee      FeeData `json:"providerFee"`
-	CalculatedProfit float64 `json:"calculatedProfit"`
-	FeeType          string  `json:"feeType"`
+	MerchantFee      FeeData     `json:"merchantFee"`
+	ProviderFee      FeeData     `json:"providerFee"`
+	CalculatedProfit money.Money `json:"calculatedProfit"`
+	FeeType          string      `json:"feeType"`
 }
 
 type FeeData struct {
-	ConfiguredFee float64 `json:"configuredFee"`
-	CalculatedFee float64 `json:"calculatedFee"`
-	ChargedFee    float64 `json:"chargedFee"`
+	ConfiguredFee money.Money `json:"configuredFee"`
+	CalculatedFee money.Money `json:"calculatedFee"`
+	ChargedFee    money.Money `json:"chargedFee"`
 }
 
 type FilterResponseDto struct {
@@ -0,0 +1,43 @@
+package dto
+
+import "github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+
+type VerifyHashChainReq struct {
+	Chain    string `json:"chain"`
+	ChainKey string `json:"chainKey"`
+}
+
+// ChainBreakDto is the first broken link found in a chain
+type ChainBreakDto struct {
+	Chain    string `json:"chain"`
+	ChainKey string `json:"chainKey"`
+	ChainSeq int64  `json:"chainSeq"`
+	RowId    int    `json:"rowId"`
+	Reason   string `json:"reason"`
+	Detail   string `json:"detail"`
+}
+
+type HashChainVerificationRespDto struct {
+	TotalChains  int             `json:"totalChains"`
+	TotalLinks   int             `json:"totalLinks"`
+	CheckpointId *int            `json:"checkpointId"`
+	Breaks       []ChainBreakDto `json:"breaks"`
+}
+
+type CreateChainCheckpointPayload struct {
+	PrevDigest string
+	Digest     string
+	CreatedBy  string
+	Tails      []entity.ChainTail
+}
+
+type QueryParamsChainCheckpoint struct {
+	MinDate string `json:"minDate"`
+	MaxDate string `json:"maxDate"`
+}
+
+// ChainCheckpointExportDto is a checkpoint with the tails its digest covers, enough to recompute the digest
+type ChainCheckpointExportDto struct {
+	entity.ChainCheckpoint
+	Tails []entity.ChainTail `json:"tails"`
+}
@@ -0,0 +1,23 @@
+package dto
+
+import "github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+
+type LedgerAccountRef struct {
+	OwnerType   string
+	OwnerId     string
+	AccountType string
+}
+
+type LedgerPostingPayload struct {
+	Account   LedgerAccountRef
+	Direction string
+	Amount    money.Money
+}
+
+type CreateLedgerJournalPayload struct {
+	PaymentId string
+	ReasonId  int
+	Notes     string
+	CreatedBy string
+	Postings  []LedgerPostingPayload
+}
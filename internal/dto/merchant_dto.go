@@ -4,37 +4,51 @@ import (
 	"time"
 
 	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
 )
 
 type CreateMerchantCapitalFlowPayload struct {
 	PaymentId         string
 	MerchantAccountId int
-	TempBalance       float64
+	TempBalance       money.Money
 	ReasonId          int
 	Status            string
 	CreateBy          string
-	Amount            float64
+	Amount            money.Money
 	Notes             string
 	CapitalType       string
 	ReverseFrom       string
 }
 
+// CreateMerchantCallbackAttemptPayload is one automatic delivery attempt, zero NextRetryDelay means no retry
+type CreateMerchantCallbackAttemptPayload struct {
+	DeliveryId              int
+	AttemptNumber           int
+	PaymentId               string
+	CallbackStatus          string
+	PaymentStatusInCallback string
+	CallbackResult          string
+	NextRetryDelay          time.Duration
+}
+
 type AdjustBalanceReqPayload struct {
-	Amount     int    `json:"amount"`
-	Notes      string `json:"notes"`
-	MerchantId string `json:"merchantId"`
-	Pin        string `json:"pin"`
+	Amount     money.Money `json:"amount"`
+	Notes      string      `json:"notes"`
+	MerchantId string      `json:"merchantId"`
+	Pin        string      `json:"pin"`
 	Username   string
+	Actor      AuditActor `json:"-"`
 }
 
 type AdjustLimitOrFeePayload struct {
-	MerchantPaychannelId int      `json:"merchantPaychannelId"`
-	Username             string   `json:"username"`
-	MinAmount            *float64 `json:"minAmount,omitempty"`
-	MaxAmount            *float64 `json:"maxAmount,omitempty"`
-	MaxDailyLimit        *float64 `json:"maxDailyAmount,omitempty"`
-	Fee                  *float64 `json:"fee,omitempty"`
-	FeeType              *string  `json:"feeType,omitempty"`
+	MerchantPaychannelId int          `json:"merchantPaychannelId"`
+	Username             string       `json:"username"`
+	MinAmount            *money.Money `json:"minAmount,omitempty"`
+	MaxAmount            *money.Money `json:"maxAmount,omitempty"`
+	MaxDailyLimit        *money.Money `json:"maxDailyAmount,omitempty"`
+	Fee                  *money.Money `json:"fee,omitempty"`
+	FeeType              *string      `json:"feeType,omitempty"`
+	Actor                AuditActor   `json:"-"`
 }
 
 type SendCallbackReqPayload struct {
@@ -45,25 +59,50 @@ type BalanceTrfReqPayload struct {
 	AccountFrom AccountData `json:"accountFrom"`
 	AccountTo   AccountData `json:"accountTo"`
 	Pin         string      `json:"pin"`
-	Amount      int         `json:"amount"`
+	Amount      money.Money `json:"amount"`
 	Notes       string      `json:"notes"`
 	Username    string
+	Actor       AuditActor `json:"-"`
 }
 
 type AccountData struct {
-	MerchantId           string `json:"merchantId"`
-	MerchantPaychannelId int    `json:"merchantPaychannelId"`
+	MerchantId           string     `json:"merchantId"`
+	MerchantPaychannelId int        `json:"merchantPaychannelId"`
+	Actor                AuditActor `json:"-"`
 }
 
 type MerchantCallbackDto struct {
-	TransactionId         string    `json:"transactionId"`
-	MerchantTransactionId string    `json:"merchantTransactionId"`
-	Status                string    `json:"status"`
-	FailedReason          string    `json:"failedReason,omitempty"`
-	Amount                float64   `json:"amount"`
-	TransactionType       string    `json:"transactionType"`
-	TransactionCreatedAt  time.Time `json:"transactionCreatedAt"`
-	TransactionUpdatedAt  time.Time `json:"transactionUpdatedAt"`
+	TransactionId         string      `json:"transactionId"`
+	MerchantTransactionId string      `json:"merchantTransactionId"`
+	Status                string      `json:"status"`
+	FailedReason          string      `json:"failedReason,omitempty"`
+	Amount                money.Money `json:"amount"`
+	TransactionType       string      `json:"transactionType"`
+	TransactionCreatedAt  time.Time   `json:"transactionCreatedAt"`
+	TransactionUpdatedAt  time.Time   `json:"transactionUpdatedAt"`
+}
+
+// MerchantSigningKey is an opened merchant key a callback is signed with
+type MerchantSigningKey struct {
+	Version int
+	Secret  string
+}
+
+type RotateMerchantKeyPayload struct {
+	Pin string `json:"pin"`
+	// ActivateAt schedules the new key, it is activated right away when empty
+	ActivateAt *time.Time `json:"activateAt"`
+	// OverlapHours is how long the replaced key keeps signing callbacks after the activation
+	OverlapHours int        `json:"overlapHours"`
+	MerchantId   string     `json:"-"`
+	Username     string     `json:"-"`
+	Actor        AuditActor `json:"-"`
+}
+
+type MerchantKeysDto struct {
+	SecretKey string                     `json:"secretKey"`
+	Version   int                        `json:"version"`
+	Keys      []entity.MerchantKeyEntity `json:"keys"`
 }
 
 type ManualPaymentDetailDto struct {
@@ -86,13 +125,13 @@ type GetMerchantAnalyticsDtoReq struct {
 }
 
 type PaymentMethodData struct {
-	PaymentMethodId         int     `json:"paymentMethodId"`
-	PaymentMethodName       string  `json:"paymentMethodName"`
-	MinAmountPerTransaction float64 `json:"minAmount"`
-	MaxAmountPerTransaction float64 `json:"maxAmount"`
-	DailyLimit              float64 `json:"dailyLimit"`
-	Fee                     float64 `json:"fee"`
-	FeeType                 string  `json:"feeType"`
+	PaymentMethodId         int         `json:"paymentMethodId"`
+	PaymentMethodName       string      `json:"paymentMethodName"`
+	MinAmountPerTransaction money.Money `json:"minAmount"`
+	MaxAmountPerTransaction money.Money `json:"maxAmount"`
+	DailyLimit              money.Money `json:"dailyLimit"`
+	Fee                     money.Money `json:"fee"`
+	FeeType                 string      `json:"feeType"`
 }
 
 type AnalyticsMerchantRespDto struct {
@@ -114,17 +153,17 @@ type SuccessPayment struct {
 }
 
 type HomeAnalyticsDataRespDto struct {
-	TotalNumber int     `json:"totalNumber"`
-	TotalAmount float64 `json:"totalAmount"`
+	TotalNumber int         `json:"totalNumber"`
+	TotalAmount money.Money `json:"totalAmount"`
 }
 
 type AnalyticsDataRespDto struct {
-	TotalVolume        float64 `json:"totalVolume"`
-	SuccessRate        string  `json:"successRate"`
-	CompletionRate     string  `json:"completionRate"`
-	TransactionTotal   int     `json:"transactionTotal"`
-	SuccessTransaction int     `json:"successTransaction"`
-	FailedTransaction  int     `json:"failedTransaction"`
+	TotalVolume        money.Money `json:"totalVolume"`
+	SuccessRate        string      `json:"successRate"`
+	CompletionRate     string      `json:"completionRate"`
+	TransactionTotal   int         `json:"transactionTotal"`
+	SuccessTransaction int         `json:"successTransaction"`
+	FailedTransaction  int         `json:"failedTransaction"`
 }
 
 type MerchantDataDtoRes struct {
@@ -144,7 +183,8 @@ type AddSegmentDtoReq struct {
 }
 
 type AddPaychannelRouting struct {
-	ProviderPaychannelId []int `json:"providerPaychannelRouting"`
+	ProviderPaychannelId []int      `json:"providerPaychannelRouting"`
+	Actor                AuditActor `json:"-"`
 }
 
 type ActiveAvailableChannelRespDto struct {
@@ -167,11 +207,16 @@ type CallbackMerchantResp struct {
 }
 
 type MerchantDisbursement struct {
-	Amount            int    `json:"amount"`
-	BankName          string `json:"bankName"`
-	BankAccountName   string `json:"bankAccountName"`
-	BankAccountNumber string `json:"bankAccountNumber"`
-	Note              string `json:"note"`
-	Pin               string `json:"pin"`
+	Amount            money.Money `json:"amount"`
+	BankName          string      `json:"bankName"`
+	BankAccountName   string      `json:"bankAccountName"`
+	BankAccountNumber string      `json:"bankAccountNumber"`
+	Note              string      `json:"note"`
+	Pin               string      `json:"pin"`
 	Username          string
+	Actor             AuditActor `json:"-"`
+}
+
+type MerchantDisbursementRespDto struct {
+	PaymentId string `json:"paymentId"`
 }
@@ -1,6 +1,11 @@
 package dto
 
-import "github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+import (
+	"net/http"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+)
 
 type GetProviderAnalyticsDtoReq struct {
 	MinDate           string `json:"minDate"`
@@ -29,11 +34,6 @@ type InquiryData struct {
 	Errors       string      `json:"errors"`
 }
 
-type ConfirmTransactionPayload struct {
-	Username   string
-	ProviderID string
-}
-
 type CreateDisbursementRequest struct {
 	ReferenceID string          `json:"reference_id"`
 	CallbackURL string          `json:"callback_url"`
@@ -102,9 +102,29 @@ type BeneficiaryData struct {
 	Bank           string `json:"bank"`
 }
 
-type JackBankCode struct {
-	Id       string
-	BankName string
+// PayoutRequest is the provider neutral disbursement request passed to payout provider adapters
+type PayoutRequest struct {
+	Username          string
+	PaymentId         string
+	Amount            money.Money
+	BankAccountNumber string
+	BankAccountName   string
+	Bank              entity.ProviderBankCodeEntity
+}
+
+type PayoutInquiryResult struct {
+	AccountNumber string
+	AccountName   string
+}
+
+// PayoutResult is the provider neutral state of a disbursement, status is one of
+// constant.StatusProcessing, constant.StatusSuccess or constant.StatusFailed
+type PayoutResult struct {
+	PaymentId           string
+	ProviderReferenceId string
+	Status              string
+	Amount              money.Money
+	ErrorMessage        string
 }
 
 type JackCredentialsDto struct {
@@ -142,13 +162,14 @@ type ProviderChannelAnalyticsResDto struct {
 }
 
 type AdjustLimitOrFeeProviderPayload struct {
-	ProviderChannelId int      `json:"providerChannelId"`
-	MinAmount         *float64 `json:"minAmount,omitempty"`
-	MaxAmount         *float64 `json:"maxAmount,omitempty"`
-	MaxDailyLimit     *float64 `json:"maxDailyAmount,omitempty"`
-	Fee               *float64 `json:"fee,omitempty"`
-	FeeType           *string  `json:"feeType,omitempty"`
-	InterfaceSetting  *string  `json:"interfaceSetting,omitempty"`
+	ProviderChannelId int          `json:"providerChannelId"`
+	MinAmount         *money.Money `json:"minAmount,omitempty"`
+	MaxAmount         *money.Money `json:"maxAmount,omitempty"`
+	MaxDailyLimit     *money.Money `json:"maxDailyAmount,omitempty"`
+	Fee               *money.Money `json:"fee,omitempty"`
+	FeeType           *string      `json:"feeType,omitempty"`
+	InterfaceSetting  *string      `json:"interfaceSetting,omitempty"`
+	Actor             AuditActor   `json:"-"`
 }
 
 type AddOperatorProviderChannelPayload struct {
@@ -158,17 +179,35 @@ type AddOperatorProviderChannelPayload struct {
 }
 
 type UpdateStatusProviderPaychannelDto struct {
-	ProviderChannelId int `json:"providerChannelId"`
+	ProviderChannelId int        `json:"providerChannelId"`
+	Actor             AuditActor `json:"-"`
 }
 
 type CreateProviderChannelDto struct {
 	ProviderInterfaceId int                                 `json:"providerInterfaceId"`
 	PaychannelName      string                              `json:"paychannelName"`
 	InterfaceSetting    *string                             `json:"interfaceSetting"`
-	MinAmount           *float64                            `json:"minAmount"`
-	MaxAmount           *float64                            `json:"maxAmount"`
-	DailyLimit          *float64                            `json:"dailyLimit"`
-	Fee                 *float64                            `json:"fee"`
+	MinAmount           *money.Money                        `json:"minAmount"`
+	MaxAmount           *money.Money                        `json:"maxAmount"`
+	DailyLimit          *money.Money                        `json:"dailyLimit"`
+	Fee                 *money.Money                        `json:"fee"`
 	FeeType             *string                             `json:"feeType"`
 	BankOperator        []AddOperatorProviderChannelPayload `json:"paymentOperator"`
 }
+
+// ProviderCallbackReq is a raw provider callback as received by the http server
+type ProviderCallbackReq struct {
+	ProviderId string
+	SourceIp   string
+	Headers    http.Header
+	Body       []byte
+}
+
+type CreateProviderCallbackPayload struct {
+	ProviderId     string
+	DedupKey       string
+	PaymentId      string
+	CallbackStatus string
+	SourceIp       string
+	RawBody        string
+}
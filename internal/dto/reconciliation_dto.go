@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+)
+
+type CreateReconciliationDiscrepancyPayload struct {
+	ReconciliationRunId int
+	MerchantId          string
+	MerchantAccountId   int
+	Bucket              string
+	ExpectedAmount      money.Money
+	ActualAmount        money.Money
+	PaymentIds          []string
+}
+
+type ResolveReconciliationDiscrepancyReq struct {
+	DiscrepancyId int    `json:"discrepancyId"`
+	Notes         string `json:"notes"`
+	Username      string
+}
+
+type ReconciliationRunDetailRespDto struct {
+	ReconciliationRun entity.ReconciliationRun           `json:"reconciliationRun"`
+	Discrepancies     []entity.ReconciliationDiscrepancy `json:"discrepancies"`
+}
@@ -2,6 +2,8 @@ package dto
 
 import (
 	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
 )
 
 type GetPaymentDetailsRequest struct {
@@ -15,47 +17,48 @@ type PaymentDetailProviderMerchantResponse struct {
 }
 
 type DetailData struct {
-	Name             string  `json:"name"`
-	PaymentId        string  `json:"orderId"`
-	Channel          string  `json:"channel"`
-	Segment          string  `json:"segment"`
-	Routing          string  `json:"routing"`
-	Fee              float64 `json:"fee"`
-	FeeType          string  `json:"feeType"`
-	ClientIpAddress  string  `json:"clientIpAddress"`
-	InterfaceSetting string  `json:"interfaceSetting"`
-	RequestMethod    string  `json:"requestMethod"`
-	RequestedAmount  float64 `json:"requestedAmount"`
+	Name             string      `json:"name"`
+	PaymentId        string      `json:"orderId"`
+	Channel          string      `json:"channel"`
+	Segment          string      `json:"segment"`
+	Routing          string      `json:"routing"`
+	Fee              money.Money `json:"fee"`
+	FeeType          string      `json:"feeType"`
+	ClientIpAddress  string      `json:"clientIpAddress"`
+	InterfaceSetting string      `json:"interfaceSetting"`
+	RequestMethod    string      `json:"requestMethod"`
+	RequestedAmount  money.Money `json:"requestedAmount"`
 }
 
 type TransactionMerchantData struct {
-	Name                  string  `json:"name"`
-	MerchantTransactionId string  `json:"merchantTransactionId"`
-	TransactionFee        float64 `json:"transactionFee"`
-	TransactionFeeType    string  `json:"transactionFeeType"`
-	TransactionAmount     float64 `json:"transactionAmount"`
-	TransactionNetAmount  float64 `json:"transactionNetAmount"`
-	TransactionMethod     string  `json:"transactionMethod"`
-	AccountName           *string `json:"accountName"`
-	AccountNumber         *string `json:"accountNumber"`
-	BankName              *string `json:"bankName"`
-	IpAddress             string  `json:"ipAddress"`
+	Name                  string      `json:"name"`
+	MerchantTransactionId string      `json:"merchantTransactionId"`
+	TransactionFee        money.Money `json:"transactionFee"`
+	TransactionFeeType    string      `json:"transactionFeeType"`
+	TransactionAmount     money.Money `json:"transactionAmount"`
+	TransactionNetAmount  money.Money `json:"transactionNetAmount"`
+	TransactionMethod     string      `json:"transactionMethod"`
+	AccountName           *string     `json:"accountName"`
+	AccountNumber         *string     `json:"accountNumber"`
+	BankName              *string     `json:"bankName"`
+	IpAddress             string      `json:"ipAddress"`
 }
 
 type TransactionsCapitalFlow struct {
-	TransactionType string    `json:"transactionType"`
-	MerchantAccount string    `json:"merchantAccount"`
-	Amount          float64   `json:"amount"`
-	Status          string    `json:"status"`
-	CapitalType     string    `json:"capitalType"`
-	CreatedAt       time.Time `json:"createdAt"`
+	TransactionType string      `json:"transactionType"`
+	MerchantAccount string      `json:"merchantAccount"`
+	Amount          money.Money `json:"amount"`
+	Status          string      `json:"status"`
+	CapitalType     string      `json:"capitalType"`
+	CreatedAt       time.Time   `json:"createdAt"`
 }
 
 type UpdateStatusTransaction struct {
-	PaymentId string `json:"paymentId"`
-	Status    string `json:"status"`
-	Notes     string `json:"notes"`
-	Pin       string `json:"pin"`
+	PaymentId string     `json:"paymentId"`
+	Status    string     `json:"status"`
+	Notes     string     `json:"notes"`
+	Pin       string     `json:"pin"`
+	Actor     AuditActor `json:"-"`
 }
 
 type CreateMerchantExportReqDto struct {
@@ -131,13 +134,13 @@ type TransactionMerchantDetailDto struct {
 }
 
 type CountDisbursementTotalAmountDto struct {
-	Amount   int `json:"amount"`
+	Amount   money.Money `json:"amount"`
 	Username string
 }
 
 type CountDisbursementRespDto struct {
-	FeeAmount   float64 `json:"feeAmount"`
-	TotalAmount float64 `json:"totalAmount"`
+	FeeAmount   money.Money `json:"feeAmount"`
+	TotalAmount money.Money `json:"totalAmount"`
 }
 
 type CreateTransactionsDto struct {
@@ -146,7 +149,7 @@ type CreateTransactionsDto struct {
 	ProviderReferenceNumber string
 	MerchantPaychanneId     int
 	ProviderPaychannelId    int
-	TransactionAmount       float64
+	TransactionAmount       money.Money
 	BankCode                string
 	Status                  string
 	RequestMethod           string
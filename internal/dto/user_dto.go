@@ -4,7 +4,28 @@ type InviteMerchantUserDto struct {
 	Email      string `json:"email"`
 	RolesId    int    `json:"rolesId"`
 	MerchantId string `json:"merchantId"`
-	Username   string
+	// Username is the user sending the invitation
+	Username string
+}
+
+type InvitationEmailDataDto struct {
+	Email       string
+	InviteLink  string
+	ExpiresDays int
+}
+
+// InvitationPayload names a pending invitation of a merchant, MerchantId is read from the user behind
+// Username when it's empty
+type InvitationPayload struct {
+	InvitationId int    `json:"invitationId"`
+	MerchantId   string `json:"merchantId"`
+	Username     string
+}
+
+type AcceptInvitationPayload struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+	Pin      string `json:"pin"`
 }
 
 type EmailDataHtmlDto struct {
@@ -13,8 +34,84 @@ type EmailDataHtmlDto struct {
 	Pin      string `json:"pin"`
 }
 
+type LockoutEmailDataDto struct {
+	Username      string
+	Credential    string
+	LockedMinutes int
+}
+
+type ResetCredentialEmailDataDto struct {
+	Username       string
+	Credential     string
+	ResetLink      string
+	ExpiresMinutes int
+}
+
+// MerchantUserPayload changes a user of a merchant, MerchantId is read from the user behind Operator when
+// it's empty
+type MerchantUserPayload struct {
+	UserId     int    `json:"userId"`
+	MerchantId string `json:"merchantId"`
+	Status     string `json:"status"`
+	RoleId     int    `json:"roleId"`
+	// Actor is the user making the change
+	Actor AuditActor `json:"-"`
+}
+
+type UnlockUserPayload struct {
+	Username  string `json:"username"`
+	IpAddress string `json:"ipAddress"`
+	// Actor is the admin doing the unlock
+	Actor AuditActor `json:"-"`
+}
+
 type UpdatePassOrPinDto struct {
 	Username string
 	Password *string `json:"password"`
 	Pin      *string `json:"pin"`
 }
+
+type RequestCredentialResetPayload struct {
+	Username string `json:"username"`
+	// Credential is PASSWORD or PIN
+	Credential string `json:"credential"`
+	IpAddress  string `json:"-"`
+}
+
+// ConfirmCredentialResetPayload sets the credential named by the reset token, only the matching field is read
+type ConfirmCredentialResetPayload struct {
+	Token    string  `json:"token"`
+	Password *string `json:"password"`
+	Pin      *string `json:"pin"`
+}
+
+type CreateRolePayload struct {
+	RoleName          string   `json:"roleName"`
+	Permissions       []string `json:"permissions"`
+	TwoFactorRequired bool     `json:"twoFactorRequired"`
+	// MerchantAssignable lets merchant users be given the role
+	MerchantAssignable bool `json:"merchantAssignable"`
+}
+
+type UpdateRolePermissionsPayload struct {
+	RoleId      int      `json:"roleId"`
+	Permissions []string `json:"permissions"`
+	// TwoFactorRequired is left as is when omitted
+	TwoFactorRequired *bool `json:"twoFactorRequired"`
+	// MerchantAssignable is left as is when omitted, users already given the role keep it
+	MerchantAssignable *bool `json:"merchantAssignable"`
+	Username           string
+}
+
+// LoginChallengePayload answers the challenge Login returns when a second factor is needed
+type LoginChallengePayload struct {
+	ChallengeToken string `json:"challengeToken"`
+	Code           string `json:"code"`
+	Device         string `json:"-"`
+	IpAddress      string `json:"-"`
+}
+
+type TwoFactorCodePayload struct {
+	Code     string `json:"code"`
+	Username string
+}
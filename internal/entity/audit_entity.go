@@ -0,0 +1,21 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditLog struct {
+	Id            int              `db:"id" json:"id"`
+	HistoryType   string           `db:"history_type" json:"historyType"`
+	Activity      *string          `db:"activity" json:"activity"`
+	TargetId      *string          `db:"target_id" json:"targetId"`
+	PayloadBefore *json.RawMessage `db:"payload_before" json:"payloadBefore"`
+	PayloadAfter  *json.RawMessage `db:"payload_after" json:"payloadAfter"`
+	Changes       *json.RawMessage `db:"changes" json:"changes"`
+	Username      *string          `db:"username" json:"username"`
+	IpAddress     *string          `db:"ip_address" json:"ipAddress"`
+	RequestId     *string          `db:"request_id" json:"requestId"`
+	ResponseCode  *int             `db:"response_code" json:"responseCode"`
+	CreatedAt     time.Time        `db:"created_at" json:"createdAt"`
+}
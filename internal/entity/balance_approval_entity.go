@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+)
+
+type BalanceApprovalThreshold struct {
+	OperationType string      `db:"operation_type" json:"operationType"`
+	MinAmount     money.Money `db:"min_amount" json:"minAmount"`
+	UpdatedBy     string      `db:"updated_by" json:"updatedBy"`
+	UpdatedAt     time.Time   `db:"updated_at" json:"updatedAt"`
+}
+
+type BalanceOperationRequest struct {
+	Id               int             `db:"id" json:"id"`
+	OperationType    string          `db:"operation_type" json:"operationType"`
+	MerchantId       *string         `db:"merchant_id" json:"merchantId"`
+	TargetMerchantId *string         `db:"target_merchant_id" json:"targetMerchantId"`
+	PaymentId        *string         `db:"payment_id" json:"paymentId"`
+	Amount           money.Money     `db:"amount" json:"amount"`
+	Notes            *string         `db:"notes" json:"notes"`
+	Payload          json.RawMessage `db:"payload" json:"payload"`
+	Status           string          `db:"status" json:"status"`
+	RequestedBy      string          `db:"requested_by" json:"requestedBy"`
+	ReviewedBy       *string         `db:"reviewed_by" json:"reviewedBy"`
+	ReviewNotes      *string         `db:"review_notes" json:"reviewNotes"`
+	ReviewedAt       *time.Time      `db:"reviewed_at" json:"reviewedAt"`
+	CreatedAt        time.Time       `db:"created_at" json:"createdAt"`
+	UpdatedAt        time.Time       `db:"updated_at" json:"updatedAt"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+)
+
+type DisbursementApprovalRule struct {
+	Id                int         `db:"id" json:"id"`
+	MerchantId        string      `db:"merchant_id" json:"merchantId"`
+	MinAmount         money.Money `db:"min_amount" json:"minAmount"`
+	RequiredApprovals int         `db:"required_approvals" json:"requiredApprovals"`
+	CreatedBy         string      `db:"created_by" json:"createdBy"`
+	CreatedAt         time.Time   `db:"created_at" json:"createdAt"`
+}
+
+type DisbursementRequest struct {
+	Id                int         `db:"id" json:"id"`
+	MerchantId        string      `db:"merchant_id" json:"merchantId"`
+	Amount            money.Money `db:"amount" json:"amount"`
+	BankName          string      `db:"bank_name" json:"bankName"`
+	BankAccountName   string      `db:"bank_account_name" json:"bankAccountName"`
+	BankAccountNumber string      `db:"bank_account_number" json:"bankAccountNumber"`
+	Note              *string     `db:"note" json:"note"`
+	RequiredApprovals int         `db:"required_approvals" json:"requiredApprovals"`
+	Status            string      `db:"status" json:"status"`
+	PaymentId         *string     `db:"payment_id" json:"paymentId"`
+	FailureReason     *string     `db:"failure_reason" json:"failureReason"`
+	RequestedBy       string      `db:"requested_by" json:"requestedBy"`
+	CreatedAt         time.Time   `db:"created_at" json:"createdAt"`
+	UpdatedAt         time.Time   `db:"updated_at" json:"updatedAt"`
+}
+
+type DisbursementRequestReview struct {
+	Id                    int       `db:"id" json:"id"`
+	DisbursementRequestId int       `db:"disbursement_request_id" json:"disbursementRequestId"`
+	ReviewedBy            string    `db:"reviewed_by" json:"reviewedBy"`
+	Decision              string    `db:"decision" json:"decision"`
+	Notes                 *string   `db:"notes" json:"notes"`
+	CreatedAt             time.Time `db:"created_at" json:"createdAt"`
+}
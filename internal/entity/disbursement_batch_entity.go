@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+)
+
+type DisbursementBatch struct {
+	Id          int         `db:"id" json:"id"`
+	MerchantId  string      `db:"merchant_id" json:"merchantId"`
+	FileName    string      `db:"file_name" json:"fileName"`
+	Status      string      `db:"status" json:"status"`
+	TotalRows   int         `db:"total_rows" json:"totalRows"`
+	ValidRows   int         `db:"valid_rows" json:"validRows"`
+	TotalAmount money.Money `db:"total_amount" json:"totalAmount"`
+	TotalFee    money.Money `db:"total_fee" json:"totalFee"`
+	UploadedBy  string      `db:"uploaded_by" json:"uploadedBy"`
+	ConfirmedBy *string     `db:"confirmed_by" json:"confirmedBy"`
+	ConfirmedAt *time.Time  `db:"confirmed_at" json:"confirmedAt"`
+	CreatedAt   time.Time   `db:"created_at" json:"createdAt"`
+	UpdatedAt   time.Time   `db:"updated_at" json:"updatedAt"`
+}
+
+type DisbursementBatchRow struct {
+	Id                    int          `db:"id" json:"id"`
+	DisbursementBatchId   int          `db:"disbursement_batch_id" json:"disbursementBatchId"`
+	RowNumber             int          `db:"row_number" json:"rowNumber"`
+	BankCode              string       `db:"bank_code" json:"bankCode"`
+	BankName              *string      `db:"bank_name" json:"bankName"`
+	BankAccountName       string       `db:"bank_account_name" json:"bankAccountName"`
+	BankAccountNumber     string       `db:"bank_account_number" json:"bankAccountNumber"`
+	Amount                *money.Money `db:"amount" json:"amount"`
+	Fee                   money.Money  `db:"fee" json:"fee"`
+	Note                  *string      `db:"note" json:"note"`
+	Status                string       `db:"status" json:"status"`
+	ErrorMessage          *string      `db:"error_message" json:"errorMessage"`
+	PaymentId             *string      `db:"payment_id" json:"paymentId"`
+	DisbursementRequestId *int         `db:"disbursement_request_id" json:"disbursementRequestId"`
+	UpdatedAt             time.Time    `db:"updated_at" json:"updatedAt"`
+}
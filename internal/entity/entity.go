@@ -1,9 +1,14 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+)
 
 type Permission struct {
 	PermissionID   int    `db:"permission_id" json:"permissionId"`
+	PermissionName string `db:"permission_name" json:"permissionName"`
 	PermissionDesc string `db:"permission_desc" json:"permissionDesc"`
 }
 
@@ -16,7 +21,6 @@ type User struct {
 	Pin            string       `db:"pin" json:"-"`
 	MerchantID     *string      `db:"merchant_id" json:"merchantId,omitempty"`
 	MerchantName   *string      `db:"merchant_name" json:"merchantName,omitempty"`
-	MerchantSecret *string      `db:"merchant_secret" json:"-"`
 	Currency       *string      `db:"currency" json:"currency,omitempty"`
 	MerchantStatus *string      `db:"merchant_status" json:"merchantStatus,omitempty"`
 	RoleId         int          `db:"role_id" json:"-"`
@@ -25,24 +29,29 @@ type User struct {
 	UserStatus     string       `db:"user_status" json:"userStatus"`
 	UserCreatedAt  time.Time    `db:"user_created_at" json:"createdAt"`
 	UserUpdatedAt  time.Time    `db:"user_updated_at" json:"updatedAt"`
+	// TwoFactorEnabled is set once the user verified a first code, TwoFactorRequired comes from the role
+	TwoFactorEnabled  bool    `db:"two_factor_enabled" json:"twoFactorEnabled"`
+	TwoFactorRequired bool    `db:"two_factor_required" json:"twoFactorRequired"`
+	TotpSecret        *string `db:"totp_secret" json:"-"`
+	TotpLastStep      *int64  `db:"totp_last_used_step" json:"-"`
 }
 
 // Transaction represents a transaction record.
 type Transaction struct {
-	Id                      int     `db:"id" json:"id"`
-	PaymentID               string  `db:"payment_id" json:"transactionId"`
-	MerchantReferenceNumber string  `db:"merchant_reference_number" json:"merchantReferenceNumber"`
-	TransactionAmount       float64 `db:"transaction_amount" json:"transactionAmount"`
-	TransactionStatus       string  `db:"transaction_status" json:"transactionStatus"`
-	MerchantId              string  `db:"merchant_id" json:"merchantId"`
-	MerchantName            string  `db:"merchant_name" json:"merchantName"`
-	PaymentMethodName       string  `db:"payment_method_name" json:"paymentMethodName"`
-	PaymentMethodType       string  `db:"payment_method_type" json:"paymentMethodType"`
-	ProviderPaychannelName  string  `db:"provider_paychannel_name" json:"providerPaychannelName"`
-	BankName                *string `db:"bank_name" json:"bankName"`
-	BankCode                *string `db:"bank_code" json:"bankCode"`
-	TransactionCreatedAt    string  `db:"transaction_created_at" json:"createdAt"`
-	TransactionUpdatedAt    string  `db:"transaction_updated_at" json:"updatedAt"`
+	Id                      int         `db:"id" json:"id"`
+	PaymentID               string      `db:"payment_id" json:"transactionId"`
+	MerchantReferenceNumber string      `db:"merchant_reference_number" json:"merchantReferenceNumber"`
+	TransactionAmount       money.Money `db:"transaction_amount" json:"transactionAmount"`
+	TransactionStatus       string      `db:"transaction_status" json:"transactionStatus"`
+	MerchantId              string      `db:"merchant_id" json:"merchantId"`
+	MerchantName            string      `db:"merchant_name" json:"merchantName"`
+	PaymentMethodName       string      `db:"payment_method_name" json:"paymentMethodName"`
+	PaymentMethodType       string      `db:"payment_method_type" json:"paymentMethodType"`
+	ProviderPaychannelName  string      `db:"provider_paychannel_name" json:"providerPaychannelName"`
+	BankName                *string     `db:"bank_name" json:"bankName"`
+	BankCode                *string     `db:"bank_code" json:"bankCode"`
+	TransactionCreatedAt    string      `db:"transaction_created_at" json:"createdAt"`
+	TransactionUpdatedAt    string      `db:"transaction_updated_at" json:"updatedAt"`
 }
 
 type AccountData struct {
@@ -61,45 +70,45 @@ type AccountData struct {
 }
 
 type PaymentDetailMerchantProvider struct {
-	TransactionID          int       `db:"transaction_id"`
-	PaymentID              string    `db:"payment_id"`
-	MerchantRefNumber      string    `db:"merchant_reference_number"`
-	ProviderRefNumber      string    `db:"provider_reference_number"`
-	TransactionAmount      float64   `db:"transaction_amount"`
-	BankCode               string    `db:"bank_code"`
-	Status                 string    `db:"status"`
-	ClientIPAddress        string    `db:"client_ip_address"`
-	MerchantCallbackURL    string    `db:"merchant_callback_url"`
-	RequestMethod          string    `db:"request_method"`
-	PaymentMethodName      string    `db:"payment_method_name"`
-	PayType                string    `db:"pay_type"`
-	TransactionCreatedAt   time.Time `db:"transaction_created_at"`
-	TransactionUpdatedAt   time.Time `db:"transaction_updated_at"`
-	MerchantPaychannelID   int       `db:"merchant_payment_method_id"`
-	Segment                *string   `db:"segment"`
-	MerchantId             string    `db:"merchant_id"`
-	MerchantName           string    `db:"merchant_name"`
-	MerchantFee            float64   `db:"merchant_fee"`
-	MerchantFeeType        string    `db:"merchant_fee_type"`
-	MerchantStatus         string    `db:"merchant_status"`
-	MerchantMinTrans       float64   `db:"merchant_min_transaction"`
-	MerchantMaxTrans       float64   `db:"merchant_max_transaction"`
-	MerchantMaxDailyTrans  float64   `db:"merchant_max_daily_transaction"`
-	MerchantPaychannelCode string    `db:"merchant_paychannel_code"`
-	MerchantCreatedAt      time.Time `db:"merchant_created_at"`
-	MerchantUpdatedAt      time.Time `db:"merchant_updated_at"`
-	ProviderName           string    `db:"provider_name"`
-	ProviderPaychannelID   int       `db:"provider_payment_method_id"`
-	PaychannelName         string    `db:"paychannel_name"`
-	ProviderFee            float64   `db:"provider_fee"`
-	ProviderFeeType        string    `db:"provider_fee_type"`
-	ProviderStatus         string    `db:"provider_status"`
-	ProviderMinTrans       float64   `db:"provider_min_transaction"`
-	ProviderMaxTrans       float64   `db:"provider_max_transaction"`
-	ProviderMaxDailyTrans  float64   `db:"provider_max_daily_transaction"`
-	InterfaceSetting       *string   `db:"interface_setting"`
-	ProviderCreatedAt      time.Time `db:"provider_created_at"`
-	ProviderUpdatedAt      time.Time `db:"provider_updated_at"`
+	TransactionID          int         `db:"transaction_id"`
+	PaymentID              string      `db:"payment_id"`
+	MerchantRefNumber      string      `db:"merchant_reference_number"`
+	ProviderRefNumber      string      `db:"provider_reference_number"`
+	TransactionAmount      money.Money `db:"transaction_amount"`
+	BankCode               string      `db:"bank_code"`
+	Status                 string      `db:"status"`
+	ClientIPAddress        string      `db:"client_ip_address"`
+	MerchantCallbackURL    string      `db:"merchant_callback_url"`
+	RequestMethod          string      `db:"request_method"`
+	PaymentMethodName      string      `db:"payment_method_name"`
+	PayType                string      `db:"pay_type"`
+	TransactionCreatedAt   time.Time   `db:"transaction_created_at"`
+	TransactionUpdatedAt   time.Time   `db:"transaction_updated_at"`
+	MerchantPaychannelID   int         `db:"merchant_payment_method_id"`
+	Segment                *string     `db:"segment"`
+	MerchantId             string      `db:"merchant_id"`
+	MerchantName           string      `db:"merchant_name"`
+	MerchantFee            money.Money `db:"merchant_fee"`
+	MerchantFeeType        string      `db:"merchant_fee_type"`
+	MerchantStatus         string      `db:"merchant_status"`
+	MerchantMinTrans       money.Money `db:"merchant_min_transaction"`
+	MerchantMaxTrans       money.Money `db:"merchant_max_transaction"`
+	MerchantMaxDailyTrans  money.Money `db:"merchant_max_daily_transaction"`
+	MerchantPaychannelCode string      `db:"merchant_paychannel_code"`
+	MerchantCreatedAt      time.Time   `db:"merchant_created_at"`
+	MerchantUpdatedAt      time.Time   `db:"merchant_updated_at"`
+	ProviderName           string      `db:"provider_name"`
+	ProviderPaychannelID   int         `db:"provider_payment_method_id"`
+	PaychannelName         string      `db:"paychannel_name"`
+	ProviderFee            money.Money `db:"provider_fee"`
+	ProviderFeeType        string      `db:"provider_fee_type"`
+	ProviderStatus         string      `db:"provider_status"`
+	ProviderMinTrans       money.Money `db:"provider_min_transaction"`
+	ProviderMaxTrans       money.Money `db:"provider_max_transaction"`
+	ProviderMaxDailyTrans  money.Money `db:"provider_max_daily_transaction"`
+	InterfaceSetting       *string     `db:"interface_setting"`
+	ProviderCreatedAt      time.Time   `db:"provider_created_at"`
+	ProviderUpdatedAt      time.Time   `db:"provider_updated_at"`
 }
 
 type ProviderConfirmDetail struct {
@@ -124,48 +133,50 @@ type TransactionStatusLogs struct {
 }
 
 type CapitalFlows struct {
-	Id                int       `db:"id" json:"id"`
-	PaymentId         string    `db:"payment_id" json:"paymentId"`
-	MerchantAccountId int       `db:"merchant_account_id" json:"merchantAccountId"`
-	MerchantId        string    `db:"merchant_id" json:"merchantCode"`
-	MerchantName      string    `db:"merchant_name" json:"merchantName"`
-	TempBalance       float64   `db:"temp_balance" json:"tempBalance"`
-	ReasonId          int       `db:"reason_id" json:"reasonId"`
-	ReasonName        string    `db:"reason_name" json:"reasonName"`
-	ReasonDescription string    `db:"reason_description" json:"reasonDescription"`
-	Status            string    `db:"status" json:"status"`
-	Notes             *string   `db:"notes" json:"notes"`
-	Amount            float64   `db:"amount" json:"amount"`
-	CapitalType       string    `db:"capital_type" json:"capitalType"`
-	CreatedBy         string    `db:"created_by" json:"createdBy"`
-	CreatedAt         time.Time `db:"created_at" json:"createdAt"`
+	Id                int         `db:"id" json:"id"`
+	PaymentId         string      `db:"payment_id" json:"paymentId"`
+	MerchantAccountId int         `db:"merchant_account_id" json:"merchantAccountId"`
+	MerchantId        string      `db:"merchant_id" json:"merchantCode"`
+	MerchantName      string      `db:"merchant_name" json:"merchantName"`
+	TempBalance       money.Money `db:"temp_balance" json:"tempBalance"`
+	ReasonId          int         `db:"reason_id" json:"reasonId"`
+	ReasonName        string      `db:"reason_name" json:"reasonName"`
+	ReasonDescription string      `db:"reason_description" json:"reasonDescription"`
+	Status            string      `db:"status" json:"status"`
+	Notes             *string     `db:"notes" json:"notes"`
+	Amount            money.Money `db:"amount" json:"amount"`
+	CapitalType       string      `db:"capital_type" json:"capitalType"`
+	CreatedBy         string      `db:"created_by" json:"createdBy"`
+	CreatedAt         time.Time   `db:"created_at" json:"createdAt"`
 }
 
 type TransactionCapitalFlows struct {
-	Id              int       `db:"id" json:"id"`
-	PaymentId       string    `db:"payment_id" json:"transactionId"`
-	TransactionType string    `db:"reason_name" json:"transactionType"`
-	Amount          float64   `db:"amount" json:"amount"`
-	CreatedAt       time.Time `db:"created_at" json:"processedAt"`
-	MerchantId      string    `db:"merchant_id" json:"correspondingAccount"`
-	Status          string    `db:"status" json:"status"`
-	TempBalance     float64   `db:"temp_balance" json:"balance"`
-	CapitalType     string    `db:"capital_type" json:"capitalType"`
+	Id              int         `db:"id" json:"id"`
+	PaymentId       string      `db:"payment_id" json:"transactionId"`
+	TransactionType string      `db:"reason_name" json:"transactionType"`
+	Amount          money.Money `db:"amount" json:"amount"`
+	CreatedAt       time.Time   `db:"created_at" json:"processedAt"`
+	MerchantId      string      `db:"merchant_id" json:"correspondingAccount"`
+	Status          string      `db:"status" json:"status"`
+	TempBalance     money.Money `db:"temp_balance" json:"balance"`
+	CapitalType     string      `db:"capital_type" json:"capitalType"`
 }
 
 type MerchantCallback struct {
-	Id                      int       `db:"id" json:"id"`
-	PaymentId               string    `db:"payment_id" json:"paymentId"`
-	CallbackStatus          string    `db:"callback_status" json:"callbackStatus"`
-	PaymentStatusInCallback string    `db:"payment_status_in_callback" json:"paymentStatusInCallback"`
-	CallbackResult          string    `db:"callback_result" json:"callbackResult"`
-	TriggeredBy             string    `db:"triggered_by" json:"triggeredBy"`
-	CallbackRequest         string    `db:"callback_request" json:"-"`
-	CreatedAt               time.Time `db:"created_at" json:"-"`
-	StartedAt               string    `json:"startedAt,omitempty"`
-	RetriedAt               string    `json:"retriedAt,omitempty"`
-	CallbackAt              string    `json:"callbackAt,omitempty"`
-	CallbackRequestResp     string    `json:"callbackRequest,omitempty"`
+	Id                      int        `db:"id" json:"id"`
+	PaymentId               string     `db:"payment_id" json:"paymentId"`
+	CallbackStatus          string     `db:"callback_status" json:"callbackStatus"`
+	PaymentStatusInCallback string     `db:"payment_status_in_callback" json:"paymentStatusInCallback"`
+	CallbackResult          string     `db:"callback_result" json:"callbackResult"`
+	TriggeredBy             string     `db:"triggered_by" json:"triggeredBy"`
+	AttemptNumber           *int       `db:"attempt_number" json:"attemptNumber"`
+	NextRetryAt             *time.Time `db:"next_retry_at" json:"nextRetryAt"`
+	CallbackRequest         string     `db:"callback_request" json:"-"`
+	CreatedAt               time.Time  `db:"created_at" json:"-"`
+	StartedAt               string     `json:"startedAt,omitempty"`
+	RetriedAt               string     `json:"retriedAt,omitempty"`
+	CallbackAt              string     `json:"callbackAt,omitempty"`
+	CallbackRequestResp     string     `json:"callbackRequest,omitempty"`
 }
 
 type ListMerchantCallback struct {
@@ -182,31 +193,34 @@ type ListMerchantCallback struct {
 	MerchantName            string      `db:"merchant_name" json:"merchantName"`
 	MerchantDetailData      interface{} `json:"merchantDetailData"`
 	PaymentType             string      `db:"pay_type" json:"payType"`
+	DeliveryStatus          *string     `db:"delivery_status" json:"deliveryStatus"`
+	AttemptNumber           *int        `db:"attempt_number" json:"attemptNumber"`
+	NextRetryAt             *time.Time  `db:"next_retry_at" json:"nextRetryAt"`
 }
 
 type MerchantAccount struct {
-	Id                    int       `db:"id" json:"id"`
-	MerchantId            string    `db:"merchant_id" json:"merchantId"`
-	SettledBalance        float64   `db:"settle_balance" json:"settleBalance"`
-	NotSettledBalance     float64   `db:"not_settle_balance" json:"notSettleBalance"`
-	HoldBalance           float64   `db:"hold_balance" json:"holdBalance"`
-	BalanceCapitalFlow    float64   `db:"balance_capital_flow" json:"balanceCapitalFlow"`
-	PendingTransactionOut float64   `db:"pending_transaction_out" json:"pendingTransactionOut"`
-	CreatedAt             time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt             time.Time `db:"updated_at" json:"updatedAt"`
+	Id                    int         `db:"id" json:"id"`
+	MerchantId            string      `db:"merchant_id" json:"merchantId"`
+	SettledBalance        money.Money `db:"settle_balance" json:"settleBalance"`
+	NotSettledBalance     money.Money `db:"not_settle_balance" json:"notSettleBalance"`
+	HoldBalance           money.Money `db:"hold_balance" json:"holdBalance"`
+	BalanceCapitalFlow    money.Money `db:"balance_capital_flow" json:"balanceCapitalFlow"`
+	PendingTransactionOut money.Money `db:"pending_transaction_out" json:"pendingTransactionOut"`
+	CreatedAt             time.Time   `db:"created_at" json:"createdAt"`
+	UpdatedAt             time.Time   `db:"updated_at" json:"updatedAt"`
 }
 
 type ManualPayment struct {
-	Id          int       `db:"id" json:"id"`
-	PaymentId   string    `db:"payment_id" json:"paymentId"`
-	MerchantId  string    `db:"merchant_id" json:"merchantAccount"`
-	ReasonId    int       `db:"reason_id" json:"-"`
-	ReasonName  string    `db:"reason_name" json:"reasonName"`
-	Amount      float64   `db:"amount" json:"amount"`
-	Status      string    `db:"status" json:"status"`
-	Notes       *string   `db:"notes" json:"-"`
-	CapitalType string    `db:"capital_type" json:"capitalType"`
-	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+	Id          int         `db:"id" json:"id"`
+	PaymentId   string      `db:"payment_id" json:"paymentId"`
+	MerchantId  string      `db:"merchant_id" json:"merchantAccount"`
+	ReasonId    int         `db:"reason_id" json:"-"`
+	ReasonName  string      `db:"reason_name" json:"reasonName"`
+	Amount      money.Money `db:"amount" json:"amount"`
+	Status      string      `db:"status" json:"status"`
+	Notes       *string     `db:"notes" json:"-"`
+	CapitalType string      `db:"capital_type" json:"capitalType"`
+	CreatedAt   time.Time   `db:"created_at" json:"createdAt"`
 }
 
 type PaymentMethods struct {
@@ -228,18 +242,27 @@ type Providers struct {
 }
 
 type Merchants struct {
-	Id             int       `db:"id" json:"id"`
-	MerchantId     string    `db:"merchant_id" json:"merchantId"`
-	MerchantName   string    `db:"merchant_name" json:"merchantName"`
-	MerchantSecret string    `db:"merchant_secret" json:"-"`
-	Currency       string    `db:"currency" json:"currency"`
-	Status         string    `db:"status" json:"status"`
-	CreatedAt      time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt      time.Time `db:"updated_at" json:"updatedAt"`
+	Id           int       `db:"id" json:"id"`
+	MerchantId   string    `db:"merchant_id" json:"merchantId"`
+	MerchantName string    `db:"merchant_name" json:"merchantName"`
+	Currency     string    `db:"currency" json:"currency"`
+	Status       string    `db:"status" json:"status"`
+	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updatedAt"`
 }
 
-type MerchantSecret struct {
-	SecretKey string `db:"merchant_secret" json:"secretKey"`
+type MerchantKeyEntity struct {
+	Id             int        `db:"id" json:"-"`
+	MerchantId     string     `db:"merchant_id" json:"-"`
+	Version        int        `db:"version" json:"version"`
+	Secret         string     `db:"secret" json:"secretKey,omitempty"`
+	Status         string     `db:"status" json:"status"`
+	OverlapSeconds int        `db:"overlap_seconds" json:"-"`
+	ActivateAt     time.Time  `db:"activate_at" json:"activateAt"`
+	RetireAt       *time.Time `db:"retire_at" json:"retireAt"`
+	CreatedBy      string     `db:"created_by" json:"createdBy"`
+	CreatedAt      time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updatedAt"`
 }
 
 type PayChannels struct {
@@ -257,51 +280,51 @@ type Reasons struct {
 }
 
 type MerchantPaychannel struct {
-	Id                      int       `db:"id" json:"id"`
-	MerchantPaymentMethodId int       `db:"merchant_payment_method_id" json:"-"`
-	PayChannelCode          string    `db:"merchant_paychannel_code" json:"merchantPaychannelCode"`
-	PaymentMethodChannel    string    `db:"name" json:"paymentMethodChannel"`
-	PayTypeChannel          string    `db:"pay_type" json:"payTypeChannel"`
-	Fee                     float64   `db:"fee" json:"channelFee"`
-	FeeType                 string    `db:"fee_type" json:"feeType"`
-	Status                  string    `db:"status" json:"status"`
-	Segment                 string    `db:"segment" json:"segment"`
-	MinTransaction          float64   `db:"min_transaction" json:"minTransaction"`
-	MaxTransaction          float64   `db:"max_transaction" json:"maxTransaction"`
-	MaxDailyTransaction     float64   `db:"max_daily_transaction" json:"maxDailyTransaction"`
-	CreatedAt               time.Time `db:"created_at" json:"createdAt"`
-	UpdatedAt               time.Time `db:"updated_at" json:"updatedAt"`
-	ActiveAvailableChannel  string    `json:"activeAvailableChannel"`
+	Id                      int         `db:"id" json:"id"`
+	MerchantPaymentMethodId int         `db:"merchant_payment_method_id" json:"-"`
+	PayChannelCode          string      `db:"merchant_paychannel_code" json:"merchantPaychannelCode"`
+	PaymentMethodChannel    string      `db:"name" json:"paymentMethodChannel"`
+	PayTypeChannel          string      `db:"pay_type" json:"payTypeChannel"`
+	Fee                     money.Money `db:"fee" json:"channelFee"`
+	FeeType                 string      `db:"fee_type" json:"feeType"`
+	Status                  string      `db:"status" json:"status"`
+	Segment                 string      `db:"segment" json:"segment"`
+	MinTransaction          money.Money `db:"min_transaction" json:"minTransaction"`
+	MaxTransaction          money.Money `db:"max_transaction" json:"maxTransaction"`
+	MaxDailyTransaction     money.Money `db:"max_daily_transaction" json:"maxDailyTransaction"`
+	CreatedAt               time.Time   `db:"created_at" json:"createdAt"`
+	UpdatedAt               time.Time   `db:"updated_at" json:"updatedAt"`
+	ActiveAvailableChannel  string      `json:"activeAvailableChannel"`
 }
 
 type ListMerchantAccountDto struct {
-	Id                    int       `db:"id" json:"id"`
-	MerchantId            string    `db:"merchant_id" json:"merchantId"`
-	MerchantName          string    `db:"merchant_name" json:"merchantName"`
-	SettleBalane          float64   `db:"settle_balance" json:"settleBalance"`
-	NotSettleBalance      float64   `db:"not_settle_balance" json:"notSettleBalance"`
-	HoldBalance           float64   `db:"hold_balance" json:"holdBalance"`
-	PendingTransactionOut float64   `db:"pending_transaction_out" json:"pendingTransactionOut"`
-	BalanceCapitalFlow    float64   `db:"balance_capital_flow" json:"balanceCapitalFlow"`
-	CreatedAt             time.Time `db:"created_at" json:"createdAt"`
+	Id                    int         `db:"id" json:"id"`
+	MerchantId            string      `db:"merchant_id" json:"merchantId"`
+	MerchantName          string      `db:"merchant_name" json:"merchantName"`
+	SettleBalane          money.Money `db:"settle_balance" json:"settleBalance"`
+	NotSettleBalance      money.Money `db:"not_settle_balance" json:"notSettleBalance"`
+	HoldBalance           money.Money `db:"hold_balance" json:"holdBalance"`
+	PendingTransactionOut money.Money `db:"pending_transaction_out" json:"pendingTransactionOut"`
+	BalanceCapitalFlow    money.Money `db:"balance_capital_flow" json:"balanceCapitalFlow"`
+	CreatedAt             time.Time   `db:"created_at" json:"createdAt"`
 }
 
 type RoutedPaychanneDto struct {
-	Id                     int     `db:"id" json:"id"`
-	ProviderPaychannelId   int     `db:"provider_paychannel_id" json:"-"`
-	ProviderPaychannelName string  `db:"paychannel_name" json:"providerPaychannelName"`
-	ProviderId             string  `db:"provider_id" json:"-"`
-	ProviderName           string  `db:"provider_name" json:"providerName"`
-	PaymentMethodType      string  `db:"pay_type" json:"paymentType"`
-	PaymentMethodName      string  `db:"name" json:"paymentMethod"`
-	FeeResp                string  `json:"fees"`
-	FeesDb                 float64 `db:"fee" json:"-"`
-	FeeType                string  `db:"fee_type" json:"-"`
-	MinTransaction         float64 `db:"min_transaction" json:"minTransaction"`
-	MaxTransaction         float64 `db:"max_transaction" json:"maxTransaction"`
-	MaxDailyTransaction    float64 `db:"max_daily_transaction" json:"maxDailyTransaction"`
-	Status                 string  `db:"status" json:"status"`
-	InterfaceSetting       string  `db:"interface_setting" json:"-"`
+	Id                     int         `db:"id" json:"id"`
+	ProviderPaychannelId   int         `db:"provider_paychannel_id" json:"-"`
+	ProviderPaychannelName string      `db:"paychannel_name" json:"providerPaychannelName"`
+	ProviderId             string      `db:"provider_id" json:"-"`
+	ProviderName           string      `db:"provider_name" json:"providerName"`
+	PaymentMethodType      string      `db:"pay_type" json:"paymentType"`
+	PaymentMethodName      string      `db:"name" json:"paymentMethod"`
+	FeeResp                string      `json:"fees"`
+	FeesDb                 money.Money `db:"fee" json:"-"`
+	FeeType                string      `db:"fee_type" json:"-"`
+	MinTransaction         money.Money `db:"min_transaction" json:"minTransaction"`
+	MaxTransaction         money.Money `db:"max_transaction" json:"maxTransaction"`
+	MaxDailyTransaction    money.Money `db:"max_daily_transaction" json:"maxDailyTransaction"`
+	Status                 string      `db:"status" json:"status"`
+	InterfaceSetting       string      `db:"interface_setting" json:"-"`
 }
 
 type BankListDto struct {
@@ -311,3 +334,15 @@ type BankListDto struct {
 	CheckListFlagging bool      `json:"checkListFlagging"`
 	CreatedAt         time.Time `db:"created_at" json:"createdAt"`
 }
+
+type MerchantCallbackDelivery struct {
+	Id             int        `db:"id" json:"id"`
+	PaymentId      string     `db:"payment_id" json:"paymentId"`
+	PaymentStatus  string     `db:"payment_status" json:"paymentStatus"`
+	DeliveryStatus string     `db:"delivery_status" json:"deliveryStatus"`
+	AttemptCount   int        `db:"attempt_count" json:"attemptCount"`
+	NextRetryAt    *time.Time `db:"next_retry_at" json:"nextRetryAt"`
+	LastResult     *string    `db:"last_result" json:"lastResult"`
+	CreatedAt      time.Time  `db:"created_at" json:"createdAt"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updatedAt"`
+}
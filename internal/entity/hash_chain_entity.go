@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// ChainLink is one row of a hash chain with the values its hash covers
+type ChainLink struct {
+	Id       int
+	ChainSeq int64
+	PrevHash *string
+	Hash     *string
+	// Fields are the hashed values as text, in the order the database hashes them
+	Fields []*string
+}
+
+// ChainTail is the last link of a chain, capital flow chains are keyed by merchant account id
+type ChainTail struct {
+	Chain    string `db:"chain" json:"chain"`
+	ChainKey string `db:"chain_key" json:"chainKey"`
+	ChainSeq int64  `db:"chain_seq" json:"chainSeq"`
+	Hash     string `db:"hash" json:"hash"`
+}
+
+type ChainCheckpoint struct {
+	Id          int       `db:"id" json:"id"`
+	PrevDigest  *string   `db:"prev_digest" json:"prevDigest"`
+	Digest      string    `db:"digest" json:"digest"`
+	TotalChains int       `db:"total_chains" json:"totalChains"`
+	CreatedBy   string    `db:"created_by" json:"createdBy"`
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+}
@@ -0,0 +1,9 @@
+package entity
+
+import "github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+
+type LedgerAccountBalance struct {
+	AccountType string      `db:"account_type" json:"accountType"`
+	Debit       money.Money `db:"debit" json:"debit"`
+	Credit      money.Money `db:"credit" json:"credit"`
+}
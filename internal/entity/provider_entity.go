@@ -1,25 +1,29 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+)
 
 type ProviderPaychannelEntity struct {
-	Id             int     `db:"id" json:"id"`
-	PaychannelName string  `db:"paychannel_name" json:"code"`
-	Provider       string  `db:"provider_name" json:"provider"`
-	Currency       string  `db:"currency" json:"currency"`
-	MinAmount      float64 `db:"min_transaction" json:"minAmount"`
-	MaxAmount      float64 `db:"max_transaction" json:"maxAmount"`
+	Id             int         `db:"id" json:"id"`
+	PaychannelName string      `db:"paychannel_name" json:"code"`
+	Provider       string      `db:"provider_name" json:"provider"`
+	Currency       string      `db:"currency" json:"currency"`
+	MinAmount      money.Money `db:"min_transaction" json:"minAmount"`
+	MaxAmount      money.Money `db:"max_transaction" json:"maxAmount"`
 }
 
 type InterfacePaychannelEntity struct {
-	Id             int     `db:"id" json:"id"`
-	PaychannelName string  `db:"paychannel_name" json:"code"`
-	Provider       string  `db:"provider_name" json:"provider"`
-	Currency       string  `db:"currency" json:"currency"`
-	MinAmount      float64 `db:"min_transaction" json:"minAmount"`
-	MaxAmount      float64 `db:"max_transaction" json:"maxAmount"`
-	MaxDailyLimit  float64 `db:"max_daily_transaction" json:"maxDailyLimit"`
-	Status         string  `db:"status" json:"status"`
+	Id             int         `db:"id" json:"id"`
+	PaychannelName string      `db:"paychannel_name" json:"code"`
+	Provider       string      `db:"provider_name" json:"provider"`
+	Currency       string      `db:"currency" json:"currency"`
+	MinAmount      money.Money `db:"min_transaction" json:"minAmount"`
+	MaxAmount      money.Money `db:"max_transaction" json:"maxAmount"`
+	MaxDailyLimit  money.Money `db:"max_daily_transaction" json:"maxDailyLimit"`
+	Status         string      `db:"status" json:"status"`
 }
 
 type ProviderListEntity struct {
@@ -43,32 +47,32 @@ type ProviderInterfacesEntity struct {
 }
 
 type ProviderPaychannelAllEntity struct {
-	Id            int     `db:"id" json:"id"`
-	Paychannel    string  `db:"paychannel_name" json:"paychannel_name"`
-	Currency      string  `db:"currency" json:"currency"`
-	Provider      string  `db:"provider_name" json:"provider"`
-	PaymentType   string  `db:"pay_type" json:"paymentType"`
-	PaymentMethod string  `db:"name" json:"paymentMethod"`
-	MinAmount     float64 `db:"min_transaction" json:"minAmount"`
-	MaxAmount     float64 `db:"max_transaction" json:"maxAmount"`
-	MaxDailyLimit float64 `db:"max_daily_transaction" json:"maxDailyLimit"`
-	Status        string  `db:"status" json:"status"`
+	Id            int         `db:"id" json:"id"`
+	Paychannel    string      `db:"paychannel_name" json:"paychannel_name"`
+	Currency      string      `db:"currency" json:"currency"`
+	Provider      string      `db:"provider_name" json:"provider"`
+	PaymentType   string      `db:"pay_type" json:"paymentType"`
+	PaymentMethod string      `db:"name" json:"paymentMethod"`
+	MinAmount     money.Money `db:"min_transaction" json:"minAmount"`
+	MaxAmount     money.Money `db:"max_transaction" json:"maxAmount"`
+	MaxDailyLimit money.Money `db:"max_daily_transaction" json:"maxDailyLimit"`
+	Status        string      `db:"status" json:"status"`
 }
 
 type ProviderChannelDetailEntity struct {
-	Id               int     `db:"id" json:"id"`
-	Paychannel       string  `db:"paychannel_name" json:"paychannel_name"`
-	Currency         string  `db:"currency" json:"currency"`
-	Provider         string  `db:"provider_name" json:"provider"`
-	PaymentType      string  `db:"pay_type" json:"paymentType"`
-	PaymentMethod    string  `db:"name" json:"paymentMethod"`
-	Fee              float64 `db:"fee" json:"fee"`
-	FeeType          string  `db:"fee_type" json:"feeType"`
-	MinAmount        float64 `db:"min_transaction" json:"minAmount"`
-	MaxAmount        float64 `db:"max_transaction" json:"maxAmount"`
-	MaxDailyLimit    float64 `db:"max_daily_transaction" json:"maxDailyLimit"`
-	InterfaceSetting string  `db:"interface_setting" json:"interfaceSetting"`
-	Status           string  `db:"status" json:"status"`
+	Id               int         `db:"id" json:"id"`
+	Paychannel       string      `db:"paychannel_name" json:"paychannel_name"`
+	Currency         string      `db:"currency" json:"currency"`
+	Provider         string      `db:"provider_name" json:"provider"`
+	PaymentType      string      `db:"pay_type" json:"paymentType"`
+	PaymentMethod    string      `db:"name" json:"paymentMethod"`
+	Fee              money.Money `db:"fee" json:"fee"`
+	FeeType          string      `db:"fee_type" json:"feeType"`
+	MinAmount        money.Money `db:"min_transaction" json:"minAmount"`
+	MaxAmount        money.Money `db:"max_transaction" json:"maxAmount"`
+	MaxDailyLimit    money.Money `db:"max_daily_transaction" json:"maxDailyLimit"`
+	InterfaceSetting string      `db:"interface_setting" json:"interfaceSetting"`
+	Status           string      `db:"status" json:"status"`
 }
 
 type ProviderCredentialsEntity struct {
@@ -82,19 +86,19 @@ type ProviderCredentialsEntity struct {
 }
 
 type ProviderRoutedChannelEntity struct {
-	Id                      int       `db:"id" json:"id"`
-	PaychannelName          string    `db:"merchant_paychannel_code" json:"paychannelName"`
-	MerchantId              string    `db:"merchant_id" json:"merchantId"`
-	MerchantName            string    `db:"merchant_name" json:"merchantName"`
-	Fee                     float64   `db:"fee" json:"fee"`
-	FeeType                 string    `db:"fee_type" json:"feeType"`
-	Status                  string    `db:"status" json:"status"`
-	ActiveAvailableChannels string    `json:"activeAvailableChannels"`
-	MinTransaction          float64   `db:"min_transaction" json:"minTransaction"`
-	MaxTransaction          float64   `db:"max_transaction" json:"maxTransaction"`
-	MaxDailyTransaction     string    `db:"max_daily_transaction" json:"maxDailyTransaction"`
-	CreatedAt               time.Time `db:"created_at" json:"createdAt"`
-	PaymentMethodName       string    `db:"name" json:"-"`
+	Id                      int         `db:"id" json:"id"`
+	PaychannelName          string      `db:"merchant_paychannel_code" json:"paychannelName"`
+	MerchantId              string      `db:"merchant_id" json:"merchantId"`
+	MerchantName            string      `db:"merchant_name" json:"merchantName"`
+	Fee                     money.Money `db:"fee" json:"fee"`
+	FeeType                 string      `db:"fee_type" json:"feeType"`
+	Status                  string      `db:"status" json:"status"`
+	ActiveAvailableChannels string      `json:"activeAvailableChannels"`
+	MinTransaction          money.Money `db:"min_transaction" json:"minTransaction"`
+	MaxTransaction          money.Money `db:"max_transaction" json:"maxTransaction"`
+	MaxDailyTransaction     string      `db:"max_daily_transaction" json:"maxDailyTransaction"`
+	CreatedAt               time.Time   `db:"created_at" json:"createdAt"`
+	PaymentMethodName       string      `db:"name" json:"-"`
 }
 
 type ProviderPaychannelBankListEntity struct {
@@ -103,3 +107,13 @@ type ProviderPaychannelBankListEntity struct {
 	BankListId           int       `db:"bank_list_id" json:"bankListId"`
 	CreatedAt            time.Time `db:"created_at" json:"createdAt"`
 }
+
+type ProviderBankCodeEntity struct {
+	Id               int       `db:"id"`
+	ProviderId       string    `db:"provider_id"`
+	BankCode         string    `db:"bank_code"`
+	ProviderBankId   string    `db:"provider_bank_id"`
+	ProviderBankName string    `db:"provider_bank_name"`
+	CreatedAt        time.Time `db:"created_at"`
+	UpdatedAt        time.Time `db:"updated_at"`
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+)
+
+type ReconciliationRun struct {
+	Id                 int        `db:"id" json:"id"`
+	TriggeredBy        string     `db:"triggered_by" json:"triggeredBy"`
+	Status             string     `db:"status" json:"status"`
+	TotalAccounts      int        `db:"total_accounts" json:"totalAccounts"`
+	TotalDiscrepancies int        `db:"total_discrepancies" json:"totalDiscrepancies"`
+	Notes              *string    `db:"notes" json:"notes"`
+	StartedAt          time.Time  `db:"started_at" json:"startedAt"`
+	FinishedAt         *time.Time `db:"finished_at" json:"finishedAt"`
+}
+
+type ReconciliationDiscrepancy struct {
+	Id                  int         `db:"id" json:"id"`
+	ReconciliationRunId int         `db:"reconciliation_run_id" json:"reconciliationRunId"`
+	MerchantId          string      `db:"merchant_id" json:"merchantId"`
+	MerchantAccountId   int         `db:"merchant_account_id" json:"merchantAccountId"`
+	Bucket              string      `db:"bucket" json:"bucket"`
+	ExpectedAmount      money.Money `db:"expected_amount" json:"expectedAmount"`
+	ActualAmount        money.Money `db:"actual_amount" json:"actualAmount"`
+	Difference          money.Money `db:"difference" json:"difference"`
+	PaymentIds          string      `db:"payment_ids" json:"paymentIds"`
+	Status              string      `db:"status" json:"status"`
+	ResolvedBy          *string     `db:"resolved_by" json:"resolvedBy"`
+	ResolutionNotes     *string     `db:"resolution_notes" json:"resolutionNotes"`
+	ResolvedAt          *time.Time  `db:"resolved_at" json:"resolvedAt"`
+	CreatedAt           time.Time   `db:"created_at" json:"createdAt"`
+}
+
+type ReconciliationCapitalFlow struct {
+	Id          int         `db:"id"`
+	PaymentId   string      `db:"payment_id"`
+	Amount      money.Money `db:"amount"`
+	TempBalance money.Money `db:"temp_balance"`
+	CapitalType string      `db:"capital_type"`
+}
@@ -8,11 +8,73 @@ type RolesEntity struct {
 	Access string `db:"permission_desc" json:"access"`
 }
 
+type RoleEntity struct {
+	Id                int    `db:"id" json:"id"`
+	RoleName          string `db:"role_name" json:"roleName"`
+	TwoFactorRequired bool   `db:"two_factor_required" json:"twoFactorRequired"`
+	// MerchantAssignable roles may be given to merchant users
+	MerchantAssignable bool      `db:"merchant_assignable" json:"merchantAssignable"`
+	CreatedAt          time.Time `db:"created_at" json:"createdAt"`
+}
+
 type ListUsersEntity struct {
 	Id        int       `db:"id" json:"id"`
 	Username  string    `db:"username" json:"username"`
 	Email     string    `db:"email" json:"email"`
 	CreatedAt time.Time `db:"created_at" json:"createdAt"`
 	Status    string    `db:"status" json:"status"`
+	RoleId    int       `db:"role_id" json:"roleId"`
 	Roles     string    `db:"role_name" json:"roles"`
 }
+
+type UserSessionEntity struct {
+	Id         int        `db:"id" json:"id"`
+	SessionId  string     `db:"session_id" json:"sessionId"`
+	UserId     int        `db:"user_id" json:"userId"`
+	Username   string     `db:"username" json:"username"`
+	Device     string     `db:"device" json:"device"`
+	IpAddress  string     `db:"ip_address" json:"ipAddress"`
+	ExpiresAt  time.Time  `db:"expires_at" json:"expiresAt"`
+	LastUsedAt time.Time  `db:"last_used_at" json:"lastUsedAt"`
+	RevokedAt  *time.Time `db:"revoked_at" json:"revokedAt"`
+	CreatedAt  time.Time  `db:"created_at" json:"createdAt"`
+}
+
+type LoginChallengeEntity struct {
+	Id        int        `db:"id" json:"id"`
+	UserId    int        `db:"user_id" json:"userId"`
+	Username  string     `db:"username" json:"username"`
+	Purpose   string     `db:"purpose" json:"purpose"`
+	Attempts  int        `db:"attempts" json:"attempts"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expiresAt"`
+	UsedAt    *time.Time `db:"used_at" json:"usedAt"`
+}
+
+type InvitationEntity struct {
+	Id         int       `db:"id" json:"id"`
+	Email      string    `db:"email" json:"email"`
+	MerchantId string    `db:"merchant_id" json:"merchantId"`
+	RoleId     int       `db:"role_id" json:"roleId"`
+	RoleName   string    `db:"role_name" json:"roleName"`
+	InvitedBy  string    `db:"invited_by" json:"invitedBy"`
+	Status     string    `db:"status" json:"status"`
+	ExpiresAt  time.Time `db:"expires_at" json:"expiresAt"`
+	CreatedAt  time.Time `db:"created_at" json:"createdAt"`
+}
+
+type CredentialResetEntity struct {
+	Id         int    `db:"id" json:"id"`
+	UserId     int    `db:"user_id" json:"userId"`
+	Username   string `db:"username" json:"username"`
+	Credential string `db:"credential" json:"credential"`
+}
+
+type AuthAttemptEntity struct {
+	Scope       string `db:"scope" json:"scope"`
+	Subject     string `db:"subject" json:"subject"`
+	Credential  string `db:"credential" json:"credential"`
+	FailedCount int    `db:"failed_count" json:"failedCount"`
+	// WaitSeconds and LockedSeconds are what is left of the delay and the lockout, zero once they passed
+	WaitSeconds   int `db:"wait_seconds" json:"waitSeconds"`
+	LockedSeconds int `db:"locked_seconds" json:"lockedSeconds"`
+}
@@ -177,15 +177,6 @@ func ToArrayOfString(v interface{}) []string {
 	return result
 }
 
-func FromStringToIntAmount(val string) int {
-	var result int
-	str := strings.TrimSpace(val)
-	num := strings.Split(str, ".")
-	result, _ = strconv.Atoi(num[0])
-
-	return result
-}
-
 func FormattedCompletionRate(averageDuration time.Duration) string {
 	return fmt.Sprintf("%02d:%02d:%02d", int(averageDuration.Hours()), int(averageDuration.Minutes())%60, int(averageDuration.Seconds())%60)
 }
@@ -0,0 +1,202 @@
+// Package envelope encrypts secrets stored in the database. Every value gets its own data key, the data key
+// is encrypted with a key-encryption key (KEK) from config and stored next to the value:
+//
+//	enc:v2:<kek id>:<base64 encrypted data key>:<base64 encrypted value>
+//
+// Both layers use AES-256-GCM with the column and row the value is stored in as additional data, a sealed
+// value copied into another row doesn't open. Rotating the KEK only re-encrypts the data keys. Values written
+// before encryption existed are read as they are, and enc:v1 values sealed without additional data are
+// still opened, until the rotation command seals them again.
+package envelope
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	prefix = "enc:v2:"
+	// legacyPrefix values were sealed without additional data
+	legacyPrefix = "enc:v1:"
+	keySize      = 32
+)
+
+var ErrUnknownKey = errors.New("envelope: value is sealed with an unknown key")
+
+var ErrMalformed = errors.New("envelope: malformed sealed value")
+
+// Keyring holds every KEK that may still be found in the database and seals with the active one
+type Keyring struct {
+	keys     map[string][]byte
+	activeId string
+}
+
+// ParseKeyring reads keys written as "id:base64key,id:base64key", every key is 32 bytes. activeId names the
+// key new values are sealed with, the others are only used to open values sealed before a rotation.
+func ParseKeyring(keys string, activeId string) (*Keyring, error) {
+	keyring := &Keyring{
+		keys:     map[string][]byte{},
+		activeId: activeId,
+	}
+
+	for _, entry := range strings.Split(keys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, encoded, found := strings.Cut(entry, ":")
+		if !found || id == "" {
+			return nil, fmt.Errorf("envelope: key entry %q is not id:base64key", entry)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("envelope: key %v is not base64: %w", id, err)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("envelope: key %v has %v bytes, want %v", id, len(key), keySize)
+		}
+
+		keyring.keys[id] = key
+	}
+
+	if _, ok := keyring.keys[activeId]; !ok {
+		return nil, fmt.Errorf("envelope: active key %q is not in the keyring", activeId)
+	}
+
+	return keyring, nil
+}
+
+// AdditionalData names the column and the row a sealed value is stored in, the same name has to be given to
+// open it again. Every row key is quoted so keys holding the separator can't name another row.
+func AdditionalData(column string, rowKey ...interface{}) string {
+	keys := make([]string, len(rowKey))
+	for i, key := range rowKey {
+		keys[i] = strconv.Quote(fmt.Sprint(key))
+	}
+
+	return column + ":" + strings.Join(keys, "/")
+}
+
+// Seal encrypts plaintext under a new data key wrapped with the active KEK, additionalData is built with
+// AdditionalData
+func (k *Keyring) Seal(plaintext string, additionalData string) (string, error) {
+	dataKey := make([]byte, keySize)
+	_, err := rand.Read(dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	wrappedKey, err := encrypt(k.keys[k.activeId], dataKey, []byte(additionalData))
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := encrypt(dataKey, []byte(plaintext), []byte(additionalData))
+	if err != nil {
+		return "", err
+	}
+
+	return prefix + k.activeId + ":" + base64.StdEncoding.EncodeToString(wrappedKey) + ":" + base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+// Open decrypts a value sealed with any KEK of the keyring for additionalData, values that were never sealed
+// come back as they are
+func (k *Keyring) Open(value string, additionalData string) (string, error) {
+	if !IsSealed(value) {
+		return value, nil
+	}
+
+	sealed, aad := strings.TrimPrefix(value, prefix), []byte(additionalData)
+	if strings.HasPrefix(value, legacyPrefix) {
+		sealed, aad = strings.TrimPrefix(value, legacyPrefix), nil
+	}
+
+	parts := strings.Split(sealed, ":")
+	if len(parts) != 3 {
+		return "", ErrMalformed
+	}
+
+	kek, ok := k.keys[parts[0]]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrMalformed
+	}
+
+	dataKey, err := decrypt(kek, wrappedKey, aad)
+	if err != nil {
+		return "", err
+	}
+
+	plaintext, err := decrypt(dataKey, ciphertext, aad)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsRotation tells whether value is plaintext, sealed without additional data or sealed with a KEK other
+// than the active one
+func (k *Keyring) NeedsRotation(value string) bool {
+	return !strings.HasPrefix(value, prefix+k.activeId+":")
+}
+
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, prefix) || strings.HasPrefix(value, legacyPrefix)
+}
+
+// encrypt returns the nonce followed by the sealed data
+func encrypt(key []byte, plaintext []byte, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func decrypt(key []byte, sealed []byte, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMalformed
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,228 @@
+package envelope
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, keySize))
+}
+
+func testKeyring(t *testing.T, keys string, activeId string) *Keyring {
+	t.Helper()
+
+	keyring, err := ParseKeyring(keys, activeId)
+	if err != nil {
+		t.Fatalf("ParseKeyring: %v", err)
+	}
+
+	return keyring
+}
+
+// legacySeal writes a value the way enc:v1 did, without additional data
+func legacySeal(t *testing.T, keyring *Keyring, plaintext string) string {
+	t.Helper()
+
+	dataKey := bytes.Repeat([]byte{9}, keySize)
+	wrappedKey, err := encrypt(keyring.keys[keyring.activeId], dataKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := encrypt(dataKey, []byte(plaintext), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return legacyPrefix + keyring.activeId + ":" + base64.StdEncoding.EncodeToString(wrappedKey) + ":" + base64.StdEncoding.EncodeToString(ciphertext)
+}
+
+func TestParseKeyring(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     string
+		activeId string
+		wantErr  bool
+	}{
+		{"one key", "k1:" + testKey(1), "k1", false},
+		{"rotated keys with spaces", " k1:" + testKey(1) + " , k2:" + testKey(2) + ",", "k2", false},
+		{"active key missing", "k1:" + testKey(1), "k2", true},
+		{"entry without id", ":" + testKey(1), "k1", true},
+		{"entry without key", "k1", "k1", true},
+		{"key not base64", "k1:not base64!", "k1", true},
+		{"short key", "k1:" + base64.StdEncoding.EncodeToString([]byte("short")), "k1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseKeyring(tt.keys, tt.activeId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseKeyring err = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	keyring := testKeyring(t, "k1:"+testKey(1), "k1")
+	data := AdditionalData("merchant_keys.secret", "M001", 2)
+
+	for _, plaintext := range []string{"secret_key-abc", "", "with:colons:inside"} {
+		sealed, err := keyring.Seal(plaintext, data)
+		if err != nil {
+			t.Fatalf("Seal(%q): %v", plaintext, err)
+		}
+		if !strings.HasPrefix(sealed, "enc:v2:k1:") {
+			t.Errorf("Seal(%q) = %v, want an enc:v2 value of k1", plaintext, sealed)
+		}
+
+		opened, err := keyring.Open(sealed, data)
+		if err != nil || opened != plaintext {
+			t.Errorf("Open(Seal(%q)) = %q, %v", plaintext, opened, err)
+		}
+	}
+
+	first, _ := keyring.Seal("same", data)
+	second, _ := keyring.Seal("same", data)
+	if first == second {
+		t.Error("sealing the same plaintext twice gives the same value")
+	}
+}
+
+func TestOpenWithOtherAdditionalData(t *testing.T) {
+	keyring := testKeyring(t, "k1:"+testKey(1), "k1")
+
+	sealed, err := keyring.Seal("secret", AdditionalData("merchant_keys.secret", "M001", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the value copied into another row or column
+	for _, data := range []string{
+		AdditionalData("merchant_keys.secret", "M002", 1),
+		AdditionalData("merchant_keys.secret", "M001", 2),
+		AdditionalData("provider_credentials.value", "M001", 1),
+		"",
+	} {
+		if _, err := keyring.Open(sealed, data); err == nil {
+			t.Errorf("Open with %q succeeded, want an error", data)
+		}
+	}
+}
+
+func TestOpenRotated(t *testing.T) {
+	data := AdditionalData("provider_credentials.value", "P1", "callback_secret")
+
+	before := testKeyring(t, "k1:"+testKey(1), "k1")
+	sealed, err := before.Seal("secret", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	after := testKeyring(t, "k1:"+testKey(1)+",k2:"+testKey(2), "k2")
+	if !after.NeedsRotation(sealed) {
+		t.Error("value sealed with k1 doesn't need rotation once k2 is active")
+	}
+
+	opened, err := after.Open(sealed, data)
+	if err != nil || opened != "secret" {
+		t.Fatalf("Open with the old key still in the keyring = %q, %v", opened, err)
+	}
+
+	resealed, err := after.Seal(opened, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.NeedsRotation(resealed) {
+		t.Error("value sealed with the active key needs rotation")
+	}
+
+	// once the old key is dropped its values can't be opened anymore
+	withoutOld := testKeyring(t, "k2:"+testKey(2), "k2")
+	if _, err := withoutOld.Open(sealed, data); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Open after dropping k1 err = %v, want ErrUnknownKey", err)
+	}
+	if opened, err := withoutOld.Open(resealed, data); err != nil || opened != "secret" {
+		t.Errorf("Open of the resealed value = %q, %v", opened, err)
+	}
+}
+
+func TestOpenLegacy(t *testing.T) {
+	keyring := testKeyring(t, "k1:"+testKey(1), "k1")
+	legacy := legacySeal(t, keyring, "secret")
+
+	opened, err := keyring.Open(legacy, AdditionalData("merchant_keys.secret", "M001", 1))
+	if err != nil || opened != "secret" {
+		t.Errorf("Open of an enc:v1 value = %q, %v", opened, err)
+	}
+	if !keyring.NeedsRotation(legacy) {
+		t.Error("enc:v1 value doesn't need rotation")
+	}
+}
+
+func TestOpenPlaintext(t *testing.T) {
+	keyring := testKeyring(t, "k1:"+testKey(1), "k1")
+
+	for _, value := range []string{"", "secret_key-plain", "enc:v3:k1:a:b", "ENC:V2:k1:a:b"} {
+		opened, err := keyring.Open(value, "")
+		if err != nil || opened != value {
+			t.Errorf("Open(%q) = %q, %v, want the value as is", value, opened, err)
+		}
+		if !keyring.NeedsRotation(value) {
+			t.Errorf("plaintext %q doesn't need rotation", value)
+		}
+	}
+}
+
+func TestOpenMalformed(t *testing.T) {
+	keyring := testKeyring(t, "k1:"+testKey(1), "k1")
+	data := AdditionalData("merchant_keys.secret", "M001", 1)
+
+	sealed, err := keyring.Seal("secret", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(strings.TrimPrefix(sealed, prefix), ":")
+	wrappedKey, ciphertext := parts[1], parts[2]
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr error
+	}{
+		{"prefix only", prefix, ErrMalformed},
+		{"missing value", prefix + "k1:" + wrappedKey, ErrMalformed},
+		{"extra part", sealed + ":extra", ErrMalformed},
+		{"unknown key", prefix + "k9:" + wrappedKey + ":" + ciphertext, ErrUnknownKey},
+		{"data key not base64", prefix + "k1:!!:" + ciphertext, ErrMalformed},
+		{"value not base64", prefix + "k1:" + wrappedKey + ":!!", ErrMalformed},
+		{"data key shorter than a nonce", prefix + "k1:" + base64.StdEncoding.EncodeToString([]byte("short")) + ":" + ciphertext, ErrMalformed},
+		{"value shorter than a nonce", prefix + "k1:" + wrappedKey + ":" + base64.StdEncoding.EncodeToString([]byte("short")), ErrMalformed},
+		{"truncated value", sealed[:len(sealed)-8], nil},
+		{"legacy prefix only", legacyPrefix, ErrMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opened, err := keyring.Open(tt.value, data)
+			if err == nil {
+				t.Fatalf("Open = %q, want an error", opened)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Open err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAdditionalData(t *testing.T) {
+	if got := AdditionalData("merchant_keys.secret", "M001", 2); got != `merchant_keys.secret:"M001"/"2"` {
+		t.Errorf("AdditionalData = %v", got)
+	}
+	if AdditionalData("provider_credentials.value", "P1/x") == AdditionalData("provider_credentials.value", "P1", "x") {
+		t.Error("a row key holding the separator names another row")
+	}
+}
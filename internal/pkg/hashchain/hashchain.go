@@ -0,0 +1,38 @@
+// Package hashchain recomputes the hashes the database stores on chained rows, see
+// 000017_hash_chain.up.sql. A row hash is the hex SHA-256 of the hash of the previous row followed by the
+// fields of the row, every value encoded as
+//
+//	<byte length>:<value>
+//
+// and a NULL as "-", so the content of one field can never be read as part of the next one.
+package hashchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// Hash returns the hash of a row linked to prevHash, nil values are NULL
+func Hash(prevHash *string, fields ...*string) string {
+	var content strings.Builder
+	writeField(&content, prevHash)
+	for _, field := range fields {
+		writeField(&content, field)
+	}
+
+	sum := sha256.Sum256([]byte(content.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func writeField(content *strings.Builder, value *string) {
+	if value == nil {
+		content.WriteString("-")
+		return
+	}
+
+	content.WriteString(strconv.Itoa(len(*value)))
+	content.WriteString(":")
+	content.WriteString(*value)
+}
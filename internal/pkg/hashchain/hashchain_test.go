@@ -0,0 +1,63 @@
+package hashchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func str(value string) *string {
+	return &value
+}
+
+// TestHashEncoding pins the encoding to chain_field of 000017_hash_chain.up.sql, every value is its
+// octet_length, a colon and the value, NULL is a dash
+func TestHashEncoding(t *testing.T) {
+	prev := "0f200a92f91ea84f7adefa3dd82db355d196b34f6e271b6c6ef3bb288d58f0d5"
+
+	tests := []struct {
+		name    string
+		prev    *string
+		fields  []*string
+		encoded string
+	}{
+		{"first link without fields", nil, nil, "-"},
+		{"values and nulls", nil, []*string{str("abc"), nil, str("Jörg"), str(""), str("10")}, "-3:abc-5:Jörg0:2:10"},
+		{"linked to a previous hash", &prev, []*string{str("7")}, "64:" + prev + "1:7"},
+		{"separator inside a value", nil, []*string{str("1:a"), str("-")}, "-3:1:a1:-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sum := sha256.Sum256([]byte(tt.encoded))
+			want := hex.EncodeToString(sum[:])
+
+			if got := Hash(tt.prev, tt.fields...); got != want {
+				t.Errorf("Hash = %v, want the hash of %q %v", got, tt.encoded, want)
+			}
+		})
+	}
+}
+
+func TestHashKnownValue(t *testing.T) {
+	// sha256 of -3:abc-5:Jörg0:2:10, the same value encode(sha256(convert_to(..., 'UTF8')), 'hex') returns
+	want := "0f200a92f91ea84f7adefa3dd82db355d196b34f6e271b6c6ef3bb288d58f0d5"
+
+	if got := Hash(nil, str("abc"), nil, str("Jörg"), str(""), str("10")); got != want {
+		t.Errorf("Hash = %v, want %v", got, want)
+	}
+}
+
+func TestHashFieldBoundaries(t *testing.T) {
+	// without the length prefix these would hash the same content
+	a := Hash(nil, str("ab"), str("c"))
+	b := Hash(nil, str("a"), str("bc"))
+	if a == b {
+		t.Error("fields split at a different place hash the same")
+	}
+
+	// an empty value is not a NULL
+	if Hash(nil, str("")) == Hash(nil, nil) {
+		t.Error("empty value and NULL hash the same")
+	}
+}
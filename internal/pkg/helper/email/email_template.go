@@ -1,6 +1,6 @@
 package email
 
-const EmailTemplate = `
+const InvitationEmailTemplate = `
 <!DOCTYPE html>
 <html lang="en">
 <head>
@@ -24,14 +24,90 @@ const EmailTemplate = `
         </div>
         <div class="content">
             <p>Dear Merchant,</p>
-            <p>You are now registered with Hypay. Here are your credentials to access our dashboard:</p>
+            <p>You are invited to the Hypay merchant dashboard as {{.Email}}. Open the link below to choose your password and pin:</p>
             <div class="info">
-                <p><strong>Username:</strong> {{.Username}}</p>
-                <p><strong>Password:</strong> {{.Password}}</p>
-                <p><strong>Pin:</strong> {{.Pin}}</p>
+                <p><a href="{{.InviteLink}}">{{.InviteLink}}</a></p>
             </div>
-            <p>Please ensure that you keep this information secure at all times.</p>
-            <p>Please change all credentials like password and pin on your manage profile menu on our merchant dashboard.</p>
+            <p>The invitation expires in {{.ExpiresDays}} days. Your username is your email address.</p>
+            <p>If you weren't expecting this invitation, you can ignore this email.</p>
+        </div>
+        <div class="footer">
+            © Hypay Indonesia. All rights reserved.
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const LockoutEmailTemplate = `
+<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Hypay Account Locked</title>
+    <style>
+        body { font-family: Arial, sans-serif; background-color: #f4f4f4; color: #333; }
+        .email-container { width: 100%; max-width: 600px; background-color: #fff; margin: 20px auto; padding: 20px; box-shadow: 0 0 10px rgba(0,0,0,0.1); }
+        .header { background-color: #8A2BE2; color: #ffffff; padding: 20px; text-align: center; border-radius: 10px 10px; }
+        .content { padding: 20px; text-align: left; line-height: 1.5; }
+        .footer { text-align: center; padding: 10px 20px; font-size: 12px; color: #999; }
+        .info { background-color: #eee; padding: 10px; margin: 10px 0; border-radius: 10px 10px; }
+    </style>
+</head>
+<body>
+    <div class="email-container">
+        <div class="header">
+            <img src="https://res.cloudinary.com/ddtewkcqc/image/upload/v1723206638/lwi2ojljn7ozab8l0siq.png" alt="Hypay Logo" style="max-width: 100px;">
+            <h1>Your account is locked</h1>
+        </div>
+        <div class="content">
+            <p>Dear {{.Username}},</p>
+            <p>We locked your Hypay dashboard account after too many wrong attempts:</p>
+            <div class="info">
+                <p><strong>Credential:</strong> {{.Credential}}</p>
+                <p><strong>Locked for:</strong> {{.LockedMinutes}} minutes</p>
+            </div>
+            <p>If this wasn't you, please change your password and pin once the lock ends and contact our support team.</p>
+        </div>
+        <div class="footer">
+            © Hypay Indonesia. All rights reserved.
+        </div>
+    </div>
+</body>
+</html>
+`
+
+const ResetCredentialEmailTemplate = `
+<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Reset your Hypay {{.Credential}}</title>
+    <style>
+        body { font-family: Arial, sans-serif; background-color: #f4f4f4; color: #333; }
+        .email-container { width: 100%; max-width: 600px; background-color: #fff; margin: 20px auto; padding: 20px; box-shadow: 0 0 10px rgba(0,0,0,0.1); }
+        .header { background-color: #8A2BE2; color: #ffffff; padding: 20px; text-align: center; border-radius: 10px 10px; }
+        .content { padding: 20px; text-align: left; line-height: 1.5; }
+        .footer { text-align: center; padding: 10px 20px; font-size: 12px; color: #999; }
+        .info { background-color: #eee; padding: 10px; margin: 10px 0; border-radius: 10px 10px; }
+    </style>
+</head>
+<body>
+    <div class="email-container">
+        <div class="header">
+            <img src="https://res.cloudinary.com/ddtewkcqc/image/upload/v1723206638/lwi2ojljn7ozab8l0siq.png" alt="Hypay Logo" style="max-width: 100px;">
+            <h1>Reset your {{.Credential}}</h1>
+        </div>
+        <div class="content">
+            <p>Dear {{.Username}},</p>
+            <p>We received a request to reset the {{.Credential}} of your Hypay dashboard account. Open the link below to set a new one:</p>
+            <div class="info">
+                <p><a href="{{.ResetLink}}">{{.ResetLink}}</a></p>
+            </div>
+            <p>The link works once and expires in {{.ExpiresMinutes}} minutes. Resetting will log you out of every device.</p>
+            <p>If you didn't ask for this, you can ignore this email, your {{.Credential}} stays the same.</p>
         </div>
         <div class="footer">
             © Hypay Indonesia. All rights reserved.
@@ -10,7 +10,6 @@ import (
 	"fmt"
 	"math/rand"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 	"unicode"
@@ -137,23 +136,6 @@ func GetInputNumber(phoneNumber string) string {
 	return prefix
 }
 
-// Check and add .00 if necessary, then change back to float64
-func FormatFloat64(num float64) float64 {
-	// Check whether num has a decimal part or not
-	if num == float64(int(num)) {
-		// Add .00
-		formattedStr := strconv.FormatFloat(num, 'f', 2, 64)
-		// Change back to float64
-		formattedFloat, err := strconv.ParseFloat(formattedStr, 64)
-		if err != nil {
-			fmt.Println("Error converting string to float64:", err)
-			return num
-		}
-		return formattedFloat
-	}
-	return num
-}
-
 func SplitString(input string) []string {
 	// Remove the leading and trailing brackets
 	input = strings.TrimPrefix(input, "[")
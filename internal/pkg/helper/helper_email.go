@@ -10,8 +10,20 @@ import (
 	"gopkg.in/gomail.v2"
 )
 
-func SendEmailInviteUser(payload dto.EmailDataHtmlDto, recipientEmail string, configAppPass string) error {
-	tmpl, err := template.New("emailTemplate").Parse(email.EmailTemplate)
+func SendEmailInvitation(payload dto.InvitationEmailDataDto, recipientEmail string, configAppPass string) error {
+	return sendEmail(email.InvitationEmailTemplate, payload, "Welcome to Hypay!", recipientEmail, configAppPass)
+}
+
+func SendEmailLockout(payload dto.LockoutEmailDataDto, recipientEmail string, configAppPass string) error {
+	return sendEmail(email.LockoutEmailTemplate, payload, "Your Hypay account is locked", recipientEmail, configAppPass)
+}
+
+func SendEmailResetCredential(payload dto.ResetCredentialEmailDataDto, recipientEmail string, configAppPass string) error {
+	return sendEmail(email.ResetCredentialEmailTemplate, payload, "Reset your Hypay "+payload.Credential, recipientEmail, configAppPass)
+}
+
+func sendEmail(htmlTemplate string, payload interface{}, subject string, recipientEmail string, configAppPass string) error {
+	tmpl, err := template.New("emailTemplate").Parse(htmlTemplate)
 	if err != nil {
 		return err
 	}
@@ -24,7 +36,7 @@ func SendEmailInviteUser(payload dto.EmailDataHtmlDto, recipientEmail string, co
 	m := gomail.NewMessage()
 	m.SetHeader("From", constant.BusinessHypayEmail)
 	m.SetHeader("To", recipientEmail)
-	m.SetHeader("Subject", "Welcome to Hypay!")
+	m.SetHeader("Subject", subject)
 	m.SetBody("text/html", body.String())
 
 	d := gomail.NewDialer("smtp.gmail.com", 587, constant.BusinessHypayEmail, configAppPass)
@@ -1,17 +1,40 @@
 package helper
 
 import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"path/filepath"
+	"strings"
+
 	"github.com/xuri/excelize/v2"
 )
 
 func CreateExcelFile(headers []string, fileName string, list [][]interface{}) error {
+	f := newExcelFile(headers, list)
+
+	if err := f.SaveAs(fileName); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateExcelBuffer is CreateExcelFile kept in memory, for files sent back in the response
+func CreateExcelBuffer(headers []string, list [][]interface{}) (*bytes.Buffer, error) {
+	f := newExcelFile(headers, list)
+
+	return f.WriteToBuffer()
+}
+
+func newExcelFile(headers []string, list [][]interface{}) *excelize.File {
 	f := excelize.NewFile()
 	sheet := "Sheet1"
 	f.NewSheet(sheet)
 
 	// Menulis header
 	for col, header := range headers {
-		cell := string('A'+col) + "1"
+		cell, _ := excelize.CoordinatesToCellName(col+1, 1)
 		f.SetCellValue("Sheet1", cell, header)
 	}
 
@@ -24,9 +47,34 @@ func CreateExcelFile(headers []string, fileName string, list [][]interface{}) er
 		}
 	}
 
-	if err := f.SaveAs(fileName); err != nil {
-		return err
+	return f
+}
+
+// ReadSpreadsheetRows returns the rows of the first sheet of a xlsx file or of a csv file, told apart by the
+// extension of fileName. Cells of a xlsx file are read as stored instead of as formatted, so numbers come without
+// thousand separators. Trailing empty cells may be left out.
+func ReadSpreadsheetRows(fileName string, content []byte) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".xlsx":
+		f, err := excelize.OpenReader(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("file has no sheet")
+		}
+
+		return f.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+	case ".csv":
+		reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+
+		return reader.ReadAll()
 	}
 
-	return nil
+	return nil, errors.New("only xlsx and csv files are supported")
 }
@@ -0,0 +1,68 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadSpreadsheetRowsCsv(t *testing.T) {
+	content := []byte("\xef\xbb\xbfBank Code,Bank Account Number,Bank Account Name,Amount,Note\n" +
+		"BCA, 1234567890,\"Budi, S\",150000\n" +
+		"\n" +
+		"BRI,0987654321,Ani,200000.50,gaji\n")
+
+	rows, err := ReadSpreadsheetRows("batch.CSV", content)
+	if err != nil {
+		t.Fatalf("ReadSpreadsheetRows got err %v", err)
+	}
+
+	// the byte order mark is dropped, rows keep their own number of cells and the blank line is skipped
+	want := [][]string{
+		{"Bank Code", "Bank Account Number", "Bank Account Name", "Amount", "Note"},
+		{"BCA", "1234567890", "Budi, S", "150000"},
+		{"BRI", "0987654321", "Ani", "200000.50", "gaji"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %q, want %q", rows, want)
+	}
+}
+
+func TestReadSpreadsheetRowsXlsx(t *testing.T) {
+	buffer, err := CreateExcelBuffer(
+		[]string{"Bank Code", "Bank Account Number", "Bank Account Name", "Amount", "Note"},
+		[][]interface{}{
+			{"BCA", "0012345678", "Budi", 1500000, "gaji"},
+			{"BRI", "0987654321", "Ani", 200000.5},
+		},
+	)
+	if err != nil {
+		t.Fatalf("CreateExcelBuffer got err %v", err)
+	}
+
+	rows, err := ReadSpreadsheetRows("batch.xlsx", buffer.Bytes())
+	if err != nil {
+		t.Fatalf("ReadSpreadsheetRows got err %v", err)
+	}
+
+	// numbers come as stored, without thousand separators, and text keeps its leading zeros
+	want := [][]string{
+		{"Bank Code", "Bank Account Number", "Bank Account Name", "Amount", "Note"},
+		{"BCA", "0012345678", "Budi", "1500000", "gaji"},
+		{"BRI", "0987654321", "Ani", "200000.5"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %q, want %q", rows, want)
+	}
+}
+
+func TestReadSpreadsheetRowsUnsupported(t *testing.T) {
+	for _, fileName := range []string{"batch.xls", "batch.txt", "batch"} {
+		if _, err := ReadSpreadsheetRows(fileName, []byte("Bank Code\n")); err == nil {
+			t.Errorf("ReadSpreadsheetRows(%v) got no err", fileName)
+		}
+	}
+
+	if _, err := ReadSpreadsheetRows("batch.xlsx", []byte("not a zip")); err == nil {
+		t.Error("ReadSpreadsheetRows of a broken xlsx got no err")
+	}
+}
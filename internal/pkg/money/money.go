@@ -0,0 +1,311 @@
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used for every amount that doesn't carry its own currency
+const DefaultCurrency = "IDR"
+
+// minorUnits is the number of minor units in one major unit, matches DECIMAL(18,2)
+const minorUnits = 100
+
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds half away from zero
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds half to the nearest even number
+	RoundHalfEven
+	// RoundUp rounds away from zero
+	RoundUp
+	// RoundDown rounds toward zero
+	RoundDown
+)
+
+var ErrCurrencyMismatch = errors.New("money currency mismatch")
+
+// Money is an exact amount stored as integer minor units plus a currency code
+type Money struct {
+	amount   int64
+	currency string
+}
+
+// New creates money from minor units
+func New(minor int64, currency string) Money {
+	return Money{amount: minor, currency: currency}
+}
+
+// FromMinor creates money from minor units in the default currency
+func FromMinor(minor int64) Money {
+	return New(minor, DefaultCurrency)
+}
+
+// FromInt creates money from whole major units in the default currency
+func FromInt(major int64) Money {
+	return FromMinor(major * minorUnits)
+}
+
+// FromFloat creates money from a float, only for values that already left the exact world
+func FromFloat(value float64) Money {
+	return FromMinor(int64(math.Round(value * minorUnits)))
+}
+
+// decimalPattern is a plain decimal with at most the two fraction digits of DECIMAL(18,2)
+var decimalPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]{1,2})?$`)
+
+// Parse reads a plain decimal string like "10000", "10000.5" or "-10000.50", exponents, fractions and more than
+// two fraction digits are rejected instead of rounded
+func Parse(value string) (Money, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Money{}, errors.New("money: empty amount")
+	}
+
+	if !decimalPattern.MatchString(value) {
+		return Money{}, fmt.Errorf("money: invalid amount %q", value)
+	}
+
+	negative := strings.HasPrefix(value, "-")
+	whole, fraction, _ := strings.Cut(strings.TrimPrefix(value, "-"), ".")
+	fraction += strings.Repeat("0", 2-len(fraction))
+
+	minor, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("money: amount %q out of range", value)
+	}
+
+	if negative {
+		minor = -minor
+	}
+
+	return FromMinor(minor), nil
+}
+
+// MustParse is Parse for constants, panics on invalid input
+func MustParse(value string) Money {
+	m, err := Parse(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// Minor returns the amount in minor units
+func (m Money) Minor() int64 {
+	return m.amount
+}
+
+// Currency returns the currency code, zero value money is in the default currency
+func (m Money) Currency() string {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+
+	return m.currency
+}
+
+// Float64 returns an approximation of the amount, only for presentation like excel cells
+func (m Money) Float64() float64 {
+	return float64(m.amount) / minorUnits
+}
+
+// Int64 returns the whole major units, the fraction is truncated
+func (m Money) Int64() int64 {
+	return m.amount / minorUnits
+}
+
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.amount > 0
+}
+
+func (m Money) Add(other Money) Money {
+	m.assertSameCurrency(other)
+	return New(m.amount+other.amount, m.Currency())
+}
+
+func (m Money) Sub(other Money) Money {
+	m.assertSameCurrency(other)
+	return New(m.amount-other.amount, m.Currency())
+}
+
+func (m Money) Neg() Money {
+	return New(-m.amount, m.Currency())
+}
+
+func (m Money) Abs() Money {
+	if m.amount < 0 {
+		return m.Neg()
+	}
+
+	return m
+}
+
+// Mul multiplies the amount by a whole number
+func (m Money) Mul(times int64) Money {
+	return New(m.amount*times, m.Currency())
+}
+
+// Cmp returns -1, 0 or 1 when m is less than, equal to or greater than other
+func (m Money) Cmp(other Money) int {
+	m.assertSameCurrency(other)
+	switch {
+	case m.amount < other.amount:
+		return -1
+	case m.amount > other.amount:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (m Money) Equal(other Money) bool {
+	return m.Cmp(other) == 0
+}
+
+func (m Money) LessThan(other Money) bool {
+	return m.Cmp(other) < 0
+}
+
+func (m Money) GreaterThan(other Money) bool {
+	return m.Cmp(other) > 0
+}
+
+// Percent returns rate percent of m rounded to a whole major unit with mode,
+// rate is a money value so a configured fee of 1.5 means 1.5 percent
+func (m Money) Percent(rate Money, mode RoundingMode) Money {
+	num := new(big.Int).Mul(big.NewInt(m.amount), big.NewInt(rate.amount))
+	// minor * minor rate / (100 percent * 100 minor rate) gives minor units,
+	// dividing by another 100 rounds on whole major units
+	den := big.NewInt(100 * minorUnits * minorUnits)
+	major := divRound(num, den, mode)
+
+	return New(major.Int64()*minorUnits, m.Currency())
+}
+
+// Round rounds m to a whole major unit with mode
+func (m Money) Round(mode RoundingMode) Money {
+	major := divRound(big.NewInt(m.amount), big.NewInt(minorUnits), mode)
+	return New(major.Int64()*minorUnits, m.Currency())
+}
+
+// String formats the amount with two decimals, e.g. "-1250.50"
+func (m Money) String() string {
+	sign := ""
+	amount := m.amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, amount/minorUnits, amount%minorUnits)
+}
+
+// MarshalJSON writes money as a json number so existing clients keep working
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a json number, a quoted decimal string or null
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if value == "null" {
+		return nil
+	}
+
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	parsed, err := Parse(value)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for DECIMAL columns
+func (m *Money) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*m = Money{}
+	case []byte:
+		parsed, err := Parse(string(value))
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	case string:
+		parsed, err := Parse(value)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+	case int64:
+		*m = FromInt(value)
+	case float64:
+		*m = FromFloat(value)
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+
+	return nil
+}
+
+// Value implements driver.Valuer, the decimal string keeps postgres numeric exact
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (m Money) assertSameCurrency(other Money) {
+	if m.Currency() != other.Currency() {
+		panic(fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency(), other.Currency()))
+	}
+}
+
+// divRound divides num by a positive den and rounds the quotient with mode
+func divRound(num *big.Int, den *big.Int, mode RoundingMode) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	// away is the step that moves the truncated quotient away from zero
+	away := big.NewInt(int64(num.Sign()))
+	twiceRem := new(big.Int).Abs(rem)
+	twiceRem.Mul(twiceRem, big.NewInt(2))
+	half := twiceRem.Cmp(den)
+
+	switch mode {
+	case RoundUp:
+		quo.Add(quo, away)
+	case RoundHalfUp:
+		if half >= 0 {
+			quo.Add(quo, away)
+		}
+	case RoundHalfEven:
+		if half > 0 || (half == 0 && quo.Bit(0) == 1) {
+			quo.Add(quo, away)
+		}
+	}
+
+	return quo
+}
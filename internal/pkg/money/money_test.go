@@ -0,0 +1,271 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestDivRound(t *testing.T) {
+	tests := []struct {
+		name string
+		num  int64
+		den  int64
+		mode RoundingMode
+		want int64
+	}{
+		{"exact", 6, 3, RoundHalfUp, 2},
+		{"exact negative", -6, 3, RoundHalfEven, -2},
+		{"half up below half", 1, 3, RoundHalfUp, 0},
+		{"half up above half", 2, 3, RoundHalfUp, 1},
+		{"half up on half", 7, 2, RoundHalfUp, 4},
+		{"half up negative on half", -7, 2, RoundHalfUp, -4},
+		{"half up negative above half", -2, 3, RoundHalfUp, -1},
+		{"half even on half to even below", 5, 2, RoundHalfEven, 2},
+		{"half even on half to even above", 7, 2, RoundHalfEven, 4},
+		{"half even negative to even below", -5, 2, RoundHalfEven, -2},
+		{"half even negative to even above", -7, 2, RoundHalfEven, -4},
+		{"half even above half", 5, 3, RoundHalfEven, 2},
+		{"up", 1, 3, RoundUp, 1},
+		{"up negative", -1, 3, RoundUp, -1},
+		{"down", 5, 3, RoundDown, 1},
+		{"down negative", -5, 3, RoundDown, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := divRound(big.NewInt(tt.num), big.NewInt(tt.den), tt.mode)
+			if got.Int64() != tt.want {
+				t.Errorf("divRound(%v, %v, %v) = %v, want %v", tt.num, tt.den, tt.mode, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "10000", want: 1000000},
+		{value: "10000.5", want: 1000050},
+		{value: " 12.34 ", want: 1234},
+		{value: "-1250.50", want: -125050},
+		{value: "-0.05", want: -5},
+		{value: "007.10", want: 710},
+		{value: "92233720368547758.07", want: 9223372036854775807},
+		{value: "", wantErr: true},
+		{value: "abc", wantErr: true},
+		{value: "1,000", wantErr: true},
+		{value: "1e4", wantErr: true},
+		{value: "1.5E2", wantErr: true},
+		{value: "1/3", wantErr: true},
+		{value: "100.005", wantErr: true},
+		{value: "0.004", wantErr: true},
+		{value: "+10", wantErr: true},
+		{value: ".5", wantErr: true},
+		{value: "5.", wantErr: true},
+		{value: "- 5", wantErr: true},
+		{value: "92233720368547758.08", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := Parse(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Parse(%q) = %v, want error", tt.value, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Parse(%q) got err %v", tt.value, err)
+			}
+			if got.Minor() != tt.want {
+				t.Errorf("Parse(%q) = %v minor units, want %v", tt.value, got.Minor(), tt.want)
+			}
+			if got.Currency() != DefaultCurrency {
+				t.Errorf("Parse(%q) currency = %v, want %v", tt.value, got.Currency(), DefaultCurrency)
+			}
+		})
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount string
+		rate   string
+		mode   RoundingMode
+		want   string
+	}{
+		{"whole result", "10000", "1.5", RoundHalfUp, "150.00"},
+		{"fraction rounds to major unit", "333", "1", RoundHalfUp, "3.00"},
+		{"half up on half", "250", "1", RoundHalfUp, "3.00"},
+		{"half even on half", "250", "1", RoundHalfEven, "2.00"},
+		{"half even on odd half", "350", "1", RoundHalfEven, "4.00"},
+		{"up", "201", "1", RoundUp, "3.00"},
+		{"down", "299", "1", RoundDown, "2.00"},
+		{"minor units of amount count", "250.50", "1", RoundHalfUp, "3.00"},
+		{"negative half up", "-250", "1", RoundHalfUp, "-3.00"},
+		{"zero rate", "10000", "0", RoundHalfUp, "0.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MustParse(tt.amount).Percent(MustParse(tt.rate), tt.mode)
+			if got.String() != tt.want {
+				t.Errorf("%v percent of %v = %v, want %v", tt.rate, tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		amount string
+		mode   RoundingMode
+		want   string
+	}{
+		{"10.50", RoundHalfUp, "11.00"},
+		{"10.50", RoundHalfEven, "10.00"},
+		{"-10.50", RoundHalfUp, "-11.00"},
+		{"10.01", RoundUp, "11.00"},
+		{"10.99", RoundDown, "10.00"},
+	}
+
+	for _, tt := range tests {
+		got := MustParse(tt.amount).Round(tt.mode)
+		if got.String() != tt.want {
+			t.Errorf("Round(%v, %v) = %v, want %v", tt.amount, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		minor int64
+		want  string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{-5, "-0.05"},
+		{125050, "1250.50"},
+		{-125050, "-1250.50"},
+	}
+
+	for _, tt := range tests {
+		if got := FromMinor(tt.minor).String(); got != tt.want {
+			t.Errorf("FromMinor(%v).String() = %v, want %v", tt.minor, got, tt.want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a := MustParse("100.10")
+	b := MustParse("0.20")
+
+	if got := a.Add(b).String(); got != "100.30" {
+		t.Errorf("Add = %v, want 100.30", got)
+	}
+	if got := b.Sub(a).String(); got != "-99.90" {
+		t.Errorf("Sub = %v, want -99.90", got)
+	}
+	if got := b.Mul(3).String(); got != "0.60" {
+		t.Errorf("Mul = %v, want 0.60", got)
+	}
+	if got := b.Sub(a).Abs().String(); got != "99.90" {
+		t.Errorf("Abs = %v, want 99.90", got)
+	}
+	if got := a.Abs(); !got.Equal(a) {
+		t.Errorf("Abs = %v, want %v", got, a)
+	}
+	if !b.LessThan(a) || !a.GreaterThan(b) || a.Equal(b) {
+		t.Errorf("comparison of %v and %v is wrong", a, b)
+	}
+
+	// zero value money is in the default currency
+	var zero Money
+	if got := zero.Add(b); !got.Equal(b) {
+		t.Errorf("zero value Add = %v, want %v", got, b)
+	}
+}
+
+func TestCurrencyMismatch(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrCurrencyMismatch) {
+			t.Errorf("recovered %v, want %v", err, ErrCurrencyMismatch)
+		}
+	}()
+
+	FromInt(1).Add(New(100, "USD"))
+}
+
+func TestJSON(t *testing.T) {
+	var payload struct {
+		Amount  Money  `json:"amount"`
+		Quoted  Money  `json:"quoted"`
+		Missing Money  `json:"missing"`
+		Pointer *Money `json:"pointer"`
+	}
+
+	err := json.Unmarshal([]byte(`{"amount": 12.5, "quoted": "1000", "missing": null, "pointer": null}`), &payload)
+	if err != nil {
+		t.Fatalf("Unmarshal got err %v", err)
+	}
+
+	if payload.Amount.Minor() != 1250 || payload.Quoted.Minor() != 100000 || !payload.Missing.IsZero() || payload.Pointer != nil {
+		t.Errorf("Unmarshal = %+v", payload)
+	}
+
+	raw, err := json.Marshal(map[string]Money{"amount": MustParse("-0.5")})
+	if err != nil {
+		t.Fatalf("Marshal got err %v", err)
+	}
+	if string(raw) != `{"amount":-0.50}` {
+		t.Errorf("Marshal = %s", raw)
+	}
+
+	for _, invalid := range []string{`"ten"`, `1e3`, `12.345`} {
+		if err = json.Unmarshal([]byte(`{"amount": `+invalid+`}`), &payload); err == nil {
+			t.Errorf("Unmarshal of amount %v got no err", invalid)
+		}
+	}
+}
+
+func TestScanValue(t *testing.T) {
+	tests := []struct {
+		src  interface{}
+		want int64
+	}{
+		{[]byte("12.34"), 1234},
+		{"-0.01", -1},
+		{int64(5), 500},
+		{float64(0.1), 10},
+		{nil, 0},
+	}
+
+	for _, tt := range tests {
+		var m Money
+		if err := m.Scan(tt.src); err != nil {
+			t.Fatalf("Scan(%v) got err %v", tt.src, err)
+		}
+		if m.Minor() != tt.want {
+			t.Errorf("Scan(%v) = %v minor units, want %v", tt.src, m.Minor(), tt.want)
+		}
+	}
+
+	var m Money
+	if err := m.Scan(true); err == nil {
+		t.Error("Scan(true) got no err")
+	}
+
+	value, err := MustParse("1250.5").Value()
+	if err != nil || value != "1250.50" {
+		t.Errorf("Value = %v, %v, want 1250.50", value, err)
+	}
+}
@@ -0,0 +1,105 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 key of the RFC 6238 test vectors, 12345678901234567890 in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestGenerate(t *testing.T) {
+	// RFC 6238 appendix B, the last 6 of the 8 digits
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	key, _ := encoding.DecodeString(rfcSecret)
+	for _, tt := range tests {
+		if got := generate(key, tt.unix/period); got != tt.want {
+			t.Errorf("generate at %v = %v, want %v", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := now.Unix() / period
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		wantOk   bool
+	}{
+		{"current step", rfcSecret, "050471", step, true},
+		{"lowercase secret", strings.ToLower(rfcSecret), "050471", step, true},
+		{"previous step", rfcSecret, codeAt(step - 1), step - 1, true},
+		{"next step", rfcSecret, codeAt(step + 1), step + 1, true},
+		{"two steps ago", rfcSecret, codeAt(step - 2), 0, false},
+		{"two steps ahead", rfcSecret, codeAt(step + 2), 0, false},
+		{"wrong code", rfcSecret, "000000", 0, false},
+		{"short code", rfcSecret, "50471", 0, false},
+		{"invalid secret", "not base32!", "050471", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, gotOk := Validate(tt.secret, tt.code, now)
+			if gotStep != tt.wantStep || gotOk != tt.wantOk {
+				t.Errorf("Validate(%v) = %v, %v, want %v, %v", tt.code, gotStep, gotOk, tt.wantStep, tt.wantOk)
+			}
+		})
+	}
+}
+
+func codeAt(step int64) string {
+	key, _ := encoding.DecodeString(rfcSecret)
+	return generate(key, step)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret got err %v", err)
+	}
+
+	key, err := encoding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Errorf("GenerateSecret = %v, want 20 bytes of base32", secret)
+	}
+
+	other, _ := GenerateSecret()
+	if other == secret {
+		t.Error("GenerateSecret returned the same secret twice")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri := URI("Hypay", "budi@example.com", rfcSecret)
+
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		t.Fatalf("URI %v doesn't parse: %v", uri, err)
+	}
+
+	if parsed.Scheme != "otpauth" || parsed.Host != "totp" || parsed.Path != "/Hypay:budi@example.com" {
+		t.Errorf("URI = %v", uri)
+	}
+
+	query := parsed.Query()
+	if query.Get("secret") != rfcSecret || query.Get("issuer") != "Hypay" || query.Get("digits") != "6" || query.Get("period") != "30" || query.Get("algorithm") != "SHA1" {
+		t.Errorf("URI params = %v", query)
+	}
+}
@@ -1,10 +1,31 @@
 package internal
 
 import (
+	"time"
+
 	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
 )
 
+type UnitOfWorkItf interface {
+	WithinTransaction(fn func(repos UnitOfWorkRepos) error) error
+}
+
+// UnitOfWorkRepos holds write repositories bound to one database transaction
+type UnitOfWorkRepos struct {
+	TransactionsWrites         TransactionsWritesRepositoryItf
+	MerchantWrites             MerchantWritesRepositoryItf
+	ProviderWrites             ProviderWritesRepositoryItf
+	UserWrites                 UserWritesRepositoryItf
+	LedgerWrites               LedgerWritesRepositoryItf
+	AuditWrites                AuditWritesRepositoryItf
+	HashChainWrites            HashChainWritesRepositoryItf
+	BalanceApprovalWrites      BalanceApprovalWritesRepositoryItf
+	DisbursementApprovalWrites DisbursementApprovalWritesRepositoryItf
+	DisbursementBatchWrites    DisbursementBatchWritesRepositoryItf
+}
+
 type TransactionsReadsRepositoryItf interface {
 	GetTransactionList(params dto.QueryParams) ([]entity.Transaction, dto.PaginatedResponse, error)
 	GetPaymentDetailProviderMerchant(paymentId string) (entity.PaymentDetailMerchantProvider, error)
@@ -28,6 +49,9 @@ type TransactionsReadsRepositoryItf interface {
 
 type TransactionsWritesRepositoryItf interface {
 	UpdateStatus(status string, paymentId string) error
+	UpdateProviderReferenceNumberRepo(paymentId string, providerReferenceNumber string) error
+	UpdateAccountInformationReferenceNumberRepo(paymentId string, accountType string, referenceNumber string) error
+	GetTransactionStatusForUpdateRepo(paymentId string) (string, error)
 	CreateTransactionStatusLog(paymentId string, statusLog string, changeBy string, notes string, realNotes string) (int, error)
 	UpdateReportStoragesByFileName(publicUrl string, fileName string, status string) error
 	CreateListReportStoragesRepo(payload dto.CreateReportStorageDto) (int, error)
@@ -56,42 +80,97 @@ type MerchantReadsRepositoryItf interface {
 	GetAggregatedPaychannelByIdRepo(id int) (entity.AggregatedPaychannelEntity, error)
 	GetMerchantPaymentMethodByIdMerchantRepo(id int) ([]entity.PaymentMethods, []entity.PaymentMethods, error)
 	GetActiveAndAvailableChannelRepo(merchantPaychannelId int, paymentMethodName string) ([]entity.ProviderPaychannelEntity, []entity.ProviderPaychannelEntity, error)
-	GetSecretKeyByMerchantIdRepo(merchantId string) (entity.MerchantSecret, error)
+	GetLiveMerchantKeysRepo(merchantId string) ([]entity.MerchantKeyEntity, error)
 	GetBankListForDisbursementRepo(routedChannelName string) ([]entity.BankListDto, error)
 }
 
 type MerchantWritesRepositoryItf interface {
-	UpdateMerchantCapitalAndNotSettleBalance(notSettleBalance float64, balanceCapitalFlow float64, merchantId string) error
-	UpdateMerchantCapitalAndSettleBalance(settleBalance float64, balanceCapitalFlow float64, merchantId string) error
+	GetMerchantAccountForUpdateRepo(merchantId string) (entity.MerchantAccount, error)
+	UpdateMerchantCapitalAndNotSettleBalance(notSettleBalance money.Money, balanceCapitalFlow money.Money, merchantId string) error
+	UpdateMerchantCapitalAndSettleBalance(settleBalance money.Money, balanceCapitalFlow money.Money, merchantId string) error
 	CreateMerchantCapitalFlow(payload dto.CreateMerchantCapitalFlowPayload) (int, error)
-	UpdateMerchantHoldBalanceAndSettleBalance(settleBalance float64, holdBalance float64, merchantId string) error
-	UpdateMerchantHoldBalanceAndNotSettleBalance(notSettleBalance float64, holdBalance float64, merchantId string) error
-	UpdateMerchantSettlement(settleBalance float64, notSettleBalance float64, merchantId string) error
-	UpdateMerchantCapitalPendingOut(pendingAmount float64, balanceCapitalFlow float64, merchantId string) error
+	UpdateMerchantHoldBalanceAndSettleBalance(settleBalance money.Money, holdBalance money.Money, merchantId string) error
+	UpdateMerchantHoldBalanceAndNotSettleBalance(notSettleBalance money.Money, holdBalance money.Money, merchantId string) error
+	UpdateMerchantSettlement(settleBalance money.Money, notSettleBalance money.Money, merchantId string) error
+	UpdateMerchantCapitalPendingOut(pendingAmount money.Money, balanceCapitalFlow money.Money, merchantId string) error
 	CreateMerchantCallback(paymentId string, callbackStatus string, paymentStatusInCallback string, callbackResult string, triggerBy string) (int, error)
-	CreateMerchantRepo(merchantName string, merchantId string, merchantSecret string) (int, error)
+	CreateMerchantRepo(merchantName string, merchantId string) (int, error)
 	CreateMerchantPaymentMethodRepo(merchantId int, paymentMethodId int) (int, error)
-	CreateMerchantPaychannelRepo(merchantPaymentMethodId int, segment string, fee float64, feeType string, minAmount float64, maxAmount float64, dailyLimit float64, merchantPaychannelCode string) (int, error)
+	CreateMerchantPaychannelRepo(merchantPaymentMethodId int, segment string, fee money.Money, feeType string, minAmount money.Money, maxAmount money.Money, dailyLimit money.Money, merchantPaychannelCode string) (int, error)
 	CreateMerchantAccountsRepo(merchantId string) (int, error)
 	UpdateMerchantStatusRepo(merchantId string, status string) error
 	UpdateMerchantPaychannelByIdRepo(payload dto.AdjustLimitOrFeePayload) error
 	UpdateStatusMerchantPaychannelById(id int, status string) error
 	DeleteRoutingPaychannelByMerchantPaychannelId(id int) error
 	AddRoutingPaychannelRepo(merchantPaychannelId int, providerPaychannelId int) (int, error)
-	UpdateMerchantSecretKeyRepo(secretKey string, merchantId string) error
-	UpdateMerchantBalanceSettleAndPendingOutBalanceRepo(settleBalance float64, pendingOutBalance float64, merchantId string) error
+	UpdateMerchantBalanceSettleAndPendingOutBalanceRepo(settleBalance money.Money, pendingOutBalance money.Money, merchantId string) error
+	CreateMerchantCallbackDeliveryRepo(paymentId string, paymentStatus string) (int, error)
+	SupersedeMerchantCallbackDeliveriesRepo(paymentId string) error
+	ClaimDueMerchantCallbackDeliveriesRepo(limit int, lease time.Duration) ([]entity.MerchantCallbackDelivery, error)
+	UpdateMerchantCallbackDeliveryRepo(deliveryId int, deliveryStatus string, attemptCount int, nextRetryDelay time.Duration, lastResult string) error
+	MarkMerchantCallbackDeliveredRepo(paymentId string) error
+	CreateMerchantCallbackAttemptRepo(payload dto.CreateMerchantCallbackAttemptPayload) (int, error)
+	CreateMerchantKeyRepo(key entity.MerchantKeyEntity, activateIn time.Duration) (int, error)
+	GetMerchantKeysForUpdateRepo(merchantId string) ([]entity.MerchantKeyEntity, error)
+	ActivateMerchantKeyRepo(keyId int) error
+	RetireMerchantKeyRepo(keyId int, status string, retireIn time.Duration) error
+	GetDueMerchantKeysRepo() ([]entity.MerchantKeyEntity, error)
+	RetireExpiredMerchantKeysRepo() error
+	GetMerchantKeySecretsRepo() ([]entity.MerchantKeyEntity, error)
+	RotateMerchantKeySecretRepo(keyId int, oldSecret string, newSecret string) error
 }
 
 type UserReadsRepositoryItf interface {
 	GetUserByUsername(username string) (entity.User, error)
 	GetPermissionByRoleId(id int) ([]entity.Permission, error)
+	GetNamedPermissionsRepo() ([]entity.Permission, error)
+	GetRoleByIdRepo(id int) (entity.RoleEntity, error)
 	GetRolesRepo() ([]entity.RolesEntity, error)
 	GetListUserByMerchantIdRepo(merchantId string) ([]entity.ListUsersEntity, error)
+	GetActiveSessionRepo(sessionId string) (entity.UserSessionEntity, error)
+	GetListPendingInvitationsRepo(merchantId string) ([]entity.InvitationEntity, error)
 }
 
 type UserWritesRepositoryItf interface {
 	CreateUsersMerchantRepo(payload dto.InviteMerchantUserDto, credentials dto.EmailDataHtmlDto) (int, error)
 	UpdatePassOrPinRepo(passHash string, pinHash string, username string) error
+	CreateRoleRepo(roleName string, twoFactorRequired bool, merchantAssignable bool) (int, error)
+	UpdateRoleTwoFactorRepo(roleId int, twoFactorRequired bool) error
+	UpdateRoleMerchantAssignableRepo(roleId int, merchantAssignable bool) error
+	DeleteRolePermissionsRepo(roleId int) error
+	CreateRolePermissionsRepo(roleId int, permissionIds []int) error
+	CreateSessionRepo(session entity.UserSessionEntity, refreshTokenHash string, ttl time.Duration) (int, error)
+	RotateSessionRefreshTokenRepo(refreshTokenHash string, newRefreshTokenHash string) (entity.UserSessionEntity, error)
+	RevokeSessionRepo(sessionId string) error
+	RevokeSessionByPreviousRefreshTokenRepo(refreshTokenHash string) error
+	RevokeUserSessionsRepo(userId int) error
+	CreateLoginChallengeRepo(userId int, challengeHash string, purpose string, ttl time.Duration) (int, error)
+	AttemptLoginChallengeRepo(challengeHash string, maxAttempts int) (entity.LoginChallengeEntity, error)
+	UseLoginChallengeRepo(challengeId int) error
+	UpdateTotpSecretRepo(userId int, secret string) error
+	EnableTwoFactorRepo(userId int, step int64) error
+	DisableTwoFactorRepo(userId int) error
+	UseTotpStepRepo(userId int, step int64) error
+	DeleteRecoveryCodesRepo(userId int) error
+	CreateRecoveryCodesRepo(userId int, codeHashes []string) error
+	UseRecoveryCodeRepo(userId int, codeHash string) error
+	GetAuthAttemptRepo(scope string, subject string, credential string) (entity.AuthAttemptEntity, error)
+	RecordAuthFailureRepo(scope string, subject string, credential string, window time.Duration) (int, error)
+	UpdateAuthAttemptDelayRepo(scope string, subject string, credential string, delay time.Duration, lockout time.Duration) error
+	ResetAuthAttemptRepo(scope string, subject string, credential string) error
+	DeleteAuthAttemptsRepo(scope string, subject string) error
+	CreateCredentialResetRepo(userId int, tokenHash string, credential string, ipAddress string, ttl time.Duration) (int, error)
+	UseCredentialResetRepo(tokenHash string) (entity.CredentialResetEntity, error)
+	ExpireCredentialResetsRepo(userId int) error
+	CreateInvitationRepo(payload dto.InviteMerchantUserDto, tokenHash string, ttl time.Duration) (int, error)
+	AcceptInvitationRepo(tokenHash string) (entity.InvitationEntity, error)
+	ResendInvitationRepo(invitationId int, merchantId string, tokenHash string, ttl time.Duration) (entity.InvitationEntity, error)
+	RevokeInvitationRepo(invitationId int, merchantId string) error
+	GetMerchantUserForUpdateRepo(userId int, merchantId string) (entity.ListUsersEntity, error)
+	GetActiveMerchantAdminIdsForUpdateRepo(merchantId string) ([]int, error)
+	UpdateUserStatusRepo(userId int, status string) error
+	UpdateUserRoleRepo(userId int, roleId int) error
+	DeleteUserRepo(userId int) error
 }
 
 type ProviderReadsRepositoryItf interface {
@@ -103,6 +182,8 @@ type ProviderReadsRepositoryItf interface {
 	GetListProviderPaychannelById(id int) ([]entity.InterfacePaychannelEntity, error)
 	GetListProviderChannelAllRepo(params dto.QueryParams) ([]entity.ProviderPaychannelAllEntity, error)
 	GetAllCredentialsRepo(providerId string, interfaceSetting string) ([]entity.ProviderCredentialsEntity, error)
+	GetProviderBankCodeRepo(providerId string, bankCode string) (entity.ProviderBankCodeEntity, error)
+	GetProviderCredentialsByKeysRepo(providerId string, keys []string) ([]entity.ProviderCredentialsEntity, error)
 	GetDetailProviderChannelById(id int) (entity.ProviderChannelDetailEntity, error)
 	GetBankListProviderMethodRepo(providerChannelId int) ([]entity.BankListDto, error)
 	GetBankListProviderChannelRepo(providerChannelId int) ([]entity.BankListDto, error)
@@ -114,9 +195,115 @@ type ProviderReadsRepositoryItf interface {
 
 type ProviderWritesRepositoryItf interface {
 	CreateProviderConfirmationDetail(source string, paymentId string, status string) (int, error)
+	CreateProviderConfirmationDetailWithNotesRepo(source string, paymentId string, result string, notes string) (int, error)
+	CreateProviderCallbackRepo(payload dto.CreateProviderCallbackPayload) (int, error)
+	UpdateProviderCallbackStatusRepo(id int, processStatus string, notes string) error
 	UpdateProviderPaychannelByIdRepo(payload dto.AdjustLimitOrFeeProviderPayload) error
 	AddOperatorProviderChannelRepo(providerChannelId int, bankListId int) (int, error)
 	DeleteOperatorProviderChannelRepo(providerChannelId int, bankListId int) error
 	UpdateStatusProviderPaychannelRepo(id int, status string) error
 	CreateProviderPaychannelRepo(payload dto.CreateProviderChannelDto) (int, error)
+	GetProviderCredentialsRepo() ([]entity.ProviderCredentialsEntity, error)
+	RotateProviderCredentialRepo(id int, oldValue string, newValue string) error
+}
+
+type LedgerReadsRepositoryItf interface {
+	GetLedgerBalancesByOwnerRepo(ownerType string, ownerId string) ([]entity.LedgerAccountBalance, error)
+}
+
+type LedgerWritesRepositoryItf interface {
+	CreateLedgerJournalRepo(payload dto.CreateLedgerJournalPayload) (int, error)
+	GetLedgerBalancesByOwnerRepo(ownerType string, ownerId string) ([]entity.LedgerAccountBalance, error)
+}
+
+type ReconciliationReadsRepositoryItf interface {
+	GetMerchantAccountsRepo() ([]entity.MerchantAccount, error)
+	GetCapitalFlowsByMerchantAccountIdRepo(merchantAccountId int) ([]entity.ReconciliationCapitalFlow, error)
+	GetCapitalFlowPaymentIdsWithoutLedgerRepo(merchantAccountId int, merchantId string) ([]string, error)
+	GetListReconciliationRunsRepo() ([]entity.ReconciliationRun, error)
+	GetReconciliationRunByIdRepo(runId int) (entity.ReconciliationRun, error)
+	GetReconciliationDiscrepanciesByRunIdRepo(runId int) ([]entity.ReconciliationDiscrepancy, error)
+}
+
+type ReconciliationWritesRepositoryItf interface {
+	CreateReconciliationRunRepo(triggeredBy string) (int, error)
+	FinishReconciliationRunRepo(runId int, status string, totalAccounts int, totalDiscrepancies int, notes string) error
+	CreateReconciliationDiscrepancyRepo(payload dto.CreateReconciliationDiscrepancyPayload) (int, error)
+	ResolveReconciliationDiscrepancyRepo(payload dto.ResolveReconciliationDiscrepancyReq) (int, error)
+}
+
+type AuditReadsRepositoryItf interface {
+	GetListAuditLogRepo(params dto.QueryParamsAuditLog) ([]entity.AuditLog, dto.PaginatedResponse, error)
+}
+
+type AuditWritesRepositoryItf interface {
+	CreateAuditLogRepo(payload dto.CreateAuditLogPayload) (int, error)
+}
+
+type HashChainReadsRepositoryItf interface {
+	GetChainTailsRepo() ([]entity.ChainTail, error)
+	GetChainLinksRepo(chain string, chainKey string, afterSeq int64, limit int) ([]entity.ChainLink, error)
+	CountUnchainedRowsRepo(chain string) (int, error)
+	GetLatestChainCheckpointRepo() (entity.ChainCheckpoint, error)
+	GetChainCheckpointTailsRepo(checkpointId int) ([]entity.ChainTail, error)
+	GetListChainCheckpointRepo(params dto.QueryParamsChainCheckpoint) ([]entity.ChainCheckpoint, error)
+}
+
+type HashChainWritesRepositoryItf interface {
+	GetLatestChainCheckpointForUpdateRepo() (entity.ChainCheckpoint, error)
+	GetChainTailsRepo() ([]entity.ChainTail, error)
+	CreateChainCheckpointRepo(payload dto.CreateChainCheckpointPayload) (int, error)
+}
+
+type BalanceApprovalReadsRepositoryItf interface {
+	GetBalanceApprovalThresholdRepo(operationType string) (entity.BalanceApprovalThreshold, error)
+	GetListBalanceApprovalThresholdRepo() ([]entity.BalanceApprovalThreshold, error)
+	GetBalanceOperationRequestByIdRepo(requestId int) (entity.BalanceOperationRequest, error)
+	GetListBalanceOperationRequestRepo(params dto.QueryParamsBalanceOperationRequest) ([]entity.BalanceOperationRequest, dto.PaginatedResponse, error)
+}
+
+type BalanceApprovalWritesRepositoryItf interface {
+	CreateBalanceOperationRequestRepo(payload dto.CreateBalanceOperationRequestPayload) (int, error)
+	GetBalanceOperationRequestForUpdateRepo(requestId int) (entity.BalanceOperationRequest, error)
+	ReviewBalanceOperationRequestRepo(requestId int, status string, reviewedBy string, reviewNotes string) error
+	UpdateBalanceApprovalThresholdRepo(payload dto.UpdateBalanceApprovalThresholdReq) (int64, error)
+}
+
+type DisbursementApprovalReadsRepositoryItf interface {
+	GetListDisbursementApprovalRuleRepo(merchantId string) ([]entity.DisbursementApprovalRule, error)
+	GetDisbursementApprovalRuleForAmountRepo(merchantId string, amount money.Money) (entity.DisbursementApprovalRule, error)
+	GetDisbursementRequestByIdRepo(requestId int, merchantId string) (entity.DisbursementRequest, error)
+	GetListDisbursementRequestReviewRepo(requestId int) ([]entity.DisbursementRequestReview, error)
+	GetListDisbursementRequestRepo(params dto.QueryParamsDisbursementRequest) ([]entity.DisbursementRequest, dto.PaginatedResponse, error)
+}
+
+type DisbursementApprovalWritesRepositoryItf interface {
+	CreateDisbursementRequestRepo(payload dto.CreateDisbursementRequestPayload) (int, error)
+	GetDisbursementRequestForUpdateRepo(requestId int) (entity.DisbursementRequest, error)
+	HasReviewedDisbursementRequestRepo(requestId int, username string) (bool, error)
+	CreateDisbursementRequestReviewRepo(requestId int, reviewedBy string, decision string, notes string) error
+	CountDisbursementRequestApprovalsRepo(requestId int) (int, error)
+	UpdateDisbursementRequestStatusRepo(requestId int, status string, paymentId string, failureReason string) error
+	FailStaleDisbursementRequestsRepo(stale time.Duration, failureReason string) ([]entity.DisbursementRequest, error)
+	DeleteDisbursementApprovalRulesRepo(merchantId string) error
+	CreateDisbursementApprovalRuleRepo(merchantId string, rule dto.DisbursementApprovalRuleDto, createdBy string) error
+}
+
+type DisbursementBatchReadsRepositoryItf interface {
+	GetDisbursementBatchByIdRepo(batchId int, merchantId string) (entity.DisbursementBatch, error)
+	GetListDisbursementBatchRowRepo(batchId int, status string) ([]entity.DisbursementBatchRow, error)
+	GetListDisbursementBatchRepo(params dto.QueryParamsDisbursementBatch) ([]entity.DisbursementBatch, dto.PaginatedResponse, error)
+}
+
+type DisbursementBatchWritesRepositoryItf interface {
+	CreateDisbursementBatchRepo(payload dto.CreateDisbursementBatchPayload) (int, error)
+	CreateDisbursementBatchRowRepo(batchId int, row dto.DisbursementBatchRowDto) error
+	GetDisbursementBatchForUpdateRepo(batchId int) (entity.DisbursementBatch, error)
+	ConfirmDisbursementBatchRepo(batchId int, confirmedBy string) error
+	UpdateDisbursementBatchStatusRepo(batchId int, status string) error
+	UpdateDisbursementBatchRowStatusRepo(rowId int, status string, paymentId string, disbursementRequestId int, errorMessage string) error
+	ClaimDisbursementBatchRowRepo(rowId int) (bool, error)
+	InterruptDisbursementBatchRowsRepo(batchId int) error
+	CompleteDisbursementBatchRepo(batchId int) (bool, error)
+	ClaimStaleDisbursementBatchesRepo(stale time.Duration) ([]entity.DisbursementBatch, error)
 }
@@ -13,15 +13,30 @@ import (
 )
 
 type Repository struct {
-	db                 *sqlx.DB
-	TransactionsReads  internal.TransactionsReadsRepositoryItf
-	TransactionsWrites internal.TransactionsWritesRepositoryItf
-	MerchantReads      internal.MerchantReadsRepositoryItf
-	MerchantWrites     internal.MerchantWritesRepositoryItf
-	ProviderReads      internal.ProviderReadsRepositoryItf
-	ProviderWrites     internal.ProviderWritesRepositoryItf
-	UserReads          internal.UserReadsRepositoryItf
-	UserWrites         internal.UserWritesRepositoryItf
+	db                         *sqlx.DB
+	TransactionsReads          internal.TransactionsReadsRepositoryItf
+	TransactionsWrites         internal.TransactionsWritesRepositoryItf
+	MerchantReads              internal.MerchantReadsRepositoryItf
+	MerchantWrites             internal.MerchantWritesRepositoryItf
+	ProviderReads              internal.ProviderReadsRepositoryItf
+	ProviderWrites             internal.ProviderWritesRepositoryItf
+	UserReads                  internal.UserReadsRepositoryItf
+	UserWrites                 internal.UserWritesRepositoryItf
+	LedgerReads                internal.LedgerReadsRepositoryItf
+	LedgerWrites               internal.LedgerWritesRepositoryItf
+	ReconciliationReads        internal.ReconciliationReadsRepositoryItf
+	ReconciliationWrites       internal.ReconciliationWritesRepositoryItf
+	AuditReads                 internal.AuditReadsRepositoryItf
+	AuditWrites                internal.AuditWritesRepositoryItf
+	HashChainReads             internal.HashChainReadsRepositoryItf
+	HashChainWrites            internal.HashChainWritesRepositoryItf
+	BalanceApprovalReads       internal.BalanceApprovalReadsRepositoryItf
+	BalanceApprovalWrites      internal.BalanceApprovalWritesRepositoryItf
+	DisbursementApprovalReads  internal.DisbursementApprovalReadsRepositoryItf
+	DisbursementApprovalWrites internal.DisbursementApprovalWritesRepositoryItf
+	DisbursementBatchReads     internal.DisbursementBatchReadsRepositoryItf
+	DisbursementBatchWrites    internal.DisbursementBatchWritesRepositoryItf
+	UnitOfWork                 internal.UnitOfWorkItf
 }
 
 func NewReadsRepo(cfg config.Storage) *Repository {
@@ -32,13 +47,27 @@ func NewReadsRepo(cfg config.Storage) *Repository {
 	merchantReads := psql.NewMerchantReads(dbDriverReads)
 	providerReads := psql.NewProviderReads(dbDriverReads)
 	userReads := psql.NewUsersReads(dbDriverReads)
+	ledgerReads := psql.NewLedgerReads(dbDriverReads)
+	reconciliationReads := psql.NewReconciliationReads(dbDriverReads)
+	auditReads := psql.NewAuditReads(dbDriverReads)
+	hashChainReads := psql.NewHashChainReads(dbDriverReads)
+	balanceApprovalReads := psql.NewBalanceApprovalReads(dbDriverReads)
+	disbursementApprovalReads := psql.NewDisbursementApprovalReads(dbDriverReads)
+	disbursementBatchReads := psql.NewDisbursementBatchReads(dbDriverReads)
 
 	return &Repository{
-		db:                dbDriverReads,
-		TransactionsReads: transactionReads,
-		MerchantReads:     merchantReads,
-		ProviderReads:     providerReads,
-		UserReads:         userReads,
+		db:                        dbDriverReads,
+		TransactionsReads:         transactionReads,
+		MerchantReads:             merchantReads,
+		ProviderReads:             providerReads,
+		UserReads:                 userReads,
+		LedgerReads:               ledgerReads,
+		ReconciliationReads:       reconciliationReads,
+		AuditReads:                auditReads,
+		HashChainReads:            hashChainReads,
+		BalanceApprovalReads:      balanceApprovalReads,
+		DisbursementApprovalReads: disbursementApprovalReads,
+		DisbursementBatchReads:    disbursementBatchReads,
 	}
 }
 
@@ -50,16 +79,37 @@ func NewWritesRepo(cfg config.Storage) *Repository {
 	merchantWrites := psql.NewMerchantWrites(dbDriverWrites)
 	userWrites := psql.NewUsersWrites(dbDriverWrites)
 	providerWrites := psql.NewProviderWrites(dbDriverWrites)
+	ledgerWrites := psql.NewLedgerWrites(dbDriverWrites)
+	reconciliationWrites := psql.NewReconciliationWrites(dbDriverWrites)
+	auditWrites := psql.NewAuditWrites(dbDriverWrites)
+	hashChainWrites := psql.NewHashChainWrites(dbDriverWrites)
+	balanceApprovalWrites := psql.NewBalanceApprovalWrites(dbDriverWrites)
+	disbursementApprovalWrites := psql.NewDisbursementApprovalWrites(dbDriverWrites)
+	disbursementBatchWrites := psql.NewDisbursementBatchWrites(dbDriverWrites)
+	unitOfWork := psql.NewUnitOfWork(dbDriverWrites)
 
 	return &Repository{
-		db:                 dbDriverWrites,
-		TransactionsWrites: transactionWrites,
-		MerchantWrites:     merchantWrites,
-		UserWrites:         userWrites,
-		ProviderWrites:     providerWrites,
+		db:                         dbDriverWrites,
+		TransactionsWrites:         transactionWrites,
+		MerchantWrites:             merchantWrites,
+		UserWrites:                 userWrites,
+		ProviderWrites:             providerWrites,
+		LedgerWrites:               ledgerWrites,
+		ReconciliationWrites:       reconciliationWrites,
+		AuditWrites:                auditWrites,
+		HashChainWrites:            hashChainWrites,
+		BalanceApprovalWrites:      balanceApprovalWrites,
+		DisbursementApprovalWrites: disbursementApprovalWrites,
+		DisbursementBatchWrites:    disbursementBatchWrites,
+		UnitOfWork:                 unitOfWork,
 	}
 }
 
+// OpenWritesDB opens the writes connection on its own, for tooling that works on the schema such as cmd/migrate
+func OpenWritesDB(cfg config.Storage) (*sqlx.DB, error) {
+	return initializeConnectionDbReads(cfg.PSQL["psqlWrites"])
+}
+
 func initializeConnectionDbReads(psql config.PSQL) (*sqlx.DB, error) {
 	connectionString := fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=require",
@@ -0,0 +1,338 @@
+// Package migration applies the embedded versioned schema changes in sql and the reference data in seed.
+// Every version has an up and a down file named <version>_<name>.up.sql and <version>_<name>.down.sql,
+// applied versions are tracked in schema_migrations.
+package migration
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed sql/*.sql
+var migrationFiles embed.FS
+
+//go:embed seed/*.sql
+var seedFiles embed.FS
+
+// lockKey serializes migrators running against the same database
+const lockKey = 20240101
+
+const createSchemaMigrations = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+func New(db *sqlx.DB) (*Migrator, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = db.Exec(createSchemaMigrations)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+func loadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		version, name, direction, err := parseFileName(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		contents, err := migrationFiles.ReadFile(path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != name {
+			return nil, fmt.Errorf("migration %v has two names %q and %q", version, migration.Name, name)
+		}
+
+		if direction == "up" {
+			migration.Up = string(contents)
+		} else {
+			migration.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %v_%v needs both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// parseFileName splits 000001_baseline.up.sql into 1, baseline and up
+func parseFileName(fileName string) (int, string, string, error) {
+	base := strings.TrimSuffix(fileName, ".sql")
+	direction := path.Ext(base)
+	if direction != ".up" && direction != ".down" {
+		return 0, "", "", fmt.Errorf("migration file %v is neither up nor down", fileName)
+	}
+	base = strings.TrimSuffix(base, direction)
+
+	versionPart, name, ok := strings.Cut(base, "_")
+	if !ok {
+		return 0, "", "", fmt.Errorf("migration file %v has no name", fileName)
+	}
+
+	version, err := strconv.Atoi(versionPart)
+	if err != nil || version < 1 {
+		return 0, "", "", fmt.Errorf("migration file %v has an invalid version", fileName)
+	}
+
+	return version, name, strings.TrimPrefix(direction, "."), nil
+}
+
+// Up applies every pending migration in version order, each one in its own transaction
+func (m *Migrator) Up() ([]Migration, error) {
+	var applied []Migration
+
+	for _, migration := range m.migrations {
+		ok, err := m.apply(migration)
+		if err != nil {
+			return applied, fmt.Errorf("migration %v_%v: %w", migration.Version, migration.Name, err)
+		}
+
+		if ok {
+			applied = append(applied, migration)
+		}
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) apply(migration Migration) (bool, error) {
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("SELECT pg_advisory_xact_lock($1)", lockKey)
+	if err != nil {
+		return false, err
+	}
+
+	// another migrator could have applied it while this one waited for the lock
+	var exists bool
+	err = tx.Get(&exists, "SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", migration.Version)
+	if err != nil || exists {
+		return false, err
+	}
+
+	_, err = tx.Exec(migration.Up)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// Down reverts the latest steps applied migrations
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	var reverted []Migration
+
+	for i := 0; i < steps; i++ {
+		migration, ok, err := m.revertLatest()
+		if err != nil {
+			return reverted, fmt.Errorf("migration %v_%v: %w", migration.Version, migration.Name, err)
+		}
+
+		if !ok {
+			break
+		}
+		reverted = append(reverted, migration)
+	}
+
+	return reverted, nil
+}
+
+func (m *Migrator) revertLatest() (Migration, bool, error) {
+	var migration Migration
+
+	tx, err := m.db.Beginx()
+	if err != nil {
+		return migration, false, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("SELECT pg_advisory_xact_lock($1)", lockKey)
+	if err != nil {
+		return migration, false, err
+	}
+
+	var versions []int
+	err = tx.Select(&versions, "SELECT version FROM schema_migrations ORDER BY version DESC LIMIT 1")
+	if err != nil || len(versions) == 0 {
+		return migration, false, err
+	}
+
+	migration, ok := m.find(versions[0])
+	if !ok {
+		return Migration{Version: versions[0]}, false, fmt.Errorf("applied version %v has no migration file", versions[0])
+	}
+
+	_, err = tx.Exec(migration.Down)
+	if err != nil {
+		return migration, false, err
+	}
+
+	_, err = tx.Exec("DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+	if err != nil {
+		return migration, false, err
+	}
+
+	return migration, true, tx.Commit()
+}
+
+func (m *Migrator) find(version int) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+
+	return Migration{}, false
+}
+
+// Status lists every known migration, AppliedAt is nil for pending ones
+func (m *Migrator) Status() ([]Status, error) {
+	var applied []struct {
+		Version   int       `db:"version"`
+		AppliedAt time.Time `db:"applied_at"`
+	}
+
+	err := m.db.Select(&applied, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	appliedAt := map[int]time.Time{}
+	for _, row := range applied {
+		appliedAt[row.Version] = row.AppliedAt
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if at, ok := appliedAt[migration.Version]; ok {
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Baseline records the migrations up to version as applied without running them, for databases
+// that were built before migrations existed
+func (m *Migrator) Baseline(version int) ([]Migration, error) {
+	var marked []Migration
+
+	if _, ok := m.find(version); !ok {
+		return nil, fmt.Errorf("unknown migration version %v", version)
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			break
+		}
+
+		result, err := m.db.Exec("INSERT INTO schema_migrations (version, name) VALUES ($1, $2) ON CONFLICT (version) DO NOTHING", migration.Version, migration.Name)
+		if err != nil {
+			return marked, err
+		}
+
+		if rows, _ := result.RowsAffected(); rows > 0 {
+			marked = append(marked, migration)
+		}
+	}
+
+	return marked, nil
+}
+
+// Seed runs every seed file in name order, seed files must be safe to run more than once
+func (m *Migrator) Seed() ([]string, error) {
+	entries, err := fs.ReadDir(seedFiles, "seed")
+	if err != nil {
+		return nil, err
+	}
+
+	var seeded []string
+	for _, entry := range entries {
+		contents, err := seedFiles.ReadFile(path.Join("seed", entry.Name()))
+		if err != nil {
+			return seeded, err
+		}
+
+		_, err = m.db.Exec(string(contents))
+		if err != nil {
+			return seeded, fmt.Errorf("seed %v: %w", entry.Name(), err)
+		}
+		seeded = append(seeded, entry.Name())
+	}
+
+	return seeded, nil
+}
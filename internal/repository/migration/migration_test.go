@@ -0,0 +1,64 @@
+package migration
+
+import "testing"
+
+func TestParseFileName(t *testing.T) {
+	tests := []struct {
+		fileName      string
+		wantVersion   int
+		wantName      string
+		wantDirection string
+		wantErr       bool
+	}{
+		{fileName: "000001_baseline.up.sql", wantVersion: 1, wantName: "baseline", wantDirection: "up"},
+		{fileName: "000001_baseline.down.sql", wantVersion: 1, wantName: "baseline", wantDirection: "down"},
+		{fileName: "000020_disbursement_batches.up.sql", wantVersion: 20, wantName: "disbursement_batches", wantDirection: "up"},
+		{fileName: "12_short.down.sql", wantVersion: 12, wantName: "short", wantDirection: "down"},
+		{fileName: "000001_baseline.sql", wantErr: true},
+		{fileName: "000001_baseline.sideways.sql", wantErr: true},
+		{fileName: "000001.up.sql", wantErr: true},
+		{fileName: "v1_baseline.up.sql", wantErr: true},
+		{fileName: "000000_zero.up.sql", wantErr: true},
+		{fileName: "-1_negative.up.sql", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			version, name, direction, err := parseFileName(tt.fileName)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseFileName(%q) = %v, %v, %v, want error", tt.fileName, version, name, direction)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseFileName(%q) got err %v", tt.fileName, err)
+			}
+			if version != tt.wantVersion || name != tt.wantName || direction != tt.wantDirection {
+				t.Errorf("parseFileName(%q) = %v, %v, %v, want %v, %v, %v", tt.fileName, version, name, direction, tt.wantVersion, tt.wantName, tt.wantDirection)
+			}
+		})
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations got err %v", err)
+	}
+
+	if len(migrations) == 0 {
+		t.Fatal("loadMigrations found no migration")
+	}
+
+	// versions are applied in order, a gap usually means a file was misnamed
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Errorf("migration %v_%v is at position %v, want version %v", migration.Version, migration.Name, i, i+1)
+		}
+		if migration.Up == "" || migration.Down == "" {
+			t.Errorf("migration %v_%v has an empty up or down", migration.Version, migration.Name)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+-- operation roles checked by constant.RoleName*
+INSERT INTO roles (role_name)
+VALUES ('admin'), ('finance'), ('customer support')
+ON CONFLICT (role_name) DO NOTHING;
@@ -0,0 +1,29 @@
+DROP TABLE IF EXISTS report_storages;
+DROP TABLE IF EXISTS provider_credentials;
+DROP TABLE IF EXISTS merchant_capital_flows;
+DROP TABLE IF EXISTS reason_lists;
+DROP TABLE IF EXISTS provider_paychannel_daily_transactions;
+DROP TABLE IF EXISTS merchant_paychannel_daily_transactions;
+DROP TABLE IF EXISTS histories_operations;
+DROP TABLE IF EXISTS paychannel_routings;
+DROP TABLE IF EXISTS transaction_status_logs;
+DROP TABLE IF EXISTS provider_transaction_confirmation_details;
+DROP TABLE IF EXISTS account_informations;
+DROP TABLE IF EXISTS merchant_callbacks;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS provider_paychannel_bank_lists;
+DROP TABLE IF EXISTS provider_paychannels;
+DROP TABLE IF EXISTS merchant_paychannels;
+DROP TABLE IF EXISTS provider_payment_method_bank_lists;
+DROP TABLE IF EXISTS merchant_payment_method_bank_lists;
+DROP TABLE IF EXISTS bank_lists;
+DROP TABLE IF EXISTS provider_payment_methods;
+DROP TABLE IF EXISTS providers;
+DROP TABLE IF EXISTS merchant_payment_methods;
+DROP TABLE IF EXISTS merchant_accounts;
+DROP TABLE IF EXISTS merchants;
+DROP TABLE IF EXISTS payment_methods;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
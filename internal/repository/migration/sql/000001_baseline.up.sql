@@ -21,7 +21,7 @@ CREATE TABLE users (
     username VARCHAR(255) UNIQUE,
     password VARCHAR(255),
     pin VARCHAR(255),
-    merchant_id VARCHAR(255),
+    merchantid VARCHAR(255),
     role_id INT REFERENCES roles(ID),
     user_type VARCHAR(255) NOT NULL,
     status VARCHAR(50) NOT NULL,
@@ -233,6 +233,7 @@ CREATE TABLE transaction_status_logs (
     status_log TEXT NOT NULL,
     change_by VARCHAR(255) NOT NULL,
     notes TEXT,
+    real_notes TEXT,
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
@@ -276,22 +277,46 @@ CREATE TABLE provider_paychannel_daily_transactions (
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
--- 26. Merchant Capital Flows
+-- 26. Reason Lists
+CREATE TABLE reason_lists (
+    ID SERIAL PRIMARY KEY,
+    reason_name VARCHAR(255) UNIQUE NOT NULL,
+    reason_description VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- reason ids are referenced by constant.ReasonId*
+INSERT INTO reason_lists (ID, reason_name, reason_description) VALUES
+(1, 'Top Up', 'balance top up by operation'),
+(2, 'Hold Balance', 'balance moved into or out of hold'),
+(3, 'Settlement', 'not settled balance settled'),
+(4, 'Out Settlement', 'settled balance paid out to merchant'),
+(5, 'In', 'payin transaction'),
+(6, 'Out', 'payout transaction'),
+(7, 'Fee', 'merchant fee of a transaction'),
+(8, 'Balance Transfer', 'balance transfer between merchant accounts');
+
+SELECT setval(pg_get_serial_sequence('reason_lists', 'id'), (SELECT MAX(ID) FROM reason_lists));
+
+-- 27. Merchant Capital Flows
 CREATE TABLE merchant_capital_flows (
     ID SERIAL PRIMARY KEY,
     payment_id VARCHAR(255) NOT NULL,
     merchant_account_id INT NOT NULL,
     temp_balance DECIMAL(18,2) NOT NULL DEFAULT 0.00,
-    transaction_type VARCHAR(50) NOT NULL,
+    amount DECIMAL(18,2) NOT NULL DEFAULT 0.00,
+    reason_id INT NOT NULL REFERENCES reason_lists(ID),
     status VARCHAR(50) NOT NULL,
     capital_type VARCHAR(50),
     notes VARCHAR(255),
     reverse_from VARCHAR(255),
+    created_by VARCHAR(255),
     created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
--- 27. Provider Credentials
+-- 28. Provider Credentials
 CREATE TABLE provider_credentials (
     ID SERIAL PRIMARY KEY,
     provider_id VARCHAR(255) REFERENCES providers(provider_id),
@@ -302,7 +327,7 @@ CREATE TABLE provider_credentials (
     updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
 );
 
--- 28. Report Storages
+-- 29. Report Storages
 CREATE TABLE report_storages (
     ID SERIAL PRIMARY KEY,
     merchant_id VARCHAR(50),
@@ -0,0 +1,67 @@
+-- Ledger Accounts
+CREATE TABLE ledger_accounts (
+    ID SERIAL PRIMARY KEY,
+    owner_type VARCHAR(50) NOT NULL,
+    owner_id VARCHAR(255) NOT NULL,
+    account_type VARCHAR(50) NOT NULL,
+    currency VARCHAR(10) NOT NULL DEFAULT 'IDR',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (owner_type, owner_id, account_type)
+);
+
+-- Ledger Journals
+CREATE TABLE ledger_journals (
+    ID SERIAL PRIMARY KEY,
+    payment_id VARCHAR(255) NOT NULL,
+    reason_id INT NOT NULL,
+    notes VARCHAR(255),
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ledger_journals_payment_id ON ledger_journals (payment_id);
+
+-- Ledger Postings
+CREATE TABLE ledger_postings (
+    ID SERIAL PRIMARY KEY,
+    ledger_journal_id INT NOT NULL REFERENCES ledger_journals(ID),
+    ledger_account_id INT NOT NULL REFERENCES ledger_accounts(ID),
+    direction VARCHAR(10) NOT NULL CHECK (direction IN ('DEBIT', 'CREDIT')),
+    amount DECIMAL(18,2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_ledger_postings_account_id ON ledger_postings (ledger_account_id);
+
+-- opening balances, move the current merchant_accounts snapshot into the ledger once
+INSERT INTO ledger_accounts (owner_type, owner_id, account_type)
+SELECT 'MERCHANT', ma.merchant_id, t.account_type
+FROM merchant_accounts ma
+CROSS JOIN (VALUES ('SETTLED'), ('NOT_SETTLED'), ('HOLD'), ('PENDING_OUT')) AS t(account_type)
+UNION ALL
+SELECT 'PLATFORM', 'HYPAY', 'OPENING_BALANCE'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO ledger_journals (payment_id, reason_id, notes, created_by)
+VALUES ('opening_balance', 0, 'opening balance from merchant_accounts', 'SYSTEM');
+
+-- every bucket is posted with its sign, a negative bucket is a debit, and each merchant posting gets its exact
+-- counterpart on the platform opening balance so the journal is balanced whatever the buckets hold
+INSERT INTO ledger_postings (ledger_journal_id, ledger_account_id, direction, amount)
+SELECT lj.ID, p.ledger_account_id, p.direction, ABS(b.amount)
+FROM merchant_accounts ma
+CROSS JOIN LATERAL (VALUES
+    ('SETTLED', ma.settle_balance),
+    ('NOT_SETTLED', ma.not_settle_balance),
+    ('HOLD', ma.hold_balance),
+    ('PENDING_OUT', ma.pending_transaction_out)
+) AS b(account_type, amount)
+JOIN ledger_accounts la ON la.owner_type = 'MERCHANT' AND la.owner_id = ma.merchant_id AND la.account_type = b.account_type
+JOIN ledger_accounts ob ON ob.owner_type = 'PLATFORM' AND ob.owner_id = 'HYPAY' AND ob.account_type = 'OPENING_BALANCE'
+JOIN ledger_journals lj ON lj.payment_id = 'opening_balance'
+CROSS JOIN LATERAL (VALUES
+    (la.ID, CASE WHEN b.amount > 0 THEN 'CREDIT' ELSE 'DEBIT' END),
+    (ob.ID, CASE WHEN b.amount > 0 THEN 'DEBIT' ELSE 'CREDIT' END)
+) AS p(ledger_account_id, direction)
+WHERE b.amount <> 0;
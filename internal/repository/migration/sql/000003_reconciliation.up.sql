@@ -0,0 +1,31 @@
+-- Reconciliation Runs
+CREATE TABLE reconciliation_runs (
+    ID SERIAL PRIMARY KEY,
+    triggered_by VARCHAR(255) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    total_accounts INT NOT NULL DEFAULT 0,
+    total_discrepancies INT NOT NULL DEFAULT 0,
+    notes VARCHAR(255),
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+-- Reconciliation Discrepancies
+CREATE TABLE reconciliation_discrepancies (
+    ID SERIAL PRIMARY KEY,
+    reconciliation_run_id INT NOT NULL REFERENCES reconciliation_runs(ID),
+    merchant_id VARCHAR(255) NOT NULL,
+    merchant_account_id INT NOT NULL,
+    bucket VARCHAR(50) NOT NULL,
+    expected_amount DECIMAL(18,2) NOT NULL,
+    actual_amount DECIMAL(18,2) NOT NULL,
+    difference DECIMAL(18,2) NOT NULL,
+    payment_ids TEXT NOT NULL DEFAULT '',
+    status VARCHAR(50) NOT NULL,
+    resolved_by VARCHAR(255),
+    resolution_notes VARCHAR(255),
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_reconciliation_discrepancies_run_id ON reconciliation_discrepancies (reconciliation_run_id);
@@ -0,0 +1,117 @@
+-- Provider Bank Codes
+CREATE TABLE provider_bank_codes (
+    ID SERIAL PRIMARY KEY,
+    provider_id VARCHAR(255) NOT NULL,
+    bank_code VARCHAR(50) NOT NULL,
+    provider_bank_id VARCHAR(50) NOT NULL,
+    provider_bank_name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider_id, bank_code)
+);
+
+-- bank codes of jack, previously hardcoded in the service
+INSERT INTO provider_bank_codes (provider_id, bank_code, provider_bank_id, provider_bank_name)
+VALUES
+('ID-JACK', 'IDR_061', '16', 'anz'),
+('ID-JACK', 'IDR_116', '8', 'aceh'),
+('ID-JACK', 'IDR_088', '15', 'antar_daerah'),
+('ID-JACK', 'IDR_037', '18', 'artha'),
+('ID-JACK', 'IDR_542', '153', 'jago'),
+('ID-JACK', 'IDR_133', '25', 'bengkulu'),
+('ID-JACK', 'IDR_547', '37', 'btpn_syar'),
+('ID-JACK', 'IDR_521', '39', 'bukopin_syar'),
+('ID-JACK', 'IDR_076', '40', 'bumi_artha'),
+('ID-JACK', 'IDR_054', '42', 'capital'),
+('ID-JACK', 'IDR_014', '3', 'bca'),
+('ID-JACK', 'IDR_036', '46', 'china_cons'),
+('ID-JACK', 'IDR_011', '7', 'danamon'),
+('ID-JACK', 'IDR_111', '58', 'dki'),
+('ID-JACK', 'IDR_161', '62', 'ganesha'),
+('ID-JACK', 'IDR_567', '64', 'harda'),
+('ID-JACK', 'IDR_513', '68', 'ina_perdana'),
+('ID-JACK', 'IDR_555', '69', 'index_selindo'),
+('ID-JACK', 'IDR_115', '71', 'jambi'),
+('ID-JACK', 'IDR_472', '72', 'jasa_jakarta'),
+('ID-JACK', 'IDR_113', '73', 'jateng'),
+('ID-JACK', 'IDR_114', '75', 'jatim'),
+('ID-JACK', 'IDR_123', '78', 'kalbar'),
+('ID-JACK', 'IDR_122', '80', 'kalsel'),
+('ID-JACK', 'IDR_125', '82', 'kalteng'),
+('ID-JACK', 'IDR_124', '84', 'kaltim'),
+('ID-JACK', 'IDR_535', '229', 'seabank'),
+('ID-JACK', 'IDR_121', '86', 'lampung'),
+('ID-JACK', 'IDR_131', '87', 'maluku'),
+('ID-JACK', 'IDR_008', '2', 'mandiri'),
+('ID-JACK', 'IDR_564', '162', 'mantap'),
+('ID-JACK', 'IDR_157', '90', 'maspion'),
+('ID-JACK', 'IDR_097', '91', 'mayapada'),
+('ID-JACK', 'IDR_553', '95', 'mayora'),
+('ID-JACK', 'IDR_426', '97', 'mega_tbk'),
+('ID-JACK', 'IDR_506', '96', 'mega_syar'),
+('ID-JACK', 'IDR_151', '98', 'mestika'),
+('ID-JACK', 'IDR_485', '103', 'mnc'),
+('ID-JACK', 'IDR_548', '105', 'multiarta'),
+('ID-JACK', 'IDR_095', '77', 'jtrust'),
+('ID-JACK', 'IDR_128', '110', 'ntb'),
+('ID-JACK', 'IDR_130', '111', 'ntt'),
+('ID-JACK', 'IDR_145', '7', 'danamon'),
+('ID-JACK', 'IDR_069', '45', 'china'),
+('ID-JACK', 'IDR_146', '70', 'india'),
+('ID-JACK', 'IDR_042', '101', 'mitsubishi'),
+('ID-JACK', 'IDR_132', '116', 'papua'),
+('ID-JACK', 'IDR_013', '6', 'permata'),
+('ID-JACK', 'IDR_520', '118', 'prima_master'),
+('ID-JACK', 'IDR_002', '4', 'bri'),
+('ID-JACK', 'IDR_119', '125', 'riau'),
+('ID-JACK', 'IDR_523', '127', 'sampoerna'),
+('ID-JACK', 'IDR_152', '129', 'shinhan'),
+('ID-JACK', 'IDR_153', '130', 'sinarmas'),
+('ID-JACK', 'IDR_126', '133', 'sulselbar'),
+('ID-JACK', 'IDR_134', '135', 'sulteng'),
+('ID-JACK', 'IDR_135', '136', 'sultenggara'),
+('ID-JACK', 'IDR_127', '137', 'sulut'),
+('ID-JACK', 'IDR_118', '138', 'sumbar'),
+('ID-JACK', 'IDR_120', '140', 'sumsel_babel'),
+('ID-JACK', 'IDR_117', '142', 'sumut'),
+('ID-JACK', 'IDR_451', '154', 'bsi'),
+('ID-JACK', 'IDR_566', '145', 'victoria'),
+('ID-JACK', 'IDR_405', '146', 'victoria_syar'),
+('ID-JACK', 'IDR_068', '147', 'woori'),
+('ID-JACK', 'IDR_490', '148', 'yudha_bhakti'),
+('ID-JACK', 'IDR_536', '24', 'bca_syar'),
+('ID-JACK', 'IDR_110', '27', 'bjb'),
+('ID-JACK', 'IDR_425', '28', 'bjb_syar'),
+('ID-JACK', 'IDR_009', '1', 'bni'),
+('ID-JACK', 'IDR_129', '20', 'bali'),
+('ID-JACK', 'IDR_137', '22', 'banten'),
+('ID-JACK', 'IDR_112', '56', 'diy'),
+('ID-JACK', 'IDR_494', '100', 'mitraniaga'),
+('ID-JACK', 'IDR_200', '34', 'btn'),
+('ID-JACK', 'IDR_213', '36', 'btpn'),
+('ID-JACK', 'IDR_441', '38', 'bukopin'),
+('ID-JACK', 'IDR_022', '5', 'cimb'),
+('ID-JACK', 'IDR_031', '50', 'citibank'),
+('ID-JACK', 'IDR_950', '51', 'commonwealth'),
+('ID-JACK', 'IDR_949', '47', 'chinatrust'),
+('ID-JACK', 'IDR_046', '53', 'dbs'),
+('ID-JACK', 'IDR_041', '66', 'hsbc'),
+('ID-JACK', 'IDR_164', '67', 'icbc'),
+('ID-JACK', 'IDR_484', '63', 'hana'),
+('ID-JACK', 'IDR_016', '92', 'maybank'),
+('ID-JACK', 'IDR_147', '104', 'muamalat'),
+('ID-JACK', 'IDR_503', '109', 'nobu'),
+('ID-JACK', 'IDR_028', '112', 'ocbc'),
+('ID-JACK', 'IDR_019', '114', 'panin'),
+('ID-JACK', 'IDR_517', '115', 'panin_syar'),
+('ID-JACK', 'IDR_167', '121', 'qnb'),
+('ID-JACK', 'IDR_498', '128', 'sbi'),
+('ID-JACK', 'IDR_050', '132', 'stanchard'),
+('ID-JACK', 'IDR_023', '144', 'uob'),
+('ID-JACK', 'IDR_945', '164', 'ibk'),
+('ID-JACK', 'IDR_201', '35', 'btn_syar'),
+('ID-JACK', 'ID_OVO', '150', 'ovo'),
+('ID-JACK', 'ID_DANA', '166', 'dana'),
+('ID-JACK', 'ID_GOPAY', '173', 'gopay'),
+('ID-JACK', 'ID_SHOPEEPAY', '236', 'shopeepay'),
+('ID-JACK', 'ID_LINKAJA', '165', 'linkaja');
@@ -0,0 +1,16 @@
+-- Provider Callbacks
+CREATE TABLE provider_callbacks (
+    ID SERIAL PRIMARY KEY,
+    provider_id VARCHAR(255) NOT NULL,
+    dedup_key VARCHAR(255) NOT NULL,
+    payment_id VARCHAR(255),
+    callback_status VARCHAR(50),
+    source_ip VARCHAR(255),
+    raw_body TEXT NOT NULL,
+    process_status VARCHAR(50) NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_provider_callbacks_dedup_key ON provider_callbacks (provider_id, dedup_key);
@@ -0,0 +1,6 @@
+ALTER TABLE merchant_callbacks
+    DROP COLUMN IF EXISTS delivery_id,
+    DROP COLUMN IF EXISTS attempt_number,
+    DROP COLUMN IF EXISTS next_retry_at;
+
+DROP TABLE IF EXISTS merchant_callback_deliveries;
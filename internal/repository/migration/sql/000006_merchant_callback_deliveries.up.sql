@@ -0,0 +1,20 @@
+-- Merchant Callback Deliveries
+CREATE TABLE merchant_callback_deliveries (
+    ID SERIAL PRIMARY KEY,
+    payment_id VARCHAR(255) NOT NULL REFERENCES transactions(payment_id),
+    payment_status VARCHAR(50) NOT NULL,
+    delivery_status VARCHAR(50) NOT NULL,
+    attempt_count INT NOT NULL DEFAULT 0,
+    next_retry_at TIMESTAMP,
+    last_result TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_merchant_callback_deliveries_due ON merchant_callback_deliveries (delivery_status, next_retry_at);
+
+-- every automatic attempt is kept in merchant_callbacks with its attempt number and next retry time
+ALTER TABLE merchant_callbacks
+    ADD COLUMN delivery_id INT REFERENCES merchant_callback_deliveries(ID),
+    ADD COLUMN attempt_number INT,
+    ADD COLUMN next_retry_at TIMESTAMP;
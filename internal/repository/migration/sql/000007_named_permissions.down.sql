@@ -0,0 +1,8 @@
+DELETE FROM role_permissions
+WHERE permission_id IN (SELECT ID FROM permissions WHERE permission_name IS NOT NULL);
+
+DELETE FROM permissions WHERE permission_name IS NOT NULL;
+
+ALTER TABLE role_permissions DROP CONSTRAINT role_permissions_role_id_permission_id_key;
+
+ALTER TABLE permissions DROP COLUMN permission_name;
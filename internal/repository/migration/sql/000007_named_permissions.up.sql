@@ -0,0 +1,82 @@
+-- permissions get a stable name checked by the route middleware, see constant.Permission*
+ALTER TABLE permissions ADD COLUMN permission_name VARCHAR(255) UNIQUE;
+
+DELETE FROM role_permissions a
+USING role_permissions b
+WHERE a.ID > b.ID
+    AND a.role_id = b.role_id
+    AND a.permission_id = b.permission_id;
+
+ALTER TABLE role_permissions ADD CONSTRAINT role_permissions_role_id_permission_id_key UNIQUE (role_id, permission_id);
+
+INSERT INTO roles (role_name)
+VALUES ('admin'), ('finance'), ('customer support')
+ON CONFLICT (role_name) DO NOTHING;
+
+INSERT INTO permissions (permission_name, permission_desc)
+VALUES
+    ('balance.topup', 'top up merchant balance'),
+    ('balance.hold', 'hold merchant balance'),
+    ('balance.settlement', 'add merchant settlement'),
+    ('balance.transfer', 'transfer balance between merchants'),
+    ('balance.payout_settlement', 'send payout settlement'),
+    ('balance.reverse', 'reverse manual payment'),
+    ('transaction.update_status', 'update transaction status'),
+    ('transaction.override_status', 'reopen final transaction status'),
+    ('callback.send', 'send merchant callback'),
+    ('disbursement.create', 'create disbursement'),
+    ('export.create', 'create export and report'),
+    ('merchant.create', 'create merchant'),
+    ('merchant.update_status', 'update merchant status'),
+    ('merchant.paychannel.manage', 'manage merchant paychannel, segment, fee and limit'),
+    ('merchant.secret.view', 'display merchant secret key'),
+    ('merchant.secret.rotate', 'generate merchant secret key'),
+    ('merchant.user.invite', 'invite merchant user'),
+    ('provider.paychannel.manage', 'manage provider paychannel, operator, fee and limit'),
+    ('reconciliation.run', 'run balance reconciliation'),
+    ('reconciliation.resolve', 'resolve reconciliation discrepancy'),
+    ('role.manage', 'create roles and edit role permissions')
+ON CONFLICT (permission_name) DO NOTHING;
+
+-- the grants match the role checks the controllers had before
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.ID, p.ID
+FROM
+    (
+        VALUES
+            ('admin', 'balance.topup'),
+            ('admin', 'balance.hold'),
+            ('admin', 'balance.settlement'),
+            ('admin', 'balance.transfer'),
+            ('admin', 'balance.payout_settlement'),
+            ('admin', 'balance.reverse'),
+            ('admin', 'transaction.update_status'),
+            ('admin', 'transaction.override_status'),
+            ('admin', 'callback.send'),
+            ('admin', 'disbursement.create'),
+            ('admin', 'export.create'),
+            ('admin', 'merchant.create'),
+            ('admin', 'merchant.update_status'),
+            ('admin', 'merchant.paychannel.manage'),
+            ('admin', 'merchant.secret.view'),
+            ('admin', 'merchant.secret.rotate'),
+            ('admin', 'merchant.user.invite'),
+            ('admin', 'provider.paychannel.manage'),
+            ('admin', 'reconciliation.run'),
+            ('admin', 'reconciliation.resolve'),
+            ('admin', 'role.manage'),
+            ('finance', 'balance.topup'),
+            ('finance', 'balance.hold'),
+            ('finance', 'balance.settlement'),
+            ('finance', 'balance.transfer'),
+            ('finance', 'balance.payout_settlement'),
+            ('finance', 'balance.reverse'),
+            ('finance', 'transaction.update_status'),
+            ('finance', 'disbursement.create'),
+            ('finance', 'export.create'),
+            ('finance', 'reconciliation.run'),
+            ('finance', 'reconciliation.resolve')
+    ) AS g(role_name, permission_name)
+    JOIN roles r ON r.role_name = g.role_name
+    JOIN permissions p ON p.permission_name = g.permission_name
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,19 @@
+-- User Sessions, one row per login on a device, access tokens carry session_id
+CREATE TABLE user_sessions (
+    ID SERIAL PRIMARY KEY,
+    session_id VARCHAR(64) NOT NULL UNIQUE,
+    user_id INT NOT NULL REFERENCES users(ID),
+    device VARCHAR(255),
+    ip_address VARCHAR(64),
+    refresh_token_hash VARCHAR(64) NOT NULL UNIQUE,
+    -- the refresh token rotated out last, presenting it again revokes the session
+    previous_refresh_token_hash VARCHAR(64),
+    expires_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_sessions_user_id ON user_sessions (user_id);
+CREATE INDEX idx_user_sessions_previous_refresh_token_hash ON user_sessions (previous_refresh_token_hash);
@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS user_login_challenges;
+
+DROP TABLE IF EXISTS user_recovery_codes;
+
+ALTER TABLE roles
+    DROP COLUMN IF EXISTS two_factor_required;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS totp_last_used_step,
+    DROP COLUMN IF EXISTS two_factor_enabled_at,
+    DROP COLUMN IF EXISTS two_factor_enabled,
+    DROP COLUMN IF EXISTS totp_secret;
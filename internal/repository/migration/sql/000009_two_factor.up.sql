@@ -0,0 +1,37 @@
+-- totp_secret is kept while enrollment is pending, two_factor_enabled flips once a first code is verified
+ALTER TABLE users
+    ADD COLUMN totp_secret VARCHAR(255),
+    ADD COLUMN two_factor_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN two_factor_enabled_at TIMESTAMP,
+    -- the last accepted totp step, a code is never accepted twice
+    ADD COLUMN totp_last_used_step BIGINT;
+
+-- users of a role that requires two factor have to enroll before their first token is issued
+ALTER TABLE roles
+    ADD COLUMN two_factor_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE roles SET two_factor_required = TRUE WHERE role_name IN ('admin', 'finance');
+
+-- User Recovery Codes, single use codes for a lost authenticator
+CREATE TABLE user_recovery_codes (
+    ID SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(ID),
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_recovery_codes_user_id ON user_recovery_codes (user_id);
+
+-- User Login Challenges, issued when the password is right and a second factor is still needed
+CREATE TABLE user_login_challenges (
+    ID SERIAL PRIMARY KEY,
+    challenge_hash VARCHAR(64) NOT NULL UNIQUE,
+    user_id INT NOT NULL REFERENCES users(ID),
+    -- VERIFY asks for a code, SETUP enrolls a user whose role requires two factor
+    purpose VARCHAR(50) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
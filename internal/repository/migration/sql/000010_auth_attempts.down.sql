@@ -0,0 +1,6 @@
+DELETE FROM role_permissions
+WHERE permission_id IN (SELECT ID FROM permissions WHERE permission_name = 'user.unlock');
+
+DELETE FROM permissions WHERE permission_name = 'user.unlock';
+
+DROP TABLE IF EXISTS auth_attempts;
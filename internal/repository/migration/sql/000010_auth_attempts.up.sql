@@ -0,0 +1,28 @@
+-- Auth Attempts, failed password and pin entries counted per user and per ip
+CREATE TABLE auth_attempts (
+    ID SERIAL PRIMARY KEY,
+    -- USER counts by username, IP counts by the client address of a login
+    scope VARCHAR(20) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    -- PASSWORD or PIN
+    credential VARCHAR(20) NOT NULL,
+    failed_count INT NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMP,
+    -- progressive delay, no attempt is checked before next_attempt_at
+    next_attempt_at TIMESTAMP,
+    locked_until TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (scope, subject, credential)
+);
+
+INSERT INTO permissions (permission_name, permission_desc)
+VALUES ('user.unlock', 'unlock users and addresses locked out by failed attempts')
+ON CONFLICT (permission_name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.ID, p.ID
+FROM roles r
+JOIN permissions p ON p.permission_name = 'user.unlock'
+WHERE r.role_name = 'admin'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
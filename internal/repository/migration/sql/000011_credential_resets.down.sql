@@ -0,0 +1 @@
+DROP TABLE IF EXISTS credential_resets;
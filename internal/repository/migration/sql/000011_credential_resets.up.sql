@@ -0,0 +1,15 @@
+-- Credential Resets, emailed single use links to set a forgotten password or pin
+CREATE TABLE credential_resets (
+    ID SERIAL PRIMARY KEY,
+    -- hash of the id signed into the reset token, the token itself is never stored
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    user_id INT NOT NULL REFERENCES users(ID),
+    -- PASSWORD or PIN
+    credential VARCHAR(20) NOT NULL,
+    ip_address VARCHAR(255),
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_credential_resets_user_id ON credential_resets (user_id);
@@ -0,0 +1,21 @@
+-- User Invitations, a pending invitation becomes a user once the invitee picks a password and pin
+CREATE TABLE user_invitations (
+    ID SERIAL PRIMARY KEY,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL,
+    merchant_id VARCHAR(255) NOT NULL,
+    role_id INT NOT NULL REFERENCES roles(ID),
+    invited_by VARCHAR(255),
+    -- PENDING, ACCEPTED or REVOKED, a pending invitation past expires_at can still be resent
+    status VARCHAR(50) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    accepted_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- an email has one pending invitation at a time
+CREATE UNIQUE INDEX idx_user_invitations_pending_email ON user_invitations (email) WHERE status = 'PENDING';
+
+CREATE INDEX idx_user_invitations_merchant_id ON user_invitations (merchant_id);
@@ -0,0 +1,20 @@
+DELETE FROM role_permissions
+WHERE permission_id IN (SELECT ID FROM permissions WHERE permission_name = 'merchant.user.manage');
+
+DELETE FROM permissions WHERE permission_name = 'merchant.user.manage';
+
+ALTER TABLE credential_resets
+    DROP CONSTRAINT IF EXISTS credential_resets_user_id_fkey,
+    ADD CONSTRAINT credential_resets_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(ID);
+
+ALTER TABLE user_login_challenges
+    DROP CONSTRAINT IF EXISTS user_login_challenges_user_id_fkey,
+    ADD CONSTRAINT user_login_challenges_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(ID);
+
+ALTER TABLE user_recovery_codes
+    DROP CONSTRAINT IF EXISTS user_recovery_codes_user_id_fkey,
+    ADD CONSTRAINT user_recovery_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(ID);
+
+ALTER TABLE user_sessions
+    DROP CONSTRAINT IF EXISTS user_sessions_user_id_fkey,
+    ADD CONSTRAINT user_sessions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(ID);
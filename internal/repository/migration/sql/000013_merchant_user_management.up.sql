@@ -0,0 +1,27 @@
+-- removing a user takes its sessions, two factor codes and pending resets with it
+ALTER TABLE user_sessions
+    DROP CONSTRAINT IF EXISTS user_sessions_user_id_fkey,
+    ADD CONSTRAINT user_sessions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(ID) ON DELETE CASCADE;
+
+ALTER TABLE user_recovery_codes
+    DROP CONSTRAINT IF EXISTS user_recovery_codes_user_id_fkey,
+    ADD CONSTRAINT user_recovery_codes_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(ID) ON DELETE CASCADE;
+
+ALTER TABLE user_login_challenges
+    DROP CONSTRAINT IF EXISTS user_login_challenges_user_id_fkey,
+    ADD CONSTRAINT user_login_challenges_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(ID) ON DELETE CASCADE;
+
+ALTER TABLE credential_resets
+    DROP CONSTRAINT IF EXISTS credential_resets_user_id_fkey,
+    ADD CONSTRAINT credential_resets_user_id_fkey FOREIGN KEY (user_id) REFERENCES users(ID) ON DELETE CASCADE;
+
+INSERT INTO permissions (permission_name, permission_desc)
+VALUES ('merchant.user.manage', 'change status and role of merchant users and remove them')
+ON CONFLICT (permission_name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.ID, p.ID
+FROM roles r
+JOIN permissions p ON p.permission_name = 'merchant.user.manage'
+WHERE r.role_name = 'admin'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
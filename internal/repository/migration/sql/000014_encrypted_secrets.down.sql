@@ -0,0 +1,6 @@
+-- fails while a sealed value is longer than 255 characters
+ALTER TABLE provider_credentials
+    ALTER COLUMN value TYPE VARCHAR(255);
+
+ALTER TABLE merchants
+    ALTER COLUMN merchant_secret TYPE VARCHAR(255);
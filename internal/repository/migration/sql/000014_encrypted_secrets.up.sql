@@ -0,0 +1,6 @@
+-- sealed values are longer than the plaintext they hold, see internal/pkg/envelope
+ALTER TABLE merchants
+    ALTER COLUMN merchant_secret TYPE TEXT;
+
+ALTER TABLE provider_credentials
+    ALTER COLUMN value TYPE TEXT;
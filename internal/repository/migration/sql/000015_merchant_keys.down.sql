@@ -0,0 +1,13 @@
+ALTER TABLE merchants
+    ADD COLUMN merchant_secret TEXT UNIQUE;
+
+UPDATE merchants m
+SET merchant_secret = k.secret
+FROM merchant_keys k
+WHERE k.merchant_id = m.merchant_id
+AND k.status = 'ACTIVE';
+
+ALTER TABLE merchants
+    ALTER COLUMN merchant_secret SET NOT NULL;
+
+DROP TABLE merchant_keys;
@@ -0,0 +1,26 @@
+-- merchants sign callbacks with several keys while a rotation overlaps, see constant/merchant_key.go
+CREATE TABLE merchant_keys (
+    id SERIAL PRIMARY KEY,
+    merchant_id VARCHAR(255) NOT NULL REFERENCES merchants(merchant_id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    secret TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    overlap_seconds INT NOT NULL DEFAULT 0,
+    activate_at TIMESTAMP NOT NULL,
+    retire_at TIMESTAMP,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (merchant_id, version)
+);
+
+CREATE UNIQUE INDEX merchant_keys_active_idx ON merchant_keys (merchant_id) WHERE status = 'ACTIVE';
+CREATE UNIQUE INDEX merchant_keys_next_idx ON merchant_keys (merchant_id) WHERE status = 'NEXT';
+CREATE INDEX merchant_keys_schedule_idx ON merchant_keys (status, activate_at);
+
+INSERT INTO merchant_keys (merchant_id, version, secret, status, activate_at, created_by, created_at, updated_at)
+SELECT merchant_id, 1, merchant_secret, 'ACTIVE', created_at, 'SYSTEM', created_at, updated_at
+FROM merchants;
+
+ALTER TABLE merchants
+    DROP COLUMN merchant_secret;
@@ -0,0 +1,20 @@
+DELETE FROM role_permissions
+WHERE permission_id IN (SELECT ID FROM permissions WHERE permission_name = 'audit.view');
+
+DELETE FROM permissions WHERE permission_name = 'audit.view';
+
+DROP INDEX IF EXISTS idx_histories_operations_target;
+DROP INDEX IF EXISTS idx_histories_operations_request_id;
+DROP INDEX IF EXISTS idx_histories_operations_username;
+DROP INDEX IF EXISTS idx_histories_operations_created_at;
+
+-- json payloads don't fit the old columns, they are cut to 255 characters
+ALTER TABLE histories_operations
+    DROP COLUMN response_code,
+    DROP COLUMN request_id,
+    DROP COLUMN ip_address,
+    DROP COLUMN target_id,
+    DROP COLUMN changes,
+    ALTER COLUMN username TYPE VARCHAR(50) USING LEFT(username, 50),
+    ALTER COLUMN payload_after TYPE VARCHAR(255) USING LEFT(CASE WHEN jsonb_typeof(payload_after) = 'string' THEN payload_after #>> '{}' ELSE payload_after::TEXT END, 255),
+    ALTER COLUMN payload_before TYPE VARCHAR(255) USING LEFT(CASE WHEN jsonb_typeof(payload_before) = 'string' THEN payload_before #>> '{}' ELSE payload_before::TEXT END, 255);
@@ -0,0 +1,27 @@
+-- the operation history becomes the audit trail: before and after are kept as json with the fields that
+-- changed, and every row knows who did it, from where and in which request
+ALTER TABLE histories_operations
+    ALTER COLUMN payload_before TYPE JSONB USING CASE WHEN payload_before IS NULL OR payload_before = '' THEN NULL ELSE to_jsonb(payload_before) END,
+    ALTER COLUMN payload_after TYPE JSONB USING CASE WHEN payload_after IS NULL OR payload_after = '' THEN NULL ELSE to_jsonb(payload_after) END,
+    ALTER COLUMN username TYPE VARCHAR(255),
+    ADD COLUMN changes JSONB,
+    ADD COLUMN target_id VARCHAR(255),
+    ADD COLUMN ip_address VARCHAR(64),
+    ADD COLUMN request_id VARCHAR(64),
+    ADD COLUMN response_code INT;
+
+CREATE INDEX idx_histories_operations_created_at ON histories_operations (created_at);
+CREATE INDEX idx_histories_operations_username ON histories_operations (username, created_at);
+CREATE INDEX idx_histories_operations_request_id ON histories_operations (request_id);
+CREATE INDEX idx_histories_operations_target ON histories_operations (history_type, target_id);
+
+INSERT INTO permissions (permission_name, permission_desc)
+VALUES ('audit.view', 'search the audit log of operator actions')
+ON CONFLICT (permission_name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.ID, p.ID
+FROM roles r
+JOIN permissions p ON p.permission_name = 'audit.view'
+WHERE r.role_name = 'admin'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,25 @@
+DROP TABLE IF EXISTS chain_checkpoint_tails;
+DROP TABLE IF EXISTS chain_checkpoints;
+
+DROP TRIGGER IF EXISTS histories_operations_chain ON histories_operations;
+DROP TRIGGER IF EXISTS merchant_capital_flows_chain ON merchant_capital_flows;
+
+DROP FUNCTION IF EXISTS chain_audit();
+DROP FUNCTION IF EXISTS chain_capital_flow();
+
+DROP INDEX IF EXISTS idx_histories_operations_chain;
+DROP INDEX IF EXISTS idx_merchant_capital_flows_chain;
+
+DROP FUNCTION IF EXISTS audit_chain_hash(histories_operations);
+DROP FUNCTION IF EXISTS capital_flow_chain_hash(merchant_capital_flows);
+DROP FUNCTION IF EXISTS chain_field(TEXT);
+
+ALTER TABLE histories_operations
+    DROP COLUMN hash,
+    DROP COLUMN prev_hash,
+    DROP COLUMN chain_seq;
+
+ALTER TABLE merchant_capital_flows
+    DROP COLUMN hash,
+    DROP COLUMN prev_hash,
+    DROP COLUMN chain_seq;
@@ -0,0 +1,168 @@
+-- capital flows and the audit trail are hash chained, every row keeps its position in its chain, the hash of
+-- the row before it and a hash of its own content. Capital flows are chained per merchant account, the audit
+-- trail is one chain. The hashes are computed by the triggers below so rows written by any service are chained,
+-- internal/pkg/hashchain recomputes them to verify the chains.
+ALTER TABLE merchant_capital_flows
+    ADD COLUMN chain_seq BIGINT,
+    ADD COLUMN prev_hash VARCHAR(64),
+    ADD COLUMN hash VARCHAR(64);
+
+ALTER TABLE histories_operations
+    ADD COLUMN chain_seq BIGINT,
+    ADD COLUMN prev_hash VARCHAR(64),
+    ADD COLUMN hash VARCHAR(64);
+
+-- chain_field encodes one hashed value as <byte length>:<value>, NULL as -
+CREATE FUNCTION chain_field(value TEXT) RETURNS TEXT AS $$
+    SELECT COALESCE(octet_length(value)::TEXT || ':' || value, '-');
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- the fields and their order must match capitalFlowChainFields in internal/repository/psql/hash_chain_reads.go
+CREATE FUNCTION capital_flow_chain_hash(mcf merchant_capital_flows) RETURNS VARCHAR(64) AS $$
+    SELECT encode(sha256(convert_to(
+        chain_field(mcf.prev_hash) ||
+        chain_field(mcf.ID::TEXT) ||
+        chain_field(mcf.chain_seq::TEXT) ||
+        chain_field(mcf.payment_id) ||
+        chain_field(mcf.merchant_account_id::TEXT) ||
+        chain_field(mcf.temp_balance::TEXT) ||
+        chain_field(mcf.amount::TEXT) ||
+        chain_field(mcf.reason_id::TEXT) ||
+        chain_field(mcf.status) ||
+        chain_field(mcf.capital_type) ||
+        chain_field(mcf.notes) ||
+        chain_field(mcf.reverse_from) ||
+        chain_field(mcf.created_by) ||
+        chain_field(to_char(mcf.created_at, 'YYYY-MM-DD"T"HH24:MI:SS.US')),
+    'UTF8')), 'hex');
+$$ LANGUAGE SQL STABLE;
+
+-- the fields and their order must match auditChainFields in internal/repository/psql/hash_chain_reads.go
+CREATE FUNCTION audit_chain_hash(ho histories_operations) RETURNS VARCHAR(64) AS $$
+    SELECT encode(sha256(convert_to(
+        chain_field(ho.prev_hash) ||
+        chain_field(ho.ID::TEXT) ||
+        chain_field(ho.chain_seq::TEXT) ||
+        chain_field(ho.history_type) ||
+        chain_field(ho.activity) ||
+        chain_field(ho.target_id) ||
+        chain_field(ho.payload_before::TEXT) ||
+        chain_field(ho.payload_after::TEXT) ||
+        chain_field(ho.changes::TEXT) ||
+        chain_field(ho.username) ||
+        chain_field(ho.ip_address) ||
+        chain_field(ho.request_id) ||
+        chain_field(ho.response_code::TEXT) ||
+        chain_field(to_char(ho.created_at, 'YYYY-MM-DD"T"HH24:MI:SS.US')),
+    'UTF8')), 'hex');
+$$ LANGUAGE SQL STABLE;
+
+-- chain existing rows in id order
+DO $$
+DECLARE
+    mcf merchant_capital_flows;
+    ho histories_operations;
+    last_account INT;
+    last_seq BIGINT := 0;
+    last_hash VARCHAR(64);
+BEGIN
+    FOR mcf IN SELECT * FROM merchant_capital_flows ORDER BY merchant_account_id, ID LOOP
+        IF last_account IS DISTINCT FROM mcf.merchant_account_id THEN
+            last_account := mcf.merchant_account_id;
+            last_seq := 0;
+            last_hash := NULL;
+        END IF;
+
+        last_seq := last_seq + 1;
+        mcf.chain_seq := last_seq;
+        mcf.prev_hash := last_hash;
+        last_hash := capital_flow_chain_hash(mcf);
+
+        UPDATE merchant_capital_flows SET chain_seq = mcf.chain_seq, prev_hash = mcf.prev_hash, hash = last_hash WHERE ID = mcf.ID;
+    END LOOP;
+
+    last_seq := 0;
+    last_hash := NULL;
+    FOR ho IN SELECT * FROM histories_operations ORDER BY ID LOOP
+        last_seq := last_seq + 1;
+        ho.chain_seq := last_seq;
+        ho.prev_hash := last_hash;
+        last_hash := audit_chain_hash(ho);
+
+        UPDATE histories_operations SET chain_seq = ho.chain_seq, prev_hash = ho.prev_hash, hash = last_hash WHERE ID = ho.ID;
+    END LOOP;
+END $$;
+
+CREATE UNIQUE INDEX idx_merchant_capital_flows_chain ON merchant_capital_flows (merchant_account_id, chain_seq);
+CREATE UNIQUE INDEX idx_histories_operations_chain ON histories_operations (chain_seq);
+
+-- the advisory lock is held until the inserting transaction ends, the next row of the chain waits for it so
+-- two rows never link to the same previous row
+CREATE FUNCTION chain_capital_flow() RETURNS TRIGGER AS $$
+DECLARE
+    last_seq BIGINT;
+    last_hash VARCHAR(64);
+BEGIN
+    PERFORM pg_advisory_xact_lock(1701, NEW.merchant_account_id);
+
+    SELECT chain_seq, hash INTO last_seq, last_hash
+    FROM merchant_capital_flows
+    WHERE merchant_account_id = NEW.merchant_account_id AND chain_seq IS NOT NULL
+    ORDER BY chain_seq DESC
+    LIMIT 1;
+
+    NEW.chain_seq := COALESCE(last_seq, 0) + 1;
+    NEW.prev_hash := last_hash;
+    NEW.hash := capital_flow_chain_hash(NEW);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION chain_audit() RETURNS TRIGGER AS $$
+DECLARE
+    last_seq BIGINT;
+    last_hash VARCHAR(64);
+BEGIN
+    PERFORM pg_advisory_xact_lock(1702, 0);
+
+    SELECT chain_seq, hash INTO last_seq, last_hash
+    FROM histories_operations
+    WHERE chain_seq IS NOT NULL
+    ORDER BY chain_seq DESC
+    LIMIT 1;
+
+    NEW.chain_seq := COALESCE(last_seq, 0) + 1;
+    NEW.prev_hash := last_hash;
+    NEW.hash := audit_chain_hash(NEW);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER merchant_capital_flows_chain BEFORE INSERT ON merchant_capital_flows
+    FOR EACH ROW EXECUTE FUNCTION chain_capital_flow();
+
+CREATE TRIGGER histories_operations_chain BEFORE INSERT ON histories_operations
+    FOR EACH ROW EXECUTE FUNCTION chain_audit();
+
+-- a checkpoint keeps the last link of every chain, exported checkpoints prove a chain wasn't rewritten as a
+-- whole after the checkpoint was taken
+CREATE TABLE chain_checkpoints (
+    ID SERIAL PRIMARY KEY,
+    prev_digest VARCHAR(64),
+    digest VARCHAR(64) NOT NULL,
+    total_chains INT NOT NULL DEFAULT 0,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE chain_checkpoint_tails (
+    ID SERIAL PRIMARY KEY,
+    chain_checkpoint_id INT NOT NULL REFERENCES chain_checkpoints(ID) ON DELETE CASCADE,
+    chain VARCHAR(64) NOT NULL,
+    chain_key VARCHAR(255) NOT NULL,
+    chain_seq BIGINT NOT NULL,
+    hash VARCHAR(64) NOT NULL
+);
+
+CREATE INDEX idx_chain_checkpoints_created_at ON chain_checkpoints (created_at);
+CREATE INDEX idx_chain_checkpoint_tails_checkpoint ON chain_checkpoint_tails (chain_checkpoint_id);
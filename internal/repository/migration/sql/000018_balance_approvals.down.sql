@@ -0,0 +1,7 @@
+DELETE FROM role_permissions
+WHERE permission_id IN (SELECT ID FROM permissions WHERE permission_name IN ('balance.approve', 'balance.threshold.manage'));
+
+DELETE FROM permissions WHERE permission_name IN ('balance.approve', 'balance.threshold.manage');
+
+DROP TABLE IF EXISTS balance_operation_requests;
+DROP TABLE IF EXISTS balance_approval_thresholds;
@@ -0,0 +1,52 @@
+-- manual balance operations whose amount reaches the threshold of their type wait for a second user to approve
+-- them, see service/balance_approval.go. Every threshold starts at zero so every operation needs approval.
+CREATE TABLE balance_approval_thresholds (
+    operation_type VARCHAR(32) PRIMARY KEY,
+    min_amount DECIMAL(18,2) NOT NULL DEFAULT 0.00,
+    updated_by VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO balance_approval_thresholds (operation_type, min_amount, updated_by)
+VALUES
+    ('TOP_UP', 0, 'SYSTEM'),
+    ('HOLD', 0, 'SYSTEM'),
+    ('SETTLEMENT', 0, 'SYSTEM'),
+    ('TRANSFER', 0, 'SYSTEM'),
+    ('PAYOUT_SETTLEMENT', 0, 'SYSTEM'),
+    ('REVERSE', 0, 'SYSTEM');
+
+-- payload keeps the operation as it was requested, without the pin
+CREATE TABLE balance_operation_requests (
+    ID SERIAL PRIMARY KEY,
+    operation_type VARCHAR(32) NOT NULL,
+    merchant_id VARCHAR(255),
+    target_merchant_id VARCHAR(255),
+    payment_id VARCHAR(255),
+    amount DECIMAL(18,2) NOT NULL DEFAULT 0.00,
+    notes VARCHAR(255),
+    payload JSONB NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    requested_by VARCHAR(255) NOT NULL,
+    reviewed_by VARCHAR(255),
+    review_notes VARCHAR(255),
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_balance_operation_requests_status ON balance_operation_requests (status, created_at);
+CREATE INDEX idx_balance_operation_requests_merchant_id ON balance_operation_requests (merchant_id);
+
+INSERT INTO permissions (permission_name, permission_desc)
+VALUES
+    ('balance.approve', 'approve or reject manual balance operations of other users'),
+    ('balance.threshold.manage', 'change the amounts from which manual balance operations need approval')
+ON CONFLICT (permission_name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.ID, p.ID
+FROM roles r
+JOIN permissions p ON p.permission_name IN ('balance.approve', 'balance.threshold.manage')
+WHERE r.role_name = 'admin'
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,9 @@
+-- the merchant approver role stays, users and invitations may still hold it
+DELETE FROM role_permissions
+WHERE permission_id IN (SELECT ID FROM permissions WHERE permission_name IN ('disbursement.approve', 'disbursement.approval.manage'));
+
+DELETE FROM permissions WHERE permission_name IN ('disbursement.approve', 'disbursement.approval.manage');
+
+DROP TABLE IF EXISTS disbursement_request_reviews;
+DROP TABLE IF EXISTS disbursement_requests;
+DROP TABLE IF EXISTS disbursement_approval_rules;
@@ -0,0 +1,66 @@
+-- merchants keep their own maker-checker for dashboard disbursements, a disbursement whose amount reaches a rule
+-- of its merchant waits for the number of approvers of the highest rule it reaches, see
+-- service/disbursement_approval.go. A merchant without rules disburses right away.
+CREATE TABLE disbursement_approval_rules (
+    ID SERIAL PRIMARY KEY,
+    merchant_id VARCHAR(255) NOT NULL,
+    min_amount DECIMAL(18,2) NOT NULL,
+    required_approvals INT NOT NULL CHECK (required_approvals > 0),
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (merchant_id, min_amount)
+);
+
+-- nothing is reserved while a request waits, the balance moves once the last approval sends it to the provider
+CREATE TABLE disbursement_requests (
+    ID SERIAL PRIMARY KEY,
+    merchant_id VARCHAR(255) NOT NULL,
+    amount DECIMAL(18,2) NOT NULL,
+    bank_name VARCHAR(255) NOT NULL,
+    bank_account_name VARCHAR(255) NOT NULL,
+    bank_account_number VARCHAR(255) NOT NULL,
+    note VARCHAR(255),
+    required_approvals INT NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    payment_id VARCHAR(255),
+    failure_reason VARCHAR(255),
+    requested_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_disbursement_requests_merchant_id ON disbursement_requests (merchant_id, status, created_at);
+
+-- one review per user, a single rejection rejects the request
+CREATE TABLE disbursement_request_reviews (
+    ID SERIAL PRIMARY KEY,
+    disbursement_request_id INT NOT NULL REFERENCES disbursement_requests(ID) ON DELETE CASCADE,
+    reviewed_by VARCHAR(255) NOT NULL,
+    decision VARCHAR(32) NOT NULL,
+    notes VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (disbursement_request_id, reviewed_by)
+);
+
+INSERT INTO roles (role_name)
+VALUES ('merchant approver')
+ON CONFLICT (role_name) DO NOTHING;
+
+INSERT INTO permissions (permission_name, permission_desc)
+VALUES
+    ('disbursement.approve', 'approve or reject disbursements of other merchant users'),
+    ('disbursement.approval.manage', 'change the amounts and approvers disbursements of the merchant need')
+ON CONFLICT (permission_name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.ID, p.ID
+FROM
+    (
+        VALUES
+            ('admin', 'disbursement.approve'),
+            ('admin', 'disbursement.approval.manage'),
+            ('merchant approver', 'disbursement.approve')
+    ) AS g(role_name, permission_name)
+    JOIN roles r ON r.role_name = g.role_name
+    JOIN permissions p ON p.permission_name = g.permission_name
+ON CONFLICT (role_id, permission_id) DO NOTHING;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS disbursement_batch_rows;
+DROP TABLE IF EXISTS disbursement_batches;
@@ -0,0 +1,39 @@
+-- a batch keeps the rows of an uploaded spreadsheet or csv, every row is validated on upload and only the valid
+-- rows are disbursed once the batch is confirmed, see service/disbursement_batch.go
+CREATE TABLE disbursement_batches (
+    ID SERIAL PRIMARY KEY,
+    merchant_id VARCHAR(255) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    total_rows INT NOT NULL DEFAULT 0,
+    valid_rows INT NOT NULL DEFAULT 0,
+    total_amount DECIMAL(18,2) NOT NULL DEFAULT 0,
+    total_fee DECIMAL(18,2) NOT NULL DEFAULT 0,
+    uploaded_by VARCHAR(255) NOT NULL,
+    confirmed_by VARCHAR(255),
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_disbursement_batches_merchant_id ON disbursement_batches (merchant_id, created_at);
+
+-- amount stays empty when the file holds something that isn't an amount, the row is invalid then
+CREATE TABLE disbursement_batch_rows (
+    ID SERIAL PRIMARY KEY,
+    disbursement_batch_id INT NOT NULL REFERENCES disbursement_batches(ID) ON DELETE CASCADE,
+    row_number INT NOT NULL,
+    bank_code VARCHAR(255) NOT NULL,
+    bank_name VARCHAR(255),
+    bank_account_name VARCHAR(255) NOT NULL,
+    bank_account_number VARCHAR(255) NOT NULL,
+    amount DECIMAL(18,2),
+    fee DECIMAL(18,2) NOT NULL DEFAULT 0,
+    note VARCHAR(255),
+    status VARCHAR(32) NOT NULL,
+    error_message VARCHAR(255),
+    payment_id VARCHAR(255),
+    disbursement_request_id INT REFERENCES disbursement_requests(ID),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (disbursement_batch_id, row_number)
+);
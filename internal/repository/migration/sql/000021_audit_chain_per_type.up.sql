@@ -0,0 +1,33 @@
+-- the audit trail was one chain, its advisory lock is held until the inserting transaction commits so every
+-- audited operation waited for the one before it, including the request rows written on every mutating
+-- request. New rows are chained per history_type instead, only rows of the same type still wait for each
+-- other and that wait lasts as long as the unit of work that writes the row. The rows chained so far stay in
+-- the chain keyed 'all', the key constant.ChainAuditKey and the checkpoints taken before already use.
+ALTER TABLE histories_operations
+    ADD COLUMN chain_key VARCHAR(50);
+
+UPDATE histories_operations SET chain_key = 'all' WHERE chain_seq IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_histories_operations_chain;
+CREATE UNIQUE INDEX idx_histories_operations_chain ON histories_operations (chain_key, chain_seq);
+
+CREATE OR REPLACE FUNCTION chain_audit() RETURNS TRIGGER AS $$
+DECLARE
+    last_seq BIGINT;
+    last_hash VARCHAR(64);
+BEGIN
+    NEW.chain_key := NEW.history_type;
+    PERFORM pg_advisory_xact_lock(1702, hashtext(NEW.chain_key));
+
+    SELECT chain_seq, hash INTO last_seq, last_hash
+    FROM histories_operations
+    WHERE chain_key = NEW.chain_key AND chain_seq IS NOT NULL
+    ORDER BY chain_seq DESC
+    LIMIT 1;
+
+    NEW.chain_seq := COALESCE(last_seq, 0) + 1;
+    NEW.prev_hash := last_hash;
+    NEW.hash := audit_chain_hash(NEW);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
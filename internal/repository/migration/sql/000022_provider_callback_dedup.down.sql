@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_provider_callbacks_dedup_key;
+
+CREATE INDEX idx_provider_callbacks_dedup_key ON provider_callbacks (provider_id, dedup_key);
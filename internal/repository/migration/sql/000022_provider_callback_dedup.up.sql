@@ -0,0 +1,12 @@
+-- the dedup key was only indexed, a callback the provider retried was stored and processed again. Keys stored
+-- more than once keep the first row, the later rows are suffixed with their id so the key can be made unique.
+UPDATE provider_callbacks pc
+SET dedup_key = pc.dedup_key || ':' || pc.ID
+WHERE EXISTS (
+    SELECT 1 FROM provider_callbacks earlier
+    WHERE earlier.provider_id = pc.provider_id AND earlier.dedup_key = pc.dedup_key AND earlier.ID < pc.ID
+);
+
+DROP INDEX IF EXISTS idx_provider_callbacks_dedup_key;
+
+CREATE UNIQUE INDEX idx_provider_callbacks_dedup_key ON provider_callbacks (provider_id, dedup_key);
@@ -0,0 +1,6 @@
+-- roles are shared by operations staff and merchant users, only the roles flagged here may be given to a
+-- merchant user. The seeded roles are the ones merchant users were given so far.
+ALTER TABLE roles
+    ADD COLUMN merchant_assignable BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE roles SET merchant_assignable = TRUE WHERE role_name IN ('admin', 'finance', 'customer support', 'merchant approver');
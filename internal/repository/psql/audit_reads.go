@@ -0,0 +1,103 @@
+package psql
+
+import (
+	"database/sql"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
+	"github.com/jmoiron/sqlx"
+)
+
+type AuditReads struct {
+	db *sqlx.DB
+}
+
+func NewAuditReads(db *sqlx.DB) *AuditReads {
+	return &AuditReads{
+		db: db,
+	}
+}
+
+func (ar *AuditReads) GetListAuditLogRepo(params dto.QueryParamsAuditLog) ([]entity.AuditLog, dto.PaginatedResponse, error) {
+	var auditLogs []entity.AuditLog
+	var pagination dto.PaginatedResponse
+	pageSizeInt := converter.ToInt(params.PageSize)
+	pageInt := converter.ToInt(params.Page)
+
+	// Set default values for pagination if not provided
+	if pageInt < 1 {
+		pageInt = 1
+	}
+	if pageSizeInt < 1 {
+		pageSizeInt = 50 // Default page size
+	}
+
+	query := `
+	SELECT
+		ho.ID,
+		ho.history_type,
+		ho.activity,
+		ho.target_id,
+		ho.payload_before,
+		ho.payload_after,
+		ho.changes,
+		ho.username,
+		ho.ip_address,
+		ho.request_id,
+		ho.response_code,
+		ho.created_at
+	FROM
+		histories_operations ho
+	`
+
+	query, args := auditLogListFilter(params).buildPage(query, "WHERE", "ORDER BY ho.created_at DESC, ho.ID DESC", pageInt, pageSizeInt)
+	err := ar.db.Select(&auditLogs, query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, pagination, err
+	}
+
+	countQueryAuditLog, countArgs := buildCountQueryAuditLog(params)
+	var totalItems int
+	err = ar.db.Get(&totalItems, countQueryAuditLog, countArgs...)
+	if err != nil && err != sql.ErrNoRows {
+		return auditLogs, pagination, err
+	}
+
+	// Calculate total pages
+	totalPages := (totalItems + pageSizeInt - 1) / pageSizeInt
+	pagination = dto.PaginatedResponse{
+		CurrentPage: pageInt,
+		PageSize:    pageSizeInt,
+		TotalItems:  totalItems,
+		TotalPages:  totalPages,
+		HasNextPage: pageInt < totalPages,
+		HasPrevPage: pageInt > 1,
+	}
+
+	return auditLogs, pagination, nil
+}
+
+func auditLogListFilter(params dto.QueryParamsAuditLog) *queryFilter {
+	return newQueryFilter().
+		from("ho.created_at", params.MinDate).
+		until("ho.created_at", params.MaxDate).
+		in("ho.username", params.Username).
+		in("ho.history_type", params.HistoryType).
+		in("ho.activity", params.Activity).
+		equal("ho.target_id", params.TargetId).
+		equal("ho.request_id", params.RequestId).
+		equal("ho.ip_address", params.IpAddress).
+		search(params.Search, "ho.activity", "ho.target_id", "ho.changes::TEXT", "ho.payload_after::TEXT")
+}
+
+func buildCountQueryAuditLog(params dto.QueryParamsAuditLog) (string, []interface{}) {
+	query := `
+	SELECT
+		COUNT(*)
+	FROM
+		histories_operations ho
+	`
+
+	return auditLogListFilter(params).build(query, "WHERE")
+}
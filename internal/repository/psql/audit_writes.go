@@ -0,0 +1,61 @@
+package psql
+
+import (
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/jmoiron/sqlx"
+)
+
+type AuditWrites struct {
+	db executor
+}
+
+func NewAuditWrites(db *sqlx.DB) *AuditWrites {
+	return &AuditWrites{
+		db: db,
+	}
+}
+
+func (aw *AuditWrites) CreateAuditLogRepo(payload dto.CreateAuditLogPayload) (int, error) {
+	var id int
+
+	query := `
+	INSERT INTO histories_operations (
+		history_type, activity, target_id, payload_before, payload_after, changes, username, ip_address, request_id,
+		response_code, created_at, updated_at
+	)
+	VALUES (
+		$1, $2, NULLIF($3, ''), $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''), NULLIF($10, 0),
+		CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	)
+	RETURNING id
+	`
+
+	row := aw.db.QueryRow(
+		query,
+		payload.HistoryType,
+		payload.Activity,
+		payload.TargetId,
+		jsonParam(payload.PayloadBefore),
+		jsonParam(payload.PayloadAfter),
+		jsonParam(payload.Changes),
+		payload.Username,
+		payload.IpAddress,
+		payload.RequestId,
+		payload.ResponseCode,
+	)
+	err := row.Scan(&id)
+	if err != nil || id == 0 {
+		return id, err
+	}
+
+	return id, nil
+}
+
+// jsonParam passes a json document as text, the driver would send a []byte as bytea
+func jsonParam(document []byte) interface{} {
+	if document == nil {
+		return nil
+	}
+
+	return string(document)
+}
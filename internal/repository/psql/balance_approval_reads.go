@@ -0,0 +1,148 @@
+package psql
+
+import (
+	"database/sql"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
+	"github.com/jmoiron/sqlx"
+)
+
+type BalanceApprovalReads struct {
+	db *sqlx.DB
+}
+
+func NewBalanceApprovalReads(db *sqlx.DB) *BalanceApprovalReads {
+	return &BalanceApprovalReads{
+		db: db,
+	}
+}
+
+func (br *BalanceApprovalReads) GetBalanceApprovalThresholdRepo(operationType string) (entity.BalanceApprovalThreshold, error) {
+	var threshold entity.BalanceApprovalThreshold
+
+	query := `
+	SELECT
+		*
+	FROM
+		balance_approval_thresholds
+	WHERE
+		operation_type = $1
+	`
+
+	err := br.db.Get(&threshold, query, operationType)
+	if err != nil {
+		return threshold, err
+	}
+
+	return threshold, nil
+}
+
+func (br *BalanceApprovalReads) GetListBalanceApprovalThresholdRepo() ([]entity.BalanceApprovalThreshold, error) {
+	var thresholds []entity.BalanceApprovalThreshold
+
+	query := `
+	SELECT
+		*
+	FROM
+		balance_approval_thresholds
+	ORDER BY operation_type
+	`
+
+	err := br.db.Select(&thresholds, query)
+	if err != nil {
+		return thresholds, err
+	}
+
+	return thresholds, nil
+}
+
+func (br *BalanceApprovalReads) GetBalanceOperationRequestByIdRepo(requestId int) (entity.BalanceOperationRequest, error) {
+	var request entity.BalanceOperationRequest
+
+	query := `
+	SELECT
+		*
+	FROM
+		balance_operation_requests
+	WHERE
+		ID = $1
+	`
+
+	err := br.db.Get(&request, query, requestId)
+	if err != nil {
+		return request, err
+	}
+
+	return request, nil
+}
+
+func (br *BalanceApprovalReads) GetListBalanceOperationRequestRepo(params dto.QueryParamsBalanceOperationRequest) ([]entity.BalanceOperationRequest, dto.PaginatedResponse, error) {
+	var requests []entity.BalanceOperationRequest
+	var pagination dto.PaginatedResponse
+	pageSizeInt := converter.ToInt(params.PageSize)
+	pageInt := converter.ToInt(params.Page)
+
+	// Set default values for pagination if not provided
+	if pageInt < 1 {
+		pageInt = 1
+	}
+	if pageSizeInt < 1 {
+		pageSizeInt = 50 // Default page size
+	}
+
+	query := `
+	SELECT
+		*
+	FROM
+		balance_operation_requests bor
+	`
+
+	query, args := balanceOperationRequestListFilter(params).buildPage(query, "WHERE", "ORDER BY bor.created_at DESC, bor.ID DESC", pageInt, pageSizeInt)
+	err := br.db.Select(&requests, query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, pagination, err
+	}
+
+	countQuery, countArgs := buildCountQueryBalanceOperationRequest(params)
+	var totalItems int
+	err = br.db.Get(&totalItems, countQuery, countArgs...)
+	if err != nil && err != sql.ErrNoRows {
+		return requests, pagination, err
+	}
+
+	// Calculate total pages
+	totalPages := (totalItems + pageSizeInt - 1) / pageSizeInt
+	pagination = dto.PaginatedResponse{
+		CurrentPage: pageInt,
+		PageSize:    pageSizeInt,
+		TotalItems:  totalItems,
+		TotalPages:  totalPages,
+		HasNextPage: pageInt < totalPages,
+		HasPrevPage: pageInt > 1,
+	}
+
+	return requests, pagination, nil
+}
+
+func balanceOperationRequestListFilter(params dto.QueryParamsBalanceOperationRequest) *queryFilter {
+	return newQueryFilter().
+		from("bor.created_at", params.MinDate).
+		until("bor.created_at", params.MaxDate).
+		in("bor.status", params.Status).
+		in("bor.operation_type", params.OperationType).
+		equal("bor.merchant_id", params.MerchantId).
+		equal("bor.requested_by", params.RequestedBy)
+}
+
+func buildCountQueryBalanceOperationRequest(params dto.QueryParamsBalanceOperationRequest) (string, []interface{}) {
+	query := `
+	SELECT
+		COUNT(*)
+	FROM
+		balance_operation_requests bor
+	`
+
+	return balanceOperationRequestListFilter(params).build(query, "WHERE")
+}
@@ -0,0 +1,110 @@
+package psql
+
+import (
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+type BalanceApprovalWrites struct {
+	db executor
+}
+
+func NewBalanceApprovalWrites(db *sqlx.DB) *BalanceApprovalWrites {
+	return &BalanceApprovalWrites{
+		db: db,
+	}
+}
+
+func (bw *BalanceApprovalWrites) CreateBalanceOperationRequestRepo(payload dto.CreateBalanceOperationRequestPayload) (int, error) {
+	var requestId int
+
+	query := `
+	INSERT INTO balance_operation_requests (
+		operation_type, merchant_id, target_merchant_id, payment_id, amount, notes, payload, status, requested_by,
+		created_at, updated_at
+	)
+	VALUES (
+		$1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''), $5, NULLIF($6, ''), $7, $8, $9,
+		CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	)
+	RETURNING id
+	`
+
+	row := bw.db.QueryRow(
+		query,
+		payload.OperationType,
+		payload.MerchantId,
+		payload.TargetMerchantId,
+		payload.PaymentId,
+		payload.Amount,
+		payload.Notes,
+		jsonParam(payload.Payload),
+		constant.BalanceRequestPendingApproval,
+		payload.RequestedBy,
+	)
+	err := row.Scan(&requestId)
+	if err != nil || requestId == 0 {
+		return requestId, err
+	}
+
+	return requestId, nil
+}
+
+func (bw *BalanceApprovalWrites) GetBalanceOperationRequestForUpdateRepo(requestId int) (entity.BalanceOperationRequest, error) {
+	var request entity.BalanceOperationRequest
+
+	query := `
+	SELECT
+		*
+	FROM
+		balance_operation_requests
+	WHERE
+		ID = $1
+	FOR UPDATE
+	`
+
+	err := bw.db.Get(&request, query, requestId)
+	if err != nil {
+		return request, err
+	}
+
+	return request, nil
+}
+
+func (bw *BalanceApprovalWrites) ReviewBalanceOperationRequestRepo(requestId int, status string, reviewedBy string, reviewNotes string) error {
+	query := `
+	UPDATE balance_operation_requests
+	SET status = $1,
+		reviewed_by = $2,
+		review_notes = $3,
+		reviewed_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta',
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE ID = $4
+	`
+
+	_, err := bw.db.Exec(query, status, reviewedBy, reviewNotes, requestId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (bw *BalanceApprovalWrites) UpdateBalanceApprovalThresholdRepo(payload dto.UpdateBalanceApprovalThresholdReq) (int64, error) {
+	query := `
+	UPDATE balance_approval_thresholds
+	SET min_amount = $1,
+		updated_by = $2,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE operation_type = $3
+	`
+
+	result, err := bw.db.Exec(query, payload.MinAmount, payload.Username, payload.OperationType)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
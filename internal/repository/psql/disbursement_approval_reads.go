@@ -0,0 +1,176 @@
+package psql
+
+import (
+	"database/sql"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+	"github.com/jmoiron/sqlx"
+)
+
+type DisbursementApprovalReads struct {
+	db *sqlx.DB
+}
+
+func NewDisbursementApprovalReads(db *sqlx.DB) *DisbursementApprovalReads {
+	return &DisbursementApprovalReads{
+		db: db,
+	}
+}
+
+func (dr *DisbursementApprovalReads) GetListDisbursementApprovalRuleRepo(merchantId string) ([]entity.DisbursementApprovalRule, error) {
+	var rules []entity.DisbursementApprovalRule
+
+	query := `
+	SELECT
+		*
+	FROM
+		disbursement_approval_rules
+	WHERE
+		merchant_id = $1
+	ORDER BY min_amount
+	`
+
+	err := dr.db.Select(&rules, query, merchantId)
+	if err != nil {
+		return rules, err
+	}
+
+	return rules, nil
+}
+
+// GetDisbursementApprovalRuleForAmountRepo returns the highest rule of the merchant amount reaches
+func (dr *DisbursementApprovalReads) GetDisbursementApprovalRuleForAmountRepo(merchantId string, amount money.Money) (entity.DisbursementApprovalRule, error) {
+	var rule entity.DisbursementApprovalRule
+
+	query := `
+	SELECT
+		*
+	FROM
+		disbursement_approval_rules
+	WHERE
+		merchant_id = $1
+		AND min_amount <= $2
+	ORDER BY min_amount DESC
+	LIMIT 1
+	`
+
+	err := dr.db.Get(&rule, query, merchantId, amount)
+	if err != nil {
+		return rule, err
+	}
+
+	return rule, nil
+}
+
+func (dr *DisbursementApprovalReads) GetDisbursementRequestByIdRepo(requestId int, merchantId string) (entity.DisbursementRequest, error) {
+	var request entity.DisbursementRequest
+
+	query := `
+	SELECT
+		*
+	FROM
+		disbursement_requests
+	WHERE
+		ID = $1
+		AND merchant_id = $2
+	`
+
+	err := dr.db.Get(&request, query, requestId, merchantId)
+	if err != nil {
+		return request, err
+	}
+
+	return request, nil
+}
+
+func (dr *DisbursementApprovalReads) GetListDisbursementRequestReviewRepo(requestId int) ([]entity.DisbursementRequestReview, error) {
+	var reviews []entity.DisbursementRequestReview
+
+	query := `
+	SELECT
+		*
+	FROM
+		disbursement_request_reviews
+	WHERE
+		disbursement_request_id = $1
+	ORDER BY created_at, ID
+	`
+
+	err := dr.db.Select(&reviews, query, requestId)
+	if err != nil {
+		return reviews, err
+	}
+
+	return reviews, nil
+}
+
+func (dr *DisbursementApprovalReads) GetListDisbursementRequestRepo(params dto.QueryParamsDisbursementRequest) ([]entity.DisbursementRequest, dto.PaginatedResponse, error) {
+	var requests []entity.DisbursementRequest
+	var pagination dto.PaginatedResponse
+	pageSizeInt := converter.ToInt(params.PageSize)
+	pageInt := converter.ToInt(params.Page)
+
+	// Set default values for pagination if not provided
+	if pageInt < 1 {
+		pageInt = 1
+	}
+	if pageSizeInt < 1 {
+		pageSizeInt = 50 // Default page size
+	}
+
+	query := `
+	SELECT
+		*
+	FROM
+		disbursement_requests dr
+	`
+
+	query, args := disbursementRequestListFilter(params).buildPage(query, "WHERE", "ORDER BY dr.created_at DESC, dr.ID DESC", pageInt, pageSizeInt)
+	err := dr.db.Select(&requests, query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, pagination, err
+	}
+
+	countQuery, countArgs := buildCountQueryDisbursementRequest(params)
+	var totalItems int
+	err = dr.db.Get(&totalItems, countQuery, countArgs...)
+	if err != nil && err != sql.ErrNoRows {
+		return requests, pagination, err
+	}
+
+	// Calculate total pages
+	totalPages := (totalItems + pageSizeInt - 1) / pageSizeInt
+	pagination = dto.PaginatedResponse{
+		CurrentPage: pageInt,
+		PageSize:    pageSizeInt,
+		TotalItems:  totalItems,
+		TotalPages:  totalPages,
+		HasNextPage: pageInt < totalPages,
+		HasPrevPage: pageInt > 1,
+	}
+
+	return requests, pagination, nil
+}
+
+func disbursementRequestListFilter(params dto.QueryParamsDisbursementRequest) *queryFilter {
+	return newQueryFilter().
+		equal("dr.merchant_id", params.MerchantId).
+		from("dr.created_at", params.MinDate).
+		until("dr.created_at", params.MaxDate).
+		in("dr.status", params.Status).
+		equal("dr.requested_by", params.RequestedBy)
+}
+
+func buildCountQueryDisbursementRequest(params dto.QueryParamsDisbursementRequest) (string, []interface{}) {
+	query := `
+	SELECT
+		COUNT(*)
+	FROM
+		disbursement_requests dr
+	`
+
+	return disbursementRequestListFilter(params).build(query, "WHERE")
+}
@@ -0,0 +1,202 @@
+package psql
+
+import (
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+type DisbursementApprovalWrites struct {
+	db executor
+}
+
+func NewDisbursementApprovalWrites(db *sqlx.DB) *DisbursementApprovalWrites {
+	return &DisbursementApprovalWrites{
+		db: db,
+	}
+}
+
+func (dw *DisbursementApprovalWrites) CreateDisbursementRequestRepo(payload dto.CreateDisbursementRequestPayload) (int, error) {
+	var requestId int
+
+	query := `
+	INSERT INTO disbursement_requests (
+		merchant_id, amount, bank_name, bank_account_name, bank_account_number, note, required_approvals, status,
+		requested_by, created_at, updated_at
+	)
+	VALUES (
+		$1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8,
+		$9, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	)
+	RETURNING id
+	`
+
+	row := dw.db.QueryRow(
+		query,
+		payload.MerchantId,
+		payload.Amount,
+		payload.BankName,
+		payload.BankAccountName,
+		payload.BankAccountNumber,
+		payload.Note,
+		payload.RequiredApprovals,
+		constant.DisbursementRequestPendingApproval,
+		payload.RequestedBy,
+	)
+	err := row.Scan(&requestId)
+	if err != nil || requestId == 0 {
+		return requestId, err
+	}
+
+	return requestId, nil
+}
+
+func (dw *DisbursementApprovalWrites) GetDisbursementRequestForUpdateRepo(requestId int) (entity.DisbursementRequest, error) {
+	var request entity.DisbursementRequest
+
+	query := `
+	SELECT
+		*
+	FROM
+		disbursement_requests
+	WHERE
+		ID = $1
+	FOR UPDATE
+	`
+
+	err := dw.db.Get(&request, query, requestId)
+	if err != nil {
+		return request, err
+	}
+
+	return request, nil
+}
+
+func (dw *DisbursementApprovalWrites) HasReviewedDisbursementRequestRepo(requestId int, username string) (bool, error) {
+	var reviewed bool
+
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM disbursement_request_reviews WHERE disbursement_request_id = $1 AND reviewed_by = $2
+	)
+	`
+
+	err := dw.db.Get(&reviewed, query, requestId, username)
+	if err != nil {
+		return reviewed, err
+	}
+
+	return reviewed, nil
+}
+
+func (dw *DisbursementApprovalWrites) CreateDisbursementRequestReviewRepo(requestId int, reviewedBy string, decision string, notes string) error {
+	query := `
+	INSERT INTO disbursement_request_reviews (disbursement_request_id, reviewed_by, decision, notes, created_at)
+	VALUES ($1, $2, $3, NULLIF($4, ''), CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	`
+
+	_, err := dw.db.Exec(query, requestId, reviewedBy, decision, notes)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dw *DisbursementApprovalWrites) CountDisbursementRequestApprovalsRepo(requestId int) (int, error) {
+	var approvals int
+
+	query := `
+	SELECT
+		COUNT(*)
+	FROM
+		disbursement_request_reviews
+	WHERE
+		disbursement_request_id = $1
+		AND decision = $2
+	`
+
+	err := dw.db.Get(&approvals, query, requestId, constant.DisbursementReviewApproved)
+	if err != nil {
+		return approvals, err
+	}
+
+	return approvals, nil
+}
+
+func (dw *DisbursementApprovalWrites) UpdateDisbursementRequestStatusRepo(requestId int, status string, paymentId string, failureReason string) error {
+	query := `
+	UPDATE disbursement_requests
+	SET status = $1,
+		payment_id = COALESCE(NULLIF($2, ''), payment_id),
+		failure_reason = COALESCE(NULLIF($3, ''), failure_reason),
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE ID = $4
+	`
+
+	_, err := dw.db.Exec(query, status, paymentId, failureReason, requestId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// FailStaleDisbursementRequestsRepo fails the processing requests which didn't change for stale, the instance
+// sending them to the provider stopped or couldn't write how they ended
+func (dw *DisbursementApprovalWrites) FailStaleDisbursementRequestsRepo(stale time.Duration, failureReason string) ([]entity.DisbursementRequest, error) {
+	var requests []entity.DisbursementRequest
+
+	query := `
+	UPDATE disbursement_requests
+	SET status = $1,
+		failure_reason = $2,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE ID IN (
+		SELECT ID
+		FROM disbursement_requests
+		WHERE status = $3
+			AND updated_at <= CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' - make_interval(secs => $4)
+		ORDER BY ID
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING *
+	`
+
+	err := dw.db.Select(&requests, query, constant.DisbursementRequestFailed, failureReason, constant.DisbursementRequestProcessing, stale.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
+
+func (dw *DisbursementApprovalWrites) DeleteDisbursementApprovalRulesRepo(merchantId string) error {
+	query := `
+	DELETE FROM disbursement_approval_rules WHERE merchant_id = $1
+	`
+
+	_, err := dw.db.Exec(query, merchantId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dw *DisbursementApprovalWrites) CreateDisbursementApprovalRuleRepo(merchantId string, rule dto.DisbursementApprovalRuleDto, createdBy string) error {
+	query := `
+	INSERT INTO disbursement_approval_rules (merchant_id, min_amount, required_approvals, created_by, created_at)
+	VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	`
+
+	_, err := dw.db.Exec(query, merchantId, rule.MinAmount, rule.RequiredApprovals, createdBy)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package psql
+
+import (
+	"database/sql"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
+	"github.com/jmoiron/sqlx"
+)
+
+type DisbursementBatchReads struct {
+	db *sqlx.DB
+}
+
+func NewDisbursementBatchReads(db *sqlx.DB) *DisbursementBatchReads {
+	return &DisbursementBatchReads{
+		db: db,
+	}
+}
+
+func (dr *DisbursementBatchReads) GetDisbursementBatchByIdRepo(batchId int, merchantId string) (entity.DisbursementBatch, error) {
+	var batch entity.DisbursementBatch
+
+	query := `
+	SELECT
+		*
+	FROM
+		disbursement_batches
+	WHERE
+		ID = $1
+		AND merchant_id = $2
+	`
+
+	err := dr.db.Get(&batch, query, batchId, merchantId)
+	if err != nil {
+		return batch, err
+	}
+
+	return batch, nil
+}
+
+func (dr *DisbursementBatchReads) GetListDisbursementBatchRowRepo(batchId int, status string) ([]entity.DisbursementBatchRow, error) {
+	var rows []entity.DisbursementBatchRow
+
+	query := `
+	SELECT
+		*
+	FROM
+		disbursement_batch_rows dbr
+	`
+
+	query, args := newQueryFilter().
+		where("dbr.disbursement_batch_id = ?", batchId).
+		in("dbr.status", status).
+		build(query, "WHERE")
+	query += `
+	ORDER BY dbr.row_number
+	`
+
+	err := dr.db.Select(&rows, query, args...)
+	if err != nil {
+		return rows, err
+	}
+
+	return rows, nil
+}
+
+func (dr *DisbursementBatchReads) GetListDisbursementBatchRepo(params dto.QueryParamsDisbursementBatch) ([]entity.DisbursementBatch, dto.PaginatedResponse, error) {
+	var batches []entity.DisbursementBatch
+	var pagination dto.PaginatedResponse
+	pageSizeInt := converter.ToInt(params.PageSize)
+	pageInt := converter.ToInt(params.Page)
+
+	// Set default values for pagination if not provided
+	if pageInt < 1 {
+		pageInt = 1
+	}
+	if pageSizeInt < 1 {
+		pageSizeInt = 50 // Default page size
+	}
+
+	query := `
+	SELECT
+		*
+	FROM
+		disbursement_batches db
+	`
+
+	query, args := disbursementBatchListFilter(params).buildPage(query, "WHERE", "ORDER BY db.created_at DESC, db.ID DESC", pageInt, pageSizeInt)
+	err := dr.db.Select(&batches, query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, pagination, err
+	}
+
+	countQuery, countArgs := buildCountQueryDisbursementBatch(params)
+	var totalItems int
+	err = dr.db.Get(&totalItems, countQuery, countArgs...)
+	if err != nil && err != sql.ErrNoRows {
+		return batches, pagination, err
+	}
+
+	// Calculate total pages
+	totalPages := (totalItems + pageSizeInt - 1) / pageSizeInt
+	pagination = dto.PaginatedResponse{
+		CurrentPage: pageInt,
+		PageSize:    pageSizeInt,
+		TotalItems:  totalItems,
+		TotalPages:  totalPages,
+		HasNextPage: pageInt < totalPages,
+		HasPrevPage: pageInt > 1,
+	}
+
+	return batches, pagination, nil
+}
+
+func disbursementBatchListFilter(params dto.QueryParamsDisbursementBatch) *queryFilter {
+	return newQueryFilter().
+		equal("db.merchant_id", params.MerchantId).
+		from("db.created_at", params.MinDate).
+		until("db.created_at", params.MaxDate).
+		in("db.status", params.Status).
+		equal("db.uploaded_by", params.UploadedBy)
+}
+
+func buildCountQueryDisbursementBatch(params dto.QueryParamsDisbursementBatch) (string, []interface{}) {
+	query := `
+	SELECT
+		COUNT(*)
+	FROM
+		disbursement_batches db
+	`
+
+	return disbursementBatchListFilter(params).build(query, "WHERE")
+}
@@ -0,0 +1,271 @@
+package psql
+
+import (
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+type DisbursementBatchWrites struct {
+	db executor
+}
+
+func NewDisbursementBatchWrites(db *sqlx.DB) *DisbursementBatchWrites {
+	return &DisbursementBatchWrites{
+		db: db,
+	}
+}
+
+func (dw *DisbursementBatchWrites) CreateDisbursementBatchRepo(payload dto.CreateDisbursementBatchPayload) (int, error) {
+	var batchId int
+
+	query := `
+	INSERT INTO disbursement_batches (
+		merchant_id, file_name, status, total_rows, valid_rows, total_amount, total_fee, uploaded_by, created_at,
+		updated_at
+	)
+	VALUES (
+		$1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta',
+		CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	)
+	RETURNING id
+	`
+
+	row := dw.db.QueryRow(
+		query,
+		payload.MerchantId,
+		payload.FileName,
+		constant.DisbursementBatchUploaded,
+		payload.TotalRows,
+		payload.ValidRows,
+		payload.TotalAmount,
+		payload.TotalFee,
+		payload.UploadedBy,
+	)
+	err := row.Scan(&batchId)
+	if err != nil || batchId == 0 {
+		return batchId, err
+	}
+
+	return batchId, nil
+}
+
+func (dw *DisbursementBatchWrites) CreateDisbursementBatchRowRepo(batchId int, row dto.DisbursementBatchRowDto) error {
+	query := `
+	INSERT INTO disbursement_batch_rows (
+		disbursement_batch_id, row_number, bank_code, bank_name, bank_account_name, bank_account_number, amount, fee,
+		note, status, error_message, updated_at
+	)
+	VALUES (
+		$1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8,
+		NULLIF($9, ''), $10, NULLIF($11, ''), CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	)
+	`
+
+	_, err := dw.db.Exec(
+		query,
+		batchId,
+		row.RowNumber,
+		row.BankCode,
+		row.BankName,
+		row.BankAccountName,
+		row.BankAccountNumber,
+		row.Amount,
+		row.Fee,
+		row.Note,
+		row.Status,
+		row.ErrorMessage,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dw *DisbursementBatchWrites) GetDisbursementBatchForUpdateRepo(batchId int) (entity.DisbursementBatch, error) {
+	var batch entity.DisbursementBatch
+
+	query := `
+	SELECT
+		*
+	FROM
+		disbursement_batches
+	WHERE
+		ID = $1
+	FOR UPDATE
+	`
+
+	err := dw.db.Get(&batch, query, batchId)
+	if err != nil {
+		return batch, err
+	}
+
+	return batch, nil
+}
+
+func (dw *DisbursementBatchWrites) ConfirmDisbursementBatchRepo(batchId int, confirmedBy string) error {
+	query := `
+	UPDATE disbursement_batches
+	SET status = $1,
+		confirmed_by = $2,
+		confirmed_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta',
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE ID = $3
+	`
+
+	_, err := dw.db.Exec(query, constant.DisbursementBatchProcessing, confirmedBy, batchId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dw *DisbursementBatchWrites) UpdateDisbursementBatchStatusRepo(batchId int, status string) error {
+	query := `
+	UPDATE disbursement_batches
+	SET status = $1,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE ID = $2
+	`
+
+	_, err := dw.db.Exec(query, status, batchId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dw *DisbursementBatchWrites) UpdateDisbursementBatchRowStatusRepo(rowId int, status string, paymentId string, disbursementRequestId int, errorMessage string) error {
+	query := `
+	UPDATE disbursement_batch_rows
+	SET status = $1,
+		payment_id = COALESCE(NULLIF($2, ''), payment_id),
+		disbursement_request_id = COALESCE(NULLIF($3, 0), disbursement_request_id),
+		error_message = COALESCE(NULLIF($4, ''), error_message),
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE ID = $5
+	`
+
+	_, err := dw.db.Exec(query, status, paymentId, disbursementRequestId, errorMessage, rowId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClaimDisbursementBatchRowRepo moves a valid row to processing, false when the row was already claimed so a row is
+// never disbursed twice by a batch being resumed
+func (dw *DisbursementBatchWrites) ClaimDisbursementBatchRowRepo(rowId int) (bool, error) {
+	query := `
+	UPDATE disbursement_batch_rows
+	SET status = $1,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE ID = $2 AND status = $3
+	`
+
+	result, err := dw.db.Exec(query, constant.DisbursementBatchRowProcessing, rowId, constant.DisbursementBatchRowValid)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// InterruptDisbursementBatchRowsRepo marks the rows of batch left in processing as interrupted
+func (dw *DisbursementBatchWrites) InterruptDisbursementBatchRowsRepo(batchId int) error {
+	query := `
+	UPDATE disbursement_batch_rows
+	SET status = $1,
+		error_message = $2,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE disbursement_batch_id = $3 AND status = $4
+	`
+
+	_, err := dw.db.Exec(query, constant.DisbursementBatchRowInterrupted, constant.DisbursementBatchRowInterruptedMsg, batchId, constant.DisbursementBatchRowProcessing)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CompleteDisbursementBatchRepo completes a processing batch once none of its rows is valid or processing anymore,
+// false when the batch isn't done yet or was completed by someone else
+func (dw *DisbursementBatchWrites) CompleteDisbursementBatchRepo(batchId int) (bool, error) {
+	query := `
+	UPDATE disbursement_batches db
+	SET status = $1,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE db.ID = $2
+		AND db.status = $3
+		AND NOT EXISTS (
+			SELECT 1
+			FROM disbursement_batch_rows dbr
+			WHERE dbr.disbursement_batch_id = db.ID AND dbr.status IN ($4, $5)
+		)
+	`
+
+	result, err := dw.db.Exec(
+		query,
+		constant.DisbursementBatchCompleted,
+		batchId,
+		constant.DisbursementBatchProcessing,
+		constant.DisbursementBatchRowValid,
+		constant.DisbursementBatchRowProcessing,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// ClaimStaleDisbursementBatchesRepo returns the processing batches none of which changed for stale, the batch
+// itself nor any of its rows. Claiming touches the batch so another instance leaves it alone for the next stale.
+func (dw *DisbursementBatchWrites) ClaimStaleDisbursementBatchesRepo(stale time.Duration) ([]entity.DisbursementBatch, error) {
+	var batches []entity.DisbursementBatch
+
+	query := `
+	UPDATE disbursement_batches
+	SET updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE ID IN (
+		SELECT db.ID
+		FROM disbursement_batches db
+		WHERE db.status = $1
+			AND db.updated_at <= CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' - make_interval(secs => $2)
+			AND NOT EXISTS (
+				SELECT 1
+				FROM disbursement_batch_rows dbr
+				WHERE dbr.disbursement_batch_id = db.ID
+					AND dbr.updated_at > CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' - make_interval(secs => $2)
+			)
+		ORDER BY db.ID
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING *
+	`
+
+	err := dw.db.Select(&batches, query, constant.DisbursementBatchProcessing, stale.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	return batches, nil
+}
@@ -0,0 +1,254 @@
+package psql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// capitalFlowChainFields are the values capital_flow_chain_hash hashes after prev_hash, in its order
+var capitalFlowChainFields = []string{
+	"id::TEXT",
+	"chain_seq::TEXT",
+	"payment_id",
+	"merchant_account_id::TEXT",
+	"temp_balance::TEXT",
+	"amount::TEXT",
+	"reason_id::TEXT",
+	"status",
+	"capital_type",
+	"notes",
+	"reverse_from",
+	"created_by",
+	`to_char(created_at, 'YYYY-MM-DD"T"HH24:MI:SS.US')`,
+}
+
+// auditChainFields are the values audit_chain_hash hashes after prev_hash, in its order
+var auditChainFields = []string{
+	"id::TEXT",
+	"chain_seq::TEXT",
+	"history_type",
+	"activity",
+	"target_id",
+	"payload_before::TEXT",
+	"payload_after::TEXT",
+	"changes::TEXT",
+	"username",
+	"ip_address",
+	"request_id",
+	"response_code::TEXT",
+	`to_char(created_at, 'YYYY-MM-DD"T"HH24:MI:SS.US')`,
+}
+
+// chainTailsQuery lists the last link of every chain
+var chainTailsQuery = fmt.Sprintf(`
+(
+	SELECT DISTINCT ON (merchant_account_id)
+		'%v' AS chain,
+		merchant_account_id::TEXT AS chain_key,
+		chain_seq,
+		hash
+	FROM
+		merchant_capital_flows
+	WHERE
+		chain_seq IS NOT NULL
+	ORDER BY merchant_account_id, chain_seq DESC
+)
+UNION ALL
+(
+	SELECT DISTINCT ON (chain_key)
+		'%v' AS chain,
+		chain_key,
+		chain_seq,
+		hash
+	FROM
+		histories_operations
+	WHERE
+		chain_seq IS NOT NULL
+	ORDER BY chain_key, chain_seq DESC
+)
+`, constant.ChainCapitalFlow, constant.ChainAudit)
+
+type HashChainReads struct {
+	db *sqlx.DB
+}
+
+func NewHashChainReads(db *sqlx.DB) *HashChainReads {
+	return &HashChainReads{
+		db: db,
+	}
+}
+
+func (hr *HashChainReads) GetChainTailsRepo() ([]entity.ChainTail, error) {
+	var tails []entity.ChainTail
+
+	err := hr.db.Select(&tails, "SELECT * FROM ("+chainTailsQuery+") tails ORDER BY chain, chain_key")
+	if err != nil {
+		return tails, err
+	}
+
+	return tails, nil
+}
+
+// GetChainLinksRepo returns up to limit links of a chain that come after afterSeq, in chain order
+func (hr *HashChainReads) GetChainLinksRepo(chain string, chainKey string, afterSeq int64, limit int) ([]entity.ChainLink, error) {
+	var links []entity.ChainLink
+	var fields []string
+	var query string
+	var args []interface{}
+
+	switch chain {
+	case constant.ChainCapitalFlow:
+		fields = capitalFlowChainFields
+		query = `
+		SELECT
+			id, chain_seq, prev_hash, hash, %v
+		FROM
+			merchant_capital_flows
+		WHERE
+			merchant_account_id = $1 AND chain_seq > $2
+		ORDER BY chain_seq
+		LIMIT $3
+		`
+		args = []interface{}{chainKey, afterSeq, limit}
+	case constant.ChainAudit:
+		fields = auditChainFields
+		query = `
+		SELECT
+			id, chain_seq, prev_hash, hash, %v
+		FROM
+			histories_operations
+		WHERE
+			chain_key = $1 AND chain_seq > $2
+		ORDER BY chain_seq
+		LIMIT $3
+		`
+		args = []interface{}{chainKey, afterSeq, limit}
+	default:
+		return links, fmt.Errorf("unknown chain %v", chain)
+	}
+
+	rows, err := hr.db.Query(fmt.Sprintf(query, strings.Join(fields, ", ")), args...)
+	if err != nil {
+		return links, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		link := entity.ChainLink{
+			Fields: make([]*string, len(fields)),
+		}
+
+		dest := []interface{}{&link.Id, &link.ChainSeq, &link.PrevHash, &link.Hash}
+		for i := range link.Fields {
+			dest = append(dest, &link.Fields[i])
+		}
+
+		err = rows.Scan(dest...)
+		if err != nil {
+			return links, err
+		}
+
+		links = append(links, link)
+	}
+
+	return links, rows.Err()
+}
+
+// CountUnchainedRowsRepo counts the rows of a chained table that were written without going through the chain
+func (hr *HashChainReads) CountUnchainedRowsRepo(chain string) (int, error) {
+	var total int
+
+	if chain != constant.ChainCapitalFlow && chain != constant.ChainAudit {
+		return total, fmt.Errorf("unknown chain %v", chain)
+	}
+
+	query := fmt.Sprintf(`
+	SELECT
+		COUNT(*)
+	FROM
+		%v
+	WHERE
+		chain_seq IS NULL OR hash IS NULL
+	`, chain)
+
+	err := hr.db.Get(&total, query)
+	if err != nil {
+		return total, err
+	}
+
+	return total, nil
+}
+
+func (hr *HashChainReads) GetLatestChainCheckpointRepo() (entity.ChainCheckpoint, error) {
+	var checkpoint entity.ChainCheckpoint
+
+	query := `
+	SELECT
+		*
+	FROM
+		chain_checkpoints
+	ORDER BY ID DESC
+	LIMIT 1
+	`
+
+	err := hr.db.Get(&checkpoint, query)
+	if err != nil {
+		return checkpoint, err
+	}
+
+	return checkpoint, nil
+}
+
+func (hr *HashChainReads) GetChainCheckpointTailsRepo(checkpointId int) ([]entity.ChainTail, error) {
+	var tails []entity.ChainTail
+
+	query := `
+	SELECT
+		chain,
+		chain_key,
+		chain_seq,
+		hash
+	FROM
+		chain_checkpoint_tails
+	WHERE
+		chain_checkpoint_id = $1
+	ORDER BY ID
+	`
+
+	err := hr.db.Select(&tails, query, checkpointId)
+	if err != nil {
+		return tails, err
+	}
+
+	return tails, nil
+}
+
+func (hr *HashChainReads) GetListChainCheckpointRepo(params dto.QueryParamsChainCheckpoint) ([]entity.ChainCheckpoint, error) {
+	var checkpoints []entity.ChainCheckpoint
+
+	query := `
+	SELECT
+		*
+	FROM
+		chain_checkpoints cc
+	`
+
+	query, args := newQueryFilter().
+		from("cc.created_at", params.MinDate).
+		until("cc.created_at", params.MaxDate).
+		build(query, "WHERE")
+	query += " ORDER BY cc.ID"
+
+	err := hr.db.Select(&checkpoints, query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return checkpoints, err
+	}
+
+	return checkpoints, nil
+}
@@ -0,0 +1,80 @@
+package psql
+
+import (
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+type HashChainWrites struct {
+	db executor
+}
+
+func NewHashChainWrites(db *sqlx.DB) *HashChainWrites {
+	return &HashChainWrites{
+		db: db,
+	}
+}
+
+// GetLatestChainCheckpointForUpdateRepo locks the latest checkpoint so the next one links to it alone
+func (hw *HashChainWrites) GetLatestChainCheckpointForUpdateRepo() (entity.ChainCheckpoint, error) {
+	var checkpoint entity.ChainCheckpoint
+
+	query := `
+	SELECT
+		*
+	FROM
+		chain_checkpoints
+	ORDER BY ID DESC
+	LIMIT 1
+	FOR UPDATE
+	`
+
+	err := hw.db.Get(&checkpoint, query)
+	if err != nil {
+		return checkpoint, err
+	}
+
+	return checkpoint, nil
+}
+
+func (hw *HashChainWrites) GetChainTailsRepo() ([]entity.ChainTail, error) {
+	var tails []entity.ChainTail
+
+	err := hw.db.Select(&tails, chainTailsQuery)
+	if err != nil {
+		return tails, err
+	}
+
+	return tails, nil
+}
+
+func (hw *HashChainWrites) CreateChainCheckpointRepo(payload dto.CreateChainCheckpointPayload) (int, error) {
+	var checkpointId int
+
+	query := `
+	INSERT INTO chain_checkpoints (prev_digest, digest, total_chains, created_by, created_at)
+	VALUES (NULLIF($1, ''), $2, $3, $4, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	RETURNING id
+	`
+
+	row := hw.db.QueryRow(query, payload.PrevDigest, payload.Digest, len(payload.Tails), payload.CreatedBy)
+	err := row.Scan(&checkpointId)
+	if err != nil || checkpointId == 0 {
+		return checkpointId, err
+	}
+
+	query = `
+	INSERT INTO chain_checkpoint_tails (chain_checkpoint_id, chain, chain_key, chain_seq, hash)
+	VALUES ($1, $2, $3, $4, $5)
+	`
+
+	for _, tail := range payload.Tails {
+		_, err = hw.db.Exec(query, checkpointId, tail.Chain, tail.ChainKey, tail.ChainSeq, tail.Hash)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return checkpointId, nil
+}
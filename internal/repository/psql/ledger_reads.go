@@ -0,0 +1,39 @@
+package psql
+
+import (
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+// ledgerBalancesByOwnerQuery sums the postings of every ledger account of an owner
+const ledgerBalancesByOwnerQuery = `
+	SELECT
+		la.account_type,
+		COALESCE(SUM(CASE WHEN lp.direction = 'DEBIT' THEN lp.amount END), 0) AS debit,
+		COALESCE(SUM(CASE WHEN lp.direction = 'CREDIT' THEN lp.amount END), 0) AS credit
+	FROM ledger_accounts la
+	LEFT JOIN ledger_postings lp ON lp.ledger_account_id = la.id
+	WHERE la.owner_type = $1 AND la.owner_id = $2
+	GROUP BY la.account_type;
+	`
+
+type LedgerReads struct {
+	db *sqlx.DB
+}
+
+func NewLedgerReads(db *sqlx.DB) *LedgerReads {
+	return &LedgerReads{
+		db: db,
+	}
+}
+
+func (lr *LedgerReads) GetLedgerBalancesByOwnerRepo(ownerType string, ownerId string) ([]entity.LedgerAccountBalance, error) {
+	var balances []entity.LedgerAccountBalance
+
+	err := lr.db.Select(&balances, ledgerBalancesByOwnerQuery, ownerType, ownerId)
+	if err != nil {
+		return balances, err
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,84 @@
+package psql
+
+import (
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+type LedgerWrites struct {
+	db executor
+}
+
+func NewLedgerWrites(db *sqlx.DB) *LedgerWrites {
+	return &LedgerWrites{
+		db: db,
+	}
+}
+
+func (lw *LedgerWrites) CreateLedgerJournalRepo(payload dto.CreateLedgerJournalPayload) (int, error) {
+	var journalId int
+
+	query := `
+	INSERT INTO ledger_journals (payment_id, reason_id, notes, created_by, created_at)
+	VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	RETURNING id
+	`
+
+	row := lw.db.QueryRow(query, payload.PaymentId, payload.ReasonId, payload.Notes, payload.CreatedBy)
+	err := row.Scan(&journalId)
+	if err != nil || journalId == 0 {
+		return journalId, err
+	}
+
+	for _, posting := range payload.Postings {
+		accountId, err := lw.getOrCreateLedgerAccount(posting.Account)
+		if err != nil {
+			return journalId, err
+		}
+
+		queryPosting := `
+		INSERT INTO ledger_postings (ledger_journal_id, ledger_account_id, direction, amount, created_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+		`
+
+		_, err = lw.db.Exec(queryPosting, journalId, accountId, posting.Direction, posting.Amount)
+		if err != nil {
+			return journalId, err
+		}
+	}
+
+	return journalId, nil
+}
+
+// GetLedgerBalancesByOwnerRepo is LedgerReads.GetLedgerBalancesByOwnerRepo inside the unit of work, so balance
+// checks see the postings made before them in the same transaction
+func (lw *LedgerWrites) GetLedgerBalancesByOwnerRepo(ownerType string, ownerId string) ([]entity.LedgerAccountBalance, error) {
+	var balances []entity.LedgerAccountBalance
+
+	err := lw.db.Select(&balances, ledgerBalancesByOwnerQuery, ownerType, ownerId)
+	if err != nil {
+		return balances, err
+	}
+
+	return balances, nil
+}
+
+func (lw *LedgerWrites) getOrCreateLedgerAccount(account dto.LedgerAccountRef) (int, error) {
+	var accountId int
+
+	query := `
+	INSERT INTO ledger_accounts (owner_type, owner_id, account_type, created_at, updated_at)
+	VALUES ($1, $2, $3, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	ON CONFLICT (owner_type, owner_id, account_type) DO UPDATE SET updated_at = ledger_accounts.updated_at
+	RETURNING id
+	`
+
+	row := lw.db.QueryRow(query, account.OwnerType, account.OwnerId, account.AccountType)
+	err := row.Scan(&accountId)
+	if err != nil || accountId == 0 {
+		return accountId, err
+	}
+
+	return accountId, nil
+}
@@ -2,14 +2,11 @@ package psql
 
 import (
 	"database/sql"
-	"fmt"
-	"strings"
 
 	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
-	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -58,79 +55,15 @@ func (mr *MerchantReads) GetListManualPayment(params dto.QueryParamsManualPaymen
 		mcf.reason_id NOT IN (5,6,7)
 	`
 
-	var conditions []string
-
-	if params.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("mcf.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("mcf.created_at <= '%v'", params.MaxDate))
-	}
-
-	if params.AmountMax != "" {
-		conditions = append(conditions, fmt.Sprintf("mcf.amount <= '%v'", params.AmountMax))
-	}
-
-	if params.AmountMin != "" {
-		conditions = append(conditions, fmt.Sprintf("mcf.amount >= '%v'", params.AmountMin))
-	}
-
-	if params.Status != "" {
-		sliceStatus := helper.SplitString(params.Status)
-		var statusConditions []string
-		for _, status := range sliceStatus {
-			statusConditions = append(statusConditions, fmt.Sprintf("mcf.status = '%v'", status))
-		}
-		conditions = append(conditions, "("+strings.Join(statusConditions, " OR ")+")")
-	}
-
-	if params.ReasonName != "" {
-		sliceReasonName := helper.SplitString(params.ReasonName)
-		var reasonNameConditions []string
-		for _, reasonName := range sliceReasonName {
-			reasonNameConditions = append(reasonNameConditions, fmt.Sprintf("r.reason_name = '%v'", reasonName))
-		}
-		conditions = append(conditions, "("+strings.Join(reasonNameConditions, " OR ")+")")
-	}
-
-	if params.MerchantName != "" {
-		sliceMerchantName := helper.SplitString(params.MerchantName)
-		var merchantNameConditions []string
-		for _, merchantName := range sliceMerchantName {
-			merchantNameConditions = append(merchantNameConditions, fmt.Sprintf("m.merchant_name = '%v'", merchantName))
-		}
-		conditions = append(conditions, "("+strings.Join(merchantNameConditions, " OR ")+")")
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(mcf.payment_id LIKE '%v')", searchStr))
-	}
-
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
-	}
-
-	query += " ORDER BY mcf.created_at DESC"
-
-	if pageSizeInt > 0 {
-		query += fmt.Sprintf(" LIMIT %d", pageSizeInt)
-	}
-
-	if pageInt > 0 {
-		offset := (pageInt - 1) * pageSizeInt
-		query += fmt.Sprintf(" OFFSET %d", offset)
-	}
-
-	err := mr.db.Select(&manualPaymentData, query)
+	query, args := manualPaymentListFilter(params).buildPage(query, "AND", "ORDER BY mcf.created_at DESC", pageInt, pageSizeInt)
+	err := mr.db.Select(&manualPaymentData, query, args...)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, pagination, err
 	}
 
-	countQueryListManualPayment := buildCountQueryManualPayment(params)
+	countQueryListManualPayment, countArgs := buildCountQueryManualPayment(params)
 	var totalItems int
-	err = mr.db.Get(&totalItems, countQueryListManualPayment)
+	err = mr.db.Get(&totalItems, countQueryListManualPayment, countArgs...)
 	if err != nil && err != sql.ErrNoRows {
 		return manualPaymentData, pagination, err
 	}
@@ -195,19 +128,15 @@ func (mr *MerchantReads) GetListMerchantCallbackWithFilter(params dto.QueryParam
 		pageSizeInt = 50 // Default page size
 	}
 
-	query, err := buildListMerchantCallbackQuery(params)
-	if err != nil {
-		return nil, pagination, err
-	}
-
-	err = mr.db.Select(&listMerchantCallback, query)
+	query, args := buildListMerchantCallbackQuery(params)
+	err := mr.db.Select(&listMerchantCallback, query, args...)
 	if err != nil {
 		return nil, pagination, err
 	}
 
-	countQueryListCallback := buildCountQueryListMerchantCallback(params)
+	countQueryListCallback, countArgs := buildCountQueryListMerchantCallback(params)
 	var totalItems int
-	err = mr.db.Get(&totalItems, countQueryListCallback)
+	err = mr.db.Get(&totalItems, countQueryListCallback, countArgs...)
 	if err != nil {
 		return nil, pagination, err
 	}
@@ -238,6 +167,8 @@ func (mr *MerchantReads) GetListMerchantCallback(paymentId string) ([]entity.Mer
     mc.callback_result,
     t.merchant_callback_url AS callback_request,
     mc.triggered_by,
+    mc.attempt_number,
+    mc.next_retry_at,
     mc.created_at
 	FROM 
 		merchant_callbacks mc
@@ -393,22 +324,25 @@ func (mr *MerchantReads) GetMerchantDataByMerchantId(merchantId string) (entity.
 	return merchantData, nil
 }
 
-func (mr *MerchantReads) GetSecretKeyByMerchantIdRepo(merchantId string) (entity.MerchantSecret, error) {
-	var secretKey entity.MerchantSecret
+// GetLiveMerchantKeysRepo returns the keys of merchantId that still sign callbacks, the active key first
+func (mr *MerchantReads) GetLiveMerchantKeysRepo(merchantId string) ([]entity.MerchantKeyEntity, error) {
+	var keys []entity.MerchantKeyEntity
 
 	query := `
-	SELECT 
-		m.merchant_secret
-	FROM merchants m
-	WHERE m.merchant_id = $1;
+	SELECT *
+	FROM merchant_keys mk
+	WHERE mk.merchant_id = $1
+	AND mk.status <> $2
+	AND (mk.retire_at IS NULL OR mk.retire_at > CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	ORDER BY mk.status = $3 DESC, mk.version DESC;
 	`
 
-	err := mr.db.Get(&secretKey, query, merchantId)
+	err := mr.db.Select(&keys, query, merchantId, constant.MerchantKeyRetired, constant.MerchantKeyActive)
 	if err != nil {
-		return secretKey, err
+		return keys, err
 	}
 
-	return secretKey, nil
+	return keys, nil
 }
 
 func (mr *MerchantReads) GetDetailManualPayment(paymentId string) ([]entity.ManualPayment, error) {
@@ -452,38 +386,15 @@ func (mr *MerchantReads) GetListMerchantWithFilterRepo(params dto.QueryParams) (
 	FROM merchants m
 	`
 
-	var conditions []string
-
-	if params.MerchantName != "" {
-		merchantNames := helper.SplitString(params.MerchantName)
-		quotedMerchantNames := make([]string, len(merchantNames))
-		for i, name := range merchantNames {
-			quotedMerchantNames[i] = fmt.Sprintf("'%v'", name)
-		}
-		conditions = append(conditions, fmt.Sprintf("m.merchant_name IN (%v)", strings.Join(quotedMerchantNames, ", ")))
-	}
-
-	if params.Status != "" {
-		statuses := helper.SplitString(params.Status)
-		quotedStatuses := make([]string, len(statuses))
-		for i, status := range statuses {
-			quotedStatuses[i] = fmt.Sprintf("'%v'", status)
-		}
-		conditions = append(conditions, fmt.Sprintf("m.status IN (%v)", strings.Join(quotedStatuses, ", ")))
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(m.merchant_id LIKE '%v' OR m.merchant_name LIKE '%v')", searchStr, searchStr))
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	filter := newQueryFilter().
+		in("m.merchant_name", params.MerchantName).
+		in("m.status", params.Status).
+		search(params.Search, "m.merchant_id", "m.merchant_name")
 
+	query, args := filter.build(query, "WHERE")
 	query += " ORDER BY m.created_at DESC"
 
-	err := mr.db.Select(&merchantList, query)
+	err := mr.db.Select(&merchantList, query, args...)
 	if err != nil {
 		return merchantList, err
 	}
@@ -583,38 +494,15 @@ func (mr *MerchantReads) GetListMerchantAccountRepo(params dto.QueryParams) ([]e
 		JOIN merchants m ON m.merchant_id = ma.merchant_id
 	`
 
-	var conditions []string
-
-	if params.MerchantName != "" {
-		merchantNames := helper.SplitString(params.MerchantName)
-		quotedMerchantNames := make([]string, len(merchantNames))
-		for i, name := range merchantNames {
-			quotedMerchantNames[i] = fmt.Sprintf("'%v'", name)
-		}
-		conditions = append(conditions, fmt.Sprintf("m.merchant_name IN (%v)", strings.Join(quotedMerchantNames, ", ")))
-	}
-
-	if params.Status != "" {
-		statuses := helper.SplitString(params.Status)
-		quotedStatuses := make([]string, len(statuses))
-		for i, status := range statuses {
-			quotedStatuses[i] = fmt.Sprintf("'%v'", status)
-		}
-		conditions = append(conditions, fmt.Sprintf("m.status IN (%v)", strings.Join(quotedStatuses, ", ")))
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(m.merchant_id LIKE '%v' OR m.merchant_name LIKE '%v')", searchStr, searchStr))
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	filter := newQueryFilter().
+		in("m.merchant_name", params.MerchantName).
+		in("m.status", params.Status).
+		search(params.Search, "m.merchant_id", "m.merchant_name")
 
+	query, args := filter.build(query, "WHERE")
 	query += " ORDER BY m.created_at DESC"
 
-	err := mr.db.Select(&listAccount, query)
+	err := mr.db.Select(&listAccount, query, args...)
 	if err != nil {
 		return listAccount, err
 	}
@@ -678,20 +566,9 @@ func (mr *MerchantReads) GetBankListProviderPaymentMethodRepo(routedChannelName
 			JOIN bank_lists bl ON ppmbl.bank_list_id = bl.ID
 	`
 
-	var conditions []string
-
-	if routedChannelName != "" {
-		routedChannelIds := helper.SplitString(routedChannelName)
-		quotedRoutedChannelIds := make([]string, len(routedChannelIds))
-		for i, name := range routedChannelIds {
-			quotedRoutedChannelIds[i] = fmt.Sprintf("'%v'", name)
-		}
-		conditions = append(conditions, fmt.Sprintf("pp.paychannel_name IN (%v)", strings.Join(quotedRoutedChannelIds, ", ")))
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	query, args := newQueryFilter().
+		in("pp.paychannel_name", routedChannelName).
+		build(query, "WHERE")
 
 	query += `
 	)
@@ -704,7 +581,7 @@ func (mr *MerchantReads) GetBankListProviderPaymentMethodRepo(routedChannelName
 		unique_bank_list
 	`
 
-	err := mr.db.Select(&bankList, query)
+	err := mr.db.Select(&bankList, query, args...)
 	if err != nil {
 		return bankList, err
 	}
@@ -725,20 +602,9 @@ func (mr *MerchantReads) GetBankListFromProviderPaychannelRepo(routedChannelName
 			JOIN bank_lists bl ON ppbl.bank_list_id = bl.ID
 	`
 
-	var conditions []string
-
-	if routedChannelName != "" {
-		routedChannelIds := helper.SplitString(routedChannelName)
-		quotedRoutedChannelIds := make([]string, len(routedChannelIds))
-		for i, name := range routedChannelIds {
-			quotedRoutedChannelIds[i] = fmt.Sprintf("'%v'", name)
-		}
-		conditions = append(conditions, fmt.Sprintf("pp.paychannel_name IN (%v)", strings.Join(quotedRoutedChannelIds, ", ")))
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	query, args := newQueryFilter().
+		in("pp.paychannel_name", routedChannelName).
+		build(query, "WHERE")
 
 	query += `
 	)
@@ -748,7 +614,7 @@ func (mr *MerchantReads) GetBankListFromProviderPaychannelRepo(routedChannelName
 		unique_bank_list
 	`
 
-	err := mr.db.Select(&bankName, query)
+	err := mr.db.Select(&bankName, query, args...)
 	if err != nil {
 		return bankName, err
 	}
@@ -773,20 +639,9 @@ func (mr *MerchantReads) GetBankListForDisbursementRepo(routedChannelName string
 			JOIN bank_lists bl ON ppbl.bank_list_id = bl.ID
 	`
 
-	var conditions []string
-
-	if routedChannelName != "" {
-		routedChannelIds := helper.SplitString(routedChannelName)
-		quotedRoutedChannelIds := make([]string, len(routedChannelIds))
-		for i, name := range routedChannelIds {
-			quotedRoutedChannelIds[i] = fmt.Sprintf("'%v'", name)
-		}
-		conditions = append(conditions, fmt.Sprintf("pp.paychannel_name IN (%v)", strings.Join(quotedRoutedChannelIds, ", ")))
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	query, args := newQueryFilter().
+		in("pp.paychannel_name", routedChannelName).
+		build(query, "WHERE")
 
 	query += `
 	)
@@ -799,7 +654,7 @@ func (mr *MerchantReads) GetBankListForDisbursementRepo(routedChannelName string
 		unique_bank_list
 	`
 
-	err := mr.db.Select(&bankList, query)
+	err := mr.db.Select(&bankList, query, args...)
 	if err != nil {
 		return bankList, err
 	}
@@ -964,8 +819,19 @@ func (mr *MerchantReads) GetActiveAndAvailableChannelRepo(merchantPaychannelId i
 	return activePaychannel, availablePaychannel, nil
 }
 
-func buildCountQueryManualPayment(params dto.QueryParamsManualPayment) string {
+func manualPaymentListFilter(params dto.QueryParamsManualPayment) *queryFilter {
+	return newQueryFilter().
+		from("mcf.created_at", params.MinDate).
+		until("mcf.created_at", params.MaxDate).
+		until("mcf.amount", params.AmountMax).
+		from("mcf.amount", params.AmountMin).
+		in("mcf.status", params.Status).
+		in("r.reason_name", params.ReasonName).
+		in("m.merchant_name", params.MerchantName).
+		search(params.Search, "mcf.payment_id")
+}
 
+func buildCountQueryManualPayment(params dto.QueryParamsManualPayment) (string, []interface{}) {
 	query := `
 	SELECT
 		COUNT(*)
@@ -978,59 +844,5 @@ func buildCountQueryManualPayment(params dto.QueryParamsManualPayment) string {
 		mcf.reason_id NOT IN (5,6,7)
 	`
 
-	var conditions []string
-
-	if params.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("mcf.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("mcf.created_at <= '%v'", params.MaxDate))
-	}
-
-	if params.AmountMax != "" {
-		conditions = append(conditions, fmt.Sprintf("mcf.amount <= '%v'", params.AmountMax))
-	}
-
-	if params.AmountMin != "" {
-		conditions = append(conditions, fmt.Sprintf("mcf.amount >= '%v'", params.AmountMin))
-	}
-
-	if params.Status != "" {
-		sliceStatus := helper.SplitString(params.Status)
-		var statusConditions []string
-		for _, status := range sliceStatus {
-			statusConditions = append(statusConditions, fmt.Sprintf("mcf.status = '%v'", status))
-		}
-		conditions = append(conditions, "("+strings.Join(statusConditions, " OR ")+")")
-	}
-
-	if params.ReasonName != "" {
-		sliceReasonName := helper.SplitString(params.ReasonName)
-		var reasonNameConditions []string
-		for _, reasonName := range sliceReasonName {
-			reasonNameConditions = append(reasonNameConditions, fmt.Sprintf("r.reason_name = '%v'", reasonName))
-		}
-		conditions = append(conditions, "("+strings.Join(reasonNameConditions, " OR ")+")")
-	}
-
-	if params.MerchantName != "" {
-		sliceMerchantName := helper.SplitString(params.MerchantName)
-		var merchantNameConditions []string
-		for _, merchantName := range sliceMerchantName {
-			merchantNameConditions = append(merchantNameConditions, fmt.Sprintf("m.merchant_name = '%v'", merchantName))
-		}
-		conditions = append(conditions, "("+strings.Join(merchantNameConditions, " OR ")+")")
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(mcf.payment_id LIKE '%v')", searchStr))
-	}
-
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
-	}
-
-	return query
+	return manualPaymentListFilter(params).build(query, "AND")
 }
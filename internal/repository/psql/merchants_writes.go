@@ -5,13 +5,17 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
 	"github.com/jmoiron/sqlx"
 )
 
 type MerchantWrites struct {
-	db *sqlx.DB
+	db executor
 }
 
 func NewMerchantWrites(db *sqlx.DB) *MerchantWrites {
@@ -20,7 +24,7 @@ func NewMerchantWrites(db *sqlx.DB) *MerchantWrites {
 	}
 }
 
-func (mw *MerchantWrites) UpdateMerchantCapitalAndNotSettleBalance(notSettleBalance float64, balanceCapitalFlow float64, merchantId string) error {
+func (mw *MerchantWrites) UpdateMerchantCapitalAndNotSettleBalance(notSettleBalance money.Money, balanceCapitalFlow money.Money, merchantId string) error {
 	query := `
 	UPDATE merchant_accounts
 	SET not_settle_balance = $1,
@@ -36,23 +40,25 @@ func (mw *MerchantWrites) UpdateMerchantCapitalAndNotSettleBalance(notSettleBala
 	return nil
 }
 
-func (mw *MerchantWrites) UpdateMerchantSecretKeyRepo(secretKey string, merchantId string) error {
+func (mw *MerchantWrites) GetMerchantAccountForUpdateRepo(merchantId string) (entity.MerchantAccount, error) {
+	var merchantAccountData entity.MerchantAccount
+
 	query := `
-	UPDATE merchants
-	SET
-		merchant_secret = $1,
-		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
-	WHERE merchant_id = $2;
+	SELECT *
+	FROM merchant_accounts
+	WHERE merchant_id = $1
+	FOR UPDATE;
 	`
 
-	_, err := mw.db.Exec(query, secretKey, merchantId)
+	err := mw.db.Get(&merchantAccountData, query, merchantId)
 	if err != nil {
-		return err
+		return merchantAccountData, err
 	}
-	return nil
+
+	return merchantAccountData, nil
 }
 
-func (mw *MerchantWrites) UpdateMerchantCapitalAndSettleBalance(settleBalance float64, balanceCapitalFlow float64, merchantId string) error {
+func (mw *MerchantWrites) UpdateMerchantCapitalAndSettleBalance(settleBalance money.Money, balanceCapitalFlow money.Money, merchantId string) error {
 	query := `
 	UPDATE merchant_accounts
 	SET settle_balance = $1,
@@ -68,7 +74,7 @@ func (mw *MerchantWrites) UpdateMerchantCapitalAndSettleBalance(settleBalance fl
 	return nil
 }
 
-func (mw *MerchantWrites) UpdateMerchantBalanceSettleAndPendingOutBalanceRepo(settleBalance float64, pendingOutBalance float64, merchantId string) error {
+func (mw *MerchantWrites) UpdateMerchantBalanceSettleAndPendingOutBalanceRepo(settleBalance money.Money, pendingOutBalance money.Money, merchantId string) error {
 	query := `
 	UPDATE merchant_accounts
 	SET settle_balance = $1,
@@ -84,7 +90,7 @@ func (mw *MerchantWrites) UpdateMerchantBalanceSettleAndPendingOutBalanceRepo(se
 	return nil
 }
 
-func (mw *MerchantWrites) UpdateMerchantCapitalPendingOut(pendingAmount float64, balanceCapitalFlow float64, merchantId string) error {
+func (mw *MerchantWrites) UpdateMerchantCapitalPendingOut(pendingAmount money.Money, balanceCapitalFlow money.Money, merchantId string) error {
 	query := `
 	UPDATE merchant_accounts
 	SET balance_capital_flow = $1,
@@ -100,7 +106,7 @@ func (mw *MerchantWrites) UpdateMerchantCapitalPendingOut(pendingAmount float64,
 	return nil
 }
 
-func (mw *MerchantWrites) UpdateMerchantHoldBalanceAndSettleBalance(settleBalance float64, holdBalance float64, merchantId string) error {
+func (mw *MerchantWrites) UpdateMerchantHoldBalanceAndSettleBalance(settleBalance money.Money, holdBalance money.Money, merchantId string) error {
 	query := `
 	UPDATE merchant_accounts
 	SET settle_balance = $1,
@@ -116,7 +122,7 @@ func (mw *MerchantWrites) UpdateMerchantHoldBalanceAndSettleBalance(settleBalanc
 	return nil
 }
 
-func (mw *MerchantWrites) UpdateMerchantHoldBalanceAndNotSettleBalance(notSettleBalance float64, holdBalance float64, merchantId string) error {
+func (mw *MerchantWrites) UpdateMerchantHoldBalanceAndNotSettleBalance(notSettleBalance money.Money, holdBalance money.Money, merchantId string) error {
 	query := `
 	UPDATE merchant_accounts
 	SET not_settle_balance = $1,
@@ -208,7 +214,7 @@ func (mw *MerchantWrites) CreateMerchantCapitalFlow(payload dto.CreateMerchantCa
 	return merchantCapitalFlowId, nil
 }
 
-func (mw *MerchantWrites) UpdateMerchantSettlement(settleBalance float64, notSettleBalance float64, merchantId string) error {
+func (mw *MerchantWrites) UpdateMerchantSettlement(settleBalance money.Money, notSettleBalance money.Money, merchantId string) error {
 	query := `
 	UPDATE merchant_accounts
 	SET settle_balance = $1,
@@ -242,16 +248,16 @@ func (mw *MerchantWrites) CreateMerchantCallback(paymentId string, callbackStatu
 	return merchantCallbackId, nil
 }
 
-func (mw *MerchantWrites) CreateMerchantRepo(merchantName string, merchantId string, merchantSecret string) (int, error) {
+func (mw *MerchantWrites) CreateMerchantRepo(merchantName string, merchantId string) (int, error) {
 	var createMerchantId int
 
 	query := `
-	INSERT INTO merchants (merchant_id, merchant_name, merchant_secret, currency, status, created_at, updated_at)
-	VALUES ($1, $2, $3, 'IDR', 'INACTIVE', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	INSERT INTO merchants (merchant_id, merchant_name, currency, status, created_at, updated_at)
+	VALUES ($1, $2, 'IDR', 'INACTIVE', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
 	RETURNING id
 	`
 
-	row := mw.db.QueryRow(query, merchantId, merchantName, merchantSecret)
+	row := mw.db.QueryRow(query, merchantId, merchantName)
 	err := row.Scan(&createMerchantId)
 	if err != nil || createMerchantId == 0 {
 		return createMerchantId, err
@@ -278,7 +284,7 @@ func (mw *MerchantWrites) CreateMerchantPaymentMethodRepo(merchantId int, paymen
 	return createPaymentMethodId, nil
 }
 
-func (mw *MerchantWrites) CreateMerchantPaychannelRepo(merchantPaymentMethodId int, segment string, fee float64, feeType string, minAmount float64, maxAmount float64, dailyLimit float64, merchantPaychannelCode string) (int, error) {
+func (mw *MerchantWrites) CreateMerchantPaychannelRepo(merchantPaymentMethodId int, segment string, fee money.Money, feeType string, minAmount money.Money, maxAmount money.Money, dailyLimit money.Money, merchantPaychannelCode string) (int, error) {
 	var merchantPaychannelId int
 
 	query := `
@@ -424,3 +430,283 @@ func (mw *MerchantWrites) AddRoutingPaychannelRepo(merchantPaychannelId int, pro
 
 	return routingPaychannelId, nil
 }
+
+// CreateMerchantCallbackDeliveryRepo queues a callback of paymentStatus, due right away
+func (mw *MerchantWrites) CreateMerchantCallbackDeliveryRepo(paymentId string, paymentStatus string) (int, error) {
+	var deliveryId int
+
+	query := `
+	INSERT INTO merchant_callback_deliveries (payment_id, payment_status, delivery_status, attempt_count, next_retry_at, created_at, updated_at)
+	VALUES ($1, $2, $3, 0, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	RETURNING id
+	`
+
+	row := mw.db.QueryRow(query, paymentId, paymentStatus, constant.CallbackDeliveryPending)
+	err := row.Scan(&deliveryId)
+	if err != nil || deliveryId == 0 {
+		return deliveryId, err
+	}
+
+	return deliveryId, nil
+}
+
+// SupersedeMerchantCallbackDeliveriesRepo stops pending deliveries of paymentId, a newer status replaces them
+func (mw *MerchantWrites) SupersedeMerchantCallbackDeliveriesRepo(paymentId string) error {
+	query := `
+	UPDATE merchant_callback_deliveries
+	SET delivery_status = $1, next_retry_at = NULL, updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE payment_id = $2 AND delivery_status = $3
+	`
+
+	_, err := mw.db.Exec(query, constant.CallbackDeliverySuperseded, paymentId, constant.CallbackDeliveryPending)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ClaimDueMerchantCallbackDeliveriesRepo pushes the next retry of due deliveries by lease so concurrent
+// workers skip them, a worker that dies before recording the attempt leaves them due again after the lease
+func (mw *MerchantWrites) ClaimDueMerchantCallbackDeliveriesRepo(limit int, lease time.Duration) ([]entity.MerchantCallbackDelivery, error) {
+	var deliveries []entity.MerchantCallbackDelivery
+
+	query := `
+	UPDATE merchant_callback_deliveries
+	SET next_retry_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' + make_interval(secs => $1),
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id IN (
+		SELECT mcd.id
+		FROM merchant_callback_deliveries mcd
+		WHERE mcd.delivery_status = $2 AND mcd.next_retry_at <= CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+		ORDER BY mcd.next_retry_at
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING *
+	`
+
+	err := mw.db.Select(&deliveries, query, lease.Seconds(), constant.CallbackDeliveryPending, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// UpdateMerchantCallbackDeliveryRepo stores the outcome of an attempt, zero nextRetryDelay clears the next retry,
+// a delivery superseded while it was being sent keeps its status
+func (mw *MerchantWrites) UpdateMerchantCallbackDeliveryRepo(deliveryId int, deliveryStatus string, attemptCount int, nextRetryDelay time.Duration, lastResult string) error {
+	query := `
+	UPDATE merchant_callback_deliveries
+	SET delivery_status = $1,
+		attempt_count = $2,
+		next_retry_at = CASE WHEN $3::float8 > 0 THEN CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' + make_interval(secs => $3::float8) END,
+		last_result = $4,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $5 AND delivery_status = $6
+	`
+
+	_, err := mw.db.Exec(query, deliveryStatus, attemptCount, nextRetryDelay.Seconds(), lastResult, deliveryId, constant.CallbackDeliveryPending)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarkMerchantCallbackDeliveredRepo closes the pending and dead letter deliveries of paymentId after a manual callback went through
+func (mw *MerchantWrites) MarkMerchantCallbackDeliveredRepo(paymentId string) error {
+	query := `
+	UPDATE merchant_callback_deliveries
+	SET delivery_status = $1, next_retry_at = NULL, updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE payment_id = $2 AND delivery_status IN ($3, $4)
+	`
+
+	_, err := mw.db.Exec(query, constant.CallbackDeliveryDelivered, paymentId, constant.CallbackDeliveryPending, constant.CallbackDeliveryDeadLetter)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (mw *MerchantWrites) CreateMerchantCallbackAttemptRepo(payload dto.CreateMerchantCallbackAttemptPayload) (int, error) {
+	var merchantCallbackId int
+
+	query := `
+	INSERT INTO merchant_callbacks (payment_id, callback_status, payment_status_in_callback, callback_result, triggered_by, delivery_id, attempt_number, next_retry_at, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7,
+		CASE WHEN $8::float8 > 0 THEN CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' + make_interval(secs => $8::float8) END,
+		CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	RETURNING id
+	`
+
+	row := mw.db.QueryRow(
+		query,
+		payload.PaymentId,
+		payload.CallbackStatus,
+		payload.PaymentStatusInCallback,
+		payload.CallbackResult,
+		constant.CreateBySystem,
+		payload.DeliveryId,
+		payload.AttemptNumber,
+		payload.NextRetryDelay.Seconds(),
+	)
+	err := row.Scan(&merchantCallbackId)
+	if err != nil || merchantCallbackId == 0 {
+		return merchantCallbackId, err
+	}
+
+	return merchantCallbackId, nil
+}
+
+// CreateMerchantKeyRepo stores a merchant key that becomes due for activation after activateIn
+func (mw *MerchantWrites) CreateMerchantKeyRepo(key entity.MerchantKeyEntity, activateIn time.Duration) (int, error) {
+	var keyId int
+
+	query := `
+	INSERT INTO merchant_keys (merchant_id, version, secret, status, overlap_seconds, activate_at, created_by, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' + make_interval(secs => $6), $7, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	RETURNING id;
+	`
+
+	err := mw.db.QueryRow(query, key.MerchantId, key.Version, key.Secret, key.Status, key.OverlapSeconds, activateIn.Seconds(), key.CreatedBy).Scan(&keyId)
+	if err != nil {
+		return keyId, err
+	}
+
+	return keyId, nil
+}
+
+// GetMerchantKeysForUpdateRepo locks every key of merchantId, the newest version comes first
+func (mw *MerchantWrites) GetMerchantKeysForUpdateRepo(merchantId string) ([]entity.MerchantKeyEntity, error) {
+	var keys []entity.MerchantKeyEntity
+
+	query := `
+	SELECT *
+	FROM merchant_keys
+	WHERE merchant_id = $1
+	ORDER BY version DESC
+	FOR UPDATE;
+	`
+
+	err := mw.db.Select(&keys, query, merchantId)
+	if err != nil {
+		return keys, err
+	}
+
+	return keys, nil
+}
+
+func (mw *MerchantWrites) ActivateMerchantKeyRepo(keyId int) error {
+	query := `
+	UPDATE merchant_keys
+	SET
+		status = $1,
+		activate_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta',
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $2;
+	`
+
+	_, err := mw.db.Exec(query, constant.MerchantKeyActive, keyId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RetireMerchantKeyRepo moves a key to status, it stops signing callbacks once retireIn passed
+func (mw *MerchantWrites) RetireMerchantKeyRepo(keyId int, status string, retireIn time.Duration) error {
+	query := `
+	UPDATE merchant_keys
+	SET
+		status = $1,
+		retire_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' + make_interval(secs => $2),
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $3;
+	`
+
+	_, err := mw.db.Exec(query, status, retireIn.Seconds(), keyId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetDueMerchantKeysRepo returns the next keys whose scheduled activation has passed
+func (mw *MerchantWrites) GetDueMerchantKeysRepo() ([]entity.MerchantKeyEntity, error) {
+	var keys []entity.MerchantKeyEntity
+
+	query := `
+	SELECT *
+	FROM merchant_keys
+	WHERE status = $1
+	AND activate_at <= CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	ORDER BY activate_at;
+	`
+
+	err := mw.db.Select(&keys, query, constant.MerchantKeyNext)
+	if err != nil {
+		return keys, err
+	}
+
+	return keys, nil
+}
+
+// RetireExpiredMerchantKeysRepo ends the overlap window of the retiring keys that ran out
+func (mw *MerchantWrites) RetireExpiredMerchantKeysRepo() error {
+	query := `
+	UPDATE merchant_keys
+	SET
+		status = $1,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE status = $2
+	AND retire_at <= CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta';
+	`
+
+	_, err := mw.db.Exec(query, constant.MerchantKeyRetired, constant.MerchantKeyRetiring)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetMerchantKeySecretsRepo returns every merchant key with its stored secret for the encryption key rotation
+func (mw *MerchantWrites) GetMerchantKeySecretsRepo() ([]entity.MerchantKeyEntity, error) {
+	var keys []entity.MerchantKeyEntity
+
+	query := `
+	SELECT *
+	FROM merchant_keys
+	ORDER BY id;
+	`
+
+	err := mw.db.Select(&keys, query)
+	if err != nil {
+		return keys, err
+	}
+
+	return keys, nil
+}
+
+// RotateMerchantKeySecretRepo replaces the stored secret only while it still holds oldSecret, sql.ErrNoRows
+// means the key was changed in the meantime
+func (mw *MerchantWrites) RotateMerchantKeySecretRepo(keyId int, oldSecret string, newSecret string) error {
+	var id int
+
+	query := `
+	UPDATE merchant_keys
+	SET
+		secret = $1
+	WHERE id = $2
+	AND secret = $3
+	RETURNING id;
+	`
+
+	err := mw.db.QueryRow(query, newSecret, keyId, oldSecret).Scan(&id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
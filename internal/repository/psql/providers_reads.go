@@ -2,14 +2,13 @@ package psql
 
 import (
 	"database/sql"
-	"fmt"
 	"strings"
 
 	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
-	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type ProviderReads struct {
@@ -81,32 +80,17 @@ func (pr *ProviderReads) GetListProvidersWithFilterRepo(paymentMethod string, se
 		JOIN payment_methods pm ON pm.ID = ppm.payment_method_id
 	`
 
-	var conditions []string
-
-	if paymentMethod != "" {
-		slicePaymentMethod := helper.SplitString(paymentMethod)
-		var paymentMethodConditions []string
-		for _, paymentMethod := range slicePaymentMethod {
-			paymentMethodConditions = append(paymentMethodConditions, fmt.Sprintf("pm.name = '%v'", paymentMethod))
-		}
-		conditions = append(conditions, "("+strings.Join(paymentMethodConditions, " OR ")+")")
-	}
-
-	if search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", search)
-		conditions = append(conditions, fmt.Sprintf("(p.provider_name LIKE '%v' OR p.provider_id LIKE '%v')", searchStr, searchStr))
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	query, args := newQueryFilter().
+		in("pm.name", paymentMethod).
+		search(search, "p.provider_name", "p.provider_id").
+		build(query, "WHERE")
 
 	query += `
 	GROUP BY p.ID, p.provider_id, p.provider_name, p.created_at
 	ORDER BY p.created_at DESC
 	`
 
-	err := pr.db.Select(&listProvider, query)
+	err := pr.db.Select(&listProvider, query, args...)
 	if err != nil {
 		return listProvider, err
 	}
@@ -193,7 +177,6 @@ func (pr *ProviderReads) GetProviderInterfacesRepoById(id int) ([]entity.Provide
 
 func (pr *ProviderReads) GetProviderInterfaceWithFilterRepo(params dto.QueryParams) ([]entity.ProviderInterfacesEntity, error) {
 	var listData []entity.ProviderInterfacesEntity
-	var conditions []string
 
 	// Base query
 	query := `
@@ -211,38 +194,11 @@ func (pr *ProviderReads) GetProviderInterfaceWithFilterRepo(params dto.QueryPara
 		LEFT JOIN provider_payment_method_bank_lists ppbl ON ppm.id = ppbl.provider_payment_method_id
 	`
 
-	// Add filters if provided using conditions
-	if params.ProviderName != "" {
-		providerNames := helper.SplitString(params.ProviderName)
-		quotedProviderNames := make([]string, len(providerNames))
-		for i, name := range providerNames {
-			quotedProviderNames[i] = fmt.Sprintf("'%v'", name)
-		}
-		conditions = append(conditions, fmt.Sprintf("p.provider_name IN (%v)", strings.Join(quotedProviderNames, ", ")))
-	}
-
-	if params.PaymentMethod != "" {
-		paymentMethods := helper.SplitString(params.PaymentMethod)
-		quotedPaymentMethod := make([]string, len(paymentMethods))
-		for i, paymentMethod := range paymentMethods {
-			quotedPaymentMethod[i] = fmt.Sprintf("'%v'", paymentMethod)
-		}
-		conditions = append(conditions, fmt.Sprintf("pm.name IN (%v)", strings.Join(quotedPaymentMethod, ", ")))
-	}
-
-	if params.PayType != "" {
-		payTypes := helper.SplitString(params.PayType)
-		quotedPayType := make([]string, len(payTypes))
-		for i, payType := range payTypes {
-			quotedPayType[i] = fmt.Sprintf("'%v'", payType)
-		}
-		conditions = append(conditions, fmt.Sprintf("pm.pay_type IN (%v)", strings.Join(quotedPayType, ", ")))
-	}
-
-	// Combine conditions
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	query, args := newQueryFilter().
+		in("p.provider_name", params.ProviderName).
+		in("pm.name", params.PaymentMethod).
+		in("pm.pay_type", params.PayType).
+		build(query, "WHERE")
 
 	// Group by clause
 	query += `
@@ -254,7 +210,7 @@ func (pr *ProviderReads) GetProviderInterfaceWithFilterRepo(params dto.QueryPara
 		pm.name
 	`
 
-	err := pr.db.Select(&listData, query)
+	err := pr.db.Select(&listData, query, args...)
 	if err != nil {
 		return listData, err
 	}
@@ -321,56 +277,17 @@ func (pr *ProviderReads) GetListProviderChannelAllRepo(params dto.QueryParams) (
 		JOIN payment_methods pm ON pm.ID = ppm.payment_method_id
 	`
 
-	var conditions []string
-
-	if params.ProviderName != "" {
-		providerNames := helper.SplitString(params.ProviderName)
-		quotedProviderNames := make([]string, len(providerNames))
-		for i, name := range providerNames {
-			quotedProviderNames[i] = fmt.Sprintf("'%v'", name)
-		}
-		conditions = append(conditions, fmt.Sprintf("p.provider_name IN (%v)", strings.Join(quotedProviderNames, ", ")))
-	}
-
-	if params.Status != "" {
-		statuses := helper.SplitString(params.Status)
-		quotedStatuses := make([]string, len(statuses))
-		for i, status := range statuses {
-			quotedStatuses[i] = fmt.Sprintf("'%v'", status)
-		}
-		conditions = append(conditions, fmt.Sprintf("pp.status IN (%v)", strings.Join(quotedStatuses, ", ")))
-	}
-
-	if params.PayType != "" {
-		payTypes := helper.SplitString(params.PayType)
-		quotedPayType := make([]string, len(payTypes))
-		for i, payType := range payTypes {
-			quotedPayType[i] = fmt.Sprintf("'%v'", payType)
-		}
-		conditions = append(conditions, fmt.Sprintf("pm.pay_type IN (%v)", strings.Join(quotedPayType, ", ")))
-	}
-
-	if params.PaymentMethod != "" {
-		paymentMethods := helper.SplitString(params.PaymentMethod)
-		quotedPaymentMethod := make([]string, len(paymentMethods))
-		for i, paymentMethod := range paymentMethods {
-			quotedPaymentMethod[i] = fmt.Sprintf("'%v'", paymentMethod)
-		}
-		conditions = append(conditions, fmt.Sprintf("pm.name IN (%v)", strings.Join(quotedPaymentMethod, ", ")))
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(p.provider_name LIKE '%v' OR pp.paychannel_name LIKE '%v')", searchStr, searchStr))
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	filter := newQueryFilter().
+		in("p.provider_name", params.ProviderName).
+		in("pp.status", params.Status).
+		in("pm.pay_type", params.PayType).
+		in("pm.name", params.PaymentMethod).
+		search(params.Search, "p.provider_name", "pp.paychannel_name")
 
+	query, args := filter.build(query, "WHERE")
 	query += " ORDER BY pp.created_at DESC"
 
-	err := pr.db.Select(&listProviderChannel, query)
+	err := pr.db.Select(&listProviderChannel, query, args...)
 	if err != nil {
 		return listProviderChannel, err
 	}
@@ -397,6 +314,45 @@ func (pr *ProviderReads) GetAllCredentialsRepo(providerId string, interfaceSetti
 	return listCredentials, nil
 }
 
+// GetProviderCredentialsByKeysRepo returns credentials of every interface setting of providerId
+func (pr *ProviderReads) GetProviderCredentialsByKeysRepo(providerId string, keys []string) ([]entity.ProviderCredentialsEntity, error) {
+	var listCredentials []entity.ProviderCredentialsEntity
+
+	query := `
+		SELECT *
+		FROM provider_credentials pc
+		WHERE pc.provider_id = $1
+		AND pc.key = ANY($2)
+		ORDER BY pc.created_at DESC;
+	`
+
+	err := pr.db.Select(&listCredentials, query, providerId, pq.Array(keys))
+	if err != nil && err != sql.ErrNoRows {
+		return listCredentials, err
+	}
+
+	return listCredentials, nil
+}
+
+// GetProviderBankCodeRepo translates internal bank code into the provider bank code
+func (pr *ProviderReads) GetProviderBankCodeRepo(providerId string, bankCode string) (entity.ProviderBankCodeEntity, error) {
+	var bankCodeData entity.ProviderBankCodeEntity
+
+	query := `
+		SELECT *
+		FROM provider_bank_codes pbc
+		WHERE pbc.provider_id = $1
+		AND pbc.bank_code = $2;
+	`
+
+	err := pr.db.Get(&bankCodeData, query, providerId, bankCode)
+	if err != nil {
+		return bankCodeData, err
+	}
+
+	return bankCodeData, nil
+}
+
 func (pr *ProviderReads) GetDetailProviderChannelById(id int) (entity.ProviderChannelDetailEntity, error) {
 	var detailData entity.ProviderChannelDetailEntity
 
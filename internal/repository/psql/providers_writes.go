@@ -1,6 +1,7 @@
 package psql
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
 	"strconv"
@@ -8,11 +9,12 @@ import (
 
 	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
 	"github.com/jmoiron/sqlx"
 )
 
 type ProviderWrites struct {
-	db *sqlx.DB
+	db executor
 }
 
 func NewProviderWrites(db *sqlx.DB) *ProviderWrites {
@@ -39,6 +41,66 @@ func (pw *ProviderWrites) CreateProviderConfirmationDetail(source string, paymen
 	return id, nil
 }
 
+func (pw *ProviderWrites) CreateProviderConfirmationDetailWithNotesRepo(source string, paymentId string, result string, notes string) (int, error) {
+	var id int
+
+	query := `
+	INSERT INTO provider_transaction_confirmation_details (payment_id, type, confirmation_result, notes, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	RETURNING id
+	`
+
+	row := pw.db.QueryRow(query, paymentId, source, result, notes)
+	err := row.Scan(&id)
+	if err != nil || id == 0 {
+		return id, err
+	}
+
+	return id, nil
+}
+
+// CreateProviderCallbackRepo stores a callback once per dedup key, the id is 0 when the key was already
+// stored. A key whose callback was rejected or failed is taken again so the provider can retry it.
+func (pw *ProviderWrites) CreateProviderCallbackRepo(payload dto.CreateProviderCallbackPayload) (int, error) {
+	var id int
+
+	query := `
+	INSERT INTO provider_callbacks (provider_id, dedup_key, payment_id, callback_status, source_ip, raw_body, process_status, created_at, updated_at)
+	VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6, $7, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	ON CONFLICT (provider_id, dedup_key) DO UPDATE
+	SET source_ip = EXCLUDED.source_ip, raw_body = EXCLUDED.raw_body, process_status = EXCLUDED.process_status, notes = NULL, updated_at = EXCLUDED.updated_at
+	WHERE provider_callbacks.process_status IN ($8, $9)
+	RETURNING id
+	`
+
+	row := pw.db.QueryRow(query, payload.ProviderId, payload.DedupKey, payload.PaymentId, payload.CallbackStatus, payload.SourceIp, payload.RawBody, constant.ProviderCallbackReceived,
+		constant.ProviderCallbackRejected, constant.ProviderCallbackFailed)
+	err := row.Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil || id == 0 {
+		return id, err
+	}
+
+	return id, nil
+}
+
+func (pw *ProviderWrites) UpdateProviderCallbackStatusRepo(id int, processStatus string, notes string) error {
+	query := `
+	UPDATE provider_callbacks
+	SET process_status = $1, notes = NULLIF($2, ''), updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $3
+	`
+
+	_, err := pw.db.Exec(query, processStatus, notes, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (pw *ProviderWrites) UpdateProviderPaychannelByIdRepo(payload dto.AdjustLimitOrFeeProviderPayload) error {
 	query := "UPDATE provider_paychannels SET "
 	var conditions []string
@@ -189,3 +251,42 @@ func (pw *ProviderWrites) CreateProviderPaychannelRepo(payload dto.CreateProvide
 	}
 	return id, nil
 }
+
+// GetProviderCredentialsRepo returns every provider credential for the key rotation
+func (pw *ProviderWrites) GetProviderCredentialsRepo() ([]entity.ProviderCredentialsEntity, error) {
+	var listCredentials []entity.ProviderCredentialsEntity
+
+	query := `
+	SELECT *
+	FROM provider_credentials
+	ORDER BY id;
+	`
+
+	err := pw.db.Select(&listCredentials, query)
+	if err != nil {
+		return listCredentials, err
+	}
+
+	return listCredentials, nil
+}
+
+// RotateProviderCredentialRepo replaces the stored value only while it still holds oldValue, sql.ErrNoRows means
+// the value was changed in the meantime
+func (pw *ProviderWrites) RotateProviderCredentialRepo(id int, oldValue string, newValue string) error {
+	var credentialId int
+
+	query := `
+	UPDATE provider_credentials
+	SET
+		value = $1
+	WHERE id = $2
+	AND value = $3
+	RETURNING id;
+	`
+
+	err := pw.db.QueryRow(query, newValue, id, oldValue).Scan(&credentialId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package psql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
+)
+
+// queryFilter collects WHERE conditions with numbered placeholders next to their arguments, so user input
+// never ends up inside the sql text. A list query and its count query are built from the same filter.
+type queryFilter struct {
+	conditions []string
+	args       []interface{}
+}
+
+// newQueryFilter starts numbering after args, the values of the placeholders already written in the base query
+func newQueryFilter(args ...interface{}) *queryFilter {
+	return &queryFilter{
+		args: args,
+	}
+}
+
+func (qf *queryFilter) bind(value interface{}) string {
+	qf.args = append(qf.args, value)
+	return fmt.Sprintf("$%d", len(qf.args))
+}
+
+// where adds condition as is, every ? in it is bound to the next value
+func (qf *queryFilter) where(condition string, values ...interface{}) *queryFilter {
+	for _, value := range values {
+		condition = strings.Replace(condition, "?", qf.bind(value), 1)
+	}
+
+	qf.conditions = append(qf.conditions, condition)
+	return qf
+}
+
+// equal matches column against value, an empty value adds nothing
+func (qf *queryFilter) equal(column string, value string) *queryFilter {
+	if value == "" {
+		return qf
+	}
+
+	return qf.where(column+" = ?", value)
+}
+
+// from keeps rows where column is at least value, an empty value adds nothing
+func (qf *queryFilter) from(column string, value string) *queryFilter {
+	if value == "" {
+		return qf
+	}
+
+	return qf.where(column+" >= ?", value)
+}
+
+// until keeps rows where column is at most value, an empty value adds nothing
+func (qf *queryFilter) until(column string, value string) *queryFilter {
+	if value == "" {
+		return qf
+	}
+
+	return qf.where(column+" <= ?", value)
+}
+
+// in matches column against any of the comma separated values
+func (qf *queryFilter) in(column string, values string) *queryFilter {
+	if values == "" {
+		return qf
+	}
+
+	return qf.inValues(column, helper.SplitString(values))
+}
+
+// inValues matches column against any of values, an empty list adds nothing
+func (qf *queryFilter) inValues(column string, values []string) *queryFilter {
+	if len(values) == 0 {
+		return qf
+	}
+
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		placeholders[i] = qf.bind(value)
+	}
+
+	qf.conditions = append(qf.conditions, fmt.Sprintf("%v IN (%v)", column, strings.Join(placeholders, ", ")))
+	return qf
+}
+
+// likeEscaper makes \, % and _ of a search value match themselves, the LIKE uses \ as escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// search keeps rows where any of columns contains value
+func (qf *queryFilter) search(value string, columns ...string) *queryFilter {
+	if value == "" {
+		return qf
+	}
+
+	placeholder := qf.bind("%" + likeEscaper.Replace(value) + "%")
+	likes := make([]string, len(columns))
+	for i, column := range columns {
+		likes[i] = fmt.Sprintf(`%v LIKE %v ESCAPE '\'`, column, placeholder)
+	}
+
+	qf.conditions = append(qf.conditions, "("+strings.Join(likes, " OR ")+")")
+	return qf
+}
+
+// build appends the conditions to base after keyword, WHERE when base has none yet or AND when it already filters
+func (qf *queryFilter) build(base string, keyword string) (string, []interface{}) {
+	query := base
+	if len(qf.conditions) > 0 {
+		query += " " + keyword + " " + strings.Join(qf.conditions, " AND ")
+	}
+
+	args := make([]interface{}, len(qf.args))
+	copy(args, qf.args)
+
+	return query, args
+}
+
+// buildPage is build followed by tail, a fixed GROUP BY or ORDER BY, and the LIMIT and OFFSET of page
+func (qf *queryFilter) buildPage(base string, keyword string, tail string, page int, pageSize int) (string, []interface{}) {
+	query, args := qf.build(base, keyword)
+	query += " " + tail
+
+	if pageSize > 0 {
+		args = append(args, pageSize)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	if page > 0 {
+		args = append(args, (page-1)*pageSize)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	return query, args
+}
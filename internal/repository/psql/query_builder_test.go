@@ -0,0 +1,107 @@
+package psql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQueryFilterBuild(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    *queryFilter
+		base      string
+		keyword   string
+		wantQuery string
+		wantArgs  []interface{}
+	}{
+		{
+			name:      "no condition",
+			filter:    newQueryFilter().equal("t.status", ""),
+			base:      "SELECT * FROM transactions t",
+			keyword:   "WHERE",
+			wantQuery: "SELECT * FROM transactions t",
+			wantArgs:  []interface{}{},
+		},
+		{
+			name: "conditions are numbered in order",
+			filter: newQueryFilter().
+				equal("t.merchant_id", "M1").
+				from("t.created_at", "2024-01-01").
+				until("t.created_at", "2024-01-31").
+				in("t.status", "SUCCESS,FAILED").
+				where("t.amount > ? AND t.fee < ?", 100, 5),
+			base:      "SELECT * FROM transactions t",
+			keyword:   "WHERE",
+			wantQuery: "SELECT * FROM transactions t WHERE t.merchant_id = $1 AND t.created_at >= $2 AND t.created_at <= $3 AND t.status IN ($4, $5) AND t.amount > $6 AND t.fee < $7",
+			wantArgs:  []interface{}{"M1", "2024-01-01", "2024-01-31", "SUCCESS", "FAILED", 100, 5},
+		},
+		{
+			name:      "numbering continues after the base query",
+			filter:    newQueryFilter("M1").equal("t.status", "SUCCESS"),
+			base:      "SELECT * FROM transactions t WHERE t.merchant_id = $1",
+			keyword:   "AND",
+			wantQuery: "SELECT * FROM transactions t WHERE t.merchant_id = $1 AND t.status = $2",
+			wantArgs:  []interface{}{"M1", "SUCCESS"},
+		},
+		{
+			name:      "search shares one placeholder across columns",
+			filter:    newQueryFilter().equal("t.status", "SUCCESS").search("budi", "t.payment_id", "t.bank_account_name"),
+			base:      "SELECT * FROM transactions t",
+			keyword:   "WHERE",
+			wantQuery: `SELECT * FROM transactions t WHERE t.status = $1 AND (t.payment_id LIKE $2 ESCAPE '\' OR t.bank_account_name LIKE $2 ESCAPE '\')`,
+			wantArgs:  []interface{}{"SUCCESS", "%budi%"},
+		},
+		{
+			name:      "search escapes like wildcards",
+			filter:    newQueryFilter().search(`50%_off\`, "t.notes"),
+			base:      "SELECT * FROM transactions t",
+			keyword:   "WHERE",
+			wantQuery: `SELECT * FROM transactions t WHERE (t.notes LIKE $1 ESCAPE '\')`,
+			wantArgs:  []interface{}{`%50\%\_off\\%`},
+		},
+		{
+			name:      "empty in list adds nothing",
+			filter:    newQueryFilter().inValues("t.status", nil).in("t.pay_type", ""),
+			base:      "SELECT * FROM transactions t",
+			keyword:   "WHERE",
+			wantQuery: "SELECT * FROM transactions t",
+			wantArgs:  []interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := tt.filter.build(tt.base, tt.keyword)
+			if query != tt.wantQuery {
+				t.Errorf("query = %v\nwant %v", query, tt.wantQuery)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestQueryFilterBuildPage(t *testing.T) {
+	filter := newQueryFilter().equal("t.status", "SUCCESS")
+
+	query, args := filter.buildPage("SELECT * FROM transactions t", "WHERE", "ORDER BY t.created_at DESC", 3, 20)
+	wantQuery := "SELECT * FROM transactions t WHERE t.status = $1 ORDER BY t.created_at DESC LIMIT $2 OFFSET $3"
+	if query != wantQuery {
+		t.Errorf("query = %v\nwant %v", query, wantQuery)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"SUCCESS", 20, 40}) {
+		t.Errorf("args = %#v", args)
+	}
+
+	// the count query built from the same filter doesn't get the page arguments
+	countQuery, countArgs := filter.build("SELECT COUNT(*) FROM transactions t", "WHERE")
+	if countQuery != "SELECT COUNT(*) FROM transactions t WHERE t.status = $1" || !reflect.DeepEqual(countArgs, []interface{}{"SUCCESS"}) {
+		t.Errorf("count query = %v, %#v", countQuery, countArgs)
+	}
+
+	query, args = newQueryFilter().buildPage("SELECT * FROM transactions t", "WHERE", "ORDER BY t.id", 0, 0)
+	if query != "SELECT * FROM transactions t ORDER BY t.id" || len(args) != 0 {
+		t.Errorf("query without page = %v, %#v", query, args)
+	}
+}
@@ -0,0 +1,153 @@
+package psql
+
+import (
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/jmoiron/sqlx"
+)
+
+type ReconciliationReads struct {
+	db *sqlx.DB
+}
+
+func NewReconciliationReads(db *sqlx.DB) *ReconciliationReads {
+	return &ReconciliationReads{
+		db: db,
+	}
+}
+
+func (rr *ReconciliationReads) GetMerchantAccountsRepo() ([]entity.MerchantAccount, error) {
+	var merchantAccounts []entity.MerchantAccount
+
+	query := `
+	SELECT
+		*
+	FROM
+		merchant_accounts
+	ORDER BY id;
+	`
+
+	err := rr.db.Select(&merchantAccounts, query)
+	if err != nil {
+		return merchantAccounts, err
+	}
+
+	return merchantAccounts, nil
+}
+
+func (rr *ReconciliationReads) GetCapitalFlowsByMerchantAccountIdRepo(merchantAccountId int) ([]entity.ReconciliationCapitalFlow, error) {
+	var capitalFlows []entity.ReconciliationCapitalFlow
+
+	query := `
+	SELECT
+		id,
+		payment_id,
+		amount,
+		temp_balance,
+		capital_type
+	FROM
+		merchant_capital_flows
+	WHERE
+		merchant_account_id = $1
+	ORDER BY id;
+	`
+
+	err := rr.db.Select(&capitalFlows, query, merchantAccountId)
+	if err != nil {
+		return capitalFlows, err
+	}
+
+	return capitalFlows, nil
+}
+
+// GetCapitalFlowPaymentIdsWithoutLedgerRepo returns payment ids of capital flows recorded after the ledger
+// went live that never got a journal on the merchant ledger accounts
+func (rr *ReconciliationReads) GetCapitalFlowPaymentIdsWithoutLedgerRepo(merchantAccountId int, merchantId string) ([]string, error) {
+	var paymentIds []string
+
+	query := `
+	SELECT DISTINCT
+		mcf.payment_id
+	FROM
+		merchant_capital_flows mcf
+	WHERE
+		mcf.merchant_account_id = $1
+		AND mcf.created_at >= (SELECT MIN(created_at) FROM ledger_journals)
+		AND NOT EXISTS (
+			SELECT 1
+			FROM ledger_journals lj
+			JOIN ledger_postings lp ON lp.ledger_journal_id = lj.id
+			JOIN ledger_accounts la ON lp.ledger_account_id = la.id
+			WHERE lj.payment_id = mcf.payment_id AND la.owner_type = $2 AND la.owner_id = $3
+		)
+	ORDER BY mcf.payment_id;
+	`
+
+	err := rr.db.Select(&paymentIds, query, merchantAccountId, constant.LedgerOwnerMerchant, merchantId)
+	if err != nil {
+		return paymentIds, err
+	}
+
+	return paymentIds, nil
+}
+
+func (rr *ReconciliationReads) GetListReconciliationRunsRepo() ([]entity.ReconciliationRun, error) {
+	var reconciliationRuns []entity.ReconciliationRun
+
+	query := `
+	SELECT
+		*
+	FROM
+		reconciliation_runs
+	ORDER BY started_at DESC
+	LIMIT 100;
+	`
+
+	err := rr.db.Select(&reconciliationRuns, query)
+	if err != nil {
+		return reconciliationRuns, err
+	}
+
+	return reconciliationRuns, nil
+}
+
+func (rr *ReconciliationReads) GetReconciliationRunByIdRepo(runId int) (entity.ReconciliationRun, error) {
+	var reconciliationRun entity.ReconciliationRun
+
+	query := `
+	SELECT
+		*
+	FROM
+		reconciliation_runs
+	WHERE
+		id = $1;
+	`
+
+	err := rr.db.Get(&reconciliationRun, query, runId)
+	if err != nil {
+		return reconciliationRun, err
+	}
+
+	return reconciliationRun, nil
+}
+
+func (rr *ReconciliationReads) GetReconciliationDiscrepanciesByRunIdRepo(runId int) ([]entity.ReconciliationDiscrepancy, error) {
+	var discrepancies []entity.ReconciliationDiscrepancy
+
+	query := `
+	SELECT
+		*
+	FROM
+		reconciliation_discrepancies
+	WHERE
+		reconciliation_run_id = $1
+	ORDER BY merchant_account_id, bucket;
+	`
+
+	err := rr.db.Select(&discrepancies, query, runId)
+	if err != nil {
+		return discrepancies, err
+	}
+
+	return discrepancies, nil
+}
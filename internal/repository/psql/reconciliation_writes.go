@@ -0,0 +1,102 @@
+package psql
+
+import (
+	"strings"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/jmoiron/sqlx"
+)
+
+type ReconciliationWrites struct {
+	db executor
+}
+
+func NewReconciliationWrites(db *sqlx.DB) *ReconciliationWrites {
+	return &ReconciliationWrites{
+		db: db,
+	}
+}
+
+func (rw *ReconciliationWrites) CreateReconciliationRunRepo(triggeredBy string) (int, error) {
+	var runId int
+
+	query := `
+	INSERT INTO reconciliation_runs (triggered_by, status, started_at)
+	VALUES ($1, $2, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	RETURNING id
+	`
+
+	row := rw.db.QueryRow(query, triggeredBy, constant.ReconciliationStatusRunning)
+	err := row.Scan(&runId)
+	if err != nil || runId == 0 {
+		return runId, err
+	}
+
+	return runId, nil
+}
+
+func (rw *ReconciliationWrites) FinishReconciliationRunRepo(runId int, status string, totalAccounts int, totalDiscrepancies int, notes string) error {
+	query := `
+	UPDATE reconciliation_runs
+	SET status = $1, total_accounts = $2, total_discrepancies = $3, notes = NULLIF($4, ''), finished_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $5
+	`
+
+	_, err := rw.db.Exec(query, status, totalAccounts, totalDiscrepancies, notes, runId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (rw *ReconciliationWrites) CreateReconciliationDiscrepancyRepo(payload dto.CreateReconciliationDiscrepancyPayload) (int, error) {
+	var discrepancyId int
+
+	query := `
+	INSERT INTO reconciliation_discrepancies (reconciliation_run_id, merchant_id, merchant_account_id, bucket, expected_amount, actual_amount, difference, payment_ids, status, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	RETURNING id
+	`
+
+	row := rw.db.QueryRow(
+		query,
+		payload.ReconciliationRunId,
+		payload.MerchantId,
+		payload.MerchantAccountId,
+		payload.Bucket,
+		payload.ExpectedAmount,
+		payload.ActualAmount,
+		payload.ActualAmount.Sub(payload.ExpectedAmount),
+		strings.Join(payload.PaymentIds, ","),
+		constant.DiscrepancyStatusOpen,
+	)
+	err := row.Scan(&discrepancyId)
+	if err != nil || discrepancyId == 0 {
+		return discrepancyId, err
+	}
+
+	return discrepancyId, nil
+}
+
+// ResolveReconciliationDiscrepancyRepo only resolves open discrepancies, sql.ErrNoRows means
+// the discrepancy doesn't exist or is already resolved
+func (rw *ReconciliationWrites) ResolveReconciliationDiscrepancyRepo(payload dto.ResolveReconciliationDiscrepancyReq) (int, error) {
+	var discrepancyId int
+
+	query := `
+	UPDATE reconciliation_discrepancies
+	SET status = $1, resolved_by = $2, resolution_notes = $3, resolved_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $4 AND status = $5
+	RETURNING id
+	`
+
+	row := rw.db.QueryRow(query, constant.DiscrepancyStatusResolved, payload.Username, payload.Notes, payload.DiscrepancyId, constant.DiscrepancyStatusOpen)
+	err := row.Scan(&discrepancyId)
+	if err != nil || discrepancyId == 0 {
+		return discrepancyId, err
+	}
+
+	return discrepancyId, nil
+}
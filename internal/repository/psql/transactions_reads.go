@@ -2,14 +2,11 @@ package psql
 
 import (
 	"database/sql"
-	"fmt"
-	"strings"
 
 	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
-	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -213,20 +210,16 @@ func (tr *TransactionsReads) GetTransactionList(params dto.QueryParams) ([]entit
 		pageSizeInt = 50 // Default page size
 	}
 
-	query, err := buildTransactionQuery(params)
-	if err != nil {
-		return nil, pagination, err
-	}
-
-	err = tr.db.Select(&transactions, query)
+	query, args := buildTransactionQuery(params)
+	err := tr.db.Select(&transactions, query, args...)
 	if err != nil {
 		return nil, pagination, err
 	}
 
 	// Get the total number of items for the given filters
-	countQuery := buildCountQuery(params)
+	countQuery, countArgs := buildCountQuery(params)
 	var totalItems int
-	err = tr.db.Get(&totalItems, countQuery)
+	err = tr.db.Get(&totalItems, countQuery, countArgs...)
 	if err != nil {
 		return nil, pagination, err
 	}
@@ -259,20 +252,16 @@ func (tr *TransactionsReads) GetTransactionInListRepo(params dto.QueryParams) ([
 		pageSizeInt = 50
 	}
 
-	query, err := buildListInMerchantQuery(params)
-	if err != nil {
-		return nil, pagination, err
-	}
-
-	err = tr.db.Select(&transactionInList, query)
+	query, args := buildListInMerchantQuery(params)
+	err := tr.db.Select(&transactionInList, query, args...)
 	if err != nil {
 		return nil, pagination, err
 	}
 
 	// Get the total number of items for the given filters
-	countQuery := buildCountListInQuery(params)
+	countQuery, countArgs := buildCountListInQuery(params)
 	var totalItems int
-	err = tr.db.Get(&totalItems, countQuery)
+	err = tr.db.Get(&totalItems, countQuery, countArgs...)
 	if err != nil {
 		return nil, pagination, err
 	}
@@ -305,17 +294,16 @@ func (tr *TransactionsReads) GetTransactionOutListRepo(params dto.QueryParams) (
 		pageSizeInt = 50
 	}
 
-	query := buildListOutMerchantQuery(params)
-
-	err := tr.db.Select(&transactionInList, query)
+	query, args := buildListOutMerchantQuery(params)
+	err := tr.db.Select(&transactionInList, query, args...)
 	if err != nil {
 		return nil, pagination, err
 	}
 
 	// Get the total number of items for the given filters
-	countQuery := buildCountListOutMerchantQuery(params)
+	countQuery, countArgs := buildCountListOutMerchantQuery(params)
 	var totalItems int
-	err = tr.db.Get(&totalItems, countQuery)
+	err = tr.db.Get(&totalItems, countQuery, countArgs...)
 	if err != nil {
 		return nil, pagination, err
 	}
@@ -393,23 +381,14 @@ func (tr *TransactionsReads) GetTransactionAnalyticsRepo(params dto.GetMerchantA
 		AND m.merchant_id = $1
 	`
 
-	var conditions []string
-
-	if params.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at <= '%v'", params.MaxDate))
-	}
-
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
-	}
+	filter := newQueryFilter(params.MerchantId).
+		from("t.created_at", params.MinDate).
+		until("t.created_at", params.MaxDate)
 
+	query, args := filter.build(query, "AND")
 	query += " ORDER BY t.created_at DESC"
 
-	err := tr.db.Select(&transactionDatas, query, params.MerchantId)
+	err := tr.db.Select(&transactionDatas, query, args...)
 	if err != nil {
 		return transactionDatas, err
 	}
@@ -476,23 +455,14 @@ func (tr *TransactionsReads) GetTransactionDataForProviderAnalyticsRepo(payload
 		AND p.ID = $1
 	`
 
-	var conditions []string
-
-	if payload.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at >= '%v'", payload.MinDate))
-	}
-
-	if payload.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at <= '%v'", payload.MaxDate))
-	}
-
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
-	}
+	filter := newQueryFilter(payload.ProviderId).
+		from("t.created_at", payload.MinDate).
+		until("t.created_at", payload.MaxDate)
 
+	query, args := filter.build(query, "AND")
 	query += " ORDER BY t.created_at DESC"
 
-	err := tr.db.Select(&transactionDatas, query, payload.ProviderId)
+	err := tr.db.Select(&transactionDatas, query, args...)
 	if err != nil {
 		return transactionDatas, err
 	}
@@ -538,72 +508,6 @@ func (tr *TransactionsReads) GetTransactionCapitalFlowRepo(params dto.QueryParam
 		mcf.reason_id IN (7, 6, 5)
 	`
 
-	var conditions []string
-
-	if params.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("mcf.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("mcf.created_at <= '%v'", params.MaxDate))
-	}
-
-	if params.Status != "" {
-		sliceStatus := helper.SplitString(params.Status)
-		var statusConditions []string
-		for _, status := range sliceStatus {
-			statusConditions = append(statusConditions, fmt.Sprintf("mcf.status = '%v'", status))
-		}
-		conditions = append(conditions, "("+strings.Join(statusConditions, " OR ")+")")
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(mcf.payment_id LIKE '%v')", searchStr))
-	}
-
-	if params.MerchantId != "" {
-		conditions = append(conditions, fmt.Sprintf("ma.merchant_id = '%v'", params.MerchantId))
-	}
-
-	if params.PayType != "" {
-		slicePayType := helper.SplitString(params.PayType)
-		var payTypeConditions []string
-		for _, payType := range slicePayType {
-			payTypeConditions = append(payTypeConditions, fmt.Sprintf("pm.pay_type = '%v'", payType))
-		}
-		conditions = append(conditions, "("+strings.Join(payTypeConditions, " OR ")+")")
-	}
-
-	if params.PaymentMethod != "" {
-		slicePaymentMethod := helper.SplitString(params.PaymentMethod)
-		var paymentMethodConditions []string
-		for _, paymentMethod := range slicePaymentMethod {
-			paymentMethodConditions = append(paymentMethodConditions, fmt.Sprintf("pm.name = '%v'", paymentMethod))
-		}
-		conditions = append(conditions, "("+strings.Join(paymentMethodConditions, " OR ")+")")
-	}
-
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
-	}
-
-	query += " ORDER BY mcf.created_at DESC"
-
-	if pageSizeInt > 0 {
-		query += fmt.Sprintf(" LIMIT %d", pageSizeInt)
-	}
-
-	if pageInt > 0 {
-		offset := (pageInt - 1) * pageSizeInt
-		query += fmt.Sprintf(" OFFSET %d", offset)
-	}
-
-	err := tr.db.Select(&listTransactionCapital, query)
-	if err != nil && err != sql.ErrNoRows {
-		return listTransactionCapital, pagination, err
-	}
-
 	countQuery := `
 	SELECT
 		COUNT (*)
@@ -620,58 +524,24 @@ func (tr *TransactionsReads) GetTransactionCapitalFlowRepo(params dto.QueryParam
 		mcf.reason_id IN (7, 6, 5)
 	`
 
-	var countConditions []string
-
-	if params.MinDate != "" {
-		countConditions = append(countConditions, fmt.Sprintf("mcf.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		countConditions = append(countConditions, fmt.Sprintf("mcf.created_at <= '%v'", params.MaxDate))
-	}
-
-	if params.Status != "" {
-		sliceStatus := helper.SplitString(params.Status)
-		var statusConditions []string
-		for _, status := range sliceStatus {
-			statusConditions = append(statusConditions, fmt.Sprintf("mcf.status = '%v'", status))
-		}
-		countConditions = append(countConditions, "("+strings.Join(statusConditions, " OR ")+")")
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		countConditions = append(countConditions, fmt.Sprintf("(mcf.payment_id LIKE '%v')", searchStr))
-	}
-
-	if params.MerchantId != "" {
-		countConditions = append(countConditions, fmt.Sprintf("ma.merchant_id = '%v'", params.MerchantId))
-	}
-
-	if params.PayType != "" {
-		slicePayType := helper.SplitString(params.PayType)
-		var payTypeConditions []string
-		for _, payType := range slicePayType {
-			payTypeConditions = append(payTypeConditions, fmt.Sprintf("pm.pay_type = '%v'", payType))
-		}
-		countConditions = append(countConditions, "("+strings.Join(payTypeConditions, " OR ")+")")
-	}
-
-	if params.PaymentMethod != "" {
-		slicePaymentMethod := helper.SplitString(params.PaymentMethod)
-		var paymentMethodConditions []string
-		for _, paymentMethod := range slicePaymentMethod {
-			paymentMethodConditions = append(paymentMethodConditions, fmt.Sprintf("pm.name = '%v'", paymentMethod))
-		}
-		countConditions = append(countConditions, "("+strings.Join(paymentMethodConditions, " OR ")+")")
-	}
-
-	if len(countConditions) > 0 {
-		countQuery += " AND " + strings.Join(countConditions, " AND ")
+	filter := newQueryFilter().
+		from("mcf.created_at", params.MinDate).
+		until("mcf.created_at", params.MaxDate).
+		in("mcf.status", params.Status).
+		search(params.Search, "mcf.payment_id").
+		equal("ma.merchant_id", params.MerchantId).
+		in("pm.pay_type", params.PayType).
+		in("pm.name", params.PaymentMethod)
+
+	query, args := filter.buildPage(query, "AND", "ORDER BY mcf.created_at DESC", pageInt, pageSizeInt)
+	err := tr.db.Select(&listTransactionCapital, query, args...)
+	if err != nil && err != sql.ErrNoRows {
+		return listTransactionCapital, pagination, err
 	}
 
+	countQuery, countArgs := filter.build(countQuery, "AND")
 	var totalItems int
-	err = tr.db.Get(&totalItems, countQuery)
+	err = tr.db.Get(&totalItems, countQuery, countArgs...)
 	if err != nil {
 		return nil, pagination, err
 	}
@@ -749,23 +619,14 @@ func (tr *TransactionsReads) GetTransactionListByMerchantPaychannelRepo(payload
 		AND t.merchant_paychannel_id = $1
 	`
 
-	var conditions []string
-
-	if payload.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at >= '%v'", payload.MinDate))
-	}
-
-	if payload.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at <= '%v'", payload.MaxDate))
-	}
-
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
-	}
+	filter := newQueryFilter(payload.MerchantPaychannelId).
+		from("t.created_at", payload.MinDate).
+		until("t.created_at", payload.MaxDate)
 
+	query, args := filter.build(query, "AND")
 	query += " ORDER BY t.created_at DESC"
 
-	err := tr.db.Select(&transactionData, query, payload.MerchantPaychannelId)
+	err := tr.db.Select(&transactionData, query, args...)
 	if err != nil {
 		return transactionData, err
 	}
@@ -793,49 +654,20 @@ func (tr *TransactionsReads) GetListMerchantReportRepo(params dto.GetListMerchan
 		JOIN merchants m ON m.merchant_id = rs.merchant_id
 	`
 
-	var conditions []string
-
-	conditions = append(conditions, fmt.Sprintf("rs.merchant_id = '%v'", merchantId))
-	conditions = append(conditions, fmt.Sprintf("rs.created_by_user = '%v'", "USER_MERCHANT"))
-
-	if params.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("rs.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("rs.created_at <= '%v'", params.MaxDate))
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(rs.merchant_id LIKE '%v')", searchStr))
-	}
-
-	if params.ExportStatus != "" {
-		sliceStatus := helper.SplitString(params.ExportStatus)
-		var statusConditions []string
-		for _, status := range sliceStatus {
-			statusConditions = append(statusConditions, fmt.Sprintf("rs.status = '%v'", status))
-		}
-		conditions = append(conditions, "("+strings.Join(statusConditions, " OR ")+")")
-	}
-
-	if params.ExportType != "" {
-		sliceExportType := helper.SplitString(params.ExportType)
-		var exportTypeConditions []string
-		for _, exportType := range sliceExportType {
-			exportTypeConditions = append(exportTypeConditions, fmt.Sprintf("rs.export_type = '%v'", exportType))
-		}
-		conditions = append(conditions, "("+strings.Join(exportTypeConditions, " OR ")+")")
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
-
+	// the merchant is always matched, an empty merchant id must not list the reports of every merchant
+	filter := newQueryFilter().
+		where("rs.merchant_id = ?", merchantId).
+		equal("rs.created_by_user", "USER_MERCHANT").
+		from("rs.created_at", params.MinDate).
+		until("rs.created_at", params.MaxDate).
+		search(params.Search, "rs.merchant_id").
+		in("rs.status", params.ExportStatus).
+		in("rs.export_type", params.ExportType)
+
+	query, args := filter.build(query, "WHERE")
 	query += " ORDER BY rs.created_at DESC"
 
-	err := tr.db.Select(&listMerchantExport, query)
+	err := tr.db.Select(&listMerchantExport, query, args...)
 	if err != nil {
 		return listMerchantExport, err
 	}
@@ -878,55 +710,18 @@ func (tr *TransactionsReads) GetListMerchantExportRepo(params dto.GetListMerchan
 		`
 	}
 
-	var conditions []string
-
-	if params.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("rs.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("rs.created_at <= '%v'", params.MaxDate))
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(rs.merchant_id LIKE '%v')", searchStr))
-	}
-
-	if params.Merchants != "" {
-		sliceMerchantName := helper.SplitString(params.Merchants)
-		var merchantNameConditions []string
-		for _, merchantName := range sliceMerchantName {
-			merchantNameConditions = append(merchantNameConditions, fmt.Sprintf("rs.merchant_id = '%v'", merchantName))
-		}
-		conditions = append(conditions, "("+strings.Join(merchantNameConditions, " OR ")+")")
-	}
-
-	if params.ExportStatus != "" {
-		sliceStatus := helper.SplitString(params.ExportStatus)
-		var statusConditions []string
-		for _, status := range sliceStatus {
-			statusConditions = append(statusConditions, fmt.Sprintf("rs.status = '%v'", status))
-		}
-		conditions = append(conditions, "("+strings.Join(statusConditions, " OR ")+")")
-	}
-
-	if params.ExportType != "" {
-		sliceExportType := helper.SplitString(params.ExportType)
-		var exportTypeConditions []string
-		for _, exportType := range sliceExportType {
-			exportTypeConditions = append(exportTypeConditions, fmt.Sprintf("rs.export_type = '%v'", exportType))
-		}
-		conditions = append(conditions, "("+strings.Join(exportTypeConditions, " OR ")+")")
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	filter := newQueryFilter().
+		from("rs.created_at", params.MinDate).
+		until("rs.created_at", params.MaxDate).
+		search(params.Search, "rs.merchant_id").
+		in("rs.merchant_id", params.Merchants).
+		in("rs.status", params.ExportStatus).
+		in("rs.export_type", params.ExportType)
 
+	query, args := filter.build(query, "WHERE")
 	query += " ORDER BY rs.created_at DESC"
 
-	err := tr.db.Select(&listMerchantExport, query)
+	err := tr.db.Select(&listMerchantExport, query, args...)
 	if err != nil {
 		return listMerchantExport, err
 	}
@@ -1037,23 +832,14 @@ func (tr *TransactionsReads) GetTransactionDataByProviderChannelRepo(payload dto
 		AND t.provider_paychannel_id = $1
 	`
 
-	var conditions []string
-
-	if payload.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at >= '%v'", payload.MinDate))
-	}
-
-	if payload.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at <= '%v'", payload.MaxDate))
-	}
-
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
-	}
+	filter := newQueryFilter(payload.ProviderChannelId).
+		from("t.created_at", payload.MinDate).
+		until("t.created_at", payload.MaxDate)
 
+	query, args := filter.build(query, "AND")
 	query += " ORDER BY t.created_at DESC"
 
-	err := tr.db.Select(&transactionData, query, payload.ProviderChannelId)
+	err := tr.db.Select(&transactionData, query, args...)
 	if err != nil {
 		return transactionData, err
 	}
@@ -1061,7 +847,23 @@ func (tr *TransactionsReads) GetTransactionDataByProviderChannelRepo(payload dto
 	return transactionData, nil
 }
 
-func buildTransactionQuery(params dto.QueryParams) (string, error) {
+func transactionListFilter(params dto.QueryParams) *queryFilter {
+	return newQueryFilter().
+		from("t.created_at", params.MinDate).
+		until("t.created_at", params.MaxDate).
+		from("t.transaction_amount", params.AmountMin).
+		until("t.transaction_amount", params.AmountMax).
+		in("pm.name", params.PaymentMethod).
+		in("pm.pay_type", params.PayType).
+		in("t.status", params.Status).
+		in("t.request_method", params.RequestMethod).
+		in("ppch.paychannel_name", params.PayChannel).
+		in("m.merchant_name", params.MerchantName).
+		in("pp.provider_name", params.ProviderName).
+		search(params.Search, "t.payment_id", "t.merchant_reference_number", "t.provider_reference_number")
+}
+
+func buildTransactionQuery(params dto.QueryParams) (string, []interface{}) {
 	pageInt := converter.ToInt(params.Page)
 	pageSizeInt := converter.ToInt(params.PageSize)
 
@@ -1100,111 +902,10 @@ func buildTransactionQuery(params dto.QueryParams) (string, error) {
 		bl.bank_code = t.bank_code
 `
 
-	var conditions []string
-
-	if params.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at <= '%v'", params.MaxDate))
-	}
-
-	if params.AmountMin != "" {
-		conditions = append(conditions, fmt.Sprintf("t.transaction_amount >= '%v'", params.AmountMin))
-	}
-
-	if params.AmountMax != "" {
-		conditions = append(conditions, fmt.Sprintf("t.transaction_amount <= '%v'", params.AmountMax))
-	}
-
-	if params.PaymentMethod != "" {
-		slicePaymentMethod := helper.SplitString(params.PaymentMethod)
-		var paymentMethodConditions []string
-		for _, paymentMethod := range slicePaymentMethod {
-			paymentMethodConditions = append(paymentMethodConditions, fmt.Sprintf("pm.name = '%v'", paymentMethod))
-		}
-		conditions = append(conditions, "("+strings.Join(paymentMethodConditions, " OR ")+")")
-	}
-
-	if params.PayType != "" {
-		slicePayType := helper.SplitString(params.PayType)
-		var payTypeConditions []string
-		for _, payType := range slicePayType {
-			payTypeConditions = append(payTypeConditions, fmt.Sprintf("pm.pay_type = '%v'", payType))
-		}
-		conditions = append(conditions, "("+strings.Join(payTypeConditions, " OR ")+")")
-	}
-
-	if params.Status != "" {
-		sliceStatus := helper.SplitString(params.Status)
-		var statusConditions []string
-		for _, status := range sliceStatus {
-			statusConditions = append(statusConditions, fmt.Sprintf("t.status = '%v'", status))
-		}
-		conditions = append(conditions, "("+strings.Join(statusConditions, " OR ")+")")
-	}
-
-	if params.RequestMethod != "" {
-		sliceRequestMethod := helper.SplitString(params.RequestMethod)
-		var requestMethodConditions []string
-		for _, requestMethod := range sliceRequestMethod {
-			requestMethodConditions = append(requestMethodConditions, fmt.Sprintf("t.request_method = '%v'", requestMethod))
-		}
-		conditions = append(conditions, "("+strings.Join(requestMethodConditions, " OR ")+")")
-	}
-
-	if params.PayChannel != "" {
-		slicePayChannel := helper.SplitString(params.PayChannel)
-		var payChannelConditions []string
-		for _, payChannel := range slicePayChannel {
-			payChannelConditions = append(payChannelConditions, fmt.Sprintf("ppch.paychannel_name = '%v'", payChannel))
-		}
-		conditions = append(conditions, "("+strings.Join(payChannelConditions, " OR ")+")")
-	}
-
-	if params.MerchantName != "" {
-		sliceMerchantName := helper.SplitString(params.MerchantName)
-		var merchantNameConditions []string
-		for _, merchantName := range sliceMerchantName {
-			merchantNameConditions = append(merchantNameConditions, fmt.Sprintf("m.merchant_name = '%v'", merchantName))
-		}
-		conditions = append(conditions, "("+strings.Join(merchantNameConditions, " OR ")+")")
-	}
-
-	if params.ProviderName != "" {
-		sliceProviderName := helper.SplitString(params.ProviderName)
-		var providerNameConditions []string
-		for _, providerName := range sliceProviderName {
-			providerNameConditions = append(providerNameConditions, fmt.Sprintf("pp.provider_name = '%v'", providerName))
-		}
-		conditions = append(conditions, "("+strings.Join(providerNameConditions, " OR ")+")")
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(t.payment_id LIKE '%v' OR t.merchant_reference_number LIKE '%v' OR t.provider_reference_number LIKE '%v')", searchStr, searchStr, searchStr))
-	}
-
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
-	}
-
-	query += " ORDER BY t.created_at DESC"
-
-	if pageSizeInt > 0 {
-		query += fmt.Sprintf(" LIMIT %d", pageSizeInt)
-	}
-
-	if pageInt > 0 {
-		offset := (pageInt - 1) * pageSizeInt
-		query += fmt.Sprintf(" OFFSET %d", offset)
-	}
-
-	return query, nil
+	return transactionListFilter(params).buildPage(query, "AND", "ORDER BY t.created_at DESC", pageInt, pageSizeInt)
 }
 
-func buildCountListInQuery(params dto.QueryParams) string {
+func buildCountListInQuery(params dto.QueryParams) (string, []interface{}) {
 	query := `
 	SELECT
 		COUNT(*)
@@ -1224,54 +925,11 @@ func buildCountListInQuery(params dto.QueryParams) string {
 		AND pm.ID NOT IN (4)
 	`
 
-	var conditions []string
-
-	if params.MerchantId != "" {
-		conditions = append(conditions, fmt.Sprintf("m.merchant_id = '%v'", params.MerchantId))
-	}
-
-	if params.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at <= '%v'", params.MaxDate))
-	}
-
-	if params.PaymentMethod != "" {
-		slicePaymentMethod := helper.SplitString(params.PaymentMethod)
-		var paymentMethodConditions []string
-		for _, paymentMethod := range slicePaymentMethod {
-			paymentMethodConditions = append(paymentMethodConditions, fmt.Sprintf("pm.name = '%v'", paymentMethod))
-		}
-		conditions = append(conditions, "("+strings.Join(paymentMethodConditions, " OR ")+")")
-	}
-
-	if params.Status != "" {
-		sliceStatus := helper.SplitString(params.Status)
-		var statusConditions []string
-		for _, status := range sliceStatus {
-			statusConditions = append(statusConditions, fmt.Sprintf("t.status = '%v'", status))
-		}
-		conditions = append(conditions, "("+strings.Join(statusConditions, " OR ")+")")
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(t.payment_id LIKE '%v' OR t.merchant_reference_number LIKE '%v')", searchStr, searchStr))
-	}
-
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
-	}
-
-	return query
+	return merchantTransactionListFilter(params).build(query, "AND")
 }
 
-func buildCountQuery(params dto.QueryParams) string {
-	var query string
-
-	query = `
+func buildCountQuery(params dto.QueryParams) (string, []interface{}) {
+	query := `
 SELECT
     COUNT(*)
 FROM
@@ -1289,100 +947,21 @@ WHERE
     bl.bank_code = t.bank_code
 `
 
-	var conditions []string
-
-	if params.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at <= '%v'", params.MaxDate))
-	}
-
-	if params.AmountMin != "" {
-		conditions = append(conditions, fmt.Sprintf("t.transaction_amount >= '%v'", params.AmountMin))
-	}
-
-	if params.AmountMax != "" {
-		conditions = append(conditions, fmt.Sprintf("t.transaction_amount <= '%v'", params.AmountMax))
-	}
-
-	if params.PaymentMethod != "" {
-		slicePaymentMethod := helper.SplitString(params.PaymentMethod)
-		var paymentMethodConditions []string
-		for _, paymentMethod := range slicePaymentMethod {
-			paymentMethodConditions = append(paymentMethodConditions, fmt.Sprintf("pm.name = '%v'", paymentMethod))
-		}
-		conditions = append(conditions, "("+strings.Join(paymentMethodConditions, " OR ")+")")
-	}
-
-	if params.PayType != "" {
-		slicePayType := helper.SplitString(params.PayType)
-		var payTypeConditions []string
-		for _, payType := range slicePayType {
-			payTypeConditions = append(payTypeConditions, fmt.Sprintf("pm.pay_type = '%v'", payType))
-		}
-		conditions = append(conditions, "("+strings.Join(payTypeConditions, " OR ")+")")
-	}
-
-	if params.Status != "" {
-		sliceStatus := helper.SplitString(params.Status)
-		var statusConditions []string
-		for _, status := range sliceStatus {
-			statusConditions = append(statusConditions, fmt.Sprintf("t.status = '%v'", status))
-		}
-		conditions = append(conditions, "("+strings.Join(statusConditions, " OR ")+")")
-	}
-
-	if params.RequestMethod != "" {
-		sliceRequestMethod := helper.SplitString(params.RequestMethod)
-		var requestMethodConditions []string
-		for _, requestMethod := range sliceRequestMethod {
-			requestMethodConditions = append(requestMethodConditions, fmt.Sprintf("t.request_method = '%v'", requestMethod))
-		}
-		conditions = append(conditions, "("+strings.Join(requestMethodConditions, " OR ")+")")
-	}
-
-	if params.PayChannel != "" {
-		slicePayChannel := helper.SplitString(params.PayChannel)
-		var payChannelConditions []string
-		for _, payChannel := range slicePayChannel {
-			payChannelConditions = append(payChannelConditions, fmt.Sprintf("ppch.paychannel_name = '%v'", payChannel))
-		}
-		conditions = append(conditions, "("+strings.Join(payChannelConditions, " OR ")+")")
-	}
-
-	if params.MerchantName != "" {
-		sliceMerchantName := helper.SplitString(params.MerchantName)
-		var merchantNameConditions []string
-		for _, merchantName := range sliceMerchantName {
-			merchantNameConditions = append(merchantNameConditions, fmt.Sprintf("m.merchant_name = '%v'", merchantName))
-		}
-		conditions = append(conditions, "("+strings.Join(merchantNameConditions, " OR ")+")")
-	}
-
-	if params.ProviderName != "" {
-		sliceProviderName := helper.SplitString(params.ProviderName)
-		var providerNameConditions []string
-		for _, providerName := range sliceProviderName {
-			providerNameConditions = append(providerNameConditions, fmt.Sprintf("pp.provider_name = '%v'", providerName))
-		}
-		conditions = append(conditions, "("+strings.Join(providerNameConditions, " OR ")+")")
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(t.payment_id LIKE '%v' OR t.merchant_reference_number LIKE '%v' OR t.provider_reference_number LIKE '%v')", searchStr, searchStr, searchStr))
-	}
-
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
-	}
+	return transactionListFilter(params).build(query, "AND")
+}
 
-	return query
+func merchantCallbackListFilter(params dto.QueryParamsMerchantCallback) *queryFilter {
+	return newQueryFilter().
+		in("m.merchant_name", params.MerchantName).
+		in("pm.pay_type", params.PayType).
+		in("lc.callback_status", params.CallbackStatus).
+		in("mcd.delivery_status", params.DeliveryStatus).
+		search(params.Search, "t.payment_id", "t.merchant_reference_number", "t.provider_reference_number").
+		from("lc.created_at", params.MinDate).
+		until("lc.created_at", params.MaxDate)
 }
 
-func buildListMerchantCallbackQuery(params dto.QueryParamsMerchantCallback) (string, error) {
+func buildListMerchantCallbackQuery(params dto.QueryParamsMerchantCallback) (string, []interface{}) {
 	pageInt := converter.ToInt(params.Page)
 	pageSizeInt := converter.ToInt(params.PageSize)
 
@@ -1416,7 +995,10 @@ func buildListMerchantCallbackQuery(params dto.QueryParamsMerchantCallback) (str
 	    t.merchant_reference_number,
 	    m.merchant_name,
 		m.merchant_id,
-	    pm.pay_type
+	    pm.pay_type,
+	    mcd.delivery_status,
+	    lc.attempt_number,
+	    lc.next_retry_at
 	FROM 
 	    latest_callbacks lc
 	    JOIN transactions t ON lc.payment_id = t.payment_id
@@ -1424,55 +1006,10 @@ func buildListMerchantCallbackQuery(params dto.QueryParamsMerchantCallback) (str
 	    JOIN merchant_payment_methods mpm ON mp.merchant_payment_method_id = mpm.ID
 	    JOIN merchants m ON mpm.merchant_id = m.ID
 	    JOIN payment_methods pm ON mpm.payment_method_id = pm.ID
+	    LEFT JOIN merchant_callback_deliveries mcd ON lc.delivery_id = mcd.ID
 	`
 
-	var conditions []string
-
-	if params.MerchantName != "" {
-		merchantNames := helper.SplitString(params.MerchantName)
-		quotedMerchantNames := make([]string, len(merchantNames))
-		for i, name := range merchantNames {
-			quotedMerchantNames[i] = fmt.Sprintf("'%v'", name)
-		}
-		conditions = append(conditions, fmt.Sprintf("m.merchant_name IN (%v)", strings.Join(quotedMerchantNames, ", ")))
-	}
-
-	if params.PayType != "" {
-		payTypes := helper.SplitString(params.PayType)
-		quotedPayTypes := make([]string, len(payTypes))
-		for i, payType := range payTypes {
-			quotedPayTypes[i] = fmt.Sprintf("'%v'", payType)
-		}
-		conditions = append(conditions, fmt.Sprintf("pm.pay_type IN (%v)", strings.Join(quotedPayTypes, ", ")))
-	}
-
-	if params.CallbackStatus != "" {
-		callbackStatuses := helper.SplitString(params.CallbackStatus)
-		quotedCallbackStatuses := make([]string, len(callbackStatuses))
-		for i, status := range callbackStatuses {
-			quotedCallbackStatuses[i] = fmt.Sprintf("'%v'", status)
-		}
-		conditions = append(conditions, fmt.Sprintf("lc.callback_status IN (%v)", strings.Join(quotedCallbackStatuses, ", ")))
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(t.payment_id LIKE '%v' OR t.merchant_reference_number LIKE '%v' OR t.provider_reference_number LIKE '%v')", searchStr, searchStr, searchStr))
-	}
-
-	if params.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("lc.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("lc.created_at <= '%v'", params.MaxDate))
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
-
-	query += `
+	tail := `
 	GROUP BY
 	    lc.ID,
 	    lc.payment_id,
@@ -1485,26 +1022,18 @@ func buildListMerchantCallbackQuery(params dto.QueryParamsMerchantCallback) (str
 	    t.merchant_reference_number,
 	    m.merchant_name,
 		m.merchant_id,
-	    pm.pay_type
+	    pm.pay_type,
+	    mcd.delivery_status,
+	    lc.attempt_number,
+	    lc.next_retry_at
 	ORDER BY lc.created_at DESC
 	`
 
-	if pageSizeInt > 0 {
-		query += fmt.Sprintf(" LIMIT %d", pageSizeInt)
-	}
-
-	if pageInt > 0 {
-		offset := (pageInt - 1) * pageSizeInt
-		query += fmt.Sprintf(" OFFSET %d", offset)
-	}
-
-	return query, nil
+	return merchantCallbackListFilter(params).buildPage(query, "WHERE", tail, pageInt, pageSizeInt)
 }
 
-func buildCountQueryListMerchantCallback(params dto.QueryParamsMerchantCallback) string {
-	var query string
-
-	query = `
+func buildCountQueryListMerchantCallback(params dto.QueryParamsMerchantCallback) (string, []interface{}) {
+	query := `
 	WITH first_last_callbacks AS (
 		SELECT 
 			mc.*,
@@ -1530,58 +1059,24 @@ func buildCountQueryListMerchantCallback(params dto.QueryParamsMerchantCallback)
 		JOIN merchant_payment_methods mpm ON mp.merchant_payment_method_id = mpm.ID
 		JOIN merchants m ON mpm.merchant_id = m.ID
 		JOIN payment_methods pm ON mpm.payment_method_id = pm.ID
+		LEFT JOIN merchant_callback_deliveries mcd ON lc.delivery_id = mcd.ID
 	`
 
-	var conditions []string
-
-	if params.MerchantName != "" {
-		merchantNames := helper.SplitString(params.MerchantName)
-		quotedMerchantNames := make([]string, len(merchantNames))
-		for i, name := range merchantNames {
-			quotedMerchantNames[i] = fmt.Sprintf("'%v'", name)
-		}
-		conditions = append(conditions, fmt.Sprintf("m.merchant_name IN (%v)", strings.Join(quotedMerchantNames, ", ")))
-	}
-
-	if params.PayType != "" {
-		payTypes := helper.SplitString(params.PayType)
-		quotedPayTypes := make([]string, len(payTypes))
-		for i, payType := range payTypes {
-			quotedPayTypes[i] = fmt.Sprintf("'%v'", payType)
-		}
-		conditions = append(conditions, fmt.Sprintf("pm.pay_type IN (%v)", strings.Join(quotedPayTypes, ", ")))
-	}
-
-	if params.CallbackStatus != "" {
-		callbackStatuses := helper.SplitString(params.CallbackStatus)
-		quotedCallbackStatuses := make([]string, len(callbackStatuses))
-		for i, status := range callbackStatuses {
-			quotedCallbackStatuses[i] = fmt.Sprintf("'%v'", status)
-		}
-		conditions = append(conditions, fmt.Sprintf("lc.callback_status IN (%v)", strings.Join(quotedCallbackStatuses, ", ")))
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(t.payment_id LIKE '%v' OR t.merchant_reference_number LIKE '%v' OR t.provider_reference_number LIKE '%v')", searchStr, searchStr, searchStr))
-	}
-
-	if params.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("lc.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("lc.created_at <= '%v'", params.MaxDate))
-	}
-
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-	}
+	return merchantCallbackListFilter(params).build(query, "WHERE")
+}
 
-	return query
+// merchantTransactionListFilter filters the transaction in and transaction out lists of a merchant
+func merchantTransactionListFilter(params dto.QueryParams) *queryFilter {
+	return newQueryFilter().
+		equal("m.merchant_id", params.MerchantId).
+		from("t.created_at", params.MinDate).
+		until("t.created_at", params.MaxDate).
+		in("pm.name", params.PaymentMethod).
+		in("t.status", params.Status).
+		search(params.Search, "t.payment_id", "t.merchant_reference_number")
 }
 
-func buildListInMerchantQuery(params dto.QueryParams) (string, error) {
+func buildListInMerchantQuery(params dto.QueryParams) (string, []interface{}) {
 	pageInt := converter.ToInt(params.Page)
 	pageSizeInt := converter.ToInt(params.PageSize)
 
@@ -1611,62 +1106,10 @@ func buildListInMerchantQuery(params dto.QueryParams) (string, error) {
 		AND pm.ID NOT IN (4)
 	`
 
-	var conditions []string
-
-	if params.MerchantId != "" {
-		conditions = append(conditions, fmt.Sprintf("m.merchant_id = '%v'", params.MerchantId))
-	}
-
-	if params.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at <= '%v'", params.MaxDate))
-	}
-
-	if params.PaymentMethod != "" {
-		slicePaymentMethod := helper.SplitString(params.PaymentMethod)
-		var paymentMethodConditions []string
-		for _, paymentMethod := range slicePaymentMethod {
-			paymentMethodConditions = append(paymentMethodConditions, fmt.Sprintf("pm.name = '%v'", paymentMethod))
-		}
-		conditions = append(conditions, "("+strings.Join(paymentMethodConditions, " OR ")+")")
-	}
-
-	if params.Status != "" {
-		sliceStatus := helper.SplitString(params.Status)
-		var statusConditions []string
-		for _, status := range sliceStatus {
-			statusConditions = append(statusConditions, fmt.Sprintf("t.status = '%v'", status))
-		}
-		conditions = append(conditions, "("+strings.Join(statusConditions, " OR ")+")")
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(t.payment_id LIKE '%v' OR t.merchant_reference_number LIKE '%v')", searchStr, searchStr))
-	}
-
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
-	}
-
-	query += " ORDER BY t.created_at DESC"
-
-	if pageSizeInt > 0 {
-		query += fmt.Sprintf(" LIMIT %d", pageSizeInt)
-	}
-
-	if pageInt > 0 {
-		offset := (pageInt - 1) * pageSizeInt
-		query += fmt.Sprintf(" OFFSET %d", offset)
-	}
-
-	return query, nil
+	return merchantTransactionListFilter(params).buildPage(query, "AND", "ORDER BY t.created_at DESC", pageInt, pageSizeInt)
 }
 
-func buildListOutMerchantQuery(params dto.QueryParams) string {
+func buildListOutMerchantQuery(params dto.QueryParams) (string, []interface{}) {
 	pageInt := converter.ToInt(params.Page)
 	pageSizeInt := converter.ToInt(params.PageSize)
 
@@ -1696,63 +1139,10 @@ func buildListOutMerchantQuery(params dto.QueryParams) string {
 		AND pm.ID NOT IN (1,2,3)
 	`
 
-	var conditions []string
-
-	if params.MerchantId != "" {
-		conditions = append(conditions, fmt.Sprintf("m.merchant_id = '%v'", params.MerchantId))
-	}
-
-	if params.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at <= '%v'", params.MaxDate))
-	}
-
-	if params.PaymentMethod != "" {
-		slicePaymentMethod := helper.SplitString(params.PaymentMethod)
-		var paymentMethodConditions []string
-		for _, paymentMethod := range slicePaymentMethod {
-			paymentMethodConditions = append(paymentMethodConditions, fmt.Sprintf("pm.name = '%v'", paymentMethod))
-		}
-		conditions = append(conditions, "("+strings.Join(paymentMethodConditions, " OR ")+")")
-	}
-
-	if params.Status != "" {
-		sliceStatus := helper.SplitString(params.Status)
-		var statusConditions []string
-		for _, status := range sliceStatus {
-			statusConditions = append(statusConditions, fmt.Sprintf("t.status = '%v'", status))
-		}
-		conditions = append(conditions, "("+strings.Join(statusConditions, " OR ")+")")
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(t.payment_id LIKE '%v' OR t.merchant_reference_number LIKE '%v')", searchStr, searchStr))
-	}
-
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
-	}
-
-	query += " ORDER BY t.created_at DESC"
-
-	if pageSizeInt > 0 {
-		query += fmt.Sprintf(" LIMIT %d", pageSizeInt)
-	}
-
-	if pageInt > 0 {
-		offset := (pageInt - 1) * pageSizeInt
-		query += fmt.Sprintf(" OFFSET %d", offset)
-	}
-
-	return query
+	return merchantTransactionListFilter(params).buildPage(query, "AND", "ORDER BY t.created_at DESC", pageInt, pageSizeInt)
 }
 
-func buildCountListOutMerchantQuery(params dto.QueryParams) string {
-
+func buildCountListOutMerchantQuery(params dto.QueryParams) (string, []interface{}) {
 	query := `
 	SELECT
 		COUNT(*)
@@ -1772,46 +1162,5 @@ func buildCountListOutMerchantQuery(params dto.QueryParams) string {
 		AND pm.ID NOT IN (1,2,3)
 	`
 
-	var conditions []string
-
-	if params.MerchantId != "" {
-		conditions = append(conditions, fmt.Sprintf("m.merchant_id = '%v'", params.MerchantId))
-	}
-
-	if params.MinDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at >= '%v'", params.MinDate))
-	}
-
-	if params.MaxDate != "" {
-		conditions = append(conditions, fmt.Sprintf("t.created_at <= '%v'", params.MaxDate))
-	}
-
-	if params.PaymentMethod != "" {
-		slicePaymentMethod := helper.SplitString(params.PaymentMethod)
-		var paymentMethodConditions []string
-		for _, paymentMethod := range slicePaymentMethod {
-			paymentMethodConditions = append(paymentMethodConditions, fmt.Sprintf("pm.name = '%v'", paymentMethod))
-		}
-		conditions = append(conditions, "("+strings.Join(paymentMethodConditions, " OR ")+")")
-	}
-
-	if params.Status != "" {
-		sliceStatus := helper.SplitString(params.Status)
-		var statusConditions []string
-		for _, status := range sliceStatus {
-			statusConditions = append(statusConditions, fmt.Sprintf("t.status = '%v'", status))
-		}
-		conditions = append(conditions, "("+strings.Join(statusConditions, " OR ")+")")
-	}
-
-	if params.Search != "" {
-		searchStr := fmt.Sprintf("%%%v%%", params.Search)
-		conditions = append(conditions, fmt.Sprintf("(t.payment_id LIKE '%v' OR t.merchant_reference_number LIKE '%v')", searchStr, searchStr))
-	}
-
-	if len(conditions) > 0 {
-		query += " AND " + strings.Join(conditions, " AND ")
-	}
-
-	return query
+	return merchantTransactionListFilter(params).build(query, "AND")
 }
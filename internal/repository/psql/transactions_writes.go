@@ -1,9 +1,6 @@
 package psql
 
 import (
-	"fmt"
-	"strings"
-
 	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
@@ -11,7 +8,7 @@ import (
 )
 
 type TransactionsWrites struct {
-	db *sqlx.DB
+	db executor
 }
 
 func NewTransactionsWrites(db *sqlx.DB) *TransactionsWrites {
@@ -34,6 +31,51 @@ func (tr *TransactionsWrites) UpdateStatus(status string, paymentId string) erro
 	return nil
 }
 
+// UpdateProviderReferenceNumberRepo keeps the reference the provider gave a transaction written before it was sent
+func (tr *TransactionsWrites) UpdateProviderReferenceNumberRepo(paymentId string, providerReferenceNumber string) error {
+	query := `
+	UPDATE transactions
+	SET provider_reference_number = $1,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE payment_id = $2;
+	`
+	_, err := tr.db.Exec(query, providerReferenceNumber, paymentId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tr *TransactionsWrites) UpdateAccountInformationReferenceNumberRepo(paymentId string, accountType string, referenceNumber string) error {
+	query := `
+	UPDATE account_informations
+	SET reference_number = $1
+	WHERE payment_id = $2 AND account_type = $3;
+	`
+	_, err := tr.db.Exec(query, referenceNumber, paymentId, accountType)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (tr *TransactionsWrites) GetTransactionStatusForUpdateRepo(paymentId string) (string, error) {
+	var status string
+	query := `
+	SELECT status
+	FROM transactions
+	WHERE payment_id = $1
+	FOR UPDATE;
+	`
+
+	err := tr.db.Get(&status, query, paymentId)
+	if err != nil {
+		return status, err
+	}
+
+	return status, nil
+}
+
 func (tr *TransactionsWrites) CreateTransactionStatusLog(paymentId string, statusLog string, changeBy string, notes string, realNotes string) (int, error) {
 	var transactionStatusLogsId int
 	query := `
@@ -78,6 +120,12 @@ func (tr *TransactionsWrites) CreateTransactionStatusLog(paymentId string, statu
 func (tr *TransactionsWrites) CreateMerchantExportCapitalFlowRepo(payload dto.CreateMerchantExportReqDto) ([]entity.MerchantExportCapitalFlowEntity, error) {
 	var listData []entity.MerchantExportCapitalFlowEntity
 	var query string
+	var args []interface{}
+
+	filter := newQueryFilter().
+		equal("m.merchant_id", payload.MerchantId).
+		from("mcf.created_at", payload.MinDate).
+		until("mcf.created_at", payload.MaxDate)
 
 	if payload.ExportType == constant.ExportTypeCapitalFlow {
 		query = `
@@ -114,24 +162,7 @@ func (tr *TransactionsWrites) CreateMerchantExportCapitalFlowRepo(payload dto.Cr
 		LEFT JOIN reason_lists rl ON mcf.reason_id = rl.ID
 		`
 
-		var conditions []string
-
-		if payload.MerchantId != "" {
-			conditions = append(conditions, fmt.Sprintf("m.merchant_id = '%v'", payload.MerchantId))
-		}
-
-		if payload.MinDate != "" {
-			conditions = append(conditions, fmt.Sprintf("mcf.created_at >= '%v'", payload.MinDate))
-		}
-
-		if payload.MaxDate != "" {
-			conditions = append(conditions, fmt.Sprintf("mcf.created_at <= '%v'", payload.MaxDate))
-		}
-
-		if len(conditions) > 0 {
-			query += " WHERE " + strings.Join(conditions, " AND ")
-		}
-
+		query, args = filter.build(query, "WHERE")
 		query += " ORDER BY mcf.created_at DESC"
 	}
 
@@ -173,24 +204,7 @@ func (tr *TransactionsWrites) CreateMerchantExportCapitalFlowRepo(payload dto.Cr
 			AND pm.name NOT IN ('Disbursement')
 		`
 
-		var conditions []string
-
-		if payload.MerchantId != "" {
-			conditions = append(conditions, fmt.Sprintf("m.merchant_id = '%v'", payload.MerchantId))
-		}
-
-		if payload.MinDate != "" {
-			conditions = append(conditions, fmt.Sprintf("mcf.created_at >= '%v'", payload.MinDate))
-		}
-
-		if payload.MaxDate != "" {
-			conditions = append(conditions, fmt.Sprintf("mcf.created_at <= '%v'", payload.MaxDate))
-		}
-
-		if len(conditions) > 0 {
-			query += " AND " + strings.Join(conditions, " AND ")
-		}
-
+		query, args = filter.build(query, "AND")
 		query += " ORDER BY mcf.created_at DESC"
 	}
 
@@ -232,28 +246,11 @@ func (tr *TransactionsWrites) CreateMerchantExportCapitalFlowRepo(payload dto.Cr
 			AND pm.ID NOT IN (1, 2, 3)
 		`
 
-		var conditions []string
-
-		if payload.MerchantId != "" {
-			conditions = append(conditions, fmt.Sprintf("m.merchant_id = '%v'", payload.MerchantId))
-		}
-
-		if payload.MinDate != "" {
-			conditions = append(conditions, fmt.Sprintf("mcf.created_at >= '%v'", payload.MinDate))
-		}
-
-		if payload.MaxDate != "" {
-			conditions = append(conditions, fmt.Sprintf("mcf.created_at <= '%v'", payload.MaxDate))
-		}
-
-		if len(conditions) > 0 {
-			query += " AND " + strings.Join(conditions, " AND ")
-		}
-
+		query, args = filter.build(query, "AND")
 		query += " ORDER BY mcf.created_at DESC"
 	}
 
-	err := tr.db.Select(&listData, query)
+	err := tr.db.Select(&listData, query, args...)
 	if err != nil {
 		return listData, err
 	}
@@ -0,0 +1,67 @@
+package psql
+
+import (
+	"database/sql"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+	"github.com/jmoiron/sqlx"
+)
+
+// executor is satisfied by both *sqlx.DB and *sqlx.Tx so write repositories
+// can run standalone or inside a unit of work
+type executor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+	Get(dest interface{}, query string, args ...interface{}) error
+	Select(dest interface{}, query string, args ...interface{}) error
+}
+
+type UnitOfWork struct {
+	db *sqlx.DB
+}
+
+func NewUnitOfWork(db *sqlx.DB) *UnitOfWork {
+	return &UnitOfWork{
+		db: db,
+	}
+}
+
+// WithinTransaction runs fn inside a single database transaction, every write
+// done through the given repositories is rolled back when fn returns an error
+func (uow *UnitOfWork) WithinTransaction(fn func(repos internal.UnitOfWorkRepos) error) error {
+	tx, err := uow.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	repos := internal.UnitOfWorkRepos{
+		TransactionsWrites:         &TransactionsWrites{db: tx},
+		MerchantWrites:             &MerchantWrites{db: tx},
+		ProviderWrites:             &ProviderWrites{db: tx},
+		UserWrites:                 &UsersWrites{db: tx},
+		LedgerWrites:               &LedgerWrites{db: tx},
+		AuditWrites:                &AuditWrites{db: tx},
+		HashChainWrites:            &HashChainWrites{db: tx},
+		BalanceApprovalWrites:      &BalanceApprovalWrites{db: tx},
+		DisbursementApprovalWrites: &DisbursementApprovalWrites{db: tx},
+		DisbursementBatchWrites:    &DisbursementBatchWrites{db: tx},
+	}
+
+	err = fn(repos)
+	if err != nil {
+		if errRollback := tx.Rollback(); errRollback != nil {
+			slog.Errorw("failed to rollback unit of work", "stack_trace", errRollback.Error())
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
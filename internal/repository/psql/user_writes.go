@@ -1,13 +1,17 @@
 package psql
 
 import (
+	"time"
+
 	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type UsersWrites struct {
-	db *sqlx.DB
+	db executor
 }
 
 func NewUsersWrites(db *sqlx.DB) *UsersWrites {
@@ -50,3 +54,728 @@ func (uw *UsersWrites) UpdatePassOrPinRepo(passHash string, pinHash string, user
 	}
 	return nil
 }
+
+// CreateRoleRepo returns sql.ErrNoRows when the role name is already taken
+func (uw *UsersWrites) CreateRoleRepo(roleName string, twoFactorRequired bool, merchantAssignable bool) (int, error) {
+	var roleId int
+
+	query := `
+	INSERT INTO roles (role_name, two_factor_required, merchant_assignable, created_at, updated_at)
+	VALUES ($1, $2, $3, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	ON CONFLICT (role_name) DO NOTHING
+	RETURNING id
+	`
+
+	row := uw.db.QueryRow(query, roleName, twoFactorRequired, merchantAssignable)
+	err := row.Scan(&roleId)
+	if err != nil || roleId == 0 {
+		return roleId, err
+	}
+
+	return roleId, nil
+}
+
+func (uw *UsersWrites) DeleteRolePermissionsRepo(roleId int) error {
+	query := `
+	DELETE FROM role_permissions
+	WHERE role_id = $1;
+	`
+
+	_, err := uw.db.Exec(query, roleId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (uw *UsersWrites) CreateRolePermissionsRepo(roleId int, permissionIds []int) error {
+	query := `
+	INSERT INTO role_permissions (role_id, permission_id, created_at, updated_at)
+	SELECT $1, UNNEST($2::INT[]), CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	ON CONFLICT (role_id, permission_id) DO NOTHING;
+	`
+
+	_, err := uw.db.Exec(query, roleId, pq.Array(permissionIds))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (uw *UsersWrites) CreateSessionRepo(session entity.UserSessionEntity, refreshTokenHash string, ttl time.Duration) (int, error) {
+	var id int
+
+	query := `
+	INSERT INTO user_sessions (session_id, user_id, device, ip_address, refresh_token_hash, expires_at, last_used_at, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' + make_interval(secs => $6), CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	RETURNING id
+	`
+
+	row := uw.db.QueryRow(query, session.SessionId, session.UserId, session.Device, session.IpAddress, refreshTokenHash, ttl.Seconds())
+	err := row.Scan(&id)
+	if err != nil || id == 0 {
+		return id, err
+	}
+
+	return id, nil
+}
+
+// RotateSessionRefreshTokenRepo swaps the refresh token of a live session in one statement so a token is only
+// ever redeemed once, it returns sql.ErrNoRows when no live session holds refreshTokenHash
+func (uw *UsersWrites) RotateSessionRefreshTokenRepo(refreshTokenHash string, newRefreshTokenHash string) (entity.UserSessionEntity, error) {
+	var session entity.UserSessionEntity
+
+	query := `
+	UPDATE user_sessions s
+	SET previous_refresh_token_hash = s.refresh_token_hash,
+		refresh_token_hash = $1,
+		last_used_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta',
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	FROM users u
+	WHERE u.id = s.user_id
+		AND s.refresh_token_hash = $2
+		AND s.revoked_at IS NULL
+		AND s.expires_at > CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	RETURNING
+		s.id,
+		s.session_id,
+		s.user_id,
+		u.username,
+		COALESCE(s.device, '') AS device,
+		COALESCE(s.ip_address, '') AS ip_address,
+		s.expires_at,
+		s.last_used_at,
+		s.revoked_at,
+		s.created_at
+	`
+
+	err := uw.db.Get(&session, query, newRefreshTokenHash, refreshTokenHash)
+	if err != nil {
+		return session, err
+	}
+
+	return session, nil
+}
+
+func (uw *UsersWrites) RevokeSessionRepo(sessionId string) error {
+	query := `
+	UPDATE user_sessions
+	SET revoked_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta',
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE session_id = $1 AND revoked_at IS NULL;
+	`
+
+	_, err := uw.db.Exec(query, sessionId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// RevokeSessionByPreviousRefreshTokenRepo revokes the session a rotated out refresh token belonged to, a
+// replayed token means it leaked so neither holder keeps the session
+func (uw *UsersWrites) RevokeSessionByPreviousRefreshTokenRepo(refreshTokenHash string) error {
+	query := `
+	UPDATE user_sessions
+	SET revoked_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta',
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE previous_refresh_token_hash = $1 AND revoked_at IS NULL;
+	`
+
+	_, err := uw.db.Exec(query, refreshTokenHash)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (uw *UsersWrites) RevokeUserSessionsRepo(userId int) error {
+	query := `
+	UPDATE user_sessions
+	SET revoked_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta',
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE user_id = $1 AND revoked_at IS NULL;
+	`
+
+	_, err := uw.db.Exec(query, userId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (uw *UsersWrites) UpdateRoleMerchantAssignableRepo(roleId int, merchantAssignable bool) error {
+	query := `
+	UPDATE roles
+	SET merchant_assignable = $1,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $2;
+	`
+
+	_, err := uw.db.Exec(query, merchantAssignable, roleId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (uw *UsersWrites) UpdateRoleTwoFactorRepo(roleId int, twoFactorRequired bool) error {
+	query := `
+	UPDATE roles
+	SET two_factor_required = $1,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $2;
+	`
+
+	_, err := uw.db.Exec(query, twoFactorRequired, roleId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (uw *UsersWrites) CreateLoginChallengeRepo(userId int, challengeHash string, purpose string, ttl time.Duration) (int, error) {
+	var id int
+
+	query := `
+	INSERT INTO user_login_challenges (challenge_hash, user_id, purpose, expires_at, created_at)
+	VALUES ($1, $2, $3, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' + make_interval(secs => $4), CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	RETURNING id
+	`
+
+	row := uw.db.QueryRow(query, challengeHash, userId, purpose, ttl.Seconds())
+	err := row.Scan(&id)
+	if err != nil || id == 0 {
+		return id, err
+	}
+
+	return id, nil
+}
+
+// AttemptLoginChallengeRepo counts an attempt against a live challenge, it returns sql.ErrNoRows when the
+// challenge is unknown, used, expired or out of attempts
+func (uw *UsersWrites) AttemptLoginChallengeRepo(challengeHash string, maxAttempts int) (entity.LoginChallengeEntity, error) {
+	var challenge entity.LoginChallengeEntity
+
+	query := `
+	UPDATE user_login_challenges c
+	SET attempts = c.attempts + 1
+	FROM users u
+	WHERE u.id = c.user_id
+		AND c.challenge_hash = $1
+		AND c.used_at IS NULL
+		AND c.expires_at > CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+		AND c.attempts < $2
+	RETURNING
+		c.id,
+		c.user_id,
+		u.username,
+		c.purpose,
+		c.attempts,
+		c.expires_at,
+		c.used_at
+	`
+
+	err := uw.db.Get(&challenge, query, challengeHash, maxAttempts)
+	if err != nil {
+		return challenge, err
+	}
+
+	return challenge, nil
+}
+
+func (uw *UsersWrites) UseLoginChallengeRepo(challengeId int) error {
+	query := `
+	UPDATE user_login_challenges
+	SET used_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $1;
+	`
+
+	_, err := uw.db.Exec(query, challengeId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UpdateTotpSecretRepo stores a pending secret, an enabled secret is never replaced
+func (uw *UsersWrites) UpdateTotpSecretRepo(userId int, secret string) error {
+	query := `
+	UPDATE users
+	SET totp_secret = $1,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $2 AND two_factor_enabled = FALSE;
+	`
+
+	_, err := uw.db.Exec(query, secret, userId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (uw *UsersWrites) EnableTwoFactorRepo(userId int, step int64) error {
+	query := `
+	UPDATE users
+	SET two_factor_enabled = TRUE,
+		two_factor_enabled_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta',
+		totp_last_used_step = $1,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $2;
+	`
+
+	_, err := uw.db.Exec(query, step, userId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (uw *UsersWrites) DisableTwoFactorRepo(userId int) error {
+	query := `
+	UPDATE users
+	SET two_factor_enabled = FALSE,
+		two_factor_enabled_at = NULL,
+		totp_secret = NULL,
+		totp_last_used_step = NULL,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $1;
+	`
+
+	_, err := uw.db.Exec(query, userId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UseTotpStepRepo records step as the last accepted code, it returns sql.ErrNoRows when step isn't newer
+// than the last one so a code can't be replayed
+func (uw *UsersWrites) UseTotpStepRepo(userId int, step int64) error {
+	var id int
+
+	query := `
+	UPDATE users
+	SET totp_last_used_step = $1
+	WHERE id = $2 AND COALESCE(totp_last_used_step, -1) < $1
+	RETURNING id
+	`
+
+	row := uw.db.QueryRow(query, step, userId)
+	err := row.Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (uw *UsersWrites) DeleteRecoveryCodesRepo(userId int) error {
+	query := `
+	DELETE FROM user_recovery_codes
+	WHERE user_id = $1;
+	`
+
+	_, err := uw.db.Exec(query, userId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (uw *UsersWrites) CreateRecoveryCodesRepo(userId int, codeHashes []string) error {
+	query := `
+	INSERT INTO user_recovery_codes (user_id, code_hash, created_at)
+	SELECT $1, UNNEST($2::VARCHAR[]), CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	`
+
+	_, err := uw.db.Exec(query, userId, pq.Array(codeHashes))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// UseRecoveryCodeRepo marks an unused code as used, it returns sql.ErrNoRows when the user has no such code
+func (uw *UsersWrites) UseRecoveryCodeRepo(userId int, codeHash string) error {
+	var id int
+
+	query := `
+	UPDATE user_recovery_codes
+	SET used_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	RETURNING id
+	`
+
+	row := uw.db.QueryRow(query, userId, codeHash)
+	err := row.Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAuthAttemptRepo reads from the primary so a lockout applies to the very next attempt, it returns
+// sql.ErrNoRows when the subject has no failures
+func (uw *UsersWrites) GetAuthAttemptRepo(scope string, subject string, credential string) (entity.AuthAttemptEntity, error) {
+	var attempt entity.AuthAttemptEntity
+
+	query := `
+	SELECT
+		scope,
+		subject,
+		credential,
+		failed_count,
+		GREATEST(CEIL(EXTRACT(EPOCH FROM (COALESCE(next_attempt_at, '-infinity') - CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'))), 0)::INT AS wait_seconds,
+		GREATEST(CEIL(EXTRACT(EPOCH FROM (COALESCE(locked_until, '-infinity') - CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'))), 0)::INT AS locked_seconds
+	FROM
+		auth_attempts
+	WHERE
+		scope = $1 AND subject = $2 AND credential = $3;
+	`
+
+	err := uw.db.Get(&attempt, query, scope, subject, credential)
+	if err != nil {
+		return attempt, err
+	}
+
+	return attempt, nil
+}
+
+// RecordAuthFailureRepo counts a failure and returns the failures in a row, the count restarts when the
+// last failure is older than window
+func (uw *UsersWrites) RecordAuthFailureRepo(scope string, subject string, credential string, window time.Duration) (int, error) {
+	var failedCount int
+
+	query := `
+	INSERT INTO auth_attempts (scope, subject, credential, failed_count, last_failed_at, created_at, updated_at)
+	VALUES ($1, $2, $3, 1, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	ON CONFLICT (scope, subject, credential) DO UPDATE
+	SET failed_count = CASE
+			WHEN auth_attempts.last_failed_at < CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' - make_interval(secs => $4) THEN 1
+			ELSE auth_attempts.failed_count + 1
+		END,
+		last_failed_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta',
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	RETURNING failed_count
+	`
+
+	row := uw.db.QueryRow(query, scope, subject, credential, window.Seconds())
+	err := row.Scan(&failedCount)
+	if err != nil {
+		return failedCount, err
+	}
+
+	return failedCount, nil
+}
+
+// UpdateAuthAttemptDelayRepo holds the next check back by delay, a non zero lockout also locks the subject
+// and starts its count again for when the lockout ends
+func (uw *UsersWrites) UpdateAuthAttemptDelayRepo(scope string, subject string, credential string, delay time.Duration, lockout time.Duration) error {
+	query := `
+	UPDATE auth_attempts
+	SET next_attempt_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' + make_interval(secs => $1),
+		locked_until = CASE WHEN $2::float8 > 0 THEN CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' + make_interval(secs => $2::float8) ELSE locked_until END,
+		failed_count = CASE WHEN $2::float8 > 0 THEN 0 ELSE failed_count END,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE scope = $3 AND subject = $4 AND credential = $5;
+	`
+
+	_, err := uw.db.Exec(query, delay.Seconds(), lockout.Seconds(), scope, subject, credential)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (uw *UsersWrites) ResetAuthAttemptRepo(scope string, subject string, credential string) error {
+	query := `
+	DELETE FROM auth_attempts
+	WHERE scope = $1 AND subject = $2 AND credential = $3;
+	`
+
+	_, err := uw.db.Exec(query, scope, subject, credential)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteAuthAttemptsRepo clears every credential of the subject
+func (uw *UsersWrites) DeleteAuthAttemptsRepo(scope string, subject string) error {
+	query := `
+	DELETE FROM auth_attempts
+	WHERE scope = $1 AND subject = $2;
+	`
+
+	_, err := uw.db.Exec(query, scope, subject)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (uw *UsersWrites) CreateCredentialResetRepo(userId int, tokenHash string, credential string, ipAddress string, ttl time.Duration) (int, error) {
+	var id int
+
+	query := `
+	INSERT INTO credential_resets (token_hash, user_id, credential, ip_address, expires_at, created_at)
+	VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' + make_interval(secs => $5), CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	RETURNING id
+	`
+
+	row := uw.db.QueryRow(query, tokenHash, userId, credential, ipAddress, ttl.Seconds())
+	err := row.Scan(&id)
+	if err != nil || id == 0 {
+		return id, err
+	}
+
+	return id, nil
+}
+
+// UseCredentialResetRepo marks a live reset as used and returns it, it returns sql.ErrNoRows when the reset
+// is unknown, already used or expired
+func (uw *UsersWrites) UseCredentialResetRepo(tokenHash string) (entity.CredentialResetEntity, error) {
+	var reset entity.CredentialResetEntity
+
+	query := `
+	UPDATE credential_resets r
+	SET used_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	FROM users u
+	WHERE u.id = r.user_id
+		AND r.token_hash = $1
+		AND r.used_at IS NULL
+		AND r.expires_at > CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	RETURNING
+		r.id,
+		r.user_id,
+		u.username,
+		r.credential
+	`
+
+	err := uw.db.Get(&reset, query, tokenHash)
+	if err != nil {
+		return reset, err
+	}
+
+	return reset, nil
+}
+
+// ExpireCredentialResetsRepo ends every reset link of the user that wasn't used yet
+func (uw *UsersWrites) ExpireCredentialResetsRepo(userId int) error {
+	query := `
+	UPDATE credential_resets
+	SET used_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE user_id = $1 AND used_at IS NULL;
+	`
+
+	_, err := uw.db.Exec(query, userId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateInvitationRepo returns sql.ErrNoRows when the email already has a pending invitation
+func (uw *UsersWrites) CreateInvitationRepo(payload dto.InviteMerchantUserDto, tokenHash string, ttl time.Duration) (int, error) {
+	var id int
+
+	query := `
+	INSERT INTO user_invitations (token_hash, email, merchant_id, role_id, invited_by, status, expires_at, created_at, updated_at)
+	VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' + make_interval(secs => $7), CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta', CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta')
+	ON CONFLICT (email) WHERE status = 'PENDING' DO NOTHING
+	RETURNING id
+	`
+
+	row := uw.db.QueryRow(query, tokenHash, payload.Email, payload.MerchantId, payload.RolesId, payload.Username, constant.InvitationStatusPending, ttl.Seconds())
+	err := row.Scan(&id)
+	if err != nil || id == 0 {
+		return id, err
+	}
+
+	return id, nil
+}
+
+// AcceptInvitationRepo marks a live pending invitation as accepted and returns it, it returns sql.ErrNoRows
+// when the invitation is unknown, no longer pending or expired
+func (uw *UsersWrites) AcceptInvitationRepo(tokenHash string) (entity.InvitationEntity, error) {
+	var invitation entity.InvitationEntity
+
+	query := `
+	UPDATE user_invitations
+	SET status = $1,
+		accepted_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta',
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE token_hash = $2
+		AND status = $3
+		AND expires_at > CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	RETURNING
+		id,
+		email,
+		merchant_id,
+		role_id,
+		COALESCE(invited_by, '') AS invited_by,
+		status,
+		expires_at,
+		created_at
+	`
+
+	err := uw.db.Get(&invitation, query, constant.InvitationStatusAccepted, tokenHash, constant.InvitationStatusPending)
+	if err != nil {
+		return invitation, err
+	}
+
+	return invitation, nil
+}
+
+// ResendInvitationRepo swaps the token of a pending invitation and starts its expiry again, the link sent
+// before stops working. It returns sql.ErrNoRows when the merchant has no such pending invitation.
+func (uw *UsersWrites) ResendInvitationRepo(invitationId int, merchantId string, tokenHash string, ttl time.Duration) (entity.InvitationEntity, error) {
+	var invitation entity.InvitationEntity
+
+	query := `
+	UPDATE user_invitations
+	SET token_hash = $1,
+		expires_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta' + make_interval(secs => $2),
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $3 AND merchant_id = $4 AND status = $5
+	RETURNING
+		id,
+		email,
+		merchant_id,
+		role_id,
+		COALESCE(invited_by, '') AS invited_by,
+		status,
+		expires_at,
+		created_at
+	`
+
+	err := uw.db.Get(&invitation, query, tokenHash, ttl.Seconds(), invitationId, merchantId, constant.InvitationStatusPending)
+	if err != nil {
+		return invitation, err
+	}
+
+	return invitation, nil
+}
+
+// RevokeInvitationRepo returns sql.ErrNoRows when the merchant has no such pending invitation
+func (uw *UsersWrites) RevokeInvitationRepo(invitationId int, merchantId string) error {
+	var id int
+
+	query := `
+	UPDATE user_invitations
+	SET status = $1,
+		revoked_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta',
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $2 AND merchant_id = $3 AND status = $4
+	RETURNING id
+	`
+
+	row := uw.db.QueryRow(query, constant.InvitationStatusRevoked, invitationId, merchantId, constant.InvitationStatusPending)
+	err := row.Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetMerchantUserForUpdateRepo locks a user of the merchant, it returns sql.ErrNoRows when the user belongs
+// to another merchant
+func (uw *UsersWrites) GetMerchantUserForUpdateRepo(userId int, merchantId string) (entity.ListUsersEntity, error) {
+	var user entity.ListUsersEntity
+
+	query := `
+	SELECT
+		u.id,
+		u.username,
+		u.email,
+		u.created_at,
+		u.status,
+		u.role_id,
+		r.role_name
+	FROM
+		users u
+		JOIN roles r ON r.ID = u.role_id
+	WHERE
+		u.id = $1 AND u.merchantid = $2 AND u.user_type = $3
+	FOR UPDATE OF u
+	`
+
+	err := uw.db.Get(&user, query, userId, merchantId, constant.UserMerchant)
+	if err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+// GetActiveMerchantAdminIdsForUpdateRepo locks the active admins of a merchant so two changes can't both
+// take away what they think is one of several admins
+func (uw *UsersWrites) GetActiveMerchantAdminIdsForUpdateRepo(merchantId string) ([]int, error) {
+	var userIds []int
+
+	query := `
+	SELECT
+		u.id
+	FROM
+		users u
+		JOIN roles r ON r.ID = u.role_id
+	WHERE
+		u.merchantid = $1 AND u.status = $2 AND r.role_name = $3
+	ORDER BY u.id
+	FOR UPDATE OF u
+	`
+
+	err := uw.db.Select(&userIds, query, merchantId, constant.StatusActive, constant.RoleNameAdmin)
+	if err != nil {
+		return userIds, err
+	}
+
+	return userIds, nil
+}
+
+func (uw *UsersWrites) UpdateUserStatusRepo(userId int, status string) error {
+	query := `
+	UPDATE users
+	SET status = $1,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $2;
+	`
+
+	_, err := uw.db.Exec(query, status, userId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (uw *UsersWrites) UpdateUserRoleRepo(userId int, roleId int) error {
+	query := `
+	UPDATE users
+	SET role_id = $1,
+		updated_at = CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+	WHERE id = $2;
+	`
+
+	_, err := uw.db.Exec(query, roleId, userId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// DeleteUserRepo removes the user, its sessions, two factor codes and resets go with it
+func (uw *UsersWrites) DeleteUserRepo(userId int) error {
+	query := `
+	DELETE FROM users
+	WHERE id = $1;
+	`
+
+	_, err := uw.db.Exec(query, userId)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package psql
 import (
 	"database/sql"
 
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
 	"github.com/jmoiron/sqlx"
@@ -30,14 +31,17 @@ func (u *UserReads) GetUserByUsername(username string) (entity.User, error) {
 		u.pin,
 		m.merchant_id,
 		m.merchant_name,
-		m.merchant_secret,
 		m.currency,
 		m.status AS merchant_status,
 		r.id AS role_id,
 		r.role_name,
 		u.status AS user_status,
 		u.created_at AS user_created_at,
-		u.updated_at AS user_updated_at
+		u.updated_at AS user_updated_at,
+		u.two_factor_enabled,
+		COALESCE(r.two_factor_required, FALSE) AS two_factor_required,
+		u.totp_secret,
+		u.totp_last_used_step
 	FROM
 		users u
 	LEFT JOIN
@@ -63,6 +67,7 @@ func (u *UserReads) GetPermissionByRoleId(id int) ([]entity.Permission, error) {
 	query := `
 	SELECT
 		p.ID AS permission_id,
+		COALESCE(p.permission_name, '') AS permission_name,
 		p.permission_desc
 	FROM
 		roles r
@@ -81,6 +86,53 @@ func (u *UserReads) GetPermissionByRoleId(id int) ([]entity.Permission, error) {
 	return permissions, nil
 }
 
+func (u *UserReads) GetNamedPermissionsRepo() ([]entity.Permission, error) {
+	var permissions []entity.Permission
+
+	query := `
+	SELECT
+		p.ID AS permission_id,
+		p.permission_name,
+		p.permission_desc
+	FROM
+		permissions p
+	WHERE
+		p.permission_name IS NOT NULL
+	ORDER BY p.permission_name;
+	`
+
+	err := u.db.Select(&permissions, query)
+	if err != nil {
+		return permissions, err
+	}
+
+	return permissions, nil
+}
+
+func (u *UserReads) GetRoleByIdRepo(id int) (entity.RoleEntity, error) {
+	var role entity.RoleEntity
+
+	query := `
+	SELECT
+		r.ID,
+		r.role_name,
+		r.two_factor_required,
+		r.merchant_assignable,
+		r.created_at
+	FROM
+		roles r
+	WHERE
+		r.ID = $1;
+	`
+
+	err := u.db.Get(&role, query, id)
+	if err != nil {
+		return role, err
+	}
+
+	return role, nil
+}
+
 func (u *UserReads) GetRolesRepo() ([]entity.RolesEntity, error) {
 	var listRoles []entity.RolesEntity
 
@@ -114,6 +166,7 @@ func (u *UserReads) GetListUserByMerchantIdRepo(merchantId string) ([]entity.Lis
 		u.email,
 		u.created_at,
 		u.status,
+		u.role_id,
 		r.role_name
 	FROM
 		users u
@@ -131,3 +184,73 @@ func (u *UserReads) GetListUserByMerchantIdRepo(merchantId string) ([]entity.Lis
 
 	return listUsers, nil
 }
+
+// GetActiveSessionRepo returns sql.ErrNoRows when the session is revoked, expired or its user is no longer active
+func (u *UserReads) GetActiveSessionRepo(sessionId string) (entity.UserSessionEntity, error) {
+	var session entity.UserSessionEntity
+
+	query := `
+	SELECT
+		s.id,
+		s.session_id,
+		s.user_id,
+		u.username,
+		COALESCE(s.device, '') AS device,
+		COALESCE(s.ip_address, '') AS ip_address,
+		s.expires_at,
+		s.last_used_at,
+		s.revoked_at,
+		s.created_at
+	FROM
+		user_sessions s
+	JOIN
+		users u ON u.id = s.user_id
+	WHERE
+		s.session_id = $1
+		AND s.revoked_at IS NULL
+		AND s.expires_at > CURRENT_TIMESTAMP AT TIME ZONE 'Asia/Jakarta'
+		AND u.status = $2;
+	`
+
+	err := u.db.Get(&session, query, sessionId, constant.StatusActive)
+	if err != nil {
+		return session, err
+	}
+
+	return session, nil
+}
+
+// GetListPendingInvitationsRepo lists the invitations of a merchant that weren't accepted or revoked, expired
+// ones included so they can be resent
+func (u *UserReads) GetListPendingInvitationsRepo(merchantId string) ([]entity.InvitationEntity, error) {
+	var invitations []entity.InvitationEntity
+
+	query := `
+	SELECT
+		i.id,
+		i.email,
+		i.merchant_id,
+		i.role_id,
+		r.role_name,
+		COALESCE(i.invited_by, '') AS invited_by,
+		i.status,
+		i.expires_at,
+		i.created_at
+	FROM
+		user_invitations i
+		JOIN roles r ON r.ID = i.role_id
+	WHERE
+		i.merchant_id = $1
+		AND i.status = $2
+	ORDER BY
+		i.created_at DESC;
+	`
+
+	err := u.db.Select(&invitations, query, merchantId, constant.InvitationStatusPending)
+	if err != nil && err != sql.ErrNoRows {
+		slog.Errorw("unexpected error", "stack_trace", err.Error())
+		return invitations, err
+	}
+
+	return invitations, nil
+}
@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+	"github.com/labstack/echo/v4"
+)
+
+// AuditMiddleware keeps every mutating request of a logged in user in the audit trail together with its query,
+// its body and its response code. It wraps the route group so it sees the user set by AuthMiddleware once the
+// handler returns, requests refused before login are left out.
+func (ctrl *Controller) AuditMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		if req.Method == http.MethodGet || req.Method == http.MethodHead || req.Method == http.MethodOptions {
+			return next(c)
+		}
+
+		body := auditRequestBody(req)
+		err := next(c)
+
+		username, _ := c.Get("username").(string)
+		if username == "" {
+			return err
+		}
+
+		responseCode := c.Response().Status
+		if err != nil && !c.Response().Committed {
+			responseCode = http.StatusInternalServerError
+			if httpErr, ok := err.(*echo.HTTPError); ok {
+				responseCode = httpErr.Code
+			}
+		}
+
+		errRecord := ctrl.auditService.RecordRequestSvc(dto.AuditEntry{
+			Activity: req.Method + " " + c.Path(),
+			After: map[string]interface{}{
+				"query": c.QueryParams(),
+				"body":  body,
+			},
+			ResponseCode: responseCode,
+			Actor:        auditActor(c),
+		})
+		if errRecord != nil {
+			slog.Errorw("failed record audit request", "stack_trace", errRecord.Error())
+		}
+
+		return err
+	}
+}
+
+// auditActor describes the user behind the request, the request id is set by the request id middleware
+func auditActor(c echo.Context) dto.AuditActor {
+	username, _ := c.Get("username").(string)
+
+	return dto.AuditActor{
+		Username:  username,
+		IpAddress: c.RealIP(),
+		RequestId: c.Response().Header().Get(echo.HeaderXRequestID),
+	}
+}
+
+// auditRequestBody reads a json body without taking it away from the handler, other bodies are only described
+func auditRequestBody(req *http.Request) interface{} {
+	if req.Body == nil || req.ContentLength == 0 {
+		return nil
+	}
+
+	contentType := req.Header.Get(echo.HeaderContentType)
+	if !strings.HasPrefix(contentType, echo.MIMEApplicationJSON) {
+		return map[string]interface{}{"contentType": contentType, "contentLength": req.ContentLength}
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(req.Body, constant.AuditMaxBody+1))
+	req.Body = io.NopCloser(io.MultiReader(bytes.NewReader(raw), req.Body))
+	if err != nil {
+		return nil
+	}
+
+	if len(raw) > constant.AuditMaxBody {
+		return map[string]interface{}{"contentType": contentType, "note": fmt.Sprintf("body larger than %v bytes is not kept", constant.AuditMaxBody)}
+	}
+
+	var body interface{}
+	err = json.Unmarshal(raw, &body)
+	if err != nil {
+		return map[string]interface{}{"contentType": contentType, "note": "body is not valid json"}
+	}
+
+	return body
+}
+
+func (ctrl *Controller) GetListAuditLogCtrl(c echo.Context) error {
+	userType := c.Get("userType").(string)
+	var queryParamsAuditLog dto.QueryParamsAuditLog
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	queryParamsAuditLog.Username = c.QueryParam("username")
+	queryParamsAuditLog.HistoryType = c.QueryParam("historyType")
+	queryParamsAuditLog.Activity = c.QueryParam("activity")
+	queryParamsAuditLog.TargetId = c.QueryParam("targetId")
+	queryParamsAuditLog.RequestId = c.QueryParam("requestId")
+	queryParamsAuditLog.IpAddress = c.QueryParam("ipAddress")
+	queryParamsAuditLog.MinDate = c.QueryParam("minDate")
+	queryParamsAuditLog.MaxDate = c.QueryParam("maxDate")
+	queryParamsAuditLog.Search = c.QueryParam("search")
+	queryParamsAuditLog.Page = c.QueryParam("page")
+	queryParamsAuditLog.PageSize = c.QueryParam("pageSize")
+
+	listAuditLog, err := ctrl.auditService.GetListAuditLogSvc(queryParamsAuditLog)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, listAuditLog)
+	}
+
+	return c.JSON(http.StatusOK, listAuditLog)
+}
@@ -0,0 +1,170 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
+	"github.com/labstack/echo/v4"
+)
+
+func (ctrl *Controller) ApproveBalanceOperationRequestCtrl(c echo.Context) error {
+	return ctrl.reviewBalanceOperationRequest(c, true)
+}
+
+func (ctrl *Controller) RejectBalanceOperationRequestCtrl(c echo.Context) error {
+	return ctrl.reviewBalanceOperationRequest(c, false)
+}
+
+func (ctrl *Controller) reviewBalanceOperationRequest(c echo.Context, approve bool) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+	var payload dto.ReviewBalanceOperationReq
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if payload.BalanceOperationRequestId == 0 || payload.Notes == "" || payload.Pin == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "balance operation request id, notes, and pin is mandatory",
+		})
+	}
+
+	payload.Approve = approve
+	payload.Username = username
+	payload.Actor = auditActor(c)
+	reviewResp, err := ctrl.merchantService.ReviewBalanceOperationRequestSvc(payload)
+	if err != nil {
+		return c.JSON(reviewResp.ResponseCode, reviewResp)
+	}
+
+	return c.JSON(http.StatusOK, reviewResp)
+}
+
+func (ctrl *Controller) GetListBalanceOperationRequestCtrl(c echo.Context) error {
+	userType := c.Get("userType").(string)
+	var params dto.QueryParamsBalanceOperationRequest
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	params.Status = c.QueryParam("status")
+	params.OperationType = c.QueryParam("operationType")
+	params.MerchantId = c.QueryParam("merchantId")
+	params.RequestedBy = c.QueryParam("requestedBy")
+	params.MinDate = c.QueryParam("minDate")
+	params.MaxDate = c.QueryParam("maxDate")
+	params.Page = c.QueryParam("page")
+	params.PageSize = c.QueryParam("pageSize")
+
+	listResp, err := ctrl.merchantService.GetListBalanceOperationRequestSvc(params)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, listResp)
+	}
+
+	return c.JSON(http.StatusOK, listResp)
+}
+
+func (ctrl *Controller) GetBalanceOperationRequestDetailCtrl(c echo.Context) error {
+	userType := c.Get("userType").(string)
+	requestId := c.QueryParam("balanceOperationRequestId")
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	if requestId == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "balance operation request id is mandatory",
+		})
+	}
+
+	detailResp, err := ctrl.merchantService.GetBalanceOperationRequestDetailSvc(converter.ToInt(requestId))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, detailResp)
+	}
+
+	return c.JSON(http.StatusOK, detailResp)
+}
+
+func (ctrl *Controller) GetListBalanceApprovalThresholdCtrl(c echo.Context) error {
+	userType := c.Get("userType").(string)
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	listResp, err := ctrl.merchantService.GetListBalanceApprovalThresholdSvc()
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, listResp)
+	}
+
+	return c.JSON(http.StatusOK, listResp)
+}
+
+func (ctrl *Controller) UpdateBalanceApprovalThresholdCtrl(c echo.Context) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+	var payload dto.UpdateBalanceApprovalThresholdReq
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if payload.OperationType == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "operation type is mandatory",
+		})
+	}
+
+	payload.Username = username
+	payload.Actor = auditActor(c)
+	updateResp, err := ctrl.merchantService.UpdateBalanceApprovalThresholdSvc(payload)
+	if err != nil {
+		return c.JSON(updateResp.ResponseCode, updateResp)
+	}
+
+	return c.JSON(http.StatusOK, updateResp)
+}
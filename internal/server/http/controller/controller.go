@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"fmt"
 	"net/http"
 	"time"
 
@@ -8,16 +9,21 @@ import (
 	"github.com/golang-jwt/jwt/request"
 	"github.com/hypay-id/backend-dashboard-hypay/config"
 	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
 	"github.com/labstack/echo/v4"
 )
 
 type Controller struct {
-	cfg                config.Schema
-	transactionService internal.TransactionServiceItf
-	merchantService    internal.MerchantServiceItf
-	userService        internal.UserServiceItf
-	providerService    internal.ProviderServiceItf
+	cfg                   config.Schema
+	transactionService    internal.TransactionServiceItf
+	merchantService       internal.MerchantServiceItf
+	userService           internal.UserServiceItf
+	providerService       internal.ProviderServiceItf
+	reconciliationService internal.ReconciliationServiceItf
+	auditService          internal.AuditServiceItf
+	hashChainService      internal.HashChainServiceItf
 }
 
 func NewController(
@@ -26,13 +32,19 @@ func NewController(
 	merchant internal.MerchantServiceItf,
 	user internal.UserServiceItf,
 	provider internal.ProviderServiceItf,
+	reconciliation internal.ReconciliationServiceItf,
+	audit internal.AuditServiceItf,
+	hashChain internal.HashChainServiceItf,
 ) *Controller {
 	return &Controller{
-		cfg:                cfg,
-		transactionService: transaction,
-		merchantService:    merchant,
-		userService:        user,
-		providerService:    provider,
+		cfg:                   cfg,
+		transactionService:    transaction,
+		merchantService:       merchant,
+		userService:           user,
+		providerService:       provider,
+		reconciliationService: reconciliation,
+		auditService:          audit,
+		hashChainService:      hashChain,
 	}
 }
 
@@ -69,11 +81,53 @@ func (ctrl *Controller) AuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "token has expired"})
 		}
 
+		username, _ := claims["username"].(string)
+		sessionId, _ := claims["sessionId"].(string)
+		err = ctrl.userService.ValidateSessionSvc(sessionId, username)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		}
+
 		c.Set("username", claims["username"])
 		c.Set("userType", claims["userType"])
 		c.Set("roleName", claims["roleName"])
+		c.Set("permissions", claimedPermissions(claims))
+		c.Set("sessionId", sessionId)
 		// slog.Infow("claimed token", "data", claims)
 
 		return next(c)
 	}
 }
+
+// RequirePermission lets the request through only when the token carries permission, it runs inside AuthMiddleware
+func (ctrl *Controller) RequirePermission(permission string, next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if !hasPermission(c, permission) {
+			return c.JSON(http.StatusForbidden, dto.ResponseDto{
+				ResponseCode:    http.StatusForbidden,
+				ResponseMessage: fmt.Sprintf("permission %v is required", permission),
+			})
+		}
+
+		return next(c)
+	}
+}
+
+func hasPermission(c echo.Context, permission string) bool {
+	permissions, _ := c.Get("permissions").([]string)
+	return helper.Contains(permissions, permission)
+}
+
+// claimedPermissions reads the permissions claim, tokens issued before it existed carry none
+func claimedPermissions(claims jwt.MapClaims) []string {
+	rawPermissions, _ := claims["permissions"].([]interface{})
+
+	permissions := make([]string, 0, len(rawPermissions))
+	for _, rawPermission := range rawPermissions {
+		if permission, ok := rawPermission.(string); ok {
+			permissions = append(permissions, permission)
+		}
+	}
+
+	return permissions
+}
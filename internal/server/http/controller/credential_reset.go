@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/labstack/echo/v4"
+)
+
+func (ctrl *Controller) RequestCredentialResetCtrl(c echo.Context) error {
+	var payload dto.RequestCredentialResetPayload
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if payload.Username == "" || payload.Credential == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "username and credential is mandatory",
+		})
+	}
+
+	payload.IpAddress = c.RealIP()
+	requestResp, err := ctrl.userService.RequestCredentialResetSvc(payload)
+	if err != nil {
+		if requestResp.ResponseCode == http.StatusBadRequest || requestResp.ResponseCode == http.StatusTooManyRequests {
+			return c.JSON(requestResp.ResponseCode, requestResp)
+		}
+
+		return c.JSON(http.StatusUnprocessableEntity, requestResp)
+	}
+
+	return c.JSON(http.StatusOK, requestResp)
+}
+
+func (ctrl *Controller) ConfirmCredentialResetCtrl(c echo.Context) error {
+	var payload dto.ConfirmCredentialResetPayload
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if payload.Token == "" || (payload.Password == nil && payload.Pin == nil) {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "token and the new password or pin is mandatory",
+		})
+	}
+
+	confirmResp, err := ctrl.userService.ConfirmCredentialResetSvc(payload)
+	if err != nil {
+		if confirmResp.ResponseCode == http.StatusBadRequest {
+			return c.JSON(http.StatusBadRequest, confirmResp)
+		}
+
+		return c.JSON(http.StatusUnprocessableEntity, confirmResp)
+	}
+
+	return c.JSON(http.StatusOK, confirmResp)
+}
@@ -0,0 +1,168 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
+	"github.com/labstack/echo/v4"
+)
+
+func (ctrl *Controller) ApproveDisbursementRequestCtrl(c echo.Context) error {
+	return ctrl.reviewDisbursementRequest(c, true)
+}
+
+func (ctrl *Controller) RejectDisbursementRequestCtrl(c echo.Context) error {
+	return ctrl.reviewDisbursementRequest(c, false)
+}
+
+func (ctrl *Controller) reviewDisbursementRequest(c echo.Context, approve bool) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+	var payload dto.ReviewDisbursementRequestReq
+
+	// blocked merchant user for further access
+	if userType != constant.UserMerchant {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only merchant can access this endpoint",
+		})
+	}
+
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if payload.DisbursementRequestId == 0 || payload.Notes == "" || payload.Pin == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "disbursement request id, notes, and pin is mandatory",
+		})
+	}
+
+	payload.Approve = approve
+	payload.Username = username
+	payload.Actor = auditActor(c)
+	reviewResp, err := ctrl.transactionService.ReviewDisbursementRequestSvc(payload)
+	if err != nil {
+		if err.Error() == "wrong pin" || err.Error() == "insufficient" {
+			return c.JSON(http.StatusBadRequest, reviewResp)
+		}
+		return c.JSON(reviewResp.ResponseCode, reviewResp)
+	}
+
+	return c.JSON(http.StatusOK, reviewResp)
+}
+
+func (ctrl *Controller) GetListDisbursementRequestCtrl(c echo.Context) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+	var params dto.QueryParamsDisbursementRequest
+
+	// blocked merchant user for further access
+	if userType != constant.UserMerchant {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only merchant can access this endpoint",
+		})
+	}
+
+	params.Status = c.QueryParam("status")
+	params.RequestedBy = c.QueryParam("requestedBy")
+	params.MinDate = c.QueryParam("minDate")
+	params.MaxDate = c.QueryParam("maxDate")
+	params.Page = c.QueryParam("page")
+	params.PageSize = c.QueryParam("pageSize")
+	params.Username = username
+
+	listResp, err := ctrl.transactionService.GetListDisbursementRequestSvc(params)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, listResp)
+	}
+
+	return c.JSON(http.StatusOK, listResp)
+}
+
+func (ctrl *Controller) GetDisbursementRequestDetailCtrl(c echo.Context) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+	requestId := c.QueryParam("disbursementRequestId")
+
+	// blocked merchant user for further access
+	if userType != constant.UserMerchant {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only merchant can access this endpoint",
+		})
+	}
+
+	if requestId == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "disbursement request id is mandatory",
+		})
+	}
+
+	detailResp, err := ctrl.transactionService.GetDisbursementRequestDetailSvc(converter.ToInt(requestId), username)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, detailResp)
+	}
+
+	return c.JSON(http.StatusOK, detailResp)
+}
+
+func (ctrl *Controller) GetListDisbursementApprovalRuleCtrl(c echo.Context) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+
+	// blocked merchant user for further access
+	if userType != constant.UserMerchant {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only merchant can access this endpoint",
+		})
+	}
+
+	listResp, err := ctrl.transactionService.GetListDisbursementApprovalRuleSvc(username)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, listResp)
+	}
+
+	return c.JSON(http.StatusOK, listResp)
+}
+
+func (ctrl *Controller) UpdateDisbursementApprovalRulesCtrl(c echo.Context) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+	var payload dto.UpdateDisbursementApprovalRulesReq
+
+	// blocked merchant user for further access
+	if userType != constant.UserMerchant {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only merchant can access this endpoint",
+		})
+	}
+
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	payload.Username = username
+	payload.Actor = auditActor(c)
+	updateResp, err := ctrl.transactionService.UpdateDisbursementApprovalRulesSvc(payload)
+	if err != nil {
+		return c.JSON(updateResp.ResponseCode, updateResp)
+	}
+
+	return c.JSON(http.StatusOK, updateResp)
+}
@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
+	"github.com/labstack/echo/v4"
+)
+
+func (ctrl *Controller) UploadDisbursementBatchCtrl(c echo.Context) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+
+	// blocked merchant user for further access
+	if userType != constant.UserMerchant {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only merchant can access this endpoint",
+		})
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "file is mandatory",
+		})
+	}
+
+	if file.Size > constant.DisbursementBatchMaxFileSize {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: fmt.Sprintf("file can't be larger than %v MB", constant.DisbursementBatchMaxFileSize>>20),
+		})
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+	defer src.Close()
+
+	content, err := io.ReadAll(io.LimitReader(src, constant.DisbursementBatchMaxFileSize))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	payload := dto.UploadDisbursementBatchReq{
+		FileName: file.Filename,
+		Content:  content,
+		Username: username,
+		Actor:    auditActor(c),
+	}
+	uploadResp, err := ctrl.transactionService.UploadDisbursementBatchSvc(payload)
+	if err != nil {
+		return c.JSON(uploadResp.ResponseCode, uploadResp)
+	}
+
+	return c.JSON(http.StatusOK, uploadResp)
+}
+
+func (ctrl *Controller) ConfirmDisbursementBatchCtrl(c echo.Context) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+	var payload dto.ConfirmDisbursementBatchReq
+
+	// blocked merchant user for further access
+	if userType != constant.UserMerchant {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only merchant can access this endpoint",
+		})
+	}
+
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if payload.DisbursementBatchId == 0 || payload.Pin == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "disbursement batch id and pin is mandatory",
+		})
+	}
+
+	payload.Username = username
+	payload.Actor = auditActor(c)
+	confirmResp, err := ctrl.transactionService.ConfirmDisbursementBatchSvc(payload)
+	if err != nil {
+		if err.Error() == "wrong pin" || err.Error() == "insufficient" {
+			return c.JSON(http.StatusBadRequest, confirmResp)
+		}
+		return c.JSON(confirmResp.ResponseCode, confirmResp)
+	}
+
+	return c.JSON(http.StatusOK, confirmResp)
+}
+
+func (ctrl *Controller) GetListDisbursementBatchCtrl(c echo.Context) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+	var params dto.QueryParamsDisbursementBatch
+
+	// blocked merchant user for further access
+	if userType != constant.UserMerchant {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only merchant can access this endpoint",
+		})
+	}
+
+	params.Status = c.QueryParam("status")
+	params.UploadedBy = c.QueryParam("uploadedBy")
+	params.MinDate = c.QueryParam("minDate")
+	params.MaxDate = c.QueryParam("maxDate")
+	params.Page = c.QueryParam("page")
+	params.PageSize = c.QueryParam("pageSize")
+	params.Username = username
+
+	listResp, err := ctrl.transactionService.GetListDisbursementBatchSvc(params)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, listResp)
+	}
+
+	return c.JSON(http.StatusOK, listResp)
+}
+
+func (ctrl *Controller) GetDisbursementBatchDetailCtrl(c echo.Context) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+	batchId := c.QueryParam("disbursementBatchId")
+
+	// blocked merchant user for further access
+	if userType != constant.UserMerchant {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only merchant can access this endpoint",
+		})
+	}
+
+	if batchId == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "disbursement batch id is mandatory",
+		})
+	}
+
+	detailResp, err := ctrl.transactionService.GetDisbursementBatchDetailSvc(converter.ToInt(batchId), username)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, detailResp)
+	}
+
+	return c.JSON(http.StatusOK, detailResp)
+}
+
+func (ctrl *Controller) DownloadDisbursementBatchResultCtrl(c echo.Context) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+	batchId := c.QueryParam("disbursementBatchId")
+
+	// blocked merchant user for further access
+	if userType != constant.UserMerchant {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only merchant can access this endpoint",
+		})
+	}
+
+	if batchId == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "disbursement batch id is mandatory",
+		})
+	}
+
+	resultResp, err := ctrl.transactionService.DownloadDisbursementBatchResultSvc(converter.ToInt(batchId), username)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, resultResp)
+	}
+
+	// a batch that isn't found has no file to send
+	result, ok := resultResp.Data.(dto.DisbursementBatchResultFileDto)
+	if !ok {
+		return c.JSON(http.StatusOK, resultResp)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", result.FileName))
+	return c.Blob(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", result.Content)
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/labstack/echo/v4"
+)
+
+func (ctrl *Controller) VerifyHashChainCtrl(c echo.Context) error {
+	userType := c.Get("userType").(string)
+	var params dto.VerifyHashChainReq
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	params.Chain = c.QueryParam("chain")
+	params.ChainKey = c.QueryParam("chainKey")
+
+	verifyResp, err := ctrl.hashChainService.VerifyHashChainSvc(params)
+	if err != nil {
+		return c.JSON(verifyResp.ResponseCode, verifyResp)
+	}
+
+	return c.JSON(http.StatusOK, verifyResp)
+}
+
+func (ctrl *Controller) GetListChainCheckpointCtrl(c echo.Context) error {
+	userType := c.Get("userType").(string)
+	var params dto.QueryParamsChainCheckpoint
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	params.MinDate = c.QueryParam("minDate")
+	params.MaxDate = c.QueryParam("maxDate")
+
+	listResp, err := ctrl.hashChainService.GetListChainCheckpointSvc(params)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, listResp)
+	}
+
+	return c.JSON(http.StatusOK, listResp)
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/labstack/echo/v4"
+)
+
+func (ctrl *Controller) GetListInvitationsCtrl(c echo.Context) error {
+	payload := dto.InvitationPayload{
+		MerchantId: c.QueryParam("merchantId"),
+	}
+
+	badRequest := scopeMerchant(c, &payload.MerchantId, &payload.Username)
+	if badRequest != nil {
+		return c.JSON(badRequest.ResponseCode, badRequest)
+	}
+
+	listInvitation, err := ctrl.userService.GetListInvitationsSvc(payload)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, listInvitation)
+	}
+
+	return c.JSON(http.StatusOK, listInvitation)
+}
+
+func (ctrl *Controller) ResendInvitationCtrl(c echo.Context) error {
+	payload, badRequest := bindInvitation(c)
+	if badRequest != nil {
+		return c.JSON(badRequest.ResponseCode, badRequest)
+	}
+
+	resendResp, err := ctrl.userService.ResendInvitationSvc(payload)
+	if err != nil {
+		if resendResp.ResponseCode == http.StatusBadRequest {
+			return c.JSON(http.StatusBadRequest, resendResp)
+		}
+
+		return c.JSON(http.StatusUnprocessableEntity, resendResp)
+	}
+
+	return c.JSON(http.StatusOK, resendResp)
+}
+
+func (ctrl *Controller) RevokeInvitationCtrl(c echo.Context) error {
+	payload, badRequest := bindInvitation(c)
+	if badRequest != nil {
+		return c.JSON(badRequest.ResponseCode, badRequest)
+	}
+
+	revokeResp, err := ctrl.userService.RevokeInvitationSvc(payload)
+	if err != nil {
+		if revokeResp.ResponseCode == http.StatusBadRequest {
+			return c.JSON(http.StatusBadRequest, revokeResp)
+		}
+
+		return c.JSON(http.StatusUnprocessableEntity, revokeResp)
+	}
+
+	return c.JSON(http.StatusOK, revokeResp)
+}
+
+func (ctrl *Controller) AcceptInvitationCtrl(c echo.Context) error {
+	var payload dto.AcceptInvitationPayload
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if payload.Token == "" || payload.Password == "" || payload.Pin == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "token, password and pin is mandatory",
+		})
+	}
+
+	acceptResp, err := ctrl.userService.AcceptInvitationSvc(payload)
+	if err != nil {
+		if acceptResp.ResponseCode == http.StatusBadRequest {
+			return c.JSON(http.StatusBadRequest, acceptResp)
+		}
+
+		return c.JSON(http.StatusUnprocessableEntity, acceptResp)
+	}
+
+	return c.JSON(http.StatusOK, acceptResp)
+}
+
+func bindInvitation(c echo.Context) (dto.InvitationPayload, *dto.ResponseDto) {
+	var payload dto.InvitationPayload
+	err := c.Bind(&payload)
+	if err != nil {
+		return payload, &dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		}
+	}
+
+	if payload.InvitationId == 0 {
+		return payload, &dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invitation id is mandatory",
+		}
+	}
+
+	return payload, scopeMerchant(c, &payload.MerchantId, &payload.Username)
+}
+
+// scopeMerchant keeps merchant users to their own merchant by clearing merchantId, the service then reads
+// it from username. operations have to name the merchant.
+func scopeMerchant(c echo.Context, merchantId *string, username *string) *dto.ResponseDto {
+	userType := c.Get("userType").(string)
+	*username = c.Get("username").(string)
+
+	if userType == constant.UserMerchant {
+		*merchantId = ""
+		return nil
+	}
+
+	if *merchantId == "" {
+		return &dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "merchant id is mandatory",
+		}
+	}
+
+	return nil
+}
@@ -68,6 +68,7 @@ func (ctrl *Controller) GetListMerchantCallback(c echo.Context) error {
 	queryParamsMerchantCallback.PayType = c.QueryParam("payType")
 	queryParamsMerchantCallback.MerchantName = c.QueryParam("merchantName")
 	queryParamsMerchantCallback.CallbackStatus = c.QueryParam("callbackStatus")
+	queryParamsMerchantCallback.DeliveryStatus = c.QueryParam("deliveryStatus")
 	queryParamsMerchantCallback.Page = c.QueryParam("page")
 	queryParamsMerchantCallback.PageSize = c.QueryParam("pageSize")
 	queryParamsMerchantCallback.Search = c.QueryParam("search")
@@ -161,7 +162,6 @@ func (ctrl *Controller) GetManualPaymentDetailCtrl(c echo.Context) error {
 func (ctrl *Controller) TopUpMerchantCtrl(c echo.Context) error {
 	username := c.Get("username").(string)
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.AdjustBalanceReqPayload
 
 	// blocked merchant user for further access
@@ -172,13 +172,6 @@ func (ctrl *Controller) TopUpMerchantCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin && roleName != constant.RoleNameFinance {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin and finance can do top up",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -187,14 +180,15 @@ func (ctrl *Controller) TopUpMerchantCtrl(c echo.Context) error {
 		})
 	}
 
-	if payload.MerchantId == "" || payload.Notes == "" || payload.Amount == 0 || payload.Pin == "" {
+	if payload.MerchantId == "" || payload.Notes == "" || !payload.Amount.IsPositive() || payload.Pin == "" {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
 			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "merchant id, notes, amount, and pin is mandatory",
+			ResponseMessage: "merchant id, notes, amount more than 0, and pin is mandatory",
 		})
 	}
 
 	payload.Username = username
+	payload.Actor = auditActor(c)
 	topResp, err := ctrl.merchantService.TopUpMerchantSvc(payload)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, topResp)
@@ -206,7 +200,6 @@ func (ctrl *Controller) TopUpMerchantCtrl(c echo.Context) error {
 func (ctrl *Controller) HoldBalanceCtrl(c echo.Context) error {
 	username := c.Get("username").(string)
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.AdjustBalanceReqPayload
 
 	// blocked merchant user for further access
@@ -217,13 +210,6 @@ func (ctrl *Controller) HoldBalanceCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin && roleName != constant.RoleNameFinance {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin and finance can do hold balance",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -232,14 +218,15 @@ func (ctrl *Controller) HoldBalanceCtrl(c echo.Context) error {
 		})
 	}
 
-	if payload.MerchantId == "" || payload.Notes == "" || payload.Amount == 0 || payload.Pin == "" {
+	if payload.MerchantId == "" || payload.Notes == "" || !payload.Amount.IsPositive() || payload.Pin == "" {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
 			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "merchant id, notes, amount, and pin is mandatory",
+			ResponseMessage: "merchant id, notes, amount more than 0, and pin is mandatory",
 		})
 	}
 
 	payload.Username = username
+	payload.Actor = auditActor(c)
 	holdBalanceResp, err := ctrl.merchantService.HoldBalanceSvc(payload)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, holdBalanceResp)
@@ -251,7 +238,6 @@ func (ctrl *Controller) HoldBalanceCtrl(c echo.Context) error {
 func (ctrl *Controller) AddSettlementCtrl(c echo.Context) error {
 	username := c.Get("username").(string)
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.AdjustBalanceReqPayload
 
 	// blocked merchant user for further access
@@ -262,13 +248,6 @@ func (ctrl *Controller) AddSettlementCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin && roleName != constant.RoleNameFinance {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin and finance can do top up",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -277,14 +256,15 @@ func (ctrl *Controller) AddSettlementCtrl(c echo.Context) error {
 		})
 	}
 
-	if payload.MerchantId == "" || payload.Notes == "" || payload.Amount == 0 || payload.Pin == "" {
+	if payload.MerchantId == "" || payload.Notes == "" || !payload.Amount.IsPositive() || payload.Pin == "" {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
 			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "merchant id, notes, amount, and pin is mandatory",
+			ResponseMessage: "merchant id, notes, amount more than 0, and pin is mandatory",
 		})
 	}
 
 	payload.Username = username
+	payload.Actor = auditActor(c)
 	settlementResp, err := ctrl.merchantService.SettlementBalanceSvc(payload)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, settlementResp)
@@ -296,7 +276,6 @@ func (ctrl *Controller) AddSettlementCtrl(c echo.Context) error {
 func (ctrl *Controller) BalanceTransferCtrl(c echo.Context) error {
 	username := c.Get("username").(string)
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.BalanceTrfReqPayload
 
 	// blocked merchant user for further access
@@ -307,13 +286,6 @@ func (ctrl *Controller) BalanceTransferCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin && roleName != constant.RoleNameFinance {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin and finance can do top up",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -324,7 +296,7 @@ func (ctrl *Controller) BalanceTransferCtrl(c echo.Context) error {
 
 	if payload.AccountFrom.MerchantId == "" ||
 		payload.AccountTo.MerchantId == "" ||
-		payload.Amount == 0 ||
+		!payload.Amount.IsPositive() ||
 		payload.Pin == "" ||
 		payload.Notes == "" {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -334,6 +306,7 @@ func (ctrl *Controller) BalanceTransferCtrl(c echo.Context) error {
 	}
 
 	payload.Username = username
+	payload.Actor = auditActor(c)
 	balanceTrfResp, err := ctrl.merchantService.BalanceTransferSvc(payload)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, balanceTrfResp)
@@ -345,7 +318,6 @@ func (ctrl *Controller) BalanceTransferCtrl(c echo.Context) error {
 func (ctrl *Controller) SendCallbackCtrl(c echo.Context) error {
 	username := c.Get("username").(string)
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.SendCallbackReqPayload
 
 	// blocked merchant user for further access
@@ -356,13 +328,6 @@ func (ctrl *Controller) SendCallbackCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can do send callback",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -389,7 +354,6 @@ func (ctrl *Controller) SendCallbackCtrl(c echo.Context) error {
 func (ctrl *Controller) SendPayoutSettlementCtrl(c echo.Context) error {
 	username := c.Get("username").(string)
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.AdjustBalanceReqPayload
 
 	// blocked merchant user for further access
@@ -400,13 +364,6 @@ func (ctrl *Controller) SendPayoutSettlementCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin && roleName != constant.RoleNameFinance {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin and finance can do out settlement",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -415,14 +372,15 @@ func (ctrl *Controller) SendPayoutSettlementCtrl(c echo.Context) error {
 		})
 	}
 
-	if payload.MerchantId == "" || payload.Notes == "" || payload.Amount == 0 || payload.Pin == "" {
+	if payload.MerchantId == "" || payload.Notes == "" || !payload.Amount.IsPositive() || payload.Pin == "" {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
 			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "merchant id, notes, amount, and pin is mandatory",
+			ResponseMessage: "merchant id, notes, amount more than 0, and pin is mandatory",
 		})
 	}
 
 	payload.Username = username
+	payload.Actor = auditActor(c)
 	outSettlementResp, err := ctrl.merchantService.PayoutSettlementSvc(payload)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, outSettlementResp)
@@ -434,7 +392,6 @@ func (ctrl *Controller) SendPayoutSettlementCtrl(c echo.Context) error {
 func (ctrl *Controller) ReverseManualPaymentCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
 	username := c.Get("username").(string)
-	roleName := c.Get("roleName").(string)
 
 	var payload dto.UpdateStatusTransaction
 
@@ -446,13 +403,6 @@ func (ctrl *Controller) ReverseManualPaymentCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin && roleName != constant.RoleNameFinance {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin and finance can do top up",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -468,6 +418,7 @@ func (ctrl *Controller) ReverseManualPaymentCtrl(c echo.Context) error {
 		})
 	}
 
+	payload.Actor = auditActor(c)
 	reverseResp, err := ctrl.merchantService.ReverseManualPaymentSvc(payload, username)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, reverseResp)
@@ -498,7 +449,6 @@ func (ctrl *Controller) GetMerchantBalanceCtrl(c echo.Context) error {
 
 func (ctrl *Controller) CreateMerchantCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.CreateMerchantDtoReq
 
 	// blocked merchant user for further access
@@ -509,13 +459,6 @@ func (ctrl *Controller) CreateMerchantCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can create merchant",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -576,7 +519,6 @@ func (ctrl *Controller) GetMerchantAnalyticsCtrl(c echo.Context) error {
 
 func (ctrl *Controller) UpdateMerchantStatusCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 
 	var payload dto.AccountData
 
@@ -588,13 +530,6 @@ func (ctrl *Controller) UpdateMerchantStatusCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can update status",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -610,6 +545,7 @@ func (ctrl *Controller) UpdateMerchantStatusCtrl(c echo.Context) error {
 		})
 	}
 
+	payload.Actor = auditActor(c)
 	merchantUpdateStatusRes, err := ctrl.merchantService.MerchantUpdateStatusSvc(payload)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, merchantUpdateStatusRes)
@@ -684,7 +620,6 @@ func (ctrl *Controller) GetListCapitalFlowTransactionCtrl(c echo.Context) error
 
 func (ctrl *Controller) GetPaychannelTierCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	merchantPaychannelId := c.QueryParam("merchantPaychannelId")
 	intMerchantPaychannelId := converter.ToInt(merchantPaychannelId)
 
@@ -696,13 +631,6 @@ func (ctrl *Controller) GetPaychannelTierCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can add tier paychannel",
-		})
-	}
-
 	tierList, err := ctrl.merchantService.GetListTierPaychannelSvc(intMerchantPaychannelId)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, tierList)
@@ -815,7 +743,6 @@ func (ctrl *Controller) GetPaymentOperatorsCtrl(c echo.Context) error {
 func (ctrl *Controller) UpdateLimitOrFeeCtrl(c echo.Context) error {
 	username := c.Get("username").(string)
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.AdjustLimitOrFeePayload
 
 	// blocked merchant user for further access
@@ -826,13 +753,6 @@ func (ctrl *Controller) UpdateLimitOrFeeCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can adjust fee and limit",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -858,6 +778,7 @@ func (ctrl *Controller) UpdateLimitOrFeeCtrl(c echo.Context) error {
 	}
 
 	payload.Username = username
+	payload.Actor = auditActor(c)
 	adjustResp, err := ctrl.merchantService.UpdateLimitOrFeeMerchantPaychannelSvc(payload)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, adjustResp)
@@ -868,7 +789,6 @@ func (ctrl *Controller) UpdateLimitOrFeeCtrl(c echo.Context) error {
 
 func (ctrl *Controller) GetAggregatedPaychannelCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	merchantPaychannelId := c.QueryParam("merchantPaychannelId")
 	intMerchantPaychannelId := converter.ToInt(merchantPaychannelId)
 
@@ -880,13 +800,6 @@ func (ctrl *Controller) GetAggregatedPaychannelCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can adjust fee and limit",
-		})
-	}
-
 	aggregatedData, err := ctrl.merchantService.GetAggregatedPaychannelSvc(intMerchantPaychannelId)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, aggregatedData)
@@ -897,7 +810,6 @@ func (ctrl *Controller) GetAggregatedPaychannelCtrl(c echo.Context) error {
 
 func (ctrl *Controller) AddSegmentCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.AddSegmentDtoReq
 
 	// blocked merchant user for further access
@@ -908,13 +820,6 @@ func (ctrl *Controller) AddSegmentCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can adjust fee and limit",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -940,7 +845,6 @@ func (ctrl *Controller) AddSegmentCtrl(c echo.Context) error {
 
 func (ctrl *Controller) UpdateStatusMerchantPaychannel(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.AccountData
 
 	// blocked merchant user for further access
@@ -951,13 +855,6 @@ func (ctrl *Controller) UpdateStatusMerchantPaychannel(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can adjust fee and limit",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -973,6 +870,7 @@ func (ctrl *Controller) UpdateStatusMerchantPaychannel(c echo.Context) error {
 		})
 	}
 
+	payload.Actor = auditActor(c)
 	updateStatusRes, err := ctrl.merchantService.ActivateOrDeactivateMerchantPaychannelSvc(payload)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, updateStatusRes)
@@ -983,7 +881,6 @@ func (ctrl *Controller) UpdateStatusMerchantPaychannel(c echo.Context) error {
 
 func (ctrl *Controller) AddChannelCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	idMerchant := c.QueryParam("idMerchant")
 	intIdMerchant := converter.ToInt(idMerchant)
 	var payload []dto.PaymentMethodData
@@ -996,13 +893,6 @@ func (ctrl *Controller) AddChannelCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can create merchant",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -1021,7 +911,6 @@ func (ctrl *Controller) AddChannelCtrl(c echo.Context) error {
 
 func (ctrl *Controller) GetListChannelCreateMerchantPaychannelCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	idMerchant := c.QueryParam("idMerchant")
 	intIdMerchant := converter.ToInt(idMerchant)
 
@@ -1033,13 +922,6 @@ func (ctrl *Controller) GetListChannelCreateMerchantPaychannelCtrl(c echo.Contex
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can create merchant",
-		})
-	}
-
 	listPaymentMethods, err := ctrl.merchantService.GetListMerchantPaymentMethodsSvc(intIdMerchant)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, listPaymentMethods)
@@ -1050,7 +932,6 @@ func (ctrl *Controller) GetListChannelCreateMerchantPaychannelCtrl(c echo.Contex
 
 func (ctrl *Controller) AddRoutingPaychannelCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	merchantPaychannelId := c.QueryParam("merchantPaychannelId")
 	intMerchantPaychannelId := converter.ToInt(merchantPaychannelId)
 	var payload dto.AddPaychannelRouting
@@ -1063,13 +944,6 @@ func (ctrl *Controller) AddRoutingPaychannelCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can create merchant",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -1078,6 +952,7 @@ func (ctrl *Controller) AddRoutingPaychannelCtrl(c echo.Context) error {
 		})
 	}
 
+	payload.Actor = auditActor(c)
 	AddRoutingResp, err := ctrl.merchantService.AddRoutingPaychannelSvc(intMerchantPaychannelId, payload)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, AddRoutingResp)
@@ -1196,7 +1071,6 @@ func (ctrl *Controller) GetTransactionOutListCtrl(c echo.Context) error {
 func (ctrl *Controller) ResendCallbackMerchantCtrl(c echo.Context) error {
 	username := c.Get("username").(string)
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 
 	var payload dto.SendCallbackReqPayload
 
@@ -1208,13 +1082,6 @@ func (ctrl *Controller) ResendCallbackMerchantCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only merchant admin can do send callback",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -1267,7 +1134,6 @@ func (ctrl *Controller) GetMerchantInformationCtrl(c echo.Context) error {
 
 func (ctrl *Controller) DisplaySecretKeyCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	username := c.Get("username").(string)
 	var payload dto.BalanceTrfReqPayload
 	merchantId := c.QueryParam("merchantId")
@@ -1280,13 +1146,6 @@ func (ctrl *Controller) DisplaySecretKeyCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin display secret key",
-		})
-	}
-
 	if merchantId == "" {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
 			ResponseCode:    http.StatusBadRequest,
@@ -1319,9 +1178,8 @@ func (ctrl *Controller) DisplaySecretKeyCtrl(c echo.Context) error {
 
 func (ctrl *Controller) GenerateSecretKeyCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	username := c.Get("username").(string)
-	var payload dto.BalanceTrfReqPayload
+	var payload dto.RotateMerchantKeyPayload
 	merchantId := c.QueryParam("merchantId")
 
 	// blocked merchant user for further access
@@ -1332,13 +1190,6 @@ func (ctrl *Controller) GenerateSecretKeyCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin display secret key",
-		})
-	}
-
 	if merchantId == "" {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
 			ResponseCode:    http.StatusBadRequest,
@@ -1361,8 +1212,15 @@ func (ctrl *Controller) GenerateSecretKeyCtrl(c echo.Context) error {
 		})
 	}
 
-	generateKey, err := ctrl.merchantService.GenerateSecretKeySvc(payload.Pin, username, merchantId)
+	payload.MerchantId = merchantId
+	payload.Username = username
+	payload.Actor = auditActor(c)
+	generateKey, err := ctrl.merchantService.GenerateSecretKeySvc(payload)
 	if err != nil {
+		if generateKey.ResponseCode == http.StatusBadRequest {
+			return c.JSON(http.StatusBadRequest, generateKey)
+		}
+
 		return c.JSON(http.StatusUnprocessableEntity, generateKey)
 	}
 
@@ -1456,6 +1314,7 @@ func (ctrl *Controller) GetListMerchantCallbackCtrl(c echo.Context) error {
 
 	queryParamsMerchantCallback.PayType = c.QueryParam("payType")
 	queryParamsMerchantCallback.CallbackStatus = c.QueryParam("callbackStatus")
+	queryParamsMerchantCallback.DeliveryStatus = c.QueryParam("deliveryStatus")
 	queryParamsMerchantCallback.Page = c.QueryParam("page")
 	queryParamsMerchantCallback.PageSize = c.QueryParam("pageSize")
 	queryParamsMerchantCallback.Search = c.QueryParam("search")
@@ -1494,7 +1353,6 @@ func (ctrl *Controller) GetMerchantAccountBalanceCtrl(c echo.Context) error {
 func (ctrl *Controller) DisbursementCtrl(c echo.Context) error {
 	username := c.Get("username").(string)
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.MerchantDisbursement
 
 	// blocked merchant user for further access
@@ -1505,13 +1363,6 @@ func (ctrl *Controller) DisbursementCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin && roleName != constant.RoleNameFinance {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin and finance can do disbursement",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -1520,7 +1371,7 @@ func (ctrl *Controller) DisbursementCtrl(c echo.Context) error {
 		})
 	}
 
-	if payload.BankAccountName == "" || payload.Amount == 0 || payload.Pin == "" || payload.BankAccountNumber == "" || payload.BankName == "" {
+	if payload.BankAccountName == "" || payload.Amount.IsZero() || payload.Pin == "" || payload.BankAccountNumber == "" || payload.BankName == "" {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
 			ResponseCode:    http.StatusBadRequest,
 			ResponseMessage: "bank account name, amount, pin, bank account number, and bank name is mandatory",
@@ -1528,6 +1379,7 @@ func (ctrl *Controller) DisbursementCtrl(c echo.Context) error {
 	}
 
 	payload.Username = username
+	payload.Actor = auditActor(c)
 	disbursementResp, err := ctrl.transactionService.MerchantDisbursementSvc(payload)
 	if err != nil {
 		if err.Error() == "wrong pin" || err.Error() == "insufficient" {
@@ -1615,7 +1467,6 @@ func (ctrl *Controller) GetInformationMerchantCtrl(c echo.Context) error {
 
 func (ctrl *Controller) DisplayMerchantKeyCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	username := c.Get("username").(string)
 	var payload dto.BalanceTrfReqPayload
 
@@ -1627,13 +1478,6 @@ func (ctrl *Controller) DisplayMerchantKeyCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin display secret key",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -1659,9 +1503,8 @@ func (ctrl *Controller) DisplayMerchantKeyCtrl(c echo.Context) error {
 
 func (ctrl *Controller) GenerateMerchantKeyCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	username := c.Get("username").(string)
-	var payload dto.BalanceTrfReqPayload
+	var payload dto.RotateMerchantKeyPayload
 
 	// blocked merchant user for further access
 	if userType != constant.UserMerchant {
@@ -1671,13 +1514,6 @@ func (ctrl *Controller) GenerateMerchantKeyCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin display secret key",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -1686,10 +1522,33 @@ func (ctrl *Controller) GenerateMerchantKeyCtrl(c echo.Context) error {
 		})
 	}
 
-	generateRes, err := ctrl.merchantService.GenerateMerchantKeySvc(payload.Pin, username)
+	payload.Username = username
+	payload.Actor = auditActor(c)
+	generateRes, err := ctrl.merchantService.GenerateMerchantKeySvc(payload)
 	if err != nil {
+		if generateRes.ResponseCode == http.StatusBadRequest {
+			return c.JSON(http.StatusBadRequest, generateRes)
+		}
+
 		return c.JSON(http.StatusUnprocessableEntity, generateRes)
 	}
 
 	return c.JSON(http.StatusOK, generateRes)
 }
+
+func (ctrl *Controller) GetLiveMerchantKeysCtrl(c echo.Context) error {
+	merchantId := c.QueryParam("merchantId")
+	var username string
+
+	badRequest := scopeMerchant(c, &merchantId, &username)
+	if badRequest != nil {
+		return c.JSON(badRequest.ResponseCode, badRequest)
+	}
+
+	liveKeys, err := ctrl.merchantService.GetLiveMerchantKeysSvc(merchantId, username)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, liveKeys)
+	}
+
+	return c.JSON(http.StatusOK, liveKeys)
+}
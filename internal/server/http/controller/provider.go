@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
@@ -121,37 +122,41 @@ func (ctrl *Controller) GetListProviderChannelAllCtrl(c echo.Context) error {
 }
 
 func (ctrl *Controller) JackDisbursementCallbackCtrl(c echo.Context) error {
-	var req dto.CreateDisbursementRequestResponseData
+	return ctrl.disbursementCallback(c, constant.ProviderJack)
+}
+
+func (ctrl *Controller) DisbursementCallbackCtrl(c echo.Context) error {
+	return ctrl.disbursementCallback(c, c.Param("providerId"))
+}
 
-	// convert response to struct
-	err := c.Bind(&req)
+// disbursementCallback hands the raw callback body to the payout adapter of providerId
+func (ctrl *Controller) disbursementCallback(c echo.Context, providerId string) error {
+	body, err := io.ReadAll(c.Request().Body)
 	if err != nil {
-		slog.Infof("JACK http-request /payOutCallback [end] [error] invalid request body (%v)", err.Error())
+		slog.Infof("%v http-request /payOutCallback [end] [error] invalid request body (%v)", providerId, err.Error())
 		return c.JSON(http.StatusOK, map[string]interface{}{
 			"status":  http.StatusUnprocessableEntity,
 			"message": "invalid request body",
 		})
 	}
 
-	amountInt := converter.FromStringToIntAmount(req.Destination.Amount)
-	if amountInt < 10000 || amountInt > 500000000 {
-		slog.Infof("JACK %v http-request /payOutCallback [end] [error] invalid amount (%v)", req.ReferenceID, req.Destination.Amount)
-		return c.JSON(http.StatusOK, map[string]interface{}{
-			"status":  http.StatusBadRequest,
-			"message": "invalid amount",
-		})
+	payload := dto.ProviderCallbackReq{
+		ProviderId: providerId,
+		SourceIp:   c.RealIP(),
+		Headers:    c.Request().Header,
+		Body:       body,
 	}
 
-	_, err = ctrl.transactionService.JackDisbursementCallbackHandlingSvc(req)
+	_, err = ctrl.transactionService.DisbursementCallbackHandlingSvc(payload)
 	if err != nil {
-		slog.Infof("JACK %v http-request /payOutCallback [end] [error] internal server error (%v)", req.ReferenceID, err.Error())
+		slog.Infof("%v http-request /payOutCallback [end] [error] (%v)", providerId, err.Error())
 		return c.JSON(http.StatusOK, map[string]interface{}{
 			"status":  http.StatusUnprocessableEntity,
-			"message": "interval server error",
+			"message": "failed to process callback",
 		})
 	}
 
-	slog.Infof("JACK http-request /payOutCallback [end] [success]")
+	slog.Infof("%v http-request /payOutCallback [end] [success]", providerId)
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"status":  "success",
 		"message": "ok",
@@ -196,7 +201,6 @@ func (ctrl *Controller) GetProviderChannelAnalyticsCtrl(c echo.Context) error {
 
 func (ctrl *Controller) UpdateLimitFeeInterfacePchannelCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.AdjustLimitOrFeeProviderPayload
 
 	// blocked merchant user for further access
@@ -207,13 +211,6 @@ func (ctrl *Controller) UpdateLimitFeeInterfacePchannelCtrl(c echo.Context) erro
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can adjust fee and limit",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -238,6 +235,7 @@ func (ctrl *Controller) UpdateLimitFeeInterfacePchannelCtrl(c echo.Context) erro
 		}
 	}
 
+	payload.Actor = auditActor(c)
 	adjustResp, err := ctrl.providerService.UpdateFeeLimitInterfaceProviderChannelSvc(payload)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, adjustResp)
@@ -290,7 +288,6 @@ func (ctrl *Controller) GetListRoutedProviderChannelCtrl(c echo.Context) error {
 
 func (ctrl *Controller) AddOperatorProviderChannelCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload []dto.AddOperatorProviderChannelPayload
 
 	// blocked merchant user for further access
@@ -301,13 +298,6 @@ func (ctrl *Controller) AddOperatorProviderChannelCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can add bank list",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -333,7 +323,6 @@ func (ctrl *Controller) AddOperatorProviderChannelCtrl(c echo.Context) error {
 
 func (ctrl *Controller) UpdateStatusProviderPaychannelSvc(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.UpdateStatusProviderPaychannelDto
 
 	// blocked merchant user for further access
@@ -344,13 +333,6 @@ func (ctrl *Controller) UpdateStatusProviderPaychannelSvc(c echo.Context) error
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can adjust fee and limit",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -366,6 +348,7 @@ func (ctrl *Controller) UpdateStatusProviderPaychannelSvc(c echo.Context) error
 		})
 	}
 
+	payload.Actor = auditActor(c)
 	updateStatusRes, err := ctrl.providerService.ActiveOrDeactivateProviderPaychannelIdSvc(payload)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, updateStatusRes)
@@ -376,7 +359,6 @@ func (ctrl *Controller) UpdateStatusProviderPaychannelSvc(c echo.Context) error
 
 func (ctrl *Controller) GetListProviderInterfaceCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var params dto.QueryParams
 
 	// blocked merchant user for further access
@@ -387,13 +369,6 @@ func (ctrl *Controller) GetListProviderInterfaceCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can access this endpoint",
-		})
-	}
-
 	params.ProviderName = c.QueryParam("provider")
 	params.PaymentMethod = c.QueryParam("paymentMethod")
 	params.PayType = c.QueryParam("payType")
@@ -408,7 +383,6 @@ func (ctrl *Controller) GetListProviderInterfaceCtrl(c echo.Context) error {
 
 func (ctrl *Controller) GetListPaymentOperatorCreateProviderChannelCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 
 	// blocked merchant user for further access
 	if userType != constant.UserOperation {
@@ -418,13 +392,6 @@ func (ctrl *Controller) GetListPaymentOperatorCreateProviderChannelCtrl(c echo.C
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can access this endpoint",
-		})
-	}
-
 	operatorList, err := ctrl.providerService.GetListPaymentOperatorCreateChannelProviderSvc(c.QueryParam("providerInterfaceId"))
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, operatorList)
@@ -435,7 +402,6 @@ func (ctrl *Controller) GetListPaymentOperatorCreateProviderChannelCtrl(c echo.C
 
 func (ctrl *Controller) CreateProviderPaychannelCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.CreateProviderChannelDto
 
 	// blocked merchant user for further access
@@ -446,13 +412,6 @@ func (ctrl *Controller) CreateProviderPaychannelCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can access this endpoint",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
+	"github.com/labstack/echo/v4"
+)
+
+func (ctrl *Controller) RunReconciliationCtrl(c echo.Context) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	runResp, err := ctrl.reconciliationService.RunReconciliationSvc(username)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, runResp)
+	}
+
+	return c.JSON(http.StatusOK, runResp)
+}
+
+func (ctrl *Controller) GetListReconciliationRunCtrl(c echo.Context) error {
+	userType := c.Get("userType").(string)
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	listResp, err := ctrl.reconciliationService.GetListReconciliationRunSvc()
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, listResp)
+	}
+
+	return c.JSON(http.StatusOK, listResp)
+}
+
+func (ctrl *Controller) GetReconciliationRunDetailCtrl(c echo.Context) error {
+	userType := c.Get("userType").(string)
+	runId := c.QueryParam("reconciliationRunId")
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	if runId == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "reconciliation run id is mandatory",
+		})
+	}
+
+	detailResp, err := ctrl.reconciliationService.GetReconciliationRunDetailSvc(converter.ToInt(runId))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, detailResp)
+	}
+
+	return c.JSON(http.StatusOK, detailResp)
+}
+
+func (ctrl *Controller) ResolveReconciliationDiscrepancyCtrl(c echo.Context) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+	var payload dto.ResolveReconciliationDiscrepancyReq
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if payload.DiscrepancyId == 0 || payload.Notes == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "discrepancy id and notes is mandatory",
+		})
+	}
+
+	payload.Username = username
+	resolveResp, err := ctrl.reconciliationService.ResolveReconciliationDiscrepancySvc(payload)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, resolveResp)
+	}
+
+	return c.JSON(http.StatusOK, resolveResp)
+}
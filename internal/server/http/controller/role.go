@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/labstack/echo/v4"
+)
+
+func (ctrl *Controller) GetPermissionsCtrl(c echo.Context) error {
+	userType := c.Get("userType").(string)
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	permissions, err := ctrl.userService.GetListPermissionsSvc()
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, permissions)
+	}
+
+	return c.JSON(http.StatusOK, permissions)
+}
+
+func (ctrl *Controller) CreateRoleCtrl(c echo.Context) error {
+	userType := c.Get("userType").(string)
+	var payload dto.CreateRolePayload
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if strings.TrimSpace(payload.RoleName) == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "role name is mandatory",
+		})
+	}
+
+	createResp, err := ctrl.userService.CreateRoleSvc(payload)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, createResp)
+	}
+
+	return c.JSON(http.StatusOK, createResp)
+}
+
+func (ctrl *Controller) UpdateRolePermissionsCtrl(c echo.Context) error {
+	username := c.Get("username").(string)
+	userType := c.Get("userType").(string)
+	var payload dto.UpdateRolePermissionsPayload
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if payload.RoleId <= 0 {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "role id is mandatory",
+		})
+	}
+
+	payload.Username = username
+	updateResp, err := ctrl.userService.UpdateRolePermissionsSvc(payload)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, updateResp)
+	}
+
+	return c.JSON(http.StatusOK, updateResp)
+}
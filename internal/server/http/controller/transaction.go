@@ -186,7 +186,6 @@ func (ctrl *Controller) GetPaymentDetailTransactions(c echo.Context) error {
 func (ctrl *Controller) UpdateStatusTransaction(c echo.Context) error {
 	username := c.Get("username").(string)
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.UpdateStatusTransaction
 
 	// blocked merchant user for further access
@@ -197,13 +196,6 @@ func (ctrl *Controller) UpdateStatusTransaction(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can update status",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -219,7 +211,7 @@ func (ctrl *Controller) UpdateStatusTransaction(c echo.Context) error {
 		})
 	}
 
-	status, err := ctrl.transactionService.UpdateStatusTransaction(payload.PaymentId, payload.Status, username, payload.Notes, payload.Pin)
+	status, err := ctrl.transactionService.UpdateStatusTransaction(payload.PaymentId, payload.Status, username, hasPermission(c, constant.PermissionTransactionOverrideStatus), payload.Notes, payload.Pin)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, status)
 	}
@@ -238,7 +230,6 @@ func (ctrl *Controller) GetListFilter(c echo.Context) error {
 
 func (ctrl *Controller) CreateMerchantExportCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.CreateMerchantExportReqDto
 
 	// blocked merchant user for further access
@@ -249,13 +240,6 @@ func (ctrl *Controller) CreateMerchantExportCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin && roleName != constant.RoleNameFinance {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin and finance can do export",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -358,7 +342,6 @@ func (ctrl *Controller) GetListFilterMerchantReportCtrl(c echo.Context) error {
 
 func (ctrl *Controller) CreateInternalExportCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.CreateMerchantExportReqDto
 
 	// blocked merchant user for further access
@@ -369,13 +352,6 @@ func (ctrl *Controller) CreateInternalExportCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin && roleName != constant.RoleNameFinance {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin and finance can do export",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -457,7 +433,6 @@ func (ctrl *Controller) GetDetailTransactionCtrl(c echo.Context) error {
 func (ctrl *Controller) CountDisbursementTotalAmountCtrl(c echo.Context) error {
 	username := c.Get("username").(string)
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.CountDisbursementTotalAmountDto
 
 	// blocked merchant user for further access
@@ -468,13 +443,6 @@ func (ctrl *Controller) CountDisbursementTotalAmountCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin && roleName != constant.RoleNameFinance {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin and finance can do export",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -483,7 +451,7 @@ func (ctrl *Controller) CountDisbursementTotalAmountCtrl(c echo.Context) error {
 		})
 	}
 
-	if payload.Amount == 0 {
+	if payload.Amount.IsZero() {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
 			ResponseCode:    http.StatusBadRequest,
 			ResponseMessage: "amount is mandatory",
@@ -501,7 +469,6 @@ func (ctrl *Controller) CountDisbursementTotalAmountCtrl(c echo.Context) error {
 
 func (ctrl *Controller) CreateMerchantReportCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	username := c.Get("username").(string)
 	var payload dto.CreateReportMerchantReqDto
 
@@ -513,13 +480,6 @@ func (ctrl *Controller) CreateMerchantReportCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin && roleName != constant.RoleNameFinance {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin and finance can create report",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
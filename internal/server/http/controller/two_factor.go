@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/labstack/echo/v4"
+)
+
+func (ctrl *Controller) LoginTwoFactorCtrl(c echo.Context) error {
+	var payload dto.LoginChallengePayload
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if payload.ChallengeToken == "" || payload.Code == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "challenge token and code is mandatory",
+		})
+	}
+
+	payload.Device = c.Request().UserAgent()
+	payload.IpAddress = c.RealIP()
+	loginResp, err := ctrl.userService.LoginTwoFactorSvc(payload)
+	if err != nil {
+		return c.JSON(twoFactorErrStatus(loginResp), loginResp)
+	}
+
+	return c.JSON(http.StatusOK, loginResp)
+}
+
+func (ctrl *Controller) SetupTwoFactorLoginCtrl(c echo.Context) error {
+	var payload dto.LoginChallengePayload
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if payload.ChallengeToken == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "challenge token is mandatory",
+		})
+	}
+
+	setupResp, err := ctrl.userService.SetupTwoFactorLoginSvc(payload)
+	if err != nil {
+		return c.JSON(twoFactorErrStatus(setupResp), setupResp)
+	}
+
+	return c.JSON(http.StatusOK, setupResp)
+}
+
+func (ctrl *Controller) ActivateTwoFactorLoginCtrl(c echo.Context) error {
+	var payload dto.LoginChallengePayload
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if payload.ChallengeToken == "" || payload.Code == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "challenge token and code is mandatory",
+		})
+	}
+
+	payload.Device = c.Request().UserAgent()
+	payload.IpAddress = c.RealIP()
+	activateResp, err := ctrl.userService.ActivateTwoFactorLoginSvc(payload)
+	if err != nil {
+		return c.JSON(twoFactorErrStatus(activateResp), activateResp)
+	}
+
+	return c.JSON(http.StatusOK, activateResp)
+}
+
+func (ctrl *Controller) SetupTwoFactorCtrl(c echo.Context) error {
+	username := c.Get("username").(string)
+
+	setupResp, err := ctrl.userService.SetupTwoFactorSvc(username)
+	if err != nil {
+		return c.JSON(twoFactorErrStatus(setupResp), setupResp)
+	}
+
+	return c.JSON(http.StatusOK, setupResp)
+}
+
+func (ctrl *Controller) ActivateTwoFactorCtrl(c echo.Context) error {
+	payload, badRequest := bindTwoFactorCode(c)
+	if badRequest != nil {
+		return c.JSON(http.StatusBadRequest, badRequest)
+	}
+
+	activateResp, err := ctrl.userService.ActivateTwoFactorSvc(payload)
+	if err != nil {
+		return c.JSON(twoFactorErrStatus(activateResp), activateResp)
+	}
+
+	return c.JSON(http.StatusOK, activateResp)
+}
+
+func (ctrl *Controller) DisableTwoFactorCtrl(c echo.Context) error {
+	payload, badRequest := bindTwoFactorCode(c)
+	if badRequest != nil {
+		return c.JSON(http.StatusBadRequest, badRequest)
+	}
+
+	disableResp, err := ctrl.userService.DisableTwoFactorSvc(payload)
+	if err != nil {
+		return c.JSON(twoFactorErrStatus(disableResp), disableResp)
+	}
+
+	return c.JSON(http.StatusOK, disableResp)
+}
+
+func (ctrl *Controller) RegenerateRecoveryCodesCtrl(c echo.Context) error {
+	payload, badRequest := bindTwoFactorCode(c)
+	if badRequest != nil {
+		return c.JSON(http.StatusBadRequest, badRequest)
+	}
+
+	regenerateResp, err := ctrl.userService.RegenerateRecoveryCodesSvc(payload)
+	if err != nil {
+		return c.JSON(twoFactorErrStatus(regenerateResp), regenerateResp)
+	}
+
+	return c.JSON(http.StatusOK, regenerateResp)
+}
+
+// bindTwoFactorCode returns the bad request response when the payload can't be used
+func bindTwoFactorCode(c echo.Context) (dto.TwoFactorCodePayload, *dto.ResponseDto) {
+	var payload dto.TwoFactorCodePayload
+	err := c.Bind(&payload)
+	if err != nil {
+		return payload, &dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		}
+	}
+
+	if payload.Code == "" {
+		return payload, &dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "code is mandatory",
+		}
+	}
+
+	payload.Username = c.Get("username").(string)
+	return payload, nil
+}
+
+// twoFactorErrStatus keeps the bad request and unauthorized answers of the service, anything else failed on our side
+func twoFactorErrStatus(resp dto.ResponseDto) int {
+	if resp.ResponseCode == http.StatusBadRequest || resp.ResponseCode == http.StatusUnauthorized {
+		return resp.ResponseCode
+	}
+
+	return http.StatusUnprocessableEntity
+}
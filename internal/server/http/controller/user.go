@@ -27,18 +27,64 @@ func (ctrl *Controller) Authentication(c echo.Context) error {
 		})
 	}
 
+	payload.Device = c.Request().UserAgent()
+	payload.IpAddress = c.RealIP()
 	authResponse, err := ctrl.userService.Login(payload)
 	if err != nil {
 		if err.Error() == "invalid password" || err.Error() == "user not found" {
 			return c.JSON(http.StatusBadRequest, authResponse)
 		}
 
+		if authResponse.ResponseCode == http.StatusTooManyRequests {
+			return c.JSON(http.StatusTooManyRequests, authResponse)
+		}
+
 		return c.JSON(http.StatusUnprocessableEntity, authResponse)
 	}
 
 	return c.JSON(http.StatusOK, authResponse)
 }
 
+func (ctrl *Controller) RefreshTokenCtrl(c echo.Context) error {
+	var payload dto.RefreshTokenPayload
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if payload.RefreshToken == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "refresh token is mandatory",
+		})
+	}
+
+	refreshResp, err := ctrl.userService.RefreshTokenSvc(payload)
+	if err != nil {
+		if refreshResp.ResponseCode == http.StatusUnauthorized {
+			return c.JSON(http.StatusUnauthorized, refreshResp)
+		}
+
+		return c.JSON(http.StatusUnprocessableEntity, refreshResp)
+	}
+
+	return c.JSON(http.StatusOK, refreshResp)
+}
+
+func (ctrl *Controller) LogoutCtrl(c echo.Context) error {
+	sessionId := c.Get("sessionId").(string)
+
+	logoutResp, err := ctrl.userService.LogoutSvc(sessionId)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, logoutResp)
+	}
+
+	return c.JSON(http.StatusOK, logoutResp)
+}
+
 func (ctrl *Controller) GetListUserMerchants(c echo.Context) error {
 	userType := c.Get("userType").(string)
 
@@ -69,7 +115,6 @@ func (ctrl *Controller) GetListUserMerchants(c echo.Context) error {
 
 func (ctrl *Controller) InviteUserMerchantCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	var payload dto.InviteMerchantUserDto
 
 	// blocked merchant user for further access
@@ -80,13 +125,6 @@ func (ctrl *Controller) InviteUserMerchantCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can invite merchant user",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
@@ -111,6 +149,7 @@ func (ctrl *Controller) InviteUserMerchantCtrl(c echo.Context) error {
 		})
 	}
 
+	payload.Username = c.Get("username").(string)
 	inviteUserResp, err := ctrl.userService.InviteUserMerchantSvc(payload)
 	if err != nil {
 		return c.JSON(http.StatusUnprocessableEntity, inviteUserResp)
@@ -176,6 +215,42 @@ func (ctrl *Controller) UpdatePinPasswordCtrl(c echo.Context) error {
 	return c.JSON(http.StatusOK, updateRes)
 }
 
+func (ctrl *Controller) UnlockUserCtrl(c echo.Context) error {
+	userType := c.Get("userType").(string)
+	var payload dto.UnlockUserPayload
+
+	// blocked merchant user for further access
+	if userType != constant.UserOperation {
+		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: "only operations can access this endpoint",
+		})
+	}
+
+	err := c.Bind(&payload)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		})
+	}
+
+	if payload.Username == "" && payload.IpAddress == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "username or ip address is mandatory",
+		})
+	}
+
+	payload.Actor = auditActor(c)
+	unlockRes, err := ctrl.userService.UnlockUserSvc(payload)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, unlockRes)
+	}
+
+	return c.JSON(http.StatusOK, unlockRes)
+}
+
 func (ctrl *Controller) GetMerchantRolesCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
 
@@ -217,7 +292,6 @@ func (ctrl *Controller) GetMerchantListUserCtrl(c echo.Context) error {
 
 func (ctrl *Controller) InviteMerchantUserCtrl(c echo.Context) error {
 	userType := c.Get("userType").(string)
-	roleName := c.Get("roleName").(string)
 	username := c.Get("username").(string)
 	var payload dto.InviteMerchantUserDto
 
@@ -229,13 +303,6 @@ func (ctrl *Controller) InviteMerchantUserCtrl(c echo.Context) error {
 		})
 	}
 
-	if roleName != constant.RoleNameAdmin {
-		return c.JSON(http.StatusBadGateway, dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: "only admin can invite user",
-		})
-	}
-
 	err := c.Bind(&payload)
 	if err != nil {
 		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
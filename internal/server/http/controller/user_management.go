@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/labstack/echo/v4"
+)
+
+func (ctrl *Controller) UpdateMerchantUserStatusCtrl(c echo.Context) error {
+	payload, badRequest := bindMerchantUser(c)
+	if badRequest != nil {
+		return c.JSON(badRequest.ResponseCode, badRequest)
+	}
+
+	if payload.Status == "" {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "status is mandatory",
+		})
+	}
+
+	updateResp, err := ctrl.userService.UpdateMerchantUserStatusSvc(payload)
+	if err != nil {
+		if updateResp.ResponseCode == http.StatusBadRequest {
+			return c.JSON(http.StatusBadRequest, updateResp)
+		}
+
+		return c.JSON(http.StatusUnprocessableEntity, updateResp)
+	}
+
+	return c.JSON(http.StatusOK, updateResp)
+}
+
+func (ctrl *Controller) UpdateMerchantUserRoleCtrl(c echo.Context) error {
+	payload, badRequest := bindMerchantUser(c)
+	if badRequest != nil {
+		return c.JSON(badRequest.ResponseCode, badRequest)
+	}
+
+	if payload.RoleId == 0 {
+		return c.JSON(http.StatusBadRequest, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "role id is mandatory",
+		})
+	}
+
+	updateResp, err := ctrl.userService.UpdateMerchantUserRoleSvc(payload)
+	if err != nil {
+		if updateResp.ResponseCode == http.StatusBadRequest {
+			return c.JSON(http.StatusBadRequest, updateResp)
+		}
+
+		return c.JSON(http.StatusUnprocessableEntity, updateResp)
+	}
+
+	return c.JSON(http.StatusOK, updateResp)
+}
+
+func (ctrl *Controller) RemoveMerchantUserCtrl(c echo.Context) error {
+	payload, badRequest := bindMerchantUser(c)
+	if badRequest != nil {
+		return c.JSON(badRequest.ResponseCode, badRequest)
+	}
+
+	removeResp, err := ctrl.userService.RemoveMerchantUserSvc(payload)
+	if err != nil {
+		if removeResp.ResponseCode == http.StatusBadRequest {
+			return c.JSON(http.StatusBadRequest, removeResp)
+		}
+
+		return c.JSON(http.StatusUnprocessableEntity, removeResp)
+	}
+
+	return c.JSON(http.StatusOK, removeResp)
+}
+
+func bindMerchantUser(c echo.Context) (dto.MerchantUserPayload, *dto.ResponseDto) {
+	var payload dto.MerchantUserPayload
+	err := c.Bind(&payload)
+	if err != nil {
+		return payload, &dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "invalid request payload",
+		}
+	}
+
+	if payload.UserId == 0 {
+		return payload, &dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "user id is mandatory",
+		}
+	}
+
+	payload.Actor = auditActor(c)
+	return payload, scopeMerchant(c, &payload.MerchantId, &payload.Actor.Username)
+}
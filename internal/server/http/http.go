@@ -1,6 +1,9 @@
 package http
 
 import (
+	"net"
+	"strings"
+
 	"github.com/hypay-id/backend-dashboard-hypay/config"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
 	httpMiddleware "github.com/hypay-id/backend-dashboard-hypay/internal/server/http/middleware"
@@ -72,12 +75,14 @@ func (s *Server) connectCoreWithEcho() {
 	}
 
 	// Middleware
+	s.echo.Use(middleware.RequestID())
 	s.echo.Use(httpMiddleware.RequestLogWithConfig())
 	s.echo.Use(middleware.Recover())
 	s.echo.Use(middleware.CORSWithConfig(corsConfig))
 
 	RegisterRoutes(s.echo, s.httpController)
 	setServerObj(s.echo, s.cfg)
+	setIPExtractor(s.echo, s.cfg)
 }
 
 func (h *Server) initGracefulServer() {
@@ -101,3 +106,31 @@ func setServerObj(e *echo.Echo, serverCfg config.HTTPServer) {
 		e.Server.IdleTimeout = serverCfg.IdleTimeout
 	}
 }
+
+// setIPExtractor decides where c.RealIP() comes from, login throttling and the provider callback
+// allowlist rely on it. X-Forwarded-For is only read when the request comes from a trusted proxy.
+func setIPExtractor(e *echo.Echo, serverCfg config.HTTPServer) {
+	if strings.TrimSpace(serverCfg.TrustedProxies) == "" {
+		e.IPExtractor = echo.ExtractIPDirect()
+		return
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, cidr := range strings.Split(serverCfg.TrustedProxies, ",") {
+		_, ipRange, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			slog.Fatalw(
+				"invalid trusted proxy range",
+				"error",
+				err.Error(),
+			)
+		}
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	e.IPExtractor = echo.ExtractIPFromXFFHeader(options...)
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hypay-id/backend-dashboard-hypay/config"
+	"github.com/labstack/echo/v4"
+)
+
+// TestRealIP pins the client address login throttling, lockouts and the callback allowlist are keyed on
+func TestRealIP(t *testing.T) {
+	tests := []struct {
+		name           string
+		trustedProxies string
+		remoteAddr     string
+		forwardedFor   string
+		realIP         string
+		want           string
+	}{
+		{"direct", "", "203.0.113.7:51000", "", "", "203.0.113.7"},
+		{"spoofed forwarded for without proxy", "", "203.0.113.7:51000", "198.51.100.1", "", "203.0.113.7"},
+		{"spoofed real ip without proxy", "", "203.0.113.7:51000", "", "198.51.100.1", "203.0.113.7"},
+		{"private address is not trusted by default", "", "10.0.0.5:51000", "198.51.100.1", "", "10.0.0.5"},
+		{"trusted proxy", "10.0.0.0/8", "10.0.0.5:51000", "198.51.100.1", "", "198.51.100.1"},
+		{"spoofed entry before the trusted proxy", "10.0.0.0/8", "10.0.0.5:51000", "192.0.2.9, 198.51.100.1", "", "198.51.100.1"},
+		{"untrusted source with trusted ranges", "10.0.0.0/8", "203.0.113.7:51000", "198.51.100.1", "", "203.0.113.7"},
+		{"several ranges", "192.168.0.0/16, 10.0.0.0/8", "10.0.0.5:51000", "198.51.100.1", "", "198.51.100.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			setIPExtractor(e, config.HTTPServer{TrustedProxies: tt.trustedProxies})
+
+			r := httptest.NewRequest(http.MethodPost, "/login", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				r.Header.Set(echo.HeaderXForwardedFor, tt.forwardedFor)
+			}
+			if tt.realIP != "" {
+				r.Header.Set(echo.HeaderXRealIP, tt.realIP)
+			}
+
+			got := e.NewContext(r, httptest.NewRecorder()).RealIP()
+			if got != tt.want {
+				t.Errorf("RealIP = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
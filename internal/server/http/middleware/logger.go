@@ -50,6 +50,7 @@ func RequestLogWithConfig() echo.MiddlewareFunc {
 				zap.String("method", req.Method),
 				zap.String("path", req.URL.Path),
 				zap.String("referer", req.Referer()),
+				zap.String("request_id", res.Header().Get(echo.HeaderXRequestID)),
 				zap.Duration("latency", stop.Sub(start)),
 				zap.String("latency_human", stop.Sub(start).String()),
 			)
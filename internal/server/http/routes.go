@@ -1,6 +1,7 @@
 package http
 
 import (
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/server/http/controller"
 	"github.com/labstack/echo/v4"
 )
@@ -8,19 +9,40 @@ import (
 func RegisterRoutes(e *echo.Echo, ctrl *controller.Controller) {
 	e.GET("/", ctrl.ReturnOK)
 
+	// every group keeps the mutating requests of logged in users in the audit trail
+
 	// dashboard login
-	u := e.Group("/v1")
+	u := e.Group("/v1", ctrl.AuditMiddleware)
 	u.POST("/login", ctrl.Authentication)
+	u.POST("/refresh", ctrl.RefreshTokenCtrl)
+	u.POST("/logout", ctrl.AuthMiddleware(ctrl.LogoutCtrl))
+	u.POST("/login/two-factor", ctrl.LoginTwoFactorCtrl)
+	u.POST("/login/two-factor/setup", ctrl.SetupTwoFactorLoginCtrl)
+	u.POST("/login/two-factor/activate", ctrl.ActivateTwoFactorLoginCtrl)
+	u.POST("/reset-credential/request", ctrl.RequestCredentialResetCtrl)
+	u.POST("/reset-credential/confirm", ctrl.ConfirmCredentialResetCtrl)
+	u.POST("/accept-invite", ctrl.AcceptInvitationCtrl)
+	u.POST("/two-factor/setup", ctrl.AuthMiddleware(ctrl.SetupTwoFactorCtrl))
+	u.POST("/two-factor/activate", ctrl.AuthMiddleware(ctrl.ActivateTwoFactorCtrl))
+	u.POST("/two-factor/disable", ctrl.AuthMiddleware(ctrl.DisableTwoFactorCtrl))
+	u.POST("/two-factor/recovery-codes", ctrl.AuthMiddleware(ctrl.RegenerateRecoveryCodesCtrl))
 
 	// operations dashboard
-	ops := e.Group("/operation-dashboard/v1")
+	ops := e.Group("/operation-dashboard/v1", ctrl.AuditMiddleware)
 	// PATCH method
-	ops.PATCH("/update-status", ctrl.AuthMiddleware(ctrl.UpdateStatusTransaction))
-	ops.PATCH("/merchant-update-status", ctrl.AuthMiddleware(ctrl.UpdateMerchantStatusCtrl))
-	ops.PATCH("/merchant-paychannel-update-status", ctrl.AuthMiddleware(ctrl.UpdateStatusMerchantPaychannel))
-	ops.PATCH("/update-provider-paychannel-status", ctrl.AuthMiddleware(ctrl.UpdateStatusProviderPaychannelSvc))
-	ops.PATCH("/update-fee-limit", ctrl.AuthMiddleware(ctrl.UpdateLimitOrFeeCtrl))
-	ops.PATCH("/update-fee-limit-interface-pchannel", ctrl.AuthMiddleware(ctrl.UpdateLimitFeeInterfacePchannelCtrl))
+	ops.PATCH("/update-status", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionTransactionUpdateStatus, ctrl.UpdateStatusTransaction)))
+	ops.PATCH("/merchant-update-status", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUpdateStatus, ctrl.UpdateMerchantStatusCtrl)))
+	ops.PATCH("/merchant-paychannel-update-status", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantPaychannelManage, ctrl.UpdateStatusMerchantPaychannel)))
+	ops.PATCH("/update-provider-paychannel-status", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionProviderPaychannelManage, ctrl.UpdateStatusProviderPaychannelSvc)))
+	ops.PATCH("/update-fee-limit", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantPaychannelManage, ctrl.UpdateLimitOrFeeCtrl)))
+	ops.PATCH("/update-fee-limit-interface-pchannel", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionProviderPaychannelManage, ctrl.UpdateLimitFeeInterfacePchannelCtrl)))
+	ops.PATCH("/update-role-permissions", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionRoleManage, ctrl.UpdateRolePermissionsCtrl)))
+	ops.PATCH("/resolve-reconciliation-discrepancy", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionReconciliationResolve, ctrl.ResolveReconciliationDiscrepancyCtrl)))
+	ops.PATCH("/unlock-user", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionUserUnlock, ctrl.UnlockUserCtrl)))
+	ops.PATCH("/revoke-invitation", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUserInvite, ctrl.RevokeInvitationCtrl)))
+	ops.PATCH("/merchant-user-status", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUserManage, ctrl.UpdateMerchantUserStatusCtrl)))
+	ops.PATCH("/merchant-user-role", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUserManage, ctrl.UpdateMerchantUserRoleCtrl)))
+	ops.PATCH("/balance-approval-threshold", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionBalanceThresholdManage, ctrl.UpdateBalanceApprovalThresholdCtrl)))
 
 	// GET method
 	ops.GET("/transaction-list", ctrl.AuthMiddleware(ctrl.GetListTransaction))
@@ -42,12 +64,12 @@ func RegisterRoutes(e *echo.Echo, ctrl *controller.Controller) {
 	ops.GET("/merchant-paychannel", ctrl.AuthMiddleware(ctrl.GetListMerchantPaychannleCtrl))
 	ops.GET("/merchant-paychannel-analytics", ctrl.AuthMiddleware(ctrl.GetMerchantPaychannelAnalyticsCtrl))
 	ops.GET("/list-capital-transaction", ctrl.AuthMiddleware(ctrl.GetListCapitalFlowTransactionCtrl))
-	ops.GET("/paychannel-tier", ctrl.AuthMiddleware(ctrl.GetPaychannelTierCtrl))
+	ops.GET("/paychannel-tier", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantPaychannelManage, ctrl.GetPaychannelTierCtrl)))
 	ops.GET("/list-merchant-account", ctrl.AuthMiddleware(ctrl.GetListMerchantAccountCtrl))
 	ops.GET("/routed-paychannel", ctrl.AuthMiddleware(ctrl.GetRoutedPaychannelCtrl))
 	ops.GET("/paychannel-payment-operators", ctrl.AuthMiddleware(ctrl.GetPaymentOperatorsCtrl))
-	ops.GET("/aggregated-paychannel", ctrl.AuthMiddleware(ctrl.GetAggregatedPaychannelCtrl))
-	ops.GET("/list-payment-method", ctrl.AuthMiddleware(ctrl.GetListChannelCreateMerchantPaychannelCtrl))
+	ops.GET("/aggregated-paychannel", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantPaychannelManage, ctrl.GetAggregatedPaychannelCtrl)))
+	ops.GET("/list-payment-method", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantPaychannelManage, ctrl.GetListChannelCreateMerchantPaychannelCtrl)))
 	ops.GET("/active-available-paychannel", ctrl.AuthMiddleware(ctrl.GetActiveAvailableChannel))
 	ops.GET("/get-list-providers", ctrl.AuthMiddleware(ctrl.GetListProvidersCtrl))
 	ops.GET("/provider-analytics", ctrl.AuthMiddleware(ctrl.GetProviderAnalyticsCtrl))
@@ -58,35 +80,52 @@ func RegisterRoutes(e *echo.Echo, ctrl *controller.Controller) {
 	ops.GET("/get-merchant-information", ctrl.AuthMiddleware(ctrl.GetMerchantInformationCtrl))
 	ops.GET("/get-roles", ctrl.AuthMiddleware(ctrl.GetRolesCtrl))
 	ops.GET("/get-users-merchant", ctrl.AuthMiddleware(ctrl.GetListUserMerchants))
+	ops.GET("/list-invitations", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUserInvite, ctrl.GetListInvitationsCtrl)))
 	ops.GET("/get-list-provider-channel-all", ctrl.AuthMiddleware(ctrl.GetListProviderChannelAllCtrl))
 	ops.GET("/provider-channel-analytics", ctrl.AuthMiddleware(ctrl.GetProviderChannelAnalyticsCtrl))
 	ops.GET("/get-list-pchannel-operators", ctrl.AuthMiddleware(ctrl.GetProviderChannelOperatorsCtrl))
 	ops.GET("/get-routed-provider-channel", ctrl.AuthMiddleware(ctrl.GetListRoutedProviderChannelCtrl))
-	ops.GET("/get-provider-interface", ctrl.AuthMiddleware(ctrl.GetListProviderInterfaceCtrl))
-	ops.GET("/get-payment-operator-create-channel", ctrl.AuthMiddleware(ctrl.GetListPaymentOperatorCreateProviderChannelCtrl))
+	ops.GET("/get-provider-interface", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionProviderPaychannelManage, ctrl.GetListProviderInterfaceCtrl)))
+	ops.GET("/get-payment-operator-create-channel", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionProviderPaychannelManage, ctrl.GetListPaymentOperatorCreateProviderChannelCtrl)))
+	ops.GET("/list-reconciliation-run", ctrl.AuthMiddleware(ctrl.GetListReconciliationRunCtrl))
+	ops.GET("/reconciliation-run-detail", ctrl.AuthMiddleware(ctrl.GetReconciliationRunDetailCtrl))
+	ops.GET("/get-permissions", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionRoleManage, ctrl.GetPermissionsCtrl)))
+	ops.GET("/audit-logs", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionAuditView, ctrl.GetListAuditLogCtrl)))
+	ops.GET("/verify-hash-chain", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionAuditView, ctrl.VerifyHashChainCtrl)))
+	ops.GET("/chain-checkpoints", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionAuditView, ctrl.GetListChainCheckpointCtrl)))
+	ops.GET("/balance-requests", ctrl.AuthMiddleware(ctrl.GetListBalanceOperationRequestCtrl))
+	ops.GET("/balance-request-detail", ctrl.AuthMiddleware(ctrl.GetBalanceOperationRequestDetailCtrl))
+	ops.GET("/balance-approval-thresholds", ctrl.AuthMiddleware(ctrl.GetListBalanceApprovalThresholdCtrl))
 
 	// POST Method
-	ops.POST("/top-up", ctrl.AuthMiddleware(ctrl.TopUpMerchantCtrl))
-	ops.POST("/hold-balance", ctrl.AuthMiddleware(ctrl.HoldBalanceCtrl))
-	ops.POST("/add-settlement", ctrl.AuthMiddleware(ctrl.AddSettlementCtrl))
-	ops.POST("/balance-transfer", ctrl.AuthMiddleware(ctrl.BalanceTransferCtrl))
-	ops.POST("/send-callback", ctrl.AuthMiddleware(ctrl.SendCallbackCtrl))
-	ops.POST("/payout-settlement", ctrl.AuthMiddleware(ctrl.SendPayoutSettlementCtrl))
-	ops.POST("/reverse-manual-payment", ctrl.AuthMiddleware(ctrl.ReverseManualPaymentCtrl))
-	ops.POST("/create-merchant", ctrl.AuthMiddleware(ctrl.CreateMerchantCtrl))
-	ops.POST("/add-segment", ctrl.AuthMiddleware(ctrl.AddSegmentCtrl))
-	ops.POST("/add-channel", ctrl.AuthMiddleware(ctrl.AddChannelCtrl))
-	ops.POST("/routing-paychannel", ctrl.AuthMiddleware(ctrl.AddRoutingPaychannelCtrl))
-	ops.POST("/merchant-export", ctrl.AuthMiddleware(ctrl.CreateMerchantExportCtrl))
-	ops.POST("/internal-export", ctrl.AuthMiddleware(ctrl.CreateInternalExportCtrl))
-	ops.POST("/display-api-key", ctrl.AuthMiddleware(ctrl.DisplaySecretKeyCtrl))
-	ops.POST("/generate-api-key-merchant", ctrl.AuthMiddleware(ctrl.GenerateSecretKeyCtrl))
-	ops.POST("/invite-user-merchant", ctrl.AuthMiddleware(ctrl.InviteUserMerchantCtrl))
-	ops.POST("/add-operator-channel", ctrl.AuthMiddleware(ctrl.AddOperatorProviderChannelCtrl))
-	ops.POST("/create-provider-paychannel", ctrl.AuthMiddleware(ctrl.CreateProviderPaychannelCtrl))
+	ops.POST("/top-up", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionBalanceTopUp, ctrl.TopUpMerchantCtrl)))
+	ops.POST("/hold-balance", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionBalanceHold, ctrl.HoldBalanceCtrl)))
+	ops.POST("/add-settlement", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionBalanceSettlement, ctrl.AddSettlementCtrl)))
+	ops.POST("/balance-transfer", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionBalanceTransfer, ctrl.BalanceTransferCtrl)))
+	ops.POST("/send-callback", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionCallbackSend, ctrl.SendCallbackCtrl)))
+	ops.POST("/payout-settlement", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionBalancePayoutSettlement, ctrl.SendPayoutSettlementCtrl)))
+	ops.POST("/reverse-manual-payment", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionBalanceReverse, ctrl.ReverseManualPaymentCtrl)))
+	ops.POST("/approve-balance-request", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionBalanceApprove, ctrl.ApproveBalanceOperationRequestCtrl)))
+	ops.POST("/reject-balance-request", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionBalanceApprove, ctrl.RejectBalanceOperationRequestCtrl)))
+	ops.POST("/create-merchant", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantCreate, ctrl.CreateMerchantCtrl)))
+	ops.POST("/add-segment", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantPaychannelManage, ctrl.AddSegmentCtrl)))
+	ops.POST("/add-channel", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantPaychannelManage, ctrl.AddChannelCtrl)))
+	ops.POST("/routing-paychannel", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantPaychannelManage, ctrl.AddRoutingPaychannelCtrl)))
+	ops.POST("/merchant-export", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionExportCreate, ctrl.CreateMerchantExportCtrl)))
+	ops.POST("/internal-export", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionExportCreate, ctrl.CreateInternalExportCtrl)))
+	ops.POST("/display-api-key", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantSecretView, ctrl.DisplaySecretKeyCtrl)))
+	ops.POST("/generate-api-key-merchant", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantSecretRotate, ctrl.GenerateSecretKeyCtrl)))
+	ops.GET("/live-merchant-keys", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantSecretView, ctrl.GetLiveMerchantKeysCtrl)))
+	ops.POST("/invite-user-merchant", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUserInvite, ctrl.InviteUserMerchantCtrl)))
+	ops.POST("/resend-invitation", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUserInvite, ctrl.ResendInvitationCtrl)))
+	ops.POST("/remove-merchant-user", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUserManage, ctrl.RemoveMerchantUserCtrl)))
+	ops.POST("/add-operator-channel", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionProviderPaychannelManage, ctrl.AddOperatorProviderChannelCtrl)))
+	ops.POST("/create-provider-paychannel", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionProviderPaychannelManage, ctrl.CreateProviderPaychannelCtrl)))
+	ops.POST("/create-role", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionRoleManage, ctrl.CreateRoleCtrl)))
+	ops.POST("/reconciliation-run", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionReconciliationRun, ctrl.RunReconciliationCtrl)))
 
 	// merchant endpoint
-	mrn := e.Group("/merchant-dashboard/v1")
+	mrn := e.Group("/merchant-dashboard/v1", ctrl.AuditMiddleware)
 
 	// get method
 	mrn.GET("/home-analytics", ctrl.AuthMiddleware(ctrl.HomeAnalyticsCtrl))
@@ -104,18 +143,37 @@ func RegisterRoutes(e *echo.Echo, ctrl *controller.Controller) {
 	mrn.GET("/get-user-information", ctrl.AuthMiddleware(ctrl.GetUserInformationCtrl))
 	mrn.GET("/get-merchant-roles", ctrl.AuthMiddleware(ctrl.GetMerchantRolesCtrl))
 	mrn.GET("/get-merchant-list-user", ctrl.AuthMiddleware(ctrl.GetMerchantListUserCtrl))
+	mrn.GET("/list-invitations", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUserInvite, ctrl.GetListInvitationsCtrl)))
 	mrn.GET("/get-information-merchant", ctrl.AuthMiddleware(ctrl.GetInformationMerchantCtrl))
+	mrn.GET("/disbursement-requests", ctrl.AuthMiddleware(ctrl.GetListDisbursementRequestCtrl))
+	mrn.GET("/disbursement-request-detail", ctrl.AuthMiddleware(ctrl.GetDisbursementRequestDetailCtrl))
+	mrn.GET("/disbursement-approval-rules", ctrl.AuthMiddleware(ctrl.GetListDisbursementApprovalRuleCtrl))
+	mrn.GET("/disbursement-batches", ctrl.AuthMiddleware(ctrl.GetListDisbursementBatchCtrl))
+	mrn.GET("/disbursement-batch-detail", ctrl.AuthMiddleware(ctrl.GetDisbursementBatchDetailCtrl))
+	mrn.GET("/disbursement-batch-result", ctrl.AuthMiddleware(ctrl.DownloadDisbursementBatchResultCtrl))
 
 	// post method
 	mrn.POST("/resend-callback", ctrl.AuthMiddleware(ctrl.ResendCallbackMerchantCtrl))
-	mrn.POST("/disbursement", ctrl.AuthMiddleware(ctrl.DisbursementCtrl))
-	mrn.POST("/count-disbursement", ctrl.AuthMiddleware(ctrl.CountDisbursementTotalAmountCtrl))
+	mrn.POST("/disbursement", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionDisbursementCreate, ctrl.DisbursementCtrl)))
+	mrn.POST("/approve-disbursement", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionDisbursementApprove, ctrl.ApproveDisbursementRequestCtrl)))
+	mrn.POST("/reject-disbursement", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionDisbursementApprove, ctrl.RejectDisbursementRequestCtrl)))
+	mrn.POST("/upload-disbursement-batch", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionDisbursementCreate, ctrl.UploadDisbursementBatchCtrl)))
+	mrn.POST("/confirm-disbursement-batch", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionDisbursementCreate, ctrl.ConfirmDisbursementBatchCtrl)))
+	mrn.POST("/count-disbursement", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionDisbursementCreate, ctrl.CountDisbursementTotalAmountCtrl)))
 	mrn.POST("/provider-jack/disbursement", ctrl.JackDisbursementCallbackCtrl)
-	mrn.POST("/create-report", ctrl.AuthMiddleware(ctrl.CreateMerchantReportCtrl))
-	mrn.POST("/invite-merchant-user", ctrl.AuthMiddleware(ctrl.InviteMerchantUserCtrl))
-	mrn.POST("/display-merchant-key", ctrl.AuthMiddleware(ctrl.DisplayMerchantKeyCtrl))
-	mrn.POST("/generate-merchant-key", ctrl.AuthMiddleware(ctrl.GenerateMerchantKeyCtrl))
+	mrn.POST("/provider/:providerId/disbursement", ctrl.DisbursementCallbackCtrl)
+	mrn.POST("/create-report", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionExportCreate, ctrl.CreateMerchantReportCtrl)))
+	mrn.POST("/invite-merchant-user", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUserInvite, ctrl.InviteMerchantUserCtrl)))
+	mrn.POST("/resend-invitation", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUserInvite, ctrl.ResendInvitationCtrl)))
+	mrn.POST("/remove-merchant-user", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUserManage, ctrl.RemoveMerchantUserCtrl)))
+	mrn.POST("/display-merchant-key", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantSecretView, ctrl.DisplayMerchantKeyCtrl)))
+	mrn.POST("/generate-merchant-key", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantSecretRotate, ctrl.GenerateMerchantKeyCtrl)))
+	mrn.GET("/live-merchant-keys", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantSecretView, ctrl.GetLiveMerchantKeysCtrl)))
 
 	// patch method
 	mrn.PATCH("/update-pin-password", ctrl.AuthMiddleware(ctrl.UpdatePinPasswordCtrl))
+	mrn.PATCH("/revoke-invitation", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUserInvite, ctrl.RevokeInvitationCtrl)))
+	mrn.PATCH("/merchant-user-status", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUserManage, ctrl.UpdateMerchantUserStatusCtrl)))
+	mrn.PATCH("/merchant-user-role", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionMerchantUserManage, ctrl.UpdateMerchantUserRoleCtrl)))
+	mrn.PATCH("/disbursement-approval-rules", ctrl.AuthMiddleware(ctrl.RequirePermission(constant.PermissionDisbursementApprovalManage, ctrl.UpdateDisbursementApprovalRulesCtrl)))
 }
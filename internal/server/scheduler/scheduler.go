@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       func() error
+}
+
+// Scheduler runs background jobs on a fixed interval next to the http server
+type Scheduler struct {
+	jobs []job
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		stop: make(chan struct{}),
+	}
+}
+
+// Every registers fn to run each interval, jobs must be registered before Start
+func (s *Scheduler) Every(interval time.Duration, name string, fn func() error) {
+	s.jobs = append(s.jobs, job{
+		name:     name,
+		interval: interval,
+		fn:       fn,
+	})
+}
+
+func (s *Scheduler) Start() {
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.run(j)
+	}
+
+	slog.Infof("scheduler started with %d jobs", len(s.jobs))
+}
+
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			err := j.fn()
+			if err != nil {
+				slog.Errorw("scheduled job failed", "job", j.name, "stack_trace", err.Error())
+			}
+		}
+	}
+}
@@ -14,7 +14,7 @@ type TransactionServiceItf interface {
 	GetStatusChangeLog(paymentId string) (dto.ResponseDto, error)
 	GetPaymentDetailCapitalFlow(paymentId string) (dto.ResponseDto, error)
 	GetListFilterSvc() (dto.ResponseDto, error)
-	UpdateStatusTransaction(paymentId string, status string, username string, notes string, pin string) (dto.ResponseDto, error)
+	UpdateStatusTransaction(paymentId string, status string, username string, canOverride bool, notes string, pin string) (dto.ResponseDto, error)
 	CreateMerchantExportSvc(payload dto.CreateMerchantExportReqDto) (dto.ResponseDto, error)
 	GetListMerchantExportSvc(params dto.GetListMerchantExportFilter) (dto.ResponseDto, error)
 	GetListFilterExportSvc() (dto.ResponseDto, error)
@@ -26,10 +26,22 @@ type TransactionServiceItf interface {
 	MerchantDisbursementSvc(payload dto.MerchantDisbursement) (dto.ResponseDto, error)
 	GetBankListDisbursementSvc(username string) (dto.ResponseDto, error)
 	CountDisbursementTotalAmountSvc(payload dto.CountDisbursementTotalAmountDto) (dto.ResponseDto, error)
-	JackDisbursementCallbackHandlingSvc(payload dto.CreateDisbursementRequestResponseData) (string, error)
+	DisbursementCallbackHandlingSvc(payload dto.ProviderCallbackReq) (string, error)
 	GetReportListMerchantSvc(req dto.GetListMerchantExportFilter, username string) (dto.ResponseDto, error)
 	CreateReportMerchantSvc(req dto.CreateReportMerchantReqDto) (dto.ResponseDto, error)
 	GetListTransactionMerchantFlowSvc(params dto.QueryParams) (dto.ResponseDto, error)
+	ReviewDisbursementRequestSvc(payload dto.ReviewDisbursementRequestReq) (dto.ResponseDto, error)
+	GetListDisbursementRequestSvc(params dto.QueryParamsDisbursementRequest) (dto.ResponseDto, error)
+	GetDisbursementRequestDetailSvc(requestId int, username string) (dto.ResponseDto, error)
+	GetListDisbursementApprovalRuleSvc(username string) (dto.ResponseDto, error)
+	UpdateDisbursementApprovalRulesSvc(payload dto.UpdateDisbursementApprovalRulesReq) (dto.ResponseDto, error)
+	FailStaleDisbursementRequestsSvc() error
+	UploadDisbursementBatchSvc(payload dto.UploadDisbursementBatchReq) (dto.ResponseDto, error)
+	ConfirmDisbursementBatchSvc(payload dto.ConfirmDisbursementBatchReq) (dto.ResponseDto, error)
+	GetListDisbursementBatchSvc(params dto.QueryParamsDisbursementBatch) (dto.ResponseDto, error)
+	GetDisbursementBatchDetailSvc(batchId int, username string) (dto.ResponseDto, error)
+	DownloadDisbursementBatchResultSvc(batchId int, username string) (dto.ResponseDto, error)
+	ResumeDisbursementBatchesSvc() error
 }
 
 type MerchantServiceItf interface {
@@ -42,6 +54,7 @@ type MerchantServiceItf interface {
 	SettlementBalanceSvc(payload dto.AdjustBalanceReqPayload) (dto.ResponseDto, error)
 	BalanceTransferSvc(payload dto.BalanceTrfReqPayload) (dto.ResponseDto, error)
 	SendCallbackSvc(paymentId string, username string) (dto.ResponseDto, error)
+	DeliverMerchantCallbacksSvc() error
 	PayoutSettlementSvc(payload dto.AdjustBalanceReqPayload) (dto.ResponseDto, error)
 	GetDetailManualPaymentSvc(paymentId string) (dto.ResponseDto, error)
 	GetListMerchantWithFilterSvc(params dto.QueryParams) (dto.ResponseDto, error)
@@ -68,17 +81,34 @@ type MerchantServiceItf interface {
 	HomeAnalyticsSvc(payload dto.HomeAnalyticsDto) (dto.ResponseDto, error)
 	GetMerchantInformationSvc(merchantId string) (dto.ResponseDto, error)
 	DisplaySecretKeySvc(pin string, username string, merchantId string) (dto.ResponseDto, error)
-	GenerateSecretKeySvc(pin string, username string, merchantId string) (dto.ResponseDto, error)
+	GenerateSecretKeySvc(payload dto.RotateMerchantKeyPayload) (dto.ResponseDto, error)
 	GetListOtherTransactionsSvc(params dto.QueryParamsManualPayment) (dto.ResponseDto, error)
 	GetListCallbackMerchantSvc(params dto.QueryParamsMerchantCallback) (dto.ResponseDto, error)
 	GetMerchantAccountBalanceSvc(username string) (dto.ResponseDto, error)
 	GetInformationMerchantSvc(username string) (dto.ResponseDto, error)
 	DisplayMerchantKeySvc(username string, pin string) (dto.ResponseDto, error)
-	GenerateMerchantKeySvc(pin string, username string) (dto.ResponseDto, error)
+	GenerateMerchantKeySvc(payload dto.RotateMerchantKeyPayload) (dto.ResponseDto, error)
+	GetLiveMerchantKeysSvc(merchantId string, username string) (dto.ResponseDto, error)
+	RotateMerchantKeysSvc() error
+	ReviewBalanceOperationRequestSvc(payload dto.ReviewBalanceOperationReq) (dto.ResponseDto, error)
+	GetListBalanceOperationRequestSvc(params dto.QueryParamsBalanceOperationRequest) (dto.ResponseDto, error)
+	GetBalanceOperationRequestDetailSvc(requestId int) (dto.ResponseDto, error)
+	GetListBalanceApprovalThresholdSvc() (dto.ResponseDto, error)
+	UpdateBalanceApprovalThresholdSvc(payload dto.UpdateBalanceApprovalThresholdReq) (dto.ResponseDto, error)
 }
 
 type UserServiceItf interface {
 	Login(payload dto.LoginPayload) (dto.ResponseDto, error)
+	RefreshTokenSvc(payload dto.RefreshTokenPayload) (dto.ResponseDto, error)
+	LogoutSvc(sessionId string) (dto.ResponseDto, error)
+	ValidateSessionSvc(sessionId string, username string) error
+	LoginTwoFactorSvc(payload dto.LoginChallengePayload) (dto.ResponseDto, error)
+	SetupTwoFactorLoginSvc(payload dto.LoginChallengePayload) (dto.ResponseDto, error)
+	ActivateTwoFactorLoginSvc(payload dto.LoginChallengePayload) (dto.ResponseDto, error)
+	SetupTwoFactorSvc(username string) (dto.ResponseDto, error)
+	ActivateTwoFactorSvc(payload dto.TwoFactorCodePayload) (dto.ResponseDto, error)
+	DisableTwoFactorSvc(payload dto.TwoFactorCodePayload) (dto.ResponseDto, error)
+	RegenerateRecoveryCodesSvc(payload dto.TwoFactorCodePayload) (dto.ResponseDto, error)
 	GetUserData(username string) (entity.User, error)
 	GetListRolesSvc() (dto.ResponseDto, error)
 	GetListUserMerchantSvc(merchantId string) (dto.ResponseDto, error)
@@ -87,6 +117,19 @@ type UserServiceItf interface {
 	InviteMerchantUserSvc(payload dto.InviteMerchantUserDto) (dto.ResponseDto, error)
 	GetUserInformationsSvc(username string) (dto.ResponseDto, error)
 	UpdatePasswordOrPinSvc(payload dto.UpdatePassOrPinDto) (dto.ResponseDto, error)
+	UnlockUserSvc(payload dto.UnlockUserPayload) (dto.ResponseDto, error)
+	RequestCredentialResetSvc(payload dto.RequestCredentialResetPayload) (dto.ResponseDto, error)
+	ConfirmCredentialResetSvc(payload dto.ConfirmCredentialResetPayload) (dto.ResponseDto, error)
+	GetListInvitationsSvc(payload dto.InvitationPayload) (dto.ResponseDto, error)
+	ResendInvitationSvc(payload dto.InvitationPayload) (dto.ResponseDto, error)
+	RevokeInvitationSvc(payload dto.InvitationPayload) (dto.ResponseDto, error)
+	AcceptInvitationSvc(payload dto.AcceptInvitationPayload) (dto.ResponseDto, error)
+	UpdateMerchantUserStatusSvc(payload dto.MerchantUserPayload) (dto.ResponseDto, error)
+	UpdateMerchantUserRoleSvc(payload dto.MerchantUserPayload) (dto.ResponseDto, error)
+	RemoveMerchantUserSvc(payload dto.MerchantUserPayload) (dto.ResponseDto, error)
+	GetListPermissionsSvc() (dto.ResponseDto, error)
+	CreateRoleSvc(payload dto.CreateRolePayload) (dto.ResponseDto, error)
+	UpdateRolePermissionsSvc(payload dto.UpdateRolePermissionsPayload) (dto.ResponseDto, error)
 }
 
 type ProviderServiceItf interface {
@@ -104,3 +147,21 @@ type ProviderServiceItf interface {
 	GetListPaymentOperatorCreateChannelProviderSvc(providerPaymentMethodId string) (dto.ResponseDto, error)
 	CreateProviderChannelSvc(payload dto.CreateProviderChannelDto) (dto.ResponseDto, error)
 }
+
+type ReconciliationServiceItf interface {
+	RunReconciliationSvc(triggeredBy string) (dto.ResponseDto, error)
+	GetListReconciliationRunSvc() (dto.ResponseDto, error)
+	GetReconciliationRunDetailSvc(runId int) (dto.ResponseDto, error)
+	ResolveReconciliationDiscrepancySvc(payload dto.ResolveReconciliationDiscrepancyReq) (dto.ResponseDto, error)
+}
+
+type AuditServiceItf interface {
+	RecordRequestSvc(entry dto.AuditEntry) error
+	GetListAuditLogSvc(params dto.QueryParamsAuditLog) (dto.ResponseDto, error)
+}
+
+type HashChainServiceItf interface {
+	VerifyHashChainSvc(params dto.VerifyHashChainReq) (dto.ResponseDto, error)
+	CreateChainCheckpointSvc(createdBy string) (dto.ResponseDto, error)
+	GetListChainCheckpointSvc(params dto.QueryParamsChainCheckpoint) (dto.ResponseDto, error)
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+// Auditor keeps the audit trail in histories_operations. An auditor taken from a unit of work records in the
+// same transaction as the change it describes, the change and its trail are committed or rolled back together.
+type Auditor struct {
+	auditRepoWrites internal.AuditWritesRepositoryItf
+}
+
+func NewAuditor(auditRepoWrites internal.AuditWritesRepositoryItf) *Auditor {
+	return &Auditor{
+		auditRepoWrites: auditRepoWrites,
+	}
+}
+
+// withUnitOfWork returns an auditor that writes through repos
+func (a *Auditor) withUnitOfWork(repos internal.UnitOfWorkRepos) *Auditor {
+	return &Auditor{
+		auditRepoWrites: repos.AuditWrites,
+	}
+}
+
+// Record writes entry, sensitive fields are redacted and when both before and after are given the fields
+// that differ are kept as {"field": {"before": ..., "after": ...}}
+func (a *Auditor) Record(entry dto.AuditEntry) error {
+	before, err := auditDocument(entry.Before)
+	if err != nil {
+		return err
+	}
+
+	after, err := auditDocument(entry.After)
+	if err != nil {
+		return err
+	}
+
+	var changes interface{}
+	if before != nil && after != nil {
+		changes = auditChanges(before, after)
+	}
+
+	payload := dto.CreateAuditLogPayload{
+		HistoryType:  entry.HistoryType,
+		Activity:     entry.Activity,
+		TargetId:     entry.TargetId,
+		Username:     entry.Actor.Username,
+		IpAddress:    entry.Actor.IpAddress,
+		RequestId:    entry.Actor.RequestId,
+		ResponseCode: entry.ResponseCode,
+	}
+
+	payload.PayloadBefore, err = marshalAuditDocument(before)
+	if err != nil {
+		return err
+	}
+
+	payload.PayloadAfter, err = marshalAuditDocument(after)
+	if err != nil {
+		return err
+	}
+
+	payload.Changes, err = marshalAuditDocument(changes)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.auditRepoWrites.CreateAuditLogRepo(payload)
+	return err
+}
+
+// RecordCommitted keeps a change that was written outside a unit of work, the change can't be rolled back
+// anymore so a failure is logged instead of returned
+func (a *Auditor) RecordCommitted(entry dto.AuditEntry) {
+	err := a.Record(entry)
+	if err != nil {
+		slog.Errorw(fmt.Sprintf("failed record %v %v of %v", entry.HistoryType, entry.Activity, entry.TargetId), "stack_trace", err.Error())
+	}
+}
+
+// auditDocument turns value into plain json values, maps, slices and scalars, with sensitive fields redacted
+func auditDocument(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var document interface{}
+	err = json.Unmarshal(raw, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	return redactAuditDocument(document), nil
+}
+
+func marshalAuditDocument(document interface{}) ([]byte, error) {
+	if document == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(document)
+}
+
+func redactAuditDocument(document interface{}) interface{} {
+	switch value := document.(type) {
+	case map[string]interface{}:
+		for field, fieldValue := range value {
+			if sensitiveAuditField(field) {
+				value[field] = constant.AuditRedacted
+				continue
+			}
+
+			value[field] = redactAuditDocument(fieldValue)
+		}
+	case []interface{}:
+		for i := range value {
+			value[i] = redactAuditDocument(value[i])
+		}
+	}
+
+	return document
+}
+
+// sensitiveAuditField tells whether a field holds a pin, a password, a secret, a token or a one time code
+func sensitiveAuditField(field string) bool {
+	field = strings.ToLower(field)
+	if field == "code" || field == "otp" || strings.HasSuffix(field, "pin") {
+		return true
+	}
+
+	for _, word := range []string{"password", "secret", "token", "credential"} {
+		if strings.Contains(field, word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// auditChanges compares the top level fields of two objects, documents that aren't objects are compared as a
+// whole under "value"
+func auditChanges(before interface{}, after interface{}) interface{} {
+	beforeFields, beforeIsObject := before.(map[string]interface{})
+	afterFields, afterIsObject := after.(map[string]interface{})
+	if !beforeIsObject || !afterIsObject {
+		beforeFields = map[string]interface{}{"value": before}
+		afterFields = map[string]interface{}{"value": after}
+	}
+
+	changes := map[string]interface{}{}
+	for field, beforeValue := range beforeFields {
+		afterValue := afterFields[field]
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			changes[field] = map[string]interface{}{"before": beforeValue, "after": afterValue}
+		}
+	}
+
+	for field, afterValue := range afterFields {
+		if _, ok := beforeFields[field]; !ok {
+			changes[field] = map[string]interface{}{"before": nil, "after": afterValue}
+		}
+	}
+
+	return changes
+}
+
+type Audit struct {
+	auditRepoReads internal.AuditReadsRepositoryItf
+	auditor        *Auditor
+}
+
+func NewAudit(auditRepoReads internal.AuditReadsRepositoryItf, auditor *Auditor) *Audit {
+	return &Audit{
+		auditRepoReads: auditRepoReads,
+		auditor:        auditor,
+	}
+}
+
+// RecordRequestSvc keeps a mutating dashboard request in the audit trail
+func (au *Audit) RecordRequestSvc(entry dto.AuditEntry) error {
+	entry.HistoryType = constant.HistoryTypeRequest
+	return au.auditor.Record(entry)
+}
+
+func (au *Audit) GetListAuditLogSvc(params dto.QueryParamsAuditLog) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	auditLogs, pagination, err := au.auditRepoReads.GetListAuditLogRepo(params)
+	if err != nil {
+		slog.Errorw("failed get list audit log", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	if len(auditLogs) < 1 {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "Data not found",
+			Data:            auditLogs,
+			Pagination:      pagination,
+		}
+		return resp, nil
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "success retrieve audit log",
+		Data:            auditLogs,
+		Pagination:      pagination,
+	}
+
+	return resp, nil
+}
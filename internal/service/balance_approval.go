@@ -0,0 +1,412 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+// verifyOperatorPin checks the pin of the user entering a balance operation or reviewing one
+func (mr *Merchant) verifyOperatorPin(username string, pin string) (dto.ResponseDto, error) {
+	user, err := mr.userRepoReads.GetUserByUsername(username)
+	if err != nil {
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusBadGateway,
+			ResponseMessage: err.Error(),
+		}, err
+	}
+
+	return mr.credentialGuard.VerifyPin(user, pin)
+}
+
+// requestBalanceOperation applies a manual balance operation right away when its amount is below the
+// threshold of its type, otherwise it's kept as a request another user has to approve. apply runs on a
+// copy of mr bound to the unit of work.
+func (mr *Merchant) requestBalanceOperation(request dto.CreateBalanceOperationRequestPayload, pin string, payload interface{}, actor dto.AuditActor, apply func(mrTx *Merchant) (dto.ResponseDto, error)) (dto.ResponseDto, error) {
+	generalErr := dto.ResponseDto{
+		ResponseCode:    http.StatusUnprocessableEntity,
+		ResponseMessage: constant.GeneralErrMsg,
+	}
+
+	// a negative amount would slip under any threshold
+	if !request.Amount.IsPositive() {
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "amount must be more than 0",
+		}, errors.New("invalid amount")
+	}
+
+	resp, err := mr.verifyOperatorPin(request.RequestedBy, pin)
+	if err != nil {
+		return resp, err
+	}
+
+	// an operation type without a threshold always needs approval
+	threshold, err := mr.balanceApprovalReads.GetBalanceApprovalThresholdRepo(request.OperationType)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		slog.Errorw("failed get balance approval threshold", "stack_trace", err.Error())
+		return generalErr, err
+	}
+
+	if err == nil && request.Amount.Abs().LessThan(threshold.MinAmount) {
+		return runInUnitOfWork(mr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+			return apply(mr.withUnitOfWork(repos))
+		})
+	}
+
+	// the pin is redacted, the approver enters their own
+	document, err := auditDocument(payload)
+	if err != nil {
+		slog.Errorw("failed build balance operation request payload", "stack_trace", err.Error())
+		return generalErr, err
+	}
+
+	request.Payload, err = marshalAuditDocument(document)
+	if err != nil {
+		slog.Errorw("failed build balance operation request payload", "stack_trace", err.Error())
+		return generalErr, err
+	}
+
+	requestId, err := mr.balanceApprovalWrites.CreateBalanceOperationRequestRepo(request)
+	if err != nil {
+		slog.Errorw("failed create balance operation request", "stack_trace", err.Error())
+		return generalErr, err
+	}
+
+	mr.auditor.RecordCommitted(dto.AuditEntry{
+		HistoryType: constant.HistoryTypeBalanceRequest,
+		Activity:    constant.HistoryActivityRequestApproval,
+		TargetId:    strconv.Itoa(requestId),
+		After:       document,
+		Actor:       actor,
+	})
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: fmt.Sprintf("%v request is waiting for approval", request.OperationType),
+		Data: map[string]interface{}{
+			"balanceOperationRequestId": requestId,
+			"status":                    constant.BalanceRequestPendingApproval,
+		},
+	}
+
+	return resp, nil
+}
+
+// applyBalanceOperationRequest runs the operation kept on request for its requester, mr has to come from a
+// unit of work
+func (mr *Merchant) applyBalanceOperationRequest(request entity.BalanceOperationRequest, actor dto.AuditActor) (dto.ResponseDto, error) {
+	var err error
+	generalErr := dto.ResponseDto{
+		ResponseCode:    http.StatusUnprocessableEntity,
+		ResponseMessage: constant.GeneralErrMsg,
+	}
+
+	switch request.OperationType {
+	case constant.BalanceOperationTopUp, constant.BalanceOperationHold, constant.BalanceOperationSettlement, constant.BalanceOperationPayoutSettlement:
+		var payload dto.AdjustBalanceReqPayload
+		err = json.Unmarshal(request.Payload, &payload)
+		if err != nil {
+			break
+		}
+		payload.Username = request.RequestedBy
+		payload.Actor = actor
+
+		switch request.OperationType {
+		case constant.BalanceOperationTopUp:
+			return mr.applyTopUp(payload)
+		case constant.BalanceOperationHold:
+			return mr.applyHold(payload)
+		case constant.BalanceOperationSettlement:
+			return mr.applySettlement(payload)
+		default:
+			return mr.applyPayoutSettlement(payload)
+		}
+	case constant.BalanceOperationTransfer:
+		var payload dto.BalanceTrfReqPayload
+		err = json.Unmarshal(request.Payload, &payload)
+		if err != nil {
+			break
+		}
+		payload.Username = request.RequestedBy
+		payload.Actor = actor
+
+		return mr.applyTransfer(payload)
+	case constant.BalanceOperationReverse:
+		var payload dto.UpdateStatusTransaction
+		err = json.Unmarshal(request.Payload, &payload)
+		if err != nil {
+			break
+		}
+		payload.Actor = actor
+
+		return mr.applyReverse(payload, request.RequestedBy)
+	default:
+		err = fmt.Errorf("unknown balance operation type %v", request.OperationType)
+	}
+
+	slog.Errorw("failed read balance operation request payload", "stack_trace", err.Error())
+	return generalErr, err
+}
+
+// ReviewBalanceOperationRequestSvc approves or rejects a pending request, only an approval changes the
+// balance. A request whose operation fails stays pending.
+func (mr *Merchant) ReviewBalanceOperationRequestSvc(payload dto.ReviewBalanceOperationReq) (dto.ResponseDto, error) {
+	resp, err := mr.verifyOperatorPin(payload.Username, payload.Pin)
+	if err != nil {
+		return resp, err
+	}
+
+	return runInUnitOfWork(mr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		var resp dto.ResponseDto
+		mrTx := mr.withUnitOfWork(repos)
+
+		request, err := mrTx.balanceApprovalWrites.GetBalanceOperationRequestForUpdateRepo(payload.BalanceOperationRequestId)
+		if errors.Is(err, sql.ErrNoRows) {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: "balance operation request not found",
+			}
+			return resp, err
+		}
+		if err != nil {
+			slog.Errorw("failed get balance operation request", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		if request.Status != constant.BalanceRequestPendingApproval {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: fmt.Sprintf("balance operation request is already %v", request.Status),
+			}
+			return resp, errors.New("balance operation request already reviewed")
+		}
+
+		if request.RequestedBy == payload.Username {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusForbidden,
+				ResponseMessage: "a balance operation can't be reviewed by the user who requested it",
+			}
+			return resp, errors.New("balance operation reviewed by its requester")
+		}
+
+		status := constant.BalanceRequestRejected
+		activity := constant.HistoryActivityReject
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "balance operation request rejected",
+		}
+
+		if payload.Approve {
+			status = constant.BalanceRequestApproved
+			activity = constant.HistoryActivityApprove
+			resp, err = mrTx.applyBalanceOperationRequest(request, payload.Actor)
+			if err != nil {
+				return resp, err
+			}
+		}
+
+		err = mrTx.balanceApprovalWrites.ReviewBalanceOperationRequestRepo(request.Id, status, payload.Username, payload.Notes)
+		if err != nil {
+			slog.Errorw("failed review balance operation request", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		err = mrTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeBalanceRequest,
+			Activity:    activity,
+			TargetId:    strconv.Itoa(request.Id),
+			Before:      map[string]interface{}{"status": request.Status},
+			After: map[string]interface{}{
+				"status":      status,
+				"reviewedBy":  payload.Username,
+				"reviewNotes": payload.Notes,
+			},
+			Actor: payload.Actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record balance operation review", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		return resp, nil
+	})
+}
+
+func (mr *Merchant) GetListBalanceOperationRequestSvc(params dto.QueryParamsBalanceOperationRequest) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	requests, pagination, err := mr.balanceApprovalReads.GetListBalanceOperationRequestRepo(params)
+	if err != nil {
+		slog.Errorw("failed get list balance operation request", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	if len(requests) < 1 {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "Data not found",
+			Data:            requests,
+			Pagination:      pagination,
+		}
+		return resp, nil
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "success retrieve balance operation requests",
+		Data:            requests,
+		Pagination:      pagination,
+	}
+
+	return resp, nil
+}
+
+func (mr *Merchant) GetBalanceOperationRequestDetailSvc(requestId int) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	request, err := mr.balanceApprovalReads.GetBalanceOperationRequestByIdRepo(requestId)
+	if errors.Is(err, sql.ErrNoRows) {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "Data not found",
+		}
+		return resp, nil
+	}
+	if err != nil {
+		slog.Errorw("failed get balance operation request", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "success retrieve balance operation request",
+		Data:            request,
+	}
+
+	return resp, nil
+}
+
+func (mr *Merchant) GetListBalanceApprovalThresholdSvc() (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	thresholds, err := mr.balanceApprovalReads.GetListBalanceApprovalThresholdRepo()
+	if err != nil {
+		slog.Errorw("failed get list balance approval threshold", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "success retrieve balance approval thresholds",
+		Data:            thresholds,
+	}
+
+	return resp, nil
+}
+
+// UpdateBalanceApprovalThresholdSvc sets the amount from which an operation type needs approval, zero makes
+// every operation of the type need it
+func (mr *Merchant) UpdateBalanceApprovalThresholdSvc(payload dto.UpdateBalanceApprovalThresholdReq) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	if !payload.MinAmount.IsZero() && !payload.MinAmount.IsPositive() {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "min amount can't be negative",
+		}
+		return resp, errors.New("negative balance approval threshold")
+	}
+
+	return runInUnitOfWork(mr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		mrTx := mr.withUnitOfWork(repos)
+
+		before, err := mrTx.balanceApprovalReads.GetBalanceApprovalThresholdRepo(payload.OperationType)
+		if errors.Is(err, sql.ErrNoRows) {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: "unknown operation type",
+			}
+			return resp, err
+		}
+		if err != nil {
+			slog.Errorw("failed get balance approval threshold", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		_, err = mrTx.balanceApprovalWrites.UpdateBalanceApprovalThresholdRepo(payload)
+		if err != nil {
+			slog.Errorw("failed update balance approval threshold", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		err = mrTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeBalanceRequest,
+			Activity:    constant.HistoryActivityUpdateThreshold,
+			TargetId:    payload.OperationType,
+			Before:      before,
+			After: entity.BalanceApprovalThreshold{
+				OperationType: payload.OperationType,
+				MinAmount:     payload.MinAmount,
+				UpdatedBy:     payload.Username,
+			},
+			Actor: payload.Actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record balance approval threshold", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "success update balance approval threshold",
+		}
+
+		return resp, nil
+	})
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/config"
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+// CredentialGuard counts wrong passwords and pins, holds repeated failures back a little longer every time
+// and locks the user or the address out for a while once they keep failing. Its writes never join a unit of
+// work, a failure is counted even when the operation around it is rolled back. Addresses are the c.RealIP()
+// of the request, forwarding headers only count when they come through one of the trusted proxies.
+type CredentialGuard struct {
+	userRepoWrites internal.UserWritesRepositoryItf
+	cfg            config.App
+	auditor        *Auditor
+}
+
+func NewCredentialGuard(userRepoWrites internal.UserWritesRepositoryItf, cfg config.App, auditor *Auditor) *CredentialGuard {
+	return &CredentialGuard{
+		userRepoWrites: userRepoWrites,
+		cfg:            cfg,
+		auditor:        auditor,
+	}
+}
+
+type authSubject struct {
+	scope   string
+	subject string
+}
+
+// VerifyPin checks the transaction pin of user, pins are only entered by logged in users so they are
+// counted per user
+func (g *CredentialGuard) VerifyPin(user entity.User, pin string) (dto.ResponseDto, error) {
+	subjects := []authSubject{{scope: constant.AuthScopeUser, subject: user.Username}}
+
+	return g.verify(user, constant.AuthCredentialPin, subjects, "wrong pin", func() bool {
+		return comparePasswords(user.Pin, []byte(pin))
+	})
+}
+
+// VerifyPassword checks the login password of user, counted per user and per client address
+func (g *CredentialGuard) VerifyPassword(user entity.User, password string, ipAddress string) (dto.ResponseDto, error) {
+	subjects := []authSubject{{scope: constant.AuthScopeUser, subject: user.Username}}
+	if ipAddress != "" {
+		subjects = append(subjects, authSubject{scope: constant.AuthScopeIp, subject: ipAddress})
+	}
+
+	return g.verify(user, constant.AuthCredentialPassword, subjects, "invalid password", func() bool {
+		return comparePasswords(user.Password, []byte(password))
+	})
+}
+
+// FailUnknownUser counts a login for a username that doesn't exist against the address so usernames can't
+// be probed without limit, it only returns an error while the address is held back
+func (g *CredentialGuard) FailUnknownUser(username string, ipAddress string) (dto.ResponseDto, error) {
+	if ipAddress == "" {
+		return dto.ResponseDto{}, nil
+	}
+
+	subjects := []authSubject{{scope: constant.AuthScopeIp, subject: ipAddress}}
+	resp, err := g.check(constant.AuthCredentialPassword, subjects)
+	if err != nil {
+		return resp, err
+	}
+
+	g.fail(entity.User{Username: username}, constant.AuthCredentialPassword, subjects)
+	return dto.ResponseDto{}, nil
+}
+
+// ThrottleResetRequest counts a reset link request per username and per address whether or not the user
+// exists, a subject past its limit is held back for constant.CredentialResetRequestWindow
+func (g *CredentialGuard) ThrottleResetRequest(username string, ipAddress string) (dto.ResponseDto, error) {
+	subjects := []authSubject{{scope: constant.AuthScopeUser, subject: username}}
+	if ipAddress != "" {
+		subjects = append(subjects, authSubject{scope: constant.AuthScopeIp, subject: ipAddress})
+	}
+
+	resp, err := g.check(constant.AuthCredentialReset, subjects)
+	if err != nil {
+		if resp.ResponseCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+
+		msg := "too many reset requests, please try again later"
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusTooManyRequests,
+			ResponseMessage: msg,
+		}, errors.New(msg)
+	}
+
+	for _, s := range subjects {
+		requestCount, err := g.userRepoWrites.RecordAuthFailureRepo(s.scope, s.subject, constant.AuthCredentialReset, constant.CredentialResetRequestWindow)
+		if err != nil {
+			slog.Errorw("failed record reset request", "stack_trace", err.Error())
+			continue
+		}
+
+		limit := constant.CredentialResetUserLimit
+		if s.scope == constant.AuthScopeIp {
+			limit = constant.CredentialResetIpLimit
+		}
+
+		if requestCount < limit {
+			continue
+		}
+
+		err = g.userRepoWrites.UpdateAuthAttemptDelayRepo(s.scope, s.subject, constant.AuthCredentialReset, 0, constant.CredentialResetRequestWindow)
+		if err != nil {
+			slog.Errorw("failed update reset request delay", "stack_trace", err.Error())
+		}
+	}
+
+	return dto.ResponseDto{}, nil
+}
+
+// Unlock clears the failures and the lockout of a username or an address
+func (g *CredentialGuard) Unlock(scope string, subject string, actor dto.AuditActor) error {
+	err := g.userRepoWrites.DeleteAuthAttemptsRepo(scope, subject)
+	if err != nil {
+		return err
+	}
+
+	g.auditor.RecordCommitted(dto.AuditEntry{
+		HistoryType: constant.HistoryTypeSecurity,
+		Activity:    constant.HistoryActivityUnlock,
+		TargetId:    scope + ":" + subject,
+		After: map[string]string{
+			"scope":   scope,
+			"subject": subject,
+		},
+		Actor: actor,
+	})
+
+	return nil
+}
+
+func (g *CredentialGuard) verify(user entity.User, credential string, subjects []authSubject, wrongMsg string, match func() bool) (dto.ResponseDto, error) {
+	resp, err := g.check(credential, subjects)
+	if err != nil {
+		return resp, err
+	}
+
+	if !match() {
+		g.fail(user, credential, subjects)
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: wrongMsg,
+		}, errors.New(wrongMsg)
+	}
+
+	// the address keeps its count, other users behind it may still be failing
+	err = g.userRepoWrites.ResetAuthAttemptRepo(constant.AuthScopeUser, user.Username, credential)
+	if err != nil {
+		slog.Errorw("failed reset auth attempts", "stack_trace", err.Error())
+	}
+
+	return dto.ResponseDto{}, nil
+}
+
+// check refuses the attempt while any subject is locked out or still waiting out its delay
+func (g *CredentialGuard) check(credential string, subjects []authSubject) (dto.ResponseDto, error) {
+	for _, s := range subjects {
+		attempt, err := g.userRepoWrites.GetAuthAttemptRepo(s.scope, s.subject, credential)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			slog.Errorw("failed get auth attempt", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		var msg string
+		switch {
+		case attempt.LockedSeconds > 0:
+			msg = fmt.Sprintf("too many failed attempts, locked for %v more minutes", (attempt.LockedSeconds+59)/60)
+		case attempt.WaitSeconds > 0:
+			msg = fmt.Sprintf("too many failed attempts, try again in %v seconds", attempt.WaitSeconds)
+		default:
+			continue
+		}
+
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusTooManyRequests,
+			ResponseMessage: msg,
+		}, errors.New(msg)
+	}
+
+	return dto.ResponseDto{}, nil
+}
+
+func (g *CredentialGuard) fail(user entity.User, credential string, subjects []authSubject) {
+	for _, s := range subjects {
+		failedCount, err := g.userRepoWrites.RecordAuthFailureRepo(s.scope, s.subject, credential, constant.AuthAttemptWindow)
+		if err != nil {
+			slog.Errorw("failed record auth failure", "stack_trace", err.Error())
+			continue
+		}
+
+		threshold := constant.AuthUserLockThreshold
+		if s.scope == constant.AuthScopeIp {
+			threshold = constant.AuthIpLockThreshold
+		}
+
+		var lockout time.Duration
+		if failedCount >= threshold {
+			lockout = constant.AuthLockout
+		}
+
+		delay := authDelay(failedCount)
+		if delay == 0 && lockout == 0 {
+			continue
+		}
+
+		err = g.userRepoWrites.UpdateAuthAttemptDelayRepo(s.scope, s.subject, credential, delay, lockout)
+		if err != nil {
+			slog.Errorw("failed update auth attempt delay", "stack_trace", err.Error())
+			continue
+		}
+
+		if lockout > 0 {
+			g.recordLockout(user, credential, s, lockout)
+		}
+	}
+}
+
+// recordLockout keeps the lockout in the operation history and tells the user by email, an address
+// lockout has no single owner to tell
+func (g *CredentialGuard) recordLockout(user entity.User, credential string, s authSubject, lockout time.Duration) {
+	slog.Infof("%v %v locked out for %v after failed %v attempts", s.scope, s.subject, lockout, credential)
+
+	g.auditor.RecordCommitted(dto.AuditEntry{
+		HistoryType: constant.HistoryTypeSecurity,
+		Activity:    constant.HistoryActivityLockout,
+		TargetId:    s.scope + ":" + s.subject,
+		After: map[string]string{
+			"scope":      s.scope,
+			"subject":    s.subject,
+			"credential": credential,
+			"lockout":    lockout.String(),
+		},
+		Actor: dto.AuditActor{Username: user.Username},
+	})
+
+	if s.scope != constant.AuthScopeUser || user.Email == "" {
+		return
+	}
+
+	err := helper.SendEmailLockout(dto.LockoutEmailDataDto{
+		Username:      user.Username,
+		Credential:    strings.ToLower(credential),
+		LockedMinutes: int(lockout.Minutes()),
+	}, user.Email, g.cfg.AppPassMail)
+	if err != nil {
+		slog.Errorw("failed send lockout email", "stack_trace", err.Error())
+	}
+}
+
+// authDelay doubles from one second for every failure past constant.AuthDelayAfter up to constant.AuthMaxDelay
+func authDelay(failedCount int) time.Duration {
+	if failedCount <= constant.AuthDelayAfter {
+		return 0
+	}
+
+	delay := constant.AuthMaxDelay
+	if doublings := failedCount - constant.AuthDelayAfter - 1; doublings < 5 {
+		delay = time.Second << doublings
+	}
+
+	if delay > constant.AuthMaxDelay {
+		return constant.AuthMaxDelay
+	}
+
+	return delay
+}
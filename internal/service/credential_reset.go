@@ -0,0 +1,271 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+var errInvalidResetToken = errors.New("reset link is invalid or expired")
+
+// RequestCredentialResetSvc emails a reset link to the owner of the username. The answer is the same whether
+// or not a link was sent so usernames can't be probed through it, requests are throttled per username and
+// per address so it can't be used to flood a mailbox either.
+func (u *User) RequestCredentialResetSvc(payload dto.RequestCredentialResetPayload) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	if payload.Credential != constant.AuthCredentialPassword && payload.Credential != constant.AuthCredentialPin {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "credential must be PASSWORD or PIN",
+		}
+		return resp, errors.New("invalid credential")
+	}
+
+	resp, err := u.credentialGuard.ThrottleResetRequest(payload.Username, payload.IpAddress)
+	if err != nil {
+		return resp, err
+	}
+
+	sentResp := dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "if the account exists, a reset link has been sent to its email",
+	}
+
+	user, err := u.userRepoReads.GetUserByUsername(payload.Username)
+	if err != nil {
+		slog.Errorw("failed get user", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	if user.UserID < 1 || user.UserStatus != constant.StatusActive || user.Email == "" {
+		slog.Infof("credential reset skipped for username %v", payload.Username)
+		return sentResp, nil
+	}
+
+	resetId, err := generateSecureToken(16)
+	if err != nil {
+		slog.Errorw("failed generate reset id", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	claims := &dto.CredentialResetClaims{
+		Username:   user.Username,
+		Credential: payload.Credential,
+		StandardClaims: jwt.StandardClaims{
+			Id:        resetId,
+			Audience:  constant.CredentialResetAudience,
+			ExpiresAt: time.Now().Add(constant.CredentialResetTTL).Unix(),
+		},
+	}
+
+	resetToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(u.cfg.JWTSecret))
+	if err != nil {
+		slog.Errorw("failed sign reset token", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	// a new link replaces the ones sent before
+	resp, err = runInUnitOfWork(u.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		uTx := u.withUnitOfWork(repos)
+
+		err := uTx.userRepoWrites.ExpireCredentialResetsRepo(user.UserID)
+		if err != nil {
+			slog.Errorw("failed expire credential resets", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		_, err = uTx.userRepoWrites.CreateCredentialResetRepo(user.UserID, hashSecureToken(resetId), payload.Credential, payload.IpAddress, constant.CredentialResetTTL)
+		if err != nil {
+			slog.Errorw("failed create credential reset", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		return sentResp, nil
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	resetLink := fmt.Sprintf("%v%v?token=%v", strings.TrimRight(u.cfg.DashboardUrl, "/"), constant.CredentialResetPath, url.QueryEscape(resetToken))
+	err = helper.SendEmailResetCredential(dto.ResetCredentialEmailDataDto{
+		Username:       user.Username,
+		Credential:     strings.ToLower(payload.Credential),
+		ResetLink:      resetLink,
+		ExpiresMinutes: int(constant.CredentialResetTTL.Minutes()),
+	}, user.Email, u.cfg.AppPassMail)
+	if err != nil {
+		slog.Errorw("failed send reset credential email", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	return sentResp, nil
+}
+
+// ConfirmCredentialResetSvc sets the credential named by the reset token and logs the user out of every
+// device, the token works only once
+func (u *User) ConfirmCredentialResetSvc(payload dto.ConfirmCredentialResetPayload) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+	invalidResp := dto.ResponseDto{
+		ResponseCode:    http.StatusBadRequest,
+		ResponseMessage: errInvalidResetToken.Error(),
+	}
+
+	claims := &dto.CredentialResetClaims{}
+	_, err := jwt.ParseWithClaims(payload.Token, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(u.cfg.JWTSecret), nil
+	})
+	if err != nil || !claims.VerifyAudience(constant.CredentialResetAudience, true) || claims.Id == "" {
+		return invalidResp, errInvalidResetToken
+	}
+
+	user, err := u.userRepoReads.GetUserByUsername(claims.Username)
+	if err != nil {
+		slog.Errorw("failed get user", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	if user.UserID < 1 {
+		return invalidResp, errInvalidResetToken
+	}
+
+	// the credential that isn't reset keeps its hash
+	passwordHash := user.Password
+	pinHash := user.Pin
+
+	switch claims.Credential {
+	case constant.AuthCredentialPassword:
+		if payload.Password == nil || !helper.IsValidPassword(*payload.Password) {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: "password have to 8 character, at least one special character, and one uppercase letter",
+			}
+			return resp, errors.New("invalid password")
+		}
+
+		passwordHash, err = helper.HashString([]byte(*payload.Password))
+	case constant.AuthCredentialPin:
+		if payload.Pin == nil || !helper.IsValidPIN(*payload.Pin) {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: "pin only 6 numeric allowed",
+			}
+			return resp, errors.New("invalid pin")
+		}
+
+		pinHash, err = helper.HashString([]byte(*payload.Pin))
+	default:
+		return invalidResp, errInvalidResetToken
+	}
+	if err != nil {
+		slog.Errorw("failed hash credential", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	resp, err = runInUnitOfWork(u.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		uTx := u.withUnitOfWork(repos)
+
+		reset, err := uTx.userRepoWrites.UseCredentialResetRepo(hashSecureToken(claims.Id))
+		if err == sql.ErrNoRows {
+			return invalidResp, errInvalidResetToken
+		}
+		if err != nil {
+			slog.Errorw("failed use credential reset", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		if reset.UserId != user.UserID || reset.Credential != claims.Credential {
+			return invalidResp, errInvalidResetToken
+		}
+
+		err = uTx.userRepoWrites.UpdatePassOrPinRepo(passwordHash, pinHash, user.Username)
+		if err != nil {
+			slog.Errorw("failed update password or pin", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		err = uTx.userRepoWrites.RevokeUserSessionsRepo(user.UserID)
+		if err != nil {
+			slog.Errorw("failed revoke user sessions", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		err = uTx.userRepoWrites.ExpireCredentialResetsRepo(user.UserID)
+		if err != nil {
+			slog.Errorw("failed expire credential resets", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: fmt.Sprintf("successfully reset %v", strings.ToLower(claims.Credential)),
+		}, nil
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	// the new credential starts without the failures of the forgotten one
+	err = u.userRepoWrites.ResetAuthAttemptRepo(constant.AuthScopeUser, user.Username, claims.Credential)
+	if err != nil {
+		slog.Errorw("failed reset auth attempts", "stack_trace", err.Error())
+	}
+
+	return resp, nil
+}
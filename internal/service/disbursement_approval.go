@@ -0,0 +1,552 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+// merchantOfUser returns the merchant of a merchant dashboard user
+func (tr *Transaction) merchantOfUser(username string) (string, dto.ResponseDto, error) {
+	user, err := tr.userRepoReads.GetUserByUsername(username)
+	if err != nil {
+		slog.Infof("username: %v, failed get user data, err: %v", username, err.Error())
+		return "", dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	if user.MerchantID == nil {
+		return "", dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "user doesn't belong to a merchant",
+		}, errors.New("user without merchant")
+	}
+
+	return *user.MerchantID, dto.ResponseDto{}, nil
+}
+
+// requestDisbursementApproval keeps the disbursement until as many users as rule asks for approved it, the
+// balance is neither checked nor reserved until then
+func (tr *Transaction) requestDisbursementApproval(merchantId string, rule entity.DisbursementApprovalRule, payload dto.MerchantDisbursement) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	requestId, err := tr.createDisbursementRequest(merchantId, rule, payload)
+	if err != nil {
+		slog.Errorw("failed create disbursement request", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: fmt.Sprintf("disbursement is waiting for %v approval", rule.RequiredApprovals),
+		Data: map[string]interface{}{
+			"disbursementRequestId": requestId,
+			"status":                constant.DisbursementRequestPendingApproval,
+			"requiredApprovals":     rule.RequiredApprovals,
+		},
+	}
+
+	return resp, nil
+}
+
+func (tr *Transaction) createDisbursementRequest(merchantId string, rule entity.DisbursementApprovalRule, payload dto.MerchantDisbursement) (int, error) {
+	request := dto.CreateDisbursementRequestPayload{
+		MerchantId:        merchantId,
+		Amount:            payload.Amount,
+		BankName:          payload.BankName,
+		BankAccountName:   payload.BankAccountName,
+		BankAccountNumber: payload.BankAccountNumber,
+		Note:              payload.Note,
+		RequiredApprovals: rule.RequiredApprovals,
+		RequestedBy:       payload.Username,
+	}
+
+	requestId, err := tr.disbursementApprovalWrites.CreateDisbursementRequestRepo(request)
+	if err != nil {
+		return requestId, err
+	}
+
+	tr.auditor.RecordCommitted(dto.AuditEntry{
+		HistoryType: constant.HistoryTypeDisbursementRequest,
+		Activity:    constant.HistoryActivityRequestApproval,
+		TargetId:    strconv.Itoa(requestId),
+		After:       request,
+		Actor:       payload.Actor,
+	})
+
+	return requestId, nil
+}
+
+// ReviewDisbursementRequestSvc keeps the review of a user of the merchant, a rejection rejects the request and
+// the approval reaching the approvals it needs sends it to the provider
+func (tr *Transaction) ReviewDisbursementRequestSvc(payload dto.ReviewDisbursementRequestReq) (dto.ResponseDto, error) {
+	user, err := tr.userRepoReads.GetUserByUsername(payload.Username)
+	if err != nil {
+		slog.Infof("username: %v, failed get user data, err: %v", payload.Username, err.Error())
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	// check input pin
+	resp, err := tr.credentialGuard.VerifyPin(user, payload.Pin)
+	if err != nil {
+		return resp, err
+	}
+
+	var request entity.DisbursementRequest
+	var execute bool
+	resp, err = runInUnitOfWork(tr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		var resp dto.ResponseDto
+		var err error
+		trTx := tr.withUnitOfWork(repos)
+
+		request, err = trTx.disbursementApprovalWrites.GetDisbursementRequestForUpdateRepo(payload.DisbursementRequestId)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			slog.Errorw("failed get disbursement request", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		// a request of another merchant is as unknown as a missing one
+		if errors.Is(err, sql.ErrNoRows) || user.MerchantID == nil || request.MerchantId != *user.MerchantID {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: "disbursement request not found",
+			}
+			return resp, errors.New("disbursement request not found")
+		}
+
+		if request.Status != constant.DisbursementRequestPendingApproval {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: fmt.Sprintf("disbursement request is already %v", request.Status),
+			}
+			return resp, errors.New("disbursement request already reviewed")
+		}
+
+		if request.RequestedBy == payload.Username {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusForbidden,
+				ResponseMessage: "a disbursement can't be reviewed by the user who requested it",
+			}
+			return resp, errors.New("disbursement reviewed by its requester")
+		}
+
+		reviewed, err := trTx.disbursementApprovalWrites.HasReviewedDisbursementRequestRepo(request.Id, payload.Username)
+		if err != nil {
+			slog.Errorw("failed check disbursement request review", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		if reviewed {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: "disbursement request is already reviewed by this user",
+			}
+			return resp, errors.New("disbursement request reviewed twice")
+		}
+
+		decision := constant.DisbursementReviewRejected
+		activity := constant.HistoryActivityReject
+		if payload.Approve {
+			decision = constant.DisbursementReviewApproved
+			activity = constant.HistoryActivityApprove
+		}
+
+		err = trTx.disbursementApprovalWrites.CreateDisbursementRequestReviewRepo(request.Id, payload.Username, decision, payload.Notes)
+		if err != nil {
+			slog.Errorw("failed create disbursement request review", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		approvals, err := trTx.disbursementApprovalWrites.CountDisbursementRequestApprovalsRepo(request.Id)
+		if err != nil {
+			slog.Errorw("failed count disbursement request approvals", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		status := request.Status
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: fmt.Sprintf("disbursement request approved, waiting for %v more approval", request.RequiredApprovals-approvals),
+		}
+
+		switch {
+		case !payload.Approve:
+			status = constant.DisbursementRequestRejected
+			resp.ResponseMessage = "disbursement request rejected"
+		case approvals >= request.RequiredApprovals:
+			// processing keeps any later review away while the provider is called
+			status = constant.DisbursementRequestProcessing
+			execute = true
+		}
+
+		if status != request.Status {
+			err = trTx.disbursementApprovalWrites.UpdateDisbursementRequestStatusRepo(request.Id, status, "", "")
+			if err != nil {
+				slog.Errorw("failed update disbursement request status", "stack_trace", err.Error())
+				resp = dto.ResponseDto{
+					ResponseCode:    http.StatusUnprocessableEntity,
+					ResponseMessage: constant.GeneralErrMsg,
+				}
+				return resp, err
+			}
+		}
+
+		err = trTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeDisbursementRequest,
+			Activity:    activity,
+			TargetId:    strconv.Itoa(request.Id),
+			Before:      map[string]interface{}{"status": request.Status},
+			After: map[string]interface{}{
+				"status":     status,
+				"approvals":  approvals,
+				"reviewedBy": payload.Username,
+				"notes":      payload.Notes,
+			},
+			Actor: payload.Actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record disbursement request review", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		return resp, nil
+	})
+	if err != nil || !execute {
+		return resp, err
+	}
+
+	return tr.executeDisbursementRequest(request, payload.Actor)
+}
+
+// executeDisbursementRequest sends an approved request to the provider outside of the review transaction. The
+// balance may have been spent since the request was made, disburse checks it again under the merchant account
+// lock when it reserves the amount and fee, and the request fails with not enough balance for disbursement. A
+// request whose outcome can't be written stays processing until FailStaleDisbursementRequestsSvc fails it.
+func (tr *Transaction) executeDisbursementRequest(request entity.DisbursementRequest, actor dto.AuditActor) (dto.ResponseDto, error) {
+	disbursement := dto.MerchantDisbursement{
+		Amount:            request.Amount,
+		BankName:          request.BankName,
+		BankAccountName:   request.BankAccountName,
+		BankAccountNumber: request.BankAccountNumber,
+		Note:              nullSafeString(request.Note),
+		Username:          request.RequestedBy,
+		Actor:             actor,
+	}
+
+	var paymentId, failureReason string
+	status := constant.DisbursementRequestDisbursed
+	resp, err := tr.disburse(request.MerchantId, disbursement)
+	if err != nil {
+		status = constant.DisbursementRequestFailed
+		failureReason = resp.ResponseMessage
+	}
+	if result, ok := resp.Data.(dto.MerchantDisbursementRespDto); ok {
+		paymentId = result.PaymentId
+	}
+
+	_, errUpdate := runInUnitOfWork(tr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		trTx := tr.withUnitOfWork(repos)
+
+		err := trTx.disbursementApprovalWrites.UpdateDisbursementRequestStatusRepo(request.Id, status, paymentId, failureReason)
+		if err != nil {
+			return dto.ResponseDto{}, err
+		}
+
+		err = trTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeDisbursementRequest,
+			Activity:    constant.HistoryActivityDisburse,
+			TargetId:    strconv.Itoa(request.Id),
+			Before:      map[string]interface{}{"status": constant.DisbursementRequestProcessing},
+			After: map[string]interface{}{
+				"status":        status,
+				"paymentId":     paymentId,
+				"failureReason": failureReason,
+			},
+			Actor: actor,
+		})
+		if err != nil {
+			return dto.ResponseDto{}, err
+		}
+
+		return dto.ResponseDto{}, nil
+	})
+	if errUpdate != nil {
+		slog.Errorw(fmt.Sprintf("failed update disbursement request %v to %v", request.Id, status), "stack_trace", errUpdate.Error())
+		// the provider may have taken the disbursement, the payment id is kept so it can be looked up
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+			Data:            resp.Data,
+		}, errUpdate
+	}
+
+	return resp, err
+}
+
+// FailStaleDisbursementRequestsSvc fails the requests left processing by an instance that stopped or couldn't
+// write how the disbursement ended. The provider may have taken them, so they are never disbursed again on their own.
+func (tr *Transaction) FailStaleDisbursementRequestsSvc() error {
+	requests, err := tr.disbursementApprovalWrites.FailStaleDisbursementRequestsRepo(constant.DisbursementRequestStaleAfter, constant.DisbursementRequestInterruptedMsg)
+	if err != nil {
+		return err
+	}
+
+	for _, request := range requests {
+		slog.Infof("disbursement request %d was left processing, failed it", request.Id)
+		tr.auditor.RecordCommitted(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeDisbursementRequest,
+			Activity:    constant.HistoryActivityFail,
+			TargetId:    strconv.Itoa(request.Id),
+			Before:      map[string]interface{}{"status": constant.DisbursementRequestProcessing},
+			After: map[string]interface{}{
+				"status":        request.Status,
+				"failureReason": constant.DisbursementRequestInterruptedMsg,
+			},
+			Actor: dto.AuditActor{Username: constant.CreateBySystem},
+		})
+	}
+
+	return nil
+}
+
+func (tr *Transaction) GetListDisbursementRequestSvc(params dto.QueryParamsDisbursementRequest) (dto.ResponseDto, error) {
+	merchantId, resp, err := tr.merchantOfUser(params.Username)
+	if err != nil {
+		return resp, err
+	}
+
+	params.MerchantId = merchantId
+	requests, pagination, err := tr.disbursementApprovalReads.GetListDisbursementRequestRepo(params)
+	if err != nil {
+		slog.Errorw("failed get list disbursement request", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	if len(requests) < 1 {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "Data not found",
+			Data:            requests,
+			Pagination:      pagination,
+		}
+		return resp, nil
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "success retrieve disbursement requests",
+		Data:            requests,
+		Pagination:      pagination,
+	}
+
+	return resp, nil
+}
+
+func (tr *Transaction) GetDisbursementRequestDetailSvc(requestId int, username string) (dto.ResponseDto, error) {
+	merchantId, resp, err := tr.merchantOfUser(username)
+	if err != nil {
+		return resp, err
+	}
+
+	request, err := tr.disbursementApprovalReads.GetDisbursementRequestByIdRepo(requestId, merchantId)
+	if errors.Is(err, sql.ErrNoRows) {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "Data not found",
+		}
+		return resp, nil
+	}
+	if err != nil {
+		slog.Errorw("failed get disbursement request", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	reviews, err := tr.disbursementApprovalReads.GetListDisbursementRequestReviewRepo(request.Id)
+	if err != nil {
+		slog.Errorw("failed get disbursement request reviews", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "success retrieve disbursement request",
+		Data: dto.DisbursementRequestDetailDto{
+			DisbursementRequest: request,
+			Reviews:             reviews,
+		},
+	}
+
+	return resp, nil
+}
+
+func (tr *Transaction) GetListDisbursementApprovalRuleSvc(username string) (dto.ResponseDto, error) {
+	merchantId, resp, err := tr.merchantOfUser(username)
+	if err != nil {
+		return resp, err
+	}
+
+	rules, err := tr.disbursementApprovalReads.GetListDisbursementApprovalRuleRepo(merchantId)
+	if err != nil {
+		slog.Errorw("failed get list disbursement approval rule", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "success retrieve disbursement approval rules",
+		Data:            rules,
+	}
+
+	return resp, nil
+}
+
+// UpdateDisbursementApprovalRulesSvc replaces the rules of the merchant of the user, no rules lets every
+// disbursement through right away. Requests already waiting keep the approvals they were created with.
+func (tr *Transaction) UpdateDisbursementApprovalRulesSvc(payload dto.UpdateDisbursementApprovalRulesReq) (dto.ResponseDto, error) {
+	merchantId, resp, err := tr.merchantOfUser(payload.Username)
+	if err != nil {
+		return resp, err
+	}
+
+	minAmounts := map[int64]bool{}
+	for _, rule := range payload.Rules {
+		if rule.MinAmount.IsNegative() {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: "min amount can't be negative",
+			}
+			return resp, errors.New("negative disbursement approval rule")
+		}
+
+		if rule.RequiredApprovals < 1 || rule.RequiredApprovals > constant.DisbursementMaxApprovers {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: fmt.Sprintf("required approvals must be between 1 and %v", constant.DisbursementMaxApprovers),
+			}
+			return resp, errors.New("invalid required approvals")
+		}
+
+		if minAmounts[rule.MinAmount.Minor()] {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: "every rule needs its own min amount",
+			}
+			return resp, errors.New("duplicate disbursement approval rule")
+		}
+		minAmounts[rule.MinAmount.Minor()] = true
+	}
+
+	rulesBefore, err := tr.disbursementApprovalReads.GetListDisbursementApprovalRuleRepo(merchantId)
+	if err != nil {
+		slog.Errorw("failed get list disbursement approval rule", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	return runInUnitOfWork(tr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		var resp dto.ResponseDto
+		trTx := tr.withUnitOfWork(repos)
+
+		err := trTx.disbursementApprovalWrites.DeleteDisbursementApprovalRulesRepo(merchantId)
+		if err != nil {
+			slog.Errorw("failed delete disbursement approval rules", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		for _, rule := range payload.Rules {
+			err = trTx.disbursementApprovalWrites.CreateDisbursementApprovalRuleRepo(merchantId, rule, payload.Username)
+			if err != nil {
+				slog.Errorw("failed create disbursement approval rule", "stack_trace", err.Error())
+				resp = dto.ResponseDto{
+					ResponseCode:    http.StatusUnprocessableEntity,
+					ResponseMessage: constant.GeneralErrMsg,
+				}
+				return resp, err
+			}
+		}
+
+		err = trTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeMerchant,
+			Activity:    constant.HistoryActivityUpdateApprovalRules,
+			TargetId:    merchantId,
+			Before:      map[string]interface{}{"rules": rulesBefore},
+			After:       map[string]interface{}{"rules": payload.Rules},
+			Actor:       payload.Actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record disbursement approval rules", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "success update disbursement approval rules",
+		}
+
+		return resp, nil
+	})
+}
@@ -0,0 +1,889 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+// disbursementBatchRouting is what the rows of a batch are validated against, the same routing disburse uses
+type disbursementBatchRouting struct {
+	merchantChannel entity.MerchantPaychannel
+	routedChannel   entity.RoutedPaychanneDto
+	banks           map[string]entity.BankListDto
+	providerBanks   map[string]bool
+}
+
+// UploadDisbursementBatchSvc validates every row of the file against the disbursement routing of the merchant and
+// keeps the batch until it is confirmed, invalid rows are kept with their reason and never disbursed
+func (tr *Transaction) UploadDisbursementBatchSvc(payload dto.UploadDisbursementBatchReq) (dto.ResponseDto, error) {
+	merchantId, resp, err := tr.merchantOfUser(payload.Username)
+	if err != nil {
+		return resp, err
+	}
+
+	fileRows, err := helper.ReadSpreadsheetRows(payload.FileName, payload.Content)
+	if err != nil {
+		slog.Infof("username: %v, failed read disbursement batch file %v, err: %v", payload.Username, payload.FileName, err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "file can't be read, only xlsx and csv files are supported",
+		}
+		return resp, err
+	}
+
+	if len(fileRows) == 0 || !isDisbursementBatchHeader(fileRows[0]) {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: fmt.Sprintf("first row of the file must be the header: %v", strings.Join(constant.DisbursementBatchHeaders, ", ")),
+		}
+		return resp, errors.New("invalid disbursement batch header")
+	}
+
+	// the first row is the header, blank rows are left out but keep the numbering of the file
+	var rows []dto.DisbursementBatchRowDto
+	var amounts []string
+	for i, cells := range fileRows {
+		if i == 0 || isBlankRow(cells) {
+			continue
+		}
+
+		rows = append(rows, dto.DisbursementBatchRowDto{
+			RowNumber:         i + 1,
+			BankCode:          spreadsheetCell(cells, 0),
+			BankAccountNumber: spreadsheetCell(cells, 1),
+			BankAccountName:   spreadsheetCell(cells, 2),
+			Note:              spreadsheetCell(cells, 4),
+		})
+		amounts = append(amounts, spreadsheetCell(cells, 3))
+	}
+
+	if len(rows) == 0 {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "file has no disbursement rows",
+		}
+		return resp, errors.New("empty disbursement batch")
+	}
+
+	if len(rows) > constant.DisbursementBatchMaxRows {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: fmt.Sprintf("a batch can't have more than %v rows", constant.DisbursementBatchMaxRows),
+		}
+		return resp, errors.New("disbursement batch too large")
+	}
+
+	routing, resp, err := tr.getDisbursementBatchRouting(merchantId, payload.Username)
+	if err != nil {
+		return resp, err
+	}
+
+	batch := dto.CreateDisbursementBatchPayload{
+		MerchantId: merchantId,
+		FileName:   payload.FileName,
+		TotalRows:  len(rows),
+		UploadedBy: payload.Username,
+	}
+
+	seen := map[string]int{}
+	for i := range rows {
+		err = tr.loadDisbursementBatchProviderBank(routing, rows[i].BankCode)
+		if err != nil {
+			slog.Errorw("failed validate disbursement batch row", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		validateDisbursementBatchRow(&rows[i], amounts[i], routing, seen)
+
+		if rows[i].Status == constant.DisbursementBatchRowValid {
+			batch.ValidRows++
+			batch.TotalAmount = batch.TotalAmount.Add(*rows[i].Amount)
+			batch.TotalFee = batch.TotalFee.Add(rows[i].Fee)
+		}
+	}
+
+	var batchId int
+	resp, err = runInUnitOfWork(tr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		var resp dto.ResponseDto
+		var err error
+		trTx := tr.withUnitOfWork(repos)
+
+		batchId, err = trTx.disbursementBatchWrites.CreateDisbursementBatchRepo(batch)
+		if err != nil {
+			slog.Errorw("failed create disbursement batch", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		for _, row := range rows {
+			err = trTx.disbursementBatchWrites.CreateDisbursementBatchRowRepo(batchId, row)
+			if err != nil {
+				slog.Errorw("failed create disbursement batch row", "stack_trace", err.Error())
+				resp = dto.ResponseDto{
+					ResponseCode:    http.StatusUnprocessableEntity,
+					ResponseMessage: constant.GeneralErrMsg,
+				}
+				return resp, err
+			}
+		}
+
+		err = trTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeDisbursementBatch,
+			Activity:    constant.HistoryActivityUpload,
+			TargetId:    strconv.Itoa(batchId),
+			After:       batch,
+			Actor:       payload.Actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record disbursement batch upload", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		return resp, nil
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: fmt.Sprintf("success upload disbursement batch, %v of %v rows are valid", batch.ValidRows, batch.TotalRows),
+		Data: dto.DisbursementBatchUploadRespDto{
+			DisbursementBatchId: batchId,
+			TotalRows:           batch.TotalRows,
+			ValidRows:           batch.ValidRows,
+			InvalidRows:         batch.TotalRows - batch.ValidRows,
+			TotalAmount:         batch.TotalAmount,
+			TotalFee:            batch.TotalFee,
+			GrandTotal:          batch.TotalAmount.Add(batch.TotalFee),
+			Rows:                rows,
+		},
+	}
+
+	return resp, nil
+}
+
+// getDisbursementBatchRouting loads the disbursement channel of the merchant, the channel it is routed to and the
+// banks that channel supports
+func (tr *Transaction) getDisbursementBatchRouting(merchantId string, username string) (disbursementBatchRouting, dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+	routing := disbursementBatchRouting{
+		banks:         map[string]entity.BankListDto{},
+		providerBanks: map[string]bool{},
+	}
+
+	listMerchantPaychannel, err := tr.merchantRepoReads.GetMerchantPaychannelByMerchantId(merchantId)
+	if err != nil {
+		slog.Infof("username: %v, failed get merchant channel, err: %v", username, err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return routing, resp, err
+	}
+
+	for _, channel := range listMerchantPaychannel {
+		if channel.PaymentMethodChannel == constant.DisbursementPaymentMethod && channel.Segment == constant.MainType {
+			routing.merchantChannel = channel
+		}
+	}
+
+	if routing.merchantChannel.Id == 0 {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "this merchant not routed for disbursement",
+		}
+		return routing, resp, errors.New("insufficient")
+	}
+
+	getRoutedChannel, err := tr.merchantRepoReads.GetListRoutedPaychannelByIdMerchantPaychannelRepo(routing.merchantChannel.Id)
+	if err != nil {
+		slog.Infof("username: %v, getRoutedChannel got err: %v", username, err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return routing, resp, err
+	}
+
+	if len(getRoutedChannel) == 0 {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "this merchant not routed for disbursement",
+		}
+		return routing, resp, errors.New("insufficient")
+	}
+	routing.routedChannel = getRoutedChannel[0]
+
+	getBankList, err := tr.merchantRepoReads.GetBankListForDisbursementRepo(fmt.Sprintf("[%v]", routing.routedChannel.ProviderPaychannelName))
+	if err != nil {
+		slog.Infof("username: %v, getBankList got err: %v", username, err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return routing, resp, err
+	}
+
+	for _, bank := range getBankList {
+		routing.banks[strings.ToUpper(bank.BankCode)] = bank
+	}
+
+	return routing, resp, nil
+}
+
+// loadDisbursementBatchProviderBank looks up once whether the provider of the routed channel supports the bank of
+// bankCode, a bank code the routing doesn't know is left to validateDisbursementBatchRow
+func (tr *Transaction) loadDisbursementBatchProviderBank(routing disbursementBatchRouting, bankCode string) error {
+	bank, ok := routing.banks[strings.ToUpper(bankCode)]
+	if !ok {
+		return nil
+	}
+
+	if _, ok = routing.providerBanks[bank.BankCode]; ok {
+		return nil
+	}
+
+	_, err := tr.providerRepoReads.GetProviderBankCodeRepo(routing.routedChannel.ProviderId, bank.BankCode)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	routing.providerBanks[bank.BankCode] = err == nil
+
+	return nil
+}
+
+// validateDisbursementBatchRow sets the status of row, a row that doesn't pass is invalid with its reason. seen
+// keeps the row number of every valid disbursement so the same one isn't paid twice, the provider banks of the
+// row must be loaded with loadDisbursementBatchProviderBank first.
+func validateDisbursementBatchRow(row *dto.DisbursementBatchRowDto, amountCell string, routing disbursementBatchRouting, seen map[string]int) {
+	row.Status = constant.DisbursementBatchRowInvalid
+
+	for _, cell := range []string{row.BankCode, row.BankAccountNumber, row.BankAccountName, row.Note} {
+		if len(cell) > 255 {
+			row.ErrorMessage = "cell is longer than 255 characters"
+			return
+		}
+	}
+
+	bank, ok := routing.banks[strings.ToUpper(row.BankCode)]
+	if !ok {
+		row.ErrorMessage = "bank code not supported for disbursement"
+		return
+	}
+	row.BankCode = bank.BankCode
+	row.BankName = bank.BankName
+
+	if !routing.providerBanks[bank.BankCode] {
+		row.ErrorMessage = "bank not supported for disbursement"
+		return
+	}
+
+	if row.BankAccountNumber == "" || strings.Trim(row.BankAccountNumber, "0123456789") != "" {
+		row.ErrorMessage = "bank account number must only contain digits"
+		return
+	}
+
+	if row.BankAccountName == "" {
+		row.ErrorMessage = "bank account name is mandatory"
+		return
+	}
+
+	amount, err := money.Parse(amountCell)
+	if err != nil {
+		row.ErrorMessage = "amount is not a number"
+		return
+	}
+	row.Amount = &amount
+
+	if !amount.IsPositive() {
+		row.ErrorMessage = "amount must be more than 0"
+		return
+	}
+
+	for _, limit := range [][2]money.Money{
+		{routing.merchantChannel.MinTransaction, routing.merchantChannel.MaxTransaction},
+		{routing.routedChannel.MinTransaction, routing.routedChannel.MaxTransaction},
+	} {
+		if !limit[0].IsPositive() && !limit[1].IsPositive() {
+			continue
+		}
+
+		if amount.LessThan(limit[0]) || amount.GreaterThan(limit[1]) {
+			row.ErrorMessage = fmt.Sprintf("amount must be between %v and %v", limit[0], limit[1])
+			return
+		}
+	}
+
+	key := strings.Join([]string{row.BankCode, row.BankAccountNumber, amount.String()}, "|")
+	if rowNumber, ok := seen[key]; ok {
+		row.ErrorMessage = fmt.Sprintf("same disbursement as row %v", rowNumber)
+		return
+	}
+	seen[key] = row.RowNumber
+
+	row.Fee = routing.merchantChannel.Fee
+	row.Status = constant.DisbursementBatchRowValid
+}
+
+// ConfirmDisbursementBatchSvc starts disbursing the valid rows of an uploaded batch in the background, the
+// batch is only confirmed once and only while the settled balance covers all of its valid rows
+func (tr *Transaction) ConfirmDisbursementBatchSvc(payload dto.ConfirmDisbursementBatchReq) (dto.ResponseDto, error) {
+	user, err := tr.userRepoReads.GetUserByUsername(payload.Username)
+	if err != nil {
+		slog.Infof("username: %v, failed get user data, err: %v", payload.Username, err.Error())
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	// check input pin
+	resp, err := tr.credentialGuard.VerifyPin(user, payload.Pin)
+	if err != nil {
+		return resp, err
+	}
+
+	var batch entity.DisbursementBatch
+	resp, err = runInUnitOfWork(tr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		var resp dto.ResponseDto
+		var err error
+		trTx := tr.withUnitOfWork(repos)
+
+		batch, err = trTx.disbursementBatchWrites.GetDisbursementBatchForUpdateRepo(payload.DisbursementBatchId)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			slog.Errorw("failed get disbursement batch", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		// a batch of another merchant is as unknown as a missing one
+		if errors.Is(err, sql.ErrNoRows) || user.MerchantID == nil || batch.MerchantId != *user.MerchantID {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: "disbursement batch not found",
+			}
+			return resp, errors.New("disbursement batch not found")
+		}
+
+		if batch.Status != constant.DisbursementBatchUploaded {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: fmt.Sprintf("disbursement batch is already %v", batch.Status),
+			}
+			return resp, errors.New("disbursement batch already confirmed")
+		}
+
+		if batch.ValidRows == 0 {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: "disbursement batch has no valid rows",
+			}
+			return resp, errors.New("insufficient")
+		}
+
+		// the account is locked so a disbursement reserving its balance right now is counted, every row still
+		// reserves its own amount and fee under the same lock and fails on its own when the balance ran out since
+		accountBalance, err := trTx.merchantRepoWrites.GetMerchantAccountForUpdateRepo(batch.MerchantId)
+		if err != nil {
+			slog.Infof("username: %v, failed get account balance, err: %v", payload.Username, err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		accountBalance, err = ledgerMerchantAccount(trTx.ledgerRepoWrites, accountBalance)
+		if err != nil {
+			slog.Infof("username: %v, failed get ledger balance, err: %v", payload.Username, err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		// rows run side by side, so the balance is checked for the whole batch before any of them starts
+		if batch.TotalAmount.Add(batch.TotalFee).GreaterThan(accountBalance.SettledBalance) {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: "not enough balance for disbursement batch",
+			}
+			return resp, errors.New("insufficient")
+		}
+
+		err = trTx.disbursementBatchWrites.ConfirmDisbursementBatchRepo(batch.Id, payload.Username)
+		if err != nil {
+			slog.Errorw("failed confirm disbursement batch", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		err = trTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeDisbursementBatch,
+			Activity:    constant.HistoryActivityConfirm,
+			TargetId:    strconv.Itoa(batch.Id),
+			Before:      map[string]interface{}{"status": batch.Status},
+			After: map[string]interface{}{
+				"status":      constant.DisbursementBatchProcessing,
+				"validRows":   batch.ValidRows,
+				"totalAmount": batch.TotalAmount,
+				"totalFee":    batch.TotalFee,
+			},
+			Actor: payload.Actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record disbursement batch confirmation", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		return resp, nil
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	go tr.processDisbursementBatch(batch, payload.Username, payload.Actor)
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: fmt.Sprintf("disbursement batch is processing %v rows", batch.ValidRows),
+		Data: map[string]interface{}{
+			"disbursementBatchId": batch.Id,
+			"status":              constant.DisbursementBatchProcessing,
+		},
+	}
+
+	return resp, nil
+}
+
+// processDisbursementBatch disburses the valid rows of batch with at most DisbursementBatchConcurrency of them
+// at the provider at the same time, every row is the disbursement of the user who confirmed the batch. A row is
+// claimed before it is disbursed, so a batch resumed by ResumeDisbursementBatchesSvc only takes the rows still valid.
+func (tr *Transaction) processDisbursementBatch(batch entity.DisbursementBatch, username string, actor dto.AuditActor) {
+	defer func() {
+		if p := recover(); p != nil {
+			tr.failDisbursementBatch(batch, actor, p)
+		}
+	}()
+
+	rows, err := tr.disbursementBatchReads.GetListDisbursementBatchRowRepo(batch.Id, constant.DisbursementBatchRowValid)
+	if err != nil {
+		// the batch is left processing and resumed once it is stale
+		slog.Errorw(fmt.Sprintf("failed get rows of disbursement batch %v", batch.Id), "stack_trace", err.Error())
+		return
+	}
+
+	limit := make(chan struct{}, constant.DisbursementBatchConcurrency)
+	var wg sync.WaitGroup
+	for _, row := range rows {
+		wg.Add(1)
+		limit <- struct{}{}
+		go func(row entity.DisbursementBatchRow) {
+			defer wg.Done()
+			defer func() { <-limit }()
+			defer func() {
+				if p := recover(); p != nil {
+					tr.interruptDisbursementBatchRow(row, p)
+				}
+			}()
+
+			tr.processDisbursementBatchRow(batch.MerchantId, row, username, actor)
+		}(row)
+	}
+	wg.Wait()
+
+	tr.completeDisbursementBatch(batch, actor)
+}
+
+// completeDisbursementBatch completes batch once every row has its outcome, the summary is taken from the rows
+// stored so rows disbursed before the batch was resumed are counted too
+func (tr *Transaction) completeDisbursementBatch(batch entity.DisbursementBatch, actor dto.AuditActor) {
+	completed, err := tr.disbursementBatchWrites.CompleteDisbursementBatchRepo(batch.Id)
+	if err != nil {
+		slog.Errorw(fmt.Sprintf("failed complete disbursement batch %v", batch.Id), "stack_trace", err.Error())
+		return
+	}
+
+	if !completed {
+		return
+	}
+
+	rows, err := tr.disbursementBatchReads.GetListDisbursementBatchRowRepo(batch.Id, "")
+	if err != nil {
+		slog.Errorw(fmt.Sprintf("failed get rows of disbursement batch %v", batch.Id), "stack_trace", err.Error())
+	}
+
+	summary := map[string]int{}
+	for _, row := range rows {
+		if row.Status != constant.DisbursementBatchRowInvalid {
+			summary[row.Status]++
+		}
+	}
+
+	tr.auditor.RecordCommitted(dto.AuditEntry{
+		HistoryType: constant.HistoryTypeDisbursementBatch,
+		Activity:    constant.HistoryActivityComplete,
+		TargetId:    strconv.Itoa(batch.Id),
+		Before:      map[string]interface{}{"status": constant.DisbursementBatchProcessing},
+		After: map[string]interface{}{
+			"status":  constant.DisbursementBatchCompleted,
+			"summary": summary,
+		},
+		Actor: actor,
+	})
+
+	slog.Infof("disbursement batch %d completed with %v", batch.Id, summary)
+}
+
+// failDisbursementBatch stops a batch that panicked, its rows keep the status they got so far and the rows still
+// valid are not disbursed anymore
+func (tr *Transaction) failDisbursementBatch(batch entity.DisbursementBatch, actor dto.AuditActor, p interface{}) {
+	slog.Errorw(fmt.Sprintf("disbursement batch %v stopped", batch.Id), "stack_trace", fmt.Sprintf("%v\n%s", p, debug.Stack()))
+
+	err := tr.disbursementBatchWrites.UpdateDisbursementBatchStatusRepo(batch.Id, constant.DisbursementBatchFailed)
+	if err != nil {
+		slog.Errorw(fmt.Sprintf("failed update disbursement batch %v to %v", batch.Id, constant.DisbursementBatchFailed), "stack_trace", err.Error())
+		return
+	}
+
+	tr.auditor.RecordCommitted(dto.AuditEntry{
+		HistoryType: constant.HistoryTypeDisbursementBatch,
+		Activity:    constant.HistoryActivityFail,
+		TargetId:    strconv.Itoa(batch.Id),
+		Before:      map[string]interface{}{"status": constant.DisbursementBatchProcessing},
+		After:       map[string]interface{}{"status": constant.DisbursementBatchFailed},
+		Actor:       actor,
+	})
+}
+
+// interruptDisbursementBatchRow keeps a row that panicked from being disbursed again, the provider may already
+// have taken it
+func (tr *Transaction) interruptDisbursementBatchRow(row entity.DisbursementBatchRow, p interface{}) {
+	slog.Errorw(fmt.Sprintf("disbursement batch row %v stopped", row.Id), "stack_trace", fmt.Sprintf("%v\n%s", p, debug.Stack()))
+
+	err := tr.disbursementBatchWrites.UpdateDisbursementBatchRowStatusRepo(row.Id, constant.DisbursementBatchRowInterrupted, "", 0, constant.DisbursementBatchRowInterruptedMsg)
+	if err != nil {
+		slog.Errorw(fmt.Sprintf("failed update disbursement batch row %v to %v", row.Id, constant.DisbursementBatchRowInterrupted), "stack_trace", err.Error())
+	}
+}
+
+// ResumeDisbursementBatchesSvc picks up the processing batches left behind by an instance that stopped, rows
+// stopped in the middle of their disbursement are interrupted and the rows still valid are disbursed
+func (tr *Transaction) ResumeDisbursementBatchesSvc() error {
+	batches, err := tr.disbursementBatchWrites.ClaimStaleDisbursementBatchesRepo(constant.DisbursementBatchStaleAfter)
+	if err != nil {
+		return err
+	}
+
+	for _, batch := range batches {
+		err = tr.disbursementBatchWrites.InterruptDisbursementBatchRowsRepo(batch.Id)
+		if err != nil {
+			slog.Errorw(fmt.Sprintf("failed interrupt rows of disbursement batch %v", batch.Id), "stack_trace", err.Error())
+			continue
+		}
+
+		// the rows are still the disbursements of the user who confirmed the batch
+		username := nullSafeString(batch.ConfirmedBy)
+		slog.Infof("resuming disbursement batch %d", batch.Id)
+		tr.processDisbursementBatch(batch, username, dto.AuditActor{Username: username})
+	}
+
+	return nil
+}
+
+// processDisbursementBatchRow goes the way of MerchantDisbursementSvc, a row reaching an approval rule of the
+// merchant waits as a disbursement request instead of being disbursed
+func (tr *Transaction) processDisbursementBatchRow(merchantId string, row entity.DisbursementBatchRow, username string, actor dto.AuditActor) {
+	claimed, err := tr.disbursementBatchWrites.ClaimDisbursementBatchRowRepo(row.Id)
+	if err != nil {
+		slog.Errorw(fmt.Sprintf("failed claim disbursement batch row %v", row.Id), "stack_trace", err.Error())
+		return
+	}
+
+	if !claimed {
+		return
+	}
+
+	disbursement := dto.MerchantDisbursement{
+		Amount:            *row.Amount,
+		BankName:          nullSafeString(row.BankName),
+		BankAccountName:   row.BankAccountName,
+		BankAccountNumber: row.BankAccountNumber,
+		Note:              nullSafeString(row.Note),
+		Username:          username,
+		Actor:             actor,
+	}
+
+	var paymentId, errorMessage string
+	var requestId int
+	status := constant.DisbursementBatchRowDisbursed
+
+	rule, err := tr.disbursementApprovalReads.GetDisbursementApprovalRuleForAmountRepo(merchantId, disbursement.Amount)
+	switch {
+	case err != nil && !errors.Is(err, sql.ErrNoRows):
+		slog.Errorw("failed get disbursement approval rule", "stack_trace", err.Error())
+		status = constant.DisbursementBatchRowFailed
+		errorMessage = constant.GeneralErrMsg
+	case err == nil:
+		requestId, err = tr.createDisbursementRequest(merchantId, rule, disbursement)
+		if err != nil {
+			slog.Errorw("failed create disbursement request", "stack_trace", err.Error())
+			status = constant.DisbursementBatchRowFailed
+			errorMessage = constant.GeneralErrMsg
+			break
+		}
+		status = constant.DisbursementBatchRowPendingApproval
+	default:
+		resp, err := tr.disburse(merchantId, disbursement)
+		if err != nil {
+			status = constant.DisbursementBatchRowFailed
+			errorMessage = resp.ResponseMessage
+		}
+		if result, ok := resp.Data.(dto.MerchantDisbursementRespDto); ok {
+			paymentId = result.PaymentId
+		}
+	}
+
+	err = tr.disbursementBatchWrites.UpdateDisbursementBatchRowStatusRepo(row.Id, status, paymentId, requestId, errorMessage)
+	if err != nil {
+		slog.Errorw(fmt.Sprintf("failed update disbursement batch row %v to %v", row.Id, status), "stack_trace", err.Error())
+	}
+}
+
+func (tr *Transaction) GetListDisbursementBatchSvc(params dto.QueryParamsDisbursementBatch) (dto.ResponseDto, error) {
+	merchantId, resp, err := tr.merchantOfUser(params.Username)
+	if err != nil {
+		return resp, err
+	}
+
+	params.MerchantId = merchantId
+	batches, pagination, err := tr.disbursementBatchReads.GetListDisbursementBatchRepo(params)
+	if err != nil {
+		slog.Errorw("failed get list disbursement batch", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	if len(batches) < 1 {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "Data not found",
+			Data:            batches,
+			Pagination:      pagination,
+		}
+		return resp, nil
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "success retrieve disbursement batches",
+		Data:            batches,
+		Pagination:      pagination,
+	}
+
+	return resp, nil
+}
+
+func (tr *Transaction) GetDisbursementBatchDetailSvc(batchId int, username string) (dto.ResponseDto, error) {
+	batch, rows, resp, err := tr.getDisbursementBatchWithRows(batchId, username)
+	if err != nil || batch.Id == 0 {
+		return resp, err
+	}
+
+	summary := map[string]int{}
+	for _, row := range rows {
+		summary[row.Status]++
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "success retrieve disbursement batch",
+		Data: dto.DisbursementBatchDetailDto{
+			DisbursementBatch: batch,
+			InvalidRows:       batch.TotalRows - batch.ValidRows,
+			GrandTotal:        batch.TotalAmount.Add(batch.TotalFee),
+			StatusSummary:     summary,
+			Rows:              rows,
+		},
+	}
+
+	return resp, nil
+}
+
+// DownloadDisbursementBatchResultSvc returns the rows of the batch with their status as a xlsx file
+func (tr *Transaction) DownloadDisbursementBatchResultSvc(batchId int, username string) (dto.ResponseDto, error) {
+	batch, rows, resp, err := tr.getDisbursementBatchWithRows(batchId, username)
+	if err != nil || batch.Id == 0 {
+		return resp, err
+	}
+
+	headers := []string{
+		"Row Number",
+		"Bank Code",
+		"Bank Name",
+		"Bank Account Number",
+		"Bank Account Name",
+		"Amount",
+		"Fee",
+		"Note",
+		"Status",
+		"Error Message",
+		"Payment ID",
+		"Disbursement Request ID",
+		"Updated At",
+	}
+
+	data := make([][]interface{}, len(rows))
+	for i, row := range rows {
+		var requestId interface{}
+		if row.DisbursementRequestId != nil {
+			requestId = *row.DisbursementRequestId
+		}
+
+		data[i] = []interface{}{
+			row.RowNumber,
+			row.BankCode,
+			nullSafeString(row.BankName),
+			row.BankAccountNumber,
+			row.BankAccountName,
+			nullSafeMoney(row.Amount),
+			row.Fee.Float64(),
+			nullSafeString(row.Note),
+			row.Status,
+			nullSafeString(row.ErrorMessage),
+			nullSafeString(row.PaymentId),
+			requestId,
+			row.UpdatedAt,
+		}
+	}
+
+	file, err := helper.CreateExcelBuffer(headers, data)
+	if err != nil {
+		slog.Errorw("failed create disbursement batch result file", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "success create disbursement batch result",
+		Data: dto.DisbursementBatchResultFileDto{
+			FileName: fmt.Sprintf("disbursement-batch-%v-result.xlsx", batch.Id),
+			Content:  file.Bytes(),
+		},
+	}
+
+	return resp, nil
+}
+
+// getDisbursementBatchWithRows leaves the batch empty with a "Data not found" response when the merchant of the
+// user has no such batch
+func (tr *Transaction) getDisbursementBatchWithRows(batchId int, username string) (entity.DisbursementBatch, []entity.DisbursementBatchRow, dto.ResponseDto, error) {
+	var batch entity.DisbursementBatch
+
+	merchantId, resp, err := tr.merchantOfUser(username)
+	if err != nil {
+		return batch, nil, resp, err
+	}
+
+	batch, err = tr.disbursementBatchReads.GetDisbursementBatchByIdRepo(batchId, merchantId)
+	if errors.Is(err, sql.ErrNoRows) {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "Data not found",
+		}
+		return entity.DisbursementBatch{}, nil, resp, nil
+	}
+	if err != nil {
+		slog.Errorw("failed get disbursement batch", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return batch, nil, resp, err
+	}
+
+	rows, err := tr.disbursementBatchReads.GetListDisbursementBatchRowRepo(batch.Id, "")
+	if err != nil {
+		slog.Errorw("failed get disbursement batch rows", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return batch, nil, resp, err
+	}
+
+	return batch, rows, resp, nil
+}
+
+// isDisbursementBatchHeader tells whether cells start with DisbursementBatchHeaders, the case doesn't matter
+func isDisbursementBatchHeader(cells []string) bool {
+	for i, header := range constant.DisbursementBatchHeaders {
+		if !strings.EqualFold(spreadsheetCell(cells, i), header) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isBlankRow(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func spreadsheetCell(cells []string, index int) string {
+	if index >= len(cells) {
+		return ""
+	}
+
+	return strings.TrimSpace(cells[index])
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+)
+
+func testDisbursementBatchRouting() disbursementBatchRouting {
+	routing := disbursementBatchRouting{
+		banks: map[string]entity.BankListDto{
+			"BCA": {BankCode: "BCA", BankName: "Bank Central Asia"},
+			"BRI": {BankCode: "BRI", BankName: "Bank Rakyat Indonesia"},
+		},
+		providerBanks: map[string]bool{
+			"BCA": true,
+			"BRI": false,
+		},
+	}
+	routing.merchantChannel.Fee = money.MustParse("2500")
+	routing.merchantChannel.MinTransaction = money.MustParse("10000")
+	routing.merchantChannel.MaxTransaction = money.MustParse("50000000")
+	routing.routedChannel.MinTransaction = money.MustParse("10000")
+	routing.routedChannel.MaxTransaction = money.MustParse("25000000")
+
+	return routing
+}
+
+func TestValidateDisbursementBatchRow(t *testing.T) {
+	tests := []struct {
+		name       string
+		row        dto.DisbursementBatchRowDto
+		amountCell string
+		wantStatus string
+		wantErr    string
+	}{
+		{
+			name:       "valid",
+			row:        dto.DisbursementBatchRowDto{BankCode: "BCA", BankAccountNumber: "1234567890", BankAccountName: "Budi"},
+			amountCell: "150000",
+			wantStatus: constant.DisbursementBatchRowValid,
+		},
+		{
+			name:       "bank code in lowercase",
+			row:        dto.DisbursementBatchRowDto{BankCode: "bca", BankAccountNumber: "1234567890", BankAccountName: "Budi"},
+			amountCell: "150000.50",
+			wantStatus: constant.DisbursementBatchRowValid,
+		},
+		{
+			name:       "cell too long",
+			row:        dto.DisbursementBatchRowDto{BankCode: "BCA", BankAccountNumber: "1234567890", BankAccountName: "Budi", Note: strings.Repeat("a", 256)},
+			amountCell: "150000",
+			wantStatus: constant.DisbursementBatchRowInvalid,
+			wantErr:    "cell is longer than 255 characters",
+		},
+		{
+			name:       "unknown bank code",
+			row:        dto.DisbursementBatchRowDto{BankCode: "XYZ", BankAccountNumber: "1234567890", BankAccountName: "Budi"},
+			amountCell: "150000",
+			wantStatus: constant.DisbursementBatchRowInvalid,
+			wantErr:    "bank code not supported for disbursement",
+		},
+		{
+			name:       "bank the provider doesn't support",
+			row:        dto.DisbursementBatchRowDto{BankCode: "BRI", BankAccountNumber: "1234567890", BankAccountName: "Budi"},
+			amountCell: "150000",
+			wantStatus: constant.DisbursementBatchRowInvalid,
+			wantErr:    "bank not supported for disbursement",
+		},
+		{
+			name:       "account number with letters",
+			row:        dto.DisbursementBatchRowDto{BankCode: "BCA", BankAccountNumber: "12345A7890", BankAccountName: "Budi"},
+			amountCell: "150000",
+			wantStatus: constant.DisbursementBatchRowInvalid,
+			wantErr:    "bank account number must only contain digits",
+		},
+		{
+			name:       "empty account number",
+			row:        dto.DisbursementBatchRowDto{BankCode: "BCA", BankAccountName: "Budi"},
+			amountCell: "150000",
+			wantStatus: constant.DisbursementBatchRowInvalid,
+			wantErr:    "bank account number must only contain digits",
+		},
+		{
+			name:       "empty account name",
+			row:        dto.DisbursementBatchRowDto{BankCode: "BCA", BankAccountNumber: "1234567890"},
+			amountCell: "150000",
+			wantStatus: constant.DisbursementBatchRowInvalid,
+			wantErr:    "bank account name is mandatory",
+		},
+		{
+			name:       "amount with thousand separators",
+			row:        dto.DisbursementBatchRowDto{BankCode: "BCA", BankAccountNumber: "1234567890", BankAccountName: "Budi"},
+			amountCell: "150,000",
+			wantStatus: constant.DisbursementBatchRowInvalid,
+			wantErr:    "amount is not a number",
+		},
+		{
+			name:       "zero amount",
+			row:        dto.DisbursementBatchRowDto{BankCode: "BCA", BankAccountNumber: "1234567890", BankAccountName: "Budi"},
+			amountCell: "0",
+			wantStatus: constant.DisbursementBatchRowInvalid,
+			wantErr:    "amount must be more than 0",
+		},
+		{
+			name:       "below the merchant channel minimum",
+			row:        dto.DisbursementBatchRowDto{BankCode: "BCA", BankAccountNumber: "1234567890", BankAccountName: "Budi"},
+			amountCell: "9999",
+			wantStatus: constant.DisbursementBatchRowInvalid,
+			wantErr:    fmt.Sprintf("amount must be between %v and %v", money.MustParse("10000"), money.MustParse("50000000")),
+		},
+		{
+			name:       "above the routed channel maximum",
+			row:        dto.DisbursementBatchRowDto{BankCode: "BCA", BankAccountNumber: "1234567890", BankAccountName: "Budi"},
+			amountCell: "30000000",
+			wantStatus: constant.DisbursementBatchRowInvalid,
+			wantErr:    fmt.Sprintf("amount must be between %v and %v", money.MustParse("10000"), money.MustParse("25000000")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := tt.row
+			validateDisbursementBatchRow(&row, tt.amountCell, testDisbursementBatchRouting(), map[string]int{})
+
+			if row.Status != tt.wantStatus || row.ErrorMessage != tt.wantErr {
+				t.Errorf("row = %v %q, want %v %q", row.Status, row.ErrorMessage, tt.wantStatus, tt.wantErr)
+			}
+
+			if row.Status != constant.DisbursementBatchRowValid {
+				return
+			}
+
+			if row.BankCode != "BCA" || row.BankName != "Bank Central Asia" {
+				t.Errorf("bank = %v %v, want the bank of the routing", row.BankCode, row.BankName)
+			}
+			if !row.Fee.Equal(money.MustParse("2500")) {
+				t.Errorf("fee = %v, want the merchant channel fee", row.Fee)
+			}
+			if want := money.MustParse(tt.amountCell); row.Amount == nil || !row.Amount.Equal(want) {
+				t.Errorf("amount = %v, want %v", row.Amount, want)
+			}
+		})
+	}
+}
+
+func TestValidateDisbursementBatchRowWithoutLimits(t *testing.T) {
+	routing := testDisbursementBatchRouting()
+	routing.merchantChannel.MinTransaction = money.Money{}
+	routing.merchantChannel.MaxTransaction = money.Money{}
+	routing.routedChannel.MinTransaction = money.Money{}
+	routing.routedChannel.MaxTransaction = money.Money{}
+
+	row := dto.DisbursementBatchRowDto{BankCode: "BCA", BankAccountNumber: "1234567890", BankAccountName: "Budi"}
+	validateDisbursementBatchRow(&row, "1", routing, map[string]int{})
+	if row.Status != constant.DisbursementBatchRowValid {
+		t.Errorf("row = %v %q, a channel without limits takes any amount", row.Status, row.ErrorMessage)
+	}
+}
+
+func TestValidateDisbursementBatchRowDuplicate(t *testing.T) {
+	routing := testDisbursementBatchRouting()
+	seen := map[string]int{}
+
+	rows := []struct {
+		row        dto.DisbursementBatchRowDto
+		amountCell string
+		wantStatus string
+		wantErr    string
+	}{
+		{dto.DisbursementBatchRowDto{RowNumber: 2, BankCode: "BCA", BankAccountNumber: "1234567890", BankAccountName: "Budi"}, "150000", constant.DisbursementBatchRowValid, ""},
+		// the bank code and amount are normalized before they are compared
+		{dto.DisbursementBatchRowDto{RowNumber: 3, BankCode: "bca", BankAccountNumber: "1234567890", BankAccountName: "Budi S"}, "150000.00", constant.DisbursementBatchRowInvalid, "same disbursement as row 2"},
+		{dto.DisbursementBatchRowDto{RowNumber: 4, BankCode: "BCA", BankAccountNumber: "1234567890", BankAccountName: "Budi"}, "150001", constant.DisbursementBatchRowValid, ""},
+		{dto.DisbursementBatchRowDto{RowNumber: 5, BankCode: "BCA", BankAccountNumber: "1234567891", BankAccountName: "Budi"}, "150000", constant.DisbursementBatchRowValid, ""},
+	}
+
+	for _, tt := range rows {
+		row := tt.row
+		validateDisbursementBatchRow(&row, tt.amountCell, routing, seen)
+		if row.Status != tt.wantStatus || row.ErrorMessage != tt.wantErr {
+			t.Errorf("row %v = %v %q, want %v %q", row.RowNumber, row.Status, row.ErrorMessage, tt.wantStatus, tt.wantErr)
+		}
+	}
+}
+
+func TestDisbursementBatchCells(t *testing.T) {
+	if !isDisbursementBatchHeader([]string{"bank code", " Bank Account Number ", "BANK ACCOUNT NAME", "Amount", "Note", "extra"}) {
+		t.Error("header in another case or with spaces isn't taken")
+	}
+	if isDisbursementBatchHeader([]string{"Bank Code", "Bank Account Name", "Bank Account Number", "Amount", "Note"}) {
+		t.Error("header with columns swapped is taken")
+	}
+	// the header needs every column, even the note rows may leave empty
+	if isDisbursementBatchHeader([]string{"Bank Code", "Bank Account Number", "Bank Account Name", "Amount"}) {
+		t.Error("header without the note column is taken")
+	}
+
+	if !isBlankRow([]string{"", "  ", "\t"}) || !isBlankRow(nil) {
+		t.Error("row of empty cells isn't blank")
+	}
+	if isBlankRow([]string{"", "BCA"}) {
+		t.Error("row with a cell is blank")
+	}
+
+	cells := []string{" BCA ", "123"}
+	if got := spreadsheetCell(cells, 0); got != "BCA" {
+		t.Errorf("spreadsheetCell = %q, want trimmed cell", got)
+	}
+	if got := spreadsheetCell(cells, 4); got != "" {
+		t.Errorf("spreadsheetCell past the last cell = %q, want empty", got)
+	}
+}
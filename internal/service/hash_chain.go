@@ -0,0 +1,356 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/hashchain"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+// HashChain verifies the hash chains of the capital flows and the audit trail and takes their checkpoints
+type HashChain struct {
+	hashChainRepoReads internal.HashChainReadsRepositoryItf
+	unitOfWork         internal.UnitOfWorkItf
+}
+
+func NewHashChain(hashChainRepoReads internal.HashChainReadsRepositoryItf, unitOfWork internal.UnitOfWorkItf) *HashChain {
+	return &HashChain{
+		hashChainRepoReads: hashChainRepoReads,
+		unitOfWork:         unitOfWork,
+	}
+}
+
+// VerifyHashChainSvc walks the chains asked for, every chain when none is, and reports the first broken link
+// of each. The last links kept by the latest checkpoint must still be found with the same hash.
+func (hc *HashChain) VerifyHashChainSvc(params dto.VerifyHashChainReq) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+	var verification dto.HashChainVerificationRespDto
+
+	generalErr := dto.ResponseDto{
+		ResponseCode:    http.StatusUnprocessableEntity,
+		ResponseMessage: constant.GeneralErrMsg,
+	}
+
+	if params.Chain != "" && params.Chain != constant.ChainCapitalFlow && params.Chain != constant.ChainAudit {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: fmt.Sprintf("chain must be %v or %v", constant.ChainCapitalFlow, constant.ChainAudit),
+		}
+		return resp, errors.New("unknown chain")
+	}
+
+	tails, err := hc.hashChainRepoReads.GetChainTailsRepo()
+	if err != nil {
+		slog.Errorw("failed get chain tails", "stack_trace", err.Error())
+		return generalErr, err
+	}
+
+	checkpointTails := map[string]entity.ChainTail{}
+	checkpoint, err := hc.hashChainRepoReads.GetLatestChainCheckpointRepo()
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		slog.Errorw("failed get latest chain checkpoint", "stack_trace", err.Error())
+		return generalErr, err
+	}
+
+	if err == nil {
+		verification.CheckpointId = &checkpoint.Id
+
+		tailsAtCheckpoint, err := hc.hashChainRepoReads.GetChainCheckpointTailsRepo(checkpoint.Id)
+		if err != nil {
+			slog.Errorw("failed get chain checkpoint tails", "stack_trace", err.Error())
+			return generalErr, err
+		}
+
+		if checkpointDigest(checkpoint.PrevDigest, tailsAtCheckpoint) != checkpoint.Digest {
+			verification.Breaks = append(verification.Breaks, dto.ChainBreakDto{
+				Chain:  constant.ChainCheckpoints,
+				RowId:  checkpoint.Id,
+				Reason: constant.ChainBreakCheckpoint,
+				Detail: "the tails of the checkpoint don't match its digest",
+			})
+		}
+
+		for _, tail := range tailsAtCheckpoint {
+			checkpointTails[tail.Chain+":"+tail.ChainKey] = tail
+		}
+	}
+
+	// a chain kept by the checkpoint but gone since is walked too, its rows were deleted
+	chains := map[string]entity.ChainTail{}
+	for _, tail := range tails {
+		chains[tail.Chain+":"+tail.ChainKey] = tail
+	}
+	for chainId, tail := range checkpointTails {
+		chains[chainId] = tail
+	}
+
+	chainIds := make([]string, 0, len(chains))
+	for chainId, tail := range chains {
+		if params.Chain != "" && tail.Chain != params.Chain || params.ChainKey != "" && tail.ChainKey != params.ChainKey {
+			continue
+		}
+		chainIds = append(chainIds, chainId)
+	}
+	sort.Strings(chainIds)
+
+	for _, chainId := range chainIds {
+		var checkpointTail *entity.ChainTail
+		if tail, ok := checkpointTails[chainId]; ok {
+			checkpointTail = &tail
+		}
+
+		links, chainBreak, err := hc.verifyChain(chains[chainId].Chain, chains[chainId].ChainKey, checkpointTail)
+		if err != nil {
+			slog.Errorw(fmt.Sprintf("failed verify chain %v", chainId), "stack_trace", err.Error())
+			return generalErr, err
+		}
+
+		verification.TotalLinks += links
+		if chainBreak != nil {
+			verification.Breaks = append(verification.Breaks, *chainBreak)
+		}
+	}
+	verification.TotalChains = len(chainIds)
+
+	// rows written while the chain triggers were off never got a link
+	for _, chain := range []string{constant.ChainCapitalFlow, constant.ChainAudit} {
+		if params.Chain != "" && chain != params.Chain {
+			continue
+		}
+
+		unchained, err := hc.hashChainRepoReads.CountUnchainedRowsRepo(chain)
+		if err != nil {
+			slog.Errorw("failed count unchained rows", "stack_trace", err.Error())
+			return generalErr, err
+		}
+
+		if unchained > 0 {
+			verification.Breaks = append(verification.Breaks, dto.ChainBreakDto{
+				Chain:  chain,
+				Reason: constant.ChainBreakUnchained,
+				Detail: fmt.Sprintf("%v rows have no link in the chain", unchained),
+			})
+		}
+	}
+
+	msg := "hash chains are intact"
+	if len(verification.Breaks) > 0 {
+		msg = fmt.Sprintf("found %v broken hash chains", len(verification.Breaks))
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: msg,
+		Data:            verification,
+	}
+
+	return resp, nil
+}
+
+// verifyChain walks one chain in order and returns how many links it has and its first broken link
+func (hc *HashChain) verifyChain(chain string, chainKey string, checkpointTail *entity.ChainTail) (int, *dto.ChainBreakDto, error) {
+	var totalLinks int
+	var lastSeq int64
+	var lastHash *string
+
+	newBreak := func(link entity.ChainLink, reason string, detail string) *dto.ChainBreakDto {
+		return &dto.ChainBreakDto{
+			Chain:    chain,
+			ChainKey: chainKey,
+			ChainSeq: link.ChainSeq,
+			RowId:    link.Id,
+			Reason:   reason,
+			Detail:   detail,
+		}
+	}
+
+	for {
+		links, err := hc.hashChainRepoReads.GetChainLinksRepo(chain, chainKey, lastSeq, constant.ChainVerifyBatch)
+		if err != nil {
+			return totalLinks, nil, err
+		}
+
+		for _, link := range links {
+			totalLinks++
+
+			if link.ChainSeq != lastSeq+1 {
+				return totalLinks, newBreak(link, constant.ChainBreakSequence, fmt.Sprintf("links %v to %v are missing", lastSeq+1, link.ChainSeq-1)), nil
+			}
+
+			if !sameHash(link.PrevHash, lastHash) {
+				return totalLinks, newBreak(link, constant.ChainBreakPrevHash, "the previous hash doesn't match the hash of the link before"), nil
+			}
+
+			if link.Hash == nil || *link.Hash != hashchain.Hash(link.PrevHash, link.Fields...) {
+				return totalLinks, newBreak(link, constant.ChainBreakHash, "the row was changed after it was chained"), nil
+			}
+
+			if checkpointTail != nil && checkpointTail.ChainSeq == link.ChainSeq && checkpointTail.Hash != *link.Hash {
+				return totalLinks, newBreak(link, constant.ChainBreakCheckpoint, "the chain was rewritten since the latest checkpoint"), nil
+			}
+
+			lastSeq = link.ChainSeq
+			lastHash = link.Hash
+		}
+
+		if len(links) < constant.ChainVerifyBatch {
+			break
+		}
+	}
+
+	if checkpointTail != nil && lastSeq < checkpointTail.ChainSeq {
+		return totalLinks, &dto.ChainBreakDto{
+			Chain:    chain,
+			ChainKey: chainKey,
+			ChainSeq: lastSeq + 1,
+			Reason:   constant.ChainBreakCheckpoint,
+			Detail:   fmt.Sprintf("the chain ends at %v but the latest checkpoint kept it up to %v", lastSeq, checkpointTail.ChainSeq),
+		}, nil
+	}
+
+	return totalLinks, nil, nil
+}
+
+// CreateChainCheckpointSvc keeps the last link of every chain under a digest linked to the previous checkpoint
+func (hc *HashChain) CreateChainCheckpointSvc(createdBy string) (dto.ResponseDto, error) {
+	return runInUnitOfWork(hc.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		var resp dto.ResponseDto
+
+		generalErr := dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+
+		latest, err := repos.HashChainWrites.GetLatestChainCheckpointForUpdateRepo()
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			slog.Errorw("failed get latest chain checkpoint", "stack_trace", err.Error())
+			return generalErr, err
+		}
+
+		tails, err := repos.HashChainWrites.GetChainTailsRepo()
+		if err != nil {
+			slog.Errorw("failed get chain tails", "stack_trace", err.Error())
+			return generalErr, err
+		}
+		sortChainTails(tails)
+
+		payload := dto.CreateChainCheckpointPayload{
+			CreatedBy: createdBy,
+			Tails:     tails,
+		}
+		if latest.Id != 0 {
+			payload.PrevDigest = latest.Digest
+		}
+		payload.Digest = checkpointDigest(&payload.PrevDigest, tails)
+
+		checkpointId, err := repos.HashChainWrites.CreateChainCheckpointRepo(payload)
+		if err != nil {
+			slog.Errorw("failed create chain checkpoint", "stack_trace", err.Error())
+			return generalErr, err
+		}
+
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "success create chain checkpoint",
+			Data: map[string]interface{}{
+				"chainCheckpointId": checkpointId,
+				"digest":            payload.Digest,
+			},
+		}
+
+		return resp, nil
+	})
+}
+
+// GetListChainCheckpointSvc exports the checkpoints with the tails their digests cover, so they can be kept
+// outside of the database and checked against it later
+func (hc *HashChain) GetListChainCheckpointSvc(params dto.QueryParamsChainCheckpoint) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+	var checkpointExports []dto.ChainCheckpointExportDto
+
+	checkpoints, err := hc.hashChainRepoReads.GetListChainCheckpointRepo(params)
+	if err != nil {
+		slog.Errorw("failed get list chain checkpoint", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	for _, checkpoint := range checkpoints {
+		tails, err := hc.hashChainRepoReads.GetChainCheckpointTailsRepo(checkpoint.Id)
+		if err != nil {
+			slog.Errorw("failed get chain checkpoint tails", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		checkpointExports = append(checkpointExports, dto.ChainCheckpointExportDto{
+			ChainCheckpoint: checkpoint,
+			Tails:           tails,
+		})
+	}
+
+	if len(checkpointExports) < 1 {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "Data not found",
+			Data:            checkpointExports,
+		}
+		return resp, nil
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "success retrieve chain checkpoints",
+		Data:            checkpointExports,
+	}
+
+	return resp, nil
+}
+
+// checkpointDigest hashes the tails, in the order they are stored, the way the rows of a chain are hashed:
+// the previous digest followed by chain, chain key, chain seq and hash of every tail
+func checkpointDigest(prevDigest *string, tails []entity.ChainTail) string {
+	if prevDigest != nil && *prevDigest == "" {
+		prevDigest = nil
+	}
+
+	var fields []*string
+	for _, tail := range tails {
+		chain, chainKey, chainSeq, hash := tail.Chain, tail.ChainKey, strconv.FormatInt(tail.ChainSeq, 10), tail.Hash
+		fields = append(fields, &chain, &chainKey, &chainSeq, &hash)
+	}
+
+	return hashchain.Hash(prevDigest, fields...)
+}
+
+func sortChainTails(tails []entity.ChainTail) {
+	sort.Slice(tails, func(i, j int) bool {
+		if tails[i].Chain != tails[j].Chain {
+			return tails[i].Chain < tails[j].Chain
+		}
+		return tails[i].ChainKey < tails[j].ChainKey
+	})
+}
+
+func sameHash(a *string, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return *a == *b
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+var errInvalidInvitation = errors.New("invitation is invalid or expired")
+
+var errInvitationNotFound = errors.New("pending invitation not found")
+
+func (u *User) GetListInvitationsSvc(payload dto.InvitationPayload) (dto.ResponseDto, error) {
+	merchantId, resp, err := scopedMerchantId(u.userRepoReads, payload.MerchantId, payload.Username)
+	if err != nil {
+		return resp, err
+	}
+
+	invitations, err := u.userRepoReads.GetListPendingInvitationsRepo(merchantId)
+	if err != nil {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	if len(invitations) < 1 {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "data not found",
+			Data:            invitations,
+		}
+		return resp, nil
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "success retrieve data",
+		Data:            invitations,
+	}
+
+	return resp, nil
+}
+
+// ResendInvitationSvc sends a pending invitation again with a new link, the old link stops working
+func (u *User) ResendInvitationSvc(payload dto.InvitationPayload) (dto.ResponseDto, error) {
+	merchantId, resp, err := scopedMerchantId(u.userRepoReads, payload.MerchantId, payload.Username)
+	if err != nil {
+		return resp, err
+	}
+
+	inviteToken, err := generateSecureToken(32)
+	if err != nil {
+		slog.Errorw("failed generate invitation token", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	// the email is sent before commit, a failed email leaves the previous link working
+	return runInUnitOfWork(u.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		uTx := u.withUnitOfWork(repos)
+
+		invitation, err := uTx.userRepoWrites.ResendInvitationRepo(payload.InvitationId, merchantId, hashSecureToken(inviteToken), constant.InvitationTTL)
+		if err == sql.ErrNoRows {
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: errInvitationNotFound.Error(),
+			}, errInvitationNotFound
+		}
+		if err != nil {
+			slog.Errorw("failed resend invitation", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		err = u.sendInvitation(invitation.Email, inviteToken)
+		if err != nil {
+			slog.Errorw("failed send invitation email", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: fmt.Sprintf("successfully resend invitation to %v", invitation.Email),
+		}, nil
+	})
+}
+
+func (u *User) RevokeInvitationSvc(payload dto.InvitationPayload) (dto.ResponseDto, error) {
+	merchantId, resp, err := scopedMerchantId(u.userRepoReads, payload.MerchantId, payload.Username)
+	if err != nil {
+		return resp, err
+	}
+
+	err = u.userRepoWrites.RevokeInvitationRepo(payload.InvitationId, merchantId)
+	if err == sql.ErrNoRows {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: errInvitationNotFound.Error(),
+		}
+		return resp, errInvitationNotFound
+	}
+	if err != nil {
+		slog.Errorw("failed revoke invitation", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "successfully revoke invitation",
+	}
+
+	return resp, nil
+}
+
+// AcceptInvitationSvc creates the invited user with the password and pin the invitee picked
+func (u *User) AcceptInvitationSvc(payload dto.AcceptInvitationPayload) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	if !helper.IsValidPassword(payload.Password) {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: "password have to 8 character, at least one special character, and one uppercase letter",
+		}
+		return resp, errors.New("invalid password")
+	}
+
+	if !helper.IsValidPIN(payload.Pin) {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: "pin only 6 numeric allowed",
+		}
+		return resp, errors.New("invalid pin")
+	}
+
+	passHash, err := helper.HashString([]byte(payload.Password))
+	if err != nil {
+		slog.Errorw("failed hash password", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	pinHash, err := helper.HashString([]byte(payload.Pin))
+	if err != nil {
+		slog.Errorw("failed hash pin", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	return runInUnitOfWork(u.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		uTx := u.withUnitOfWork(repos)
+
+		invitation, err := uTx.userRepoWrites.AcceptInvitationRepo(hashSecureToken(payload.Token))
+		if err == sql.ErrNoRows {
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: errInvalidInvitation.Error(),
+			}, errInvalidInvitation
+		}
+		if err != nil {
+			slog.Errorw("failed accept invitation", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		invitee := dto.InviteMerchantUserDto{
+			Email:      invitation.Email,
+			RolesId:    invitation.RoleId,
+			MerchantId: invitation.MerchantId,
+		}
+		credential := dto.EmailDataHtmlDto{
+			Username: invitation.Email,
+			Password: passHash,
+			Pin:      pinHash,
+		}
+
+		idUser, err := uTx.userRepoWrites.CreateUsersMerchantRepo(invitee, credential)
+		if err != nil {
+			slog.Errorw("failed create invited user", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: fmt.Sprintf("Success create user %v with id: %v", invitation.Email, idUser),
+		}, nil
+	})
+}
+
+// inviteUser stores a pending invitation and emails its link, the user is only created once the invitee
+// accepts it
+func (u *User) inviteUser(payload dto.InviteMerchantUserDto) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	user, err := u.userRepoReads.GetUserByUsername(payload.Email)
+	if err != nil {
+		slog.Errorw("failed get user", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	if user.UserID > 0 {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: fmt.Sprintf("user %v already exists", payload.Email),
+		}
+		return resp, errors.New("user already exists")
+	}
+
+	_, resp, err = u.merchantRole(payload.RolesId)
+	if err != nil {
+		return resp, err
+	}
+
+	inviteToken, err := generateSecureToken(32)
+	if err != nil {
+		slog.Errorw("failed generate invitation token", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	// the email is sent before commit, an invitation that never reached the invitee isn't kept
+	return runInUnitOfWork(u.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		uTx := u.withUnitOfWork(repos)
+
+		invitationId, err := uTx.userRepoWrites.CreateInvitationRepo(payload, hashSecureToken(inviteToken), constant.InvitationTTL)
+		if err == sql.ErrNoRows {
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: fmt.Sprintf("%v already has a pending invitation", payload.Email),
+			}, errors.New("invitation already pending")
+		}
+		if err != nil {
+			slog.Errorw("failed create invitation", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		err = u.sendInvitation(payload.Email, inviteToken)
+		if err != nil {
+			slog.Errorw("failed send invitation email", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: fmt.Sprintf("Success invite user %v with invitation id: %v", payload.Email, invitationId),
+		}, nil
+	})
+}
+
+func (u *User) sendInvitation(email string, inviteToken string) error {
+	inviteLink := fmt.Sprintf("%v%v?token=%v", strings.TrimRight(u.cfg.DashboardUrl, "/"), constant.InvitationPath, url.QueryEscape(inviteToken))
+
+	return helper.SendEmailInvitation(dto.InvitationEmailDataDto{
+		Email:       email,
+		InviteLink:  inviteLink,
+		ExpiresDays: int(constant.InvitationTTL.Hours() / 24),
+	}, email, u.cfg.AppPassMail)
+}
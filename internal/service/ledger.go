@@ -0,0 +1,139 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+)
+
+// ledgerJournal collects balanced postings for one business operation
+type ledgerJournal struct {
+	payload dto.CreateLedgerJournalPayload
+}
+
+func newLedgerJournal(paymentId string, reasonId int, createdBy string, notes string) *ledgerJournal {
+	return &ledgerJournal{
+		payload: dto.CreateLedgerJournalPayload{
+			PaymentId: paymentId,
+			ReasonId:  reasonId,
+			Notes:     notes,
+			CreatedBy: createdBy,
+		},
+	}
+}
+
+// move debits account from and credits account to with the same amount
+func (lj *ledgerJournal) move(from dto.LedgerAccountRef, to dto.LedgerAccountRef, amount money.Money) *ledgerJournal {
+	if amount.IsZero() {
+		return lj
+	}
+
+	// negative amount means the money flows the other way around
+	if amount.IsNegative() {
+		from, to = to, from
+		amount = amount.Neg()
+	}
+
+	lj.payload.Postings = append(lj.payload.Postings,
+		dto.LedgerPostingPayload{Account: from, Direction: constant.LedgerDirectionDebit, Amount: amount},
+		dto.LedgerPostingPayload{Account: to, Direction: constant.LedgerDirectionCredit, Amount: amount},
+	)
+
+	return lj
+}
+
+func postLedgerJournal(ledgerRepoWrites internal.LedgerWritesRepositoryItf, journal *ledgerJournal) error {
+	if len(journal.payload.Postings) == 0 {
+		return nil
+	}
+
+	var debit, credit money.Money
+	for _, posting := range journal.payload.Postings {
+		if posting.Direction == constant.LedgerDirectionDebit {
+			debit = debit.Add(posting.Amount)
+		} else {
+			credit = credit.Add(posting.Amount)
+		}
+	}
+
+	if !debit.Equal(credit) {
+		return errors.New("ledger journal is not balanced")
+	}
+
+	_, err := ledgerRepoWrites.CreateLedgerJournalRepo(journal.payload)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func merchantLedgerAccount(merchantId string, accountType string) dto.LedgerAccountRef {
+	return dto.LedgerAccountRef{
+		OwnerType:   constant.LedgerOwnerMerchant,
+		OwnerId:     merchantId,
+		AccountType: accountType,
+	}
+}
+
+func platformLedgerAccount(accountType string) dto.LedgerAccountRef {
+	return dto.LedgerAccountRef{
+		OwnerType:   constant.LedgerOwnerPlatform,
+		OwnerId:     constant.LedgerPlatformId,
+		AccountType: accountType,
+	}
+}
+
+func providerFloatLedgerAccount(providerName string) dto.LedgerAccountRef {
+	return dto.LedgerAccountRef{
+		OwnerType:   constant.LedgerOwnerProvider,
+		OwnerId:     providerName,
+		AccountType: constant.LedgerAccountProviderFloat,
+	}
+}
+
+// merchantAccountFromLedger derives merchant balances from postings, merchant buckets are credit-normal
+func merchantAccountFromLedger(merchantAccount entity.MerchantAccount, balances []entity.LedgerAccountBalance) entity.MerchantAccount {
+	merchantAccount.SettledBalance = money.Money{}
+	merchantAccount.NotSettledBalance = money.Money{}
+	merchantAccount.HoldBalance = money.Money{}
+	merchantAccount.PendingTransactionOut = money.Money{}
+
+	for _, balance := range balances {
+		amount := balance.Credit.Sub(balance.Debit)
+
+		switch balance.AccountType {
+		case constant.LedgerAccountSettled:
+			merchantAccount.SettledBalance = amount
+		case constant.LedgerAccountNotSettled:
+			merchantAccount.NotSettledBalance = amount
+		case constant.LedgerAccountHold:
+			merchantAccount.HoldBalance = amount
+		case constant.LedgerAccountPendingOut:
+			merchantAccount.PendingTransactionOut = amount
+		}
+	}
+
+	merchantAccount.BalanceCapitalFlow = merchantAccount.SettledBalance.
+		Add(merchantAccount.NotSettledBalance).
+		Add(merchantAccount.HoldBalance).
+		Add(merchantAccount.PendingTransactionOut)
+
+	return merchantAccount
+}
+
+// ledgerMerchantAccount returns merchantAccount with its buckets summed from the ledger postings, balance checks
+// read these. The caller holds the merchant account lock, every posting of the merchant is made under it. The
+// merchant_accounts columns are still written as a projection for the capital flows and compared by reconciliation.
+func ledgerMerchantAccount(ledgerRepoWrites internal.LedgerWritesRepositoryItf, merchantAccount entity.MerchantAccount) (entity.MerchantAccount, error) {
+	balances, err := ledgerRepoWrites.GetLedgerBalancesByOwnerRepo(constant.LedgerOwnerMerchant, merchantAccount.MerchantId)
+	if err != nil {
+		return merchantAccount, err
+	}
+
+	return merchantAccountFromLedger(merchantAccount, balances), nil
+}
@@ -13,7 +13,9 @@ import (
 	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/envelope"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
 )
 
@@ -24,6 +26,14 @@ type Merchant struct {
 	merchantCallbackAdptr internal.MerchantCallbackItf
 	transactionRepoReads  internal.TransactionsReadsRepositoryItf
 	providerRepoReads     internal.ProviderReadsRepositoryItf
+	unitOfWork            internal.UnitOfWorkItf
+	ledgerRepoReads       internal.LedgerReadsRepositoryItf
+	ledgerRepoWrites      internal.LedgerWritesRepositoryItf
+	credentialGuard       *CredentialGuard
+	keyring               *envelope.Keyring
+	auditor               *Auditor
+	balanceApprovalReads  internal.BalanceApprovalReadsRepositoryItf
+	balanceApprovalWrites internal.BalanceApprovalWritesRepositoryItf
 }
 
 func NewMerchant(
@@ -33,6 +43,14 @@ func NewMerchant(
 	adapterMerchantCallback internal.MerchantCallbackItf,
 	transactionRepoReads internal.TransactionsReadsRepositoryItf,
 	providerRepoReads internal.ProviderReadsRepositoryItf,
+	unitOfWork internal.UnitOfWorkItf,
+	ledgerRepoReads internal.LedgerReadsRepositoryItf,
+	ledgerRepoWrites internal.LedgerWritesRepositoryItf,
+	credentialGuard *CredentialGuard,
+	keyring *envelope.Keyring,
+	auditor *Auditor,
+	balanceApprovalReads internal.BalanceApprovalReadsRepositoryItf,
+	balanceApprovalWrites internal.BalanceApprovalWritesRepositoryItf,
 ) *Merchant {
 	return &Merchant{
 		merchantRepoReads:     merchantRepoReads,
@@ -41,6 +59,14 @@ func NewMerchant(
 		merchantCallbackAdptr: adapterMerchantCallback,
 		transactionRepoReads:  transactionRepoReads,
 		providerRepoReads:     providerRepoReads,
+		unitOfWork:            unitOfWork,
+		ledgerRepoReads:       ledgerRepoReads,
+		ledgerRepoWrites:      ledgerRepoWrites,
+		credentialGuard:       credentialGuard,
+		keyring:               keyring,
+		auditor:               auditor,
+		balanceApprovalReads:  balanceApprovalReads,
+		balanceApprovalWrites: balanceApprovalWrites,
 	}
 }
 
@@ -261,29 +287,30 @@ func (mr *Merchant) GetListManualPaymentWithFilter(params dto.QueryParamsManualP
 }
 
 func (mr *Merchant) TopUpMerchantSvc(payload dto.AdjustBalanceReqPayload) (dto.ResponseDto, error) {
-	var resp dto.ResponseDto
-
-	// user data
-	user, err := mr.userRepoReads.GetUserByUsername(payload.Username)
-	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: err.Error(),
-		}
-		return resp, err
+	request := dto.CreateBalanceOperationRequestPayload{
+		OperationType: constant.BalanceOperationTopUp,
+		MerchantId:    payload.MerchantId,
+		Amount:        payload.Amount,
+		Notes:         payload.Notes,
+		RequestedBy:   payload.Username,
 	}
 
-	// check input pin
-	if !comparePasswords(user.Pin, []byte(payload.Pin)) {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "wrong pin",
-		}
+	return mr.requestBalanceOperation(request, payload.Pin, payload, payload.Actor, func(mrTx *Merchant) (dto.ResponseDto, error) {
+		return mrTx.applyTopUp(payload)
+	})
+}
 
-		return resp, errors.New("wrong pin")
-	}
+// applyTopUp changes the balance, mr has to come from a unit of work
+func (mr *Merchant) applyTopUp(payload dto.AdjustBalanceReqPayload) (dto.ResponseDto, error) {
+	return mr.auditBalance(constant.HistoryActivityTopUp, payload.Actor, []string{payload.MerchantId}, func() (dto.ResponseDto, error) {
+		return mr.topUpMerchant(payload)
+	})
+}
+
+func (mr *Merchant) topUpMerchant(payload dto.AdjustBalanceReqPayload) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
 
-	merchantAccountBalance, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(payload.MerchantId)
+	merchantAccountBalance, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(payload.MerchantId)
 	if err != nil {
 		slog.Infof("top-up mechant id %v got failed: %v", payload.MerchantId, err.Error())
 		resp = dto.ResponseDto{
@@ -301,13 +328,11 @@ func (mr *Merchant) TopUpMerchantSvc(payload dto.AdjustBalanceReqPayload) (dto.R
 		return resp, errors.New("wrong merchant id")
 	}
 
-	topUpBalance := merchantAccountBalance.SettledBalance + float64(payload.Amount)
-	balanceCapitalFlow := merchantAccountBalance.BalanceCapitalFlow + float64(payload.Amount)
-	formattedTopUpBalance := helper.FormatFloat64(topUpBalance)
-	formattedBalanceCapitalFlow := helper.FormatFloat64(balanceCapitalFlow)
+	topUpBalance := merchantAccountBalance.SettledBalance.Add(payload.Amount)
+	balanceCapitalFlow := merchantAccountBalance.BalanceCapitalFlow.Add(payload.Amount)
 
 	// update merchant account balance
-	err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formattedTopUpBalance, formattedBalanceCapitalFlow, payload.MerchantId)
+	err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(topUpBalance, balanceCapitalFlow, payload.MerchantId)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -317,17 +342,16 @@ func (mr *Merchant) TopUpMerchantSvc(payload dto.AdjustBalanceReqPayload) (dto.R
 	}
 
 	// create merchant capital flow
-	formattedPayloadAmount := helper.FormatFloat64(float64(payload.Amount))
 	randomStr := helper.GenerateRandomString(30)
 	id := "top_up-" + randomStr
 	payloadMerchantCapitalFlowTopUp := dto.CreateMerchantCapitalFlowPayload{
 		PaymentId:         id,
 		MerchantAccountId: merchantAccountBalance.Id,
-		TempBalance:       formattedBalanceCapitalFlow,
+		TempBalance:       balanceCapitalFlow,
 		ReasonId:          constant.ReasonIdTopUp,
 		Status:            constant.StatusSuccess,
 		CreateBy:          payload.Username,
-		Amount:            formattedPayloadAmount,
+		Amount:            payload.Amount,
 		Notes:             payload.Notes,
 		CapitalType:       constant.CapitalTypeCredit,
 	}
@@ -340,7 +364,19 @@ func (mr *Merchant) TopUpMerchantSvc(payload dto.AdjustBalanceReqPayload) (dto.R
 		return resp, err
 	}
 
-	merchantAccountAfterTopUp, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(payload.MerchantId)
+	// post ledger journal
+	journal := newLedgerJournal(id, constant.ReasonIdTopUp, payload.Username, payload.Notes).
+		move(platformLedgerAccount(constant.LedgerAccountClearing), merchantLedgerAccount(payload.MerchantId, constant.LedgerAccountSettled), payload.Amount)
+	err = postLedgerJournal(mr.ledgerRepoWrites, journal)
+	if err != nil {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: err.Error(),
+		}
+		return resp, err
+	}
+
+	merchantAccountAfterTopUp, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(payload.MerchantId)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -360,30 +396,48 @@ func (mr *Merchant) TopUpMerchantSvc(payload dto.AdjustBalanceReqPayload) (dto.R
 }
 
 func (mr *Merchant) HoldBalanceSvc(payload dto.AdjustBalanceReqPayload) (dto.ResponseDto, error) {
+	request := dto.CreateBalanceOperationRequestPayload{
+		OperationType: constant.BalanceOperationHold,
+		MerchantId:    payload.MerchantId,
+		Amount:        payload.Amount,
+		Notes:         payload.Notes,
+		RequestedBy:   payload.Username,
+	}
+
+	return mr.requestBalanceOperation(request, payload.Pin, payload, payload.Actor, func(mrTx *Merchant) (dto.ResponseDto, error) {
+		return mrTx.applyHold(payload)
+	})
+}
+
+// applyHold changes the balance, mr has to come from a unit of work
+func (mr *Merchant) applyHold(payload dto.AdjustBalanceReqPayload) (dto.ResponseDto, error) {
+	return mr.auditBalance(constant.HistoryActivityHold, payload.Actor, []string{payload.MerchantId}, func() (dto.ResponseDto, error) {
+		return mr.holdBalance(payload)
+	})
+}
+
+func (mr *Merchant) holdBalance(payload dto.AdjustBalanceReqPayload) (dto.ResponseDto, error) {
 	var resp dto.ResponseDto
 	balanceSettleOrNotSettleFlagging := constant.SettleBalance
 
-	// user data
-	user, err := mr.userRepoReads.GetUserByUsername(payload.Username)
+	merchantAccountBalance, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(payload.MerchantId)
 	if err != nil {
 		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
+			ResponseCode:    http.StatusUnprocessableEntity,
 			ResponseMessage: err.Error(),
 		}
 		return resp, err
 	}
 
-	// check input pin
-	if !comparePasswords(user.Pin, []byte(payload.Pin)) {
+	if merchantAccountBalance.Id == 0 {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "wrong pin",
+			ResponseMessage: "wrong merchant id",
 		}
-
-		return resp, errors.New("wrong pin")
+		return resp, errors.New("wrong merchant id")
 	}
 
-	merchantAccountBalance, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(payload.MerchantId)
+	ledgerAccount, err := ledgerMerchantAccount(mr.ledgerRepoWrites, merchantAccountBalance)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -392,28 +446,18 @@ func (mr *Merchant) HoldBalanceSvc(payload dto.AdjustBalanceReqPayload) (dto.Res
 		return resp, err
 	}
 
-	if merchantAccountBalance.Id == 0 {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "wrong merchant id",
-		}
-		return resp, errors.New("wrong merchant id")
-	}
-
 	settleOrNotSettleBalance := merchantAccountBalance.SettledBalance
-	if settleOrNotSettleBalance < float64(payload.Amount) {
+	if ledgerAccount.SettledBalance.LessThan(payload.Amount) {
 		settleOrNotSettleBalance = merchantAccountBalance.NotSettledBalance
 		balanceSettleOrNotSettleFlagging = constant.NotSettledBalance
 	}
 
-	adjustedSettleOrNotSettleBalance := settleOrNotSettleBalance - float64(payload.Amount)
-	holdBalance := merchantAccountBalance.HoldBalance + float64(payload.Amount)
-	formattedHoldBalance := helper.FormatFloat64(holdBalance)
-	formattedAdjustMerchantBalance := helper.FormatFloat64(adjustedSettleOrNotSettleBalance)
+	adjustedSettleOrNotSettleBalance := settleOrNotSettleBalance.Sub(payload.Amount)
+	holdBalance := merchantAccountBalance.HoldBalance.Add(payload.Amount)
 
 	// if using settle balance to hold (updated)
 	if balanceSettleOrNotSettleFlagging == constant.SettleBalance {
-		err = mr.merchantRepoWrites.UpdateMerchantHoldBalanceAndSettleBalance(formattedAdjustMerchantBalance, formattedHoldBalance, payload.MerchantId)
+		err = mr.merchantRepoWrites.UpdateMerchantHoldBalanceAndSettleBalance(adjustedSettleOrNotSettleBalance, holdBalance, payload.MerchantId)
 		if err != nil {
 			resp = dto.ResponseDto{
 				ResponseCode:    http.StatusUnprocessableEntity,
@@ -425,7 +469,7 @@ func (mr *Merchant) HoldBalanceSvc(payload dto.AdjustBalanceReqPayload) (dto.Res
 
 	// if using not settle balance to hold (updated)
 	if balanceSettleOrNotSettleFlagging == constant.NotSettledBalance {
-		err = mr.merchantRepoWrites.UpdateMerchantHoldBalanceAndNotSettleBalance(formattedAdjustMerchantBalance, formattedHoldBalance, payload.MerchantId)
+		err = mr.merchantRepoWrites.UpdateMerchantHoldBalanceAndNotSettleBalance(adjustedSettleOrNotSettleBalance, holdBalance, payload.MerchantId)
 		if err != nil {
 			resp = dto.ResponseDto{
 				ResponseCode:    http.StatusUnprocessableEntity,
@@ -436,10 +480,9 @@ func (mr *Merchant) HoldBalanceSvc(payload dto.AdjustBalanceReqPayload) (dto.Res
 	}
 
 	// create merchant capital flow
-	formattedPayloadAmount := helper.FormatFloat64(float64(payload.Amount))
 	randomStr := helper.GenerateRandomString(30)
 	id := "hld_blnc-" + randomStr
-	tempBalance := helper.FormatFloat64(merchantAccountBalance.BalanceCapitalFlow)
+	tempBalance := merchantAccountBalance.BalanceCapitalFlow
 	payloadMerchantCapitalFlowHoldBalance := dto.CreateMerchantCapitalFlowPayload{
 		PaymentId:         id,
 		MerchantAccountId: merchantAccountBalance.Id,
@@ -447,7 +490,7 @@ func (mr *Merchant) HoldBalanceSvc(payload dto.AdjustBalanceReqPayload) (dto.Res
 		ReasonId:          constant.ReasonIdHoldBalance,
 		Status:            constant.StatusSuccess,
 		CreateBy:          payload.Username,
-		Amount:            formattedPayloadAmount,
+		Amount:            payload.Amount,
 		Notes:             payload.Notes,
 		CapitalType:       constant.CapitalTypeNotDebitNotCredit,
 	}
@@ -460,7 +503,23 @@ func (mr *Merchant) HoldBalanceSvc(payload dto.AdjustBalanceReqPayload) (dto.Res
 		return resp, err
 	}
 
-	merchantAccountAfterHoldBalance, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(payload.MerchantId)
+	// post ledger journal
+	ledgerSourceAccount := constant.LedgerAccountSettled
+	if balanceSettleOrNotSettleFlagging == constant.NotSettledBalance {
+		ledgerSourceAccount = constant.LedgerAccountNotSettled
+	}
+	journal := newLedgerJournal(id, constant.ReasonIdHoldBalance, payload.Username, payload.Notes).
+		move(merchantLedgerAccount(payload.MerchantId, ledgerSourceAccount), merchantLedgerAccount(payload.MerchantId, constant.LedgerAccountHold), payload.Amount)
+	err = postLedgerJournal(mr.ledgerRepoWrites, journal)
+	if err != nil {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: err.Error(),
+		}
+		return resp, err
+	}
+
+	merchantAccountAfterHoldBalance, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(payload.MerchantId)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -480,29 +539,47 @@ func (mr *Merchant) HoldBalanceSvc(payload dto.AdjustBalanceReqPayload) (dto.Res
 }
 
 func (mr *Merchant) SettlementBalanceSvc(payload dto.AdjustBalanceReqPayload) (dto.ResponseDto, error) {
+	request := dto.CreateBalanceOperationRequestPayload{
+		OperationType: constant.BalanceOperationSettlement,
+		MerchantId:    payload.MerchantId,
+		Amount:        payload.Amount,
+		Notes:         payload.Notes,
+		RequestedBy:   payload.Username,
+	}
+
+	return mr.requestBalanceOperation(request, payload.Pin, payload, payload.Actor, func(mrTx *Merchant) (dto.ResponseDto, error) {
+		return mrTx.applySettlement(payload)
+	})
+}
+
+// applySettlement changes the balance, mr has to come from a unit of work
+func (mr *Merchant) applySettlement(payload dto.AdjustBalanceReqPayload) (dto.ResponseDto, error) {
+	return mr.auditBalance(constant.HistoryActivitySettlement, payload.Actor, []string{payload.MerchantId}, func() (dto.ResponseDto, error) {
+		return mr.settlementBalance(payload)
+	})
+}
+
+func (mr *Merchant) settlementBalance(payload dto.AdjustBalanceReqPayload) (dto.ResponseDto, error) {
 	var resp dto.ResponseDto
 
-	// user data
-	user, err := mr.userRepoReads.GetUserByUsername(payload.Username)
+	merchantAccountBalance, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(payload.MerchantId)
 	if err != nil {
 		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
+			ResponseCode:    http.StatusUnprocessableEntity,
 			ResponseMessage: err.Error(),
 		}
 		return resp, err
 	}
 
-	// check input pin
-	if !comparePasswords(user.Pin, []byte(payload.Pin)) {
+	if merchantAccountBalance.Id == 0 {
 		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "wrong pin",
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: "wrong merchant id",
 		}
-
-		return resp, errors.New("wrong pin")
+		return resp, errors.New("wrong merchant id")
 	}
 
-	merchantAccountBalance, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(payload.MerchantId)
+	ledgerAccount, err := ledgerMerchantAccount(mr.ledgerRepoWrites, merchantAccountBalance)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -511,15 +588,7 @@ func (mr *Merchant) SettlementBalanceSvc(payload dto.AdjustBalanceReqPayload) (d
 		return resp, err
 	}
 
-	if merchantAccountBalance.Id == 0 {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusUnprocessableEntity,
-			ResponseMessage: "wrong merchant id",
-		}
-		return resp, errors.New("wrong merchant id")
-	}
-
-	if merchantAccountBalance.NotSettledBalance < float64(payload.Amount) {
+	if ledgerAccount.NotSettledBalance.LessThan(payload.Amount) {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
 			ResponseMessage: "not balance enough",
@@ -531,12 +600,10 @@ func (mr *Merchant) SettlementBalanceSvc(payload dto.AdjustBalanceReqPayload) (d
 	notSettleBalance := merchantAccountBalance.NotSettledBalance
 	settleBalance := merchantAccountBalance.SettledBalance
 
-	notSettleBalanceDebited := notSettleBalance - float64(payload.Amount)
-	settleBalanceCredited := settleBalance + float64(payload.Amount)
-	formattedNoSettleBalance := helper.FormatFloat64(notSettleBalanceDebited)
-	formattedSettleBalance := helper.FormatFloat64(settleBalanceCredited)
+	notSettleBalanceDebited := notSettleBalance.Sub(payload.Amount)
+	settleBalanceCredited := settleBalance.Add(payload.Amount)
 
-	err = mr.merchantRepoWrites.UpdateMerchantSettlement(formattedSettleBalance, formattedNoSettleBalance, merchantAccountBalance.MerchantId)
+	err = mr.merchantRepoWrites.UpdateMerchantSettlement(settleBalanceCredited, notSettleBalanceDebited, merchantAccountBalance.MerchantId)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -546,10 +613,9 @@ func (mr *Merchant) SettlementBalanceSvc(payload dto.AdjustBalanceReqPayload) (d
 	}
 
 	// create merchant capital flow
-	formattedPayloadAmount := helper.FormatFloat64(float64(payload.Amount))
 	randomStr := helper.GenerateRandomString(30)
 	id := "settle_blnc-" + randomStr
-	tempBalance := helper.FormatFloat64(merchantAccountBalance.BalanceCapitalFlow)
+	tempBalance := merchantAccountBalance.BalanceCapitalFlow
 	payloadMerchantCapitalFlowSettlementBalance := dto.CreateMerchantCapitalFlowPayload{
 		PaymentId:         id,
 		MerchantAccountId: merchantAccountBalance.Id,
@@ -557,7 +623,7 @@ func (mr *Merchant) SettlementBalanceSvc(payload dto.AdjustBalanceReqPayload) (d
 		ReasonId:          constant.ReasonIdSettlement,
 		Status:            constant.StatusSuccess,
 		CreateBy:          payload.Username,
-		Amount:            formattedPayloadAmount,
+		Amount:            payload.Amount,
 		Notes:             payload.Notes,
 		CapitalType:       constant.CapitalTypeNotDebitNotCredit,
 	}
@@ -570,7 +636,19 @@ func (mr *Merchant) SettlementBalanceSvc(payload dto.AdjustBalanceReqPayload) (d
 		return resp, err
 	}
 
-	merchantAccountAfterSettlement, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(payload.MerchantId)
+	// post ledger journal
+	journal := newLedgerJournal(id, constant.ReasonIdSettlement, payload.Username, payload.Notes).
+		move(merchantLedgerAccount(payload.MerchantId, constant.LedgerAccountNotSettled), merchantLedgerAccount(payload.MerchantId, constant.LedgerAccountSettled), payload.Amount)
+	err = postLedgerJournal(mr.ledgerRepoWrites, journal)
+	if err != nil {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: err.Error(),
+		}
+		return resp, err
+	}
+
+	merchantAccountAfterSettlement, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(payload.MerchantId)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -590,30 +668,41 @@ func (mr *Merchant) SettlementBalanceSvc(payload dto.AdjustBalanceReqPayload) (d
 }
 
 func (mr *Merchant) BalanceTransferSvc(payload dto.BalanceTrfReqPayload) (dto.ResponseDto, error) {
-	var resp dto.ResponseDto
+	request := dto.CreateBalanceOperationRequestPayload{
+		OperationType:    constant.BalanceOperationTransfer,
+		MerchantId:       payload.AccountFrom.MerchantId,
+		TargetMerchantId: payload.AccountTo.MerchantId,
+		Amount:           payload.Amount,
+		Notes:            payload.Notes,
+		RequestedBy:      payload.Username,
+	}
+
+	return mr.requestBalanceOperation(request, payload.Pin, payload, payload.Actor, func(mrTx *Merchant) (dto.ResponseDto, error) {
+		return mrTx.applyTransfer(payload)
+	})
+}
 
-	// user data
-	user, err := mr.userRepoReads.GetUserByUsername(payload.Username)
+// applyTransfer moves the balance, mr has to come from a unit of work
+func (mr *Merchant) applyTransfer(payload dto.BalanceTrfReqPayload) (dto.ResponseDto, error) {
+	// lock both accounts in a fixed order so opposite transfers can't deadlock
+	err := lockMerchantAccounts(mr.merchantRepoWrites, payload.AccountFrom.MerchantId, payload.AccountTo.MerchantId)
 	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
 			ResponseMessage: err.Error(),
-		}
-		return resp, err
+		}, err
 	}
 
-	// check input pin
-	if !comparePasswords(user.Pin, []byte(payload.Pin)) {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "wrong pin",
-		}
+	return mr.auditBalance(constant.HistoryActivityTransfer, payload.Actor, []string{payload.AccountFrom.MerchantId, payload.AccountTo.MerchantId}, func() (dto.ResponseDto, error) {
+		return mr.balanceTransfer(payload)
+	})
+}
 
-		return resp, errors.New("wrong pin")
-	}
+func (mr *Merchant) balanceTransfer(payload dto.BalanceTrfReqPayload) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
 
 	// adjust balance merchant account from first
-	merchantAccountBalanceFrom, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(payload.AccountFrom.MerchantId)
+	merchantAccountBalanceFrom, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(payload.AccountFrom.MerchantId)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -632,13 +721,11 @@ func (mr *Merchant) BalanceTransferSvc(payload dto.BalanceTrfReqPayload) (dto.Re
 
 	settleBalanceFrom := merchantAccountBalanceFrom.SettledBalance
 	balanceCapitalFrom := merchantAccountBalanceFrom.BalanceCapitalFlow
-	adjustSettleBalanceFrom := settleBalanceFrom - float64(payload.Amount)
-	adjustBalanceCapitalFrom := balanceCapitalFrom - float64(payload.Amount)
-	formattedSettleBalanceFrom := helper.FormatFloat64(adjustSettleBalanceFrom)
-	formattedBalanceCapitalFrom := helper.FormatFloat64(adjustBalanceCapitalFrom)
+	adjustSettleBalanceFrom := settleBalanceFrom.Sub(payload.Amount)
+	adjustBalanceCapitalFrom := balanceCapitalFrom.Sub(payload.Amount)
 
 	// update first merchant balance account from
-	err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formattedSettleBalanceFrom, formattedBalanceCapitalFrom, merchantAccountBalanceFrom.MerchantId)
+	err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(adjustSettleBalanceFrom, adjustBalanceCapitalFrom, merchantAccountBalanceFrom.MerchantId)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -648,18 +735,17 @@ func (mr *Merchant) BalanceTransferSvc(payload dto.BalanceTrfReqPayload) (dto.Re
 	}
 
 	// create merchant capital flow for merchant account from
-	formattedPayloadAmount := helper.FormatFloat64(float64(payload.Amount))
 	randomStr := helper.GenerateRandomString(30)
 	id := "blnc_trf-" + randomStr
 
 	payloadMerchantCapitalFlowAccountFrom := dto.CreateMerchantCapitalFlowPayload{
 		PaymentId:         id,
 		MerchantAccountId: merchantAccountBalanceFrom.Id,
-		TempBalance:       formattedBalanceCapitalFrom,
+		TempBalance:       adjustBalanceCapitalFrom,
 		ReasonId:          constant.ReasonIdBalanceTransfer,
 		Status:            constant.StatusSuccess,
 		CreateBy:          payload.Username,
-		Amount:            formattedPayloadAmount,
+		Amount:            payload.Amount,
 		Notes:             payload.Notes,
 		CapitalType:       constant.CapitalTypeDebit,
 	}
@@ -674,7 +760,7 @@ func (mr *Merchant) BalanceTransferSvc(payload dto.BalanceTrfReqPayload) (dto.Re
 
 	// adjust merchant account to add
 	// get merchant account beneficiary
-	merchantAccountBalanceTo, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(payload.AccountTo.MerchantId)
+	merchantAccountBalanceTo, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(payload.AccountTo.MerchantId)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -693,13 +779,11 @@ func (mr *Merchant) BalanceTransferSvc(payload dto.BalanceTrfReqPayload) (dto.Re
 
 	settleBalanceTo := merchantAccountBalanceTo.SettledBalance
 	balanceCapitalTo := merchantAccountBalanceTo.BalanceCapitalFlow
-	adjustSettleBalanceTo := settleBalanceTo + float64(payload.Amount)
-	adjustBalanceCapitalTo := balanceCapitalTo + float64(payload.Amount)
-	formattedSettleBalanceTo := helper.FormatFloat64(adjustSettleBalanceTo)
-	formattedBalanceCapitalTo := helper.FormatFloat64(adjustBalanceCapitalTo)
+	adjustSettleBalanceTo := settleBalanceTo.Add(payload.Amount)
+	adjustBalanceCapitalTo := balanceCapitalTo.Add(payload.Amount)
 
 	// update merchant balance account to
-	err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formattedSettleBalanceTo, formattedBalanceCapitalTo, merchantAccountBalanceTo.MerchantId)
+	err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(adjustSettleBalanceTo, adjustBalanceCapitalTo, merchantAccountBalanceTo.MerchantId)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -712,11 +796,11 @@ func (mr *Merchant) BalanceTransferSvc(payload dto.BalanceTrfReqPayload) (dto.Re
 	payloadMerchantCapitalFlowAccountTo := dto.CreateMerchantCapitalFlowPayload{
 		PaymentId:         id,
 		MerchantAccountId: merchantAccountBalanceTo.Id,
-		TempBalance:       formattedBalanceCapitalTo,
+		TempBalance:       adjustBalanceCapitalTo,
 		ReasonId:          constant.ReasonIdBalanceTransfer,
 		Status:            constant.StatusSuccess,
 		CreateBy:          payload.Username,
-		Amount:            formattedPayloadAmount,
+		Amount:            payload.Amount,
 		Notes:             payload.Notes,
 		CapitalType:       constant.CapitalTypeCredit,
 	}
@@ -729,6 +813,18 @@ func (mr *Merchant) BalanceTransferSvc(payload dto.BalanceTrfReqPayload) (dto.Re
 		return resp, err
 	}
 
+	// post ledger journal
+	journal := newLedgerJournal(id, constant.ReasonIdBalanceTransfer, payload.Username, payload.Notes).
+		move(merchantLedgerAccount(merchantAccountBalanceFrom.MerchantId, constant.LedgerAccountSettled), merchantLedgerAccount(merchantAccountBalanceTo.MerchantId, constant.LedgerAccountSettled), payload.Amount)
+	err = postLedgerJournal(mr.ledgerRepoWrites, journal)
+	if err != nil {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: err.Error(),
+		}
+		return resp, err
+	}
+
 	msg := fmt.Sprintf("success transfer balance from %v to merchant account %v", merchantAccountBalanceFrom.MerchantId, merchantAccountBalanceTo.MerchantId)
 	resp = dto.ResponseDto{
 		ResponseCode:    http.StatusOK,
@@ -759,7 +855,7 @@ func (mr *Merchant) SendCallbackSvc(paymentId string, username string) (dto.Resp
 		return resp, errors.New("wrong transaction id")
 	}
 
-	merchantData, err := mr.merchantRepoReads.GetMerchantDataByMerchantId(transactionDetail.MerchantId)
+	transactionStatusLatest, err := mr.transactionRepoReads.GetStatusChangeLogData(transactionDetail.PaymentID)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -768,18 +864,19 @@ func (mr *Merchant) SendCallbackSvc(paymentId string, username string) (dto.Resp
 		return resp, err
 	}
 
-	transactionStatusLatest, err := mr.transactionRepoReads.GetStatusChangeLogData(transactionDetail.PaymentID)
+	signingKeys, err := mr.signingKeys(transactionDetail.MerchantId)
 	if err != nil {
+		slog.Errorw("failed get merchant signing keys", "stack_trace", err.Error())
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
-			ResponseMessage: err.Error(),
+			ResponseMessage: constant.GeneralErrMsg,
 		}
 		return resp, err
 	}
 
 	// send callback http
 	callbackStatus := constant.StatusSuccess
-	merchantResponse, err := mr.merchantCallbackAdptr.SendCallbackAdptr(transactionDetail.MerchantCallbackURL, transactionDetail, transactionStatusLatest[0], merchantData.MerchantSecret)
+	merchantResponse, err := mr.merchantCallbackAdptr.SendCallbackAdptr(transactionDetail.MerchantCallbackURL, transactionDetail, transactionStatusLatest[0], signingKeys)
 	if err != nil {
 		callbackStatus = constant.StatusFailed
 		resp = dto.ResponseDto{
@@ -825,6 +922,16 @@ func (mr *Merchant) SendCallbackSvc(paymentId string, username string) (dto.Resp
 		return resp, err
 	}
 
+	// the merchant got the callback, stop the automatic delivery of this payment
+	err = mr.merchantRepoWrites.MarkMerchantCallbackDeliveredRepo(transactionDetail.PaymentID)
+	if err != nil {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: err.Error(),
+		}
+		return resp, err
+	}
+
 	msg := fmt.Sprintf("success send callback for transaction id: %v", paymentId)
 	resp = dto.ResponseDto{
 		ResponseCode:    http.StatusOK,
@@ -835,29 +942,30 @@ func (mr *Merchant) SendCallbackSvc(paymentId string, username string) (dto.Resp
 }
 
 func (mr *Merchant) PayoutSettlementSvc(payload dto.AdjustBalanceReqPayload) (dto.ResponseDto, error) {
-	var resp dto.ResponseDto
-
-	// user data
-	user, err := mr.userRepoReads.GetUserByUsername(payload.Username)
-	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: err.Error(),
-		}
-		return resp, err
+	request := dto.CreateBalanceOperationRequestPayload{
+		OperationType: constant.BalanceOperationPayoutSettlement,
+		MerchantId:    payload.MerchantId,
+		Amount:        payload.Amount,
+		Notes:         payload.Notes,
+		RequestedBy:   payload.Username,
 	}
 
-	// check input pin
-	if !comparePasswords(user.Pin, []byte(payload.Pin)) {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "wrong pin",
-		}
+	return mr.requestBalanceOperation(request, payload.Pin, payload, payload.Actor, func(mrTx *Merchant) (dto.ResponseDto, error) {
+		return mrTx.applyPayoutSettlement(payload)
+	})
+}
 
-		return resp, errors.New("wrong pin")
-	}
+// applyPayoutSettlement changes the balance, mr has to come from a unit of work
+func (mr *Merchant) applyPayoutSettlement(payload dto.AdjustBalanceReqPayload) (dto.ResponseDto, error) {
+	return mr.auditBalance(constant.HistoryActivityPayoutSettlement, payload.Actor, []string{payload.MerchantId}, func() (dto.ResponseDto, error) {
+		return mr.payoutSettlement(payload)
+	})
+}
 
-	merchantAccountBalance, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(payload.MerchantId)
+func (mr *Merchant) payoutSettlement(payload dto.AdjustBalanceReqPayload) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	merchantAccountBalance, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(payload.MerchantId)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -874,13 +982,11 @@ func (mr *Merchant) PayoutSettlementSvc(payload dto.AdjustBalanceReqPayload) (dt
 		return resp, errors.New("wrong merchant id")
 	}
 
-	outBalance := merchantAccountBalance.SettledBalance - float64(payload.Amount)
-	balanceCapitalFlowMinusPayoutSettlement := merchantAccountBalance.BalanceCapitalFlow - float64(payload.Amount)
-	formattedOutBalance := helper.FormatFloat64(outBalance)
-	formattedBalanceCapitalFlowMinusPayoutSettlement := helper.FormatFloat64(balanceCapitalFlowMinusPayoutSettlement)
+	outBalance := merchantAccountBalance.SettledBalance.Sub(payload.Amount)
+	balanceCapitalFlowMinusPayoutSettlement := merchantAccountBalance.BalanceCapitalFlow.Sub(payload.Amount)
 
 	// update merchant account balance
-	err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formattedOutBalance, formattedBalanceCapitalFlowMinusPayoutSettlement, payload.MerchantId)
+	err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(outBalance, balanceCapitalFlowMinusPayoutSettlement, payload.MerchantId)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -890,17 +996,16 @@ func (mr *Merchant) PayoutSettlementSvc(payload dto.AdjustBalanceReqPayload) (dt
 	}
 
 	// create merchant capital flow
-	formattedPayloadAmount := helper.FormatFloat64(float64(payload.Amount))
 	randomStr := helper.GenerateRandomString(30)
 	id := "out_sttlmnt-" + randomStr
 	payloadMerchantCapitalFlowTopUp := dto.CreateMerchantCapitalFlowPayload{
 		PaymentId:         id,
 		MerchantAccountId: merchantAccountBalance.Id,
-		TempBalance:       formattedBalanceCapitalFlowMinusPayoutSettlement,
+		TempBalance:       balanceCapitalFlowMinusPayoutSettlement,
 		ReasonId:          constant.ReasonIdOutSettlement,
 		Status:            constant.StatusSuccess,
 		CreateBy:          payload.Username,
-		Amount:            formattedPayloadAmount,
+		Amount:            payload.Amount,
 		Notes:             payload.Notes,
 		CapitalType:       constant.CapitalTypeDebit,
 	}
@@ -913,7 +1018,19 @@ func (mr *Merchant) PayoutSettlementSvc(payload dto.AdjustBalanceReqPayload) (dt
 		return resp, err
 	}
 
-	merchantAccountAfterOutSettlement, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(payload.MerchantId)
+	// post ledger journal
+	journal := newLedgerJournal(id, constant.ReasonIdOutSettlement, payload.Username, payload.Notes).
+		move(merchantLedgerAccount(payload.MerchantId, constant.LedgerAccountSettled), platformLedgerAccount(constant.LedgerAccountClearing), payload.Amount)
+	err = postLedgerJournal(mr.ledgerRepoWrites, journal)
+	if err != nil {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: err.Error(),
+		}
+		return resp, err
+	}
+
+	merchantAccountAfterOutSettlement, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(payload.MerchantId)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -1091,28 +1208,57 @@ func (mr *Merchant) GetListMerchantWithFilterSvc(params dto.QueryParams) (dto.Re
 }
 
 func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction, username string) (dto.ResponseDto, error) {
-	var resp dto.ResponseDto
-
-	// user data
-	user, err := mr.userRepoReads.GetUserByUsername(username)
+	manualPaymentData, err := mr.merchantRepoReads.GetDetailManualPayment(payload.PaymentId)
 	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadGateway,
-			ResponseMessage: err.Error(),
-		}
-		return resp, err
+		slog.Infof("get manual payment data error: %v", err.Error())
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: "oops there is something error please ask customer support for more detail",
+		}, err
 	}
 
-	// check input pin
-	if !comparePasswords(user.Pin, []byte(payload.Pin)) {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "wrong pin",
-		}
+	if len(manualPaymentData) < 1 {
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: "data not found maybe wrong transaction id",
+		}, nil
+	}
 
-		return resp, errors.New("wrong pin")
+	request := dto.CreateBalanceOperationRequestPayload{
+		OperationType: constant.BalanceOperationReverse,
+		MerchantId:    manualPaymentData[0].MerchantId,
+		PaymentId:     payload.PaymentId,
+		Amount:        manualPaymentData[0].Amount,
+		Notes:         payload.Notes,
+		RequestedBy:   username,
 	}
 
+	return mr.requestBalanceOperation(request, payload.Pin, payload, payload.Actor, func(mrTx *Merchant) (dto.ResponseDto, error) {
+		return mrTx.applyReverse(payload, username)
+	})
+}
+
+// applyReverse reverses the manual payment, mr has to come from a unit of work
+func (mr *Merchant) applyReverse(payload dto.UpdateStatusTransaction, username string) (dto.ResponseDto, error) {
+	// the accounts the reverse touches, an unknown payment is refused by reverseManualPayment
+	manualPaymentData, err := mr.merchantRepoReads.GetDetailManualPayment(payload.PaymentId)
+	if err != nil {
+		slog.Infof("get manual payment data error: %v", err.Error())
+	}
+
+	merchantIds := make([]string, len(manualPaymentData))
+	for i, manualPayment := range manualPaymentData {
+		merchantIds[i] = manualPayment.MerchantId
+	}
+
+	return mr.auditBalance(constant.HistoryActivityReverse, payload.Actor, merchantIds, func() (dto.ResponseDto, error) {
+		return mr.reverseManualPayment(payload, username)
+	})
+}
+
+func (mr *Merchant) reverseManualPayment(payload dto.UpdateStatusTransaction, username string) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
 	manualPaymentData, err := mr.merchantRepoReads.GetDetailManualPayment(payload.PaymentId)
 	if err != nil {
 		slog.Infof("get manual payment data error: %v", err.Error())
@@ -1186,7 +1332,7 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 			}
 		}
 
-		creditorMerchantAccount, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(balanceTrfCreditor.MerchantId)
+		creditorMerchantAccount, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(balanceTrfCreditor.MerchantId)
 		if err != nil {
 			slog.Infof("creditor merchant account got error: %v", err.Error())
 			resp = dto.ResponseDto{
@@ -1197,13 +1343,10 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 		}
 
 		// adjust balance creditor
-		creditorBalanceCapitalMinusBalanceTrf := creditorMerchantAccount.BalanceCapitalFlow - balanceTrfCreditor.Amount
-		creditorSettleBalanceMinusBalanceTrf := creditorMerchantAccount.SettledBalance - balanceTrfCreditor.Amount
-		formattedBalanceCapitalCreditor := helper.FormatFloat64(creditorBalanceCapitalMinusBalanceTrf)
-		formattedSettleBalanceCreditor := helper.FormatFloat64(creditorSettleBalanceMinusBalanceTrf)
-		formattedCreditorAmount := helper.FormatFloat64(balanceTrfCreditor.Amount)
+		creditorBalanceCapitalMinusBalanceTrf := creditorMerchantAccount.BalanceCapitalFlow.Sub(balanceTrfCreditor.Amount)
+		creditorSettleBalanceMinusBalanceTrf := creditorMerchantAccount.SettledBalance.Sub(balanceTrfCreditor.Amount)
 
-		err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formattedSettleBalanceCreditor, formattedBalanceCapitalCreditor, balanceTrfCreditor.MerchantId)
+		err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(creditorSettleBalanceMinusBalanceTrf, creditorBalanceCapitalMinusBalanceTrf, balanceTrfCreditor.MerchantId)
 		if err != nil {
 			resp = dto.ResponseDto{
 				ResponseCode:    http.StatusUnprocessableEntity,
@@ -1219,11 +1362,11 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 		payloadMerchantCapitalCreditor := dto.CreateMerchantCapitalFlowPayload{
 			PaymentId:         id,
 			MerchantAccountId: creditorMerchantAccount.Id,
-			TempBalance:       formattedBalanceCapitalCreditor,
+			TempBalance:       creditorBalanceCapitalMinusBalanceTrf,
 			ReasonId:          balanceTrfCreditor.ReasonId,
 			Status:            constant.StatusReversed,
 			CreateBy:          username,
-			Amount:            formattedCreditorAmount,
+			Amount:            balanceTrfCreditor.Amount,
 			Notes:             reverseNotes,
 			CapitalType:       constant.CapitalTypeDebit,
 			ReverseFrom:       balanceTrfCreditor.PaymentId,
@@ -1237,7 +1380,7 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 			return resp, err
 		}
 
-		debitorMerchantAccount, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(balanceTrfDebitor.MerchantId)
+		debitorMerchantAccount, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(balanceTrfDebitor.MerchantId)
 		if err != nil {
 			slog.Infof("creditor merchant account got error: %v", err.Error())
 			resp = dto.ResponseDto{
@@ -1248,14 +1391,12 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 		}
 
 		// adjust balance debitor
-		debitorBalanceCapitalAddBalanceTrf := debitorMerchantAccount.BalanceCapitalFlow + balanceTrfDebitor.Amount
-		debitorSettleBalanceAddBalanceTrf := debitorMerchantAccount.SettledBalance + balanceTrfDebitor.Amount
-		formattedDebitorAmount := helper.FormatFloat64(balanceTrfDebitor.Amount)
-		formattedBalanceCapitalDebitor := helper.FormatFloat64(debitorBalanceCapitalAddBalanceTrf)
-		formatedSettleBalanceDebitor := helper.FormatFloat64(debitorSettleBalanceAddBalanceTrf)
+		debitorBalanceCapitalAddBalanceTrf := debitorMerchantAccount.BalanceCapitalFlow.Add(balanceTrfDebitor.Amount)
+		debitorSettleBalanceAddBalanceTrf := debitorMerchantAccount.SettledBalance.Add(balanceTrfDebitor.Amount)
+		formatedSettleBalanceDebitor := debitorSettleBalanceAddBalanceTrf
 
 		// update merchant balance debitor
-		err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formatedSettleBalanceDebitor, formattedBalanceCapitalDebitor, balanceTrfDebitor.MerchantId)
+		err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formatedSettleBalanceDebitor, debitorBalanceCapitalAddBalanceTrf, balanceTrfDebitor.MerchantId)
 		if err != nil {
 			resp = dto.ResponseDto{
 				ResponseCode:    http.StatusUnprocessableEntity,
@@ -1268,11 +1409,11 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 		payloadMerchantCapitalDebitor := dto.CreateMerchantCapitalFlowPayload{
 			PaymentId:         id,
 			MerchantAccountId: debitorMerchantAccount.Id,
-			TempBalance:       formattedBalanceCapitalDebitor,
+			TempBalance:       debitorBalanceCapitalAddBalanceTrf,
 			ReasonId:          balanceTrfDebitor.ReasonId,
 			Status:            constant.StatusReversed,
 			CreateBy:          username,
-			Amount:            formattedDebitorAmount,
+			Amount:            balanceTrfDebitor.Amount,
 			Notes:             reverseNotes,
 			CapitalType:       constant.CapitalTypeCredit,
 			ReverseFrom:       balanceTrfCreditor.PaymentId,
@@ -1286,6 +1427,18 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 			return resp, err
 		}
 
+		// post ledger journal
+		journal := newLedgerJournal(id, balanceTrfCreditor.ReasonId, username, reverseNotes).
+			move(merchantLedgerAccount(balanceTrfCreditor.MerchantId, constant.LedgerAccountSettled), merchantLedgerAccount(balanceTrfDebitor.MerchantId, constant.LedgerAccountSettled), balanceTrfCreditor.Amount)
+		err = postLedgerJournal(mr.ledgerRepoWrites, journal)
+		if err != nil {
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: err.Error(),
+			}
+			return resp, err
+		}
+
 		msg := fmt.Sprintf("success reversed with id %v for balance trf id %v", id, balanceTrfCreditor.PaymentId)
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusOK,
@@ -1295,7 +1448,7 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 
 	if len(manualPaymentData) == 1 {
 		if manualPaymentData[0].ReasonId == constant.ReasonIdTopUp {
-			merchantAccountBalance, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(manualPaymentData[0].MerchantId)
+			merchantAccountBalance, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(manualPaymentData[0].MerchantId)
 			if err != nil {
 				resp = dto.ResponseDto{
 					ResponseCode:    http.StatusUnprocessableEntity,
@@ -1305,13 +1458,11 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 			}
 
 			// reverse balance
-			settleBalanceMinusTopup := merchantAccountBalance.SettledBalance - manualPaymentData[0].Amount
-			balanceCapitalMinusTopup := merchantAccountBalance.BalanceCapitalFlow - manualPaymentData[0].Amount
-			formattedTopupAmount := helper.FormatFloat64(manualPaymentData[0].Amount)
-			formattedSettleBalanceMinusTopup := helper.FormatFloat64(settleBalanceMinusTopup)
-			formattedBalanceCapitalMinusTopup := helper.FormatFloat64(balanceCapitalMinusTopup)
+			settleBalanceMinusTopup := merchantAccountBalance.SettledBalance.Sub(manualPaymentData[0].Amount)
+			balanceCapitalMinusTopup := merchantAccountBalance.BalanceCapitalFlow.Sub(manualPaymentData[0].Amount)
+			formattedTopupAmount := manualPaymentData[0].Amount
 
-			err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formattedSettleBalanceMinusTopup, formattedBalanceCapitalMinusTopup, merchantAccountBalance.MerchantId)
+			err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(settleBalanceMinusTopup, balanceCapitalMinusTopup, merchantAccountBalance.MerchantId)
 			if err != nil {
 				resp = dto.ResponseDto{
 					ResponseCode:    http.StatusUnprocessableEntity,
@@ -1327,7 +1478,7 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 			payloadMerchantCapitalTopup := dto.CreateMerchantCapitalFlowPayload{
 				PaymentId:         id,
 				MerchantAccountId: merchantAccountBalance.Id,
-				TempBalance:       formattedBalanceCapitalMinusTopup,
+				TempBalance:       balanceCapitalMinusTopup,
 				ReasonId:          manualPaymentData[0].ReasonId,
 				Status:            constant.StatusReversed,
 				CreateBy:          username,
@@ -1345,6 +1496,18 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 				return resp, err
 			}
 
+			// post ledger journal
+			journal := newLedgerJournal(id, manualPaymentData[0].ReasonId, username, reverseNotes).
+				move(merchantLedgerAccount(merchantAccountBalance.MerchantId, constant.LedgerAccountSettled), platformLedgerAccount(constant.LedgerAccountClearing), formattedTopupAmount)
+			err = postLedgerJournal(mr.ledgerRepoWrites, journal)
+			if err != nil {
+				resp = dto.ResponseDto{
+					ResponseCode:    http.StatusUnprocessableEntity,
+					ResponseMessage: err.Error(),
+				}
+				return resp, err
+			}
+
 			msg := fmt.Sprintf("success reverse with id %v, for top up id %v", id, manualPaymentData[0].PaymentId)
 			resp = dto.ResponseDto{
 				ResponseCode:    http.StatusOK,
@@ -1353,7 +1516,7 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 		}
 
 		if manualPaymentData[0].ReasonId == constant.ReasonIdHoldBalance {
-			merchantAccountBalance, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(manualPaymentData[0].MerchantId)
+			merchantAccountBalance, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(manualPaymentData[0].MerchantId)
 			if err != nil {
 				resp = dto.ResponseDto{
 					ResponseCode:    http.StatusUnprocessableEntity,
@@ -1363,14 +1526,11 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 			}
 
 			// reverse balance for hold balance
-			formattedBalanceCapitalHoldBalance := helper.FormatFloat64(merchantAccountBalance.BalanceCapitalFlow)
-			holdBalanceMinusAmount := merchantAccountBalance.HoldBalance - manualPaymentData[0].Amount
-			settleBalanceAddAmount := merchantAccountBalance.SettledBalance + manualPaymentData[0].Amount
-			formattedHoldBalanceMinusAmount := helper.FormatFloat64(holdBalanceMinusAmount)
-			formattedSettleBalanceAddAmount := helper.FormatFloat64(settleBalanceAddAmount)
-			formattedAmountHoldBalance := helper.FormatFloat64(manualPaymentData[0].Amount)
-
-			err = mr.merchantRepoWrites.UpdateMerchantHoldBalanceAndSettleBalance(formattedSettleBalanceAddAmount, formattedHoldBalanceMinusAmount, merchantAccountBalance.MerchantId)
+			holdBalanceMinusAmount := merchantAccountBalance.HoldBalance.Sub(manualPaymentData[0].Amount)
+			settleBalanceAddAmount := merchantAccountBalance.SettledBalance.Add(manualPaymentData[0].Amount)
+			formattedAmountHoldBalance := manualPaymentData[0].Amount
+
+			err = mr.merchantRepoWrites.UpdateMerchantHoldBalanceAndSettleBalance(settleBalanceAddAmount, holdBalanceMinusAmount, merchantAccountBalance.MerchantId)
 			if err != nil {
 				resp = dto.ResponseDto{
 					ResponseCode:    http.StatusUnprocessableEntity,
@@ -1386,7 +1546,7 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 			payloadMerchantCapitalHoldBalance := dto.CreateMerchantCapitalFlowPayload{
 				PaymentId:         id,
 				MerchantAccountId: merchantAccountBalance.Id,
-				TempBalance:       formattedBalanceCapitalHoldBalance,
+				TempBalance:       merchantAccountBalance.BalanceCapitalFlow,
 				ReasonId:          manualPaymentData[0].ReasonId,
 				Status:            constant.StatusReversed,
 				CreateBy:          username,
@@ -1404,6 +1564,18 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 				return resp, err
 			}
 
+			// post ledger journal
+			journal := newLedgerJournal(id, manualPaymentData[0].ReasonId, username, reverseNotes).
+				move(merchantLedgerAccount(merchantAccountBalance.MerchantId, constant.LedgerAccountHold), merchantLedgerAccount(merchantAccountBalance.MerchantId, constant.LedgerAccountSettled), formattedAmountHoldBalance)
+			err = postLedgerJournal(mr.ledgerRepoWrites, journal)
+			if err != nil {
+				resp = dto.ResponseDto{
+					ResponseCode:    http.StatusUnprocessableEntity,
+					ResponseMessage: err.Error(),
+				}
+				return resp, err
+			}
+
 			msg := fmt.Sprintf("success reverse with id %v, for hold balance id %v", id, manualPaymentData[0].PaymentId)
 			resp = dto.ResponseDto{
 				ResponseCode:    http.StatusOK,
@@ -1412,7 +1584,7 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 		}
 
 		if manualPaymentData[0].ReasonId == constant.ReasonIdSettlement {
-			merchantAccountBalance, err := mr.merchantRepoReads.GetMerchantAccountByMerchantId(manualPaymentData[0].MerchantId)
+			merchantAccountBalance, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(manualPaymentData[0].MerchantId)
 			if err != nil {
 				resp = dto.ResponseDto{
 					ResponseCode:    http.StatusUnprocessableEntity,
@@ -1422,15 +1594,12 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 			}
 
 			// reverse balance
-			formattedBalanceCapitalMerchant := helper.FormatFloat64(merchantAccountBalance.BalanceCapitalFlow)
-			settleBalanceMinusAmount := merchantAccountBalance.SettledBalance - manualPaymentData[0].Amount
-			notSettleBalanceAddAmount := merchantAccountBalance.NotSettledBalance + manualPaymentData[0].Amount
-			formattedSettleBalanceMinusAmount := helper.FormatFloat64(settleBalanceMinusAmount)
-			formattedNotSettleBalanceAddAmount := helper.FormatFloat64(notSettleBalanceAddAmount)
-			formattedSettlementAmount := helper.FormatFloat64(manualPaymentData[0].Amount)
+			settleBalanceMinusAmount := merchantAccountBalance.SettledBalance.Sub(manualPaymentData[0].Amount)
+			notSettleBalanceAddAmount := merchantAccountBalance.NotSettledBalance.Add(manualPaymentData[0].Amount)
+			formattedSettlementAmount := manualPaymentData[0].Amount
 
 			// update settle balance
-			err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formattedSettleBalanceMinusAmount, formattedBalanceCapitalMerchant, manualPaymentData[0].MerchantId)
+			err = mr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(settleBalanceMinusAmount, merchantAccountBalance.BalanceCapitalFlow, manualPaymentData[0].MerchantId)
 			if err != nil {
 				resp = dto.ResponseDto{
 					ResponseCode:    http.StatusUnprocessableEntity,
@@ -1440,7 +1609,7 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 			}
 
 			// update not settle balance
-			err = mr.merchantRepoWrites.UpdateMerchantCapitalAndNotSettleBalance(formattedNotSettleBalanceAddAmount, formattedBalanceCapitalMerchant, manualPaymentData[0].MerchantId)
+			err = mr.merchantRepoWrites.UpdateMerchantCapitalAndNotSettleBalance(notSettleBalanceAddAmount, merchantAccountBalance.BalanceCapitalFlow, manualPaymentData[0].MerchantId)
 			if err != nil {
 				resp = dto.ResponseDto{
 					ResponseCode:    http.StatusUnprocessableEntity,
@@ -1455,7 +1624,7 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 			payloadMerchantCapitalSettlement := dto.CreateMerchantCapitalFlowPayload{
 				PaymentId:         id,
 				MerchantAccountId: merchantAccountBalance.Id,
-				TempBalance:       formattedBalanceCapitalMerchant,
+				TempBalance:       merchantAccountBalance.BalanceCapitalFlow,
 				ReasonId:          manualPaymentData[0].ReasonId,
 				Status:            constant.StatusReversed,
 				CreateBy:          username,
@@ -1473,6 +1642,18 @@ func (mr *Merchant) ReverseManualPaymentSvc(payload dto.UpdateStatusTransaction,
 				return resp, err
 			}
 
+			// post ledger journal
+			journal := newLedgerJournal(id, manualPaymentData[0].ReasonId, username, reverseNotes).
+				move(merchantLedgerAccount(manualPaymentData[0].MerchantId, constant.LedgerAccountSettled), merchantLedgerAccount(manualPaymentData[0].MerchantId, constant.LedgerAccountNotSettled), formattedSettlementAmount)
+			err = postLedgerJournal(mr.ledgerRepoWrites, journal)
+			if err != nil {
+				resp = dto.ResponseDto{
+					ResponseCode:    http.StatusUnprocessableEntity,
+					ResponseMessage: err.Error(),
+				}
+				return resp, err
+			}
+
 			msg := fmt.Sprintf("success reverse with id %v, for settlement id %v", id, manualPaymentData[0].PaymentId)
 			resp = dto.ResponseDto{
 				ResponseCode:    http.StatusOK,
@@ -1507,10 +1688,22 @@ func (mr *Merchant) GetMerchantBalanceSvc(merchantId string) (dto.ResponseDto, e
 		return resp, err
 	}
 
+	// balances are derived from ledger postings
+	ledgerBalances, err := mr.ledgerRepoReads.GetLedgerBalancesByOwnerRepo(constant.LedgerOwnerMerchant, merchantId)
+	if err != nil {
+		slog.Infof("got error GetLedgerBalancesByOwnerRepo: %v", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: "oopss got error, please ask customer support for more detail",
+		}
+
+		return resp, err
+	}
+
 	resp = dto.ResponseDto{
 		ResponseCode:    http.StatusOK,
 		ResponseMessage: "success retrive merchant balance data",
-		Data:            merchantBalanceData,
+		Data:            merchantAccountFromLedger(merchantBalanceData, ledgerBalances),
 	}
 
 	return resp, nil
@@ -1519,11 +1712,9 @@ func (mr *Merchant) GetMerchantBalanceSvc(merchantId string) (dto.ResponseDto, e
 func (mr *Merchant) CreateMerchantSvc(payload dto.CreateMerchantDtoReq) (dto.ResponseDto, error) {
 	var resp dto.ResponseDto
 	randomStrMerchantId := helper.GenerateRandomString(10)
-	randomStrMerchantScret := helper.GenerateRandomString(30)
 	merchantId := "hypy_mrchnt-" + randomStrMerchantId
-	merchantSecret := "secret_key-" + randomStrMerchantScret
 
-	createMerchantId, err := mr.merchantRepoWrites.CreateMerchantRepo(payload.MerchantName, merchantId, merchantSecret)
+	createMerchantId, err := mr.merchantRepoWrites.CreateMerchantRepo(payload.MerchantName, merchantId)
 	if err != nil {
 		msg := fmt.Sprintf("failed to create merchant with err: %v", err.Error())
 		resp = dto.ResponseDto{
@@ -1533,6 +1724,27 @@ func (mr *Merchant) CreateMerchantSvc(payload dto.CreateMerchantDtoReq) (dto.Res
 		return resp, err
 	}
 
+	merchantKey, err := mr.newMerchantKey(merchantId, 1, constant.CreateBySystem)
+	if err != nil {
+		slog.Errorw("failed generate merchant key", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+	merchantKey.Status = constant.MerchantKeyActive
+
+	_, err = mr.merchantRepoWrites.CreateMerchantKeyRepo(merchantKey, 0)
+	if err != nil {
+		msg := fmt.Sprintf("failed to create merchant key with err: %v", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: msg,
+		}
+		return resp, err
+	}
+
 	_, err = mr.merchantRepoWrites.CreateMerchantAccountsRepo(merchantId)
 	if err != nil {
 		msg := fmt.Sprintf("failed to create merchant account with err: %v", err.Error())
@@ -1706,37 +1918,48 @@ func (mr *Merchant) MerchantUpdateStatusSvc(payload dto.AccountData) (dto.Respon
 
 	merchantStatus := merchantData.Status
 
-	var msgRes string
-	if merchantStatus == constant.StatusActive {
-		// updated to inactive
-		msgRes = fmt.Sprintf("Merchant %v has been successfully deactived", payload.MerchantId)
-		err := mr.merchantRepoWrites.UpdateMerchantStatusRepo(payload.MerchantId, constant.StatusInactive)
-		if err != nil {
-			res = dto.ResponseDto{
-				ResponseCode:    http.StatusUnprocessableEntity,
-				ResponseMessage: err.Error(),
-			}
-			return res, err
-		}
-	} else {
+	// updated to inactive
+	statusAfter := constant.StatusInactive
+	msgRes := fmt.Sprintf("Merchant %v has been successfully deactived", payload.MerchantId)
+	if merchantStatus != constant.StatusActive {
 		// update to active
+		statusAfter = constant.StatusActive
 		msgRes = fmt.Sprintf("Merchant %v has been successfully actived", payload.MerchantId)
-		err := mr.merchantRepoWrites.UpdateMerchantStatusRepo(payload.MerchantId, constant.StatusActive)
+	}
+
+	// the status and its trail are committed together
+	return runInUnitOfWork(mr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		mrTx := mr.withUnitOfWork(repos)
+
+		err := mrTx.merchantRepoWrites.UpdateMerchantStatusRepo(payload.MerchantId, statusAfter)
 		if err != nil {
-			res = dto.ResponseDto{
+			return dto.ResponseDto{
 				ResponseCode:    http.StatusUnprocessableEntity,
 				ResponseMessage: err.Error(),
-			}
-			return res, err
+			}, err
 		}
-	}
 
-	res = dto.ResponseDto{
-		ResponseCode:    http.StatusOK,
-		ResponseMessage: msgRes,
-	}
+		err = mrTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeMerchant,
+			Activity:    constant.HistoryActivityUpdateStatus,
+			TargetId:    payload.MerchantId,
+			Before:      map[string]string{"status": merchantStatus},
+			After:       map[string]string{"status": statusAfter},
+			Actor:       payload.Actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record merchant status change", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
 
-	return res, nil
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: msgRes,
+		}, nil
+	})
 }
 
 func (mr *Merchant) GetMerchantPaychannelSvc(merchantId string) (dto.ResponseDto, error) {
@@ -1942,11 +2165,11 @@ func (mr *Merchant) GetRoutedPaychannelSvc(merchantPaychannelId int) (dto.Respon
 	// convert payload for response
 	for i := range routedPaychannelList {
 		if routedPaychannelList[i].FeeType == constant.FeeTypePercentage {
-			feeDb := converter.ToString(routedPaychannelList[i].FeesDb)
+			feeDb := routedPaychannelList[i].FeesDb.String()
 			feeRes := feeDb + "%"
 			routedPaychannelList[i].FeeResp = feeRes
 		} else {
-			feeDb := converter.ToString(routedPaychannelList[i].FeesDb)
+			feeDb := routedPaychannelList[i].FeesDb.String()
 			routedPaychannelList[i].FeeResp = feeDb
 		}
 	}
@@ -2032,34 +2255,50 @@ func (mr *Merchant) UpdateLimitOrFeeMerchantPaychannelSvc(payload dto.AdjustLimi
 		payload.FeeType = &merchantPaychannelData.FeeType
 	}
 
-	// Update merchant pay channel
-	err = mr.merchantRepoWrites.UpdateMerchantPaychannelByIdRepo(payload)
-	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusInternalServerError,
-			ResponseMessage: fmt.Sprintf("Failed to update merchant pay channel: %v", err),
-		}
-		return resp, err
-	}
+	// the reads repo doesn't see the update before commit, the pay channel after the update is the one read
+	// before it with the new limits and fee
+	merchantPaychannelDataUpdated := merchantPaychannelData
+	merchantPaychannelDataUpdated.MaxTransaction = *payload.MaxAmount
+	merchantPaychannelDataUpdated.MinTransaction = *payload.MinAmount
+	merchantPaychannelDataUpdated.MaxDailyTransaction = *payload.MaxDailyLimit
+	merchantPaychannelDataUpdated.Fee = *payload.Fee
+	merchantPaychannelDataUpdated.FeeType = *payload.FeeType
 
-	// Fetch updated merchant pay channel details
-	merchantPaychannelDataUpdated, err := mr.merchantRepoReads.GetMerchantPaychannelDetailById(payload.MerchantPaychannelId)
-	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusInternalServerError,
-			ResponseMessage: fmt.Sprintf("Failed to retrieve updated merchant pay channel details: %v", err),
-		}
-		return resp, err
-	}
+	return runInUnitOfWork(mr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		mrTx := mr.withUnitOfWork(repos)
 
-	msg := fmt.Sprintf("Successfully updated merchant pay channel with id: %v", payload.MerchantPaychannelId)
-	resp = dto.ResponseDto{
-		ResponseCode:    http.StatusOK,
-		ResponseMessage: msg,
-		Data:            merchantPaychannelDataUpdated,
-	}
+		// Update merchant pay channel
+		err := mrTx.merchantRepoWrites.UpdateMerchantPaychannelByIdRepo(payload)
+		if err != nil {
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusInternalServerError,
+				ResponseMessage: fmt.Sprintf("Failed to update merchant pay channel: %v", err),
+			}, err
+		}
+
+		err = mrTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeMerchantPaychannel,
+			Activity:    constant.HistoryActivityUpdateFeeLimit,
+			TargetId:    converter.ToString(payload.MerchantPaychannelId),
+			Before:      merchantPaychannelData,
+			After:       merchantPaychannelDataUpdated,
+			Actor:       payload.Actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record merchant pay channel fee limit change", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
 
-	return resp, nil
+		msg := fmt.Sprintf("Successfully updated merchant pay channel with id: %v", payload.MerchantPaychannelId)
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: msg,
+			Data:            merchantPaychannelDataUpdated,
+		}, nil
+	})
 }
 
 func (mr *Merchant) GetAggregatedPaychannelSvc(paychannelId int) (dto.ResponseDto, error) {
@@ -2099,10 +2338,10 @@ func (mr *Merchant) AddSegmentMerchantPaychannelSvc(payload dto.AddSegmentDtoReq
 	method := constant.TransformPaymentMethodNameIntoCode[paychannel.PaymentMethodChannel]
 	randomStrMerchantChannelCode := helper.GenerateRandomString(15)
 	merchantPaychannelCode := method + "-" + strings.ToUpper(payload.TierName) + "-" + randomStrMerchantChannelCode
-	minAmount := 0.00
-	maxAmount := 0.00
-	dailyLimit := 0.00
-	fee := 0.00
+	minAmount := money.Money{}
+	maxAmount := money.Money{}
+	dailyLimit := money.Money{}
+	fee := money.Money{}
 	feeType := constant.FeeTypeFixedFee
 
 	_, err = mr.merchantRepoWrites.CreateMerchantPaychannelRepo(paychannel.MerchantPaymentMethodId, payload.TierName, fee, feeType, minAmount, maxAmount, dailyLimit, merchantPaychannelCode)
@@ -2160,24 +2399,41 @@ func (mr *Merchant) ActivateOrDeactivateMerchantPaychannelSvc(payload dto.Accoun
 		return resp, errors.New("must have routed paychannel first")
 	}
 
-	// update merchant paychannel status
-	err = mr.merchantRepoWrites.UpdateStatusMerchantPaychannelById(payload.MerchantPaychannelId, status)
-	if err != nil {
-		slog.Infof("merchant paychannel ID %v got err when update status %v", payload.MerchantPaychannelId, err.Error())
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusUnprocessableEntity,
-			ResponseMessage: "Please contact support for more info",
-		}
-		return resp, err
-	}
+	return runInUnitOfWork(mr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		mrTx := mr.withUnitOfWork(repos)
 
-	msg := fmt.Sprintf("Merchant paychannel id %v, success to %v", payload.MerchantPaychannelId, status)
-	resp = dto.ResponseDto{
-		ResponseCode:    http.StatusOK,
-		ResponseMessage: msg,
-	}
+		// update merchant paychannel status
+		err := mrTx.merchantRepoWrites.UpdateStatusMerchantPaychannelById(payload.MerchantPaychannelId, status)
+		if err != nil {
+			slog.Infof("merchant paychannel ID %v got err when update status %v", payload.MerchantPaychannelId, err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: "Please contact support for more info",
+			}, err
+		}
+
+		err = mrTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeMerchantPaychannel,
+			Activity:    constant.HistoryActivityUpdateStatus,
+			TargetId:    converter.ToString(payload.MerchantPaychannelId),
+			Before:      map[string]string{"status": merchanPaychannelData.Status},
+			After:       map[string]string{"status": status},
+			Actor:       payload.Actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record merchant pay channel status change", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
 
-	return resp, nil
+		msg := fmt.Sprintf("Merchant paychannel id %v, success to %v", payload.MerchantPaychannelId, status)
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: msg,
+		}, nil
+	})
 }
 
 func (mr *Merchant) AddChannelSvc(id int, payload []dto.PaymentMethodData) (dto.ResponseDto, error) {
@@ -2275,8 +2531,7 @@ func (mr *Merchant) GetListMerchantPaymentMethodsSvc(idMerchant int) (dto.Respon
 func (mr *Merchant) AddRoutingPaychannelSvc(merchantPaychannelId int, payload dto.AddPaychannelRouting) (dto.ResponseDto, error) {
 	var resp dto.ResponseDto
 
-	// delete paychannel routing
-	err := mr.merchantRepoWrites.DeleteRoutingPaychannelByMerchantPaychannelId(merchantPaychannelId)
+	routedList, err := mr.merchantRepoReads.GetListRoutedPaychannelByIdMerchantPaychannelRepo(merchantPaychannelId)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -2285,24 +2540,56 @@ func (mr *Merchant) AddRoutingPaychannelSvc(merchantPaychannelId int, payload dt
 		return resp, err
 	}
 
-	// add paychannel routing
-	for _, providerPaychannelId := range payload.ProviderPaychannelId {
-		_, err := mr.merchantRepoWrites.AddRoutingPaychannelRepo(merchantPaychannelId, providerPaychannelId)
+	routedIds := make([]int, len(routedList))
+	for i, routed := range routedList {
+		routedIds[i] = routed.ProviderPaychannelId
+	}
+
+	// a routing replaced halfway would leave the pay channel without its providers
+	return runInUnitOfWork(mr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		mrTx := mr.withUnitOfWork(repos)
+
+		// delete paychannel routing
+		err := mrTx.merchantRepoWrites.DeleteRoutingPaychannelByMerchantPaychannelId(merchantPaychannelId)
 		if err != nil {
-			resp = dto.ResponseDto{
+			return dto.ResponseDto{
 				ResponseCode:    http.StatusUnprocessableEntity,
 				ResponseMessage: err.Error(),
+			}, err
+		}
+
+		// add paychannel routing
+		for _, providerPaychannelId := range payload.ProviderPaychannelId {
+			_, err := mrTx.merchantRepoWrites.AddRoutingPaychannelRepo(merchantPaychannelId, providerPaychannelId)
+			if err != nil {
+				return dto.ResponseDto{
+					ResponseCode:    http.StatusUnprocessableEntity,
+					ResponseMessage: err.Error(),
+				}, err
 			}
-			return resp, err
 		}
-	}
 
-	resp = dto.ResponseDto{
-		ResponseCode:    http.StatusOK,
-		ResponseMessage: "Success updated routing paychannel",
-	}
+		err = mrTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeMerchantPaychannel,
+			Activity:    constant.HistoryActivityReplaceRouting,
+			TargetId:    converter.ToString(merchantPaychannelId),
+			Before:      map[string][]int{"providerPaychannelIds": routedIds},
+			After:       map[string][]int{"providerPaychannelIds": payload.ProviderPaychannelId},
+			Actor:       payload.Actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record merchant pay channel routing change", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
 
-	return resp, nil
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "Success updated routing paychannel",
+		}, nil
+	})
 }
 
 func (mr *Merchant) GetActiveAvailablePaychannelSvc(merchantPaychannelId int) (dto.ResponseDto, error) {
@@ -2434,39 +2721,39 @@ func (mr *Merchant) DisplaySecretKeySvc(pin string, username string, merchantId
 	}
 
 	// check input pin
-	if !comparePasswords(user.Pin, []byte(pin)) {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "wrong pin",
-		}
-
-		return resp, errors.New("wrong pin")
+	resp, err = mr.credentialGuard.VerifyPin(user, pin)
+	if err != nil {
+		return resp, err
 	}
 
-	secretKey, err := mr.merchantRepoReads.GetSecretKeyByMerchantIdRepo(merchantId)
+	keys, err := mr.openLiveMerchantKeys(merchantId)
 	if err != nil {
+		slog.Errorw("failed get merchant key", "stack_trace", err.Error())
 		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: err.Error(),
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
 		}
 		return resp, err
 	}
 
+	// secretKey stays the active key, keys adds the next and retiring keys that sign callbacks next to it
 	resp = dto.ResponseDto{
 		ResponseCode:    http.StatusOK,
 		ResponseMessage: "Success retrieve secret key",
-		Data:            secretKey,
+		Data: dto.MerchantKeysDto{
+			SecretKey: keys[0].Secret,
+			Version:   keys[0].Version,
+			Keys:      keys,
+		},
 	}
 
 	return resp, nil
 }
 
-func (mr *Merchant) GenerateSecretKeySvc(pin string, username string, merchantId string) (dto.ResponseDto, error) {
+func (mr *Merchant) GenerateSecretKeySvc(payload dto.RotateMerchantKeyPayload) (dto.ResponseDto, error) {
 	var resp dto.ResponseDto
-	randomStrMerchantScret := helper.GenerateRandomString(30)
-	merchantSecret := "secret_key-" + randomStrMerchantScret
 
-	user, err := mr.userRepoReads.GetUserByUsername(username)
+	user, err := mr.userRepoReads.GetUserByUsername(payload.Username)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusBadGateway,
@@ -2476,30 +2763,12 @@ func (mr *Merchant) GenerateSecretKeySvc(pin string, username string, merchantId
 	}
 
 	// check input pin
-	if !comparePasswords(user.Pin, []byte(pin)) {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "wrong pin",
-		}
-
-		return resp, errors.New("wrong pin")
-	}
-
-	err = mr.merchantRepoWrites.UpdateMerchantSecretKeyRepo(merchantSecret, merchantId)
+	resp, err = mr.credentialGuard.VerifyPin(user, payload.Pin)
 	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusUnprocessableEntity,
-			ResponseMessage: err.Error(),
-		}
 		return resp, err
 	}
 
-	resp = dto.ResponseDto{
-		ResponseCode:    http.StatusOK,
-		ResponseMessage: "success generated new secret key",
-	}
-
-	return resp, nil
+	return mr.rotateMerchantKey(payload)
 }
 
 func (mr *Merchant) GetMerchantAccountBalanceSvc(username string) (dto.ResponseDto, error) {
@@ -2523,10 +2792,20 @@ func (mr *Merchant) GetMerchantAccountBalanceSvc(username string) (dto.ResponseD
 		return resp, err
 	}
 
+	// balances are derived from ledger postings
+	ledgerBalances, err := mr.ledgerRepoReads.GetLedgerBalancesByOwnerRepo(constant.LedgerOwnerMerchant, *user.MerchantID)
+	if err != nil {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: err.Error(),
+		}
+		return resp, err
+	}
+
 	resp = dto.ResponseDto{
 		ResponseCode:    http.StatusOK,
 		ResponseMessage: "Success retrieve data",
-		Data:            accountBalance,
+		Data:            merchantAccountFromLedger(accountBalance, ledgerBalances),
 	}
 
 	return resp, nil
@@ -2578,16 +2857,12 @@ func (mr *Merchant) DisplayMerchantKeySvc(username string, pin string) (dto.Resp
 	}
 
 	// check input pin
-	if !comparePasswords(user.Pin, []byte(pin)) {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "wrong pin",
-		}
-
-		return resp, errors.New("wrong pin")
+	resp, err = mr.credentialGuard.VerifyPin(user, pin)
+	if err != nil {
+		return resp, err
 	}
 
-	secretKey, err := mr.merchantRepoReads.GetSecretKeyByMerchantIdRepo(*user.MerchantID)
+	keys, err := mr.openLiveMerchantKeys(*user.MerchantID)
 	if err != nil {
 		slog.Errorw("failed get merchant key", "stack_trace", err.Error())
 		resp = dto.ResponseDto{
@@ -2597,21 +2872,24 @@ func (mr *Merchant) DisplayMerchantKeySvc(username string, pin string) (dto.Resp
 		return resp, err
 	}
 
+	// secretKey stays the active key, keys adds the next and retiring keys that sign callbacks next to it
 	resp = dto.ResponseDto{
 		ResponseCode:    http.StatusOK,
 		ResponseMessage: "Success retrieve secret key",
-		Data:            secretKey,
+		Data: dto.MerchantKeysDto{
+			SecretKey: keys[0].Secret,
+			Version:   keys[0].Version,
+			Keys:      keys,
+		},
 	}
 
 	return resp, nil
 }
 
-func (mr *Merchant) GenerateMerchantKeySvc(pin string, username string) (dto.ResponseDto, error) {
+func (mr *Merchant) GenerateMerchantKeySvc(payload dto.RotateMerchantKeyPayload) (dto.ResponseDto, error) {
 	var resp dto.ResponseDto
-	randomStrMerchantScret := helper.GenerateRandomString(30)
-	merchantSecret := "secret_key-" + randomStrMerchantScret
 
-	user, err := mr.userRepoReads.GetUserByUsername(username)
+	user, err := mr.userRepoReads.GetUserByUsername(payload.Username)
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusBadGateway,
@@ -2621,34 +2899,17 @@ func (mr *Merchant) GenerateMerchantKeySvc(pin string, username string) (dto.Res
 	}
 
 	// check input pin
-	if !comparePasswords(user.Pin, []byte(pin)) {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "wrong pin",
-		}
-
-		return resp, errors.New("wrong pin")
-	}
-
-	err = mr.merchantRepoWrites.UpdateMerchantSecretKeyRepo(merchantSecret, *user.MerchantID)
+	resp, err = mr.credentialGuard.VerifyPin(user, payload.Pin)
 	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusUnprocessableEntity,
-			ResponseMessage: err.Error(),
-		}
 		return resp, err
 	}
 
-	resp = dto.ResponseDto{
-		ResponseCode:    http.StatusOK,
-		ResponseMessage: "success generated new secret key",
-	}
-
-	return resp, nil
+	payload.MerchantId = *user.MerchantID
+	return mr.rotateMerchantKey(payload)
 }
 
 func supportMerchantAnalyticsSvc(payload []entity.PaymentDetailMerchantProvider) dto.AnalyticsMerchantRespDto {
-	var totalVolumeSuccessIn float64
+	var totalVolumeSuccessIn money.Money
 	var totalSuccessTransactionIn int
 	var totalFailedTransactionIn int
 	var totalProcessingTransactionIn int
@@ -2657,7 +2918,7 @@ func supportMerchantAnalyticsSvc(payload []entity.PaymentDetailMerchantProvider)
 	var totalDurationIn time.Duration
 	var totalCompletedIn int
 
-	var totalVolumeSuccessOut float64
+	var totalVolumeSuccessOut money.Money
 	var totalSuccessTransactionOut int
 	var totalFailedTransactionOut int
 	var totalProcessingTransactionOut int
@@ -2670,7 +2931,7 @@ func supportMerchantAnalyticsSvc(payload []entity.PaymentDetailMerchantProvider)
 		if transaction.PayType == constant.PayTypePayin {
 			totalTransactionIn++
 			if transaction.Status == constant.StatusSuccess {
-				totalVolumeSuccessIn += transaction.TransactionAmount
+				totalVolumeSuccessIn = totalVolumeSuccessIn.Add(transaction.TransactionAmount)
 				totalSuccessTransactionIn++
 
 				// completion rate
@@ -2699,7 +2960,7 @@ func supportMerchantAnalyticsSvc(payload []entity.PaymentDetailMerchantProvider)
 		if transaction.PayType == constant.PayTypePayout {
 			totalTransactionOut++
 			if transaction.Status == constant.StatusSuccess {
-				totalVolumeSuccessOut += transaction.TransactionAmount
+				totalVolumeSuccessOut = totalVolumeSuccessOut.Add(transaction.TransactionAmount)
 				totalSuccessTransactionOut++
 
 				// completion rate
@@ -2782,7 +3043,7 @@ func supportMerchantAnalyticsSvc(payload []entity.PaymentDetailMerchantProvider)
 }
 
 func supportMerchantAnalyticsByMerchantPaychannelSvc(payload []entity.PaymentDetailMerchantProvider) dto.AnalyticsDataRespDto {
-	var totalVolumeSuccess float64
+	var totalVolumeSuccess money.Money
 	var successRate float64
 	var totalSuccess int
 	var totalFailed int
@@ -2794,7 +3055,7 @@ func supportMerchantAnalyticsByMerchantPaychannelSvc(payload []entity.PaymentDet
 	for _, transaction := range payload {
 		totalTransaction++
 		if transaction.Status == constant.StatusSuccess {
-			totalVolumeSuccess += transaction.TransactionAmount
+			totalVolumeSuccess = totalVolumeSuccess.Add(transaction.TransactionAmount)
 			totalSuccess++
 
 			// completion rate
@@ -2850,54 +3111,54 @@ func supportMerchantAnalyticsByMerchantPaychannelSvc(payload []entity.PaymentDet
 
 func supportHomeAnalyticsSvc(payload []entity.PaymentDetailMerchantProvider) dto.HomeAnalyticsRespDto {
 	var totalNumberTransactionIn int
-	var totalAmountTransactionIn float64
+	var totalAmountTransactionIn money.Money
 	var totalNumberTransactionOut int
-	var totalAmountTransactionOut float64
+	var totalAmountTransactionOut money.Money
 
 	// Qris
 	var totalNumberQris int
-	var totalAmountQris float64
+	var totalAmountQris money.Money
 
 	// ewallet
 	var totalNumberEwallet int
-	var totalAmountEwallet float64
+	var totalAmountEwallet money.Money
 
 	// virtual account
 	var totalNumberVa int
-	var totalAmountVa float64
+	var totalAmountVa money.Money
 
 	// disbursement
 	var totalNumberDisbursement int
-	var totalAmountDisbursement float64
+	var totalAmountDisbursement money.Money
 
 	for _, transaction := range payload {
 		if transaction.PayType == constant.PayTypePayin {
 			totalNumberTransactionIn++
-			totalAmountTransactionIn += transaction.TransactionAmount
+			totalAmountTransactionIn = totalAmountTransactionIn.Add(transaction.TransactionAmount)
 
 			if transaction.PaymentMethodName == constant.QrisPaymentMethod {
 				totalNumberQris++
-				totalAmountQris += transaction.TransactionAmount
+				totalAmountQris = totalAmountQris.Add(transaction.TransactionAmount)
 			}
 
 			if transaction.PaymentMethodName == constant.VirtualAccountPaymentMethod {
 				totalNumberVa++
-				totalAmountVa += transaction.TransactionAmount
+				totalAmountVa = totalAmountVa.Add(transaction.TransactionAmount)
 			}
 
 			if transaction.PaymentMethodName == constant.EwalletPaymentMethod {
 				totalNumberEwallet++
-				totalAmountEwallet += transaction.TransactionAmount
+				totalAmountEwallet = totalAmountEwallet.Add(transaction.TransactionAmount)
 			}
 		}
 
 		if transaction.PayType == constant.PayTypePayout {
 			totalNumberTransactionOut++
-			totalAmountTransactionOut += transaction.TransactionAmount
+			totalAmountTransactionOut = totalAmountTransactionOut.Add(transaction.TransactionAmount)
 
 			if transaction.PaymentMethodName == constant.DisbursementPaymentMethod {
 				totalNumberDisbursement++
-				totalAmountDisbursement += transaction.TransactionAmount
+				totalAmountDisbursement = totalAmountDisbursement.Add(transaction.TransactionAmount)
 			}
 		}
 	}
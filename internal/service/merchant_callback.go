@@ -0,0 +1,93 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+// DeliverMerchantCallbacksSvc sends the queued merchant callbacks that are due, a failed attempt is retried
+// after constant.DelayBasedOnCounter and the delivery is dead lettered once constant.MaxRetrySyncStatus retries failed
+func (mr *Merchant) DeliverMerchantCallbacksSvc() error {
+	deliveries, err := mr.merchantRepoWrites.ClaimDueMerchantCallbackDeliveriesRepo(constant.CallbackDeliveryBatchSize, constant.CallbackDeliveryLease)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		err = mr.deliverMerchantCallback(delivery)
+		if err != nil {
+			// the claim lease runs out and the delivery is picked up again
+			slog.Errorw(fmt.Sprintf("merchant callback delivery %v of %v failed", delivery.Id, delivery.PaymentId), "stack_trace", err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (mr *Merchant) deliverMerchantCallback(delivery entity.MerchantCallbackDelivery) error {
+	attemptNumber := delivery.AttemptCount + 1
+
+	transactionDetail, err := mr.transactionRepoReads.GetPaymentDetailProviderMerchant(delivery.PaymentId)
+	if err != nil {
+		return err
+	}
+
+	transactionStatusLogs, err := mr.transactionRepoReads.GetStatusChangeLogData(delivery.PaymentId)
+	if err != nil {
+		return err
+	}
+
+	if len(transactionStatusLogs) < 1 {
+		return fmt.Errorf("payment id %v has no status log", delivery.PaymentId)
+	}
+
+	signingKeys, err := mr.signingKeys(transactionDetail.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// send callback http
+	callbackStatus := constant.StatusSuccess
+	deliveryStatus := constant.CallbackDeliveryDelivered
+	var nextRetryDelay time.Duration
+
+	merchantResponse, err := mr.merchantCallbackAdptr.SendCallbackAdptr(transactionDetail.MerchantCallbackURL, transactionDetail, transactionStatusLogs[0], signingKeys)
+	callbackResult, _ := merchantResponse.(string)
+	if err != nil {
+		callbackStatus = constant.StatusFailed
+		deliveryStatus = constant.CallbackDeliveryDeadLetter
+		if attemptNumber <= constant.MaxRetrySyncStatus {
+			deliveryStatus = constant.CallbackDeliveryPending
+			nextRetryDelay = constant.DelayBasedOnCounter[attemptNumber]
+		}
+
+		if callbackResult == "" {
+			callbackResult = err.Error()
+		}
+	}
+
+	slog.Infof("merchant callback delivery %v of %v attempt %v: %v", delivery.Id, delivery.PaymentId, attemptNumber, deliveryStatus)
+
+	return mr.unitOfWork.WithinTransaction(func(repos internal.UnitOfWorkRepos) error {
+		_, err := repos.MerchantWrites.CreateMerchantCallbackAttemptRepo(dto.CreateMerchantCallbackAttemptPayload{
+			DeliveryId:              delivery.Id,
+			AttemptNumber:           attemptNumber,
+			PaymentId:               delivery.PaymentId,
+			CallbackStatus:          callbackStatus,
+			PaymentStatusInCallback: transactionDetail.Status,
+			CallbackResult:          callbackResult,
+			NextRetryDelay:          nextRetryDelay,
+		})
+		if err != nil {
+			return err
+		}
+
+		return repos.MerchantWrites.UpdateMerchantCallbackDeliveryRepo(delivery.Id, deliveryStatus, attemptNumber, nextRetryDelay, callbackResult)
+	})
+}
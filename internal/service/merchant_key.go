@@ -0,0 +1,300 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/envelope"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+// GetLiveMerchantKeysSvc lists the key versions that currently sign the callbacks of a merchant, without
+// their secrets
+func (mr *Merchant) GetLiveMerchantKeysSvc(merchantId string, username string) (dto.ResponseDto, error) {
+	merchantId, resp, err := scopedMerchantId(mr.userRepoReads, merchantId, username)
+	if err != nil {
+		return resp, err
+	}
+
+	keys, err := mr.merchantRepoReads.GetLiveMerchantKeysRepo(merchantId)
+	if err != nil {
+		slog.Errorw("failed get live merchant keys", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	for i := range keys {
+		keys[i].Secret = ""
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "success retrieve live merchant keys",
+		Data:            keys,
+	}
+
+	return resp, nil
+}
+
+// RotateMerchantKeysSvc activates the next keys whose schedule has passed and retires the keys whose overlap
+// window ran out
+func (mr *Merchant) RotateMerchantKeysSvc() error {
+	dueKeys, err := mr.merchantRepoWrites.GetDueMerchantKeysRepo()
+	if err != nil {
+		return err
+	}
+
+	for _, dueKey := range dueKeys {
+		_, err = runInUnitOfWork(mr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+			mrTx := mr.withUnitOfWork(repos)
+
+			keys, err := mrTx.merchantRepoWrites.GetMerchantKeysForUpdateRepo(dueKey.MerchantId)
+			if err != nil {
+				return dto.ResponseDto{}, err
+			}
+
+			// the key may have been replaced by a newer schedule while waiting for the lock
+			for _, key := range keys {
+				if key.Id == dueKey.Id && key.Status == constant.MerchantKeyNext {
+					return dto.ResponseDto{}, mrTx.activateMerchantKey(keys, key)
+				}
+			}
+
+			return dto.ResponseDto{}, nil
+		})
+		if err != nil {
+			slog.Errorw(fmt.Sprintf("failed activate key version %v of %v", dueKey.Version, dueKey.MerchantId), "stack_trace", err.Error())
+			continue
+		}
+
+		slog.Infof("merchant %v key version %v activated", dueKey.MerchantId, dueKey.Version)
+	}
+
+	return mr.merchantRepoWrites.RetireExpiredMerchantKeysRepo()
+}
+
+// rotateMerchantKey schedules a new key for the merchant, it replaces a next key that wasn't activated yet
+// and is activated right away when no activation time is asked for
+func (mr *Merchant) rotateMerchantKey(payload dto.RotateMerchantKeyPayload) (dto.ResponseDto, error) {
+	var activateIn time.Duration
+	if payload.ActivateAt != nil {
+		activateIn = time.Until(*payload.ActivateAt)
+	}
+
+	overlap := constant.MerchantKeyOverlap
+	if payload.OverlapHours != 0 {
+		overlap = time.Duration(payload.OverlapHours) * time.Hour
+	}
+
+	if activateIn > constant.MerchantKeyMaxSchedule || overlap < 0 || overlap > constant.MerchantKeyMaxOverlap {
+		msg := fmt.Sprintf("activation can be scheduled up to %v days ahead and overlap up to %v hours", constant.MerchantKeyMaxSchedule/constant.OneDay, constant.MerchantKeyMaxOverlap.Hours())
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: msg,
+		}, errors.New(msg)
+	}
+
+	if activateIn < 0 {
+		activateIn = 0
+	}
+
+	return runInUnitOfWork(mr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		mrTx := mr.withUnitOfWork(repos)
+		var resp dto.ResponseDto
+
+		keys, err := mrTx.merchantRepoWrites.GetMerchantKeysForUpdateRepo(payload.MerchantId)
+		if err != nil {
+			slog.Errorw("failed get merchant keys", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		var version int
+		for _, key := range keys {
+			if key.Version > version {
+				version = key.Version
+			}
+
+			if key.Status != constant.MerchantKeyNext {
+				continue
+			}
+
+			// a next key never signed alone, merchants can't depend on it yet
+			err = mrTx.merchantRepoWrites.RetireMerchantKeyRepo(key.Id, constant.MerchantKeyRetired, 0)
+			if err != nil {
+				slog.Errorw("failed retire next merchant key", "stack_trace", err.Error())
+				resp = dto.ResponseDto{
+					ResponseCode:    http.StatusUnprocessableEntity,
+					ResponseMessage: constant.GeneralErrMsg,
+				}
+				return resp, err
+			}
+		}
+
+		nextKey, err := mr.newMerchantKey(payload.MerchantId, version+1, payload.Username)
+		if err != nil {
+			slog.Errorw("failed generate merchant key", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+		nextKey.OverlapSeconds = int(overlap.Seconds())
+
+		nextKey.Id, err = mrTx.merchantRepoWrites.CreateMerchantKeyRepo(nextKey, activateIn)
+		if err != nil {
+			slog.Errorw("failed create merchant key", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		msg := fmt.Sprintf("new secret key version %v is scheduled, it signs callbacks next to the active key until it's activated", nextKey.Version)
+		nextKeyStatus := constant.MerchantKeyNext
+		if activateIn == 0 {
+			err = mrTx.activateMerchantKey(keys, nextKey)
+			if err != nil {
+				slog.Errorw("failed activate merchant key", "stack_trace", err.Error())
+				resp = dto.ResponseDto{
+					ResponseCode:    http.StatusUnprocessableEntity,
+					ResponseMessage: constant.GeneralErrMsg,
+				}
+				return resp, err
+			}
+
+			msg = fmt.Sprintf("success generated new secret key version %v", nextKey.Version)
+			nextKeyStatus = constant.MerchantKeyActive
+		}
+
+		err = mrTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeMerchant,
+			Activity:    constant.HistoryActivityRotateKey,
+			TargetId:    payload.MerchantId,
+			After: map[string]interface{}{
+				"version":      nextKey.Version,
+				"status":       nextKeyStatus,
+				"activateAt":   time.Now().Add(activateIn),
+				"overlapHours": overlap.Hours(),
+			},
+			Actor: payload.Actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record merchant key rotation", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: msg,
+		}
+
+		return resp, nil
+	})
+}
+
+// activateMerchantKey makes nextKey the active key, the key it replaces keeps signing callbacks for the
+// overlap asked for when nextKey was scheduled
+func (mr *Merchant) activateMerchantKey(keys []entity.MerchantKeyEntity, nextKey entity.MerchantKeyEntity) error {
+	overlap := time.Duration(nextKey.OverlapSeconds) * time.Second
+	retireStatus := constant.MerchantKeyRetiring
+	if overlap == 0 {
+		retireStatus = constant.MerchantKeyRetired
+	}
+
+	for _, key := range keys {
+		if key.Status != constant.MerchantKeyActive {
+			continue
+		}
+
+		err := mr.merchantRepoWrites.RetireMerchantKeyRepo(key.Id, retireStatus, overlap)
+		if err != nil {
+			return err
+		}
+	}
+
+	return mr.merchantRepoWrites.ActivateMerchantKeyRepo(nextKey.Id)
+}
+
+// newMerchantKey generates a merchant secret from crypto/rand sealed for storage
+func (mr *Merchant) newMerchantKey(merchantId string, version int, createdBy string) (entity.MerchantKeyEntity, error) {
+	token, err := generateSecureToken(32)
+	if err != nil {
+		return entity.MerchantKeyEntity{}, err
+	}
+
+	secret, err := mr.keyring.Seal("secret_key-"+token, merchantKeyData(merchantId, version))
+	if err != nil {
+		return entity.MerchantKeyEntity{}, err
+	}
+
+	return entity.MerchantKeyEntity{
+		MerchantId: merchantId,
+		Version:    version,
+		Secret:     secret,
+		Status:     constant.MerchantKeyNext,
+		CreatedBy:  createdBy,
+	}, nil
+}
+
+// merchantKeyData binds a sealed secret to the merchant key it belongs to
+func merchantKeyData(merchantId string, version int) string {
+	return envelope.AdditionalData("merchant_keys.secret", merchantId, version)
+}
+
+// openLiveMerchantKeys returns the keys that sign the callbacks of merchantId with their secrets opened,
+// the active key comes first
+func (mr *Merchant) openLiveMerchantKeys(merchantId string) ([]entity.MerchantKeyEntity, error) {
+	keys, err := mr.merchantRepoReads.GetLiveMerchantKeysRepo(merchantId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 || keys[0].Status != constant.MerchantKeyActive {
+		return nil, fmt.Errorf("merchant %v has no active key", merchantId)
+	}
+
+	for i := range keys {
+		keys[i].Secret, err = mr.keyring.Open(keys[i].Secret, merchantKeyData(merchantId, keys[i].Version))
+		if err != nil {
+			return nil, fmt.Errorf("key version %v of merchant %v: %w", keys[i].Version, merchantId, err)
+		}
+	}
+
+	return keys, nil
+}
+
+func (mr *Merchant) signingKeys(merchantId string) ([]dto.MerchantSigningKey, error) {
+	keys, err := mr.openLiveMerchantKeys(merchantId)
+	if err != nil {
+		return nil, err
+	}
+
+	signingKeys := make([]dto.MerchantSigningKey, len(keys))
+	for i, key := range keys {
+		signingKeys[i] = dto.MerchantSigningKey{
+			Version: key.Version,
+			Secret:  key.Secret,
+		}
+	}
+
+	return signingKeys, nil
+}
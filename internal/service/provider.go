@@ -14,6 +14,7 @@ import (
 	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
 )
 
@@ -23,7 +24,9 @@ type Provider struct {
 	providerRepoReads    internal.ProviderReadsRepositoryItf
 	providerRepoWrites   internal.ProviderWritesRepositoryItf
 	userRepoReads        internal.UserReadsRepositoryItf
+	unitOfWork           internal.UnitOfWorkItf
 	config               config.App
+	auditor              *Auditor
 }
 
 func NewProvider(
@@ -32,7 +35,9 @@ func NewProvider(
 	providerRepoReads internal.ProviderReadsRepositoryItf,
 	providerRepoWrites internal.ProviderWritesRepositoryItf,
 	userRepoReads internal.UserReadsRepositoryItf,
+	unitOfWork internal.UnitOfWorkItf,
 	config config.App,
+	auditor *Auditor,
 ) *Provider {
 	return &Provider{
 		transactionRepoReads: transactionRepoReads,
@@ -40,7 +45,9 @@ func NewProvider(
 		providerRepoReads:    providerRepoReads,
 		providerRepoWrites:   providerRepoWrites,
 		userRepoReads:        userRepoReads,
+		unitOfWork:           unitOfWork,
 		config:               config,
+		auditor:              auditor,
 	}
 }
 
@@ -252,32 +259,50 @@ func (pr *Provider) UpdateFeeLimitInterfaceProviderChannelSvc(payload dto.Adjust
 		payload.InterfaceSetting = &providerPaychannelData.InterfaceSetting
 	}
 
-	// update provider channel
-	err = pr.providerRepoWrites.UpdateProviderPaychannelByIdRepo(payload)
-	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusUnprocessableEntity,
-			ResponseMessage: err.Error(),
-		}
-		return resp, err
-	}
+	// the reads repo doesn't see the update before commit, the provider channel after the update is the one
+	// read before it with the new limits and fee
+	providerChannelUpdated := providerPaychannelData
+	providerChannelUpdated.MaxAmount = *payload.MaxAmount
+	providerChannelUpdated.MinAmount = *payload.MinAmount
+	providerChannelUpdated.MaxDailyLimit = *payload.MaxDailyLimit
+	providerChannelUpdated.Fee = *payload.Fee
+	providerChannelUpdated.FeeType = *payload.FeeType
+	providerChannelUpdated.InterfaceSetting = *payload.InterfaceSetting
 
-	providerChannelUpdated, err := pr.providerRepoReads.GetDetailProviderChannelById(payload.ProviderChannelId)
-	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusUnprocessableEntity,
-			ResponseMessage: err.Error(),
+	return runInUnitOfWork(pr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		prTx := pr.withUnitOfWork(repos)
+
+		// update provider channel
+		err := prTx.providerRepoWrites.UpdateProviderPaychannelByIdRepo(payload)
+		if err != nil {
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: err.Error(),
+			}, err
 		}
-		return resp, err
-	}
 
-	resp = dto.ResponseDto{
-		ResponseCode:    http.StatusOK,
-		ResponseMessage: fmt.Sprintf("Successfully updated merchant pay channel with id: %v", payload.ProviderChannelId),
-		Data:            providerChannelUpdated,
-	}
+		err = prTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeProviderPaychannel,
+			Activity:    constant.HistoryActivityUpdateFeeLimit,
+			TargetId:    converter.ToString(payload.ProviderChannelId),
+			Before:      providerPaychannelData,
+			After:       providerChannelUpdated,
+			Actor:       payload.Actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record provider pay channel fee limit change", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
 
-	return resp, nil
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: fmt.Sprintf("Successfully updated merchant pay channel with id: %v", payload.ProviderChannelId),
+			Data:            providerChannelUpdated,
+		}, nil
+	})
 }
 
 func (pr *Provider) GetProviderChannelOperatorSvc(providerChannelId int) (dto.ResponseDto, error) {
@@ -482,7 +507,7 @@ func (pr *Provider) ActiveOrDeactivateProviderPaychannelIdSvc(payload dto.Update
 	}
 
 	// check if fee already set
-	if providerPaychannelDetail.Fee == 0 {
+	if providerPaychannelDetail.Fee.IsZero() {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
 			ResponseMessage: "can't activate need have to set fee first",
@@ -490,22 +515,39 @@ func (pr *Provider) ActiveOrDeactivateProviderPaychannelIdSvc(payload dto.Update
 		return resp, errors.New("need to set fee")
 	}
 
-	// update status provider paychannel
-	err = pr.providerRepoWrites.UpdateStatusProviderPaychannelRepo(payload.ProviderChannelId, status)
-	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusUnprocessableEntity,
-			ResponseMessage: err.Error(),
+	return runInUnitOfWork(pr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		prTx := pr.withUnitOfWork(repos)
+
+		// update status provider paychannel
+		err := prTx.providerRepoWrites.UpdateStatusProviderPaychannelRepo(payload.ProviderChannelId, status)
+		if err != nil {
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: err.Error(),
+			}, err
 		}
-		return resp, err
-	}
 
-	resp = dto.ResponseDto{
-		ResponseCode:    http.StatusOK,
-		ResponseMessage: fmt.Sprintf("success update status for provider paychannel id %v", payload.ProviderChannelId),
-	}
+		err = prTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeProviderPaychannel,
+			Activity:    constant.HistoryActivityUpdateStatus,
+			TargetId:    converter.ToString(payload.ProviderChannelId),
+			Before:      map[string]string{"status": providerPaychannelDetail.Status},
+			After:       map[string]string{"status": status},
+			Actor:       payload.Actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record provider pay channel status change", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
 
-	return resp, nil
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: fmt.Sprintf("success update status for provider paychannel id %v", payload.ProviderChannelId),
+		}, nil
+	})
 }
 
 func (pr *Provider) GetListInterfaceProviderSvc(params dto.QueryParams) (dto.ResponseDto, error) {
@@ -573,22 +615,22 @@ func (pr *Provider) CreateProviderChannelSvc(payload dto.CreateProviderChannelDt
 	}
 
 	if payload.MinAmount == nil {
-		defaultMinAmount := 0.0
+		defaultMinAmount := money.Money{}
 		payload.MinAmount = &defaultMinAmount
 	}
 
 	if payload.MaxAmount == nil {
-		defaultMaxAmount := 0.0
+		defaultMaxAmount := money.Money{}
 		payload.MaxAmount = &defaultMaxAmount
 	}
 
 	if payload.DailyLimit == nil {
-		defaultDailyLimit := 0.0
+		defaultDailyLimit := money.Money{}
 		payload.DailyLimit = &defaultDailyLimit
 	}
 
 	if payload.Fee == nil {
-		defaultFee := 0.0
+		defaultFee := money.Money{}
 		payload.Fee = &defaultFee
 	}
 
@@ -649,7 +691,7 @@ func (pr *Provider) CreateProviderChannelSvc(payload dto.CreateProviderChannelDt
 }
 
 func supportProviderAnalyticsSvc(payload []entity.PaymentDetailMerchantProvider) dto.AnalyticsProviderRespDto {
-	var totalVolumeSuccessIn float64
+	var totalVolumeSuccessIn money.Money
 	var totalSuccessTransactionIn int
 	var totalFailedTransactionIn int
 	var totalProcessingTransactionIn int
@@ -658,7 +700,7 @@ func supportProviderAnalyticsSvc(payload []entity.PaymentDetailMerchantProvider)
 	var totalDurationIn time.Duration
 	var totalCompletedIn int
 
-	var totalVolumeSuccessOut float64
+	var totalVolumeSuccessOut money.Money
 	var totalSuccessTransactionOut int
 	var totalFailedTransactionOut int
 	var totalProcessingTransactionOut int
@@ -671,7 +713,7 @@ func supportProviderAnalyticsSvc(payload []entity.PaymentDetailMerchantProvider)
 		if transaction.PayType == constant.PayTypePayin {
 			totalTransactionIn++
 			if transaction.Status == constant.StatusSuccess {
-				totalVolumeSuccessIn += transaction.TransactionAmount
+				totalVolumeSuccessIn = totalVolumeSuccessIn.Add(transaction.TransactionAmount)
 				totalSuccessTransactionIn++
 
 				// completion rate
@@ -700,7 +742,7 @@ func supportProviderAnalyticsSvc(payload []entity.PaymentDetailMerchantProvider)
 		if transaction.PayType == constant.PayTypePayout {
 			totalTransactionOut++
 			if transaction.Status == constant.StatusSuccess {
-				totalVolumeSuccessOut += transaction.TransactionAmount
+				totalVolumeSuccessOut = totalVolumeSuccessOut.Add(transaction.TransactionAmount)
 				totalSuccessTransactionOut++
 
 				// completion rate
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+type Reconciliation struct {
+	reconciliationRepoReads  internal.ReconciliationReadsRepositoryItf
+	reconciliationRepoWrites internal.ReconciliationWritesRepositoryItf
+	ledgerRepoReads          internal.LedgerReadsRepositoryItf
+	// running makes sure the scheduled and on demand runs never overlap
+	running sync.Mutex
+}
+
+func NewReconciliation(
+	reconciliationRepoReads internal.ReconciliationReadsRepositoryItf,
+	reconciliationRepoWrites internal.ReconciliationWritesRepositoryItf,
+	ledgerRepoReads internal.LedgerReadsRepositoryItf,
+) *Reconciliation {
+	return &Reconciliation{
+		reconciliationRepoReads:  reconciliationRepoReads,
+		reconciliationRepoWrites: reconciliationRepoWrites,
+		ledgerRepoReads:          ledgerRepoReads,
+	}
+}
+
+// RunReconciliationSvc starts a reconciliation run in the background and returns its id
+func (rc *Reconciliation) RunReconciliationSvc(triggeredBy string) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	if !rc.running.TryLock() {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusConflict,
+			ResponseMessage: "reconciliation is already running",
+		}
+		return resp, errors.New("reconciliation is already running")
+	}
+
+	runId, err := rc.reconciliationRepoWrites.CreateReconciliationRunRepo(triggeredBy)
+	if err != nil {
+		rc.running.Unlock()
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	go func() {
+		defer rc.running.Unlock()
+
+		totalAccounts, totalDiscrepancies, err := rc.reconcile(runId)
+		status := constant.ReconciliationStatusCompleted
+		notes := ""
+		if err != nil {
+			slog.Errorw("reconciliation run failed", "stack_trace", err.Error())
+			status = constant.ReconciliationStatusFailed
+			notes = err.Error()
+		}
+
+		err = rc.reconciliationRepoWrites.FinishReconciliationRunRepo(runId, status, totalAccounts, totalDiscrepancies, notes)
+		if err != nil {
+			slog.Errorw("failed to finish reconciliation run", "stack_trace", err.Error())
+			return
+		}
+
+		slog.Infof("reconciliation run %d %s with %d discrepancies on %d accounts", runId, status, totalDiscrepancies, totalAccounts)
+	}()
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "Reconciliation started",
+		Data:            map[string]int{"reconciliationRunId": runId},
+	}
+
+	return resp, nil
+}
+
+// reconcile replays the capital flows of every merchant account and stores the discrepancies found
+func (rc *Reconciliation) reconcile(runId int) (int, int, error) {
+	merchantAccounts, err := rc.reconciliationRepoReads.GetMerchantAccountsRepo()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	totalDiscrepancies := 0
+	for _, merchantAccount := range merchantAccounts {
+		discrepancies, err := rc.reconcileMerchantAccount(runId, merchantAccount)
+		if err != nil {
+			return len(merchantAccounts), totalDiscrepancies, err
+		}
+
+		for _, discrepancy := range discrepancies {
+			_, err = rc.reconciliationRepoWrites.CreateReconciliationDiscrepancyRepo(discrepancy)
+			if err != nil {
+				return len(merchantAccounts), totalDiscrepancies, err
+			}
+		}
+
+		totalDiscrepancies += len(discrepancies)
+	}
+
+	return len(merchantAccounts), totalDiscrepancies, nil
+}
+
+func (rc *Reconciliation) reconcileMerchantAccount(runId int, merchantAccount entity.MerchantAccount) ([]dto.CreateReconciliationDiscrepancyPayload, error) {
+	var discrepancies []dto.CreateReconciliationDiscrepancyPayload
+
+	newDiscrepancy := func(bucket string, expected money.Money, actual money.Money, paymentIds []string) {
+		discrepancies = append(discrepancies, dto.CreateReconciliationDiscrepancyPayload{
+			ReconciliationRunId: runId,
+			MerchantId:          merchantAccount.MerchantId,
+			MerchantAccountId:   merchantAccount.Id,
+			Bucket:              bucket,
+			ExpectedAmount:      expected,
+			ActualAmount:        actual,
+			PaymentIds:          paymentIds,
+		})
+	}
+
+	capitalFlows, err := rc.reconciliationRepoReads.GetCapitalFlowsByMerchantAccountIdRepo(merchantAccount.Id)
+	if err != nil {
+		return discrepancies, err
+	}
+
+	// replay the flows, every temp_balance must match the previous snapshot plus the flow amount
+	var replayedCapital, snapshot money.Money
+	var offendingPaymentIds []string
+	for _, capitalFlow := range capitalFlows {
+		var delta money.Money
+		switch capitalFlow.CapitalType {
+		case constant.CapitalTypeCredit:
+			delta = capitalFlow.Amount
+		case constant.CapitalTypeDebit:
+			delta = capitalFlow.Amount.Neg()
+		}
+
+		replayedCapital = replayedCapital.Add(delta)
+		if !capitalFlow.TempBalance.Equal(snapshot.Add(delta)) {
+			offendingPaymentIds = append(offendingPaymentIds, capitalFlow.PaymentId)
+		}
+		// compare the next row against its recorded predecessor so one bad row doesn't flag every row after it
+		snapshot = capitalFlow.TempBalance
+	}
+
+	if !replayedCapital.Equal(merchantAccount.BalanceCapitalFlow) || len(offendingPaymentIds) > 0 {
+		newDiscrepancy(constant.ReconciliationBucketCapitalFlow, replayedCapital, merchantAccount.BalanceCapitalFlow, offendingPaymentIds)
+	}
+
+	bucketSum := merchantAccount.SettledBalance.
+		Add(merchantAccount.NotSettledBalance).
+		Add(merchantAccount.HoldBalance).
+		Add(merchantAccount.PendingTransactionOut)
+	if !bucketSum.Equal(merchantAccount.BalanceCapitalFlow) {
+		newDiscrepancy(constant.ReconciliationBucketSum, merchantAccount.BalanceCapitalFlow, bucketSum, nil)
+	}
+
+	// every bucket must match the ledger, flows without a journal are the usual suspects
+	ledgerBalances, err := rc.ledgerRepoReads.GetLedgerBalancesByOwnerRepo(constant.LedgerOwnerMerchant, merchantAccount.MerchantId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return discrepancies, err
+	}
+	expectedAccount := merchantAccountFromLedger(merchantAccount, ledgerBalances)
+
+	buckets := []struct {
+		name     string
+		expected money.Money
+		actual   money.Money
+	}{
+		{constant.LedgerAccountSettled, expectedAccount.SettledBalance, merchantAccount.SettledBalance},
+		{constant.LedgerAccountNotSettled, expectedAccount.NotSettledBalance, merchantAccount.NotSettledBalance},
+		{constant.LedgerAccountHold, expectedAccount.HoldBalance, merchantAccount.HoldBalance},
+		{constant.LedgerAccountPendingOut, expectedAccount.PendingTransactionOut, merchantAccount.PendingTransactionOut},
+	}
+
+	var paymentIdsWithoutLedger []string
+	for _, bucket := range buckets {
+		if bucket.expected.Equal(bucket.actual) {
+			continue
+		}
+
+		if paymentIdsWithoutLedger == nil {
+			paymentIdsWithoutLedger, err = rc.reconciliationRepoReads.GetCapitalFlowPaymentIdsWithoutLedgerRepo(merchantAccount.Id, merchantAccount.MerchantId)
+			if err != nil {
+				return discrepancies, err
+			}
+		}
+
+		newDiscrepancy(bucket.name, bucket.expected, bucket.actual, paymentIdsWithoutLedger)
+	}
+
+	return discrepancies, nil
+}
+
+func (rc *Reconciliation) GetListReconciliationRunSvc() (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	reconciliationRuns, err := rc.reconciliationRepoReads.GetListReconciliationRunsRepo()
+	if err != nil {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: err.Error(),
+		}
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "Success retrieve data",
+		Data:            reconciliationRuns,
+	}
+
+	return resp, nil
+}
+
+func (rc *Reconciliation) GetReconciliationRunDetailSvc(runId int) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	reconciliationRun, err := rc.reconciliationRepoReads.GetReconciliationRunByIdRepo(runId)
+	if err != nil {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: "reconciliation run not found",
+		}
+		return resp, err
+	}
+
+	discrepancies, err := rc.reconciliationRepoReads.GetReconciliationDiscrepanciesByRunIdRepo(runId)
+	if err != nil {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: err.Error(),
+		}
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "Success retrieve data",
+		Data: dto.ReconciliationRunDetailRespDto{
+			ReconciliationRun: reconciliationRun,
+			Discrepancies:     discrepancies,
+		},
+	}
+
+	return resp, nil
+}
+
+func (rc *Reconciliation) ResolveReconciliationDiscrepancySvc(payload dto.ResolveReconciliationDiscrepancyReq) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	_, err := rc.reconciliationRepoWrites.ResolveReconciliationDiscrepancyRepo(payload)
+	if err != nil {
+		message := constant.GeneralErrMsg
+		if errors.Is(err, sql.ErrNoRows) {
+			message = "discrepancy not found or already resolved"
+		}
+
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: message,
+		}
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "Success resolve discrepancy",
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+func (u *User) GetListPermissionsSvc() (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	permissions, err := u.userRepoReads.GetNamedPermissionsRepo()
+	if err != nil {
+		slog.Errorw("failed get permissions", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "success retrieve data",
+		Data:            permissions,
+	}
+
+	return resp, nil
+}
+
+func (u *User) CreateRoleSvc(payload dto.CreateRolePayload) (dto.ResponseDto, error) {
+	payload.RoleName = strings.TrimSpace(payload.RoleName)
+
+	permissionIds, resp, err := u.resolvePermissionIds(payload.Permissions)
+	if err != nil {
+		return resp, err
+	}
+
+	return runInUnitOfWork(u.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		uTx := u.withUnitOfWork(repos)
+
+		roleId, err := uTx.userRepoWrites.CreateRoleRepo(payload.RoleName, payload.TwoFactorRequired, payload.MerchantAssignable)
+		if err == sql.ErrNoRows {
+			msg := fmt.Sprintf("role %v already exists", payload.RoleName)
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: msg,
+			}, errors.New(msg)
+		}
+		if err != nil {
+			slog.Errorw("failed create role", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		err = uTx.userRepoWrites.CreateRolePermissionsRepo(roleId, permissionIds)
+		if err != nil {
+			slog.Errorw("failed create role permissions", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: fmt.Sprintf("success create role %v with id: %v", payload.RoleName, roleId),
+		}, nil
+	})
+}
+
+// UpdateRolePermissionsSvc replaces every permission of the role with payload.Permissions and sets its two
+// factor policy and whether merchant users may be given it when given, the change reaches users of the role
+// on their next login
+func (u *User) UpdateRolePermissionsSvc(payload dto.UpdateRolePermissionsPayload) (dto.ResponseDto, error) {
+	role, err := u.userRepoReads.GetRoleByIdRepo(payload.RoleId)
+	if err == sql.ErrNoRows {
+		msg := fmt.Sprintf("role id %v not found", payload.RoleId)
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: msg,
+		}, errors.New(msg)
+	}
+	if err != nil {
+		slog.Errorw("failed get role", "stack_trace", err.Error())
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	permissionIds, resp, err := u.resolvePermissionIds(payload.Permissions)
+	if err != nil {
+		return resp, err
+	}
+
+	// keep at least one way back in, a role can't take role management away from itself
+	user, err := u.userRepoReads.GetUserByUsername(payload.Username)
+	if err != nil {
+		slog.Errorw("failed get user", "stack_trace", err.Error())
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	if user.RoleId == role.Id && !helper.Contains(payload.Permissions, constant.PermissionRoleManage) {
+		msg := fmt.Sprintf("role %v can't remove %v from itself", role.RoleName, constant.PermissionRoleManage)
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: msg,
+		}, errors.New(msg)
+	}
+
+	return runInUnitOfWork(u.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		uTx := u.withUnitOfWork(repos)
+
+		err := uTx.userRepoWrites.DeleteRolePermissionsRepo(role.Id)
+		if err != nil {
+			slog.Errorw("failed delete role permissions", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		err = uTx.userRepoWrites.CreateRolePermissionsRepo(role.Id, permissionIds)
+		if err != nil {
+			slog.Errorw("failed create role permissions", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		if payload.TwoFactorRequired != nil {
+			err = uTx.userRepoWrites.UpdateRoleTwoFactorRepo(role.Id, *payload.TwoFactorRequired)
+			if err != nil {
+				slog.Errorw("failed update role two factor", "stack_trace", err.Error())
+				return dto.ResponseDto{
+					ResponseCode:    http.StatusUnprocessableEntity,
+					ResponseMessage: constant.GeneralErrMsg,
+				}, err
+			}
+		}
+
+		if payload.MerchantAssignable != nil {
+			err = uTx.userRepoWrites.UpdateRoleMerchantAssignableRepo(role.Id, *payload.MerchantAssignable)
+			if err != nil {
+				slog.Errorw("failed update role merchant assignable", "stack_trace", err.Error())
+				return dto.ResponseDto{
+					ResponseCode:    http.StatusUnprocessableEntity,
+					ResponseMessage: constant.GeneralErrMsg,
+				}, err
+			}
+		}
+
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: fmt.Sprintf("success updated permissions of role %v", role.RoleName),
+		}, nil
+	})
+}
+
+// resolvePermissionIds maps permission names to their ids, unknown names are rejected
+func (u *User) resolvePermissionIds(names []string) ([]int, dto.ResponseDto, error) {
+	permissions, err := u.userRepoReads.GetNamedPermissionsRepo()
+	if err != nil {
+		slog.Errorw("failed get permissions", "stack_trace", err.Error())
+		return nil, dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	permissionIdByName := make(map[string]int, len(permissions))
+	for _, permission := range permissions {
+		permissionIdByName[permission.PermissionName] = permission.PermissionID
+	}
+
+	permissionIds := make([]int, 0, len(names))
+	for _, name := range names {
+		permissionId, ok := permissionIdByName[name]
+		if !ok {
+			msg := fmt.Sprintf("unknown permission %v", name)
+			return nil, dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: msg,
+			}, errors.New(msg)
+		}
+		permissionIds = append(permissionIds, permissionId)
+	}
+
+	return permissionIds, dto.ResponseDto{}, nil
+}
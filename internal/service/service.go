@@ -3,14 +3,18 @@ package service
 import (
 	"github.com/hypay-id/backend-dashboard-hypay/config"
 	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/envelope"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/repository"
 )
 
 type Service struct {
-	Transactions internal.TransactionServiceItf
-	Merchants    internal.MerchantServiceItf
-	Users        internal.UserServiceItf
-	Providers    internal.ProviderServiceItf
+	Transactions    internal.TransactionServiceItf
+	Merchants       internal.MerchantServiceItf
+	Users           internal.UserServiceItf
+	Providers       internal.ProviderServiceItf
+	Reconciliations internal.ReconciliationServiceItf
+	Audits          internal.AuditServiceItf
+	HashChains      internal.HashChainServiceItf
 }
 
 func New(
@@ -18,8 +22,11 @@ func New(
 	repoWrites *repository.Repository,
 	cfg config.App,
 	adptrMerchantCallback internal.MerchantCallbackItf,
-	jackProvider internal.JackProviderItf,
+	payoutProviders internal.PayoutProviderRegistryItf,
+	keyring *envelope.Keyring,
 ) *Service {
+	auditor := NewAuditor(repoWrites.AuditWrites)
+	credentialGuard := NewCredentialGuard(repoWrites.UserWrites, cfg, auditor)
 	transactions := NewTransaction(
 		repoReads.TransactionsReads,
 		repoWrites.TransactionsWrites,
@@ -27,30 +34,60 @@ func New(
 		repoReads.MerchantReads,
 		repoWrites.MerchantWrites,
 		cfg,
-		jackProvider,
+		payoutProviders,
 		repoReads.ProviderReads,
 		repoWrites.ProviderWrites,
+		repoWrites.UnitOfWork,
+		repoWrites.LedgerWrites,
+		credentialGuard,
+		keyring,
+		auditor,
+		repoReads.DisbursementApprovalReads,
+		repoWrites.DisbursementApprovalWrites,
+		repoReads.DisbursementBatchReads,
+		repoWrites.DisbursementBatchWrites,
 	)
 	merchants := NewMerchant(repoReads.MerchantReads,
 		repoWrites.MerchantWrites,
 		repoReads.UserReads,
 		adptrMerchantCallback,
 		repoReads.TransactionsReads,
-		repoReads.ProviderReads)
+		repoReads.ProviderReads,
+		repoWrites.UnitOfWork,
+		repoReads.LedgerReads,
+		repoWrites.LedgerWrites,
+		credentialGuard,
+		keyring,
+		auditor,
+		repoReads.BalanceApprovalReads,
+		repoWrites.BalanceApprovalWrites,
+	)
 	providers := NewProvider(
 		repoReads.TransactionsReads,
 		repoReads.MerchantReads,
 		repoReads.ProviderReads,
 		repoWrites.ProviderWrites,
 		repoReads.UserReads,
+		repoWrites.UnitOfWork,
 		cfg,
+		auditor,
+	)
+	users := NewUser(repoReads.UserReads, repoWrites.UserWrites, repoWrites.UnitOfWork, cfg, credentialGuard, auditor)
+	reconciliations := NewReconciliation(
+		repoReads.ReconciliationReads,
+		repoWrites.ReconciliationWrites,
+		repoReads.LedgerReads,
 	)
-	users := NewUser(repoReads.UserReads, repoWrites.UserWrites, cfg)
+	audits := NewAudit(repoReads.AuditReads, auditor)
+	hashChains := NewHashChain(repoReads.HashChainReads, repoWrites.UnitOfWork)
 
 	return &Service{
-		Transactions: transactions,
-		Merchants:    merchants,
-		Users:        users,
-		Providers:    providers,
+		Transactions:    transactions,
+		Merchants:       merchants,
+		Users:           users,
+		Providers:       providers,
+		Reconciliations: reconciliations,
+		Audits:          audits,
+		HashChains:      hashChains,
 	}
 }
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"net/http"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+var errSessionInactive = errors.New("session is no longer active, please login again")
+
+var errInvalidRefreshToken = errors.New("invalid refresh token")
+
+// RefreshTokenSvc redeems a refresh token for a new access token and a new refresh token, the old refresh
+// token stops working right away
+func (u *User) RefreshTokenSvc(payload dto.RefreshTokenPayload) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+	refreshTokenHash := hashSecureToken(payload.RefreshToken)
+
+	newRefreshToken, err := generateSecureToken(32)
+	if err != nil {
+		slog.Errorw("failed generate refresh token", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	session, err := u.userRepoWrites.RotateSessionRefreshTokenRepo(refreshTokenHash, hashSecureToken(newRefreshToken))
+	if err == sql.ErrNoRows {
+		// a refresh token that was already rotated out is being replayed, end the session for every holder
+		err = u.userRepoWrites.RevokeSessionByPreviousRefreshTokenRepo(refreshTokenHash)
+		if err != nil {
+			slog.Errorw("failed revoke replayed session", "stack_trace", err.Error())
+		}
+
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnauthorized,
+			ResponseMessage: errInvalidRefreshToken.Error(),
+		}
+		return resp, errInvalidRefreshToken
+	}
+	if err != nil {
+		slog.Errorw("failed rotate refresh token", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	user, err := u.userRepoReads.GetUserByUsername(session.Username)
+	if err != nil {
+		slog.Errorw("failed get user", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	if user.UserStatus != constant.StatusActive {
+		err = u.userRepoWrites.RevokeSessionRepo(session.SessionId)
+		if err != nil {
+			slog.Errorw("failed revoke session", "stack_trace", err.Error())
+		}
+
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnauthorized,
+			ResponseMessage: "user is not active",
+		}
+		return resp, errors.New("user not active")
+	}
+
+	// a role that started requiring two factor sends its users back through login to enroll
+	if user.TwoFactorRequired && !user.TwoFactorEnabled {
+		err = u.userRepoWrites.RevokeSessionRepo(session.SessionId)
+		if err != nil {
+			slog.Errorw("failed revoke session", "stack_trace", err.Error())
+		}
+
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnauthorized,
+			ResponseMessage: "two factor authentication is required, please login again",
+		}
+		return resp, errors.New("two factor not enabled")
+	}
+
+	userPermissions, err := u.userRepoReads.GetPermissionByRoleId(user.RoleId)
+	if err != nil {
+		slog.Errorw("failed get permissions", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+	user.Permissions = userPermissions
+
+	token, err := u.generateJWTToken(user, session.SessionId)
+	if err != nil {
+		slog.Errorw("failed generate token", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "successfully refresh token",
+		Data: map[string]interface{}{
+			"token":        token,
+			"refreshToken": newRefreshToken,
+		},
+	}
+
+	return resp, nil
+}
+
+func (u *User) LogoutSvc(sessionId string) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	err := u.userRepoWrites.RevokeSessionRepo(sessionId)
+	if err != nil {
+		slog.Errorw("failed revoke session", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "successfully logout",
+	}
+
+	return resp, nil
+}
+
+// ValidateSessionSvc fails when the session behind an access token was revoked, expired or belongs to a user
+// that is no longer active
+func (u *User) ValidateSessionSvc(sessionId string, username string) error {
+	// tokens issued before sessions existed carry no session id
+	if sessionId == "" {
+		return errSessionInactive
+	}
+
+	session, err := u.userRepoReads.GetActiveSessionRepo(sessionId)
+	if err == sql.ErrNoRows {
+		return errSessionInactive
+	}
+	if err != nil {
+		slog.Errorw("failed get session", "stack_trace", err.Error())
+		return errors.New(constant.GeneralErrMsg)
+	}
+
+	if session.Username != username {
+		return errSessionInactive
+	}
+
+	return nil
+}
+
+// startSession records a login on a device and returns the session with its refresh token, only the hash
+// of the refresh token is stored
+func (u *User) startSession(user entity.User, device string, ipAddress string) (entity.UserSessionEntity, string, error) {
+	sessionId, err := generateSecureToken(16)
+	if err != nil {
+		return entity.UserSessionEntity{}, "", err
+	}
+
+	refreshToken, err := generateSecureToken(32)
+	if err != nil {
+		return entity.UserSessionEntity{}, "", err
+	}
+
+	session := entity.UserSessionEntity{
+		SessionId: sessionId,
+		UserId:    user.UserID,
+		Username:  user.Username,
+		Device:    device,
+		IpAddress: ipAddress,
+	}
+
+	session.Id, err = u.userRepoWrites.CreateSessionRepo(session, hashSecureToken(refreshToken), constant.RefreshTokenTTL)
+	if err != nil {
+		return entity.UserSessionEntity{}, "", err
+	}
+
+	return session, refreshToken, nil
+}
+
+func generateSecureToken(length int) (string, error) {
+	b := make([]byte, length)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func hashSecureToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,666 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+)
+
+var (
+	errIllegalTransition   = errors.New("illegal transaction status transition")
+	errTransitionForbidden = errors.New("transaction status transition needs the override status permission")
+)
+
+type transactionTransitionKey struct {
+	payType string
+	from    string
+	to      string
+}
+
+// transactionTransition is one legal status change, guard runs before anything is written and apply moves
+// the merchant balance once the status and its status log are stored
+type transactionTransition struct {
+	statusLog string
+	override  bool
+	guard     func(tr *Transaction, change transactionStatusChange) error
+	apply     func(tr *Transaction, change transactionStatusChange) error
+}
+
+// transactionStatusChange is a requested status change of a locked transaction and who asked for it
+type transactionStatusChange struct {
+	transaction entity.PaymentDetailMerchantProvider
+	to          string
+	changeBy    string
+	canOverride bool
+	notes       string
+	realNotes   string
+}
+
+// journalNotes prefers the provider message over the notes shown to the merchant
+func (change transactionStatusChange) journalNotes() string {
+	if change.realNotes != "" {
+		return change.realNotes
+	}
+
+	return change.notes
+}
+
+// transactionTransitions lists every legal status change per pay type, anything else is rejected
+var transactionTransitions = map[transactionTransitionKey]transactionTransition{
+	// payin lifecycle
+	{constant.PayTypePayin, constant.StatusProcessing, constant.StatusSuccess}: {
+		statusLog: constant.StatusLogSuccess,
+		apply:     (*Transaction).creditPayin,
+	},
+	{constant.PayTypePayin, constant.StatusProcessing, constant.StatusFailed}: {
+		statusLog: constant.StatusLogFailed,
+		apply:     (*Transaction).closeUnpaidPayin,
+	},
+	{constant.PayTypePayin, constant.StatusProcessing, constant.StatusExpired}: {
+		statusLog: constant.StatusLogExpired,
+		apply:     (*Transaction).closeUnpaidPayin,
+	},
+	{constant.PayTypePayin, constant.StatusFailed, constant.StatusSuccess}: {
+		statusLog: constant.StatusLogSuccess,
+		override:  true,
+		guard:     (*Transaction).payinNeverSucceeded,
+		apply:     (*Transaction).creditPayin,
+	},
+	{constant.PayTypePayin, constant.StatusExpired, constant.StatusSuccess}: {
+		statusLog: constant.StatusLogSuccess,
+		override:  true,
+		guard:     (*Transaction).payinNeverSucceeded,
+		apply:     (*Transaction).creditPayin,
+	},
+	{constant.PayTypePayin, constant.StatusSuccess, constant.StatusFailed}: {
+		statusLog: constant.StatusLogFailed,
+		override:  true,
+		apply:     (*Transaction).reversePayin,
+	},
+	{constant.PayTypePayin, constant.StatusSuccess, constant.StatusReversed}: {
+		statusLog: constant.StatusLogReversed,
+		override:  true,
+		apply:     (*Transaction).reversePayin,
+	},
+	{constant.PayTypePayin, constant.StatusSuccess, constant.StatusRefunded}: {
+		statusLog: constant.StatusLogRefunded,
+		override:  true,
+		apply:     (*Transaction).reversePayin,
+	},
+
+	// payout lifecycle
+	{constant.PayTypePayout, constant.StatusProcessing, constant.StatusSuccess}: {
+		statusLog: constant.StatusLogSuccess,
+		apply:     (*Transaction).settlePendingPayout,
+	},
+	{constant.PayTypePayout, constant.StatusProcessing, constant.StatusFailed}: {
+		statusLog: constant.StatusLogFailed,
+		apply:     (*Transaction).releasePendingPayout,
+	},
+	{constant.PayTypePayout, constant.StatusProcessing, constant.StatusExpired}: {
+		statusLog: constant.StatusLogExpired,
+		apply:     (*Transaction).releasePendingPayout,
+	},
+	{constant.PayTypePayout, constant.StatusFailed, constant.StatusSuccess}: {
+		statusLog: constant.StatusLogSuccess,
+		override:  true,
+		apply:     (*Transaction).debitSettledPayout,
+	},
+	{constant.PayTypePayout, constant.StatusExpired, constant.StatusSuccess}: {
+		statusLog: constant.StatusLogSuccess,
+		override:  true,
+		apply:     (*Transaction).debitSettledPayout,
+	},
+	{constant.PayTypePayout, constant.StatusSuccess, constant.StatusFailed}: {
+		statusLog: constant.StatusLogFailed,
+		override:  true,
+		apply:     (*Transaction).reversePayout,
+	},
+	{constant.PayTypePayout, constant.StatusSuccess, constant.StatusReversed}: {
+		statusLog: constant.StatusLogReversed,
+		override:  true,
+		apply:     (*Transaction).reversePayout,
+	},
+}
+
+// transitionTransaction moves the transaction of change into change.to, the caller must hold the
+// transaction row lock and run it inside a unit of work
+func (tr *Transaction) transitionTransaction(change transactionStatusChange) error {
+	transactionData := change.transaction
+
+	transition, ok := transactionTransitions[transactionTransitionKey{transactionData.PayType, transactionData.Status, change.to}]
+	if !ok {
+		return fmt.Errorf("%w: %v transaction %v can't change from %v to %v", errIllegalTransition, strings.ToLower(transactionData.PayType), transactionData.PaymentID, transactionData.Status, change.to)
+	}
+
+	if transition.override && !change.canOverride {
+		return fmt.Errorf("%w: %v transaction %v from %v to %v", errTransitionForbidden, strings.ToLower(transactionData.PayType), transactionData.PaymentID, transactionData.Status, change.to)
+	}
+
+	if transition.guard != nil {
+		err := transition.guard(tr, change)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tr.transactionRepoWrites.UpdateStatus(change.to, transactionData.PaymentID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tr.transactionRepoWrites.CreateTransactionStatusLog(transactionData.PaymentID, transition.statusLog, change.changeBy, change.notes, change.realNotes)
+	if err != nil {
+		return err
+	}
+
+	// every status reached here is final, the callback is sent by the delivery job once this unit of work commits
+	err = tr.merchantRepoWrites.SupersedeMerchantCallbackDeliveriesRepo(transactionData.PaymentID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tr.merchantRepoWrites.CreateMerchantCallbackDeliveryRepo(transactionData.PaymentID, change.to)
+	if err != nil {
+		return err
+	}
+
+	if transition.apply == nil {
+		return nil
+	}
+
+	return transition.apply(tr, change)
+}
+
+// payinNeverSucceeded blocks crediting a payin twice after it was reversed into failed
+func (tr *Transaction) payinNeverSucceeded(change transactionStatusChange) error {
+	transactionStatusLogs, err := tr.transactionRepoReads.GetStatusChangeLogData(change.transaction.PaymentID)
+	if err != nil {
+		return err
+	}
+
+	for _, statusLog := range transactionStatusLogs {
+		if statusLog.StatusLog == constant.StatusLogSuccess {
+			return fmt.Errorf("%w: payin transaction %v can't change into success due to have been success before", errIllegalTransition, change.transaction.PaymentID)
+		}
+	}
+
+	return nil
+}
+
+// payinFee is the merchant fee of a payin, percentage fee is taken from the transaction amount
+func payinFee(transactionData entity.PaymentDetailMerchantProvider) money.Money {
+	if transactionData.MerchantFeeType == constant.FeeTypePercentage {
+		return transactionData.TransactionAmount.Percent(transactionData.MerchantFee, money.RoundUp)
+	}
+
+	return transactionData.MerchantFee
+}
+
+// creditPayin adds the payin minus fee into the not settled balance
+func (tr *Transaction) creditPayin(change transactionStatusChange) error {
+	transactionData := change.transaction
+
+	// get merchant account
+	merchantAccountData, err := tr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// payin amount to balance
+	balanceCapitalAddPayin := merchantAccountData.BalanceCapitalFlow.Add(transactionData.TransactionAmount)
+	notSettledBalanceAddPayin := merchantAccountData.NotSettledBalance.Add(transactionData.TransactionAmount)
+
+	// update merchant balance
+	err = tr.merchantRepoWrites.UpdateMerchantCapitalAndNotSettleBalance(notSettledBalanceAddPayin, balanceCapitalAddPayin, transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// create merchant capital flow for transaction amount
+	_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(dto.CreateMerchantCapitalFlowPayload{
+		PaymentId:         transactionData.PaymentID,
+		MerchantAccountId: merchantAccountData.Id,
+		TempBalance:       balanceCapitalAddPayin,
+		ReasonId:          constant.ReasonIdPayin,
+		Status:            change.to,
+		CreateBy:          constant.CreateBySystem,
+		Amount:            transactionData.TransactionAmount,
+		CapitalType:       constant.CapitalTypeCredit,
+	})
+	if err != nil {
+		return err
+	}
+
+	feeTransactions := payinFee(transactionData)
+
+	// get merchant account after add payin
+	merchantAccountDataUpdated, err := tr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// adjust balance minus fee
+	balanceCapitalMinusFee := merchantAccountDataUpdated.BalanceCapitalFlow.Sub(feeTransactions)
+	notSettledBalanceMinusFee := merchantAccountDataUpdated.NotSettledBalance.Sub(feeTransactions)
+
+	err = tr.merchantRepoWrites.UpdateMerchantCapitalAndNotSettleBalance(notSettledBalanceMinusFee, balanceCapitalMinusFee, transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// create merchant capital flow for fee
+	_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(dto.CreateMerchantCapitalFlowPayload{
+		PaymentId:         transactionData.PaymentID,
+		MerchantAccountId: merchantAccountData.Id,
+		TempBalance:       balanceCapitalMinusFee,
+		ReasonId:          constant.ReasonIdFee,
+		Status:            change.to,
+		CreateBy:          constant.CreateBySystem,
+		Amount:            feeTransactions,
+		CapitalType:       constant.CapitalTypeDebit,
+	})
+	if err != nil {
+		return err
+	}
+
+	// post ledger journal
+	journal := newLedgerJournal(transactionData.PaymentID, constant.ReasonIdPayin, constant.CreateBySystem, change.journalNotes()).
+		move(providerFloatLedgerAccount(transactionData.ProviderName), merchantLedgerAccount(transactionData.MerchantId, constant.LedgerAccountNotSettled), transactionData.TransactionAmount).
+		move(merchantLedgerAccount(transactionData.MerchantId, constant.LedgerAccountNotSettled), platformLedgerAccount(constant.LedgerAccountFeeRevenue), feeTransactions)
+	return postLedgerJournal(tr.ledgerRepoWrites, journal)
+}
+
+// closeUnpaidPayin records the payin and fee as unchanged flows, nothing was credited yet
+func (tr *Transaction) closeUnpaidPayin(change transactionStatusChange) error {
+	transactionData := change.transaction
+
+	// get merchant account
+	merchantAccountData, err := tr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// create merchant capital flow for transaction amount
+	_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(dto.CreateMerchantCapitalFlowPayload{
+		PaymentId:         transactionData.PaymentID,
+		MerchantAccountId: merchantAccountData.Id,
+		TempBalance:       merchantAccountData.BalanceCapitalFlow,
+		ReasonId:          constant.ReasonIdPayin,
+		Status:            change.to,
+		CreateBy:          constant.CreateBySystem,
+		Amount:            transactionData.TransactionAmount,
+		CapitalType:       constant.CapitalTypeNotDebitNotCredit,
+	})
+	if err != nil {
+		return err
+	}
+
+	// create merchant capital flow for fee
+	_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(dto.CreateMerchantCapitalFlowPayload{
+		PaymentId:         transactionData.PaymentID,
+		MerchantAccountId: merchantAccountData.Id,
+		TempBalance:       merchantAccountData.BalanceCapitalFlow,
+		ReasonId:          constant.ReasonIdFee,
+		Status:            change.to,
+		CreateBy:          constant.CreateBySystem,
+		Amount:            payinFee(transactionData),
+		CapitalType:       constant.CapitalTypeNotDebitNotCredit,
+	})
+	return err
+}
+
+// reversePayin takes the credited payin minus fee back, from settled balance when it covers the amount
+func (tr *Transaction) reversePayin(change transactionStatusChange) error {
+	transactionData := change.transaction
+
+	// get merchant account
+	merchantAccountData, err := tr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	ledgerAccount, err := ledgerMerchantAccount(tr.ledgerRepoWrites, merchantAccountData)
+	if err != nil {
+		return err
+	}
+
+	balanceUse := constant.SettleBalance
+	settleOrNotSettleBalance := merchantAccountData.SettledBalance
+	if ledgerAccount.SettledBalance.LessThan(transactionData.TransactionAmount) {
+		balanceUse = constant.NotSettledBalance
+		settleOrNotSettleBalance = merchantAccountData.NotSettledBalance
+	}
+
+	feeTransactions := payinFee(transactionData)
+
+	// transaction amount will minus fee first and take out from balance
+	transactionAmountMinusFee := transactionData.TransactionAmount.Sub(feeTransactions)
+
+	balanceCapitalMinusFee := merchantAccountData.BalanceCapitalFlow.Sub(transactionAmountMinusFee)
+	settleOrNotSettleBalanceMinusFee := settleOrNotSettleBalance.Sub(transactionAmountMinusFee)
+
+	if balanceUse == constant.NotSettledBalance {
+		err = tr.merchantRepoWrites.UpdateMerchantCapitalAndNotSettleBalance(settleOrNotSettleBalanceMinusFee, balanceCapitalMinusFee, transactionData.MerchantId)
+	} else {
+		err = tr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(settleOrNotSettleBalanceMinusFee, balanceCapitalMinusFee, transactionData.MerchantId)
+	}
+	if err != nil {
+		return err
+	}
+
+	// create merchant capital flow for transaction amount that already minus on capital balance
+	_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(dto.CreateMerchantCapitalFlowPayload{
+		PaymentId:         transactionData.PaymentID,
+		MerchantAccountId: merchantAccountData.Id,
+		TempBalance:       balanceCapitalMinusFee,
+		ReasonId:          constant.ReasonIdPayin,
+		Status:            reversalFlowStatus(change.to),
+		CreateBy:          constant.CreateBySystem,
+		Amount:            transactionAmountMinusFee,
+		Notes:             fmt.Sprintf("reversed balance due to manual change into %v", strings.ToLower(change.to)),
+		CapitalType:       constant.CapitalTypeDebit,
+	})
+	if err != nil {
+		return err
+	}
+
+	// post ledger journal
+	ledgerSourceAccount := constant.LedgerAccountSettled
+	if balanceUse == constant.NotSettledBalance {
+		ledgerSourceAccount = constant.LedgerAccountNotSettled
+	}
+	journal := newLedgerJournal(transactionData.PaymentID, constant.ReasonIdPayin, constant.CreateBySystem, change.journalNotes()).
+		move(merchantLedgerAccount(transactionData.MerchantId, ledgerSourceAccount), providerFloatLedgerAccount(transactionData.ProviderName), transactionData.TransactionAmount).
+		move(platformLedgerAccount(constant.LedgerAccountFeeRevenue), merchantLedgerAccount(transactionData.MerchantId, ledgerSourceAccount), feeTransactions)
+	return postLedgerJournal(tr.ledgerRepoWrites, journal)
+}
+
+// settlePendingPayout pays the held payout and fee out of pending out balance
+func (tr *Transaction) settlePendingPayout(change transactionStatusChange) error {
+	transactionData := change.transaction
+	feeTransaction := transactionData.MerchantFee
+
+	// get merchant account
+	merchantAccountData, err := tr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// payout amount to balance
+	balanceCapitalMinusPayout := merchantAccountData.BalanceCapitalFlow.Sub(transactionData.TransactionAmount)
+	pendingOutBalanceMinusPayout := merchantAccountData.PendingTransactionOut.Sub(transactionData.TransactionAmount)
+
+	// update merchant balance
+	err = tr.merchantRepoWrites.UpdateMerchantCapitalPendingOut(pendingOutBalanceMinusPayout, balanceCapitalMinusPayout, transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// create merchant capital flow for transaction amount
+	_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(dto.CreateMerchantCapitalFlowPayload{
+		PaymentId:         transactionData.PaymentID,
+		MerchantAccountId: merchantAccountData.Id,
+		TempBalance:       balanceCapitalMinusPayout,
+		ReasonId:          constant.ReasonIdPayout,
+		Status:            change.to,
+		CreateBy:          constant.CreateBySystem,
+		Amount:            transactionData.TransactionAmount,
+		CapitalType:       constant.CapitalTypeDebit,
+	})
+	if err != nil {
+		return err
+	}
+
+	// get merchant account after minus payout
+	merchantAccountDataUpdated, err := tr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// adjust balance minus fee
+	balanceCapitalMinusFee := merchantAccountDataUpdated.BalanceCapitalFlow.Sub(feeTransaction)
+	pendingPayoutMinusFee := merchantAccountDataUpdated.PendingTransactionOut.Sub(feeTransaction)
+
+	err = tr.merchantRepoWrites.UpdateMerchantCapitalPendingOut(pendingPayoutMinusFee, balanceCapitalMinusFee, transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// create merchant capital flow for fee
+	_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(dto.CreateMerchantCapitalFlowPayload{
+		PaymentId:         transactionData.PaymentID,
+		MerchantAccountId: merchantAccountData.Id,
+		TempBalance:       balanceCapitalMinusFee,
+		ReasonId:          constant.ReasonIdFee,
+		Status:            change.to,
+		CreateBy:          constant.CreateBySystem,
+		Amount:            feeTransaction,
+		CapitalType:       constant.CapitalTypeDebit,
+	})
+	if err != nil {
+		return err
+	}
+
+	// post ledger journal
+	journal := newLedgerJournal(transactionData.PaymentID, constant.ReasonIdPayout, constant.CreateBySystem, change.journalNotes()).
+		move(merchantLedgerAccount(transactionData.MerchantId, constant.LedgerAccountPendingOut), providerFloatLedgerAccount(transactionData.ProviderName), transactionData.TransactionAmount).
+		move(merchantLedgerAccount(transactionData.MerchantId, constant.LedgerAccountPendingOut), platformLedgerAccount(constant.LedgerAccountFeeRevenue), feeTransaction)
+	return postLedgerJournal(tr.ledgerRepoWrites, journal)
+}
+
+// releasePendingPayout moves the held payout and fee from pending out back into settled balance
+func (tr *Transaction) releasePendingPayout(change transactionStatusChange) error {
+	transactionData := change.transaction
+	feeTransaction := transactionData.MerchantFee
+	transactionPlusFee := transactionData.TransactionAmount.Add(feeTransaction)
+
+	// get merchant account
+	merchantAccountData, err := tr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	settleBalancePlusOut := merchantAccountData.SettledBalance.Add(transactionPlusFee)
+	pendingPayoutMinusOut := merchantAccountData.PendingTransactionOut.Sub(transactionPlusFee)
+	err = tr.merchantRepoWrites.UpdateMerchantBalanceSettleAndPendingOutBalanceRepo(settleBalancePlusOut, pendingPayoutMinusOut, transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// create merchant capital flow for transaction amount
+	_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(dto.CreateMerchantCapitalFlowPayload{
+		PaymentId:         transactionData.PaymentID,
+		MerchantAccountId: merchantAccountData.Id,
+		TempBalance:       merchantAccountData.BalanceCapitalFlow,
+		ReasonId:          constant.ReasonIdPayout,
+		Status:            change.to,
+		CreateBy:          constant.CreateBySystem,
+		Amount:            transactionData.TransactionAmount,
+		CapitalType:       constant.CapitalTypeNotDebitNotCredit,
+	})
+	if err != nil {
+		return err
+	}
+
+	// create merchant capital flow for fee
+	_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(dto.CreateMerchantCapitalFlowPayload{
+		PaymentId:         transactionData.PaymentID,
+		MerchantAccountId: merchantAccountData.Id,
+		TempBalance:       merchantAccountData.BalanceCapitalFlow,
+		ReasonId:          constant.ReasonIdFee,
+		Status:            change.to,
+		CreateBy:          constant.CreateBySystem,
+		Amount:            feeTransaction,
+		CapitalType:       constant.CapitalTypeNotDebitNotCredit,
+	})
+	if err != nil {
+		return err
+	}
+
+	// post ledger journal
+	journal := newLedgerJournal(transactionData.PaymentID, constant.ReasonIdPayout, constant.CreateBySystem, change.journalNotes()).
+		move(merchantLedgerAccount(transactionData.MerchantId, constant.LedgerAccountPendingOut), merchantLedgerAccount(transactionData.MerchantId, constant.LedgerAccountSettled), transactionPlusFee)
+	return postLedgerJournal(tr.ledgerRepoWrites, journal)
+}
+
+// debitSettledPayout pays a payout whose hold was already released out of settled balance
+func (tr *Transaction) debitSettledPayout(change transactionStatusChange) error {
+	transactionData := change.transaction
+	feeTransaction := transactionData.MerchantFee
+
+	// get merchant account
+	merchantAccountData, err := tr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// adjust settle balance minus transaction amount
+	balanceCapitalMinusPayout := merchantAccountData.BalanceCapitalFlow.Sub(transactionData.TransactionAmount)
+	settleBalanceMinusPayout := merchantAccountData.SettledBalance.Sub(transactionData.TransactionAmount)
+
+	// update merchant balance
+	err = tr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(settleBalanceMinusPayout, balanceCapitalMinusPayout, transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// create merchant capital flow for transaction amount
+	_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(dto.CreateMerchantCapitalFlowPayload{
+		PaymentId:         transactionData.PaymentID,
+		MerchantAccountId: merchantAccountData.Id,
+		TempBalance:       balanceCapitalMinusPayout,
+		ReasonId:          constant.ReasonIdPayout,
+		Status:            change.to,
+		CreateBy:          constant.CreateBySystem,
+		Amount:            transactionData.TransactionAmount,
+		CapitalType:       constant.CapitalTypeDebit,
+	})
+	if err != nil {
+		return err
+	}
+
+	// get merchant account after minus payout
+	merchantAccountDataUpdated, err := tr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// adjust balance minus fee
+	balanceCapitalMinusFee := merchantAccountDataUpdated.BalanceCapitalFlow.Sub(feeTransaction)
+	settleBalanceMinusFee := merchantAccountDataUpdated.SettledBalance.Sub(feeTransaction)
+
+	err = tr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(settleBalanceMinusFee, balanceCapitalMinusFee, transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// create merchant capital flow for fee
+	_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(dto.CreateMerchantCapitalFlowPayload{
+		PaymentId:         transactionData.PaymentID,
+		MerchantAccountId: merchantAccountData.Id,
+		TempBalance:       balanceCapitalMinusFee,
+		ReasonId:          constant.ReasonIdFee,
+		Status:            change.to,
+		CreateBy:          constant.CreateBySystem,
+		Amount:            feeTransaction,
+		CapitalType:       constant.CapitalTypeDebit,
+	})
+	if err != nil {
+		return err
+	}
+
+	// post ledger journal
+	journal := newLedgerJournal(transactionData.PaymentID, constant.ReasonIdPayout, constant.CreateBySystem, change.journalNotes()).
+		move(merchantLedgerAccount(transactionData.MerchantId, constant.LedgerAccountSettled), providerFloatLedgerAccount(transactionData.ProviderName), transactionData.TransactionAmount).
+		move(merchantLedgerAccount(transactionData.MerchantId, constant.LedgerAccountSettled), platformLedgerAccount(constant.LedgerAccountFeeRevenue), feeTransaction)
+	return postLedgerJournal(tr.ledgerRepoWrites, journal)
+}
+
+// reversePayout gives the paid out amount and fee back into settled balance
+func (tr *Transaction) reversePayout(change transactionStatusChange) error {
+	transactionData := change.transaction
+	feeTransactions := transactionData.MerchantFee
+
+	// get merchant account
+	merchantAccountData, err := tr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	balanceCapitalAddPayout := merchantAccountData.BalanceCapitalFlow.Add(transactionData.TransactionAmount)
+	settleBalanceAddPayout := merchantAccountData.SettledBalance.Add(transactionData.TransactionAmount)
+
+	// update merchant balance
+	err = tr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(settleBalanceAddPayout, balanceCapitalAddPayout, transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// create merchant capital flow for transaction amount that already add on capital balance
+	_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(dto.CreateMerchantCapitalFlowPayload{
+		PaymentId:         transactionData.PaymentID,
+		MerchantAccountId: merchantAccountData.Id,
+		TempBalance:       balanceCapitalAddPayout,
+		ReasonId:          constant.ReasonIdPayout,
+		Status:            reversalFlowStatus(change.to),
+		CreateBy:          constant.CreateBySystem,
+		Amount:            transactionData.TransactionAmount,
+		Notes:             fmt.Sprintf("reversed balance due to manual change into %v", strings.ToLower(change.to)),
+		CapitalType:       constant.CapitalTypeCredit,
+	})
+	if err != nil {
+		return err
+	}
+
+	// get merchant account after add payout
+	merchantAccountDataUpdated, err := tr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// adjust balance add fee
+	balanceCapitalAddFee := merchantAccountDataUpdated.BalanceCapitalFlow.Add(feeTransactions)
+	settledBalanceAddFee := merchantAccountDataUpdated.SettledBalance.Add(feeTransactions)
+
+	err = tr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(settledBalanceAddFee, balanceCapitalAddFee, transactionData.MerchantId)
+	if err != nil {
+		return err
+	}
+
+	// create merchant capital flow for fee
+	_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(dto.CreateMerchantCapitalFlowPayload{
+		PaymentId:         transactionData.PaymentID,
+		MerchantAccountId: merchantAccountData.Id,
+		TempBalance:       balanceCapitalAddFee,
+		ReasonId:          constant.ReasonIdFee,
+		Status:            reversalFlowStatus(change.to),
+		CreateBy:          constant.CreateBySystem,
+		Amount:            feeTransactions,
+		Notes:             fmt.Sprintf("reverse fee to balance due to manual change into %v", strings.ToLower(change.to)),
+		CapitalType:       constant.CapitalTypeCredit,
+	})
+	if err != nil {
+		return err
+	}
+
+	// post ledger journal
+	journal := newLedgerJournal(transactionData.PaymentID, constant.ReasonIdPayout, constant.CreateBySystem, change.journalNotes()).
+		move(providerFloatLedgerAccount(transactionData.ProviderName), merchantLedgerAccount(transactionData.MerchantId, constant.LedgerAccountSettled), transactionData.TransactionAmount).
+		move(platformLedgerAccount(constant.LedgerAccountFeeRevenue), merchantLedgerAccount(transactionData.MerchantId, constant.LedgerAccountSettled), feeTransactions)
+	return postLedgerJournal(tr.ledgerRepoWrites, journal)
+}
+
+// reversalFlowStatus is the capital flow status of money taken back from a successful transaction
+func reversalFlowStatus(to string) string {
+	if to == constant.StatusRefunded {
+		return constant.StatusRefunded
+	}
+
+	return constant.StatusReversed
+}
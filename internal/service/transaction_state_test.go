@@ -0,0 +1,152 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
+)
+
+func TestTransactionTransitions(t *testing.T) {
+	tests := []struct {
+		payType   string
+		from      string
+		to        string
+		statusLog string
+		override  bool
+	}{
+		{constant.PayTypePayin, constant.StatusProcessing, constant.StatusSuccess, constant.StatusLogSuccess, false},
+		{constant.PayTypePayin, constant.StatusProcessing, constant.StatusFailed, constant.StatusLogFailed, false},
+		{constant.PayTypePayin, constant.StatusProcessing, constant.StatusExpired, constant.StatusLogExpired, false},
+		{constant.PayTypePayin, constant.StatusFailed, constant.StatusSuccess, constant.StatusLogSuccess, true},
+		{constant.PayTypePayin, constant.StatusExpired, constant.StatusSuccess, constant.StatusLogSuccess, true},
+		{constant.PayTypePayin, constant.StatusSuccess, constant.StatusFailed, constant.StatusLogFailed, true},
+		{constant.PayTypePayin, constant.StatusSuccess, constant.StatusReversed, constant.StatusLogReversed, true},
+		{constant.PayTypePayin, constant.StatusSuccess, constant.StatusRefunded, constant.StatusLogRefunded, true},
+		{constant.PayTypePayout, constant.StatusProcessing, constant.StatusSuccess, constant.StatusLogSuccess, false},
+		{constant.PayTypePayout, constant.StatusProcessing, constant.StatusFailed, constant.StatusLogFailed, false},
+		{constant.PayTypePayout, constant.StatusProcessing, constant.StatusExpired, constant.StatusLogExpired, false},
+		{constant.PayTypePayout, constant.StatusFailed, constant.StatusSuccess, constant.StatusLogSuccess, true},
+		{constant.PayTypePayout, constant.StatusExpired, constant.StatusSuccess, constant.StatusLogSuccess, true},
+		{constant.PayTypePayout, constant.StatusSuccess, constant.StatusFailed, constant.StatusLogFailed, true},
+		{constant.PayTypePayout, constant.StatusSuccess, constant.StatusReversed, constant.StatusLogReversed, true},
+	}
+
+	if len(transactionTransitions) != len(tests) {
+		t.Errorf("transactionTransitions has %v transitions, want %v", len(transactionTransitions), len(tests))
+	}
+
+	for _, tt := range tests {
+		transition, ok := transactionTransitions[transactionTransitionKey{tt.payType, tt.from, tt.to}]
+		if !ok {
+			t.Errorf("%v %v -> %v is not a transition", tt.payType, tt.from, tt.to)
+			continue
+		}
+
+		if transition.statusLog != tt.statusLog || transition.override != tt.override {
+			t.Errorf("%v %v -> %v = status log %q override %v, want %q %v", tt.payType, tt.from, tt.to, transition.statusLog, transition.override, tt.statusLog, tt.override)
+		}
+
+		// every transition moves the balance, a missing apply would change the status only
+		if transition.apply == nil {
+			t.Errorf("%v %v -> %v has no apply", tt.payType, tt.from, tt.to)
+		}
+	}
+}
+
+func TestTransitionTransactionRejected(t *testing.T) {
+	tests := []struct {
+		name        string
+		payType     string
+		from        string
+		to          string
+		canOverride bool
+		wantErr     error
+	}{
+		{"payin back to processing", constant.PayTypePayin, constant.StatusSuccess, constant.StatusProcessing, true, errIllegalTransition},
+		{"payin failed to expired", constant.PayTypePayin, constant.StatusFailed, constant.StatusExpired, true, errIllegalTransition},
+		{"payin refunded again", constant.PayTypePayin, constant.StatusRefunded, constant.StatusSuccess, true, errIllegalTransition},
+		{"payout refunded", constant.PayTypePayout, constant.StatusSuccess, constant.StatusRefunded, true, errIllegalTransition},
+		{"payout reversed to success", constant.PayTypePayout, constant.StatusReversed, constant.StatusSuccess, true, errIllegalTransition},
+		{"same status", constant.PayTypePayout, constant.StatusSuccess, constant.StatusSuccess, true, errIllegalTransition},
+		{"unknown pay type", "TOPUP", constant.StatusProcessing, constant.StatusSuccess, true, errIllegalTransition},
+		{"payin override without permission", constant.PayTypePayin, constant.StatusSuccess, constant.StatusReversed, false, errTransitionForbidden},
+		{"payout override without permission", constant.PayTypePayout, constant.StatusFailed, constant.StatusSuccess, false, errTransitionForbidden},
+	}
+
+	// rejected changes return before any repository is touched
+	tr := &Transaction{}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tr.transitionTransaction(transactionStatusChange{
+				transaction: entity.PaymentDetailMerchantProvider{
+					PaymentID: "PAY-1",
+					PayType:   tt.payType,
+					Status:    tt.from,
+				},
+				to:          tt.to,
+				changeBy:    "tester",
+				canOverride: tt.canOverride,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("transitionTransaction got err %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPayinFee(t *testing.T) {
+	tests := []struct {
+		name    string
+		feeType string
+		fee     string
+		amount  string
+		want    string
+	}{
+		{"fixed", constant.FeeTypeFixedFee, "2500", "100000", "2500.00"},
+		{"percentage", constant.FeeTypePercentage, "1.5", "100000", "1500.00"},
+		{"percentage rounds up", constant.FeeTypePercentage, "1", "10050", "101.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := payinFee(entity.PaymentDetailMerchantProvider{
+				MerchantFeeType:   tt.feeType,
+				MerchantFee:       money.MustParse(tt.fee),
+				TransactionAmount: money.MustParse(tt.amount),
+			})
+			if got.String() != tt.want {
+				t.Errorf("payinFee = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReversalFlowStatus(t *testing.T) {
+	tests := map[string]string{
+		constant.StatusRefunded: constant.StatusRefunded,
+		constant.StatusReversed: constant.StatusReversed,
+		constant.StatusFailed:   constant.StatusReversed,
+	}
+
+	for to, want := range tests {
+		if got := reversalFlowStatus(to); got != want {
+			t.Errorf("reversalFlowStatus(%v) = %v, want %v", to, got, want)
+		}
+	}
+}
+
+func TestJournalNotes(t *testing.T) {
+	change := transactionStatusChange{notes: "failed"}
+	if got := change.journalNotes(); got != "failed" {
+		t.Errorf("journalNotes = %v, want failed", got)
+	}
+
+	change.realNotes = "bank rejected the account"
+	if got := change.journalNotes(); got != "bank rejected the account" {
+		t.Errorf("journalNotes = %v, want the provider message", got)
+	}
+}
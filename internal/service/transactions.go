@@ -1,9 +1,12 @@
 package service
 
 import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"math"
+	"net"
 	"net/http"
 	"os"
 	"regexp"
@@ -14,22 +17,35 @@ import (
 	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
-	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/envelope"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/money"
 	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
 )
 
+// errInsufficientBalance is returned when the locked account doesn't cover a disbursement anymore
+var errInsufficientBalance = errors.New("not enough balance for disbursement")
+
 type Transaction struct {
-	transactionRepoReads  internal.TransactionsReadsRepositoryItf
-	transactionRepoWrites internal.TransactionsWritesRepositoryItf
-	merchantRepoReads     internal.MerchantReadsRepositoryItf
-	merchantRepoWrites    internal.MerchantWritesRepositoryItf
-	providerRepoReads     internal.ProviderReadsRepositoryItf
-	providerRepoWrites    internal.ProviderWritesRepositoryItf
-	userRepoReads         internal.UserReadsRepositoryItf
-	configApp             config.App
-	jackProvider          internal.JackProviderItf
-	regex                 *regexp.Regexp
+	transactionRepoReads       internal.TransactionsReadsRepositoryItf
+	transactionRepoWrites      internal.TransactionsWritesRepositoryItf
+	merchantRepoReads          internal.MerchantReadsRepositoryItf
+	merchantRepoWrites         internal.MerchantWritesRepositoryItf
+	providerRepoReads          internal.ProviderReadsRepositoryItf
+	providerRepoWrites         internal.ProviderWritesRepositoryItf
+	userRepoReads              internal.UserReadsRepositoryItf
+	configApp                  config.App
+	payoutProviders            internal.PayoutProviderRegistryItf
+	unitOfWork                 internal.UnitOfWorkItf
+	ledgerRepoWrites           internal.LedgerWritesRepositoryItf
+	credentialGuard            *CredentialGuard
+	keyring                    *envelope.Keyring
+	regex                      *regexp.Regexp
+	auditor                    *Auditor
+	disbursementApprovalReads  internal.DisbursementApprovalReadsRepositoryItf
+	disbursementApprovalWrites internal.DisbursementApprovalWritesRepositoryItf
+	disbursementBatchReads     internal.DisbursementBatchReadsRepositoryItf
+	disbursementBatchWrites    internal.DisbursementBatchWritesRepositoryItf
 }
 
 func NewTransaction(
@@ -39,23 +55,41 @@ func NewTransaction(
 	merchantRepoReads internal.MerchantReadsRepositoryItf,
 	merchantRepoWrites internal.MerchantWritesRepositoryItf,
 	configApp config.App,
-	jackProvider internal.JackProviderItf,
+	payoutProviders internal.PayoutProviderRegistryItf,
 	providerRepoReads internal.ProviderReadsRepositoryItf,
 	providerRepoWrites internal.ProviderWritesRepositoryItf,
+	unitOfWork internal.UnitOfWorkItf,
+	ledgerRepoWrites internal.LedgerWritesRepositoryItf,
+	credentialGuard *CredentialGuard,
+	keyring *envelope.Keyring,
+	auditor *Auditor,
+	disbursementApprovalReads internal.DisbursementApprovalReadsRepositoryItf,
+	disbursementApprovalWrites internal.DisbursementApprovalWritesRepositoryItf,
+	disbursementBatchReads internal.DisbursementBatchReadsRepositoryItf,
+	disbursementBatchWrites internal.DisbursementBatchWritesRepositoryItf,
 ) *Transaction {
 	// regex only allow string
 	reg, _ := regexp.Compile("[^a-zA-Z]+")
 	return &Transaction{
-		transactionRepoReads:  transactionRepoReads,
-		transactionRepoWrites: transactionRepoWrites,
-		merchantRepoReads:     merchantRepoReads,
-		merchantRepoWrites:    merchantRepoWrites,
-		userRepoReads:         userRepoReads,
-		configApp:             configApp,
-		jackProvider:          jackProvider,
-		providerRepoReads:     providerRepoReads,
-		providerRepoWrites:    providerRepoWrites,
-		regex:                 reg,
+		transactionRepoReads:       transactionRepoReads,
+		transactionRepoWrites:      transactionRepoWrites,
+		merchantRepoReads:          merchantRepoReads,
+		merchantRepoWrites:         merchantRepoWrites,
+		userRepoReads:              userRepoReads,
+		configApp:                  configApp,
+		payoutProviders:            payoutProviders,
+		providerRepoReads:          providerRepoReads,
+		providerRepoWrites:         providerRepoWrites,
+		unitOfWork:                 unitOfWork,
+		ledgerRepoWrites:           ledgerRepoWrites,
+		credentialGuard:            credentialGuard,
+		keyring:                    keyring,
+		regex:                      reg,
+		auditor:                    auditor,
+		disbursementApprovalReads:  disbursementApprovalReads,
+		disbursementApprovalWrites: disbursementApprovalWrites,
+		disbursementBatchReads:     disbursementBatchReads,
+		disbursementBatchWrites:    disbursementBatchWrites,
 	}
 }
 
@@ -397,8 +431,8 @@ func (tr *Transaction) GetPaymentDetailFee(paymentId string) (dto.ResponseDto, e
 
 	// calculate fee for percentage
 	if paymentData.MerchantFeeType == constant.FeeTypePercentage {
-		calculatedFeeMerchant := math.Ceil(paymentData.TransactionAmount * (paymentData.MerchantFee / 100))
-		calculatedFeeProvider := math.Ceil(paymentData.TransactionAmount * (paymentData.ProviderFee / 100))
+		calculatedFeeMerchant := paymentData.TransactionAmount.Percent(paymentData.MerchantFee, money.RoundUp)
+		calculatedFeeProvider := paymentData.TransactionAmount.Percent(paymentData.ProviderFee, money.RoundUp)
 
 		merchantFee := dto.FeeData{
 			ConfiguredFee: paymentData.MerchantFee,
@@ -411,7 +445,7 @@ func (tr *Transaction) GetPaymentDetailFee(paymentId string) (dto.ResponseDto, e
 			CalculatedFee: calculatedFeeProvider,
 		}
 
-		calculatedProfit := calculatedFeeMerchant - calculatedFeeProvider
+		calculatedProfit := calculatedFeeMerchant.Sub(calculatedFeeProvider)
 
 		feeResp = dto.FeeResponse{
 			MerchantFee:      merchantFee,
@@ -440,7 +474,7 @@ func (tr *Transaction) GetPaymentDetailFee(paymentId string) (dto.ResponseDto, e
 			CalculatedFee: paymentData.ProviderFee,
 		}
 
-		calculatedProfit := paymentData.MerchantFee - paymentData.ProviderFee
+		calculatedProfit := paymentData.MerchantFee.Sub(paymentData.ProviderFee)
 
 		feeResp = dto.FeeResponse{
 			MerchantFee:      merchantFee,
@@ -459,8 +493,15 @@ func (tr *Transaction) GetPaymentDetailFee(paymentId string) (dto.ResponseDto, e
 	return resp, nil
 }
 
-func (tr *Transaction) UpdateStatusTransaction(paymentId string, status string, username string, notes string, pin string) (dto.ResponseDto, error) {
+func (tr *Transaction) UpdateStatusTransaction(paymentId string, status string, username string, canOverride bool, notes string, pin string) (dto.ResponseDto, error) {
+	return runInUnitOfWork(tr.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		return tr.withUnitOfWork(repos).updateStatusTransaction(paymentId, status, username, canOverride, notes, pin)
+	})
+}
+
+func (tr *Transaction) updateStatusTransaction(paymentId string, status string, username string, canOverride bool, notes string, pin string) (dto.ResponseDto, error) {
 	var resp dto.ResponseDto
+	status = strings.ToUpper(status)
 
 	// user data
 	user, err := tr.userRepoReads.GetUserByUsername(username)
@@ -473,13 +514,9 @@ func (tr *Transaction) UpdateStatusTransaction(paymentId string, status string,
 	}
 
 	// check input pin
-	if !comparePasswords(user.Pin, []byte(pin)) {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "wrong pin",
-		}
-
-		return resp, errors.New("wrong pin")
+	resp, err = tr.credentialGuard.VerifyPin(user, pin)
+	if err != nil {
+		return resp, err
 	}
 
 	transactionData, err := tr.transactionRepoReads.GetPaymentDetailProviderMerchant(paymentId)
@@ -490,7 +527,7 @@ func (tr *Transaction) UpdateStatusTransaction(paymentId string, status string,
 		}, err
 	}
 
-	if transactionData.Status == strings.ToUpper(status) {
+	if transactionData.Status == status {
 		msg := fmt.Sprintf("status already %v", status)
 		return dto.ResponseDto{
 			ResponseCode:    http.StatusOK,
@@ -506,717 +543,44 @@ func (tr *Transaction) UpdateStatusTransaction(paymentId string, status string,
 		}, nil
 	}
 
-	if transactionData.PayType == constant.PayTypePayin && strings.ToUpper(status) == constant.StatusSuccess {
-		// validate if transaction have been success - failed, and would like to change into success
-		transactionStatusLogs, err := tr.transactionRepoReads.GetStatusChangeLogData(transactionData.PaymentID)
-		if err != nil {
-			resp = dto.ResponseDto{
-				ResponseCode:    http.StatusUnprocessableEntity,
-				ResponseMessage: err.Error(),
-			}
-			return resp, err
-		}
+	// lock transaction row, status from reads connection could be stale
+	lockedStatus, err := tr.transactionRepoWrites.GetTransactionStatusForUpdateRepo(paymentId)
+	if err != nil {
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: err.Error(),
+		}, err
+	}
 
-		if transactionStatusLogs[1].StatusLog == constant.StatusLogSuccess {
-			resp = dto.ResponseDto{
-				ResponseCode:    http.StatusBadRequest,
-				ResponseMessage: "can't updated into success due to have been success before",
-			}
-			return resp, nil
-		}
+	if lockedStatus == status {
+		msg := fmt.Sprintf("status already %v", status)
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: msg,
+		}, nil
 	}
+	transactionData.Status = lockedStatus
 
-	// update transaction status
-	err = tr.transactionRepoWrites.UpdateStatus(strings.ToUpper(status), paymentId)
+	err = tr.transitionTransaction(transactionStatusChange{
+		transaction: transactionData,
+		to:          status,
+		changeBy:    username,
+		canOverride: canOverride,
+		notes:       notes,
+	})
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
 			ResponseMessage: err.Error(),
 		}
-		return resp, err
-	}
-
-	// handle for status SUCCESS
-	if strings.ToUpper(status) == constant.StatusSuccess {
-		// update status change log
-		_, err := tr.transactionRepoWrites.CreateTransactionStatusLog(paymentId, constant.StatusLogSuccess, username, notes, "")
-		if err != nil {
-			resp = dto.ResponseDto{
-				ResponseCode:    http.StatusUnprocessableEntity,
-				ResponseMessage: err.Error(),
-			}
-			return resp, err
-		}
-
-		// handle for payin and SUCCESS
-		if transactionData.PayType == constant.PayTypePayin {
-			// get merchant account
-			merchantAccountData, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(transactionData.MerchantId)
-			if err != nil {
-				resp = dto.ResponseDto{
-					ResponseCode:    http.StatusUnprocessableEntity,
-					ResponseMessage: err.Error(),
-				}
-				return resp, err
-			}
-
-			balanceCapitalFlow := merchantAccountData.BalanceCapitalFlow
-			notSettledBalance := merchantAccountData.NotSettledBalance
-
-			// payin amount to balance
-			balanceCapitalAddPayin := balanceCapitalFlow + transactionData.TransactionAmount
-			notSettledBalanceAddPayin := notSettledBalance + transactionData.TransactionAmount
-			formattedNotSettledBalanceAddPayin := helper.FormatFloat64(notSettledBalanceAddPayin)
-			formattedBalanceAddPayin := helper.FormatFloat64(balanceCapitalAddPayin)
-			// update merchant balance
-			err = tr.merchantRepoWrites.UpdateMerchantCapitalAndNotSettleBalance(formattedNotSettledBalanceAddPayin, formattedBalanceAddPayin, transactionData.MerchantId)
-			if err != nil {
-				resp = dto.ResponseDto{
-					ResponseCode:    http.StatusUnprocessableEntity,
-					ResponseMessage: err.Error(),
-				}
-				return resp, err
-			}
-
-			// create merchant capital flow for transaction amount
-			payloadMerchantCapitalFlowPayin := dto.CreateMerchantCapitalFlowPayload{
-				PaymentId:         transactionData.PaymentID,
-				MerchantAccountId: merchantAccountData.Id,
-				TempBalance:       formattedBalanceAddPayin,
-				ReasonId:          constant.ReasonIdPayin,
-				Status:            strings.ToUpper(status),
-				CreateBy:          constant.CreateBySystem,
-				Amount:            helper.FormatFloat64(transactionData.TransactionAmount),
-				CapitalType:       constant.CapitalTypeCredit,
-			}
-			_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowPayin)
-			if err != nil {
-				resp = dto.ResponseDto{
-					ResponseCode:    http.StatusUnprocessableEntity,
-					ResponseMessage: err.Error(),
-				}
-				return resp, err
-			}
-
-			// calculate fee for merchant capital flow
-			var feeTransactions float64
-			if transactionData.MerchantFeeType == constant.FeeTypePercentage {
-				calculateFee := math.Ceil(transactionData.TransactionAmount * (transactionData.MerchantFee / 100))
-				feeTransactions = helper.FormatFloat64(calculateFee)
-			} else {
-				feeTransactions = helper.FormatFloat64(transactionData.MerchantFee)
-			}
-
-			// get merchant account after add payin
-			merchantAccountDataUpdated, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(transactionData.MerchantId)
-			if err != nil {
-				resp = dto.ResponseDto{
-					ResponseCode:    http.StatusUnprocessableEntity,
-					ResponseMessage: err.Error(),
-				}
-				return resp, err
-			}
-
-			balanceCapitalFlow = merchantAccountDataUpdated.BalanceCapitalFlow
-			notSettledBalance = merchantAccountDataUpdated.NotSettledBalance
-
-			// adjust balance minus fee
-			balanceCapitalMinusFee := balanceCapitalFlow - feeTransactions
-			notSettledBalanceMinusFee := notSettledBalance - feeTransactions
-			formattedNotSettledBalanceMinusFee := helper.FormatFloat64(notSettledBalanceMinusFee)
-			formattedBalanceMinusFee := helper.FormatFloat64(balanceCapitalMinusFee)
-
-			err = tr.merchantRepoWrites.UpdateMerchantCapitalAndNotSettleBalance(formattedNotSettledBalanceMinusFee, formattedBalanceMinusFee, transactionData.MerchantId)
-			if err != nil {
-				resp = dto.ResponseDto{
-					ResponseCode:    http.StatusUnprocessableEntity,
-					ResponseMessage: err.Error(),
-				}
-				return resp, err
-			}
-
-			// create merchant capital flow for fee
-			payloadMerchantCapitalFlowFee := dto.CreateMerchantCapitalFlowPayload{
-				PaymentId:         transactionData.PaymentID,
-				MerchantAccountId: merchantAccountData.Id,
-				TempBalance:       formattedBalanceMinusFee,
-				ReasonId:          constant.ReasonIdFee,
-				Status:            strings.ToUpper(status),
-				CreateBy:          constant.CreateBySystem,
-				Amount:            feeTransactions,
-				CapitalType:       constant.CapitalTypeDebit,
-			}
-			_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowFee)
-			if err != nil {
-				resp = dto.ResponseDto{
-					ResponseCode:    http.StatusUnprocessableEntity,
-					ResponseMessage: err.Error(),
-				}
-				return resp, err
-			}
-		}
-
-		// handle for payout and SUCCESS
-		if transactionData.PayType == constant.PayTypePayout {
-			// get merchant account
-			merchantAccountData, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(transactionData.MerchantId)
-			if err != nil {
-				resp = dto.ResponseDto{
-					ResponseCode:    http.StatusUnprocessableEntity,
-					ResponseMessage: err.Error(),
-				}
-				return resp, err
-			}
-
-			// check if transaction before is failed
-			if transactionData.Status == constant.StatusFailed {
-				balanceCapitalFlow := merchantAccountData.BalanceCapitalFlow
-				settleBalance := merchantAccountData.SettledBalance
-
-				// adjust settle balance minum transaction amount
-				balanceCapitalMinusPayout := balanceCapitalFlow - float64(transactionData.TransactionAmount)
-				settleBalanceMinusPayout := settleBalance - float64(transactionData.TransactionAmount)
-				formattedBalanceCapitalMinusPayout := helper.FormatFloat64(balanceCapitalMinusPayout)
-				formattedSettleBalanceMinusPayout := helper.FormatFloat64(settleBalanceMinusPayout)
-
-				// update merchant balance
-				err = tr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formattedSettleBalanceMinusPayout, formattedBalanceCapitalMinusPayout, transactionData.MerchantId)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				// create merchant capital flow for transaction amount
-				payloadMerchantCapitalFlowPayout := dto.CreateMerchantCapitalFlowPayload{
-					PaymentId:         transactionData.PaymentID,
-					MerchantAccountId: merchantAccountData.Id,
-					TempBalance:       formattedBalanceCapitalMinusPayout,
-					ReasonId:          constant.ReasonIdPayout,
-					Status:            strings.ToUpper(status),
-					CreateBy:          constant.CreateBySystem,
-					Amount:            helper.FormatFloat64(transactionData.TransactionAmount),
-					CapitalType:       constant.CapitalTypeDebit,
-				}
-				_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowPayout)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				// get merchant account after minus payout
-				merchantAccountDataUpdated, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(transactionData.MerchantId)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				balanceCapitalFlow = merchantAccountDataUpdated.BalanceCapitalFlow
-				settleBalance = merchantAccountDataUpdated.SettledBalance
-
-				// fee transaction
-				feeTransaction := helper.FormatFloat64(transactionData.MerchantFee)
-
-				// adjust balance minus fee
-				balanceCapitalMinusFee := balanceCapitalFlow - feeTransaction
-				settleBalanceMinusFee := settleBalance - feeTransaction
-				formattedBalanceCapitalMinusFee := helper.FormatFloat64(balanceCapitalMinusFee)
-				formattedSettleBalanceMinusFee := helper.FormatFloat64(settleBalanceMinusFee)
-
-				err = tr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formattedSettleBalanceMinusFee, formattedBalanceCapitalMinusFee, transactionData.MerchantId)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				// create merchant capital flow for fee
-				payloadMerchantCapitalFlowFee := dto.CreateMerchantCapitalFlowPayload{
-					PaymentId:         transactionData.PaymentID,
-					MerchantAccountId: merchantAccountData.Id,
-					TempBalance:       formattedBalanceCapitalMinusFee,
-					ReasonId:          constant.ReasonIdFee,
-					Status:            strings.ToUpper(status),
-					CreateBy:          constant.CreateBySystem,
-					Amount:            feeTransaction,
-					CapitalType:       constant.CapitalTypeDebit,
-				}
-				_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowFee)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-			}
-
-			if transactionData.Status == constant.StatusProcessing {
-				balanceCapitalFlow := merchantAccountData.BalanceCapitalFlow
-				pendingOutBalance := merchantAccountData.PendingTransactionOut
-
-				// payout amount to balance
-				balanceCapitalMinusPayout := balanceCapitalFlow - transactionData.TransactionAmount
-				pendingOutBalanceMinusPayout := pendingOutBalance - transactionData.TransactionAmount
-				formattedPendingOutBalanceMinusPayout := helper.FormatFloat64(pendingOutBalanceMinusPayout)
-				formattedBalanceMinusPayout := helper.FormatFloat64(balanceCapitalMinusPayout)
-
-				// update merchant balance
-				err = tr.merchantRepoWrites.UpdateMerchantCapitalPendingOut(formattedPendingOutBalanceMinusPayout, formattedBalanceMinusPayout, transactionData.MerchantId)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				// create merchant capital flow for transaction amount
-				payloadMerchantCapitalFlowPayout := dto.CreateMerchantCapitalFlowPayload{
-					PaymentId:         transactionData.PaymentID,
-					MerchantAccountId: merchantAccountData.Id,
-					TempBalance:       formattedBalanceMinusPayout,
-					ReasonId:          constant.ReasonIdPayout,
-					Status:            strings.ToUpper(status),
-					CreateBy:          constant.CreateBySystem,
-					Amount:            helper.FormatFloat64(transactionData.TransactionAmount),
-					CapitalType:       constant.CapitalTypeDebit,
-				}
-				_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowPayout)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				// fee payout merchant
-				feeTransaction := helper.FormatFloat64(transactionData.MerchantFee)
-
-				// get merchant account after minus payout
-				merchantAccountDataUpdated, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(transactionData.MerchantId)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				balanceCapitalFlow = merchantAccountDataUpdated.BalanceCapitalFlow
-				pendingOutBalance = merchantAccountDataUpdated.PendingTransactionOut
-
-				// adjust balance minus fee
-				balanceCapitalMinusFee := balanceCapitalFlow - feeTransaction
-				pendingPayoutMinusFee := pendingOutBalance - feeTransaction
-				formattedPendingPayoutBalance := helper.FormatFloat64(pendingPayoutMinusFee)
-				formattedBalanceMinusFee := helper.FormatFloat64(balanceCapitalMinusFee)
-
-				err = tr.merchantRepoWrites.UpdateMerchantCapitalPendingOut(formattedPendingPayoutBalance, formattedBalanceMinusFee, transactionData.MerchantId)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				// create merchant capital flow for fee
-				payloadMerchantCapitalFlowFee := dto.CreateMerchantCapitalFlowPayload{
-					PaymentId:         transactionData.PaymentID,
-					MerchantAccountId: merchantAccountData.Id,
-					TempBalance:       formattedBalanceMinusFee,
-					ReasonId:          constant.ReasonIdFee,
-					Status:            strings.ToUpper(status),
-					CreateBy:          constant.CreateBySystem,
-					Amount:            feeTransaction,
-					CapitalType:       constant.CapitalTypeDebit,
-				}
-				_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowFee)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-			}
-		}
-	}
-	// end of blocked status handler SUCCESS
-
-	// start blocked code for handle for status FAILED
-	if strings.ToUpper(status) == constant.StatusFailed {
-
-		// update status change log
-		_, err := tr.transactionRepoWrites.CreateTransactionStatusLog(paymentId, constant.StatusLogFailed, username, notes, "")
-		if err != nil {
-			resp = dto.ResponseDto{
-				ResponseCode:    http.StatusUnprocessableEntity,
-				ResponseMessage: err.Error(),
-			}
-			return resp, err
+		if errors.Is(err, errIllegalTransition) {
+			resp.ResponseCode = http.StatusBadRequest
 		}
-
-		// handle for payin and FAILED
-		if transactionData.PayType == constant.PayTypePayin {
-			// get merchant account
-			merchantAccountData, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(transactionData.MerchantId)
-			if err != nil {
-				resp = dto.ResponseDto{
-					ResponseCode:    http.StatusUnprocessableEntity,
-					ResponseMessage: err.Error(),
-				}
-				return resp, err
-			}
-
-			// check if transaction have SUCCESS status before
-			if transactionData.Status == constant.StatusSuccess {
-				balanceUse := constant.SettleBalance
-
-				balanceCapitalFlow := merchantAccountData.BalanceCapitalFlow
-				settleOrNotSettleBalance := merchantAccountData.SettledBalance
-				if settleOrNotSettleBalance < transactionData.TransactionAmount {
-					balanceUse = constant.NotSettledBalance
-					settleOrNotSettleBalance = merchantAccountData.NotSettledBalance
-				}
-
-				// calculate fee for merchant capital flow
-				var feeTransactions float64
-				if transactionData.MerchantFeeType == constant.FeeTypePercentage {
-					calculateFee := math.Ceil(transactionData.TransactionAmount * (transactionData.MerchantFee / 100))
-					feeTransactions = helper.FormatFloat64(calculateFee)
-				} else {
-					feeTransactions = helper.FormatFloat64(transactionData.MerchantFee)
-				}
-
-				// transaction amount will minus fee first and take out from balance
-				transactionAmountMinusFee := transactionData.TransactionAmount - feeTransactions
-
-				balanceCapitalMinusFee := balanceCapitalFlow - transactionAmountMinusFee
-				settleOrNotSettleBalanceMinusFee := settleOrNotSettleBalance - transactionAmountMinusFee
-				formattedNotSettledOrSettleBalanceMinusFee := helper.FormatFloat64(settleOrNotSettleBalanceMinusFee)
-				formattedBalanceMinusFee := helper.FormatFloat64(balanceCapitalMinusFee)
-
-				if balanceUse == constant.NotSettledBalance {
-					err = tr.merchantRepoWrites.UpdateMerchantCapitalAndNotSettleBalance(formattedNotSettledOrSettleBalanceMinusFee, formattedBalanceMinusFee, transactionData.MerchantId)
-					if err != nil {
-						resp = dto.ResponseDto{
-							ResponseCode:    http.StatusUnprocessableEntity,
-							ResponseMessage: err.Error(),
-						}
-						return resp, err
-					}
-				}
-
-				if balanceUse == constant.SettleBalance {
-					// update merchant balance
-					err = tr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formattedNotSettledOrSettleBalanceMinusFee, formattedBalanceMinusFee, transactionData.MerchantId)
-					if err != nil {
-						resp = dto.ResponseDto{
-							ResponseCode:    http.StatusUnprocessableEntity,
-							ResponseMessage: err.Error(),
-						}
-						return resp, err
-					}
-				}
-
-				// create merchant capital flow for transaction amount that already minus on capital balance
-				payloadMerchantCapitalFlowPayin := dto.CreateMerchantCapitalFlowPayload{
-					PaymentId:         transactionData.PaymentID,
-					MerchantAccountId: merchantAccountData.Id,
-					TempBalance:       formattedBalanceMinusFee,
-					ReasonId:          constant.ReasonIdPayin,
-					Status:            constant.StatusReversed,
-					CreateBy:          constant.CreateBySystem,
-					Amount:            helper.FormatFloat64(transactionAmountMinusFee),
-					Notes:             "reversed balance due to manual change into failed",
-					CapitalType:       constant.CapitalTypeDebit,
-				}
-				_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowPayin)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-			}
-			// end of blocked code transaction have SUCCESS status before
-
-			// start of blocked code transaction have PROCESSING status change into FAILED
-			if transactionData.Status == constant.StatusProcessing {
-				// create merchant capital flow for transaction amount
-				payloadMerchantCapitalFlowPayin := dto.CreateMerchantCapitalFlowPayload{
-					PaymentId:         transactionData.PaymentID,
-					MerchantAccountId: merchantAccountData.Id,
-					TempBalance:       helper.FormatFloat64(merchantAccountData.BalanceCapitalFlow),
-					ReasonId:          constant.ReasonIdPayin,
-					Status:            strings.ToUpper(status),
-					CreateBy:          constant.CreateBySystem,
-					Amount:            helper.FormatFloat64(transactionData.TransactionAmount),
-					CapitalType:       constant.CapitalTypeNotDebitNotCredit,
-				}
-				_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowPayin)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				// calculate fee for merchant capital flow
-				var feeTransactions float64
-				if transactionData.MerchantFeeType == constant.FeeTypePercentage {
-					calculateFee := math.Ceil(transactionData.TransactionAmount * (transactionData.MerchantFee / 100))
-					feeTransactions = helper.FormatFloat64(calculateFee)
-				} else {
-					feeTransactions = helper.FormatFloat64(transactionData.MerchantFee)
-				}
-
-				// create merchant capital flow for fee
-				payloadMerchantCapitalFlowFee := dto.CreateMerchantCapitalFlowPayload{
-					PaymentId:         transactionData.PaymentID,
-					MerchantAccountId: merchantAccountData.Id,
-					TempBalance:       helper.FormatFloat64(merchantAccountData.BalanceCapitalFlow),
-					ReasonId:          constant.ReasonIdFee,
-					Status:            strings.ToUpper(status),
-					CreateBy:          constant.CreateBySystem,
-					Amount:            feeTransactions,
-					CapitalType:       constant.CapitalTypeNotDebitNotCredit,
-				}
-				_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowFee)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-			}
-			// end of blocked code transaction have PROCESSING status change into FAILED
-		}
-
-		// handle for payout and FAILED
-		if transactionData.PayType == constant.PayTypePayout {
-			// get merchant account
-			merchantAccountData, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(transactionData.MerchantId)
-			if err != nil {
-				resp = dto.ResponseDto{
-					ResponseCode:    http.StatusUnprocessableEntity,
-					ResponseMessage: err.Error(),
-				}
-				return resp, err
-			}
-
-			// check if transaction have SUCCESS status before
-			if transactionData.Status == constant.StatusSuccess {
-				balanceCapitalFlow := merchantAccountData.BalanceCapitalFlow
-				settledBalance := merchantAccountData.SettledBalance
-
-				// calculate fee for merchant capital flow
-				feeTransactions := helper.FormatFloat64(transactionData.MerchantFee)
-
-				balanceCapitalAddPayout := balanceCapitalFlow + transactionData.TransactionAmount
-				settleBalanceAddPayout := settledBalance + transactionData.TransactionAmount
-				formattedSettleBalanceAddPayout := helper.FormatFloat64(settleBalanceAddPayout)
-				formattedBalanceAddPayout := helper.FormatFloat64(balanceCapitalAddPayout)
-
-				// update merchant balance
-				err = tr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formattedSettleBalanceAddPayout, formattedBalanceAddPayout, transactionData.MerchantId)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				// create merchant capital flow for transaction amount that already add on capital balance
-				payloadMerchantCapitalFlowPayin := dto.CreateMerchantCapitalFlowPayload{
-					PaymentId:         transactionData.PaymentID,
-					MerchantAccountId: merchantAccountData.Id,
-					TempBalance:       formattedBalanceAddPayout,
-					ReasonId:          constant.ReasonIdPayout,
-					Status:            constant.StatusReversed,
-					CreateBy:          constant.CreateBySystem,
-					Amount:            helper.FormatFloat64(transactionData.TransactionAmount),
-					Notes:             "reversed balance due to manual change into failed",
-					CapitalType:       constant.CapitalTypeCredit,
-				}
-				_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowPayin)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				// get merchant account after add payout
-				merchantAccountDataUpdated, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(transactionData.MerchantId)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				balanceCapitalFlow = merchantAccountDataUpdated.BalanceCapitalFlow
-				settledBalance = merchantAccountDataUpdated.SettledBalance
-
-				// adjust balance add fee
-				balanceCapitalAddFee := balanceCapitalFlow + feeTransactions
-				settledBalanceAddFee := settledBalance + feeTransactions
-				formattedSettledBalanceAddFee := helper.FormatFloat64(settledBalanceAddFee)
-				formattedBalanceAddFee := helper.FormatFloat64(balanceCapitalAddFee)
-
-				err = tr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formattedSettledBalanceAddFee, formattedBalanceAddFee, transactionData.MerchantId)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				// create merchant capital flow for fee
-				payloadMerchantCapitalFlowFee := dto.CreateMerchantCapitalFlowPayload{
-					PaymentId:         transactionData.PaymentID,
-					MerchantAccountId: merchantAccountData.Id,
-					TempBalance:       formattedBalanceAddFee,
-					ReasonId:          constant.ReasonIdFee,
-					Status:            constant.StatusReversed,
-					CreateBy:          constant.CreateBySystem,
-					Amount:            feeTransactions,
-					Notes:             "reverse fee to balance due to manual failed",
-					CapitalType:       constant.CapitalTypeCredit,
-				}
-				_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowFee)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-			}
-			// end of blocked code transaction have SUCCESS status before
-
-			// start of blocked code transaction have PROCESSING status change into FAILED
-			if transactionData.Status == constant.StatusProcessing {
-				feeTransactions := helper.FormatFloat64(transactionData.MerchantFee)
-
-				// get merchant account
-				merchantAccountData, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(transactionData.MerchantId)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				transactionPlusFee := transactionData.TransactionAmount + feeTransactions
-				pendingOutBalance := merchantAccountData.PendingTransactionOut
-
-				// payout amount to balance
-				pendingOutBalanceMinusFeeAndPayout := pendingOutBalance - transactionPlusFee
-				formattedPendingOutBalanceMinusPayout := helper.FormatFloat64(pendingOutBalanceMinusFeeAndPayout)
-				formattedBalanceCapital := helper.FormatFloat64(merchantAccountData.BalanceCapitalFlow)
-
-				// update merchant balance
-				err = tr.merchantRepoWrites.UpdateMerchantCapitalPendingOut(formattedPendingOutBalanceMinusPayout, formattedBalanceCapital, transactionData.MerchantId)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				// get merchant account after minus payout
-				merchantAccountDataUpdated, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(transactionData.MerchantId)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				// adding settle balance from pending payout
-				settleBalance := merchantAccountDataUpdated.SettledBalance
-				settleBalanceAddFeeAndPayout := settleBalance + transactionPlusFee
-				formattedSettleBalance := helper.FormatFloat64(settleBalanceAddFeeAndPayout)
-				formattedBalanceCapitalUpdated := helper.FormatFloat64(merchantAccountDataUpdated.BalanceCapitalFlow)
-
-				// update merchant balance for settle balanc
-				err = tr.merchantRepoWrites.UpdateMerchantCapitalAndSettleBalance(formattedSettleBalance, formattedBalanceCapitalUpdated, merchantAccountDataUpdated.MerchantId)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				// create merchant capital flow for transaction amount
-				payloadMerchantCapitalFlowPayout := dto.CreateMerchantCapitalFlowPayload{
-					PaymentId:         transactionData.PaymentID,
-					MerchantAccountId: merchantAccountData.Id,
-					TempBalance:       helper.FormatFloat64(merchantAccountDataUpdated.BalanceCapitalFlow),
-					ReasonId:          constant.ReasonIdPayout,
-					Status:            strings.ToUpper(status),
-					CreateBy:          constant.CreateBySystem,
-					Amount:            helper.FormatFloat64(transactionData.TransactionAmount),
-					CapitalType:       constant.CapitalTypeNotDebitNotCredit,
-				}
-				_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowPayout)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-
-				// create merchant capital flow for fee
-				payloadMerchantCapitalFlowFee := dto.CreateMerchantCapitalFlowPayload{
-					PaymentId:         transactionData.PaymentID,
-					MerchantAccountId: merchantAccountData.Id,
-					TempBalance:       helper.FormatFloat64(merchantAccountDataUpdated.BalanceCapitalFlow),
-					ReasonId:          constant.ReasonIdFee,
-					Status:            strings.ToUpper(status),
-					CreateBy:          constant.CreateBySystem,
-					Amount:            feeTransactions,
-					CapitalType:       constant.CapitalTypeNotDebitNotCredit,
-				}
-				_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowFee)
-				if err != nil {
-					resp = dto.ResponseDto{
-						ResponseCode:    http.StatusUnprocessableEntity,
-						ResponseMessage: err.Error(),
-					}
-					return resp, err
-				}
-			}
+		if errors.Is(err, errTransitionForbidden) {
+			resp.ResponseCode = http.StatusForbidden
 		}
+		return resp, err
 	}
-	// end blocked code for handle FAILED status
 
 	msg := fmt.Sprintf("success updated transaction status for payment id %v", transactionData.PaymentID)
 	resp = dto.ResponseDto{
@@ -1495,7 +859,7 @@ func (tr *Transaction) GetTransactionDetailSvc(paymentId string) (dto.ResponseDt
 
 	// transaction detail data
 	if paymentData.PaymentMethodName == constant.VirtualAccountPaymentMethod {
-		transactionNetAmount := paymentData.TransactionAmount - paymentData.MerchantFee
+		transactionNetAmount := paymentData.TransactionAmount.Sub(paymentData.MerchantFee)
 		accountData, _ := tr.transactionRepoReads.GetAccountInformationByPaymentIdAccountType(paymentId, constant.AccountTypeCreditor)
 
 		transactionData = dto.TransactionMerchantData{
@@ -1514,9 +878,9 @@ func (tr *Transaction) GetTransactionDetailSvc(paymentId string) (dto.ResponseDt
 	}
 
 	if paymentData.PaymentMethodName == constant.QrisPaymentMethod || paymentData.PaymentMethodName == constant.EwalletPaymentMethod {
-		transactionFee := math.Ceil(paymentData.TransactionAmount * (paymentData.MerchantFee / 100))
-		transactionFeeFormatted := helper.FormatFloat64(transactionFee)
-		transactionNetAmount := paymentData.TransactionAmount - transactionFeeFormatted
+		transactionFee := paymentData.TransactionAmount.Percent(paymentData.MerchantFee, money.RoundUp)
+		transactionFeeFormatted := transactionFee
+		transactionNetAmount := paymentData.TransactionAmount.Sub(transactionFeeFormatted)
 
 		transactionData = dto.TransactionMerchantData{
 			Name:                  paymentData.MerchantName,
@@ -1534,7 +898,7 @@ func (tr *Transaction) GetTransactionDetailSvc(paymentId string) (dto.ResponseDt
 	}
 
 	if paymentData.PaymentMethodName == constant.DisbursementPaymentMethod {
-		transactionNetAmount := paymentData.TransactionAmount + paymentData.MerchantFee
+		transactionNetAmount := paymentData.TransactionAmount.Add(paymentData.MerchantFee)
 		accountData, _ := tr.transactionRepoReads.GetAccountInformationByPaymentIdAccountType(paymentId, constant.AccountTypeCreditor)
 
 		transactionData = dto.TransactionMerchantData{
@@ -1610,7 +974,6 @@ func (tr *Transaction) GetTransactionDetailSvc(paymentId string) (dto.ResponseDt
 
 func (tr *Transaction) MerchantDisbursementSvc(payload dto.MerchantDisbursement) (dto.ResponseDto, error) {
 	var resp dto.ResponseDto
-	var disburseMerchantChannel entity.MerchantPaychannel
 
 	// business validation
 	user, err := tr.userRepoReads.GetUserByUsername(payload.Username)
@@ -1624,15 +987,36 @@ func (tr *Transaction) MerchantDisbursementSvc(payload dto.MerchantDisbursement)
 	}
 
 	// check input pin
-	if !comparePasswords(user.Pin, []byte(payload.Pin)) {
+	resp, err = tr.credentialGuard.VerifyPin(user, payload.Pin)
+	if err != nil {
+		return resp, err
+	}
+
+	// amounts reaching a rule of the merchant wait for its approvers
+	rule, err := tr.disbursementApprovalReads.GetDisbursementApprovalRuleForAmountRepo(*user.MerchantID, payload.Amount)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		slog.Errorw("failed get disbursement approval rule", "stack_trace", err.Error())
 		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "wrong pin",
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
 		}
-		return resp, errors.New("wrong pin")
+		return resp, err
+	}
+
+	if err == nil {
+		return tr.requestDisbursementApproval(*user.MerchantID, rule, payload)
 	}
 
-	merchantData, err := tr.merchantRepoReads.GetMerchantDataByMerchantId(*user.MerchantID)
+	return tr.disburse(*user.MerchantID, payload)
+}
+
+// disburse validates the disbursement against the routing and the balance of the merchant, sends it to the
+// provider and reserves the balance
+func (tr *Transaction) disburse(merchantId string, payload dto.MerchantDisbursement) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+	var disburseMerchantChannel entity.MerchantPaychannel
+
+	merchantData, err := tr.merchantRepoReads.GetMerchantDataByMerchantId(merchantId)
 	if err != nil {
 		slog.Infof("username: %v, failed get merchant data, err: %v", payload.Username, err.Error())
 		resp = dto.ResponseDto{
@@ -1650,7 +1034,7 @@ func (tr *Transaction) MerchantDisbursementSvc(payload dto.MerchantDisbursement)
 		return resp, errors.New("insufficient")
 	}
 
-	accountBalance, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(*user.MerchantID)
+	accountBalance, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(merchantId)
 	if err != nil {
 		slog.Infof("username: %v, failed get account balance, err: %v", payload.Username, err.Error())
 		resp = dto.ResponseDto{
@@ -1660,7 +1044,17 @@ func (tr *Transaction) MerchantDisbursementSvc(payload dto.MerchantDisbursement)
 		return resp, err
 	}
 
-	listMerchantPaychannel, err := tr.merchantRepoReads.GetMerchantPaychannelByMerchantId(*user.MerchantID)
+	accountBalance, err = ledgerMerchantAccount(tr.ledgerRepoWrites, accountBalance)
+	if err != nil {
+		slog.Infof("username: %v, failed get ledger balance, err: %v", payload.Username, err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
+	listMerchantPaychannel, err := tr.merchantRepoReads.GetMerchantPaychannelByMerchantId(merchantId)
 	if err != nil {
 		slog.Infof("username: %v, failed get merchant channel, err: %v", payload.Username, err.Error())
 		resp = dto.ResponseDto{
@@ -1692,8 +1086,9 @@ func (tr *Transaction) MerchantDisbursementSvc(payload dto.MerchantDisbursement)
 		return resp, errors.New("insufficient")
 	}
 
-	amountPlusFee := float64(payload.Amount) + disburseMerchantChannel.Fee
-	if amountPlusFee > accountBalance.SettledBalance {
+	// fails early only, the balance is checked again under the account lock when it is reserved
+	amountPlusFee := payload.Amount.Add(disburseMerchantChannel.Fee)
+	if amountPlusFee.GreaterThan(accountBalance.SettledBalance) {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusBadRequest,
 			ResponseMessage: "not enough balance for disbursement",
@@ -1727,8 +1122,8 @@ func (tr *Transaction) MerchantDisbursementSvc(payload dto.MerchantDisbursement)
 		return resp, errors.New("insufficient")
 	}
 
-	if disburseMerchantChannel.MinTransaction > 0 || disburseMerchantChannel.MaxTransaction > 0 {
-		if float64(payload.Amount) < disburseMerchantChannel.MinTransaction || float64(payload.Amount) > disburseMerchantChannel.MaxTransaction {
+	if disburseMerchantChannel.MinTransaction.IsPositive() || disburseMerchantChannel.MaxTransaction.IsPositive() {
+		if payload.Amount.LessThan(disburseMerchantChannel.MinTransaction) || payload.Amount.GreaterThan(disburseMerchantChannel.MaxTransaction) {
 			resp = dto.ResponseDto{
 				ResponseCode:    http.StatusBadRequest,
 				ResponseMessage: "amount limit",
@@ -1737,8 +1132,8 @@ func (tr *Transaction) MerchantDisbursementSvc(payload dto.MerchantDisbursement)
 		}
 	}
 
-	if getRoutedChannel[0].MinTransaction > 0 || getRoutedChannel[0].MaxTransaction > 0 {
-		if float64(payload.Amount) < getRoutedChannel[0].MinTransaction || float64(payload.Amount) > getRoutedChannel[0].MaxTransaction {
+	if getRoutedChannel[0].MinTransaction.IsPositive() || getRoutedChannel[0].MaxTransaction.IsPositive() {
+		if payload.Amount.LessThan(getRoutedChannel[0].MinTransaction) || payload.Amount.GreaterThan(getRoutedChannel[0].MaxTransaction) {
 			resp = dto.ResponseDto{
 				ResponseCode:    http.StatusBadRequest,
 				ResponseMessage: "amount limit",
@@ -1759,6 +1154,16 @@ func (tr *Transaction) MerchantDisbursementSvc(payload dto.MerchantDisbursement)
 		return resp, err
 	}
 
+	credentials, err = tr.openCredentials(credentials)
+	if err != nil {
+		slog.Errorw("failed open provider credentials", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+		return resp, err
+	}
+
 	bankData, err := tr.transactionRepoReads.GetBankDataDetailRepo(payload.BankName)
 	if err != nil {
 		slog.Infof("username: %v, got err: %v", payload.Username, err.Error())
@@ -1775,7 +1180,14 @@ func (tr *Transaction) MerchantDisbursementSvc(payload dto.MerchantDisbursement)
 		BankCode:             bankData.BankCode,
 	}
 
-	_, err = tr.disbursementSupport(credentials, payload, *user.MerchantID, disburseMerchantChannel.Fee, channelIdCodePayload)
+	paymentId, err := tr.disbursementSupport(providerId, interfaceSetting, credentials, payload, merchantId, disburseMerchantChannel.Fee, channelIdCodePayload)
+	if errors.Is(err, errInsufficientBalance) {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "not enough balance for disbursement",
+		}
+		return resp, errors.New("insufficient")
+	}
 	if err != nil {
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusUnprocessableEntity,
@@ -1787,6 +1199,9 @@ func (tr *Transaction) MerchantDisbursementSvc(payload dto.MerchantDisbursement)
 	resp = dto.ResponseDto{
 		ResponseCode:    http.StatusOK,
 		ResponseMessage: fmt.Sprintf("success dibursement with bank name: %v, account number: %v, account name: %v", payload.BankName, payload.BankAccountNumber, payload.BankAccountName),
+		Data: dto.MerchantDisbursementRespDto{
+			PaymentId: paymentId,
+		},
 	}
 
 	return resp, nil
@@ -1929,7 +1344,7 @@ func (tr *Transaction) CountDisbursementTotalAmountSvc(payload dto.CountDisburse
 		return resp, nil
 	}
 
-	countTotalAmount := float64(payload.Amount) + disburseMerchantChannel.Fee
+	countTotalAmount := payload.Amount.Add(disburseMerchantChannel.Fee)
 
 	countResp := dto.CountDisbursementRespDto{
 		FeeAmount:   disburseMerchantChannel.Fee,
@@ -1945,191 +1360,222 @@ func (tr *Transaction) CountDisbursementTotalAmountSvc(payload dto.CountDisburse
 	return resp, nil
 }
 
-func (tr *Transaction) JackDisbursementCallbackHandlingSvc(payload dto.CreateDisbursementRequestResponseData) (string, error) {
-	amountInt := converter.FromStringToIntAmount(payload.Destination.Amount)
+// DisbursementCallbackHandlingSvc stores the raw callback, verifies its source and signature with the
+// adapter of the provider and settles the disbursement once, the result is the provider callback process status
+func (tr *Transaction) DisbursementCallbackHandlingSvc(payload dto.ProviderCallbackReq) (string, error) {
+	payoutProvider, err := tr.payoutProviders.Resolve(payload.ProviderId, "")
+	if err != nil {
+		slog.Infof("DisbursementCallbackHandlingSvc %v got err: %v", payload.ProviderId, err.Error())
+		return "", err
+	}
+
+	// parsing has no side effect, a body that can't be parsed is still stored below
+	result, parseErr := payoutProvider.ParseCallback(payload.Body)
+	dedupKey := callbackDedupKey(payload.Body)
+	if parseErr == nil {
+		dedupKey = strings.Join([]string{result.PaymentId, result.ProviderReferenceId, result.Status}, ":")
+	}
 
-	slog.Infof("Jack %v callback payload: %v", payload.ReferenceID, payload)
+	callbackId, err := tr.providerRepoWrites.CreateProviderCallbackRepo(dto.CreateProviderCallbackPayload{
+		ProviderId:     payload.ProviderId,
+		DedupKey:       dedupKey,
+		PaymentId:      result.PaymentId,
+		CallbackStatus: result.Status,
+		SourceIp:       payload.SourceIp,
+		RawBody:        string(payload.Body),
+	})
+	if err != nil {
+		slog.Infof("DisbursementCallbackHandlingSvc %v failed to store callback: %v", payload.ProviderId, err.Error())
+		return "", err
+	}
 
-	if payload.State == constant.JackStateStatusDeclined || payload.State == constant.JackStateStatusCanceled {
-		// update status into failed
-		err := tr.transactionRepoWrites.UpdateStatus(constant.StatusFailed, payload.ReferenceID)
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
-		}
+	// the provider retried a callback that is processed or still being processed
+	if callbackId == 0 {
+		slog.Infof("DisbursementCallbackHandlingSvc %v duplicate callback %v ignored", payload.ProviderId, dedupKey)
+		return constant.ProviderCallbackIgnored, nil
+	}
 
-		// update merchant balance
-		detailTransaction, err := tr.transactionRepoReads.GetPaymentDetailProviderMerchant(payload.ReferenceID)
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
-		}
+	processStatus, notes, err := tr.processDisbursementCallback(payoutProvider, payload, result, parseErr)
+	if err != nil {
+		notes = err.Error()
+	}
 
-		merchantBalance, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(detailTransaction.MerchantId)
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
-		}
+	errUpdate := tr.providerRepoWrites.UpdateProviderCallbackStatusRepo(callbackId, processStatus, notes)
+	if errUpdate != nil {
+		slog.Infof("DisbursementCallbackHandlingSvc %v failed to update callback %v: %v", result.PaymentId, callbackId, errUpdate.Error())
+	}
 
-		settleBalance := merchantBalance.SettledBalance
-		pendingPayout := merchantBalance.PendingTransactionOut
+	if err != nil {
+		slog.Infof("DisbursementCallbackHandlingSvc %v callback %v %v: %v", result.PaymentId, callbackId, processStatus, err.Error())
+		return processStatus, err
+	}
 
-		settleBalancePlusOut := settleBalance + float64(amountInt) + detailTransaction.MerchantFee
-		pendingPayoutMinusOut := pendingPayout - float64(amountInt) - detailTransaction.MerchantFee
-		err = tr.merchantRepoWrites.UpdateMerchantBalanceSettleAndPendingOutBalanceRepo(settleBalancePlusOut, pendingPayoutMinusOut, detailTransaction.MerchantId)
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
-		}
-
-		// create merchant capital flow for transaction amount
-		payloadMerchantCapitalFlowPayout := dto.CreateMerchantCapitalFlowPayload{
-			PaymentId:         payload.ReferenceID,
-			MerchantAccountId: merchantBalance.Id,
-			TempBalance:       helper.FormatFloat64(merchantBalance.BalanceCapitalFlow),
-			ReasonId:          constant.ReasonIdPayout,
-			Status:            strings.ToUpper(constant.StatusFailed),
-			CreateBy:          constant.CreateBySystem,
-			Amount:            helper.FormatFloat64(float64(amountInt)),
-			CapitalType:       constant.CapitalTypeNotDebitNotCredit,
-		}
-		_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowPayout)
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
-		}
-
-		// create merchant capital flow for fee
-		payloadMerchantCapitalFlowFee := dto.CreateMerchantCapitalFlowPayload{
-			PaymentId:         payload.ReferenceID,
-			MerchantAccountId: merchantBalance.Id,
-			TempBalance:       helper.FormatFloat64(merchantBalance.BalanceCapitalFlow),
-			ReasonId:          constant.ReasonIdFee,
-			Status:            strings.ToUpper(constant.StatusFailed),
-			CreateBy:          constant.CreateBySystem,
-			Amount:            detailTransaction.MerchantFee,
-			CapitalType:       constant.CapitalTypeNotDebitNotCredit,
-		}
-		_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowFee)
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
-		}
+	slog.Infof("DisbursementCallbackHandlingSvc %v callback %v %v", result.PaymentId, callbackId, processStatus)
+	return processStatus, nil
+}
 
-		// update status transaction log
-		_, err = tr.transactionRepoWrites.CreateTransactionStatusLog(payload.ReferenceID, constant.StatusLogFailed, constant.CreateBySystem, constant.GeneralErrMsg, payload.ErrorMessage)
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
-		}
+func (tr *Transaction) processDisbursementCallback(payoutProvider internal.PayoutProviderItf, payload dto.ProviderCallbackReq, result dto.PayoutResult, parseErr error) (string, string, error) {
+	credentials, err := tr.providerRepoReads.GetProviderCredentialsByKeysRepo(payload.ProviderId, []string{constant.CallbackSecretCred, constant.CallbackIpAllowlistCred})
+	if err != nil {
+		return constant.ProviderCallbackFailed, "", err
+	}
 
-		// create provider confirmation detail
-		_, err = tr.providerRepoWrites.CreateProviderConfirmationDetail(constant.SourceCallback, payload.ReferenceID, constant.StatusFailed)
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
-		}
+	credentials, err = tr.openCredentials(credentials)
+	if err != nil {
+		return constant.ProviderCallbackFailed, "", err
 	}
 
-	if payload.State == constant.JackStateStatusCompleted {
-		// update status into success
-		err := tr.transactionRepoWrites.UpdateStatus(constant.StatusSuccess, payload.ReferenceID)
+	if !callbackSourceAllowed(payload.SourceIp, credentials) {
+		return constant.ProviderCallbackRejected, "", fmt.Errorf("source ip %v is not allowed", payload.SourceIp)
+	}
+
+	err = payoutProvider.VerifyCallback(payload.Headers, payload.Body, credentials)
+	if err != nil {
+		return constant.ProviderCallbackRejected, "", err
+	}
+
+	if parseErr != nil {
+		return constant.ProviderCallbackRejected, "", parseErr
+	}
+
+	if result.Amount.LessThan(constant.DisbursementMinAmount) || result.Amount.GreaterThan(constant.DisbursementMaxAmount) {
+		return constant.ProviderCallbackRejected, "", errors.New("invalid amount")
+	}
+
+	if result.Status != constant.StatusSuccess && result.Status != constant.StatusFailed {
+		return constant.ProviderCallbackIgnored, "state is not final", nil
+	}
+
+	processStatus := constant.ProviderCallbackApplied
+	notes := ""
+	err = tr.unitOfWork.WithinTransaction(func(repos internal.UnitOfWorkRepos) error {
+		trTx := tr.withUnitOfWork(repos)
+
+		// the transaction row lock serializes concurrent callbacks of the same payment
+		currentStatus, err := trTx.transactionRepoWrites.GetTransactionStatusForUpdateRepo(result.PaymentId)
 		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
+			return err
 		}
 
-		// update merchant balance
-		detailTransaction, err := tr.transactionRepoReads.GetPaymentDetailProviderMerchant(payload.ReferenceID)
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
+		if currentStatus != constant.StatusProcessing {
+			processStatus = constant.ProviderCallbackIgnored
+			notes = fmt.Sprintf("%v callback ignored, transaction already %v", result.Status, currentStatus)
+			_, err = trTx.providerRepoWrites.CreateProviderConfirmationDetailWithNotesRepo(constant.SourceCallback, result.PaymentId, constant.ConfirmationResultDuplicateIgnored, notes)
+			return err
 		}
 
-		merchantBalance, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(detailTransaction.MerchantId)
+		detailTransaction, err := trTx.transactionRepoReads.GetPaymentDetailProviderMerchant(result.PaymentId)
 		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
+			return err
 		}
 
-		pendingPayout := merchantBalance.PendingTransactionOut
-		BalanceCapital := merchantBalance.BalanceCapitalFlow
+		// a callback of another amount is not about this disbursement, it changes nothing
+		if !result.Amount.Equal(detailTransaction.TransactionAmount) {
+			processStatus = constant.ProviderCallbackRejected
+			return fmt.Errorf("callback amount %v doesn't match transaction amount %v", result.Amount, detailTransaction.TransactionAmount)
+		}
 
-		pendingPayoutMinusOut := pendingPayout - float64(amountInt)
-		balanceCapitalMinusOut := BalanceCapital - float64(amountInt)
-		err = tr.merchantRepoWrites.UpdateMerchantCapitalPendingOut(pendingPayoutMinusOut, balanceCapitalMinusOut, detailTransaction.MerchantId)
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
-		}
-
-		// create merchant capital flow out
-		payloadMerchantCapitalFlowOut := dto.CreateMerchantCapitalFlowPayload{
-			PaymentId:         payload.ReferenceID,
-			MerchantAccountId: merchantBalance.Id,
-			TempBalance:       balanceCapitalMinusOut,
-			ReasonId:          constant.ReasonIdPayout,
-			Status:            strings.ToUpper(constant.StatusSuccess),
-			CreateBy:          constant.CreateBySystem,
-			Amount:            helper.FormatFloat64(float64(amountInt)),
-			CapitalType:       constant.CapitalTypeDebit,
-		}
-		_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowOut)
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
+		detailTransaction.Status = currentStatus
+		return trTx.disbursementCallbackHandling(result, detailTransaction)
+	})
+	if err != nil {
+		if processStatus == constant.ProviderCallbackRejected {
+			return processStatus, "", err
 		}
+		return constant.ProviderCallbackFailed, "", err
+	}
+
+	return processStatus, notes, nil
+}
 
-		merchantBalanceUpdate, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(detailTransaction.MerchantId)
+// openCredentials decrypts the credential values right before they are handed to a payout provider
+func (tr *Transaction) openCredentials(credentials []entity.ProviderCredentialsEntity) ([]entity.ProviderCredentialsEntity, error) {
+	opened := make([]entity.ProviderCredentialsEntity, len(credentials))
+	for i, cred := range credentials {
+		value, err := tr.keyring.Open(cred.Value, providerCredentialData(cred.ProviderId, cred.Key))
 		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
+			return nil, fmt.Errorf("credential %v of provider %v: %w", cred.Key, cred.ProviderId, err)
 		}
 
-		pendingPayoutUpdated := merchantBalanceUpdate.PendingTransactionOut
-		balanceCapitalUpdate := merchantBalanceUpdate.BalanceCapitalFlow
+		cred.Value = value
+		opened[i] = cred
+	}
 
-		pendingPayoutUpdatedMinusFee := pendingPayoutUpdated - detailTransaction.MerchantFee
-		balanceCapitalUpdateMinusFee := balanceCapitalUpdate - detailTransaction.MerchantFee
-		err = tr.merchantRepoWrites.UpdateMerchantCapitalPendingOut(pendingPayoutUpdatedMinusFee, balanceCapitalUpdateMinusFee, detailTransaction.MerchantId)
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
-		}
-
-		// create merchant capital fee
-		payloadMerchantCapitalFlowFee := dto.CreateMerchantCapitalFlowPayload{
-			PaymentId:         payload.ReferenceID,
-			MerchantAccountId: merchantBalance.Id,
-			TempBalance:       balanceCapitalUpdateMinusFee,
-			ReasonId:          constant.ReasonIdFee,
-			Status:            strings.ToUpper(constant.StatusSuccess),
-			CreateBy:          constant.CreateBySystem,
-			Amount:            detailTransaction.MerchantFee,
-			CapitalType:       constant.CapitalTypeDebit,
-		}
-		_, err = tr.merchantRepoWrites.CreateMerchantCapitalFlow(payloadMerchantCapitalFlowFee)
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
+	return opened, nil
+}
+
+// providerCredentialData binds a sealed credential value to the provider and key it belongs to
+func providerCredentialData(providerId string, key string) string {
+	return envelope.AdditionalData("provider_credentials.value", providerId, key)
+}
+
+// callbackSourceAllowed checks ip against the allowlist, no allowlist configured allows every source
+func callbackSourceAllowed(ip string, credentials []entity.ProviderCredentialsEntity) bool {
+	var allowlist []string
+	for _, cred := range credentials {
+		if cred.Key == constant.CallbackIpAllowlistCred && strings.TrimSpace(cred.Value) != "" {
+			allowlist = append(allowlist, strings.Split(cred.Value, ",")...)
 		}
+	}
 
-		// update transaction status log
-		_, err = tr.transactionRepoWrites.CreateTransactionStatusLog(payload.ReferenceID, constant.StatusLogSuccess, constant.CreateBySystem, "", "")
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
+	if len(allowlist) == 0 {
+		return true
+	}
+
+	sourceIp := net.ParseIP(ip)
+	if sourceIp == nil {
+		return false
+	}
+
+	for _, allowed := range allowlist {
+		allowed = strings.TrimSpace(allowed)
+		if _, ipNet, err := net.ParseCIDR(allowed); err == nil {
+			if ipNet.Contains(sourceIp) {
+				return true
+			}
+			continue
 		}
 
-		// create provider confirmation detail
-		_, err = tr.providerRepoWrites.CreateProviderConfirmationDetail(constant.SourceCallback, payload.ReferenceID, constant.StatusSuccess)
-		if err != nil {
-			slog.Infof("JackDisbursementHandlingSvc %v got err: %v", payload.ReferenceID, err.Error())
-			return "", err
+		if allowedIp := net.ParseIP(allowed); allowedIp != nil && allowedIp.Equal(sourceIp) {
+			return true
 		}
 	}
 
-	return "ok", nil
+	return false
+}
+
+// callbackDedupKey identifies a callback body that couldn't be parsed
+func callbackDedupKey(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// disbursementCallbackHandling settles a locked disbursement with the final state reported by the provider
+func (tr *Transaction) disbursementCallbackHandling(payload dto.PayoutResult, detailTransaction entity.PaymentDetailMerchantProvider) error {
+	change := transactionStatusChange{
+		transaction: detailTransaction,
+		to:          payload.Status,
+		changeBy:    constant.CreateBySystem,
+	}
+	if payload.Status == constant.StatusFailed {
+		change.notes = constant.GeneralErrMsg
+		change.realNotes = payload.ErrorMessage
+	}
+
+	err := tr.transitionTransaction(change)
+	if err != nil {
+		slog.Infof("DisbursementCallbackHandlingSvc %v got err: %v", payload.PaymentId, err.Error())
+		return err
+	}
+
+	// create provider confirmation detail
+	_, err = tr.providerRepoWrites.CreateProviderConfirmationDetail(constant.SourceCallback, payload.PaymentId, payload.Status)
+	if err != nil {
+		slog.Infof("DisbursementCallbackHandlingSvc %v got err: %v", payload.PaymentId, err.Error())
+		return err
+	}
+
+	return nil
 }
 
 func (tr *Transaction) GetReportListMerchantSvc(req dto.GetListMerchantExportFilter, username string) (dto.ResponseDto, error) {
@@ -2144,6 +1590,14 @@ func (tr *Transaction) GetReportListMerchantSvc(req dto.GetListMerchantExportFil
 		return resp, err
 	}
 
+	if user.MerchantID == nil || *user.MerchantID == "" {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "user doesn't belong to a merchant",
+		}
+		return resp, errors.New("user without merchant")
+	}
+
 	if req.MinDate == "" {
 		req.MinDate = helper.GenerateTime(0)
 	}
@@ -2330,12 +1784,12 @@ func (tr *Transaction) supportExportTypeCapitalFlow(payload dto.CreateMerchantEx
 				item.PaymentId,
 				nullSafeString(item.MerchantId),
 				nullSafeString(item.MerchantName),
-				nullSafeFloat64(item.Amount),
+				nullSafeMoney(item.Amount),
 				nullSafeString(item.ReasonName),
 				nullSafeString(item.PaymentMethod),
-				nullSafeFloat64(item.Fee),
+				nullSafeMoney(item.Fee),
 				nullSafeString(item.FeeType),
-				nullSafeFloat64(item.MerchantBalance),
+				nullSafeMoney(item.MerchantBalance),
 				nullSafeString(item.Status),
 				nullSafeString(item.Notes),
 				nullSafeString(item.CapitalType),
@@ -2374,15 +1828,15 @@ func (tr *Transaction) supportExportTypeCapitalFlow(payload dto.CreateMerchantEx
 				item.PaymentId,
 				nullSafeString(item.MerchantId),
 				nullSafeString(item.MerchantName),
-				nullSafeFloat64(item.Amount),
+				nullSafeMoney(item.Amount),
 				nullSafeString(item.ReasonName),
 				nullSafeString(item.PaymentMethod),
-				nullSafeFloat64(item.Fee),
+				nullSafeMoney(item.Fee),
 				nullSafeString(item.FeeType),
-				nullSafeFloat64(item.MerchantBalance),
+				nullSafeMoney(item.MerchantBalance),
 				nullSafeString(item.Provider),
 				nullSafeString(item.PaychannelRouted),
-				nullSafeFloat64(item.ProviderFee),
+				nullSafeMoney(item.ProviderFee),
 				nullSafeString(item.ProviderFeeType),
 				nullSafeString(item.Status),
 				nullSafeString(item.Notes),
@@ -2416,47 +1870,51 @@ func (tr *Transaction) supportExportTypeCapitalFlow(payload dto.CreateMerchantEx
 	return "ok", nil
 }
 
-func (tr *Transaction) disbursementSupport(credentials []entity.ProviderCredentialsEntity, payload dto.MerchantDisbursement, merchantId string, merchantFee float64, channelCodeId dto.ChannelIdCodeDisbursement) (string, error) {
-	providerId := credentials[0].ProviderId
-
-	if providerId == constant.ProviderJack {
-		_, err := tr.jackSupportDisbursement(credentials, payload, merchantId, merchantFee, channelCodeId)
-		if err != nil {
-			slog.Infof("username: %v, disbursementSupport got err %v", payload.Username, err.Error())
-			return "", err
-		}
-	}
-
-	if !helper.StringInSlice(providerId, constant.ProviderListName) {
+// disbursementSupport returns the payment id of the disbursement
+func (tr *Transaction) disbursementSupport(providerId string, interfaceSetting string, credentials []entity.ProviderCredentialsEntity, payload dto.MerchantDisbursement, merchantId string, merchantFee money.Money, channelCodeId dto.ChannelIdCodeDisbursement) (string, error) {
+	payoutProvider, err := tr.payoutProviders.Resolve(providerId, interfaceSetting)
+	if err != nil {
+		slog.Infof("username: %v, disbursementSupport got err %v", payload.Username, err.Error())
 		return "", errors.New("this merchant not routed for disbursement")
 	}
 
-	return "ok", nil
-}
+	bankCode, err := tr.providerRepoReads.GetProviderBankCodeRepo(providerId, channelCodeId.BankCode)
+	if err != nil {
+		slog.Infof("username: %v, bank code %v not mapped for provider %v: %v", payload.Username, channelCodeId.BankCode, providerId, err.Error())
+		return "", errors.New("bank not supported for disbursement")
+	}
 
-func (tr *Transaction) jackSupportDisbursement(credentials []entity.ProviderCredentialsEntity, payload dto.MerchantDisbursement, merchantId string, merchantFee float64, channelCodeId dto.ChannelIdCodeDisbursement) (string, error) {
 	randomStr := helper.GenerateRandomString(30)
 	randomStrMerchantReferenceNumber := helper.GenerateRandomString(30)
 	paymentId := "out_dsb-" + randomStr
 	merchantReferenceNumber := merchantId + "-" + randomStrMerchantReferenceNumber
-	currentBalance, err := tr.jackProvider.GetBalance(payload.Username, credentials)
+	payoutRequest := dto.PayoutRequest{
+		Username:          payload.Username,
+		PaymentId:         paymentId,
+		Amount:            payload.Amount,
+		BankAccountNumber: payload.BankAccountNumber,
+		BankAccountName:   payload.BankAccountName,
+		Bank:              bankCode,
+	}
+
+	currentBalance, err := payoutProvider.GetBalance(payload.Username, credentials)
 	if err != nil {
 		slog.Infof("username: %v, disbursementSupport got err %v", payload.Username, err.Error())
 		return "", err
 	}
 
-	if payload.Amount > currentBalance {
+	if payload.Amount.GreaterThan(currentBalance) {
 		slog.Infof("username: %v, disbursement limit", payload.Username)
 		return "", errors.New("amount limit")
 	}
 
-	inquiryData, err := tr.jackProvider.InquiryAccount(payload, credentials, channelCodeId.BankCode)
+	inquiryData, err := payoutProvider.InquiryAccount(payoutRequest, credentials)
 	if err != nil {
 		slog.Infof("username: %v, disbursementSupport got err %v", payload.Username, err.Error())
 		return "", err
 	}
 
-	accountHolder := inquiryData.Data.AccountName
+	accountHolder := inquiryData.AccountName
 	responseName := tr.regex.ReplaceAllString(accountHolder, "")
 	requestName := tr.regex.ReplaceAllString(payload.BankAccountName, "")
 	similarWord := helper.CompareTwoStrings(strings.ToLower(responseName), strings.ToLower(requestName))
@@ -2465,52 +1923,123 @@ func (tr *Transaction) jackSupportDisbursement(credentials []entity.ProviderCred
 		return "", errors.New("name validation not match")
 	}
 
-	createDisbursement, err := tr.jackProvider.CreateDisbursement(payload, credentials, channelCodeId.BankCode, paymentId)
+	// the balance is reserved and the transaction written under the account lock before the provider moves any
+	// money, so disbursements running at the same time can't spend it twice and a callback always finds it
+	err = tr.unitOfWork.WithinTransaction(func(repos internal.UnitOfWorkRepos) error {
+		trTx := tr.withUnitOfWork(repos)
+
+		err := trTx.reserveDisbursement(payload, merchantId, merchantFee, paymentId)
+		if err != nil {
+			return err
+		}
+
+		return trTx.recordDisbursement(payload, channelCodeId, paymentId, merchantReferenceNumber)
+	})
 	if err != nil {
 		slog.Infof("username: %v, disbursementSupport got err %v", payload.Username, err.Error())
 		return "", err
 	}
 
-	providerCreateId := converter.ToString(createDisbursement.Data.ID)
-	confirmTransactionData := dto.ConfirmTransactionPayload{
-		Username:   payload.Username,
-		ProviderID: providerCreateId,
+	createDisbursement, err := payoutProvider.CreatePayout(payoutRequest, credentials)
+	if err != nil {
+		slog.Infof("username: %v, disbursementSupport got err %v", payload.Username, err.Error())
+		return "", tr.failDisbursement(paymentId, err)
 	}
 
-	confirm, err := tr.jackProvider.ConfirmDisbursement(confirmTransactionData, credentials)
+	confirm, err := payoutProvider.ConfirmPayout(payload.Username, createDisbursement.ProviderReferenceId, credentials)
 	if err != nil {
 		slog.Infof("username: %v, disbursementSupport got err %v", payload.Username, err.Error())
-		return "", err
+		return "", tr.failDisbursement(paymentId, err)
 	}
 
-	// update merchant account
-	merchantAccount, err := tr.merchantRepoReads.GetMerchantAccountByMerchantId(merchantId)
+	// the provider took the disbursement, without its reference the transaction is still settled by the callback
+	err = tr.unitOfWork.WithinTransaction(func(repos internal.UnitOfWorkRepos) error {
+		return tr.withUnitOfWork(repos).acceptDisbursement(paymentId, confirm.ProviderReferenceId)
+	})
 	if err != nil {
-		slog.Infof("username: %v, disbursementSupport got err %v", payload.Username, err.Error())
-		return "", err
+		slog.Errorw(fmt.Sprintf("username: %v, failed record provider reference of disbursement %v", payload.Username, paymentId), "stack_trace", err.Error())
+	}
+
+	return paymentId, nil
+}
+
+// reserveDisbursement moves amount and fee from settled to pending out once the locked account still covers them
+func (tr *Transaction) reserveDisbursement(payload dto.MerchantDisbursement, merchantId string, merchantFee money.Money, paymentId string) error {
+	// lock merchant account
+	merchantAccount, err := tr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(merchantId)
+	if err != nil {
+		return err
+	}
+
+	ledgerAccount, err := ledgerMerchantAccount(tr.ledgerRepoWrites, merchantAccount)
+	if err != nil {
+		return err
+	}
+
+	amountPlusFee := payload.Amount.Add(merchantFee)
+	if amountPlusFee.GreaterThan(ledgerAccount.SettledBalance) {
+		return errInsufficientBalance
 	}
 
 	// balance adjustment with fee
-	settleBalance := merchantAccount.SettledBalance
-	pendingOutBalance := merchantAccount.PendingTransactionOut
-	settleBalanceMinusOutAndFee := settleBalance - float64(payload.Amount) - merchantFee
-	pendingOutBalancePlusOutAndFee := pendingOutBalance + float64(payload.Amount) + merchantFee
+	settleBalanceMinusOutAndFee := merchantAccount.SettledBalance.Sub(amountPlusFee)
+	pendingOutBalancePlusOutAndFee := merchantAccount.PendingTransactionOut.Add(amountPlusFee)
 
 	// updated merchant settle balance
 	err = tr.merchantRepoWrites.UpdateMerchantBalanceSettleAndPendingOutBalanceRepo(settleBalanceMinusOutAndFee, pendingOutBalancePlusOutAndFee, merchantId)
 	if err != nil {
-		slog.Infof("username: %v, disbursementSupport got err %v", payload.Username, err.Error())
-		return "", err
+		return err
 	}
 
+	// post ledger journal
+	journal := newLedgerJournal(paymentId, constant.ReasonIdPayout, payload.Username, payload.Note).
+		move(merchantLedgerAccount(merchantId, constant.LedgerAccountSettled), merchantLedgerAccount(merchantId, constant.LedgerAccountPendingOut), amountPlusFee)
+	return postLedgerJournal(tr.ledgerRepoWrites, journal)
+}
+
+// failDisbursement fails the transaction of a disbursement the provider didn't take, which gives its reservation
+// back. cause is returned, together with the failure to release when there is one.
+func (tr *Transaction) failDisbursement(paymentId string, cause error) error {
+	err := tr.unitOfWork.WithinTransaction(func(repos internal.UnitOfWorkRepos) error {
+		trTx := tr.withUnitOfWork(repos)
+
+		currentStatus, err := trTx.transactionRepoWrites.GetTransactionStatusForUpdateRepo(paymentId)
+		if err != nil {
+			return err
+		}
+
+		detailTransaction, err := trTx.transactionRepoReads.GetPaymentDetailProviderMerchant(paymentId)
+		if err != nil {
+			return err
+		}
+		detailTransaction.Status = currentStatus
+
+		return trTx.transitionTransaction(transactionStatusChange{
+			transaction: detailTransaction,
+			to:          constant.StatusFailed,
+			changeBy:    constant.CreateBySystem,
+			notes:       constant.GeneralErrMsg,
+			realNotes:   cause.Error(),
+		})
+	})
+	if err != nil {
+		slog.Errorw(fmt.Sprintf("failed release reservation of disbursement %v", paymentId), "stack_trace", err.Error())
+		return errors.Join(cause, fmt.Errorf("release reservation of disbursement %v: %w", paymentId, err))
+	}
+
+	return cause
+}
+
+// recordDisbursement writes the processing transaction of a disbursement before it is sent to the provider, its
+// balance is reserved in the same unit of work
+func (tr *Transaction) recordDisbursement(payload dto.MerchantDisbursement, channelCodeId dto.ChannelIdCodeDisbursement, paymentId string, merchantReferenceNumber string) error {
 	// create transaction
 	createTransactionPayload := dto.CreateTransactionsDto{
 		PaymentId:               paymentId,
 		MerchantReferenceNumber: merchantReferenceNumber,
-		ProviderReferenceNumber: converter.ToString(confirm.Data.ID),
 		MerchantPaychanneId:     channelCodeId.MerchantPaychanneId,
 		ProviderPaychannelId:    channelCodeId.ProviderPaychannelId,
-		TransactionAmount:       float64(payload.Amount),
+		TransactionAmount:       payload.Amount,
 		BankCode:                channelCodeId.BankCode,
 		Status:                  constant.StatusProcessing,
 		RequestMethod:           "MERCHANT_DASHBOARD",
@@ -2518,39 +2047,32 @@ func (tr *Transaction) jackSupportDisbursement(credentials []entity.ProviderCred
 		CallbackUrl:             constant.CallbackUrlHypay,
 	}
 
-	_, err = tr.transactionRepoWrites.CreateTransactionsRepo(createTransactionPayload)
+	_, err := tr.transactionRepoWrites.CreateTransactionsRepo(createTransactionPayload)
 	if err != nil {
 		slog.Infof("username: %v, disbursementSupport got err %v", payload.Username, err.Error())
-		return "", err
+		return err
 	}
 
 	// create transaction logs
 	_, err = tr.transactionRepoWrites.CreateTransactionStatusLog(paymentId, constant.StatusLogAcceptedByPlatform, constant.CreateBySystem, "", "")
 	if err != nil {
 		slog.Infof("username: %v, disbursementSupport got err %v", payload.Username, err.Error())
-		return "", err
-	}
-
-	_, err = tr.transactionRepoWrites.CreateTransactionStatusLog(paymentId, constant.StatusLogAcceptedByProvider, constant.CreateBySystem, "", "")
-	if err != nil {
-		slog.Infof("username: %v, disbursementSupport got err %v", payload.Username, err.Error())
-		return "", err
+		return err
 	}
 
 	// create account information
 	payloadAccountInformationCreditor := dto.CreateAccountInformationDto{
-		PaymentId:       paymentId,
-		AccountNumber:   payload.BankAccountNumber,
-		AccountName:     payload.BankAccountName,
-		BankName:        payload.BankName,
-		BankCode:        channelCodeId.BankCode,
-		ReferenceNumber: converter.ToString(confirm.Data.ID),
-		AccountType:     constant.AccountTypeCreditor,
+		PaymentId:     paymentId,
+		AccountNumber: payload.BankAccountNumber,
+		AccountName:   payload.BankAccountName,
+		BankName:      payload.BankName,
+		BankCode:      channelCodeId.BankCode,
+		AccountType:   constant.AccountTypeCreditor,
 	}
 	_, err = tr.transactionRepoWrites.CreateAccountInformationRepo(payloadAccountInformationCreditor)
 	if err != nil {
 		slog.Infof("username: %v, disbursementSupport got err %v", payload.Username, err.Error())
-		return "", err
+		return err
 	}
 
 	payloadAccountInformationDebitor := dto.CreateAccountInformationDto{
@@ -2560,10 +2082,26 @@ func (tr *Transaction) jackSupportDisbursement(credentials []entity.ProviderCred
 	_, err = tr.transactionRepoWrites.CreateAccountInformationRepo(payloadAccountInformationDebitor)
 	if err != nil {
 		slog.Infof("username: %v, disbursementSupport got err %v", payload.Username, err.Error())
-		return "", err
+		return err
 	}
 
-	return "ok", nil
+	return nil
+}
+
+// acceptDisbursement keeps the reference of a disbursement the provider took
+func (tr *Transaction) acceptDisbursement(paymentId string, providerReferenceNumber string) error {
+	err := tr.transactionRepoWrites.UpdateProviderReferenceNumberRepo(paymentId, providerReferenceNumber)
+	if err != nil {
+		return err
+	}
+
+	err = tr.transactionRepoWrites.UpdateAccountInformationReferenceNumberRepo(paymentId, constant.AccountTypeCreditor, providerReferenceNumber)
+	if err != nil {
+		return err
+	}
+
+	_, err = tr.transactionRepoWrites.CreateTransactionStatusLog(paymentId, constant.StatusLogAcceptedByProvider, constant.CreateBySystem, "", "")
+	return err
 }
 
 func nullSafeString(value *string) string {
@@ -2573,9 +2111,9 @@ func nullSafeString(value *string) string {
 	return *value
 }
 
-func nullSafeFloat64(value *float64) float64 {
+func nullSafeMoney(value *money.Money) float64 {
 	if value == nil {
 		return 0
 	}
-	return *value
+	return value.Float64()
 }
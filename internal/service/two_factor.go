@@ -0,0 +1,490 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/totp"
+)
+
+var errInvalidChallenge = errors.New("login challenge is invalid or expired, please login again")
+
+var errInvalidTwoFactorCode = errors.New("invalid two factor code")
+
+// LoginTwoFactorSvc finishes the login of an enrolled user with a totp code or one of the recovery codes
+func (u *User) LoginTwoFactorSvc(payload dto.LoginChallengePayload) (dto.ResponseDto, error) {
+	challenge, user, resp, err := u.attemptLoginChallenge(payload.ChallengeToken, constant.LoginChallengeVerify)
+	if err != nil {
+		return resp, err
+	}
+
+	resp, err = u.verifyTwoFactorCode(user, payload.Code)
+	if err != nil {
+		return resp, err
+	}
+
+	err = u.userRepoWrites.UseLoginChallengeRepo(challenge.Id)
+	if err != nil {
+		slog.Errorw("failed use login challenge", "stack_trace", err.Error())
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	return u.completeLogin(user, payload.Device, payload.IpAddress)
+}
+
+// SetupTwoFactorLoginSvc starts the enrollment a role requires before the first token of its user is issued
+func (u *User) SetupTwoFactorLoginSvc(payload dto.LoginChallengePayload) (dto.ResponseDto, error) {
+	_, user, resp, err := u.attemptLoginChallenge(payload.ChallengeToken, constant.LoginChallengeSetup)
+	if err != nil {
+		return resp, err
+	}
+
+	return u.setupTwoFactor(user)
+}
+
+// ActivateTwoFactorLoginSvc enables two factor with the first code of the new secret and finishes the login,
+// the recovery codes are shown this one time
+func (u *User) ActivateTwoFactorLoginSvc(payload dto.LoginChallengePayload) (dto.ResponseDto, error) {
+	challenge, user, resp, err := u.attemptLoginChallenge(payload.ChallengeToken, constant.LoginChallengeSetup)
+	if err != nil {
+		return resp, err
+	}
+
+	recoveryCodes, resp, err := u.activateTwoFactor(user, payload.Code)
+	if err != nil {
+		return resp, err
+	}
+
+	err = u.userRepoWrites.UseLoginChallengeRepo(challenge.Id)
+	if err != nil {
+		slog.Errorw("failed use login challenge", "stack_trace", err.Error())
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	resp, err = u.completeLogin(user, payload.Device, payload.IpAddress)
+	if err != nil {
+		return resp, err
+	}
+
+	data := resp.Data.(map[string]interface{})
+	data["recoveryCodes"] = recoveryCodes
+
+	return resp, nil
+}
+
+// SetupTwoFactorSvc starts an optional enrollment for a logged in user
+func (u *User) SetupTwoFactorSvc(username string) (dto.ResponseDto, error) {
+	user, resp, err := u.getTwoFactorUser(username)
+	if err != nil {
+		return resp, err
+	}
+
+	if user.TwoFactorEnabled {
+		msg := "two factor authentication is already enabled"
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: msg,
+		}, errors.New(msg)
+	}
+
+	return u.setupTwoFactor(user)
+}
+
+func (u *User) ActivateTwoFactorSvc(payload dto.TwoFactorCodePayload) (dto.ResponseDto, error) {
+	user, resp, err := u.getTwoFactorUser(payload.Username)
+	if err != nil {
+		return resp, err
+	}
+
+	recoveryCodes, resp, err := u.activateTwoFactor(user, payload.Code)
+	if err != nil {
+		return resp, err
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "two factor authentication enabled",
+		Data: map[string]interface{}{
+			"recoveryCodes": recoveryCodes,
+		},
+	}
+
+	return resp, nil
+}
+
+// DisableTwoFactorSvc turns two factor off again, users of a role that requires it can't
+func (u *User) DisableTwoFactorSvc(payload dto.TwoFactorCodePayload) (dto.ResponseDto, error) {
+	user, resp, err := u.getTwoFactorUser(payload.Username)
+	if err != nil {
+		return resp, err
+	}
+
+	if !user.TwoFactorEnabled {
+		msg := "two factor authentication is not enabled"
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: msg,
+		}, errors.New(msg)
+	}
+
+	if user.TwoFactorRequired {
+		msg := fmt.Sprintf("role %v requires two factor authentication", user.RoleName)
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: msg,
+		}, errors.New(msg)
+	}
+
+	resp, err = u.verifyTwoFactorCode(user, payload.Code)
+	if err != nil {
+		return resp, err
+	}
+
+	return runInUnitOfWork(u.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		uTx := u.withUnitOfWork(repos)
+
+		err := uTx.userRepoWrites.DisableTwoFactorRepo(user.UserID)
+		if err != nil {
+			slog.Errorw("failed disable two factor", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		err = uTx.userRepoWrites.DeleteRecoveryCodesRepo(user.UserID)
+		if err != nil {
+			slog.Errorw("failed delete recovery codes", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "two factor authentication disabled",
+		}, nil
+	})
+}
+
+// RegenerateRecoveryCodesSvc replaces every recovery code of the user, used or not
+func (u *User) RegenerateRecoveryCodesSvc(payload dto.TwoFactorCodePayload) (dto.ResponseDto, error) {
+	user, resp, err := u.getTwoFactorUser(payload.Username)
+	if err != nil {
+		return resp, err
+	}
+
+	if !user.TwoFactorEnabled {
+		msg := "two factor authentication is not enabled"
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: msg,
+		}, errors.New(msg)
+	}
+
+	resp, err = u.verifyTwoFactorCode(user, payload.Code)
+	if err != nil {
+		return resp, err
+	}
+
+	recoveryCodes, codeHashes, err := generateRecoveryCodes()
+	if err != nil {
+		slog.Errorw("failed generate recovery codes", "stack_trace", err.Error())
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	return runInUnitOfWork(u.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		uTx := u.withUnitOfWork(repos)
+
+		err := uTx.userRepoWrites.DeleteRecoveryCodesRepo(user.UserID)
+		if err != nil {
+			slog.Errorw("failed delete recovery codes", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		err = uTx.userRepoWrites.CreateRecoveryCodesRepo(user.UserID, codeHashes)
+		if err != nil {
+			slog.Errorw("failed create recovery codes", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: "success regenerate recovery codes",
+			Data: map[string]interface{}{
+				"recoveryCodes": recoveryCodes,
+			},
+		}, nil
+	})
+}
+
+// issueLoginChallenge is what Login returns instead of tokens while a second factor is outstanding
+func (u *User) issueLoginChallenge(user entity.User, purpose string) (dto.ResponseDto, error) {
+	challengeToken, err := generateSecureToken(32)
+	if err != nil {
+		slog.Errorw("failed generate login challenge", "stack_trace", err.Error())
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	_, err = u.userRepoWrites.CreateLoginChallengeRepo(user.UserID, hashSecureToken(challengeToken), purpose, constant.LoginChallengeTTL)
+	if err != nil {
+		slog.Errorw("failed create login challenge", "stack_trace", err.Error())
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	msg := "two factor code is required"
+	if purpose == constant.LoginChallengeSetup {
+		msg = fmt.Sprintf("role %v requires two factor authentication, please enroll to continue", user.RoleName)
+	}
+
+	return dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: msg,
+		Data: map[string]interface{}{
+			"challengeToken":   challengeToken,
+			"challengePurpose": purpose,
+		},
+	}, nil
+}
+
+// attemptLoginChallenge counts an attempt against the challenge and loads its user
+func (u *User) attemptLoginChallenge(challengeToken string, purpose string) (entity.LoginChallengeEntity, entity.User, dto.ResponseDto, error) {
+	challenge, err := u.userRepoWrites.AttemptLoginChallengeRepo(hashSecureToken(challengeToken), constant.LoginChallengeMaxAttempts)
+	if err == sql.ErrNoRows || (err == nil && challenge.Purpose != purpose) {
+		return challenge, entity.User{}, dto.ResponseDto{
+			ResponseCode:    http.StatusUnauthorized,
+			ResponseMessage: errInvalidChallenge.Error(),
+		}, errInvalidChallenge
+	}
+	if err != nil {
+		slog.Errorw("failed attempt login challenge", "stack_trace", err.Error())
+		return challenge, entity.User{}, dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	user, resp, err := u.getTwoFactorUser(challenge.Username)
+	if err != nil {
+		return challenge, user, resp, err
+	}
+
+	// the user may have been deactivated or enrolled elsewhere since the challenge was issued
+	if user.UserStatus != constant.StatusActive || (purpose == constant.LoginChallengeSetup && user.TwoFactorEnabled) {
+		return challenge, user, dto.ResponseDto{
+			ResponseCode:    http.StatusUnauthorized,
+			ResponseMessage: errInvalidChallenge.Error(),
+		}, errInvalidChallenge
+	}
+
+	return challenge, user, dto.ResponseDto{}, nil
+}
+
+func (u *User) getTwoFactorUser(username string) (entity.User, dto.ResponseDto, error) {
+	user, err := u.userRepoReads.GetUserByUsername(username)
+	if err != nil {
+		slog.Errorw("failed get user", "stack_trace", err.Error())
+		return user, dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	return user, dto.ResponseDto{}, nil
+}
+
+// setupTwoFactor stores a fresh pending secret and returns it with the qr code authenticator apps scan
+func (u *User) setupTwoFactor(user entity.User) (dto.ResponseDto, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		slog.Errorw("failed generate totp secret", "stack_trace", err.Error())
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	err = u.userRepoWrites.UpdateTotpSecretRepo(user.UserID, secret)
+	if err != nil {
+		slog.Errorw("failed update totp secret", "stack_trace", err.Error())
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	uri := totp.URI(constant.TwoFactorIssuer, user.Username, secret)
+
+	return dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "scan the qr code and confirm with the first code",
+		Data: map[string]interface{}{
+			"secret": secret,
+			"uri":    uri,
+			"qrCode": converter.ToBase64Img(uri),
+		},
+	}, nil
+}
+
+// activateTwoFactor enables the pending secret once code proves the authenticator holds it
+func (u *User) activateTwoFactor(user entity.User, code string) ([]string, dto.ResponseDto, error) {
+	if user.TwoFactorEnabled {
+		msg := "two factor authentication is already enabled"
+		return nil, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: msg,
+		}, errors.New(msg)
+	}
+
+	if user.TotpSecret == nil {
+		msg := "two factor setup has not been started"
+		return nil, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: msg,
+		}, errors.New(msg)
+	}
+
+	step, ok := totp.Validate(*user.TotpSecret, code, time.Now())
+	if !ok {
+		return nil, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: errInvalidTwoFactorCode.Error(),
+		}, errInvalidTwoFactorCode
+	}
+
+	recoveryCodes, codeHashes, err := generateRecoveryCodes()
+	if err != nil {
+		slog.Errorw("failed generate recovery codes", "stack_trace", err.Error())
+		return nil, dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	resp, err := runInUnitOfWork(u.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		uTx := u.withUnitOfWork(repos)
+
+		err := uTx.userRepoWrites.EnableTwoFactorRepo(user.UserID, step)
+		if err != nil {
+			slog.Errorw("failed enable two factor", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		err = uTx.userRepoWrites.DeleteRecoveryCodesRepo(user.UserID)
+		if err != nil {
+			slog.Errorw("failed delete recovery codes", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		err = uTx.userRepoWrites.CreateRecoveryCodesRepo(user.UserID, codeHashes)
+		if err != nil {
+			slog.Errorw("failed create recovery codes", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		return dto.ResponseDto{}, nil
+	})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return recoveryCodes, dto.ResponseDto{}, nil
+}
+
+// verifyTwoFactorCode accepts a totp code that wasn't used before or an unused recovery code
+func (u *User) verifyTwoFactorCode(user entity.User, code string) (dto.ResponseDto, error) {
+	code = strings.TrimSpace(code)
+	invalidResp := dto.ResponseDto{
+		ResponseCode:    http.StatusBadRequest,
+		ResponseMessage: errInvalidTwoFactorCode.Error(),
+	}
+
+	var step int64
+	var ok bool
+	if user.TotpSecret != nil {
+		step, ok = totp.Validate(*user.TotpSecret, code, time.Now())
+	}
+
+	var err error
+	if ok {
+		err = u.userRepoWrites.UseTotpStepRepo(user.UserID, step)
+	} else {
+		err = u.userRepoWrites.UseRecoveryCodeRepo(user.UserID, hashSecureToken(normalizeRecoveryCode(code)))
+	}
+
+	if err == sql.ErrNoRows {
+		return invalidResp, errInvalidTwoFactorCode
+	}
+	if err != nil {
+		slog.Errorw("failed verify two factor code", "stack_trace", err.Error())
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	return dto.ResponseDto{}, nil
+}
+
+// generateRecoveryCodes returns the codes to show once and the hashes to store
+func generateRecoveryCodes() ([]string, []string, error) {
+	recoveryCodes := make([]string, 0, constant.TwoFactorRecoveryCodeCount)
+	codeHashes := make([]string, 0, constant.TwoFactorRecoveryCodeCount)
+
+	for i := 0; i < constant.TwoFactorRecoveryCodeCount; i++ {
+		code, err := generateSecureToken(5)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		recoveryCodes = append(recoveryCodes, code[:5]+"-"+code[5:])
+		codeHashes = append(codeHashes, hashSecureToken(code))
+	}
+
+	return recoveryCodes, codeHashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "-", ""))
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/helper"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+// runInUnitOfWork executes fn inside one database transaction, the transaction
+// is rolled back whenever fn returns an error
+func runInUnitOfWork(unitOfWork internal.UnitOfWorkItf, fn func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error)) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	err := unitOfWork.WithinTransaction(func(repos internal.UnitOfWorkRepos) error {
+		var err error
+		resp, err = fn(repos)
+		return err
+	})
+
+	// begin or commit failed, business response can't be trusted anymore
+	if err != nil && (resp.ResponseCode == 0 || resp.ResponseCode == http.StatusOK) {
+		slog.Errorw("unit of work failed", "stack_trace", err.Error())
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}
+	}
+
+	return resp, err
+}
+
+// withUnitOfWork returns a copy of the service that writes through repos
+func (tr *Transaction) withUnitOfWork(repos internal.UnitOfWorkRepos) *Transaction {
+	trTx := *tr
+	trTx.transactionRepoWrites = repos.TransactionsWrites
+	trTx.merchantRepoWrites = repos.MerchantWrites
+	trTx.providerRepoWrites = repos.ProviderWrites
+	trTx.ledgerRepoWrites = repos.LedgerWrites
+	trTx.disbursementApprovalWrites = repos.DisbursementApprovalWrites
+	trTx.disbursementBatchWrites = repos.DisbursementBatchWrites
+	trTx.auditor = tr.auditor.withUnitOfWork(repos)
+
+	return &trTx
+}
+
+// withUnitOfWork returns a copy of the service that writes through repos
+func (mr *Merchant) withUnitOfWork(repos internal.UnitOfWorkRepos) *Merchant {
+	mrTx := *mr
+	mrTx.merchantRepoWrites = repos.MerchantWrites
+	mrTx.ledgerRepoWrites = repos.LedgerWrites
+	mrTx.balanceApprovalWrites = repos.BalanceApprovalWrites
+	mrTx.auditor = mr.auditor.withUnitOfWork(repos)
+
+	return &mrTx
+}
+
+// withUnitOfWork returns a copy of the service that writes through repos
+func (pr *Provider) withUnitOfWork(repos internal.UnitOfWorkRepos) *Provider {
+	prTx := *pr
+	prTx.providerRepoWrites = repos.ProviderWrites
+	prTx.auditor = pr.auditor.withUnitOfWork(repos)
+
+	return &prTx
+}
+
+// withUnitOfWork returns a copy of the service that writes through repos
+func (u *User) withUnitOfWork(repos internal.UnitOfWorkRepos) *User {
+	uTx := *u
+	uTx.userRepoWrites = repos.UserWrites
+	uTx.auditor = u.auditor.withUnitOfWork(repos)
+
+	return &uTx
+}
+
+// lockMerchantAccounts takes row locks on the merchant accounts ordered by merchant id
+func lockMerchantAccounts(merchantRepoWrites internal.MerchantWritesRepositoryItf, merchantIds ...string) error {
+	sortedMerchantIds := append([]string{}, merchantIds...)
+	sort.Strings(sortedMerchantIds)
+
+	for _, merchantId := range sortedMerchantIds {
+		_, err := merchantRepoWrites.GetMerchantAccountForUpdateRepo(merchantId)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// auditBalance runs change and records the merchant accounts of merchantIds before and after it, mr has to
+// come from a unit of work so the balances and their trail are committed together. The accounts are locked
+// ordered by merchant id like lockMerchantAccounts does.
+func (mr *Merchant) auditBalance(activity string, actor dto.AuditActor, merchantIds []string, change func() (dto.ResponseDto, error)) (dto.ResponseDto, error) {
+	generalErr := dto.ResponseDto{
+		ResponseCode:    http.StatusUnprocessableEntity,
+		ResponseMessage: constant.GeneralErrMsg,
+	}
+
+	sortedMerchantIds := []string{}
+	for _, merchantId := range merchantIds {
+		if !helper.Contains(sortedMerchantIds, merchantId) {
+			sortedMerchantIds = append(sortedMerchantIds, merchantId)
+		}
+	}
+	sort.Strings(sortedMerchantIds)
+
+	// an account the change opens has no before
+	accountsBefore := map[string]interface{}{}
+	for _, merchantId := range sortedMerchantIds {
+		account, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(merchantId)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			slog.Errorw("failed get merchant account before change", "stack_trace", err.Error())
+			return generalErr, err
+		}
+		accountsBefore[merchantId] = account
+	}
+
+	resp, err := change()
+	if err != nil {
+		return resp, err
+	}
+
+	for _, merchantId := range sortedMerchantIds {
+		accountAfter, err := mr.merchantRepoWrites.GetMerchantAccountForUpdateRepo(merchantId)
+		if err != nil {
+			slog.Errorw("failed get merchant account after change", "stack_trace", err.Error())
+			return generalErr, err
+		}
+
+		err = mr.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeBalance,
+			Activity:    activity,
+			TargetId:    merchantId,
+			Before:      accountsBefore[merchantId],
+			After:       accountAfter,
+			Actor:       actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record balance change", "stack_trace", err.Error())
+			return generalErr, err
+		}
+	}
+
+	return resp, nil
+}
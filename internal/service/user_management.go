@@ -0,0 +1,248 @@
+package service
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hypay-id/backend-dashboard-hypay/internal"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/constant"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/dto"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/entity"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/converter"
+	"github.com/hypay-id/backend-dashboard-hypay/internal/pkg/slog"
+)
+
+var errMerchantUserNotFound = errors.New("user not found in merchant")
+
+var errLastMerchantAdmin = errors.New("merchant must keep at least one active admin")
+
+var errRoleNotAssignable = errors.New("role not found or can't be given to merchant users")
+
+// UpdateMerchantUserStatusSvc activates or deactivates a user of a merchant, a deactivated user is logged out
+// of every device
+func (u *User) UpdateMerchantUserStatusSvc(payload dto.MerchantUserPayload) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	if payload.Status != constant.StatusActive && payload.Status != constant.StatusInactive {
+		resp = dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: "status must be ACTIVE or INACTIVE",
+		}
+		return resp, errors.New("invalid status")
+	}
+
+	return u.changeMerchantUser(payload, func(uTx *User, merchantId string, user entity.ListUsersEntity) (string, error) {
+		if payload.Status == constant.StatusInactive {
+			err := uTx.keepMerchantAdmin(merchantId, user)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		err := uTx.userRepoWrites.UpdateUserStatusRepo(user.Id, payload.Status)
+		if err != nil {
+			return "", err
+		}
+
+		return constant.HistoryActivityUpdateUserStatus, nil
+	}, func(user entity.ListUsersEntity) entity.ListUsersEntity {
+		user.Status = payload.Status
+		return user
+	})
+}
+
+// UpdateMerchantUserRoleSvc moves a user of a merchant to another role, the user logs in again to pick up
+// the permissions of the new role
+func (u *User) UpdateMerchantUserRoleSvc(payload dto.MerchantUserPayload) (dto.ResponseDto, error) {
+	role, resp, err := u.merchantRole(payload.RoleId)
+	if err != nil {
+		return resp, err
+	}
+
+	return u.changeMerchantUser(payload, func(uTx *User, merchantId string, user entity.ListUsersEntity) (string, error) {
+		if role.RoleName != constant.RoleNameAdmin {
+			err := uTx.keepMerchantAdmin(merchantId, user)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		err := uTx.userRepoWrites.UpdateUserRoleRepo(user.Id, role.Id)
+		if err != nil {
+			return "", err
+		}
+
+		return constant.HistoryActivityUpdateUserRole, nil
+	}, func(user entity.ListUsersEntity) entity.ListUsersEntity {
+		user.RoleId = role.Id
+		user.Roles = role.RoleName
+		return user
+	})
+}
+
+// merchantRole gets a role merchant users may be given, the roles of operations staff are rejected
+func (u *User) merchantRole(roleId int) (entity.RoleEntity, dto.ResponseDto, error) {
+	role, err := u.userRepoReads.GetRoleByIdRepo(roleId)
+	if err == sql.ErrNoRows || (err == nil && !role.MerchantAssignable) {
+		return role, dto.ResponseDto{
+			ResponseCode:    http.StatusBadRequest,
+			ResponseMessage: errRoleNotAssignable.Error(),
+		}, errRoleNotAssignable
+	}
+	if err != nil {
+		slog.Errorw("failed get role", "stack_trace", err.Error())
+		return role, dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	return role, dto.ResponseDto{}, nil
+}
+
+// RemoveMerchantUserSvc deletes a user of a merchant together with its sessions
+func (u *User) RemoveMerchantUserSvc(payload dto.MerchantUserPayload) (dto.ResponseDto, error) {
+	return u.changeMerchantUser(payload, func(uTx *User, merchantId string, user entity.ListUsersEntity) (string, error) {
+		err := uTx.keepMerchantAdmin(merchantId, user)
+		if err != nil {
+			return "", err
+		}
+
+		err = uTx.userRepoWrites.DeleteUserRepo(user.Id)
+		if err != nil {
+			return "", err
+		}
+
+		return constant.HistoryActivityRemoveUser, nil
+	}, nil)
+}
+
+// changeMerchantUser locks the user, applies change and revokes the sessions of the user in one unit of
+// work, the change is kept in the audit trail. after describes the user once changed, nil when the
+// user is gone.
+func (u *User) changeMerchantUser(
+	payload dto.MerchantUserPayload,
+	change func(uTx *User, merchantId string, user entity.ListUsersEntity) (string, error),
+	after func(user entity.ListUsersEntity) entity.ListUsersEntity,
+) (dto.ResponseDto, error) {
+	merchantId, resp, err := scopedMerchantId(u.userRepoReads, payload.MerchantId, payload.Actor.Username)
+	if err != nil {
+		return resp, err
+	}
+	payload.MerchantId = merchantId
+
+	return runInUnitOfWork(u.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		uTx := u.withUnitOfWork(repos)
+
+		user, err := uTx.userRepoWrites.GetMerchantUserForUpdateRepo(payload.UserId, payload.MerchantId)
+		if err == sql.ErrNoRows {
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusBadRequest,
+				ResponseMessage: errMerchantUserNotFound.Error(),
+			}, errMerchantUserNotFound
+		}
+		if err != nil {
+			slog.Errorw("failed get merchant user", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		err = uTx.userRepoWrites.RevokeUserSessionsRepo(user.Id)
+		if err != nil {
+			slog.Errorw("failed revoke user sessions", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		activity, err := change(uTx, payload.MerchantId, user)
+		if err == errLastMerchantAdmin {
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: errLastMerchantAdmin.Error(),
+			}, err
+		}
+		if err != nil {
+			slog.Errorw("failed change merchant user", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		var userAfter interface{}
+		if after != nil {
+			userAfter = after(user)
+		}
+
+		err = uTx.auditor.Record(dto.AuditEntry{
+			HistoryType: constant.HistoryTypeUserManagement,
+			Activity:    activity,
+			TargetId:    converter.ToString(user.Id),
+			Before:      user,
+			After:       userAfter,
+			Actor:       payload.Actor,
+		})
+		if err != nil {
+			slog.Errorw("failed record user management history", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
+		}
+
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: fmt.Sprintf("success updated user %v", user.Username),
+		}, nil
+	})
+}
+
+// keepMerchantAdmin fails when user is the only active admin of the merchant
+func (u *User) keepMerchantAdmin(merchantId string, user entity.ListUsersEntity) error {
+	if user.Roles != constant.RoleNameAdmin || user.Status != constant.StatusActive {
+		return nil
+	}
+
+	adminIds, err := u.userRepoWrites.GetActiveMerchantAdminIdsForUpdateRepo(merchantId)
+	if err != nil {
+		return err
+	}
+
+	if len(adminIds) <= 1 {
+		return errLastMerchantAdmin
+	}
+
+	return nil
+}
+
+// scopedMerchantId returns the merchant a request works on, merchant dashboard users only reach their own
+// merchant so the merchant of username is used when merchantId is empty
+func scopedMerchantId(userRepoReads internal.UserReadsRepositoryItf, merchantId string, username string) (string, dto.ResponseDto, error) {
+	if merchantId != "" {
+		return merchantId, dto.ResponseDto{}, nil
+	}
+
+	user, err := userRepoReads.GetUserByUsername(username)
+	if err != nil {
+		slog.Errorw("failed get user data", "stack_trace", err.Error())
+		return "", dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: constant.GeneralErrMsg,
+		}, err
+	}
+
+	if user.MerchantID == nil || *user.MerchantID == "" {
+		return "", dto.ResponseDto{
+			ResponseCode:    http.StatusUnprocessableEntity,
+			ResponseMessage: "user has no merchant",
+		}, errors.New("user has no merchant")
+	}
+
+	return *user.MerchantID, dto.ResponseDto{}, nil
+}
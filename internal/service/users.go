@@ -18,20 +18,29 @@ import (
 )
 
 type User struct {
-	userRepoReads  internal.UserReadsRepositoryItf
-	userRepoWrites internal.UserWritesRepositoryItf
-	cfg            config.App
+	userRepoReads   internal.UserReadsRepositoryItf
+	userRepoWrites  internal.UserWritesRepositoryItf
+	unitOfWork      internal.UnitOfWorkItf
+	cfg             config.App
+	credentialGuard *CredentialGuard
+	auditor         *Auditor
 }
 
 func NewUser(
 	userRepoReads internal.UserReadsRepositoryItf,
 	userRepoWrites internal.UserWritesRepositoryItf,
+	unitOfWork internal.UnitOfWorkItf,
 	cfg config.App,
+	credentialGuard *CredentialGuard,
+	auditor *Auditor,
 ) *User {
 	return &User{
-		userRepoReads:  userRepoReads,
-		userRepoWrites: userRepoWrites,
-		cfg:            cfg,
+		userRepoReads:   userRepoReads,
+		userRepoWrites:  userRepoWrites,
+		unitOfWork:      unitOfWork,
+		cfg:             cfg,
+		credentialGuard: credentialGuard,
+		auditor:         auditor,
 	}
 }
 
@@ -48,6 +57,11 @@ func (u *User) Login(payload dto.LoginPayload) (dto.ResponseDto, error) {
 	}
 
 	if user.UserID < 1 {
+		resp, err = u.credentialGuard.FailUnknownUser(payload.Username, payload.IpAddress)
+		if err != nil {
+			return resp, err
+		}
+
 		resp = dto.ResponseDto{
 			ResponseCode:    http.StatusBadRequest,
 			ResponseMessage: "user not found",
@@ -63,14 +77,27 @@ func (u *User) Login(payload dto.LoginPayload) (dto.ResponseDto, error) {
 		return resp, errors.New("user not active")
 	}
 
-	if !comparePasswords(user.Password, []byte(payload.Password)) {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusBadRequest,
-			ResponseMessage: "invalid password",
-		}
-		return resp, errors.New("invalid password")
+	resp, err = u.credentialGuard.VerifyPassword(user, payload.Password, payload.IpAddress)
+	if err != nil {
+		return resp, err
+	}
+
+	// the password alone is enough only when neither the user nor the role asks for a second factor
+	if user.TwoFactorEnabled {
+		return u.issueLoginChallenge(user, constant.LoginChallengeVerify)
+	}
+
+	if user.TwoFactorRequired {
+		return u.issueLoginChallenge(user, constant.LoginChallengeSetup)
 	}
 
+	return u.completeLogin(user, payload.Device, payload.IpAddress)
+}
+
+// completeLogin starts a session for a user that passed every login step and returns its tokens
+func (u *User) completeLogin(user entity.User, device string, ipAddress string) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
 	userPermissions, err := u.userRepoReads.GetPermissionByRoleId(user.RoleId)
 	if err != nil {
 		resp = dto.ResponseDto{
@@ -81,9 +108,15 @@ func (u *User) Login(payload dto.LoginPayload) (dto.ResponseDto, error) {
 	}
 	user.Permissions = userPermissions
 
-	token, err := u.generateJWTToken(user)
+	session, refreshToken, err := u.startSession(user, device, ipAddress)
+	if err != nil {
+		slog.Infof("%v failed to start session with error message %v", user.Username, err.Error())
+		return dto.ResponseDto{}, errors.New(err.Error())
+	}
+
+	token, err := u.generateJWTToken(user, session.SessionId)
 	if err != nil {
-		slog.Infof("%v failed to generate token with error message %v", payload.Username, err.Error())
+		slog.Infof("%v failed to generate token with error message %v", user.Username, err.Error())
 		return dto.ResponseDto{}, errors.New(err.Error())
 	}
 
@@ -91,8 +124,9 @@ func (u *User) Login(payload dto.LoginPayload) (dto.ResponseDto, error) {
 		ResponseCode:    http.StatusOK,
 		ResponseMessage: "succcessfully login",
 		Data: map[string]interface{}{
-			"user":  user,
-			"token": token,
+			"user":         user,
+			"token":        token,
+			"refreshToken": refreshToken,
 		},
 	}, nil
 }
@@ -198,65 +232,11 @@ func (u *User) GetListMerchantUserSvc(username string) (dto.ResponseDto, error)
 }
 
 func (u *User) InviteUserMerchantSvc(payload dto.InviteMerchantUserDto) (dto.ResponseDto, error) {
-	var resp dto.ResponseDto
-	cfgAppMail := u.cfg.AppPassMail
-	username := payload.Email
-	password := helper.GenerateRandomString(8)
-	pin := helper.GenerateRandomPinNumericString(6)
-
-	passHash, _ := helper.HashString([]byte(password))
-	pinHash, _ := helper.HashString([]byte(pin))
-
-	credentialForRepo := dto.EmailDataHtmlDto{
-		Password: passHash,
-		Pin:      pinHash,
-		Username: payload.Email,
-	}
-
-	// create user
-	idUser, err := u.userRepoWrites.CreateUsersMerchantRepo(payload, credentialForRepo)
-	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusUnprocessableEntity,
-			ResponseMessage: err.Error(),
-		}
-		return resp, err
-	}
-
-	// credential for send email
-	credentialSendMail := dto.EmailDataHtmlDto{
-		Password: password,
-		Pin:      pin,
-		Username: username,
-	}
-
-	// send email
-	err = helper.SendEmailInviteUser(credentialSendMail, payload.Email, cfgAppMail)
-	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusUnprocessableEntity,
-			ResponseMessage: err.Error(),
-		}
-		return resp, err
-	}
-
-	resp = dto.ResponseDto{
-		ResponseCode:    http.StatusOK,
-		ResponseMessage: fmt.Sprintf("Success create user %v with id: %v", payload.Email, idUser),
-	}
-
-	return resp, nil
+	return u.inviteUser(payload)
 }
 
 func (u *User) InviteMerchantUserSvc(payload dto.InviteMerchantUserDto) (dto.ResponseDto, error) {
 	var resp dto.ResponseDto
-	cfgAppMail := u.cfg.AppPassMail
-	username := payload.Email
-	password := helper.GenerateRandomString(8)
-	pin := helper.GenerateRandomPinNumericString(6)
-
-	passHash, _ := helper.HashString([]byte(password))
-	pinHash, _ := helper.HashString([]byte(pin))
 
 	user, err := u.userRepoReads.GetUserByUsername(payload.Username)
 	if err != nil {
@@ -269,45 +249,7 @@ func (u *User) InviteMerchantUserSvc(payload dto.InviteMerchantUserDto) (dto.Res
 	}
 
 	payload.MerchantId = *user.MerchantID
-	credentialForRepo := dto.EmailDataHtmlDto{
-		Password: passHash,
-		Pin:      pinHash,
-		Username: payload.Email,
-	}
-
-	// create user
-	idUser, err := u.userRepoWrites.CreateUsersMerchantRepo(payload, credentialForRepo)
-	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusUnprocessableEntity,
-			ResponseMessage: err.Error(),
-		}
-		return resp, err
-	}
-
-	// credential for send email
-	credentialSendMail := dto.EmailDataHtmlDto{
-		Password: password,
-		Pin:      pin,
-		Username: username,
-	}
-
-	// send email
-	err = helper.SendEmailInviteUser(credentialSendMail, payload.Email, cfgAppMail)
-	if err != nil {
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusUnprocessableEntity,
-			ResponseMessage: err.Error(),
-		}
-		return resp, err
-	}
-
-	resp = dto.ResponseDto{
-		ResponseCode:    http.StatusOK,
-		ResponseMessage: fmt.Sprintf("Success create user %v with id: %v", payload.Email, idUser),
-	}
-
-	return resp, nil
+	return u.inviteUser(payload)
 }
 
 func (u *User) GetUserInformationsSvc(username string) (dto.ResponseDto, error) {
@@ -393,31 +335,54 @@ func (u *User) UpdatePasswordOrPinSvc(payload dto.UpdatePassOrPinDto) (dto.Respo
 	}
 
 	// update pin or password
-	err = u.userRepoWrites.UpdatePassOrPinRepo(passwordHash, pinHash, payload.Username)
-	if err != nil {
-		slog.Errorw("failed get user", "stack_trace", err.Error())
-		resp = dto.ResponseDto{
-			ResponseCode:    http.StatusUnprocessableEntity,
-			ResponseMessage: constant.GeneralErrMsg,
+	return runInUnitOfWork(u.unitOfWork, func(repos internal.UnitOfWorkRepos) (dto.ResponseDto, error) {
+		uTx := u.withUnitOfWork(repos)
+
+		err := uTx.userRepoWrites.UpdatePassOrPinRepo(passwordHash, pinHash, payload.Username)
+		if err != nil {
+			slog.Errorw("failed get user", "stack_trace", err.Error())
+			return dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}, err
 		}
-		return resp, err
-	}
 
-	resp = dto.ResponseDto{
-		ResponseCode:    http.StatusOK,
-		ResponseMessage: fmt.Sprintf("success updated for username %v", payload.Username),
-	}
+		// a new password logs out every device, including the one that changed it
+		if payload.Password != nil {
+			err = uTx.userRepoWrites.RevokeUserSessionsRepo(user.UserID)
+			if err != nil {
+				slog.Errorw("failed revoke user sessions", "stack_trace", err.Error())
+				return dto.ResponseDto{
+					ResponseCode:    http.StatusUnprocessableEntity,
+					ResponseMessage: constant.GeneralErrMsg,
+				}, err
+			}
+		}
 
-	return resp, nil
+		return dto.ResponseDto{
+			ResponseCode:    http.StatusOK,
+			ResponseMessage: fmt.Sprintf("success updated for username %v", payload.Username),
+		}, nil
+	})
 }
 
-func (u *User) generateJWTToken(user entity.User) (string, error) {
+func (u *User) generateJWTToken(user entity.User, sessionId string) (string, error) {
+	// permissions without a name are descriptions only, the route middleware can't check them
+	var permissions []string
+	for _, permission := range user.Permissions {
+		if permission.PermissionName != "" {
+			permissions = append(permissions, permission.PermissionName)
+		}
+	}
+
 	claims := &dto.Claims{
-		Username: user.Username,
-		UserType: user.UserType,
-		RoleName: user.RoleName,
+		Username:    user.Username,
+		UserType:    user.UserType,
+		RoleName:    user.RoleName,
+		Permissions: permissions,
+		SessionId:   sessionId,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 24 * 1).Unix(),
+			ExpiresAt: time.Now().Add(constant.AccessTokenTTL).Unix(),
 		},
 	}
 
@@ -425,6 +390,42 @@ func (u *User) generateJWTToken(user entity.User) (string, error) {
 	return token.SignedString([]byte(u.cfg.JWTSecret))
 }
 
+// UnlockUserSvc lifts the lockout of a username, an address or both before it runs out on its own
+func (u *User) UnlockUserSvc(payload dto.UnlockUserPayload) (dto.ResponseDto, error) {
+	var resp dto.ResponseDto
+
+	if payload.Username != "" {
+		err := u.credentialGuard.Unlock(constant.AuthScopeUser, payload.Username, payload.Actor)
+		if err != nil {
+			slog.Errorw("failed unlock user", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+	}
+
+	if payload.IpAddress != "" {
+		err := u.credentialGuard.Unlock(constant.AuthScopeIp, payload.IpAddress, payload.Actor)
+		if err != nil {
+			slog.Errorw("failed unlock ip address", "stack_trace", err.Error())
+			resp = dto.ResponseDto{
+				ResponseCode:    http.StatusUnprocessableEntity,
+				ResponseMessage: constant.GeneralErrMsg,
+			}
+			return resp, err
+		}
+	}
+
+	resp = dto.ResponseDto{
+		ResponseCode:    http.StatusOK,
+		ResponseMessage: "successfully unlock user",
+	}
+
+	return resp, nil
+}
+
 func comparePasswords(hashedPwd string, plainPwd []byte) bool {
 	// Since we'll be getting the hashed password from the DB it
 	// will be a string so we'll need to convert it to a byte slice